@@ -19,6 +19,7 @@ import (
 	"github.com/concourse/atc/dbng/dbngfakes"
 
 	"github.com/concourse/atc/api/jobserver/jobserverfakes"
+	"github.com/concourse/atc/api/pipelineserver/pipelineserverfakes"
 	"github.com/concourse/atc/api/pipes/pipesfakes"
 	"github.com/concourse/atc/api/resourceserver/resourceserverfakes"
 	"github.com/concourse/atc/auth/authfakes"
@@ -44,6 +45,7 @@ var (
 	fakeVolumeFactory             *dbngfakes.FakeVolumeFactory
 	fakeContainerFactory          *dbngfakes.FakeContainerFactory
 	pipeDB                        *pipesfakes.FakePipeDB
+	pipelinesDB                   *pipelineserverfakes.FakePipelinesDB
 	pipelineDBFactory             *dbfakes.FakePipelineDBFactory
 	teamDBFactory                 *dbfakes.FakeTeamDBFactory
 	dbTeamFactory                 *dbngfakes.FakeTeamFactory
@@ -111,6 +113,7 @@ var _ = BeforeEach(func() {
 	teamDB = new(dbfakes.FakeTeamDB)
 	teamDBFactory.GetTeamDBReturns(teamDB)
 	pipeDB = new(pipesfakes.FakePipeDB)
+	pipelinesDB = new(pipelineserverfakes.FakePipelinesDB)
 
 	authValidator = new(authfakes.FakeValidator)
 	userContextReader = new(authfakes.FakeUserContextReader)
@@ -147,7 +150,7 @@ var _ = BeforeEach(func() {
 
 	build = new(dbngfakes.FakeBuild)
 
-	checkPipelineAccessHandlerFactory := auth.NewCheckPipelineAccessHandlerFactory(dbTeamFactory)
+	checkPipelineAccessHandlerFactory := auth.NewCheckPipelineAccessHandlerFactory(dbTeamFactory, teamDBFactory)
 
 	checkBuildReadAccessHandlerFactory := auth.NewCheckBuildReadAccessHandlerFactory(dbBuildFactory)
 
@@ -185,6 +188,7 @@ var _ = BeforeEach(func() {
 		dbBuildFactory,
 
 		pipeDB,
+		pipelinesDB,
 
 		peerAddr,
 		constructedEventHandler.Construct,
@@ -19,6 +19,7 @@ import (
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/dbng/dbngfakes"
 	"github.com/concourse/atc/engine/enginefakes"
+	"github.com/concourse/atc/event"
 )
 
 var _ = Describe("Builds API", func() {
@@ -69,6 +70,7 @@ var _ = Describe("Builds API", func() {
 						build.StartTimeReturns(time.Unix(1, 0))
 						build.EndTimeReturns(time.Unix(100, 0))
 						build.ReapTimeReturns(time.Unix(200, 0))
+						build.TriggerReasonReturns(dbng.BuildTriggerReasonManual)
 						return build, nil
 					}
 				})
@@ -117,7 +119,8 @@ var _ = Describe("Builds API", func() {
 							"api_url": "/api/v1/builds/42",
 							"start_time": 1,
 							"end_time": 100,
-							"reap_time": 200
+							"reap_time": 200,
+							"trigger_reason": "manual"
 						}`))
 					})
 
@@ -1014,6 +1017,90 @@ var _ = Describe("Builds API", func() {
 		})
 	})
 
+	Describe("GET /api/v1/builds/:build_id/steps/:step_name/events", func() {
+		var (
+			response *http.Response
+			query    string
+		)
+
+		BeforeEach(func() {
+			query = "?attempt=1,2"
+
+			build.JobNameReturns("some-job")
+			build.TeamNameReturns("some-team")
+			build.IDReturns(128)
+			build.PipelineReturns(fakePipeline, true, nil)
+			dbBuildFactory.BuildReturns(build, true, nil)
+
+			fakeEventSource := new(dbngfakes.FakeEventSource)
+			fakeEventSource.NextReturns(event.Envelope{}, dbng.ErrEndOfBuildEventStream)
+			dbBuildFactory.GetStepEventsReturns(fakeEventSource, nil)
+
+			authValidator.IsAuthenticatedReturns(true)
+			userContextReader.GetTeamReturns("some-team", false, true)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Get(server.URL + "/api/v1/builds/128/steps/some-step/events" + query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			response.Body.Close()
+		})
+
+		It("returns 200", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(response.Header.Get("Content-Type")).To(Equal("text/event-stream; charset=utf-8"))
+		})
+
+		It("streams the events of the step and attempt", func() {
+			Expect(dbBuildFactory.GetStepEventsCallCount()).To(Equal(1))
+
+			buildID, stepName, attempt, from := dbBuildFactory.GetStepEventsArgsForCall(0)
+			Expect(buildID).To(Equal(128))
+			Expect(stepName).To(Equal("some-step"))
+			Expect(attempt).To(Equal([]int{1, 2}))
+			Expect(from).To(BeZero())
+		})
+
+		Context("when no attempt is given", func() {
+			BeforeEach(func() {
+				query = ""
+			})
+
+			It("streams the events of every attempt", func() {
+				Expect(dbBuildFactory.GetStepEventsCallCount()).To(Equal(1))
+
+				_, _, attempt, _ := dbBuildFactory.GetStepEventsArgsForCall(0)
+				Expect(attempt).To(BeEmpty())
+			})
+		})
+
+		Context("when the attempt is malformed", func() {
+			BeforeEach(func() {
+				query = "?attempt=first"
+			})
+
+			It("returns 400", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(dbBuildFactory.GetStepEventsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				userContextReader.GetTeamReturns("some-other-team", false, true)
+			})
+
+			It("returns 403", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+			})
+		})
+	})
+
 	Describe("PUT /api/v1/builds/:build_id/abort", func() {
 		var (
 			abortTarget *ghttp.Server
@@ -10,6 +10,7 @@ import (
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc/dbng"
+	"github.com/concourse/atc/event"
 	"github.com/vito/go-sse/sse"
 )
 
@@ -17,6 +18,10 @@ const ProtocolVersionHeader = "X-ATC-Stream-Version"
 const CurrentProtocolVersion = "2.0"
 
 func NewEventHandler(logger lager.Logger, build dbng.Build) http.Handler {
+	return newEventStreamHandler(logger, build.ID(), build.Events)
+}
+
+func newEventStreamHandler(logger lager.Logger, buildID int, eventSource func(from uint) (dbng.EventSource, error)) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		clientNotifier := w.(http.CloseNotifier)
 
@@ -55,9 +60,9 @@ func NewEventHandler(logger lager.Logger, build dbng.Build) http.Handler {
 			writer.writeFlusher = gz
 		}
 
-		events, err := build.Events(eventID)
+		events, err := eventSource(eventID)
 		if err != nil {
-			logger.Error("failed-to-get-build-events", err, lager.Data{"build-id": build.ID(), "start": eventID})
+			logger.Error("failed-to-get-build-events", err, lager.Data{"build-id": buildID, "start": eventID})
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -85,6 +90,27 @@ func NewEventHandler(logger lager.Logger, build dbng.Build) http.Handler {
 				return
 			}
 
+			if ev.Event == event.EventTypeResynced {
+				var resynced event.Resynced
+				err := json.Unmarshal(*ev.Data, &resynced)
+				if err != nil {
+					logger.Error("failed-to-parse-resynced-event", err)
+					return
+				}
+
+				// the build's events were compacted; continue numbering from
+				// wherever the stream was resumed
+				eventID = resynced.From
+
+				err = writer.WriteResynced(eventID, ev)
+				if err != nil {
+					logger.Info("failed-to-write-resynced", lager.Data{"error": err.Error()})
+					return
+				}
+
+				continue
+			}
+
 			err = writer.WriteEvent(eventID, ev)
 			if err != nil {
 				logger.Info("failed-to-write-event", lager.Data{"error": err.Error()})
@@ -107,13 +133,29 @@ type eventWriter struct {
 }
 
 func (writer eventWriter) WriteEvent(id uint, envelope interface{}) error {
+	return writer.writeEvent(fmt.Sprintf("%d", id), envelope)
+}
+
+// WriteResynced writes the marker for a stream that resumed at the given
+// event ID. It's identified as the event before it, so that a client
+// reconnecting right after it resumes at the same place.
+func (writer eventWriter) WriteResynced(from uint, envelope interface{}) error {
+	var id string
+	if from > 0 {
+		id = fmt.Sprintf("%d", from-1)
+	}
+
+	return writer.writeEvent(id, envelope)
+}
+
+func (writer eventWriter) writeEvent(id string, envelope interface{}) error {
 	payload, err := json.Marshal(envelope)
 	if err != nil {
 		return err
 	}
 
 	err = sse.Event{
-		ID:   fmt.Sprintf("%d", id),
+		ID:   id,
 		Name: "event",
 		Data: payload,
 	}.Write(writer.responseWriter)
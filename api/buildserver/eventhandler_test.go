@@ -167,6 +167,58 @@ var _ = Describe("Handler", func() {
 					Expect(actualFrom).To(Equal(uint(2)))
 				})
 			})
+
+			Context("when the events are compacted while streaming", func() {
+				BeforeEach(func() {
+					resynced := json.RawMessage(`{"from":1}`)
+
+					returnedEvents = []event.Envelope{
+						fakeEvent(`{"event":1}`),
+						fakeEvent(`{"event":2}`),
+						{
+							Data:    &resynced,
+							Event:   event.EventTypeResynced,
+							Version: "1.0",
+						},
+						fakeEvent(`{"event":3}`),
+					}
+				})
+
+				It("emits the resynced event and continues numbering from where it resumed", func() {
+					defer response.Body.Close()
+					reader := sse.NewReadCloser(response.Body)
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"event":1},"event":"fake","version":"42.0"}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "1",
+						Name: "event",
+						Data: []byte(`{"data":{"event":2},"event":"fake","version":"42.0"}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "0",
+						Name: "event",
+						Data: []byte(`{"data":{"from":1},"event":"resynced","version":"1.0"}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "1",
+						Name: "event",
+						Data: []byte(`{"data":{"event":3},"event":"fake","version":"42.0"}`),
+					}))
+
+					Expect(reader.Next()).To(Equal(sse.Event{
+						ID:   "2",
+						Name: "end",
+						Data: []byte{},
+					}))
+				})
+			})
 		})
 
 		Context("when the eventsource returns an error", func() {
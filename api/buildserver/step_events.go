@@ -0,0 +1,47 @@
+package buildserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/dbng"
+)
+
+func (s *Server) BuildStepEvents(build dbng.Build) http.Handler {
+	hLog := s.logger.Session("build-step-events")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stepName := r.FormValue(":step_name")
+
+		var attempt []int
+		if r.URL.Query().Get("attempt") != "" {
+			for _, a := range strings.Split(r.URL.Query().Get("attempt"), ",") {
+				n, err := strconv.Atoi(a)
+				if err != nil {
+					hLog.Info("malformed-attempt", lager.Data{"attempt": r.URL.Query().Get("attempt")})
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				attempt = append(attempt, n)
+			}
+		}
+
+		streamDone := make(chan struct{})
+
+		go func() {
+			defer close(streamDone)
+
+			newEventStreamHandler(hLog, build.ID(), func(from uint) (dbng.EventSource, error) {
+				return s.buildFactory.GetStepEvents(build.ID(), stepName, attempt, from)
+			}).ServeHTTP(w, r)
+		}()
+
+		select {
+		case <-streamDone:
+		case <-s.drain:
+		}
+	})
+}
@@ -143,7 +143,7 @@ var _ = Describe("Config API", func() {
 
 			Context("when the config can be loaded", func() {
 				BeforeEach(func() {
-					teamDB.GetConfigReturns(pipelineConfig, atc.RawConfig("raw-config"), 1, nil)
+					dbTeam.PipelineConfigReturns(pipelineConfig, atc.RawConfig("raw-config"), 1, nil)
 				})
 
 				It("returns 200", func() {
@@ -165,14 +165,35 @@ var _ = Describe("Config API", func() {
 					}))
 				})
 
-				It("calls get config with the correct arguments", func() {
-					Expect(teamDB.GetConfigArgsForCall(0)).To(Equal("something-else"))
+				It("looks up the config in the correct team", func() {
+					Expect(dbTeamFactory.FindTeamArgsForCall(0)).To(Equal("a-team"))
+					Expect(dbTeam.PipelineConfigArgsForCall(0)).To(Equal("something-else"))
+				})
+			})
+
+			Context("when the team is not found", func() {
+				BeforeEach(func() {
+					dbTeamFactory.FindTeamReturns(nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+			})
+
+			Context("when finding the team fails", func() {
+				BeforeEach(func() {
+					dbTeamFactory.FindTeamReturns(nil, false, errors.New("oh no!"))
+				})
+
+				It("returns 500", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
 				})
 			})
 
 			Context("when getting the config fails", func() {
 				BeforeEach(func() {
-					teamDB.GetConfigReturns(atc.Config{}, atc.RawConfig(""), 0, errors.New("oh no!"))
+					dbTeam.PipelineConfigReturns(atc.Config{}, atc.RawConfig(""), 0, errors.New("oh no!"))
 				})
 
 				It("returns 500", func() {
@@ -182,7 +203,7 @@ var _ = Describe("Config API", func() {
 
 			Context("when getting the config fails because it is malformed", func() {
 				BeforeEach(func() {
-					teamDB.GetConfigReturns(atc.Config{}, atc.RawConfig("raw-config"), 42, atc.MalformedConfigError{UnmarshalError: errors.New("invalid character")})
+					dbTeam.PipelineConfigReturns(atc.Config{}, atc.RawConfig("raw-config"), 42, atc.MalformedConfigError{UnmarshalError: errors.New("invalid character")})
 				})
 
 				It("returns 200", func() {
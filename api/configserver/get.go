@@ -12,8 +12,22 @@ import (
 func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
 	logger := s.logger.Session("get-config")
 	pipelineName := rata.Param(r, "pipeline_name")
-	teamDB := s.teamDBFactory.GetTeamDB(rata.Param(r, "team_name"))
-	config, rawConfig, id, err := teamDB.GetConfig(pipelineName)
+	teamName := rata.Param(r, "team_name")
+
+	team, found, err := s.teamFactory.FindTeam(teamName)
+	if err != nil {
+		logger.Error("failed-to-find-team", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !found {
+		logger.Debug("team-not-found")
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	config, rawConfig, id, err := team.PipelineConfig(pipelineName)
 	if err != nil {
 		if malformedErr, ok := err.(atc.MalformedConfigError); ok {
 			getConfigResponse := atc.ConfigResponse{
@@ -2,24 +2,20 @@ package configserver
 
 import (
 	"code.cloudfoundry.org/lager"
-	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/dbng"
 )
 
 type Server struct {
-	logger        lager.Logger
-	teamDBFactory db.TeamDBFactory
-	teamFactory   dbng.TeamFactory
+	logger      lager.Logger
+	teamFactory dbng.TeamFactory
 }
 
 func NewServer(
 	logger lager.Logger,
-	teamDBFactory db.TeamDBFactory,
 	teamFactory dbng.TeamFactory,
 ) *Server {
 	return &Server{
-		logger:        logger,
-		teamDBFactory: teamDBFactory,
-		teamFactory:   teamFactory,
+		logger:      logger,
+		teamFactory: teamFactory,
 	}
 }
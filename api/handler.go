@@ -55,6 +55,7 @@ func NewHandler(
 	dbBuildFactory dbng.BuildFactory,
 
 	pipeDB pipes.PipeDB,
+	pipelinesDB pipelineserver.PipelinesDB,
 
 	peerURL string,
 	eventHandlerFactory buildserver.EventHandlerFactory,
@@ -108,14 +109,14 @@ func NewHandler(
 		drain,
 	)
 
-	jobServer := jobserver.NewServer(logger, schedulerFactory, externalURL)
+	jobServer := jobserver.NewServer(logger, schedulerFactory, workerClient, externalURL)
 	resourceServer := resourceserver.NewServer(logger, scannerFactory)
 	versionServer := versionserver.NewServer(logger, externalURL)
 	pipeServer := pipes.NewServer(logger, peerURL, externalURL, pipeDB)
 
-	pipelineServer := pipelineserver.NewServer(logger, dbTeamFactory, teamDBFactory, dbPipelineFactory)
+	pipelineServer := pipelineserver.NewServer(logger, dbTeamFactory, teamDBFactory, dbPipelineFactory, pipelinesDB)
 
-	configServer := configserver.NewServer(logger, teamDBFactory, dbTeamFactory)
+	configServer := configserver.NewServer(logger, dbTeamFactory)
 
 	workerServer := workerserver.NewServer(logger, teamDBFactory, dbTeamFactory, dbWorkerFactory)
 
@@ -146,6 +147,7 @@ func NewHandler(
 		atc.GetBuildPlan:        buildHandlerFactory.HandlerFor(buildServer.GetBuildPlan),
 		atc.GetBuildPreparation: buildHandlerFactory.HandlerFor(buildServer.GetBuildPreparation),
 		atc.BuildEvents:         buildHandlerFactory.HandlerFor(buildServer.BuildEvents),
+		atc.BuildStepEvents:     buildHandlerFactory.HandlerFor(buildServer.BuildStepEvents),
 
 		atc.ListJobs:       pipelineHandlerFactory.HandlerFor(jobServer.ListJobs),
 		atc.GetJob:         pipelineHandlerFactory.HandlerFor(jobServer.GetJob),
@@ -153,22 +155,25 @@ func NewHandler(
 		atc.ListJobInputs:  pipelineHandlerFactory.HandlerFor(jobServer.ListJobInputs),
 		atc.GetJobBuild:    pipelineHandlerFactory.HandlerFor(jobServer.GetJobBuild),
 		atc.CreateJobBuild: pipelineHandlerFactory.HandlerFor(jobServer.CreateJobBuild),
+		atc.RerunJobBuild:  pipelineHandlerFactory.HandlerFor(jobServer.RerunJobBuild),
 		atc.PauseJob:       pipelineHandlerFactory.HandlerFor(jobServer.PauseJob),
 		atc.UnpauseJob:     pipelineHandlerFactory.HandlerFor(jobServer.UnpauseJob),
 		atc.JobBadge:       pipelineHandlerFactory.HandlerFor(jobServer.JobBadge),
 		atc.MainJobBadge:   mainredirect.Handler{atc.Routes, atc.JobBadge},
 
-		atc.ListAllPipelines: http.HandlerFunc(pipelineServer.ListAllPipelines),
-		atc.ListPipelines:    http.HandlerFunc(pipelineServer.ListPipelines),
-		atc.GetPipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipeline),
-		atc.DeletePipeline:   pipelineHandlerFactory.HandlerFor(pipelineServer.DeletePipeline),
-		atc.OrderPipelines:   http.HandlerFunc(pipelineServer.OrderPipelines),
-		atc.PausePipeline:    pipelineHandlerFactory.HandlerFor(pipelineServer.PausePipeline),
-		atc.UnpausePipeline:  pipelineHandlerFactory.HandlerFor(pipelineServer.UnpausePipeline),
-		atc.ExposePipeline:   pipelineHandlerFactory.HandlerFor(pipelineServer.ExposePipeline),
-		atc.HidePipeline:     pipelineHandlerFactory.HandlerFor(pipelineServer.HidePipeline),
-		atc.GetVersionsDB:    pipelineHandlerFactory.HandlerFor(pipelineServer.GetVersionsDB),
-		atc.RenamePipeline:   pipelineHandlerFactory.HandlerFor(pipelineServer.RenamePipeline),
+		atc.ListAllPipelines:    http.HandlerFunc(pipelineServer.ListAllPipelines),
+		atc.ListPipelines:       http.HandlerFunc(pipelineServer.ListPipelines),
+		atc.GetPipeline:         pipelineHandlerFactory.HandlerFor(pipelineServer.GetPipeline),
+		atc.DeletePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.DeletePipeline),
+		atc.OrderPipelines:      http.HandlerFunc(pipelineServer.OrderPipelines),
+		atc.PauseAllPipelines:   http.HandlerFunc(pipelineServer.PauseAllPipelines),
+		atc.UnpauseAllPipelines: http.HandlerFunc(pipelineServer.UnpauseAllPipelines),
+		atc.PausePipeline:       pipelineHandlerFactory.HandlerFor(pipelineServer.PausePipeline),
+		atc.UnpausePipeline:     pipelineHandlerFactory.HandlerFor(pipelineServer.UnpausePipeline),
+		atc.ExposePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.ExposePipeline),
+		atc.HidePipeline:        pipelineHandlerFactory.HandlerFor(pipelineServer.HidePipeline),
+		atc.GetVersionsDB:       pipelineHandlerFactory.HandlerFor(pipelineServer.GetVersionsDB),
+		atc.RenamePipeline:      pipelineHandlerFactory.HandlerFor(pipelineServer.RenamePipeline),
 
 		atc.ListResources:        pipelineHandlerFactory.HandlerFor(resourceServer.ListResources),
 		atc.GetResource:          pipelineHandlerFactory.HandlerFor(resourceServer.GetResource),
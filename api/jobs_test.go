@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,6 +18,8 @@ import (
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/dbng/dbngfakes"
 	"github.com/concourse/atc/scheduler/schedulerfakes"
+	"github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
 )
 
 var _ = Describe("Jobs API", func() {
@@ -30,6 +33,7 @@ var _ = Describe("Jobs API", func() {
 		pipelineDBFactory.BuildReturns(pipelineDB)
 		expectedSavedPipeline = db.SavedPipeline{}
 		teamDB.GetPipelineByNameReturns(expectedSavedPipeline, true, nil)
+		teamDB.GetPipelineByNameIgnoringCaseReturns(expectedSavedPipeline, true, nil)
 		fakeJob = new(dbngfakes.FakeJob)
 
 		versionedResourceTypes = atc.VersionedResourceTypes{
@@ -1209,11 +1213,168 @@ var _ = Describe("Jobs API", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusConflict))
 				})
 
+				It("explains why and how to enable manual triggering", func() {
+					Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"error": "manual triggering is disabled for job 'some-job'; to enable it, remove 'disable_manual_trigger: true' from the job in the pipeline config and set the pipeline again"
+					}`))
+				})
+
 				It("does not trigger the build", func() {
 					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(0))
 				})
 			})
 
+			Context("when the pipeline is archived", func() {
+				BeforeEach(func() {
+					pipelineDB.ConfigReturns(atc.Config{
+						Jobs: []atc.JobConfig{
+							{Name: "some-job"},
+						},
+					})
+
+					fakePipeline.ArchivedReturns(true)
+				})
+
+				It("returns 409 without triggering", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when asked to check for compatible workers", func() {
+				BeforeEach(func() {
+					var err error
+					request, err = http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds?check_workers=true", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					fakePipeline.TeamIDReturns(734)
+
+					pipelineDB.ConfigReturns(atc.Config{
+						Jobs: []atc.JobConfig{
+							{
+								Name: "some-job",
+								Plan: atc.PlanSequence{
+									{
+										Get: "some-input",
+									},
+									{
+										Task: "some-task",
+										Tags: atc.Tags{"some-tag"},
+										TaskConfig: &atc.TaskConfig{
+											Platform: "windows",
+										},
+									},
+								},
+							},
+						},
+					})
+				})
+
+				It("checks the task's platform and tags against the workers", func() {
+					Expect(fakeWorkerClient.SatisfyingCallCount()).To(Equal(1))
+
+					_, spec, resourceTypes := fakeWorkerClient.SatisfyingArgsForCall(0)
+					Expect(spec).To(Equal(worker.WorkerSpec{
+						Platform: "windows",
+						Tags:     []string{"some-tag"},
+						TeamID:   734,
+					}))
+					Expect(resourceTypes).To(Equal(versionedResourceTypes))
+				})
+
+				Context("when no worker is compatible", func() {
+					BeforeEach(func() {
+						fakeWorker := new(workerfakes.FakeWorker)
+						fakeWorker.NameReturns("some-worker")
+
+						fakeWorkerClient.SatisfyingReturns(nil, worker.NoCompatibleWorkersError{
+							Spec:    worker.WorkerSpec{Platform: "windows", Tags: []string{"some-tag"}, TeamID: 734},
+							Workers: []worker.Worker{fakeWorker},
+							Mismatches: []worker.WorkerMismatch{
+								{
+									Worker: "some-worker",
+									Reason: worker.ErrIncompatiblePlatform,
+									Detail: "platform linux != windows",
+								},
+							},
+						})
+					})
+
+					It("returns 422 explaining which workers were rejected", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+						Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+
+						var body struct {
+							Error           string `json:"error"`
+							Step            string `json:"step"`
+							RejectedWorkers []struct {
+								Worker string `json:"worker"`
+								Detail string `json:"detail"`
+							} `json:"rejected_workers"`
+						}
+						Expect(json.NewDecoder(response.Body).Decode(&body)).To(Succeed())
+
+						Expect(body.Step).To(Equal("some-task"))
+						Expect(body.Error).To(ContainSubstring("no workers can run task 'some-task'"))
+						Expect(body.RejectedWorkers).To(HaveLen(1))
+						Expect(body.RejectedWorkers[0].Worker).To(Equal("some-worker"))
+						Expect(body.RejectedWorkers[0].Detail).To(Equal("platform linux != windows"))
+					})
+
+					It("does not trigger the build", func() {
+						Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when a worker is compatible", func() {
+					BeforeEach(func() {
+						fakeWorkerClient.SatisfyingReturns(new(workerfakes.FakeWorker), nil)
+						fakeScheduler.TriggerImmediatelyReturns(new(dbngfakes.FakeBuild), nil, nil)
+					})
+
+					It("triggers the build", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+						Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when checking the workers fails", func() {
+					BeforeEach(func() {
+						fakeWorkerClient.SatisfyingReturns(nil, errors.New("oh no!"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when not asked to check for compatible workers", func() {
+				BeforeEach(func() {
+					pipelineDB.ConfigReturns(atc.Config{
+						Jobs: []atc.JobConfig{
+							{
+								Name: "some-job",
+								Plan: atc.PlanSequence{
+									{Task: "some-task"},
+								},
+							},
+						},
+					})
+					fakeScheduler.TriggerImmediatelyReturns(new(dbngfakes.FakeBuild), nil, nil)
+				})
+
+				It("does not check the workers", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+					Expect(fakeWorkerClient.SatisfyingCallCount()).To(Equal(0))
+				})
+			})
+
 			Context("when getting the job config succeeds", func() {
 				BeforeEach(func() {
 					pipelineDB.ConfigReturns(atc.Config{
@@ -1252,7 +1413,7 @@ var _ = Describe("Jobs API", func() {
 					It("triggers using the current config", func() {
 						Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
 
-						_, job, resources, resourceTypes := fakeScheduler.TriggerImmediatelyArgsForCall(0)
+						_, job, resources, resourceTypes, overrides := fakeScheduler.TriggerImmediatelyArgsForCall(0)
 						Expect(job).To(Equal(atc.JobConfig{
 							Name: "some-job",
 							Plan: atc.PlanSequence{
@@ -1266,6 +1427,84 @@ var _ = Describe("Jobs API", func() {
 							{Name: "resource-2", Type: "some-other-type"},
 						}))
 						Expect(resourceTypes).To(Equal(versionedResourceTypes))
+						Expect(overrides.IsEmpty()).To(BeTrue())
+					})
+
+					Context("when the request has overrides", func() {
+						BeforeEach(func() {
+							var err error
+							request, err = http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds", bytes.NewBufferString(`{
+								"versions": {"some-input": {"ref": "abc"}},
+								"params": {"some-input": {"depth": 1}}
+							}`))
+							Expect(err).NotTo(HaveOccurred())
+						})
+
+						It("triggers the unchanged job with the overrides kept for the build", func() {
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(Equal(1))
+
+							_, job, _, _, overrides := fakeScheduler.TriggerImmediatelyArgsForCall(0)
+							Expect(job).To(Equal(atc.JobConfig{
+								Name: "some-job",
+								Plan: atc.PlanSequence{
+									{
+										Get: "some-input",
+									},
+								},
+							}))
+							Expect(overrides).To(Equal(atc.BuildOverrides{
+								Versions: map[string]atc.Version{"some-input": {"ref": "abc"}},
+								Params:   map[string]atc.Params{"some-input": {"depth": float64(1)}},
+							}))
+						})
+
+						It("returns 200 OK", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusOK))
+						})
+					})
+
+					Context("when the overrides name a step that is not in the job", func() {
+						BeforeEach(func() {
+							var err error
+							request, err = http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds", bytes.NewBufferString(`{
+								"params": {"bogus-step": {"depth": 1}}
+							}`))
+							Expect(err).NotTo(HaveOccurred())
+						})
+
+						It("returns 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+
+							body, err := ioutil.ReadAll(response.Body)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(body)).To(ContainSubstring("cannot override params of unknown step 'bogus-step'"))
+						})
+
+						It("does not trigger the build", func() {
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when the overrides have an unknown key", func() {
+						BeforeEach(func() {
+							var err error
+							request, err = http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds", bytes.NewBufferString(`{
+								"bogus": {}
+							}`))
+							Expect(err).NotTo(HaveOccurred())
+						})
+
+						It("returns 400 Bad Request", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+
+							body, err := ioutil.ReadAll(response.Body)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(string(body)).To(ContainSubstring(`unknown field "bogus"`))
+						})
+
+						It("does not trigger the build", func() {
+							Expect(fakeScheduler.TriggerImmediatelyCallCount()).To(BeZero())
+						})
 					})
 
 					It("returns 200 OK", func() {
@@ -1318,6 +1557,226 @@ var _ = Describe("Jobs API", func() {
 		})
 	})
 
+	Describe("POST /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name/rerun", func() {
+		var request *http.Request
+		var response *http.Response
+
+		var fakeScheduler *schedulerfakes.FakeBuildScheduler
+
+		BeforeEach(func() {
+			var err error
+
+			request, err = http.NewRequest("POST", server.URL+"/api/v1/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds/3/rerun", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeScheduler = new(schedulerfakes.FakeBuildScheduler)
+			fakeSchedulerFactory.BuildSchedulerReturns(fakeScheduler)
+
+			pipelineDB.ConfigReturns(atc.Config{
+				Jobs: []atc.JobConfig{
+					{
+						Name: "some-job",
+						Plan: atc.PlanSequence{
+							{
+								Get: "some-input",
+							},
+						},
+					},
+				},
+
+				Resources: atc.ResourceConfigs{
+					{Name: "some-input", Type: "some-type"},
+				},
+			})
+		})
+
+		JustBeforeEach(func() {
+			var err error
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(true)
+				userContextReader.GetTeamReturns("some-team", true, true)
+			})
+
+			Context("when the build exists", func() {
+				var sourceBuild *dbfakes.FakeBuild
+				var rerunBuild *dbngfakes.FakeBuild
+
+				BeforeEach(func() {
+					sourceBuild = new(dbfakes.FakeBuild)
+					sourceBuild.IDReturns(7)
+					sourceBuild.NameReturns("3")
+					sourceBuild.GetResourcesReturns([]db.BuildInput{
+						{
+							Name: "some-input",
+							VersionedResource: db.VersionedResource{
+								Resource: "some-input",
+								Version:  db.Version{"ref": "abc"},
+							},
+						},
+					}, nil, nil)
+					pipelineDB.GetJobBuildReturns(sourceBuild, true, nil)
+
+					rerunBuild = new(dbngfakes.FakeBuild)
+					rerunBuild.IDReturns(42)
+					rerunBuild.NameReturns("4")
+					rerunBuild.JobNameReturns("some-job")
+					rerunBuild.PipelineNameReturns("some-pipeline")
+					rerunBuild.TeamNameReturns("some-team")
+					rerunBuild.StatusReturns(dbng.BuildStatusPending)
+					fakeScheduler.RerunImmediatelyReturns(rerunBuild, nil, nil)
+				})
+
+				It("looks up the requested build of the job", func() {
+					Expect(pipelineDB.GetJobBuildCallCount()).To(Equal(1))
+
+					jobName, buildName := pipelineDB.GetJobBuildArgsForCall(0)
+					Expect(jobName).To(Equal("some-job"))
+					Expect(buildName).To(Equal("3"))
+				})
+
+				It("reruns the job with the inputs of the build", func() {
+					Expect(fakeScheduler.RerunImmediatelyCallCount()).To(Equal(1))
+
+					_, job, resources, _, sourceBuildID, _ := fakeScheduler.RerunImmediatelyArgsForCall(0)
+					Expect(job).To(Equal(atc.JobConfig{
+						Name: "some-job",
+						Plan: atc.PlanSequence{
+							{
+								Get: "some-input",
+							},
+						},
+					}))
+					Expect(resources).To(Equal(atc.ResourceConfigs{
+						{Name: "some-input", Type: "some-type"},
+					}))
+					Expect(sourceBuildID).To(Equal(7))
+				})
+
+				It("links the new build to the one it reran as it is created", func() {
+					_, _, _, _, _, metadata := fakeScheduler.RerunImmediatelyArgsForCall(0)
+					Expect(metadata).To(HaveLen(1))
+					Expect(metadata["rerun-of"]).To(MatchJSON(`{
+						"build_id": 7,
+						"build_name": "3",
+						"note": "rerun of #3"
+					}`))
+
+					Expect(rerunBuild.SetMetadataCallCount()).To(BeZero())
+				})
+
+				It("returns the new build", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+					body, err := ioutil.ReadAll(response.Body)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(body).To(MatchJSON(`{
+						"id": 42,
+						"name": "4",
+						"job_name": "some-job",
+						"status": "pending",
+						"url": "/teams/some-team/pipelines/some-pipeline/jobs/some-job/builds/4",
+						"api_url": "/api/v1/builds/42",
+						"pipeline_name": "some-pipeline",
+						"team_name": "some-team"
+					}`))
+				})
+
+				Context("when one of the build's inputs is no longer in the job", func() {
+					BeforeEach(func() {
+						sourceBuild.GetResourcesReturns([]db.BuildInput{
+							{Name: "removed-input"},
+						}, nil, nil)
+					})
+
+					It("returns 409 without triggering", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusConflict))
+						Expect(fakeScheduler.RerunImmediatelyCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when getting the build's resources fails", func() {
+					BeforeEach(func() {
+						sourceBuild.GetResourcesReturns(nil, nil, errors.New("oh no!"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+
+				Context("when creating the rerun fails", func() {
+					BeforeEach(func() {
+						fakeScheduler.RerunImmediatelyReturns(nil, nil, errors.New("oh no!"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when the build does not exist", func() {
+				BeforeEach(func() {
+					pipelineDB.GetJobBuildReturns(nil, false, nil)
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+
+				It("does not trigger a build", func() {
+					Expect(fakeScheduler.RerunImmediatelyCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when manual triggering is disabled", func() {
+				BeforeEach(func() {
+					pipelineDB.ConfigReturns(atc.Config{
+						Jobs: []atc.JobConfig{
+							{
+								Name:                 "some-job",
+								DisableManualTrigger: true,
+							},
+						},
+					})
+				})
+
+				It("returns 409 without triggering", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(fakeScheduler.RerunImmediatelyCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the pipeline is archived", func() {
+				BeforeEach(func() {
+					fakePipeline.ArchivedReturns(true)
+				})
+
+				It("returns 409 without triggering", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusConflict))
+					Expect(fakeScheduler.RerunImmediatelyCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when not authorized", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/inputs", func() {
 		var response *http.Response
 
@@ -1335,8 +1794,8 @@ var _ = Describe("Jobs API", func() {
 			})
 
 			It("looked up the proper pipeline", func() {
-				Expect(teamDB.GetPipelineByNameCallCount()).To(Equal(1))
-				pipelineName := teamDB.GetPipelineByNameArgsForCall(0)
+				Expect(teamDB.GetPipelineByNameIgnoringCaseCallCount()).To(Equal(1))
+				pipelineName := teamDB.GetPipelineByNameIgnoringCaseArgsForCall(0)
 				Expect(pipelineName).To(Equal("some-pipeline"))
 				Expect(pipelineDBFactory.BuildCallCount()).To(Equal(1))
 				actualSavedPipeline := pipelineDBFactory.BuildArgsForCall(0)
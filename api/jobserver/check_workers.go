@@ -0,0 +1,81 @@
+package jobserver
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/worker"
+)
+
+type incompatibleTaskError struct {
+	Step string
+	Err  error
+}
+
+func (err incompatibleTaskError) Error() string {
+	return fmt.Sprintf("no workers can run task '%s': %s", err.Step, err.Err)
+}
+
+type rejectedWorker struct {
+	Worker string `json:"worker"`
+	Detail string `json:"detail"`
+}
+
+func (err incompatibleTaskError) present() interface{} {
+	rejected := []rejectedWorker{}
+	if noCompatible, ok := err.Err.(worker.NoCompatibleWorkersError); ok {
+		for _, mismatch := range noCompatible.Mismatches {
+			rejected = append(rejected, rejectedWorker{
+				Worker: mismatch.Worker,
+				Detail: mismatch.Detail,
+			})
+		}
+	}
+
+	return struct {
+		Error           string           `json:"error"`
+		Step            string           `json:"step"`
+		RejectedWorkers []rejectedWorker `json:"rejected_workers"`
+	}{
+		Error:           err.Error(),
+		Step:            err.Step,
+		RejectedWorkers: rejected,
+	}
+}
+
+// checkCompatibleWorkers makes sure every task in the job can be placed on at
+// least one worker. Only the platform of inline task configs is known up
+// front; tasks loaded from a file are checked by their tags alone.
+func (s *Server) checkCompatibleWorkers(
+	logger lager.Logger,
+	teamID int,
+	job atc.JobConfig,
+	resourceTypes atc.VersionedResourceTypes,
+) error {
+	for _, plan := range job.Plans() {
+		if plan.Task == "" {
+			continue
+		}
+
+		spec := worker.WorkerSpec{
+			Tags:   plan.Tags,
+			TeamID: teamID,
+		}
+
+		if plan.TaskConfig != nil {
+			spec.Platform = plan.TaskConfig.Platform
+		}
+
+		_, err := s.workerClient.Satisfying(logger, spec, resourceTypes)
+		if err != nil {
+			if _, ok := err.(worker.NoCompatibleWorkersError); ok || err == worker.ErrNoWorkers {
+				return incompatibleTaskError{Step: plan.Name(), Err: err}
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -3,8 +3,11 @@ package jobserver
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/api/present"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/dbng"
@@ -25,10 +28,36 @@ func (s *Server) CreateJobBuild(pipelineDB db.PipelineDB, dbPipeline dbng.Pipeli
 		}
 
 		if job.DisableManualTrigger {
-			w.WriteHeader(http.StatusConflict)
+			writeManualTriggerDisabled(w, jobName)
 			return
 		}
 
+		if dbPipeline.Archived() {
+			writePipelineArchived(w)
+			return
+		}
+
+		overrides, err := decodeOverrides(r.Body)
+		if err != nil {
+			logger.Info("malformed-overrides", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "malformed overrides: %s", err)
+			return
+		}
+
+		// the overrides are applied when the build is scheduled; applying them
+		// here only validates them and lets the worker check see them
+		overriddenJob := job
+		if !overrides.IsEmpty() {
+			overriddenJob, err = overrides.Apply(job)
+			if err != nil {
+				logger.Info("invalid-overrides", lager.Data{"error": err.Error()})
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "invalid overrides: %s", err)
+				return
+			}
+		}
+
 		scheduler := s.schedulerFactory.BuildScheduler(pipelineDB, dbPipeline, s.externalURL)
 
 		resourceTypes, err := dbPipeline.ResourceTypes()
@@ -38,7 +67,24 @@ func (s *Server) CreateJobBuild(pipelineDB db.PipelineDB, dbPipeline dbng.Pipeli
 			return
 		}
 
-		build, _, err := scheduler.TriggerImmediately(logger, job, config.Resources, resourceTypes.Deserialize())
+		if r.URL.Query().Get("check_workers") == "true" {
+			err := s.checkCompatibleWorkers(logger, dbPipeline.TeamID(), overriddenJob, resourceTypes.Deserialize())
+			if err != nil {
+				if incompatible, ok := err.(incompatibleTaskError); ok {
+					logger.Info("no-compatible-workers", lager.Data{"step": incompatible.Step})
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					json.NewEncoder(w).Encode(incompatible.present())
+					return
+				}
+
+				logger.Error("failed-to-check-workers", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		build, _, err := scheduler.TriggerImmediately(logger, job, config.Resources, resourceTypes.Deserialize(), overrides)
 		if err != nil {
 			logger.Error("failed-to-trigger", err)
 			w.WriteHeader(http.StatusInternalServerError)
@@ -49,3 +95,65 @@ func (s *Server) CreateJobBuild(pipelineDB db.PipelineDB, dbPipeline dbng.Pipeli
 		json.NewEncoder(w).Encode(present.Build(build))
 	})
 }
+
+var overrideKeys = map[string]bool{
+	"versions": true,
+	"params":   true,
+}
+
+// decodeOverrides reads the overrides from the request body, which may be
+// empty. Unknown keys are rejected so that a typo doesn't trigger a build
+// without the override the user asked for.
+func decodeOverrides(body io.Reader) (atc.BuildOverrides, error) {
+	var raw map[string]*json.RawMessage
+	err := json.NewDecoder(body).Decode(&raw)
+	if err == io.EOF {
+		return atc.BuildOverrides{}, nil
+	}
+
+	if err != nil {
+		return atc.BuildOverrides{}, err
+	}
+
+	for key := range raw {
+		if !overrideKeys[key] {
+			return atc.BuildOverrides{}, fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	var overrides atc.BuildOverrides
+	if raw["versions"] != nil {
+		err = json.Unmarshal(*raw["versions"], &overrides.Versions)
+		if err != nil {
+			return atc.BuildOverrides{}, err
+		}
+	}
+
+	if raw["params"] != nil {
+		err = json.Unmarshal(*raw["params"], &overrides.Params)
+		if err != nil {
+			return atc.BuildOverrides{}, err
+		}
+	}
+
+	return overrides, nil
+}
+
+func writeManualTriggerDisabled(w http.ResponseWriter, jobName string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": fmt.Sprintf(
+			"manual triggering is disabled for job '%s'; to enable it, remove 'disable_manual_trigger: true' from the job in the pipeline config and set the pipeline again",
+			jobName,
+		),
+	})
+}
+
+func writePipelineArchived(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": "the pipeline is archived; unarchive it to run its jobs",
+	})
+}
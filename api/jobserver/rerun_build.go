@@ -0,0 +1,121 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/api/present"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/dbng"
+)
+
+// RerunOfMetadata is the build metadata linking a rerun to the build whose
+// inputs it reused.
+const RerunOfMetadata = "rerun-of"
+
+type rerunOf struct {
+	BuildID   int    `json:"build_id"`
+	BuildName string `json:"build_name"`
+	Note      string `json:"note"`
+}
+
+func (s *Server) RerunJobBuild(pipelineDB db.PipelineDB, dbPipeline dbng.Pipeline) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := s.logger.Session("rerun-job-build")
+
+		jobName := r.FormValue(":job_name")
+		buildName := r.FormValue(":build_name")
+
+		config := pipelineDB.Config()
+
+		job, found := config.Jobs.Lookup(jobName)
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if job.DisableManualTrigger {
+			writeManualTriggerDisabled(w, jobName)
+			return
+		}
+
+		if dbPipeline.Archived() {
+			writePipelineArchived(w)
+			return
+		}
+
+		sourceBuild, found, err := pipelineDB.GetJobBuild(jobName, buildName)
+		if err != nil {
+			logger.Error("failed-to-get-job-build", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		inputs, _, err := sourceBuild.GetResources()
+		if err != nil {
+			logger.Error("failed-to-get-build-resources", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// the rerun gets the source build's inputs as they are, so make sure
+		// the job still has a get step for each of them
+		pinned := atc.BuildOverrides{Versions: map[string]atc.Version{}}
+		for _, input := range inputs {
+			pinned.Versions[input.Name] = atc.Version(input.Version)
+		}
+
+		_, err = pinned.Apply(job)
+		if err != nil {
+			logger.Info("inputs-no-longer-in-job", lager.Data{"error": err.Error()})
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintf(w, "cannot rerun build: %s", err)
+			return
+		}
+
+		link, err := json.Marshal(rerunOf{
+			BuildID:   sourceBuild.ID(),
+			BuildName: sourceBuild.Name(),
+			Note:      fmt.Sprintf("rerun of #%s", sourceBuild.Name()),
+		})
+		if err != nil {
+			logger.Error("failed-to-marshal-rerun-link", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		scheduler := s.schedulerFactory.BuildScheduler(pipelineDB, dbPipeline, s.externalURL)
+
+		resourceTypes, err := dbPipeline.ResourceTypes()
+		if err != nil {
+			logger.Error("failed-to-get-resource-types", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		build, _, err := scheduler.RerunImmediately(
+			logger,
+			job,
+			config.Resources,
+			resourceTypes.Deserialize(),
+			sourceBuild.ID(),
+			map[string]json.RawMessage{RerunOfMetadata: link},
+		)
+		if err != nil {
+			logger.Error("failed-to-rerun", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "failed to rerun: %s", err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(present.Build(build))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/scheduler"
+	"github.com/concourse/atc/worker"
 )
 
 //go:generate counterfeiter . SchedulerFactory
@@ -18,6 +19,7 @@ type Server struct {
 	logger lager.Logger
 
 	schedulerFactory SchedulerFactory
+	workerClient     worker.Client
 	externalURL      string
 	rejector         auth.Rejector
 }
@@ -25,11 +27,13 @@ type Server struct {
 func NewServer(
 	logger lager.Logger,
 	schedulerFactory SchedulerFactory,
+	workerClient worker.Client,
 	externalURL string,
 ) *Server {
 	return &Server{
 		logger:           logger,
 		schedulerFactory: schedulerFactory,
+		workerClient:     workerClient,
 		externalURL:      externalURL,
 		rejector:         auth.UnauthorizedRejector{},
 	}
@@ -2,10 +2,12 @@ package api_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -38,6 +40,7 @@ var _ = Describe("Pipelines API", func() {
 		pipelineDBFactory.BuildReturns(pipelineDB)
 		expectedSavedPipeline = db.SavedPipeline{}
 		teamDB.GetPipelineByNameReturns(expectedSavedPipeline, true, nil)
+		teamDB.GetPipelineByNameIgnoringCaseReturns(expectedSavedPipeline, true, nil)
 
 		publicPipeline = new(dbngfakes.FakePipeline)
 		publicPipeline.IDReturns(1)
@@ -142,6 +145,7 @@ var _ = Describe("Pipelines API", func() {
 					"url": "/teams/main/pipelines/public-pipeline",
 					"paused": true,
 					"public": true,
+					"archived": false,
 					"team_name": "main",
 					"groups": [
 						{
@@ -157,6 +161,7 @@ var _ = Describe("Pipelines API", func() {
 					"url": "/teams/another/pipelines/another-pipeline",
 					"paused": true,
 					"public": true,
+					"archived": false,
 					"team_name": "another"
 				}]`))
 			})
@@ -180,6 +185,7 @@ var _ = Describe("Pipelines API", func() {
 					"url": "/teams/main/pipelines/private-pipeline",
 					"paused": false,
 					"public": false,
+					"archived": false,
 					"team_name": "main",
 					"groups": [
 						{
@@ -195,6 +201,7 @@ var _ = Describe("Pipelines API", func() {
 					"url": "/teams/main/pipelines/public-pipeline",
 					"paused": true,
 					"public": true,
+					"archived": false,
 					"team_name": "main",
 					"groups": [
 						{
@@ -210,6 +217,7 @@ var _ = Describe("Pipelines API", func() {
 					"url": "/teams/another/pipelines/another-pipeline",
 					"paused": true,
 					"public": true,
+					"archived": false,
 					"team_name": "another"
 				}]`))
 			})
@@ -270,6 +278,7 @@ var _ = Describe("Pipelines API", func() {
 						"url": "/teams/main/pipelines/private-pipeline",
 						"paused": false,
 						"public": false,
+						"archived": false,
 						"team_name": "main",
 						"groups": [
 							{
@@ -285,6 +294,7 @@ var _ = Describe("Pipelines API", func() {
 						"url": "/teams/main/pipelines/public-pipeline",
 						"paused": true,
 						"public": true,
+						"archived": false,
 						"team_name": "main",
 						"groups": [
 							{
@@ -325,6 +335,7 @@ var _ = Describe("Pipelines API", func() {
 						"url": "/teams/main/pipelines/public-pipeline",
 						"paused": true,
 						"public": true,
+						"archived": false,
 						"team_name": "main",
 						"groups": [
 							{
@@ -355,6 +366,7 @@ var _ = Describe("Pipelines API", func() {
 						"url": "/teams/main/pipelines/public-pipeline",
 						"paused": true,
 						"public": true,
+						"archived": false,
 						"team_name": "main",
 						"groups": [
 							{
@@ -443,6 +455,7 @@ var _ = Describe("Pipelines API", func() {
 						"url": "/teams/a-team/pipelines/some-specific-pipeline",
 						"paused": false,
 						"public": true,
+						"archived": false,
 						"team_name": "a-team",
 						"groups": [
 							{
@@ -458,6 +471,20 @@ var _ = Describe("Pipelines API", func() {
 						]
 					}`))
 			})
+
+			Context("when the pipeline knows when its config was last saved", func() {
+				BeforeEach(func() {
+					fakePipeline.LastUpdatedReturns(time.Unix(1500000000, 0))
+				})
+
+				It("includes when it was last updated", func() {
+					var pipeline atc.Pipeline
+					err := json.NewDecoder(response.Body).Decode(&pipeline)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pipeline.LastUpdated).To(Equal(int64(1500000000)))
+				})
+			})
 		})
 
 		Context("when authenticated as another team", func() {
@@ -905,6 +932,168 @@ var _ = Describe("Pipelines API", func() {
 		})
 	})
 
+	Describe("PUT /api/v1/teams/:team_name/pipelines/pause", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/pause", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			Context("when requester belongs to the team", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("a-team", true, true)
+				})
+
+				Context("when pausing the pipelines succeeds", func() {
+					BeforeEach(func() {
+						pipelinesDB.PauseAllPipelinesReturns(3, nil)
+					})
+
+					It("pauses the team's pipelines", func() {
+						Expect(pipelinesDB.PauseAllPipelinesCallCount()).To(Equal(1))
+						Expect(pipelinesDB.PauseAllPipelinesArgsForCall(0)).To(Equal("a-team"))
+						Expect(pipelinesDB.UnpauseAllPipelinesCallCount()).To(BeZero())
+					})
+
+					It("returns 200", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+					})
+
+					It("returns the number of pipelines that were paused", func() {
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(body).To(MatchJSON(`{"count": 3}`))
+					})
+				})
+
+				Context("when pausing the pipelines fails", func() {
+					BeforeEach(func() {
+						pipelinesDB.PauseAllPipelinesReturns(0, errors.New("welp"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when requester does not belong to the team", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("another-team", true, true)
+				})
+
+				It("returns 403 Forbidden", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+
+				It("does not pause any pipelines", func() {
+					Expect(pipelinesDB.PauseAllPipelinesCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("PUT /api/v1/teams/:team_name/pipelines/unpause", func() {
+		var response *http.Response
+
+		JustBeforeEach(func() {
+			var err error
+
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/teams/a-team/pipelines/unpause", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			response, err = client.Do(request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when authenticated", func() {
+			Context("when requester belongs to the team", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("a-team", true, true)
+				})
+
+				Context("when unpausing the pipelines succeeds", func() {
+					BeforeEach(func() {
+						pipelinesDB.UnpauseAllPipelinesReturns(3, nil)
+					})
+
+					It("unpauses the team's pipelines", func() {
+						Expect(pipelinesDB.UnpauseAllPipelinesCallCount()).To(Equal(1))
+						Expect(pipelinesDB.UnpauseAllPipelinesArgsForCall(0)).To(Equal("a-team"))
+						Expect(pipelinesDB.PauseAllPipelinesCallCount()).To(BeZero())
+					})
+
+					It("returns 200", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+					})
+
+					It("returns the number of pipelines that were unpaused", func() {
+						body, err := ioutil.ReadAll(response.Body)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(body).To(MatchJSON(`{"count": 3}`))
+					})
+				})
+
+				Context("when unpausing the pipelines fails", func() {
+					BeforeEach(func() {
+						pipelinesDB.UnpauseAllPipelinesReturns(0, errors.New("welp"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when requester does not belong to the team", func() {
+				BeforeEach(func() {
+					authValidator.IsAuthenticatedReturns(true)
+					userContextReader.GetTeamReturns("another-team", true, true)
+				})
+
+				It("returns 403 Forbidden", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusForbidden))
+				})
+
+				It("does not unpause any pipelines", func() {
+					Expect(pipelinesDB.UnpauseAllPipelinesCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when not authenticated", func() {
+			BeforeEach(func() {
+				authValidator.IsAuthenticatedReturns(false)
+			})
+
+			It("returns 401 Unauthorized", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
 	Describe("PUT /api/v1/teams/:team_name/pipelines/ordering", func() {
 		var response *http.Response
 		var body io.Reader
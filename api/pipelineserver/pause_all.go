@@ -0,0 +1,40 @@
+package pipelineserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+)
+
+func (s *Server) PauseAllPipelines(w http.ResponseWriter, r *http.Request) {
+	s.setAllPipelinesPaused(w, r, s.logger.Session("pause-all-pipelines"), s.pipelinesDB.PauseAllPipelines)
+}
+
+func (s *Server) UnpauseAllPipelines(w http.ResponseWriter, r *http.Request) {
+	s.setAllPipelinesPaused(w, r, s.logger.Session("unpause-all-pipelines"), s.pipelinesDB.UnpauseAllPipelines)
+}
+
+func (s *Server) setAllPipelinesPaused(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger lager.Logger,
+	toggle func(teamName string) (int, error),
+) {
+	teamName := r.FormValue(":team_name")
+
+	count, err := toggle(teamName)
+	if err != nil {
+		logger.Error("failed-to-update-pipelines", err, lager.Data{"team": teamName})
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("updated-pipelines", lager.Data{"team": teamName, "count": count})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	json.NewEncoder(w).Encode(atc.PipelinesPausedState{Count: count})
+}
@@ -0,0 +1,165 @@
+// This file was generated by counterfeiter
+package pipelineserverfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/api/pipelineserver"
+)
+
+type FakePipelinesDB struct {
+	PauseAllPipelinesStub        func(teamName string) (int, error)
+	pauseAllPipelinesMutex       sync.RWMutex
+	pauseAllPipelinesArgsForCall []struct {
+		teamName string
+	}
+	pauseAllPipelinesReturns struct {
+		result1 int
+		result2 error
+	}
+	pauseAllPipelinesReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	UnpauseAllPipelinesStub        func(teamName string) (int, error)
+	unpauseAllPipelinesMutex       sync.RWMutex
+	unpauseAllPipelinesArgsForCall []struct {
+		teamName string
+	}
+	unpauseAllPipelinesReturns struct {
+		result1 int
+		result2 error
+	}
+	unpauseAllPipelinesReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePipelinesDB) PauseAllPipelines(teamName string) (int, error) {
+	fake.pauseAllPipelinesMutex.Lock()
+	ret, specificReturn := fake.pauseAllPipelinesReturnsOnCall[len(fake.pauseAllPipelinesArgsForCall)]
+	fake.pauseAllPipelinesArgsForCall = append(fake.pauseAllPipelinesArgsForCall, struct {
+		teamName string
+	}{teamName})
+	fake.recordInvocation("PauseAllPipelines", []interface{}{teamName})
+	fake.pauseAllPipelinesMutex.Unlock()
+	if fake.PauseAllPipelinesStub != nil {
+		return fake.PauseAllPipelinesStub(teamName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.pauseAllPipelinesReturns.result1, fake.pauseAllPipelinesReturns.result2
+}
+
+func (fake *FakePipelinesDB) PauseAllPipelinesCallCount() int {
+	fake.pauseAllPipelinesMutex.RLock()
+	defer fake.pauseAllPipelinesMutex.RUnlock()
+	return len(fake.pauseAllPipelinesArgsForCall)
+}
+
+func (fake *FakePipelinesDB) PauseAllPipelinesArgsForCall(i int) string {
+	fake.pauseAllPipelinesMutex.RLock()
+	defer fake.pauseAllPipelinesMutex.RUnlock()
+	return fake.pauseAllPipelinesArgsForCall[i].teamName
+}
+
+func (fake *FakePipelinesDB) PauseAllPipelinesReturns(result1 int, result2 error) {
+	fake.PauseAllPipelinesStub = nil
+	fake.pauseAllPipelinesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelinesDB) PauseAllPipelinesReturnsOnCall(i int, result1 int, result2 error) {
+	fake.PauseAllPipelinesStub = nil
+	if fake.pauseAllPipelinesReturnsOnCall == nil {
+		fake.pauseAllPipelinesReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.pauseAllPipelinesReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelinesDB) UnpauseAllPipelines(teamName string) (int, error) {
+	fake.unpauseAllPipelinesMutex.Lock()
+	ret, specificReturn := fake.unpauseAllPipelinesReturnsOnCall[len(fake.unpauseAllPipelinesArgsForCall)]
+	fake.unpauseAllPipelinesArgsForCall = append(fake.unpauseAllPipelinesArgsForCall, struct {
+		teamName string
+	}{teamName})
+	fake.recordInvocation("UnpauseAllPipelines", []interface{}{teamName})
+	fake.unpauseAllPipelinesMutex.Unlock()
+	if fake.UnpauseAllPipelinesStub != nil {
+		return fake.UnpauseAllPipelinesStub(teamName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.unpauseAllPipelinesReturns.result1, fake.unpauseAllPipelinesReturns.result2
+}
+
+func (fake *FakePipelinesDB) UnpauseAllPipelinesCallCount() int {
+	fake.unpauseAllPipelinesMutex.RLock()
+	defer fake.unpauseAllPipelinesMutex.RUnlock()
+	return len(fake.unpauseAllPipelinesArgsForCall)
+}
+
+func (fake *FakePipelinesDB) UnpauseAllPipelinesArgsForCall(i int) string {
+	fake.unpauseAllPipelinesMutex.RLock()
+	defer fake.unpauseAllPipelinesMutex.RUnlock()
+	return fake.unpauseAllPipelinesArgsForCall[i].teamName
+}
+
+func (fake *FakePipelinesDB) UnpauseAllPipelinesReturns(result1 int, result2 error) {
+	fake.UnpauseAllPipelinesStub = nil
+	fake.unpauseAllPipelinesReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelinesDB) UnpauseAllPipelinesReturnsOnCall(i int, result1 int, result2 error) {
+	fake.UnpauseAllPipelinesStub = nil
+	if fake.unpauseAllPipelinesReturnsOnCall == nil {
+		fake.unpauseAllPipelinesReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.unpauseAllPipelinesReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelinesDB) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.pauseAllPipelinesMutex.RLock()
+	defer fake.pauseAllPipelinesMutex.RUnlock()
+	fake.unpauseAllPipelinesMutex.RLock()
+	defer fake.unpauseAllPipelinesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakePipelinesDB) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ pipelineserver.PipelinesDB = new(FakePipelinesDB)
@@ -31,6 +31,33 @@ func (pdbh *ScopedHandlerFactory) HandlerFor(pipelineScopedHandler func(db.Pipel
 		teamName := r.FormValue(":team_name")
 		pipelineName := r.FormValue(":pipeline_name")
 
+		teamDB := pdbh.teamDBFactory.GetTeamDB(teamName)
+
+		var savedDBPipeline db.SavedPipeline
+		var found bool
+		var err error
+		if auth.IsReadOnly(r) {
+			savedDBPipeline, found, err = teamDB.GetPipelineByNameIgnoringCase(pipelineName)
+		} else {
+			// anything that could change or delete the pipeline must name it
+			// exactly
+			savedDBPipeline, found, err = teamDB.GetPipelineByName(pipelineName)
+		}
+		if err != nil {
+			if _, ok := err.(db.AmbiguousPipelineNameError); ok {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
 		pipeline, ok := r.Context().Value(auth.PipelineContextKey).(dbng.Pipeline)
 		if !ok {
 			dbngTeam, found, err := pdbh.teamDBNGFactory.FindTeam(teamName)
@@ -44,7 +71,7 @@ func (pdbh *ScopedHandlerFactory) HandlerFor(pipelineScopedHandler func(db.Pipel
 				return
 			}
 
-			pipeline, found, err = dbngTeam.Pipeline(pipelineName)
+			pipeline, found, err = dbngTeam.Pipeline(savedDBPipeline.Name)
 			if err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 				return
@@ -56,17 +83,6 @@ func (pdbh *ScopedHandlerFactory) HandlerFor(pipelineScopedHandler func(db.Pipel
 			}
 		}
 
-		savedDBPipeline, found, err := pdbh.teamDBFactory.GetTeamDB(teamName).GetPipelineByName(pipelineName)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		}
-
-		if !found {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-
 		dbPipeline := pdbh.pipelineDBFactory.Build(savedDBPipeline)
 
 		pipelineScopedHandler(dbPipeline, pipeline).ServeHTTP(w, r)
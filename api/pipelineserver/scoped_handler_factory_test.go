@@ -30,13 +30,17 @@ var _ = Describe("Handler", func() {
 		fakePipeline  *dbngfakes.FakePipeline
 
 		handler http.Handler
+
+		method string
 	)
 
 	BeforeEach(func() {
+		method = "GET"
+
 		teamDBFactory = new(dbfakes.FakeTeamDBFactory)
 		teamDB = new(dbfakes.FakeTeamDB)
 		teamDBFactory.GetTeamDBReturns(teamDB)
-		teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, true, nil)
+		teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{}, true, nil)
 
 		pipelineDB = new(dbfakes.FakePipelineDB)
 		delegate = &delegateHandler{}
@@ -58,7 +62,7 @@ var _ = Describe("Handler", func() {
 	JustBeforeEach(func() {
 		server = httptest.NewServer(handler)
 
-		request, err := http.NewRequest("POST", server.URL+"?:team_name=some-team&:pipeline_name=some-pipeline", nil)
+		request, err := http.NewRequest(method, server.URL+"?:team_name=some-team&:pipeline_name=some-pipeline", nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		response, err = new(http.Client).Do(request)
@@ -86,7 +90,7 @@ var _ = Describe("Handler", func() {
 	Context("when pipelineDB is not in request context", func() {
 		Context("when pipeline does not exist", func() {
 			BeforeEach(func() {
-				teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, nil)
+				teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{}, false, nil)
 			})
 
 			It("returns 404", func() {
@@ -100,7 +104,7 @@ var _ = Describe("Handler", func() {
 
 		Context("when pipeline exists", func() {
 			BeforeEach(func() {
-				teamDB.GetPipelineByNameReturns(db.SavedPipeline{Pipeline: db.Pipeline{Name: "some-pipeline"}}, true, nil)
+				teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{Pipeline: db.Pipeline{Name: "some-pipeline"}}, true, nil)
 			})
 
 			It("looks up the team by the right name", func() {
@@ -109,8 +113,8 @@ var _ = Describe("Handler", func() {
 			})
 
 			It("looks up the pipeline by the right name", func() {
-				Expect(teamDB.GetPipelineByNameCallCount()).To(Equal(1))
-				Expect(teamDB.GetPipelineByNameArgsForCall(0)).To(Equal("some-pipeline"))
+				Expect(teamDB.GetPipelineByNameIgnoringCaseCallCount()).To(Equal(1))
+				Expect(teamDB.GetPipelineByNameIgnoringCaseArgsForCall(0)).To(Equal("some-pipeline"))
 			})
 
 			It("returns 200", func() {
@@ -121,6 +125,58 @@ var _ = Describe("Handler", func() {
 				Expect(delegate.IsCalled).To(BeTrue())
 			})
 		})
+
+		Context("when the pipeline name only matches ignoring case", func() {
+			BeforeEach(func() {
+				teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{Pipeline: db.Pipeline{Name: "Some-Pipeline"}}, true, nil)
+			})
+
+			It("looks up the pipeline by its stored name", func() {
+				Expect(fakeTeam.PipelineCallCount()).To(Equal(1))
+				Expect(fakeTeam.PipelineArgsForCall(0)).To(Equal("Some-Pipeline"))
+			})
+
+			It("calls the scoped handler", func() {
+				Expect(delegate.IsCalled).To(BeTrue())
+			})
+
+			Context("when the request is destructive", func() {
+				BeforeEach(func() {
+					method = "DELETE"
+					teamDB.GetPipelineByNameReturns(db.SavedPipeline{}, false, nil)
+				})
+
+				It("only looks the pipeline up by its exact name", func() {
+					Expect(teamDB.GetPipelineByNameIgnoringCaseCallCount()).To(BeZero())
+					Expect(teamDB.GetPipelineByNameArgsForCall(0)).To(Equal("some-pipeline"))
+				})
+
+				It("returns 404", func() {
+					Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+				})
+
+				It("does not call the scoped handler", func() {
+					Expect(delegate.IsCalled).To(BeFalse())
+				})
+			})
+		})
+
+		Context("when the pipeline name is ambiguous", func() {
+			BeforeEach(func() {
+				teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{}, false, db.AmbiguousPipelineNameError{
+					Name:    "some-pipeline",
+					Matches: []string{"Some-Pipeline", "SOME-PIPELINE"},
+				})
+			})
+
+			It("returns 409", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusConflict))
+			})
+
+			It("does not call the scoped handler", func() {
+				Expect(delegate.IsCalled).To(BeFalse())
+			})
+		})
 	})
 })
 
@@ -13,6 +13,14 @@ type Server struct {
 	teamDBFactory   db.TeamDBFactory
 	rejector        auth.Rejector
 	pipelineFactory dbng.PipelineFactory
+	pipelinesDB     PipelinesDB
+}
+
+//go:generate counterfeiter . PipelinesDB
+
+type PipelinesDB interface {
+	PauseAllPipelines(teamName string) (int, error)
+	UnpauseAllPipelines(teamName string) (int, error)
 }
 
 func NewServer(
@@ -20,6 +28,7 @@ func NewServer(
 	teamFactory dbng.TeamFactory,
 	teamDBFactory db.TeamDBFactory,
 	pipelineFactory dbng.PipelineFactory,
+	pipelinesDB PipelinesDB,
 ) *Server {
 	return &Server{
 		logger:          logger,
@@ -27,5 +36,6 @@ func NewServer(
 		teamDBFactory:   teamDBFactory,
 		rejector:        auth.UnauthorizedRejector{},
 		pipelineFactory: pipelineFactory,
+		pipelinesDB:     pipelinesDB,
 	}
 }
@@ -53,6 +53,8 @@ func Build(build dbng.Build) atc.Build {
 		TeamName:     build.TeamName(),
 		URL:          reqURL,
 		APIURL:       apiURL,
+
+		TriggerReason: string(build.TriggerReason()),
 	}
 
 	if !build.StartTime().IsZero() {
@@ -113,6 +115,8 @@ func DBBuild(build db.Build) atc.Build {
 		TeamName:     build.TeamName(),
 		URL:          reqURL,
 		APIURL:       apiURL,
+
+		TriggerReason: string(build.TriggerReason()),
 	}
 
 	if !build.StartTime().IsZero() {
@@ -18,15 +18,22 @@ func Pipeline(savedPipeline dbng.Pipeline) atc.Pipeline {
 		panic("failed to generate url: " + err.Error())
 	}
 
-	return atc.Pipeline{
+	pipeline := atc.Pipeline{
 		ID:       savedPipeline.ID(),
 		Name:     savedPipeline.Name(),
 		TeamName: savedPipeline.TeamName(),
 		URL:      pathForRoute,
 		Paused:   savedPipeline.Paused(),
 		Public:   savedPipeline.Public(),
+		Archived: savedPipeline.Archived(),
 		Groups:   savedPipeline.Config().Groups,
 	}
+
+	if !savedPipeline.LastUpdated().IsZero() {
+		pipeline.LastUpdated = savedPipeline.LastUpdated().Unix()
+	}
+
+	return pipeline
 }
 func DBPipeline(savedPipeline db.SavedPipeline) atc.Pipeline {
 	pathForRoute, err := web.Routes.CreatePathForRoute(web.Pipeline, rata.Params{
@@ -38,13 +45,20 @@ func DBPipeline(savedPipeline db.SavedPipeline) atc.Pipeline {
 		panic("failed to generate url: " + err.Error())
 	}
 
-	return atc.Pipeline{
+	pipeline := atc.Pipeline{
 		ID:       savedPipeline.ID,
 		Name:     savedPipeline.Name,
 		TeamName: savedPipeline.TeamName,
 		URL:      pathForRoute,
 		Paused:   savedPipeline.Paused,
 		Public:   savedPipeline.Public,
+		Archived: savedPipeline.Archived,
 		Groups:   savedPipeline.Config.Groups,
 	}
+
+	if !savedPipeline.LastUpdated.IsZero() {
+		pipeline.LastUpdated = savedPipeline.LastUpdated.Unix()
+	}
+
+	return pipeline
 }
@@ -27,6 +27,7 @@ var _ = Describe("Resources API", func() {
 		pipelineDBFactory.BuildReturns(fakePipelineDB)
 		expectedSavedPipeline = db.SavedPipeline{}
 		teamDB.GetPipelineByNameReturns(expectedSavedPipeline, true, nil)
+		teamDB.GetPipelineByNameIgnoringCaseReturns(expectedSavedPipeline, true, nil)
 	})
 
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources", func() {
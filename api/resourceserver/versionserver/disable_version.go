@@ -4,12 +4,15 @@ import (
 	"net/http"
 	"strconv"
 
+	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/dbng"
 	"github.com/tedsuo/rata"
 )
 
-func (s *Server) DisableResourceVersion(pipelineDB db.PipelineDB, _ dbng.Pipeline) http.Handler {
+const disabledTriggerAbortReason = "aborted: the resource version that triggered this build was disabled"
+
+func (s *Server) DisableResourceVersion(pipelineDB db.PipelineDB, dbPipeline dbng.Pipeline) http.Handler {
 	logger := s.logger.Session("disable-resource-version")
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resourceID, err := strconv.Atoi(rata.Param(r, "resource_version_id"))
@@ -25,6 +28,29 @@ func (s *Server) DisableResourceVersion(pipelineDB db.PipelineDB, _ dbng.Pipelin
 			return
 		}
 
+		// pending builds determine their inputs when they start, so they would
+		// not use the disabled version; but the reason they were created for is
+		// gone, so don't let them run
+		pendingBuilds, err := dbPipeline.GetPendingBuildsTriggeredByVersion(resourceID)
+		if err != nil {
+			logger.Error("failed-to-get-pending-builds-triggered-by-version", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		for _, build := range pendingBuilds {
+			aborted, err := build.AbortPending(disabledTriggerAbortReason)
+			if err != nil {
+				logger.Error("failed-to-abort-pending-build", err, lager.Data{"build": build.ID()})
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			if aborted {
+				logger.Info("aborted-pending-build", lager.Data{"build": build.ID()})
+			}
+		}
+
 		w.WriteHeader(http.StatusOK)
 	})
 }
@@ -12,6 +12,8 @@ import (
 
 	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/db/dbfakes"
+	"github.com/concourse/atc/dbng"
+	"github.com/concourse/atc/dbng/dbngfakes"
 )
 
 var _ = Describe("Versions API", func() {
@@ -23,6 +25,7 @@ var _ = Describe("Versions API", func() {
 		pipelineDBFactory.BuildReturns(pipelineDB)
 		expectedSavedPipeline = db.SavedPipeline{}
 		teamDB.GetPipelineByNameReturns(expectedSavedPipeline, true, nil)
+		teamDB.GetPipelineByNameIgnoringCaseReturns(expectedSavedPipeline, true, nil)
 	})
 
 	Describe("GET /api/v1/teams/:team_name/pipelines/:pipeline_name/resources/:resource_name/versions", func() {
@@ -346,6 +349,56 @@ var _ = Describe("Versions API", func() {
 				It("returns 200", func() {
 					Expect(response.StatusCode).To(Equal(http.StatusOK))
 				})
+
+				Context("when pending builds were triggered by the version", func() {
+					var (
+						fakeBuild1 *dbngfakes.FakeBuild
+						fakeBuild2 *dbngfakes.FakeBuild
+					)
+
+					BeforeEach(func() {
+						fakeBuild1 = new(dbngfakes.FakeBuild)
+						fakeBuild1.AbortPendingReturns(true, nil)
+
+						fakeBuild2 = new(dbngfakes.FakeBuild)
+						fakeBuild2.AbortPendingReturns(false, nil)
+
+						fakePipeline.GetPendingBuildsTriggeredByVersionReturns([]dbng.Build{fakeBuild1, fakeBuild2}, nil)
+					})
+
+					It("aborts them with a reason", func() {
+						Expect(fakePipeline.GetPendingBuildsTriggeredByVersionArgsForCall(0)).To(Equal(42))
+
+						Expect(fakeBuild1.AbortPendingCallCount()).To(Equal(1))
+						Expect(fakeBuild1.AbortPendingArgsForCall(0)).To(ContainSubstring("version that triggered this build was disabled"))
+
+						Expect(fakeBuild2.AbortPendingCallCount()).To(Equal(1))
+					})
+
+					It("returns 200", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+					})
+
+					Context("when aborting a build fails", func() {
+						BeforeEach(func() {
+							fakeBuild1.AbortPendingReturns(false, errors.New("welp"))
+						})
+
+						It("returns 500", func() {
+							Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+						})
+					})
+				})
+
+				Context("when getting the pending builds fails", func() {
+					BeforeEach(func() {
+						fakePipeline.GetPendingBuildsTriggeredByVersionReturns(nil, errors.New("welp"))
+					})
+
+					It("returns 500", func() {
+						Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+					})
+				})
 			})
 
 			Context("when enabling the resource fails", func() {
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	mathrand "math/rand"
 	"net/http"
 	_ "net/http/pprof"
 	"net/url"
@@ -61,6 +62,8 @@ import (
 	// dynamically registered auth providers
 	_ "github.com/concourse/atc/auth/genericoauth"
 	_ "github.com/concourse/atc/auth/github"
+	_ "github.com/concourse/atc/auth/gitlab"
+	_ "github.com/concourse/atc/auth/ldap"
 	_ "github.com/concourse/atc/auth/uaa"
 
 	// dynamically registered metric emitters
@@ -89,6 +92,8 @@ type ATCCommand struct {
 
 	AuthDuration time.Duration `long:"auth-duration" default:"24h" description:"Length of time for which tokens are valid. Afterwards, users will have to log back in."`
 
+	MembershipCacheTTL time.Duration `long:"auth-membership-cache-ttl" default:"1m" description:"Length of time for which a user's membership, as checked with an auth provider, is reused. Denied checks are reused for at most 5 seconds."`
+
 	Postgres PostgresConfig `group:"PostgreSQL Configuration" namespace:"postgres"`
 
 	DebugBindIP   IPFlag `long:"debug-bind-ip"   default:"127.0.0.1" description:"IP address on which to listen for the pprof debugger endpoints."`
@@ -130,7 +135,27 @@ type ATCCommand struct {
 
 	GCInterval time.Duration `long:"gc-interval" default:"30s" description:"Interval on which to perform garbage collection."`
 
+	StalledWorkerGracePeriod time.Duration `long:"stalled-worker-grace-period" default:"0" description:"How long a stalled worker may go without heartbeating before it is removed. If 0, stalled workers are kept until they are pruned."`
+
 	BuildTrackerInterval time.Duration `long:"build-tracker-interval" default:"10s" description:"Interval on which to run build tracking."`
+
+	ImageFetchRetries int `long:"image-fetch-retries" default:"3" description:"Number of times to retry checking or fetching a task's image resource after a transient network error."`
+
+	InputStreamBufferLimit  int64         `long:"input-stream-buffer-limit"  default:"10485760" description:"Size in bytes up to which an input is buffered in memory while it is streamed into a container, so that the stream can be retried after a transient error."`
+	InputStreamAttempts     int           `long:"input-stream-attempts"      default:"3"        description:"Number of times to try streaming an input into a container when it fails with a transient error."`
+	InputStreamRetryBackoff time.Duration `long:"input-stream-retry-backoff" default:"1s"       description:"How long to wait before retrying a failed input stream. Doubles with each retry."`
+	InputStreamMaxBackoff   time.Duration `long:"input-stream-max-backoff"   default:"30s"      description:"Maximum time to wait between retries of a failed input stream."`
+
+	DedupeTaskOutputs bool `long:"dedupe-task-outputs" description:"Hash each task output on its worker and reuse an earlier output in the build with identical contents on the same worker. Hashing runs a process in every task container that has outputs."`
+
+	MaxEventStoreBacklog int `long:"max-event-store-backlog" default:"0" description:"Defer starting new builds while more than this many build events are waiting to be written by this ATC. 0 means no limit."`
+
+	ContainerPlacementStrategy string `long:"container-placement-strategy" default:"random" choice:"random" choice:"capacity-weighted" choice:"fewest-containers" description:"Method by which a worker is chosen for a new container."`
+	MaxContainersPerWorker     int    `long:"max-containers-per-worker" default:"250" description:"Number of containers a worker is assumed to be able to run, used by the capacity-weighted placement strategy and when reporting cluster capacity."`
+	TeamWorkerAffinity         string `long:"team-worker-affinity" default:"strict" choice:"strict" choice:"preferred" description:"Whether a team's containers are only placed on its own workers (strict), or also on general workers once its own have reached --max-containers-per-worker (preferred)."`
+
+	TaggedWorkerWindows   []TimeWindowFlag `long:"tagged-worker-window"     description:"Daily window (HH:MM-HH:MM, in the ATC's local time) during which workers with the tagged-worker-window-tag are preferred. Outside of all windows they are avoided. Can be specified multiple times."`
+	TaggedWorkerWindowTag string           `long:"tagged-worker-window-tag" default:"spot" description:"Tag identifying the workers preferred during the tagged-worker-windows."`
 }
 
 func (cmd *ATCCommand) WireDynamicFlags(commandFlags *flags.Command) {
@@ -254,6 +279,7 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		dbResourceConfigFactory,
 		dbWorkerBaseResourceTypeFactory,
 		dbVolumeFactory,
+		dbContainerFactory,
 		dbWorkerFactory,
 		dbTeamFactory,
 		workerVersion,
@@ -262,12 +288,15 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 	resourceFetcher := resourceFetcherFactory.FetcherFor(workerClient)
 	resourceFactory := resourceFactoryFactory.FactoryFor(workerClient)
 	teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
-	engine := cmd.constructEngine(workerClient, resourceFetcher, resourceFactory, dbResourceCacheFactory, teamDBFactory)
+	engine := cmd.constructEngine(workerClient, resourceFetcher, resourceFactory, dbResourceCacheFactory, dbBuildFactory, teamDBFactory, sqlDB)
 
 	radarSchedulerFactory := pipelines.NewRadarSchedulerFactory(
 		resourceFactory,
 		cmd.ResourceCheckingInterval,
 		engine,
+		dbng.NewSchedulerSettings(dbngConn),
+		dbBuildFactory,
+		cmd.MaxEventStoreBacklog,
 	)
 
 	radarScannerFactory := radar.NewScannerFactory(
@@ -301,6 +330,8 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		return nil, err
 	}
 
+	membershipCache := provider.NewMembershipCache(clock.NewClock(), cmd.MembershipCacheTTL)
+
 	drain := make(chan struct{})
 
 	apiHandler, err := cmd.constructAPIHandler(
@@ -315,6 +346,7 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		dbContainerFactory,
 		dbBuildFactory,
 		providerFactory,
+		membershipCache,
 		signingKey,
 		pipelineDBFactory,
 		engine,
@@ -335,6 +367,7 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 		signingKey,
 		cmd.AuthDuration,
 		cmd.isTLSEnabled(),
+		membershipCache,
 	)
 	if err != nil {
 		return nil, err
@@ -425,6 +458,13 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 			Clock:    clock.NewClock(),
 		}},
 
+		{"db-pool-stats", dbng.PoolStatsReporter{
+			Logger:   logger.Session("db-pool-stats"),
+			Source:   dbngConn,
+			Interval: 10 * time.Second,
+			Clock:    clock.NewClock(),
+		}},
+
 		{"builds", builds.TrackerRunner{
 			Tracker: builds.NewTracker(
 				logger.Session("build-tracker"),
@@ -449,6 +489,7 @@ func (cmd *ATCCommand) Runner(args []string) (ifrit.Runner, error) {
 				gcng.NewWorkerCollector(
 					logger.Session("worker-collector"),
 					dbWorkerLifecycle,
+					cmd.StalledWorkerGracePeriod,
 				),
 				gcng.NewResourceCacheUseCollector(
 					logger.Session("resource-cache-use-collector"),
@@ -625,6 +666,20 @@ func (cmd *ATCCommand) validate() error {
 		)
 	}
 
+	if cmd.InputStreamBufferLimit < 0 {
+		errs = multierror.Append(
+			errs,
+			errors.New("--input-stream-buffer-limit must not be negative"),
+		)
+	}
+
+	if cmd.InputStreamAttempts < 1 {
+		errs = multierror.Append(
+			errs,
+			errors.New("--input-stream-attempts must be at least 1"),
+		)
+	}
+
 	return errs.ErrorOrNil()
 }
 
@@ -673,7 +728,7 @@ func (cmd *ATCCommand) configureMetrics(logger lager.Logger) {
 }
 
 func (cmd *ATCCommand) constructDBConn(driverName string, logger lager.Logger) (db.Conn, dbng.Conn, error) {
-	dbngConn, err := dbng.Open(logger.Session("db"), driverName, cmd.Postgres.ConnectionString())
+	dbngConn, err := dbng.Open(logger.Session("db"), driverName, cmd.Postgres.ConnectionString(), cmd.Postgres.ReplicaDataSource, dbng.DefaultRetryPolicy)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to migrate database: %s", err)
 	}
@@ -711,6 +766,7 @@ func (cmd *ATCCommand) constructWorkerPool(
 	dbResourceConfigFactory dbng.ResourceConfigFactory,
 	dbWorkerBaseResourceTypeFactory dbng.WorkerBaseResourceTypeFactory,
 	dbVolumeFactory dbng.VolumeFactory,
+	dbContainerFactory dbng.ContainerFactory,
 	dbWorkerFactory dbng.WorkerFactory,
 	dbTeamFactory dbng.TeamFactory,
 	workerVersion *version.Version,
@@ -720,6 +776,7 @@ func (cmd *ATCCommand) constructWorkerPool(
 		resourceFactoryFactory,
 		dbResourceCacheFactory,
 		dbResourceConfigFactory,
+		cmd.ImageFetchRetries,
 		clock.NewClock(),
 	)
 	return worker.NewPool(
@@ -731,10 +788,56 @@ func (cmd *ATCCommand) constructWorkerPool(
 			dbResourceConfigFactory,
 			dbWorkerBaseResourceTypeFactory,
 			dbVolumeFactory,
+			dbContainerFactory,
 			dbTeamFactory,
 			dbWorkerFactory,
 			workerVersion,
+			cmd.inputStreamPolicy(),
 		),
+		cmd.constructSelectionStrategy(),
+		cmd.constructSelectionConstraint(),
+		cmd.MaxContainersPerWorker,
+		worker.TeamWorkerAffinity(cmd.TeamWorkerAffinity),
+		cmd.inputStreamPolicy(),
+	)
+}
+
+func (cmd *ATCCommand) inputStreamPolicy() worker.StreamInRetryPolicy {
+	return worker.StreamInRetryPolicy{
+		BufferLimit: cmd.InputStreamBufferLimit,
+		Attempts:    cmd.InputStreamAttempts,
+		Backoff:     cmd.InputStreamRetryBackoff,
+		MaxBackoff:  cmd.InputStreamMaxBackoff,
+	}
+}
+
+func (cmd *ATCCommand) constructSelectionStrategy() worker.SelectionStrategy {
+	source := mathrand.NewSource(time.Now().UnixNano())
+
+	switch cmd.ContainerPlacementStrategy {
+	case "capacity-weighted":
+		return worker.NewCapacityWeightedStrategy(cmd.MaxContainersPerWorker, source)
+	case "fewest-containers":
+		return worker.NewFewestContainersStrategy(source)
+	default:
+		return worker.NewRandomStrategy(source)
+	}
+}
+
+func (cmd *ATCCommand) constructSelectionConstraint() worker.SelectionConstraint {
+	if len(cmd.TaggedWorkerWindows) == 0 {
+		return nil
+	}
+
+	windows := make([]worker.TimeWindow, len(cmd.TaggedWorkerWindows))
+	for i, window := range cmd.TaggedWorkerWindows {
+		windows[i] = worker.TimeWindow(window)
+	}
+
+	return worker.NewTimeWindowConstraint(
+		clock.NewClock(),
+		cmd.TaggedWorkerWindowTag,
+		windows,
 	)
 }
 
@@ -806,19 +909,23 @@ func (cmd *ATCCommand) constructEngine(
 	resourceFetcher resource.Fetcher,
 	resourceFactory resource.ResourceFactory,
 	dbResourceCacheFactory dbng.ResourceCacheFactory,
+	dbBuildFactory dbng.BuildFactory,
 	teamDBFactory db.TeamDBFactory,
+	sqlDB db.DB,
 ) engine.Engine {
 	gardenFactory := exec.NewGardenFactory(
 		workerClient,
 		resourceFetcher,
 		resourceFactory,
 		dbResourceCacheFactory,
+		cmd.DedupeTaskOutputs,
 	)
 
 	execV2Engine := engine.NewExecEngine(
 		gardenFactory,
-		engine.NewBuildDelegateFactory(),
+		engine.NewBuildDelegateFactory(dbBuildFactory),
 		teamDBFactory,
+		sqlDB,
 		cmd.ExternalURL.String(),
 	)
 
@@ -870,6 +977,7 @@ func (cmd *ATCCommand) constructAPIHandler(
 	dbContainerFactory dbng.ContainerFactory,
 	dbBuildFactory dbng.BuildFactory,
 	providerFactory auth.OAuthFactory,
+	membershipCache *provider.MembershipCache,
 	signingKey *rsa.PrivateKey,
 	pipelineDBFactory db.PipelineDBFactory,
 	engine engine.Engine,
@@ -882,10 +990,11 @@ func (cmd *ATCCommand) constructAPIHandler(
 		PublicKey: &signingKey.PublicKey,
 	}
 
-	getTokenValidator := auth.NewTeamAuthValidator(dbTeamFactory, authValidator)
+	getTokenValidator := auth.NewTeamAuthValidator(dbTeamFactory, authValidator, membershipCache)
 
 	checkPipelineAccessHandlerFactory := auth.NewCheckPipelineAccessHandlerFactory(
 		dbTeamFactory,
+		teamDBFactory,
 	)
 
 	checkBuildReadAccessHandlerFactory := auth.NewCheckBuildReadAccessHandlerFactory(dbBuildFactory)
@@ -928,6 +1037,7 @@ func (cmd *ATCCommand) constructAPIHandler(
 		dbBuildFactory,
 
 		sqlDB, // pipes.PipeDB
+		sqlDB, // pipelineserver.PipelinesDB
 
 		cmd.PeerURL.String(),
 		buildserver.NewEventHandler,
@@ -24,6 +24,8 @@ type PostgresConfig struct {
 	ClientKey  FileFlag `long:"client-key"  description:"Client key file location."`
 
 	Database string `long:"database" description:"The name of the database to use." default:"atc"`
+
+	ReplicaDataSource string `long:"replica-data-source" description:"PostgreSQL connection string for a read-only replica. When set, read-heavy queries such as build listings are sent to it."`
 }
 
 func (config PostgresConfig) ConnectionString() string {
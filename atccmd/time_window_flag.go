@@ -0,0 +1,16 @@
+package atccmd
+
+import "github.com/concourse/atc/worker"
+
+type TimeWindowFlag worker.TimeWindow
+
+func (f *TimeWindowFlag) UnmarshalFlag(value string) error {
+	window, err := worker.ParseTimeWindow(value)
+	if err != nil {
+		return err
+	}
+
+	*f = TimeWindowFlag(window)
+
+	return nil
+}
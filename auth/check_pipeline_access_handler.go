@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/concourse/atc/db"
 	"github.com/concourse/atc/dbng"
 )
 
@@ -12,14 +13,17 @@ type CheckPipelineAccessHandlerFactory interface {
 }
 
 type checkPipelineAccessHandlerFactory struct {
-	teamFactory dbng.TeamFactory
+	teamFactory   dbng.TeamFactory
+	teamDBFactory db.TeamDBFactory
 }
 
 func NewCheckPipelineAccessHandlerFactory(
 	teamFactory dbng.TeamFactory,
+	teamDBFactory db.TeamDBFactory,
 ) *checkPipelineAccessHandlerFactory {
 	return &checkPipelineAccessHandlerFactory{
-		teamFactory: teamFactory,
+		teamFactory:   teamFactory,
+		teamDBFactory: teamDBFactory,
 	}
 }
 
@@ -30,6 +34,7 @@ func (f *checkPipelineAccessHandlerFactory) HandlerFor(
 	return checkPipelineAccessHandler{
 		rejector:        rejector,
 		teamFactory:     f.teamFactory,
+		teamDBFactory:   f.teamDBFactory,
 		delegateHandler: delegateHandler,
 	}
 }
@@ -37,6 +42,7 @@ func (f *checkPipelineAccessHandlerFactory) HandlerFor(
 type checkPipelineAccessHandler struct {
 	rejector        Rejector
 	teamFactory     dbng.TeamFactory
+	teamDBFactory   db.TeamDBFactory
 	delegateHandler http.Handler
 }
 
@@ -54,6 +60,7 @@ func (h checkPipelineAccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+
 	pipeline, found, err := team.Pipeline(pipelineName)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -61,8 +68,39 @@ func (h checkPipelineAccessHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 	}
 
 	if !found {
-		w.WriteHeader(http.StatusNotFound)
-		return
+		// only read-only requests may name the pipeline ignoring case; anything
+		// that could change or delete it must name it exactly
+		if !IsReadOnly(r) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		savedPipeline, found, err := h.teamDBFactory.GetTeamDB(requestTeamName).GetPipelineByNameIgnoringCase(pipelineName)
+		if err != nil {
+			if _, ok := err.(db.AmbiguousPipelineNameError); ok {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		pipeline, found, err = team.Pipeline(savedPipeline.Name)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
 	}
 
 	if IsAuthorized(r) || pipeline.Public() {
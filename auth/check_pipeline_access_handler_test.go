@@ -7,6 +7,8 @@ import (
 
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/dbfakes"
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/dbng/dbngfakes"
 
@@ -24,18 +26,29 @@ var _ = Describe("CheckPipelineAccessHandler", func() {
 		pipeline    *dbngfakes.FakePipeline
 		handler     http.Handler
 
+		teamDBFactory *dbfakes.FakeTeamDBFactory
+		teamDB        *dbfakes.FakeTeamDB
+
 		authValidator     *authfakes.FakeValidator
 		userContextReader *authfakes.FakeUserContextReader
+
+		method string
 	)
 
 	BeforeEach(func() {
+		method = "GET"
+
 		teamFactory = new(dbngfakes.FakeTeamFactory)
 		team = new(dbngfakes.FakeTeam)
 		teamFactory.FindTeamReturns(team, true, nil)
 
 		pipeline = new(dbngfakes.FakePipeline)
 
-		handlerFactory := auth.NewCheckPipelineAccessHandlerFactory(teamFactory)
+		teamDBFactory = new(dbfakes.FakeTeamDBFactory)
+		teamDB = new(dbfakes.FakeTeamDB)
+		teamDBFactory.GetTeamDBReturns(teamDB)
+
+		handlerFactory := auth.NewCheckPipelineAccessHandlerFactory(teamFactory, teamDBFactory)
 
 		authValidator = new(authfakes.FakeValidator)
 		userContextReader = new(authfakes.FakeUserContextReader)
@@ -48,7 +61,7 @@ var _ = Describe("CheckPipelineAccessHandler", func() {
 	JustBeforeEach(func() {
 		server = httptest.NewServer(handler)
 
-		request, err := http.NewRequest("POST", server.URL+"?:team_name=some-team&:pipeline_name=some-pipeline", nil)
+		request, err := http.NewRequest(method, server.URL+"?:team_name=some-team&:pipeline_name=some-pipeline", nil)
 		Expect(err).NotTo(HaveOccurred())
 
 		response, err = new(http.Client).Do(request)
@@ -139,6 +152,68 @@ var _ = Describe("CheckPipelineAccessHandler", func() {
 		})
 	})
 
+	Context("when the pipeline name only matches ignoring case", func() {
+		BeforeEach(func() {
+			pipeline.PublicReturns(true)
+			team.PipelineStub = func(name string) (dbng.Pipeline, bool, error) {
+				if name == "Some-Pipeline" {
+					return pipeline, true, nil
+				}
+
+				return nil, false, nil
+			}
+
+			teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{Pipeline: db.Pipeline{Name: "Some-Pipeline"}}, true, nil)
+		})
+
+		It("resolves the pipeline by its stored name", func() {
+			Expect(teamDBFactory.GetTeamDBArgsForCall(0)).To(Equal("some-team"))
+			Expect(teamDB.GetPipelineByNameIgnoringCaseArgsForCall(0)).To(Equal("some-pipeline"))
+			Expect(team.PipelineArgsForCall(1)).To(Equal("Some-Pipeline"))
+		})
+
+		It("calls pipelineScopedHandler with pipelineDB in context", func() {
+			Expect(delegate.IsCalled).To(BeTrue())
+			Expect(delegate.ContextPipelineDB).To(BeIdenticalTo(pipeline))
+		})
+
+		Context("when the request is destructive", func() {
+			BeforeEach(func() {
+				method = "DELETE"
+			})
+
+			It("returns 404", func() {
+				Expect(response.StatusCode).To(Equal(http.StatusNotFound))
+			})
+
+			It("does not look the pipeline up ignoring case", func() {
+				Expect(teamDB.GetPipelineByNameIgnoringCaseCallCount()).To(BeZero())
+			})
+
+			It("does not call the scoped handler", func() {
+				Expect(delegate.IsCalled).To(BeFalse())
+			})
+		})
+	})
+
+	Context("when the pipeline name is ambiguous ignoring case", func() {
+		BeforeEach(func() {
+			team.PipelineReturns(nil, false, nil)
+			teamDB.GetPipelineByNameIgnoringCaseReturns(db.SavedPipeline{}, false, db.AmbiguousPipelineNameError{
+				Name:    "some-pipeline",
+				Matches: []string{"Some-Pipeline", "SOME-PIPELINE"},
+			})
+		})
+
+		It("returns 409", func() {
+			Expect(response.StatusCode).To(Equal(http.StatusConflict))
+		})
+
+		It("does not call the scoped handler", func() {
+			Expect(delegate.IsCalled).To(BeFalse())
+		})
+	})
+
 	Context("when getting pipeline fails", func() {
 		BeforeEach(func() {
 			team.PipelineReturns(nil, false, errors.New("disaster"))
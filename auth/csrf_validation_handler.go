@@ -36,7 +36,7 @@ func (h csrfValidationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	// We don't validate CSRF token for GET requests
 	// since they are not changing the state
-	if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+	if !IsReadOnly(r) {
 		isCSRFRequired, ok := r.Context().Value(CSRFRequiredKey).(bool)
 		if ok && isCSRFRequired {
 			if r.Header.Get(CSRFHeaderName) == "" {
@@ -0,0 +1,111 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+//go:generate counterfeiter . Client
+
+type Client interface {
+	CurrentUser(*http.Client) (string, error)
+	Groups(*http.Client) ([]string, error)
+}
+
+type client struct {
+	apiURL string
+}
+
+// NewClient returns a Client for the GitLab v4 API at apiURL, e.g.
+// https://gitlab.com/api/v4.
+func NewClient(apiURL string) Client {
+	return &client{apiURL: apiURL}
+}
+
+type user struct {
+	Username string `json:"username"`
+}
+
+// guestAccessLevel is the lowest access level a group member can have.
+const guestAccessLevel = 10
+
+type group struct {
+	FullPath string `json:"full_path"`
+}
+
+func (c *client) CurrentUser(httpClient *http.Client) (string, error) {
+	var currentUser user
+
+	_, err := c.get(httpClient, "/user", &currentUser)
+	if err != nil {
+		return "", err
+	}
+
+	return currentUser.Username, nil
+}
+
+// Groups returns the full path of every group the user is a member of,
+// including subgroups, e.g. "some-group/some-subgroup". Without a minimum
+// access level GitLab lists every group to admins, so membership is required
+// explicitly.
+func (c *client) Groups(httpClient *http.Client) ([]string, error) {
+	nextPage := 1
+	groups := []string{}
+
+	for nextPage != 0 {
+		var page []group
+
+		resp, err := c.get(httpClient, fmt.Sprintf("/groups?min_access_level=%d&page=%d", guestAccessLevel, nextPage), &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range page {
+			groups = append(groups, g.FullPath)
+		}
+
+		nextPage = 0
+		if next := resp.Header.Get("X-Next-Page"); next != "" {
+			nextPage, err = strconv.Atoi(next)
+			if err != nil {
+				return nil, fmt.Errorf("invalid next page '%s' from gitlab", next)
+			}
+		}
+	}
+
+	return groups, nil
+}
+
+func (c *client) get(httpClient *http.Client, path string, result interface{}) (*http.Response, error) {
+	resp, err := httpClient.Get(c.apiURL + path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, UnexpectedResponseError{
+			Path:       path,
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+type UnexpectedResponseError struct {
+	Path       string
+	StatusCode int
+}
+
+func (err UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("unexpected response from gitlab for %s: %d", err.Path, err.StatusCode)
+}
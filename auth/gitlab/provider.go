@@ -0,0 +1,159 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"fmt"
+
+	"encoding/json"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/routes"
+	"github.com/concourse/atc/auth/verifier"
+	"github.com/hashicorp/go-multierror"
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tedsuo/rata"
+)
+
+const ProviderName = "gitlab"
+const DisplayName = "GitLab"
+
+const DefaultURL = "https://gitlab.com"
+
+var Scopes = []string{"api"}
+
+type GitLabAuthConfig struct {
+	ClientID     string `json:"client_id"     long:"client-id"     description:"Application client ID for enabling GitLab OAuth."`
+	ClientSecret string `json:"client_secret" long:"client-secret" description:"Application client secret for enabling GitLab OAuth."`
+
+	Groups []string `json:"groups,omitempty" long:"group" description:"GitLab group whose members will have access." value-name:"GROUP"`
+	Users  []string `json:"users,omitempty"  long:"user"  description:"GitLab user to permit access." value-name:"USERNAME"`
+	URL    string   `json:"url,omitempty"    long:"url"   description:"Override the default GitLab URL for self-hosted GitLab."`
+}
+
+func (*GitLabAuthConfig) AuthMethod(oauthBaseURL string, teamName string) atc.AuthMethod {
+	path, err := routes.OAuthRoutes.CreatePathForRoute(
+		routes.OAuthBegin,
+		rata.Params{"provider": ProviderName},
+	)
+	if err != nil {
+		panic("failed to construct oauth begin handler route: " + err.Error())
+	}
+
+	path = path + fmt.Sprintf("?team_name=%s", teamName)
+
+	return atc.AuthMethod{
+		Type:        atc.AuthTypeOAuth,
+		DisplayName: DisplayName,
+		AuthURL:     oauthBaseURL + path,
+	}
+}
+
+func (auth *GitLabAuthConfig) IsConfigured() bool {
+	return auth.ClientID != "" ||
+		auth.ClientSecret != "" ||
+		len(auth.Groups) > 0 ||
+		len(auth.Users) > 0
+}
+
+func (auth *GitLabAuthConfig) Validate() error {
+	var errs *multierror.Error
+	if auth.ClientID == "" || auth.ClientSecret == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --gitlab-auth-client-id and --gitlab-auth-client-secret to use GitLab OAuth."),
+		)
+	}
+	if len(auth.Groups) == 0 && len(auth.Users) == 0 {
+		errs = multierror.Append(
+			errs,
+			errors.New("at least one of the following is required for gitlab-auth: groups, users."),
+		)
+	}
+	return errs.ErrorOrNil()
+}
+
+func (auth *GitLabAuthConfig) baseURL() string {
+	if auth.URL == "" {
+		return DefaultURL
+	}
+
+	return strings.TrimRight(auth.URL, "/")
+}
+
+type GitLabProvider struct {
+	*oauth2.Config
+	verifier.Verifier
+}
+
+func init() {
+	provider.Register(ProviderName, GitLabTeamProvider{})
+}
+
+type GitLabTeamProvider struct {
+}
+
+func (GitLabTeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	flags := &GitLabAuthConfig{}
+
+	glGroup, err := group.AddGroup("GitLab Authentication", "", flags)
+	if err != nil {
+		panic(err)
+	}
+
+	glGroup.Namespace = "gitlab-auth"
+
+	return flags
+}
+
+func (GitLabTeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	flags := &GitLabAuthConfig{}
+	if config != nil {
+		err := json.Unmarshal(*config, &flags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flags, nil
+}
+
+func (GitLabTeamProvider) ProviderConstructor(
+	config provider.AuthConfig,
+	redirectURL string,
+) (provider.Provider, bool) {
+	gitlabAuth := config.(*GitLabAuthConfig)
+
+	baseURL := gitlabAuth.baseURL()
+
+	client := NewClient(baseURL + "/api/v4")
+
+	return GitLabProvider{
+		Verifier: verifier.NewVerifierBasket(
+			NewGroupVerifier(gitlabAuth.Groups, client),
+			NewUserVerifier(gitlabAuth.Users, client),
+		),
+		Config: &oauth2.Config{
+			ClientID:     gitlabAuth.ClientID,
+			ClientSecret: gitlabAuth.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  baseURL + "/oauth/authorize",
+				TokenURL: baseURL + "/oauth/token",
+			},
+			Scopes:      Scopes,
+			RedirectURL: redirectURL,
+		},
+	}, true
+}
+
+func (GitLabProvider) PreTokenClient() (*http.Client, error) {
+	return &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+		},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package gitlab_test
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth/gitlab"
+	"github.com/concourse/atc/auth/provider"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitLab Provider", func() {
+	Describe("AuthMethod", func() {
+		var (
+			authMethod atc.AuthMethod
+			authConfig *gitlab.GitLabAuthConfig
+		)
+		BeforeEach(func() {
+			authConfig = &gitlab.GitLabAuthConfig{}
+			authMethod = authConfig.AuthMethod("http://bum-bum-bum.com", "dudududum")
+		})
+
+		It("creates path for route", func() {
+			Expect(authMethod).To(Equal(atc.AuthMethod{
+				Type:        atc.AuthTypeOAuth,
+				DisplayName: "GitLab",
+				AuthURL:     "http://bum-bum-bum.com/auth/gitlab?team_name=dudududum",
+			}))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("requires a client ID and secret", func() {
+			authConfig := &gitlab.GitLabAuthConfig{Users: []string{"some-user"}}
+			Expect(authConfig.Validate()).To(MatchError(ContainSubstring("--gitlab-auth-client-id")))
+		})
+
+		It("requires groups or users", func() {
+			authConfig := &gitlab.GitLabAuthConfig{ClientID: "some-id", ClientSecret: "some-secret"}
+			Expect(authConfig.Validate()).To(MatchError(ContainSubstring("groups, users")))
+		})
+
+		It("is valid with a client and a group", func() {
+			authConfig := &gitlab.GitLabAuthConfig{
+				ClientID:     "some-id",
+				ClientSecret: "some-secret",
+				Groups:       []string{"some-group"},
+			}
+			Expect(authConfig.Validate()).To(Succeed())
+		})
+	})
+
+	Describe("ProviderConstructor", func() {
+		var (
+			authConfig     *gitlab.GitLabAuthConfig
+			gitlabProvider provider.Provider
+		)
+
+		BeforeEach(func() {
+			authConfig = &gitlab.GitLabAuthConfig{
+				ClientID:     "some-id",
+				ClientSecret: "some-secret",
+			}
+		})
+
+		JustBeforeEach(func() {
+			var found bool
+			gitlabProvider, found = gitlab.GitLabTeamProvider{}.ProviderConstructor(authConfig, "http://some.redirect/url")
+			Expect(found).To(BeTrue())
+		})
+
+		It("authenticates against gitlab.com", func() {
+			Expect(gitlabProvider.AuthCodeURL("some-state")).To(HavePrefix("https://gitlab.com/oauth/authorize?"))
+		})
+
+		Context("when a URL is configured", func() {
+			BeforeEach(func() {
+				authConfig.URL = "https://gitlab.example.com/"
+			})
+
+			It("authenticates against it instead", func() {
+				Expect(gitlabProvider.AuthCodeURL("some-state")).To(HavePrefix("https://gitlab.example.com/oauth/authorize?"))
+			})
+		})
+	})
+})
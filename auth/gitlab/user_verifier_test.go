@@ -0,0 +1,89 @@
+package gitlab_test
+
+import (
+	"errors"
+	"net/http"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/concourse/atc/auth/gitlab"
+	"github.com/concourse/atc/auth/gitlab/gitlabfakes"
+	"github.com/concourse/atc/auth/verifier"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserVerifier", func() {
+	var (
+		users      []string
+		fakeClient *gitlabfakes.FakeClient
+
+		verifier verifier.Verifier
+	)
+
+	BeforeEach(func() {
+		users = []string{"some-user", "another-user"}
+		fakeClient = new(gitlabfakes.FakeClient)
+
+		verifier = NewUserVerifier(users, fakeClient)
+	})
+
+	Describe("Verify", func() {
+		var (
+			httpClient *http.Client
+
+			verified  bool
+			verifyErr error
+		)
+
+		BeforeEach(func() {
+			httpClient = &http.Client{}
+		})
+
+		JustBeforeEach(func() {
+			verified, verifyErr = verifier.Verify(lagertest.NewTestLogger("test"), httpClient)
+		})
+
+		Context("when the client yields a user", func() {
+			Context("that is one of the desired users", func() {
+				BeforeEach(func() {
+					fakeClient.CurrentUserReturns("another-user", nil)
+				})
+
+				It("succeeds", func() {
+					Expect(verifyErr).ToNot(HaveOccurred())
+				})
+
+				It("returns true", func() {
+					Expect(verified).To(BeTrue())
+				})
+			})
+
+			Context("that is not one of the desired users", func() {
+				BeforeEach(func() {
+					fakeClient.CurrentUserReturns("bogus-user", nil)
+				})
+
+				It("succeeds", func() {
+					Expect(verifyErr).ToNot(HaveOccurred())
+				})
+
+				It("returns false", func() {
+					Expect(verified).To(BeFalse())
+				})
+			})
+		})
+
+		Context("when the client fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeClient.CurrentUserReturns("", disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(verifyErr).To(Equal(disaster))
+			})
+		})
+	})
+})
@@ -0,0 +1,8 @@
+package auth
+
+import "net/http"
+
+// IsReadOnly reports whether the request's method cannot change any state.
+func IsReadOnly(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+}
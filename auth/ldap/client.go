@@ -0,0 +1,122 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+
+	goldap "gopkg.in/ldap.v2"
+)
+
+//go:generate counterfeiter . Client
+
+// Client is the subset of an LDAP connection used to authenticate users.
+type Client interface {
+	Bind(username, password string) error
+	Search(*goldap.SearchRequest) (*goldap.SearchResult, error)
+	Close()
+}
+
+const (
+	// TLSModeTLS connects over LDAPS. It is the default, since binding sends
+	// passwords in the clear.
+	TLSModeTLS = "tls"
+
+	// TLSModeStartTLS connects in plain text and upgrades the connection
+	// with StartTLS before binding.
+	TLSModeStartTLS = "starttls"
+
+	// TLSModeNone never encrypts the connection.
+	TLSModeNone = "none"
+)
+
+// ErrInvalidCACert is returned when the configured CA certificate contains
+// no PEM-encoded certificates.
+var ErrInvalidCACert = errors.New("ldap CA cert contains no PEM-encoded certificates")
+
+// ConnectionConfig describes how to reach and secure the LDAP server.
+type ConnectionConfig struct {
+	Host               string
+	TLSMode            string
+	CACert             string
+	InsecureSkipVerify bool
+}
+
+// TLSConfig builds the TLS settings for the connection, verifying the server
+// against CACert if given and the system roots otherwise.
+func (config ConnectionConfig) TLSConfig() (*tls.Config, error) {
+	serverName, _, err := net.SplitHostPort(config.Host)
+	if err != nil {
+		serverName = config.Host
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(config.CACert)) {
+			return nil, ErrInvalidCACert
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// Dialer opens a connection to the LDAP server.
+type Dialer func(ConnectionConfig) (Client, error)
+
+// Dial connects to the configured host, which may be given as host:port,
+// securing the connection according to its TLS mode.
+func Dial(config ConnectionConfig) (Client, error) {
+	switch config.TLSMode {
+	case TLSModeNone:
+		conn, err := goldap.Dial("tcp", config.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+
+	case TLSModeStartTLS:
+		tlsConfig, err := config.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := goldap.Dial("tcp", config.Host)
+		if err != nil {
+			return nil, err
+		}
+
+		err = conn.StartTLS(tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return conn, nil
+
+	case TLSModeTLS, "":
+		tlsConfig, err := config.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		conn, err := goldap.DialTLS("tcp", config.Host, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+
+	default:
+		return nil, fmt.Errorf("unknown ldap tls mode: %s", config.TLSMode)
+	}
+}
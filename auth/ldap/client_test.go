@@ -0,0 +1,75 @@
+package ldap_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/concourse/atc/auth/ldap"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const caCert = `-----BEGIN CERTIFICATE-----
+MIICsjCCAhugAwIBAgIJAJgyGeIL1aiPMA0GCSqGSIb3DQEBBQUAMEUxCzAJBgNV
+BAYTAkFVMRMwEQYDVQQIEwpTb21lLVN0YXRlMSEwHwYDVQQKExhJbnRlcm5ldCBX
+aWRnaXRzIFB0eSBMdGQwIBcNMTUwMzE5MjE1NzAxWhgPMjI4ODEyMzEyMTU3MDFa
+MEUxCzAJBgNVBAYTAkFVMRMwEQYDVQQIEwpTb21lLVN0YXRlMSEwHwYDVQQKExhJ
+bnRlcm5ldCBXaWRnaXRzIFB0eSBMdGQwgZ8wDQYJKoZIhvcNAQEBBQADgY0AMIGJ
+AoGBAOTD37e9wnQz5fHVPdQdU8rjokOVuFj0wBtQLNO7B2iN+URFaP2wi0KOU0ye
+njISc5M/mpua7Op72/cZ3+bq8u5lnQ8VcjewD1+f3LCq+Os7iE85A/mbEyT1Mazo
+GGo9L/gfz5kNq78L9cQp5lrD04wF0C05QtL8LVI5N9SqT7mlAgMBAAGjgacwgaQw
+HQYDVR0OBBYEFNtN+q97oIhvyUEC+/Sc4q0ASv4zMHUGA1UdIwRuMGyAFNtN+q97
+oIhvyUEC+/Sc4q0ASv4zoUmkRzBFMQswCQYDVQQGEwJBVTETMBEGA1UECBMKU29t
+ZS1TdGF0ZTEhMB8GA1UEChMYSW50ZXJuZXQgV2lkZ2l0cyBQdHkgTHRkggkAmDIZ
+4gvVqI8wDAYDVR0TBAUwAwEB/zANBgkqhkiG9w0BAQUFAAOBgQCZKuxfGc/RrMlz
+aai4+5s0GnhSuq0CdfnpwZR+dXsjMO6dlrD1NgQoQVhYO7UbzktwU1Hz9Mc3XE7t
+HCu8gfq+3WRUgddCQnYJUXtig2yAqmHf/WGR9yYYnfMUDKa85i0inolq1EnLvgVV
+K4iijxtW0XYe5R1Od6lWOEKZ6un9Ag==
+-----END CERTIFICATE-----
+`
+
+var _ = Describe("ConnectionConfig", func() {
+	Describe("TLSConfig", func() {
+		It("verifies the server by its host name", func() {
+			tlsConfig, err := ldap.ConnectionConfig{Host: "ldap.example.com:636"}.TLSConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tlsConfig.ServerName).To(Equal("ldap.example.com"))
+			Expect(tlsConfig.InsecureSkipVerify).To(BeFalse())
+			Expect(tlsConfig.RootCAs).To(BeNil())
+		})
+
+		It("accepts a host without a port", func() {
+			tlsConfig, err := ldap.ConnectionConfig{Host: "ldap.example.com"}.TLSConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tlsConfig.ServerName).To(Equal("ldap.example.com"))
+		})
+
+		It("trusts the configured CA cert", func() {
+			tlsConfig, err := ldap.ConnectionConfig{
+				Host:   "ldap.example.com:636",
+				CACert: caCert,
+			}.TLSConfig()
+			Expect(err).NotTo(HaveOccurred())
+
+			block, _ := pem.Decode([]byte(caCert))
+			cert, err := x509.ParseCertificate(block.Bytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tlsConfig.RootCAs.Subjects()).To(ContainElement(cert.RawSubject))
+		})
+
+		It("fails when the CA cert has no certificates", func() {
+			_, err := ldap.ConnectionConfig{CACert: "bogus"}.TLSConfig()
+			Expect(err).To(Equal(ldap.ErrInvalidCACert))
+		})
+
+		It("can skip verification", func() {
+			tlsConfig, err := ldap.ConnectionConfig{
+				Host:               "ldap.example.com:636",
+				InsecureSkipVerify: true,
+			}.TLSConfig()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tlsConfig.InsecureSkipVerify).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package ldap_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestLDAP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LDAP Suite")
+}
@@ -0,0 +1,184 @@
+// This file was generated by counterfeiter
+package ldapfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/auth/ldap"
+	goldap "gopkg.in/ldap.v2"
+)
+
+type FakeClient struct {
+	BindStub        func(username, password string) error
+	bindMutex       sync.RWMutex
+	bindArgsForCall []struct {
+		username string
+		password string
+	}
+	bindReturns struct {
+		result1 error
+	}
+	bindReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SearchStub        func(*goldap.SearchRequest) (*goldap.SearchResult, error)
+	searchMutex       sync.RWMutex
+	searchArgsForCall []struct {
+		arg1 *goldap.SearchRequest
+	}
+	searchReturns struct {
+		result1 *goldap.SearchResult
+		result2 error
+	}
+	searchReturnsOnCall map[int]struct {
+		result1 *goldap.SearchResult
+		result2 error
+	}
+	CloseStub        func()
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct{}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeClient) Bind(username string, password string) error {
+	fake.bindMutex.Lock()
+	ret, specificReturn := fake.bindReturnsOnCall[len(fake.bindArgsForCall)]
+	fake.bindArgsForCall = append(fake.bindArgsForCall, struct {
+		username string
+		password string
+	}{username, password})
+	fake.recordInvocation("Bind", []interface{}{username, password})
+	fake.bindMutex.Unlock()
+	if fake.BindStub != nil {
+		return fake.BindStub(username, password)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.bindReturns.result1
+}
+
+func (fake *FakeClient) BindCallCount() int {
+	fake.bindMutex.RLock()
+	defer fake.bindMutex.RUnlock()
+	return len(fake.bindArgsForCall)
+}
+
+func (fake *FakeClient) BindArgsForCall(i int) (string, string) {
+	fake.bindMutex.RLock()
+	defer fake.bindMutex.RUnlock()
+	return fake.bindArgsForCall[i].username, fake.bindArgsForCall[i].password
+}
+
+func (fake *FakeClient) BindReturns(result1 error) {
+	fake.BindStub = nil
+	fake.bindReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) BindReturnsOnCall(i int, result1 error) {
+	fake.BindStub = nil
+	if fake.bindReturnsOnCall == nil {
+		fake.bindReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.bindReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) Search(arg1 *goldap.SearchRequest) (*goldap.SearchResult, error) {
+	fake.searchMutex.Lock()
+	ret, specificReturn := fake.searchReturnsOnCall[len(fake.searchArgsForCall)]
+	fake.searchArgsForCall = append(fake.searchArgsForCall, struct {
+		arg1 *goldap.SearchRequest
+	}{arg1})
+	fake.recordInvocation("Search", []interface{}{arg1})
+	fake.searchMutex.Unlock()
+	if fake.SearchStub != nil {
+		return fake.SearchStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.searchReturns.result1, fake.searchReturns.result2
+}
+
+func (fake *FakeClient) SearchCallCount() int {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return len(fake.searchArgsForCall)
+}
+
+func (fake *FakeClient) SearchArgsForCall(i int) *goldap.SearchRequest {
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	return fake.searchArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) SearchReturns(result1 *goldap.SearchResult, result2 error) {
+	fake.SearchStub = nil
+	fake.searchReturns = struct {
+		result1 *goldap.SearchResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) SearchReturnsOnCall(i int, result1 *goldap.SearchResult, result2 error) {
+	fake.SearchStub = nil
+	if fake.searchReturnsOnCall == nil {
+		fake.searchReturnsOnCall = make(map[int]struct {
+			result1 *goldap.SearchResult
+			result2 error
+		})
+	}
+	fake.searchReturnsOnCall[i] = struct {
+		result1 *goldap.SearchResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Close() {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if fake.CloseStub != nil {
+		fake.CloseStub()
+	}
+}
+
+func (fake *FakeClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.bindMutex.RLock()
+	defer fake.bindMutex.RUnlock()
+	fake.searchMutex.RLock()
+	defer fake.searchMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ ldap.Client = new(FakeClient)
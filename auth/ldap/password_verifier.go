@@ -0,0 +1,117 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	goldap "gopkg.in/ldap.v2"
+)
+
+// UserFilter finds the entry of the user logging in by their uid.
+const UserFilter = "(uid=%s)"
+
+// GroupFilter finds the groups that list a user's DN as a member.
+const GroupFilter = "(|(member=%s)(uniqueMember=%s))"
+
+type PasswordVerifier struct {
+	connection      ConnectionConfig
+	bindDN          string
+	bindPassword    string
+	userSearchBase  string
+	groupSearchBase string
+	groups          []string
+
+	dial Dialer
+}
+
+func NewPasswordVerifier(config *LDAPAuthConfig, dial Dialer) PasswordVerifier {
+	return PasswordVerifier{
+		connection:      config.ConnectionConfig(),
+		bindDN:          config.BindDN,
+		bindPassword:    config.BindPassword,
+		userSearchBase:  config.UserSearchBase,
+		groupSearchBase: config.GroupSearchBase,
+		groups:          config.Groups,
+
+		dial: dial,
+	}
+}
+
+// VerifyPassword binds as the user to check their password, then checks
+// that the user is a member of one of the allowed groups. Wrong credentials
+// and unknown users are reported as unverified rather than as errors.
+func (verifier PasswordVerifier) VerifyPassword(username string, password string) (bool, error) {
+	// an empty password would make an unauthenticated bind, which most
+	// servers allow
+	if username == "" || password == "" {
+		return false, nil
+	}
+
+	client, err := verifier.dial(verifier.connection)
+	if err != nil {
+		return false, err
+	}
+
+	defer client.Close()
+
+	err = client.Bind(verifier.bindDN, verifier.bindPassword)
+	if err != nil {
+		return false, err
+	}
+
+	users, err := client.Search(goldap.NewSearchRequest(
+		verifier.userSearchBase,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(UserFilter, goldap.EscapeFilter(username)),
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return false, err
+	}
+
+	if len(users.Entries) != 1 {
+		return false, nil
+	}
+
+	userDN := users.Entries[0].DN
+
+	err = client.Bind(userDN, password)
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	// search for groups as the configured user, which may be able to see more
+	// than the user logging in
+	err = client.Bind(verifier.bindDN, verifier.bindPassword)
+	if err != nil {
+		return false, err
+	}
+
+	escapedDN := goldap.EscapeFilter(userDN)
+
+	groups, err := client.Search(goldap.NewSearchRequest(
+		verifier.groupSearchBase,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(GroupFilter, escapedDN, escapedDN),
+		[]string{"dn"},
+		nil,
+	))
+	if err != nil {
+		return false, err
+	}
+
+	for _, group := range groups.Entries {
+		for _, allowedGroup := range verifier.groups {
+			if strings.EqualFold(group.DN, allowedGroup) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
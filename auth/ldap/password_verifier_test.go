@@ -0,0 +1,206 @@
+package ldap_test
+
+import (
+	"errors"
+
+	. "github.com/concourse/atc/auth/ldap"
+	"github.com/concourse/atc/auth/ldap/ldapfakes"
+	goldap "gopkg.in/ldap.v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PasswordVerifier", func() {
+	var (
+		config     *LDAPAuthConfig
+		fakeClient *ldapfakes.FakeClient
+		dialed     ConnectionConfig
+		dialErr    error
+
+		username string
+		password string
+
+		verified  bool
+		verifyErr error
+	)
+
+	BeforeEach(func() {
+		config = &LDAPAuthConfig{
+			Host:            "ldap.example.com:389",
+			BindDN:          "cn=admin,dc=example,dc=com",
+			BindPassword:    "admin-password",
+			UserSearchBase:  "ou=people,dc=example,dc=com",
+			GroupSearchBase: "ou=groups,dc=example,dc=com",
+			Groups:          []string{"cn=ci,ou=groups,dc=example,dc=com"},
+		}
+
+		fakeClient = new(ldapfakes.FakeClient)
+		dialErr = nil
+
+		username = "some-user"
+		password = "some-password"
+
+		fakeClient.SearchStub = func(request *goldap.SearchRequest) (*goldap.SearchResult, error) {
+			switch request.BaseDN {
+			case config.UserSearchBase:
+				return &goldap.SearchResult{
+					Entries: []*goldap.Entry{{DN: "uid=some-user,ou=people,dc=example,dc=com"}},
+				}, nil
+			default:
+				return &goldap.SearchResult{
+					Entries: []*goldap.Entry{{DN: "CN=ci,OU=groups,DC=example,DC=com"}},
+				}, nil
+			}
+		}
+	})
+
+	JustBeforeEach(func() {
+		verifier := NewPasswordVerifier(config, func(connection ConnectionConfig) (Client, error) {
+			dialed = connection
+			if dialErr != nil {
+				return nil, dialErr
+			}
+
+			return fakeClient, nil
+		})
+
+		verified, verifyErr = verifier.VerifyPassword(username, password)
+	})
+
+	Context("when the user exists, the password is right, and the user is in an allowed group", func() {
+		It("returns true", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+
+		It("connects to the configured host over TLS and closes the connection", func() {
+			Expect(dialed.Host).To(Equal("ldap.example.com:389"))
+			Expect(dialed.TLSMode).To(Equal(TLSModeTLS))
+			Expect(fakeClient.CloseCallCount()).To(Equal(1))
+		})
+
+		It("binds as the configured DN, then as the user, then as the configured DN again", func() {
+			Expect(fakeClient.BindCallCount()).To(Equal(3))
+
+			dn, pass := fakeClient.BindArgsForCall(0)
+			Expect(dn).To(Equal("cn=admin,dc=example,dc=com"))
+			Expect(pass).To(Equal("admin-password"))
+
+			dn, pass = fakeClient.BindArgsForCall(1)
+			Expect(dn).To(Equal("uid=some-user,ou=people,dc=example,dc=com"))
+			Expect(pass).To(Equal("some-password"))
+
+			dn, pass = fakeClient.BindArgsForCall(2)
+			Expect(dn).To(Equal("cn=admin,dc=example,dc=com"))
+			Expect(pass).To(Equal("admin-password"))
+		})
+
+		It("searches for the user by uid and their groups by member DN", func() {
+			Expect(fakeClient.SearchCallCount()).To(Equal(2))
+
+			userSearch := fakeClient.SearchArgsForCall(0)
+			Expect(userSearch.BaseDN).To(Equal("ou=people,dc=example,dc=com"))
+			Expect(userSearch.Filter).To(Equal("(uid=some-user)"))
+
+			groupSearch := fakeClient.SearchArgsForCall(1)
+			Expect(groupSearch.BaseDN).To(Equal("ou=groups,dc=example,dc=com"))
+			Expect(groupSearch.Filter).To(Equal("(|(member=uid=some-user,ou=people,dc=example,dc=com)(uniqueMember=uid=some-user,ou=people,dc=example,dc=com))"))
+		})
+	})
+
+	Context("when the username needs escaping", func() {
+		BeforeEach(func() {
+			username = "*)(uid=*"
+		})
+
+		It("escapes it in the search filter", func() {
+			userSearch := fakeClient.SearchArgsForCall(0)
+			Expect(userSearch.Filter).To(Equal(`(uid=\2a\29\28uid=\2a)`))
+		})
+	})
+
+	Context("when the password is empty", func() {
+		BeforeEach(func() {
+			password = ""
+		})
+
+		It("returns false without connecting", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+			Expect(fakeClient.BindCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when the user cannot be found", func() {
+		BeforeEach(func() {
+			fakeClient.SearchStub = nil
+			fakeClient.SearchReturns(&goldap.SearchResult{}, nil)
+		})
+
+		It("returns false", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+
+	Context("when the password is wrong", func() {
+		BeforeEach(func() {
+			fakeClient.BindReturnsOnCall(1, goldap.NewError(goldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")))
+		})
+
+		It("returns false", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+
+	Context("when the user is not in an allowed group", func() {
+		BeforeEach(func() {
+			config.Groups = []string{"cn=admins,ou=groups,dc=example,dc=com"}
+		})
+
+		It("returns false", func() {
+			Expect(verifyErr).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+	})
+
+	Context("when connecting fails", func() {
+		BeforeEach(func() {
+			dialErr = errors.New("connection refused")
+		})
+
+		It("returns the error", func() {
+			Expect(verifyErr).To(Equal(dialErr))
+		})
+	})
+
+	Context("when binding as the configured DN fails", func() {
+		var disaster error
+
+		BeforeEach(func() {
+			disaster = errors.New("nope")
+			fakeClient.BindReturnsOnCall(0, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(verifyErr).To(Equal(disaster))
+			Expect(fakeClient.SearchCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when searching fails", func() {
+		var disaster error
+
+		BeforeEach(func() {
+			disaster = errors.New("nope")
+			fakeClient.SearchStub = nil
+			fakeClient.SearchReturns(nil, disaster)
+		})
+
+		It("returns the error", func() {
+			Expect(verifyErr).To(Equal(disaster))
+		})
+	})
+})
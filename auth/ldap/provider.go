@@ -0,0 +1,199 @@
+package ldap
+
+import (
+	"errors"
+	"net/http"
+
+	"encoding/json"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/uaa"
+	"github.com/concourse/atc/web"
+	"github.com/hashicorp/go-multierror"
+	flags "github.com/jessevdk/go-flags"
+	"github.com/tedsuo/rata"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+const ProviderName = "ldap"
+const DisplayName = "LDAP"
+
+// ErrNotOAuth is returned when the OAuth flow is attempted with LDAP, which
+// only supports logging in with a username and password.
+var ErrNotOAuth = errors.New("ldap auth does not support oauth")
+
+type LDAPAuthConfig struct {
+	Host         string `json:"host"          long:"host"          description:"LDAP server to authenticate against, as host:port."`
+	BindDN       string `json:"bind_dn"       long:"bind-dn"       description:"DN to bind as when searching for users and groups."`
+	BindPassword string `json:"bind_password" long:"bind-password" description:"Password for the bind DN."`
+
+	UserSearchBase  string   `json:"user_search_base"  long:"user-search-base"  description:"Base DN to search for users under."`
+	GroupSearchBase string   `json:"group_search_base" long:"group-search-base" description:"Base DN to search for groups under."`
+	Groups          []string `json:"groups,omitempty"  long:"group"             description:"DN of a group whose members will have access." value-name:"DN"`
+
+	TLS                string               `json:"tls,omitempty"                  long:"tls"                  default:"tls" choice:"tls" choice:"starttls" choice:"none" description:"How to secure the connection to the LDAP server."`
+	CACert             uaa.FileContentsFlag `json:"ca_cert,omitempty"              long:"ca-cert"              description:"Path to a PEM-encoded CA certificate to verify the LDAP server with."`
+	InsecureSkipVerify bool                 `json:"insecure_skip_verify,omitempty" long:"insecure-skip-verify" description:"Skip verification of the LDAP server's certificate."`
+}
+
+// ConnectionConfig returns how to connect to the LDAP server. Configs that
+// don't set a TLS mode, such as team configs saved before it existed, use TLS.
+func (auth *LDAPAuthConfig) ConnectionConfig() ConnectionConfig {
+	tlsMode := auth.TLS
+	if tlsMode == "" {
+		tlsMode = TLSModeTLS
+	}
+
+	return ConnectionConfig{
+		Host:               auth.Host,
+		TLSMode:            tlsMode,
+		CACert:             string(auth.CACert),
+		InsecureSkipVerify: auth.InsecureSkipVerify,
+	}
+}
+
+func (*LDAPAuthConfig) AuthMethod(oauthBaseURL string, teamName string) atc.AuthMethod {
+	path, err := web.Routes.CreatePathForRoute(
+		web.TeamLogIn,
+		rata.Params{"team_name": teamName},
+	)
+	if err != nil {
+		panic("failed to construct team login route: " + err.Error())
+	}
+
+	return atc.AuthMethod{
+		Type:        atc.AuthTypeBasic,
+		DisplayName: DisplayName,
+		AuthURL:     oauthBaseURL + path,
+	}
+}
+
+func (auth *LDAPAuthConfig) IsConfigured() bool {
+	return auth.Host != "" ||
+		auth.BindDN != "" ||
+		auth.BindPassword != "" ||
+		auth.UserSearchBase != "" ||
+		auth.GroupSearchBase != "" ||
+		len(auth.Groups) > 0
+}
+
+func (auth *LDAPAuthConfig) Validate() error {
+	var errs *multierror.Error
+	if auth.Host == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --ldap-auth-host to use LDAP auth."),
+		)
+	}
+	if auth.BindDN == "" || auth.BindPassword == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --ldap-auth-bind-dn and --ldap-auth-bind-password to use LDAP auth."),
+		)
+	}
+	if auth.UserSearchBase == "" || auth.GroupSearchBase == "" {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --ldap-auth-user-search-base and --ldap-auth-group-search-base to use LDAP auth."),
+		)
+	}
+	if len(auth.Groups) == 0 {
+		errs = multierror.Append(
+			errs,
+			errors.New("must specify --ldap-auth-group to use LDAP auth."),
+		)
+	}
+	switch auth.TLS {
+	case "", TLSModeTLS, TLSModeStartTLS, TLSModeNone:
+	default:
+		errs = multierror.Append(
+			errs,
+			errors.New("--ldap-auth-tls must be one of tls, starttls, or none."),
+		)
+	}
+	if auth.CACert != "" {
+		_, err := auth.ConnectionConfig().TLSConfig()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// LDAPProvider checks usernames and passwords against an LDAP server. It
+// satisfies provider.Provider so that it can be looked up like any other
+// provider, but every step of the OAuth flow fails with ErrNotOAuth.
+type LDAPProvider struct {
+	provider.PasswordVerifier
+}
+
+func init() {
+	provider.Register(ProviderName, LDAPTeamProvider{})
+}
+
+type LDAPTeamProvider struct {
+	Dialer Dialer
+}
+
+func (LDAPTeamProvider) AddAuthGroup(group *flags.Group) provider.AuthConfig {
+	flags := &LDAPAuthConfig{}
+
+	ldGroup, err := group.AddGroup("LDAP Authentication", "", flags)
+	if err != nil {
+		panic(err)
+	}
+
+	ldGroup.Namespace = "ldap-auth"
+
+	return flags
+}
+
+func (LDAPTeamProvider) UnmarshalConfig(config *json.RawMessage) (provider.AuthConfig, error) {
+	flags := &LDAPAuthConfig{}
+	if config != nil {
+		err := json.Unmarshal(*config, &flags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flags, nil
+}
+
+func (teamProvider LDAPTeamProvider) ProviderConstructor(
+	config provider.AuthConfig,
+	redirectURL string,
+) (provider.Provider, bool) {
+	ldapAuth := config.(*LDAPAuthConfig)
+
+	dialer := teamProvider.Dialer
+	if dialer == nil {
+		dialer = Dial
+	}
+
+	return LDAPProvider{
+		PasswordVerifier: NewPasswordVerifier(ldapAuth, dialer),
+	}, true
+}
+
+func (LDAPProvider) PreTokenClient() (*http.Client, error) {
+	return nil, ErrNotOAuth
+}
+
+func (LDAPProvider) AuthCodeURL(string, ...oauth2.AuthCodeOption) string {
+	return ""
+}
+
+func (LDAPProvider) Exchange(context.Context, string) (*oauth2.Token, error) {
+	return nil, ErrNotOAuth
+}
+
+func (LDAPProvider) Client(context.Context, *oauth2.Token) *http.Client {
+	return nil
+}
+
+func (LDAPProvider) Verify(lager.Logger, *http.Client) (bool, error) {
+	return false, ErrNotOAuth
+}
@@ -0,0 +1,110 @@
+package ldap_test
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/auth/ldap"
+	"github.com/concourse/atc/auth/provider"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LDAP Provider", func() {
+	Describe("AuthMethod", func() {
+		It("points at the team's login page", func() {
+			authConfig := &ldap.LDAPAuthConfig{}
+			authMethod := authConfig.AuthMethod("http://bum-bum-bum.com", "dudududum")
+
+			Expect(authMethod).To(Equal(atc.AuthMethod{
+				Type:        atc.AuthTypeBasic,
+				DisplayName: "LDAP",
+				AuthURL:     "http://bum-bum-bum.com/teams/dudududum/login",
+			}))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("requires every setting", func() {
+			authConfig := &ldap.LDAPAuthConfig{}
+
+			err := authConfig.Validate()
+			Expect(err).To(MatchError(ContainSubstring("--ldap-auth-host")))
+			Expect(err).To(MatchError(ContainSubstring("--ldap-auth-bind-dn")))
+			Expect(err).To(MatchError(ContainSubstring("--ldap-auth-user-search-base")))
+			Expect(err).To(MatchError(ContainSubstring("--ldap-auth-group")))
+		})
+
+		It("is valid when everything is set", func() {
+			authConfig := &ldap.LDAPAuthConfig{
+				Host:            "ldap.example.com:389",
+				BindDN:          "cn=admin,dc=example,dc=com",
+				BindPassword:    "admin-password",
+				UserSearchBase:  "ou=people,dc=example,dc=com",
+				GroupSearchBase: "ou=groups,dc=example,dc=com",
+				Groups:          []string{"cn=ci,ou=groups,dc=example,dc=com"},
+			}
+
+			Expect(authConfig.Validate()).To(Succeed())
+		})
+
+		It("rejects unknown TLS modes", func() {
+			authConfig := &ldap.LDAPAuthConfig{TLS: "ssl"}
+			Expect(authConfig.Validate()).To(MatchError(ContainSubstring("--ldap-auth-tls")))
+		})
+
+		It("rejects a CA cert with no certificates in it", func() {
+			authConfig := &ldap.LDAPAuthConfig{CACert: "not-a-cert"}
+			Expect(authConfig.Validate()).To(MatchError(ContainSubstring(ldap.ErrInvalidCACert.Error())))
+		})
+	})
+
+	Describe("ConnectionConfig", func() {
+		It("defaults to TLS", func() {
+			authConfig := &ldap.LDAPAuthConfig{Host: "ldap.example.com:636"}
+			Expect(authConfig.ConnectionConfig()).To(Equal(ldap.ConnectionConfig{
+				Host:    "ldap.example.com:636",
+				TLSMode: ldap.TLSModeTLS,
+			}))
+		})
+
+		It("carries the configured TLS settings", func() {
+			authConfig := &ldap.LDAPAuthConfig{
+				Host:               "ldap.example.com:389",
+				TLS:                ldap.TLSModeStartTLS,
+				CACert:             "some-ca-cert",
+				InsecureSkipVerify: true,
+			}
+
+			Expect(authConfig.ConnectionConfig()).To(Equal(ldap.ConnectionConfig{
+				Host:               "ldap.example.com:389",
+				TLSMode:            ldap.TLSModeStartTLS,
+				CACert:             "some-ca-cert",
+				InsecureSkipVerify: true,
+			}))
+		})
+	})
+
+	Describe("ProviderConstructor", func() {
+		var ldapProvider provider.Provider
+
+		BeforeEach(func() {
+			var found bool
+			ldapProvider, found = ldap.LDAPTeamProvider{}.ProviderConstructor(&ldap.LDAPAuthConfig{}, "some-redirect-url")
+			Expect(found).To(BeTrue())
+		})
+
+		It("verifies passwords", func() {
+			Expect(ldapProvider).To(BeAssignableToTypeOf(ldap.LDAPProvider{}))
+
+			_, ok := ldapProvider.(provider.PasswordVerifier)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("does not support the oauth flow", func() {
+			_, err := ldapProvider.PreTokenClient()
+			Expect(err).To(Equal(ldap.ErrNotOAuth))
+
+			_, err = ldapProvider.Exchange(nil, "some-code")
+			Expect(err).To(Equal(ldap.ErrNotOAuth))
+		})
+	})
+})
@@ -4,19 +4,33 @@ import (
 	"net/http"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/auth/provider"
 )
 
 type LogOutHandler struct {
-	logger lager.Logger
+	logger          lager.Logger
+	membershipCache *provider.MembershipCache
 }
 
-func NewLogOutHandler(logger lager.Logger) http.Handler {
-	return &LogOutHandler{logger: logger}
+func NewLogOutHandler(logger lager.Logger, membershipCache *provider.MembershipCache) http.Handler {
+	return &LogOutHandler{
+		logger:          logger,
+		membershipCache: membershipCache,
+	}
 }
 
 func (handler *LogOutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	handler.logger.Session("logout")
 
+	if handler.membershipCache != nil {
+		// the auth cookie doesn't say who the user is, so only credentials sent
+		// with the request can be evicted; anything else expires with its TTL
+		username, password, err := extractUsernameAndPassword(r.Header.Get("Authorization"))
+		if err == nil {
+			handler.membershipCache.InvalidateCredential(username, password)
+		}
+	}
+
 	http.SetCookie(w, &http.Cookie{
 		Name:   AuthCookieName,
 		Path:   "/",
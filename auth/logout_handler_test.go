@@ -7,12 +7,14 @@ import (
 	"net/http/httptest"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/auth/provider"
 	"github.com/concourse/atc/dbng/dbngfakes"
 )
 
@@ -20,6 +22,7 @@ var _ = Describe("LogOutHandler", func() {
 	Describe("GET /auth/logout", func() {
 		var (
 			fakeProviderFactory *authfakes.FakeProviderFactory
+			membershipCache     *provider.MembershipCache
 			signingKey          *rsa.PrivateKey
 			server              *httptest.Server
 			client              *http.Client
@@ -36,6 +39,8 @@ var _ = Describe("LogOutHandler", func() {
 			Expect(err).ToNot(HaveOccurred())
 			expire = 24 * time.Hour
 
+			membershipCache = provider.NewMembershipCache(clock.NewClock(), time.Minute)
+
 			handler, err := auth.NewOAuthHandler(
 				lagertest.NewTestLogger("test"),
 				fakeProviderFactory,
@@ -43,6 +48,7 @@ var _ = Describe("LogOutHandler", func() {
 				signingKey,
 				expire,
 				false,
+				membershipCache,
 			)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -72,5 +78,55 @@ var _ = Describe("LogOutHandler", func() {
 			Expect(deletedCookie.Name).To(Equal(auth.AuthCookieName))
 			Expect(deletedCookie.MaxAge).To(Equal(-1))
 		})
+
+		Context("when memberships are cached", func() {
+			var (
+				someUserKey  provider.MembershipKey
+				otherUserKey provider.MembershipKey
+			)
+
+			BeforeEach(func() {
+				someUserKey = provider.NewMembershipKey("ldap", "some-team", "some-user", "some-password")
+				otherUserKey = provider.NewMembershipKey("ldap", "some-team", "other-user", "other-password")
+
+				membershipCache.Store(someUserKey, true)
+				membershipCache.Store(otherUserKey, true)
+			})
+
+			Context("when the request has the user's credentials", func() {
+				BeforeEach(func() {
+					request.SetBasicAuth("some-user", "some-password")
+				})
+
+				It("forgets only that user's memberships", func() {
+					_, found := membershipCache.Lookup(someUserKey)
+					Expect(found).To(BeFalse())
+
+					_, found = membershipCache.Lookup(otherUserKey)
+					Expect(found).To(BeTrue())
+				})
+			})
+
+			Context("when the request has the user's name with another password", func() {
+				BeforeEach(func() {
+					request.SetBasicAuth("some-user", "wrong-password")
+				})
+
+				It("forgets nothing", func() {
+					_, found := membershipCache.Lookup(someUserKey)
+					Expect(found).To(BeTrue())
+				})
+			})
+
+			Context("when the request does not say who the user is", func() {
+				It("forgets nothing", func() {
+					_, found := membershipCache.Lookup(someUserKey)
+					Expect(found).To(BeTrue())
+
+					_, found = membershipCache.Lookup(otherUserKey)
+					Expect(found).To(BeTrue())
+				})
+			})
+		})
 	})
 })
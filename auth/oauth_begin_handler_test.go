@@ -64,6 +64,7 @@ var _ = Describe("OAuthBeginHandler", func() {
 			signingKey,
 			expire,
 			false,
+			nil,
 		)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -104,6 +104,7 @@ var _ = Describe("OAuthCallbackHandler", func() {
 			signingKey,
 			expire,
 			false,
+			nil,
 		)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -7,6 +7,9 @@ import (
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/genericoauth"
 	"github.com/concourse/atc/auth/github"
+	"github.com/concourse/atc/auth/gitlab"
+	"github.com/concourse/atc/auth/ldap"
+	"github.com/concourse/atc/auth/provider"
 	"github.com/concourse/atc/auth/routes"
 	"github.com/concourse/atc/auth/uaa"
 	"github.com/concourse/atc/dbng/dbngfakes"
@@ -67,6 +70,39 @@ var _ = Describe("OAuthFactory", func() {
 			})
 		})
 
+		Context("when asking for gitlab provider", func() {
+			Context("when gitlab provider is setup", func() {
+				It("returns back GitLab's auth provider", func() {
+					data := []byte(`
+					{
+						"ClientID": "user1",
+						"ClientSecret": "password1",
+						"Groups": ["some-group"]
+					}`)
+					authConfig = map[string]*json.RawMessage{
+						"gitlab": (*json.RawMessage)(&data),
+					}
+
+					fakeTeam.NameReturns("some-team")
+					fakeTeam.AuthReturns(authConfig)
+
+					provider, found, err := oauthFactory.GetProvider(fakeTeam, gitlab.ProviderName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(provider).NotTo(BeNil())
+				})
+			})
+
+			Context("when gitlab provider is not setup", func() {
+				It("returns false", func() {
+					fakeTeam.NameReturns("some-team")
+					_, found, err := oauthFactory.GetProvider(fakeTeam, gitlab.ProviderName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
 		Context("when asking for uaa provider", func() {
 			Context("when UAA provider is setup", func() {
 				It("returns back UAA's auth provider", func() {
@@ -99,6 +135,45 @@ var _ = Describe("OAuthFactory", func() {
 			})
 		})
 
+		Context("when asking for ldap provider", func() {
+			Context("when LDAP provider is setup", func() {
+				It("returns back LDAP's auth provider", func() {
+					data := []byte(`
+					{
+						"Host": "ldap.example.com:389",
+						"BindDN": "cn=admin,dc=example,dc=com",
+						"BindPassword": "password1",
+						"UserSearchBase": "ou=people,dc=example,dc=com",
+						"GroupSearchBase": "ou=groups,dc=example,dc=com",
+						"Groups": ["cn=ci,ou=groups,dc=example,dc=com"]
+					}`)
+					authConfig = map[string]*json.RawMessage{
+						"ldap": (*json.RawMessage)(&data),
+					}
+
+					fakeTeam.NameReturns("some-team")
+					fakeTeam.AuthReturns(authConfig)
+
+					ldapProvider, found, err := oauthFactory.GetProvider(fakeTeam, ldap.ProviderName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(ldapProvider).NotTo(BeNil())
+
+					_, verifiesPasswords := ldapProvider.(provider.PasswordVerifier)
+					Expect(verifiesPasswords).To(BeTrue())
+				})
+			})
+
+			Context("when ldap provider is not setup", func() {
+				It("returns false", func() {
+					fakeTeam.NameReturns("some-team")
+					_, found, err := oauthFactory.GetProvider(fakeTeam, ldap.ProviderName)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
 		Context("when asking for generic oauth", func() {
 			Context("when Generic OAuth provider is setup", func() {
 				It("returns back GOA's auth provider", func() {
@@ -28,6 +28,7 @@ func NewOAuthHandler(
 	signingKey *rsa.PrivateKey,
 	expire time.Duration,
 	isTLSEnabled bool,
+	membershipCache *provider.MembershipCache,
 ) (http.Handler, error) {
 	return rata.NewRouter(
 		routes.OAuthRoutes,
@@ -50,6 +51,7 @@ func NewOAuthHandler(
 			),
 			routes.LogOut: NewLogOutHandler(
 				logger.Session("logout"),
+				membershipCache,
 			),
 		},
 	)
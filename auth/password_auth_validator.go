@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/dbng"
+)
+
+type passwordAuthValidator struct {
+	team            dbng.Team
+	membershipCache *provider.MembershipCache
+}
+
+// NewPasswordAuthValidator checks basic auth credentials against each of the
+// team's providers that verify passwords themselves, such as LDAP. Results
+// are reused from the membership cache, if one is given.
+func NewPasswordAuthValidator(team dbng.Team, membershipCache *provider.MembershipCache) Validator {
+	return passwordAuthValidator{
+		team:            team,
+		membershipCache: membershipCache,
+	}
+}
+
+func (v passwordAuthValidator) IsAuthenticated(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	username, password, err := extractUsernameAndPassword(auth)
+	if err != nil {
+		return false
+	}
+
+	for providerName, config := range v.team.Auth() {
+		teamProvider, found := provider.NewProvider(config, providerName, "")
+		if !found {
+			continue
+		}
+
+		verifier, ok := teamProvider.(provider.PasswordVerifier)
+		if !ok {
+			continue
+		}
+
+		if v.membershipCache != nil {
+			verifier = provider.NewCachingPasswordVerifier(v.membershipCache, providerName, v.team.Name(), verifier)
+		}
+
+		verified, err := verifier.VerifyPassword(username, password)
+		if err == nil && verified {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// DefaultMembershipCacheTTL is how long a successful membership check is
+// reused for if no other TTL is configured.
+const DefaultMembershipCacheTTL = time.Minute
+
+// MaxDeniedMembershipCacheTTL caps how long a denied membership check is
+// reused for, so that a user who has just been granted access is not locked
+// out for the full TTL.
+const MaxDeniedMembershipCacheTTL = 5 * time.Second
+
+// MembershipKey identifies a membership check. Credential distinguishes
+// checks made with different credentials for the same user, so that a
+// cached result is never reused for a wrong password.
+type MembershipKey struct {
+	Provider   string
+	Team       string
+	User       string
+	Credential [sha256.Size]byte
+}
+
+// NewMembershipKey returns the key for checking user's membership with the
+// given credential. Only a digest of the credential is kept.
+func NewMembershipKey(providerName string, teamName string, user string, credential string) MembershipKey {
+	return MembershipKey{
+		Provider:   providerName,
+		Team:       teamName,
+		User:       user,
+		Credential: sha256.Sum256([]byte(user + ":" + credential)),
+	}
+}
+
+// MembershipCache remembers the results of recent membership checks so that
+// repeated checks for the same user don't each go to the provider.
+type MembershipCache struct {
+	clock     clock.Clock
+	ttl       time.Duration
+	deniedTTL time.Duration
+
+	entriesL  sync.Mutex
+	entries   map[MembershipKey]membershipEntry
+	lastSweep time.Time
+}
+
+type membershipEntry struct {
+	verified bool
+	expires  time.Time
+}
+
+func NewMembershipCache(clock clock.Clock, ttl time.Duration) *MembershipCache {
+	deniedTTL := ttl
+	if deniedTTL > MaxDeniedMembershipCacheTTL {
+		deniedTTL = MaxDeniedMembershipCacheTTL
+	}
+
+	return &MembershipCache{
+		clock:     clock,
+		ttl:       ttl,
+		deniedTTL: deniedTTL,
+
+		entries:   map[MembershipKey]membershipEntry{},
+		lastSweep: clock.Now(),
+	}
+}
+
+// Lookup returns the cached result for the key, if there is one that has
+// not yet expired.
+func (cache *MembershipCache) Lookup(key MembershipKey) (bool, bool) {
+	cache.entriesL.Lock()
+	defer cache.entriesL.Unlock()
+
+	entry, found := cache.entries[key]
+	if !found {
+		return false, false
+	}
+
+	if !cache.clock.Now().Before(entry.expires) {
+		delete(cache.entries, key)
+		return false, false
+	}
+
+	return entry.verified, true
+}
+
+// Store caches the result for the key. Entries that are never looked up
+// again would otherwise stay forever, so expired entries are swept at most
+// once per TTL.
+func (cache *MembershipCache) Store(key MembershipKey, verified bool) {
+	ttl := cache.ttl
+	if !verified {
+		ttl = cache.deniedTTL
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	cache.entriesL.Lock()
+	defer cache.entriesL.Unlock()
+
+	now := cache.clock.Now()
+
+	if now.Sub(cache.lastSweep) >= cache.ttl {
+		for k, entry := range cache.entries {
+			if !now.Before(entry.expires) {
+				delete(cache.entries, k)
+			}
+		}
+
+		cache.lastSweep = now
+	}
+
+	cache.entries[key] = membershipEntry{
+		verified: verified,
+		expires:  now.Add(ttl),
+	}
+}
+
+// InvalidateCredential forgets every cached result for the user checked
+// with the given credential, across all providers and teams. Results for the
+// user's other credentials are kept, so that nobody can evict a user
+// without knowing their password.
+func (cache *MembershipCache) InvalidateCredential(user string, credential string) {
+	digest := NewMembershipKey("", "", user, credential).Credential
+
+	cache.entriesL.Lock()
+	defer cache.entriesL.Unlock()
+
+	for key := range cache.entries {
+		if key.User == user && key.Credential == digest {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// Len returns how many results are cached, including expired ones that have
+// not been swept yet.
+func (cache *MembershipCache) Len() int {
+	cache.entriesL.Lock()
+	defer cache.entriesL.Unlock()
+
+	return len(cache.entries)
+}
+
+type cachingPasswordVerifier struct {
+	cache        *MembershipCache
+	providerName string
+	teamName     string
+	verifier     PasswordVerifier
+}
+
+// NewCachingPasswordVerifier reuses the verifier's results from the cache
+// for repeated checks of the same user and password on the same team.
+// Errors are never cached.
+func NewCachingPasswordVerifier(
+	cache *MembershipCache,
+	providerName string,
+	teamName string,
+	verifier PasswordVerifier,
+) PasswordVerifier {
+	return cachingPasswordVerifier{
+		cache:        cache,
+		providerName: providerName,
+		teamName:     teamName,
+		verifier:     verifier,
+	}
+}
+
+func (v cachingPasswordVerifier) VerifyPassword(username string, password string) (bool, error) {
+	key := NewMembershipKey(v.providerName, v.teamName, username, password)
+
+	verified, found := v.cache.Lookup(key)
+	if found {
+		return verified, nil
+	}
+
+	verified, err := v.verifier.VerifyPassword(username, password)
+	if err != nil {
+		return false, err
+	}
+
+	v.cache.Store(key, verified)
+
+	return verified, nil
+}
@@ -0,0 +1,172 @@
+package provider_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/concourse/atc/auth/provider"
+	"github.com/concourse/atc/auth/provider/providerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MembershipCache", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		cache     *provider.MembershipCache
+
+		fakeVerifier *providerfakes.FakePasswordVerifier
+		verifier     provider.PasswordVerifier
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 456))
+		cache = provider.NewMembershipCache(fakeClock, time.Minute)
+
+		fakeVerifier = new(providerfakes.FakePasswordVerifier)
+		verifier = provider.NewCachingPasswordVerifier(cache, "some-provider", "some-team", fakeVerifier)
+	})
+
+	Context("when the user is verified", func() {
+		BeforeEach(func() {
+			fakeVerifier.VerifyPasswordReturns(true, nil)
+
+			verified, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		})
+
+		It("does not ask the provider again within the TTL", func() {
+			fakeClock.Increment(59 * time.Second)
+
+			verified, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(1))
+		})
+
+		It("asks the provider again once the TTL has passed", func() {
+			fakeClock.Increment(time.Minute)
+
+			_, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+
+		It("asks the provider when the password is different", func() {
+			fakeVerifier.VerifyPasswordReturns(false, nil)
+
+			verified, err := verifier.VerifyPassword("some-user", "wrong-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+
+		It("asks the provider for the same user on another team", func() {
+			otherTeamVerifier := provider.NewCachingPasswordVerifier(cache, "some-provider", "other-team", fakeVerifier)
+
+			_, err := otherTeamVerifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+
+		It("asks the provider again once the credential is invalidated", func() {
+			cache.InvalidateCredential("some-user", "some-password")
+
+			_, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+
+		It("keeps the result when another credential is invalidated", func() {
+			cache.InvalidateCredential("some-user", "wrong-password")
+
+			_, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(1))
+		})
+
+		It("sweeps expired results that are never looked up again", func() {
+			Expect(cache.Len()).To(Equal(1))
+
+			fakeClock.Increment(time.Minute)
+
+			_, err := verifier.VerifyPassword("other-user", "other-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cache.Len()).To(Equal(1))
+		})
+	})
+
+	Context("when the user is denied", func() {
+		BeforeEach(func() {
+			fakeVerifier.VerifyPasswordReturns(false, nil)
+
+			verified, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		})
+
+		It("reuses the denial only briefly", func() {
+			fakeClock.Increment(provider.MaxDeniedMembershipCacheTTL - time.Second)
+
+			verified, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified).To(BeFalse())
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(1))
+
+			fakeVerifier.VerifyPasswordReturns(true, nil)
+			fakeClock.Increment(time.Second)
+
+			verified, err = verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(verified).To(BeTrue())
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("when the provider fails", func() {
+		var disaster error
+
+		BeforeEach(func() {
+			disaster = errors.New("nope")
+			fakeVerifier.VerifyPasswordReturns(false, disaster)
+		})
+
+		It("returns the error and does not cache it", func() {
+			_, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).To(Equal(disaster))
+
+			_, err = verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).To(Equal(disaster))
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("when the TTL is zero", func() {
+		BeforeEach(func() {
+			cache = provider.NewMembershipCache(fakeClock, 0)
+			verifier = provider.NewCachingPasswordVerifier(cache, "some-provider", "some-team", fakeVerifier)
+			fakeVerifier.VerifyPasswordReturns(true, nil)
+		})
+
+		It("does not cache anything", func() {
+			_, err := verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = verifier.VerifyPassword("some-user", "some-password")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVerifier.VerifyPasswordCallCount()).To(Equal(2))
+		})
+	})
+})
@@ -35,6 +35,15 @@ type Verifier interface {
 	Verify(lager.Logger, *http.Client) (bool, error)
 }
 
+//go:generate counterfeiter . PasswordVerifier
+
+// PasswordVerifier is implemented by providers that check a username and
+// password directly, rather than by redirecting to an OAuth server. Such
+// providers are used for basic auth requests instead of the OAuth flow.
+type PasswordVerifier interface {
+	VerifyPassword(username string, password string) (bool, error)
+}
+
 //go:generate counterfeiter . AuthConfig
 
 type AuthConfig interface {
@@ -0,0 +1,101 @@
+// This file was generated by counterfeiter
+package providerfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/auth/provider"
+)
+
+type FakePasswordVerifier struct {
+	VerifyPasswordStub        func(string, string) (bool, error)
+	verifyPasswordMutex       sync.RWMutex
+	verifyPasswordArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	verifyPasswordReturns struct {
+		result1 bool
+		result2 error
+	}
+	verifyPasswordReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePasswordVerifier) VerifyPassword(arg1 string, arg2 string) (bool, error) {
+	fake.verifyPasswordMutex.Lock()
+	ret, specificReturn := fake.verifyPasswordReturnsOnCall[len(fake.verifyPasswordArgsForCall)]
+	fake.verifyPasswordArgsForCall = append(fake.verifyPasswordArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("VerifyPassword", []interface{}{arg1, arg2})
+	fake.verifyPasswordMutex.Unlock()
+	if fake.VerifyPasswordStub != nil {
+		return fake.VerifyPasswordStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.verifyPasswordReturns.result1, fake.verifyPasswordReturns.result2
+}
+
+func (fake *FakePasswordVerifier) VerifyPasswordCallCount() int {
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	return len(fake.verifyPasswordArgsForCall)
+}
+
+func (fake *FakePasswordVerifier) VerifyPasswordArgsForCall(i int) (string, string) {
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	return fake.verifyPasswordArgsForCall[i].arg1, fake.verifyPasswordArgsForCall[i].arg2
+}
+
+func (fake *FakePasswordVerifier) VerifyPasswordReturns(result1 bool, result2 error) {
+	fake.VerifyPasswordStub = nil
+	fake.verifyPasswordReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePasswordVerifier) VerifyPasswordReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.VerifyPasswordStub = nil
+	if fake.verifyPasswordReturnsOnCall == nil {
+		fake.verifyPasswordReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.verifyPasswordReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePasswordVerifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.verifyPasswordMutex.RLock()
+	defer fake.verifyPasswordMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakePasswordVerifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ provider.PasswordVerifier = new(FakePasswordVerifier)
@@ -8,17 +8,20 @@ import (
 )
 
 type teamAuthValidator struct {
-	teamFactory  dbng.TeamFactory
-	jwtValidator Validator
+	teamFactory     dbng.TeamFactory
+	jwtValidator    Validator
+	membershipCache *provider.MembershipCache
 }
 
 func NewTeamAuthValidator(
 	teamFactory dbng.TeamFactory,
 	jwtValidator Validator,
+	membershipCache *provider.MembershipCache,
 ) Validator {
 	return &teamAuthValidator{
-		teamFactory:  teamFactory,
-		jwtValidator: jwtValidator,
+		teamFactory:     teamFactory,
+		jwtValidator:    jwtValidator,
+		membershipCache: membershipCache,
 	}
 }
 
@@ -37,6 +40,10 @@ func (v teamAuthValidator) IsAuthenticated(r *http.Request) bool {
 		return true
 	}
 
+	if NewPasswordAuthValidator(team, v.membershipCache).IsAuthenticated(r) {
+		return true
+	}
+
 	return v.jwtValidator.IsAuthenticated(r)
 
 }
@@ -48,7 +48,7 @@ var _ = Describe("TeamAuthValidator", func() {
 		fakeTeam = new(dbngfakes.FakeTeam)
 		fakeTeam.NameReturns(atc.DefaultTeamName)
 
-		validator = auth.NewTeamAuthValidator(fakeTeamFactory, jwtValidator)
+		validator = auth.NewTeamAuthValidator(fakeTeamFactory, jwtValidator, nil)
 
 		request, err = http.NewRequest("GET", "http://example.com", nil)
 		Expect(err).ToNot(HaveOccurred())
@@ -193,6 +193,69 @@ var _ = Describe("TeamAuthValidator", func() {
 				})
 			})
 		})
+
+		Context("when team has a password provider configured", func() {
+			var fakePasswordVerifier *providerfakes.FakePasswordVerifier
+
+			BeforeEach(func() {
+				fakePasswordVerifier = new(providerfakes.FakePasswordVerifier)
+
+				provider.Register("fake-password-provider", fakeTeamProvider)
+				fakeTeamProvider.ProviderConstructorReturns(passwordProvider{
+					FakeProvider:         new(providerfakes.FakeProvider),
+					FakePasswordVerifier: fakePasswordVerifier,
+				}, true)
+
+				data := []byte(`{"Host": "ldap.example.com:389"}`)
+				authProvider = map[string]*json.RawMessage{
+					"fake-password-provider": (*json.RawMessage)(&data),
+				}
+				fakeTeam.AuthReturns(authProvider)
+				fakeTeam.BasicAuthReturns(&atc.BasicAuth{})
+
+				request.Header.Set("Authorization", "Basic "+b64(username+":"+password))
+			})
+
+			It("checks the credentials with the provider", func() {
+				Expect(fakePasswordVerifier.VerifyPasswordCallCount()).To(Equal(1))
+
+				actualUsername, actualPassword := fakePasswordVerifier.VerifyPasswordArgsForCall(0)
+				Expect(actualUsername).To(Equal(username))
+				Expect(actualPassword).To(Equal(password))
+			})
+
+			Context("when the provider verifies the credentials", func() {
+				BeforeEach(func() {
+					fakePasswordVerifier.VerifyPasswordReturns(true, nil)
+				})
+
+				It("returns true without checking for a token", func() {
+					Expect(isAuthenticated).To(BeTrue())
+					Expect(jwtValidator.IsAuthenticatedCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when the provider rejects the credentials", func() {
+				BeforeEach(func() {
+					fakePasswordVerifier.VerifyPasswordReturns(false, nil)
+				})
+
+				It("falls back to the jwtValidator", func() {
+					Expect(isAuthenticated).To(BeFalse())
+					Expect(jwtValidator.IsAuthenticatedCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the provider fails to check the credentials", func() {
+				BeforeEach(func() {
+					fakePasswordVerifier.VerifyPasswordReturns(true, errors.New("nope"))
+				})
+
+				It("returns false", func() {
+					Expect(isAuthenticated).To(BeFalse())
+				})
+			})
+		})
 	})
 
 	Context("when the team cannot be found", func() {
@@ -215,3 +278,8 @@ var _ = Describe("TeamAuthValidator", func() {
 		})
 	})
 })
+
+type passwordProvider struct {
+	*providerfakes.FakeProvider
+	*providerfakes.FakePasswordVerifier
+}
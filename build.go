@@ -23,6 +23,8 @@ type Build struct {
 	StartTime    int64  `json:"start_time,omitempty"`
 	EndTime      int64  `json:"end_time,omitempty"`
 	ReapTime     int64  `json:"reap_time,omitempty"`
+
+	TriggerReason string `json:"trigger_reason,omitempty"`
 }
 
 func (b Build) IsRunning() bool {
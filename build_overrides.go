@@ -0,0 +1,126 @@
+package atc
+
+import "fmt"
+
+// BuildOverrides are provided when manually triggering a job's build to
+// change how that build runs without changing the pipeline's config.
+type BuildOverrides struct {
+	// pins the version of a get step, keyed by the step's name
+	Versions map[string]Version `json:"versions,omitempty"`
+
+	// merged into the params of a get, put, or task step, keyed by the step's
+	// name
+	Params map[string]Params `json:"params,omitempty"`
+}
+
+type UnknownOverrideError struct {
+	Kind string
+	Name string
+}
+
+func (err UnknownOverrideError) Error() string {
+	return fmt.Sprintf("cannot override %s of unknown step '%s'", err.Kind, err.Name)
+}
+
+func (overrides BuildOverrides) IsEmpty() bool {
+	return len(overrides.Versions) == 0 && len(overrides.Params) == 0
+}
+
+// Apply returns a copy of the job with the overrides applied, leaving the
+// given job untouched. Overrides naming steps that are not in the job result
+// in an UnknownOverrideError.
+func (overrides BuildOverrides) Apply(job JobConfig) (JobConfig, error) {
+	gets := map[string]bool{}
+	steps := map[string]bool{}
+	for _, plan := range job.Plans() {
+		if plan.Get != "" {
+			gets[plan.Name()] = true
+		}
+
+		if plan.Get != "" || plan.Put != "" || plan.Task != "" {
+			steps[plan.Name()] = true
+		}
+	}
+
+	for name := range overrides.Versions {
+		if !gets[name] {
+			return JobConfig{}, UnknownOverrideError{Kind: "version", Name: name}
+		}
+	}
+
+	for name := range overrides.Params {
+		if !steps[name] {
+			return JobConfig{}, UnknownOverrideError{Kind: "params", Name: name}
+		}
+	}
+
+	job.Plan = overrides.applyToSequence(job.Plan)
+	job.Success = overrides.applyToPlanPointer(job.Success)
+	job.Failure = overrides.applyToPlanPointer(job.Failure)
+	job.Ensure = overrides.applyToPlanPointer(job.Ensure)
+
+	return job, nil
+}
+
+func (overrides BuildOverrides) applyToSequence(seq PlanSequence) PlanSequence {
+	if seq == nil {
+		return nil
+	}
+
+	applied := make(PlanSequence, len(seq))
+	for i, plan := range seq {
+		applied[i] = overrides.applyToPlan(plan)
+	}
+
+	return applied
+}
+
+func (overrides BuildOverrides) applyToSequencePointer(seq *PlanSequence) *PlanSequence {
+	if seq == nil {
+		return nil
+	}
+
+	applied := overrides.applyToSequence(*seq)
+	return &applied
+}
+
+func (overrides BuildOverrides) applyToPlanPointer(plan *PlanConfig) *PlanConfig {
+	if plan == nil {
+		return nil
+	}
+
+	applied := overrides.applyToPlan(*plan)
+	return &applied
+}
+
+func (overrides BuildOverrides) applyToPlan(plan PlanConfig) PlanConfig {
+	plan.Do = overrides.applyToSequencePointer(plan.Do)
+	plan.Aggregate = overrides.applyToSequencePointer(plan.Aggregate)
+	plan.Success = overrides.applyToPlanPointer(plan.Success)
+	plan.Failure = overrides.applyToPlanPointer(plan.Failure)
+	plan.Ensure = overrides.applyToPlanPointer(plan.Ensure)
+	plan.Try = overrides.applyToPlanPointer(plan.Try)
+
+	if plan.Get == "" && plan.Put == "" && plan.Task == "" {
+		return plan
+	}
+
+	if version, found := overrides.Versions[plan.Name()]; found && plan.Get != "" {
+		plan.Version = &VersionConfig{Pinned: version}
+	}
+
+	if params, found := overrides.Params[plan.Name()]; found {
+		merged := Params{}
+		for k, v := range plan.Params {
+			merged[k] = v
+		}
+
+		for k, v := range params {
+			merged[k] = v
+		}
+
+		plan.Params = merged
+	}
+
+	return plan
+}
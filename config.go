@@ -24,6 +24,8 @@ type Config struct {
 	Resources     ResourceConfigs `yaml:"resources" json:"resources" mapstructure:"resources"`
 	ResourceTypes ResourceTypes   `yaml:"resource_types" json:"resource_types" mapstructure:"resource_types"`
 	Jobs          JobConfigs      `yaml:"jobs" json:"jobs" mapstructure:"jobs"`
+
+	MaxInFlight int `yaml:"max_in_flight,omitempty" json:"max_in_flight,omitempty" mapstructure:"max_in_flight"`
 }
 
 type RawConfig string
@@ -401,12 +403,30 @@ type PlanConfig struct {
 	// used on any step to interrupt the step after a given duration
 	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" mapstructure:"timeout"`
 
+	// used on any step to only make its outputs available to the rest of the
+	// build once the step has succeeded
+	StageOutputs bool `yaml:"stage_outputs,omitempty" json:"stage_outputs,omitempty" mapstructure:"stage_outputs"`
+
 	// not present in yaml
 	DependentGet string `yaml:"-" json:"-"`
 
 	// repeat the step up to N times, until it works
 	Attempts int `yaml:"attempts,omitempty" json:"attempts,omitempty" mapstructure:"attempts"`
 
+	// used by Task to only repeat the step when it exits with one of these codes
+	RetryableExitCodes []int `yaml:"retryable_exit_codes,omitempty" json:"retryable_exit_codes,omitempty" mapstructure:"retryable_exit_codes"`
+
+	// used by Task to limit how long its script may run
+	ScriptTimeout string `yaml:"script_timeout,omitempty" json:"script_timeout,omitempty" mapstructure:"script_timeout"`
+
+	// used by Task to wait for its script to exit when interrupted before
+	// killing it
+	StopTimeout string `yaml:"stop_timeout,omitempty" json:"stop_timeout,omitempty" mapstructure:"stop_timeout"`
+
+	// used by Task to run its script from somewhere other than the directory
+	// its inputs are placed in
+	Dir string `yaml:"dir,omitempty" json:"dir,omitempty" mapstructure:"dir"`
+
 	Version *VersionConfig `yaml:"version,omitempty" json:"version,omitempty" mapstructure:"version"`
 }
 
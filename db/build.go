@@ -23,8 +23,8 @@ const (
 	StatusErrored   Status = "errored"
 )
 
-const buildColumns = "id, name, job_id, team_id, status, manually_triggered, scheduled, engine, engine_metadata, start_time, end_time, reap_time"
-const qualifiedBuildColumns = "b.id, b.name, b.job_id, b.team_id, b.status, b.manually_triggered, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.reap_time, j.name as job_name, p.id as pipeline_id, p.name as pipeline_name, t.name as team_name"
+const buildColumns = "id, name, job_id, team_id, status, manually_triggered, trigger_reason, scheduled, engine, engine_metadata, start_time, end_time, reap_time"
+const qualifiedBuildColumns = "b.id, b.name, b.job_id, b.team_id, b.status, b.manually_triggered, b.trigger_reason, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.reap_time, j.name as job_name, p.id as pipeline_id, p.name as pipeline_name, t.name as team_name"
 
 //go:generate counterfeiter . Build
 
@@ -46,6 +46,7 @@ type Build interface {
 	IsScheduled() bool
 	IsRunning() bool
 	IsManuallyTriggered() bool
+	TriggerReason() string
 
 	Reload() (bool, error)
 
@@ -83,6 +84,7 @@ type build struct {
 	jobName      string
 
 	isManuallyTriggered bool
+	triggerReason       string
 
 	engine         string
 	engineMetadata string
@@ -133,6 +135,10 @@ func (b *build) IsManuallyTriggered() bool {
 	return b.isManuallyTriggered
 }
 
+func (b *build) TriggerReason() string {
+	return b.triggerReason
+}
+
 func (b *build) Engine() string {
 	return b.engine
 }
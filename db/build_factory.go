@@ -34,8 +34,9 @@ func (f *buildFactory) ScanBuild(row scannable) (Build, bool, error) {
 	var reapTime pq.NullTime
 	var teamName string
 	var isManuallyTriggered bool
+	var triggerReason string
 
-	err := row.Scan(&id, &name, &jobID, &teamID, &status, &isManuallyTriggered, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &reapTime, &jobName, &pipelineID, &pipelineName, &teamName)
+	err := row.Scan(&id, &name, &jobID, &teamID, &status, &isManuallyTriggered, &triggerReason, &scheduled, &engine, &engineMetadata, &startTime, &endTime, &reapTime, &jobName, &pipelineID, &pipelineName, &teamName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, false, nil
@@ -54,6 +55,7 @@ func (f *buildFactory) ScanBuild(row scannable) (Build, bool, error) {
 		status:              Status(status),
 		scheduled:           scheduled,
 		isManuallyTriggered: isManuallyTriggered,
+		triggerReason:       triggerReason,
 
 		engine:         engine.String,
 		engineMetadata: engineMetadata.String,
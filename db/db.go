@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/db/lock"
 	"github.com/concourse/atc/event"
 	"github.com/lib/pq"
@@ -76,9 +77,22 @@ type DB interface {
 	CreatePipe(pipeGUID string, url string, teamName string) error
 	GetPipe(pipeGUID string) (Pipe, error)
 
+	GetReferencedResourceTypes(teamID int) ([]ResourceTypeUsage, error)
+
+	SetPipelineVar(pipelineID int, key string, value string) error
+	GetPipelineVars(pipelineID int) (map[string]string, error)
+	GetEffectiveConfig(pipelineID int) (atc.Config, error)
+
+	PauseAllPipelines(teamName string) (int, error)
+	UnpauseAllPipelines(teamName string) (int, error)
+
 	GetTaskLock(logger lager.Logger, taskName string) (lock.Lock, bool, error)
 
 	DeleteBuildEventsByBuildIDs(buildIDs []int) error
+	PruneBuilds(policy BuildPruningPolicy) (int, error)
+
+	GetOrphanedVersionedResources(limit int) ([]SavedVersionedResource, error)
+	DeleteVersionedResources(ids []int) (int, error)
 }
 
 //go:generate counterfeiter . Notifier
@@ -9,7 +9,9 @@ import (
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/db/lock"
+	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/event"
 )
 
@@ -210,4 +212,197 @@ var _ = Describe("Builds", func() {
 			Expect(build4DB.ReapTime()).To(Equal(build1DB.ReapTime()))
 		})
 	})
+
+	Describe("PruneBuilds", func() {
+		var (
+			oldestBuild   db.Build
+			olderBuild    db.Build
+			runningBuild  db.Build
+			latestBuild   db.Build
+			otherJobBuild db.Build
+		)
+
+		finishedAgo := func(build db.Build, age time.Duration) {
+			_, err := dbConn.Exec(`
+				UPDATE builds
+				SET end_time = $2
+				WHERE id = $1
+			`, build.ID(), time.Now().Add(-age))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		buildExists := func(build db.Build) bool {
+			found, err := build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			return found
+		}
+
+		BeforeEach(func() {
+			oldestBuild = createAndFinishBuild(database, pipelineDB, "some-job", db.StatusSucceeded)
+			err := oldestBuild.SaveEvent(event.Log{Payload: "oldest"})
+			Expect(err).NotTo(HaveOccurred())
+			finishedAgo(oldestBuild, 48*time.Hour)
+
+			olderBuild = createAndFinishBuild(database, pipelineDB, "some-job", db.StatusFailed)
+			finishedAgo(olderBuild, 24*time.Hour)
+
+			runningBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+			started, err := runningBuild.Start("some-engine", "some-metadata")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			latestBuild = createAndFinishBuild(database, pipelineDB, "some-job", db.StatusSucceeded)
+			finishedAgo(latestBuild, 48*time.Hour)
+
+			otherJobBuild = createAndFinishBuild(database, pipelineDB, "some-other-job", db.StatusSucceeded)
+			finishedAgo(otherJobBuild, 48*time.Hour)
+		})
+
+		Context("when keeping the latest N builds of each job", func() {
+			It("prunes older completed builds, but not running ones", func() {
+				pruned, err := database.PruneBuilds(db.BuildPruningPolicy{KeepLatest: 2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(Equal(1))
+
+				Expect(buildExists(oldestBuild)).To(BeFalse())
+				Expect(buildExists(olderBuild)).To(BeTrue())
+				Expect(buildExists(runningBuild)).To(BeTrue())
+				Expect(buildExists(latestBuild)).To(BeTrue())
+				Expect(buildExists(otherJobBuild)).To(BeTrue())
+			})
+
+			It("does not count pending builds towards the latest N", func() {
+				pendingBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				pruned, err := database.PruneBuilds(db.BuildPruningPolicy{KeepLatest: 1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(Equal(2))
+
+				Expect(buildExists(oldestBuild)).To(BeFalse())
+				Expect(buildExists(olderBuild)).To(BeFalse())
+				Expect(buildExists(runningBuild)).To(BeTrue())
+				Expect(buildExists(latestBuild)).To(BeTrue())
+				Expect(buildExists(pendingBuild)).To(BeTrue())
+			})
+
+			It("is safe to run repeatedly", func() {
+				_, err := database.PruneBuilds(db.BuildPruningPolicy{KeepLatest: 2})
+				Expect(err).NotTo(HaveOccurred())
+
+				pruned, err := database.PruneBuilds(db.BuildPruningPolicy{KeepLatest: 2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(BeZero())
+			})
+
+			Context("when a job's latest successful build is older than its latest N", func() {
+				var (
+					upstreamBuild db.Build
+					savedVersion  db.SavedVersionedResource
+				)
+
+				BeforeEach(func() {
+					var err error
+					upstreamBuild, err = pipelineDB.CreateJobBuild("some-random-job")
+					Expect(err).NotTo(HaveOccurred())
+
+					savedVersion, err = pipelineDB.SaveOutput(upstreamBuild.ID(), db.VersionedResource{
+						Resource:   "some-resource",
+						Type:       "some-type",
+						Version:    db.Version{"ver": "1"},
+						PipelineID: pipelineDB.GetPipelineID(),
+					}, false)
+					Expect(err).NotTo(HaveOccurred())
+
+					err = upstreamBuild.Finish(db.StatusSucceeded)
+					Expect(err).NotTo(HaveOccurred())
+
+					createAndFinishBuild(database, pipelineDB, "some-random-job", db.StatusFailed)
+					createAndFinishBuild(database, pipelineDB, "some-random-job", db.StatusFailed)
+				})
+
+				It("keeps it, so that jobs with the job in passed can still resolve", func() {
+					_, err := database.PruneBuilds(db.BuildPruningPolicy{KeepLatest: 1})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(buildExists(upstreamBuild)).To(BeTrue())
+
+					teamFactory := dbng.NewTeamFactory(postgresRunner.OpenConn(), lock.NewLockFactory(postgresRunner.OpenSingleton()))
+					team, found, err := teamFactory.FindTeam("some-team")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+
+					dbngPipeline, found, err := team.Pipeline("some-pipeline")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+
+					versionsDB, err := dbngPipeline.LoadVersionsDB()
+					Expect(err).NotTo(HaveOccurred())
+
+					inputMapping, ok := algorithm.InputConfigs{
+						{
+							Name:       "some-input",
+							JobName:    "some-job",
+							Passed:     algorithm.JobSet{versionsDB.JobIDs["some-random-job"]: struct{}{}},
+							ResourceID: versionsDB.ResourceIDs["some-resource"],
+							JobID:      versionsDB.JobIDs["some-job"],
+						},
+					}.Resolve(versionsDB)
+					Expect(ok).To(BeTrue())
+					Expect(inputMapping["some-input"].VersionID).To(Equal(savedVersion.ID))
+				})
+			})
+
+			It("deletes the events of pruned builds", func() {
+				_, err := database.PruneBuilds(db.BuildPruningPolicy{KeepLatest: 2})
+				Expect(err).NotTo(HaveOccurred())
+
+				var count int
+				err = dbConn.QueryRow(`
+					SELECT COUNT(*) FROM build_events WHERE build_id = $1
+				`, oldestBuild.ID()).Scan(&count)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(BeZero())
+			})
+		})
+
+		Context("when pruning builds older than a duration", func() {
+			It("prunes old completed builds, but never the latest build of a job", func() {
+				pruned, err := database.PruneBuilds(db.BuildPruningPolicy{OlderThan: 36 * time.Hour})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(Equal(1))
+
+				Expect(buildExists(oldestBuild)).To(BeFalse())
+				Expect(buildExists(olderBuild)).To(BeTrue())
+				Expect(buildExists(runningBuild)).To(BeTrue())
+				Expect(buildExists(latestBuild)).To(BeTrue())
+				Expect(buildExists(otherJobBuild)).To(BeTrue())
+			})
+
+			It("prunes old one-off builds", func() {
+				oneOffBuild, err := teamDB.CreateOneOffBuild()
+				Expect(err).NotTo(HaveOccurred())
+				err = oneOffBuild.Finish(db.StatusSucceeded)
+				Expect(err).NotTo(HaveOccurred())
+				finishedAgo(oneOffBuild, 48*time.Hour)
+
+				pruned, err := database.PruneBuilds(db.BuildPruningPolicy{OlderThan: 36 * time.Hour})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(Equal(2))
+
+				Expect(buildExists(oneOffBuild)).To(BeFalse())
+			})
+		})
+
+		Context("when no policy is given", func() {
+			It("prunes nothing", func() {
+				pruned, err := database.PruneBuilds(db.BuildPruningPolicy{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pruned).To(BeZero())
+
+				Expect(buildExists(oldestBuild)).To(BeTrue())
+			})
+		})
+	})
 })
@@ -0,0 +1,403 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/lock"
+)
+
+var _ = Describe("SQL DB Pipelines", func() {
+	var dbConn db.Conn
+	var listener *pq.Listener
+
+	var database db.DB
+	var teamDBFactory db.TeamDBFactory
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.OpenDB())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		lockFactory := lock.NewLockFactory(postgresRunner.OpenSingleton())
+		teamDBFactory = db.NewTeamDBFactory(dbConn, bus, lockFactory)
+		database = db.NewSQL(dbConn, bus, lockFactory)
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("GetReferencedResourceTypes", func() {
+		var team db.SavedTeam
+
+		BeforeEach(func() {
+			var err error
+			team, err = database.CreateTeam(db.Team{Name: "some-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			otherTeam, err := database.CreateTeam(db.Team{Name: "some-other-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			teamDB := teamDBFactory.GetTeamDB(team.Name)
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("pipeline-a", atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-repo", Type: "git"},
+					{Name: "some-timer", Type: "time"},
+				},
+				Jobs: atc.JobConfigs{
+					{
+						Name: "job-1",
+						Plan: atc.PlanSequence{
+							{Get: "some-repo"},
+							{Get: "some-timer", Trigger: true},
+						},
+					},
+					{
+						Name: "job-2",
+						Plan: atc.PlanSequence{
+							{Get: "some-repo"},
+							{
+								Task: "some-task",
+								TaskConfig: &atc.TaskConfig{
+									ImageResource: &atc.ImageResource{
+										Type: "docker-image",
+									},
+								},
+							},
+						},
+					},
+				},
+			}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("pipeline-b", atc.Config{
+				ResourceTypes: atc.ResourceTypes{
+					{Name: "some-custom-type", Type: "docker-image"},
+				},
+				Resources: atc.ResourceConfigs{
+					{Name: "other-repo", Type: "git"},
+					{Name: "some-custom-resource", Type: "some-custom-type"},
+				},
+				Jobs: atc.JobConfigs{
+					{
+						Name: "job-3",
+						Plan: atc.PlanSequence{
+							{Get: "other-repo"},
+							{Put: "some-custom-resource"},
+						},
+					},
+				},
+			}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("paused-pipeline", atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-bucket", Type: "s3"},
+				},
+			}, db.ConfigVersion(0), db.PipelinePaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDBFactory.GetTeamDB(otherTeam.Name).SaveConfigToBeDeprecated("other-team-pipeline", atc.Config{
+				Resources: atc.ResourceConfigs{
+					{Name: "some-repo", Type: "git"},
+					{Name: "some-pool", Type: "pool"},
+				},
+			}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns each type referenced by the team's active pipelines with usage counts", func() {
+			usages, err := database.GetReferencedResourceTypes(team.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usages).To(Equal([]db.ResourceTypeUsage{
+				{Type: "docker-image", Pipelines: 2, Jobs: 1},
+				{Type: "git", Pipelines: 2, Jobs: 3},
+				{Type: "some-custom-type", Pipelines: 1, Jobs: 1},
+				{Type: "time", Pipelines: 1, Jobs: 1},
+			}))
+		})
+
+		Context("when the team has no pipelines", func() {
+			It("returns an empty list", func() {
+				emptyTeam, err := database.CreateTeam(db.Team{Name: "empty-team"})
+				Expect(err).NotTo(HaveOccurred())
+
+				usages, err := database.GetReferencedResourceTypes(emptyTeam.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(usages).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("pipeline vars", func() {
+		var pipeline db.SavedPipeline
+		var otherPipeline db.SavedPipeline
+
+		BeforeEach(func() {
+			team, err := database.CreateTeam(db.Team{Name: "some-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			teamDB := teamDBFactory.GetTeamDB(team.Name)
+
+			pipeline, _, err = teamDB.SaveConfigToBeDeprecated("some-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			otherPipeline, _, err = teamDB.SaveConfigToBeDeprecated("some-other-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns no vars for a pipeline that has none", func() {
+			vars, err := database.GetPipelineVars(pipeline.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(BeEmpty())
+		})
+
+		It("returns the vars set on the pipeline", func() {
+			err := database.SetPipelineVar(pipeline.ID, "region", "us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.SetPipelineVar(pipeline.ID, "bucket", "some-bucket")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.SetPipelineVar(otherPipeline.ID, "region", "eu-west-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			vars, err := database.GetPipelineVars(pipeline.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(map[string]string{
+				"region": "us-east-1",
+				"bucket": "some-bucket",
+			}))
+		})
+
+		It("overwrites a var that is set again", func() {
+			err := database.SetPipelineVar(pipeline.ID, "region", "us-east-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = database.SetPipelineVar(pipeline.ID, "region", "us-west-2")
+			Expect(err).NotTo(HaveOccurred())
+
+			vars, err := database.GetPipelineVars(pipeline.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(Equal(map[string]string{"region": "us-west-2"}))
+		})
+
+		Describe("GetEffectiveConfig", func() {
+			var configuredPipeline db.SavedPipeline
+
+			BeforeEach(func() {
+				teamDB := teamDBFactory.GetTeamDB("some-team")
+
+				var err error
+				configuredPipeline, _, err = teamDB.SaveConfigToBeDeprecated("configured-pipeline", atc.Config{
+					Resources: atc.ResourceConfigs{
+						{
+							Name: "some-resource",
+							Type: "s3",
+							Source: atc.Source{
+								"bucket":            "((var:bucket))",
+								"region":            "us-((var:region))-1",
+								"secret_access_key": "((aws-secret-key))",
+							},
+						},
+					},
+					Jobs: atc.JobConfigs{
+						{
+							Name: "some-job",
+							Plan: atc.PlanSequence{
+								{
+									Task: "some-task",
+									TaskConfig: &atc.TaskConfig{
+										Params: map[string]string{
+											"MESSAGE": "((var:message))",
+											"UNSET":   "((var:unset))",
+										},
+									},
+								},
+							},
+						},
+					},
+				}, db.ConfigVersion(0), db.PipelineUnpaused)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = database.SetPipelineVar(configuredPipeline.ID, "bucket", "some-bucket")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = database.SetPipelineVar(configuredPipeline.ID, "region", "east")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = database.SetPipelineVar(configuredPipeline.ID, "message", `say "hi"`)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = database.SetPipelineVar(pipeline.ID, "unset", "some-other-pipeline-value")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("substitutes the pipeline's vars", func() {
+				config, err := database.GetEffectiveConfig(configuredPipeline.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config.Resources[0].Source["bucket"]).To(Equal("some-bucket"))
+				Expect(config.Resources[0].Source["region"]).To(Equal("us-east-1"))
+				Expect(config.Jobs[0].Plan[0].TaskConfig.Params["MESSAGE"]).To(Equal(`say "hi"`))
+			})
+
+			It("leaves references to vars that are not set", func() {
+				config, err := database.GetEffectiveConfig(configuredPipeline.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config.Jobs[0].Plan[0].TaskConfig.Params["UNSET"]).To(Equal("((var:unset))"))
+			})
+
+			It("redacts references to credentials", func() {
+				config, err := database.GetEffectiveConfig(configuredPipeline.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(config.Resources[0].Source["secret_access_key"]).To(Equal("((redacted))"))
+			})
+
+			It("does not change the stored config", func() {
+				_, err := database.GetEffectiveConfig(configuredPipeline.ID)
+				Expect(err).NotTo(HaveOccurred())
+
+				config, _, _, err := teamDBFactory.GetTeamDB("some-team").GetConfig("configured-pipeline")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Resources[0].Source["bucket"]).To(Equal("((var:bucket))"))
+			})
+		})
+	})
+
+	Describe("pausing all pipelines of a team", func() {
+		var teamDB db.TeamDB
+		var otherTeamDB db.TeamDB
+
+		BeforeEach(func() {
+			team, err := database.CreateTeam(db.Team{Name: "some-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			otherTeam, err := database.CreateTeam(db.Team{Name: "some-other-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			teamDB = teamDBFactory.GetTeamDB(team.Name)
+			otherTeamDB = teamDBFactory.GetTeamDB(otherTeam.Name)
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("pipeline-a", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("pipeline-b", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("paused-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelinePaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = otherTeamDB.SaveConfigToBeDeprecated("other-team-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		isPaused := func(teamDB db.TeamDB, pipelineName string) bool {
+			pipeline, found, err := teamDB.GetPipelineByName(pipelineName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			return pipeline.Paused
+		}
+
+		It("pauses only the team's pipelines, returning how many were paused", func() {
+			count, err := database.PauseAllPipelines("some-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			Expect(isPaused(teamDB, "pipeline-a")).To(BeTrue())
+			Expect(isPaused(teamDB, "pipeline-b")).To(BeTrue())
+			Expect(isPaused(teamDB, "paused-pipeline")).To(BeTrue())
+			Expect(isPaused(otherTeamDB, "other-team-pipeline")).To(BeFalse())
+		})
+
+		It("unpauses only the team's pipelines, returning how many were unpaused", func() {
+			_, err := database.PauseAllPipelines("some-other-team")
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := database.UnpauseAllPipelines("some-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+
+			Expect(isPaused(teamDB, "pipeline-a")).To(BeFalse())
+			Expect(isPaused(teamDB, "pipeline-b")).To(BeFalse())
+			Expect(isPaused(teamDB, "paused-pipeline")).To(BeFalse())
+			Expect(isPaused(otherTeamDB, "other-team-pipeline")).To(BeTrue())
+		})
+
+		It("matches the team name case-insensitively", func() {
+			count, err := database.PauseAllPipelines("SOME-TEAM")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+		})
+
+		It("affects nothing for an unknown team", func() {
+			count, err := database.PauseAllPipelines("bogus-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+
+			Expect(isPaused(otherTeamDB, "other-team-pipeline")).To(BeFalse())
+		})
+	})
+
+	Describe("GetAllActivePipelines", func() {
+		BeforeEach(func() {
+			team, err := database.CreateTeam(db.Team{Name: "some-team"})
+			Expect(err).NotTo(HaveOccurred())
+
+			teamDB := teamDBFactory.GetTeamDB(team.Name)
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("active-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = teamDB.SaveConfigToBeDeprecated("paused-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelinePaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			archived, _, err := teamDB.SaveConfigToBeDeprecated("archived-pipeline", atc.Config{}, db.ConfigVersion(0), db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = dbConn.Exec(`UPDATE pipelines SET archived = true WHERE id = $1`, archived.ID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		pipelineNames := func(pipelines []db.SavedPipeline) []string {
+			names := []string{}
+			for _, pipeline := range pipelines {
+				names = append(names, pipeline.Name)
+			}
+			return names
+		}
+
+		It("excludes archived pipelines", func() {
+			pipelines, err := database.(*db.SQLDB).GetAllActivePipelines()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pipelineNames(pipelines)).To(ConsistOf("active-pipeline", "paused-pipeline"))
+		})
+
+		It("leaves them in GetAllPipelines, marked as archived", func() {
+			pipelines, err := database.(*db.SQLDB).GetAllPipelines()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pipelineNames(pipelines)).To(ConsistOf("active-pipeline", "paused-pipeline", "archived-pipeline"))
+
+			for _, pipeline := range pipelines {
+				Expect(pipeline.Archived).To(Equal(pipeline.Name == "archived-pipeline"))
+			}
+		})
+	})
+})
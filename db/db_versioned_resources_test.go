@@ -0,0 +1,154 @@
+package db_test
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/db"
+	"github.com/concourse/atc/db/lock"
+)
+
+var _ = Describe("Orphaned versioned resources", func() {
+	var (
+		dbConn     db.Conn
+		listener   *pq.Listener
+		database   db.DB
+		pipelineDB db.PipelineDB
+		teamDB     db.TeamDB
+		config     atc.Config
+
+		orphanedVersion   db.SavedVersionedResource
+		referencedVersion db.SavedVersionedResource
+		activeVersion     db.SavedVersionedResource
+	)
+
+	BeforeEach(func() {
+		postgresRunner.Truncate()
+
+		dbConn = db.Wrap(postgresRunner.OpenDB())
+		listener = pq.NewListener(postgresRunner.DataSourceName(), time.Second, time.Minute, nil)
+
+		Eventually(listener.Ping, 5*time.Second).ShouldNot(HaveOccurred())
+		bus := db.NewNotificationsBus(listener, dbConn)
+
+		lockFactory := lock.NewLockFactory(postgresRunner.OpenSingleton())
+		database = db.NewSQL(dbConn, bus, lockFactory)
+		_, err := database.CreateTeam(db.Team{Name: "some-team"})
+		Expect(err).NotTo(HaveOccurred())
+
+		teamDBFactory := db.NewTeamDBFactory(dbConn, bus, lockFactory)
+		teamDB = teamDBFactory.GetTeamDB("some-team")
+
+		config = atc.Config{
+			Jobs: atc.JobConfigs{
+				{
+					Name: "some-job",
+				},
+			},
+			Resources: atc.ResourceConfigs{
+				{
+					Name: "some-resource",
+					Type: "some-type",
+				},
+				{
+					Name: "some-removed-resource",
+					Type: "some-type",
+				},
+			},
+		}
+
+		pipeline, _, err := teamDB.SaveConfigToBeDeprecated("some-pipeline", config, db.ConfigVersion(1), db.PipelineUnpaused)
+		Expect(err).NotTo(HaveOccurred())
+
+		pipelineDBFactory := db.NewPipelineDBFactory(dbConn, bus, lockFactory)
+		pipelineDB = pipelineDBFactory.Build(pipeline)
+
+		err = pipelineDB.SaveResourceVersions(config.Resources[0], []atc.Version{{"version": "active"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		activeVersion, _, err = pipelineDB.GetLatestVersionedResource("some-resource")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = pipelineDB.SaveResourceVersions(config.Resources[1], []atc.Version{{"version": "orphaned"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		orphanedVersion, _, err = pipelineDB.GetLatestVersionedResource("some-removed-resource")
+		Expect(err).NotTo(HaveOccurred())
+
+		build, err := pipelineDB.CreateJobBuild("some-job")
+		Expect(err).NotTo(HaveOccurred())
+
+		referencedVersion, err = pipelineDB.SaveInput(build.ID(), db.BuildInput{
+			Name: "some-input",
+			VersionedResource: db.VersionedResource{
+				Resource:   "some-removed-resource",
+				Type:       "some-type",
+				Version:    db.Version{"version": "referenced"},
+				PipelineID: pipeline.ID,
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		config.Resources = config.Resources[:1]
+
+		_, _, err = teamDB.SaveConfigToBeDeprecated("some-pipeline", config, pipelineDB.ConfigVersion(), db.PipelineNoChange)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := dbConn.Close()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = listener.Close()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("GetOrphanedVersionedResources", func() {
+		It("returns only unreferenced versions of resources no longer in the config", func() {
+			orphaned, err := database.GetOrphanedVersionedResources(10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphaned).To(HaveLen(1))
+			Expect(orphaned[0].ID).To(Equal(orphanedVersion.ID))
+			Expect(orphaned[0].Resource).To(Equal("some-removed-resource"))
+			Expect(orphaned[0].Version).To(Equal(db.Version{"version": "orphaned"}))
+		})
+
+		It("respects the limit", func() {
+			orphaned, err := database.GetOrphanedVersionedResources(0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphaned).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteVersionedResources", func() {
+		It("deletes orphaned versions", func() {
+			deleted, err := database.DeleteVersionedResources([]int{orphanedVersion.ID})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(Equal(1))
+
+			orphaned, err := database.GetOrphanedVersionedResources(10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphaned).To(BeEmpty())
+		})
+
+		It("refuses to delete versions referenced by builds or belonging to active resources", func() {
+			deleted, err := database.DeleteVersionedResources([]int{referencedVersion.ID, activeVersion.ID})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeZero())
+
+			_, found, err := pipelineDB.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
+		It("does nothing if the list is empty", func() {
+			deleted, err := database.DeleteVersionedResources([]int{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deleted).To(BeZero())
+		})
+	})
+})
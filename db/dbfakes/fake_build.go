@@ -165,6 +165,15 @@ type FakeBuild struct {
 	isManuallyTriggeredReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	TriggerReasonStub        func() string
+	triggerReasonMutex       sync.RWMutex
+	triggerReasonArgsForCall []struct{}
+	triggerReasonReturns     struct {
+		result1 string
+	}
+	triggerReasonReturnsOnCall map[int]struct {
+		result1 string
+	}
 	ReloadStub        func() (bool, error)
 	reloadMutex       sync.RWMutex
 	reloadArgsForCall []struct{}
@@ -999,6 +1008,46 @@ func (fake *FakeBuild) IsManuallyTriggeredReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeBuild) TriggerReason() string {
+	fake.triggerReasonMutex.Lock()
+	ret, specificReturn := fake.triggerReasonReturnsOnCall[len(fake.triggerReasonArgsForCall)]
+	fake.triggerReasonArgsForCall = append(fake.triggerReasonArgsForCall, struct{}{})
+	fake.recordInvocation("TriggerReason", []interface{}{})
+	fake.triggerReasonMutex.Unlock()
+	if fake.TriggerReasonStub != nil {
+		return fake.TriggerReasonStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.triggerReasonReturns.result1
+}
+
+func (fake *FakeBuild) TriggerReasonCallCount() int {
+	fake.triggerReasonMutex.RLock()
+	defer fake.triggerReasonMutex.RUnlock()
+	return len(fake.triggerReasonArgsForCall)
+}
+
+func (fake *FakeBuild) TriggerReasonReturns(result1 string) {
+	fake.TriggerReasonStub = nil
+	fake.triggerReasonReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeBuild) TriggerReasonReturnsOnCall(i int, result1 string) {
+	fake.TriggerReasonStub = nil
+	if fake.triggerReasonReturnsOnCall == nil {
+		fake.triggerReasonReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.triggerReasonReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
 func (fake *FakeBuild) Reload() (bool, error) {
 	fake.reloadMutex.Lock()
 	ret, specificReturn := fake.reloadReturnsOnCall[len(fake.reloadArgsForCall)]
@@ -1622,6 +1671,8 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.isRunningMutex.RUnlock()
 	fake.isManuallyTriggeredMutex.RLock()
 	defer fake.isManuallyTriggeredMutex.RUnlock()
+	fake.triggerReasonMutex.RLock()
+	defer fake.triggerReasonMutex.RUnlock()
 	fake.reloadMutex.RLock()
 	defer fake.reloadMutex.RUnlock()
 	fake.eventsMutex.RLock()
@@ -69,6 +69,15 @@ type FakePipelineDB struct {
 	configReturnsOnCall map[int]struct {
 		result1 atc.Config
 	}
+	MaxInFlightStub        func() int
+	maxInFlightMutex       sync.RWMutex
+	maxInFlightArgsForCall []struct{}
+	maxInFlightReturns     struct {
+		result1 int
+	}
+	maxInFlightReturnsOnCall map[int]struct {
+		result1 int
+	}
 	ConfigVersionStub        func() db.ConfigVersion
 	configVersionMutex       sync.RWMutex
 	configVersionArgsForCall []struct{}
@@ -458,6 +467,34 @@ type FakePipelineDB struct {
 		result2 bool
 		result3 error
 	}
+	GetNextEligibleBuildForSerialGroupStub        func(serialGroup string) (db.Build, bool, error)
+	getNextEligibleBuildForSerialGroupMutex       sync.RWMutex
+	getNextEligibleBuildForSerialGroupArgsForCall []struct {
+		serialGroup string
+	}
+	getNextEligibleBuildForSerialGroupReturns struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
+	getNextEligibleBuildForSerialGroupReturnsOnCall map[int]struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}
+	CountRunningBuildsForPipelineStub        func(int) (int, error)
+	countRunningBuildsForPipelineMutex       sync.RWMutex
+	countRunningBuildsForPipelineArgsForCall []struct {
+		pipelineID int
+	}
+	countRunningBuildsForPipelineReturns struct {
+		result1 int
+		result2 error
+	}
+	countRunningBuildsForPipelineReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	GetJobFinishedAndNextBuildStub        func(job string) (db.Build, db.Build, error)
 	getJobFinishedAndNextBuildMutex       sync.RWMutex
 	getJobFinishedAndNextBuildArgsForCall []struct {
@@ -609,6 +646,21 @@ type FakePipelineDB struct {
 		result1 db.SavedVersionedResource
 		result2 error
 	}
+	SaveOutputsStub        func(buildID int, vrs []db.VersionedResource, explicit bool) ([]db.SavedVersionedResource, error)
+	saveOutputsMutex       sync.RWMutex
+	saveOutputsArgsForCall []struct {
+		buildID  int
+		vrs      []db.VersionedResource
+		explicit bool
+	}
+	saveOutputsReturns struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}
+	saveOutputsReturnsOnCall map[int]struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}
 	GetBuildsWithVersionAsInputStub        func(versionedResourceID int) ([]db.Build, error)
 	getBuildsWithVersionAsInputMutex       sync.RWMutex
 	getBuildsWithVersionAsInputArgsForCall []struct {
@@ -635,6 +687,22 @@ type FakePipelineDB struct {
 		result1 []db.Build
 		result2 error
 	}
+	GetBuildsForResourceVersionStub        func(resourceName string, version atc.Version) (db.ResourceVersionBuilds, bool, error)
+	getBuildsForResourceVersionMutex       sync.RWMutex
+	getBuildsForResourceVersionArgsForCall []struct {
+		resourceName string
+		version      atc.Version
+	}
+	getBuildsForResourceVersionReturns struct {
+		result1 db.ResourceVersionBuilds
+		result2 bool
+		result3 error
+	}
+	getBuildsForResourceVersionReturnsOnCall map[int]struct {
+		result1 db.ResourceVersionBuilds
+		result2 bool
+		result3 error
+	}
 	GetDashboardStub        func() (db.Dashboard, atc.GroupConfigs, error)
 	getDashboardMutex       sync.RWMutex
 	getDashboardArgsForCall []struct{}
@@ -918,6 +986,46 @@ func (fake *FakePipelineDB) ConfigReturnsOnCall(i int, result1 atc.Config) {
 	}{result1}
 }
 
+func (fake *FakePipelineDB) MaxInFlight() int {
+	fake.maxInFlightMutex.Lock()
+	ret, specificReturn := fake.maxInFlightReturnsOnCall[len(fake.maxInFlightArgsForCall)]
+	fake.maxInFlightArgsForCall = append(fake.maxInFlightArgsForCall, struct{}{})
+	fake.recordInvocation("MaxInFlight", []interface{}{})
+	fake.maxInFlightMutex.Unlock()
+	if fake.MaxInFlightStub != nil {
+		return fake.MaxInFlightStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.maxInFlightReturns.result1
+}
+
+func (fake *FakePipelineDB) MaxInFlightCallCount() int {
+	fake.maxInFlightMutex.RLock()
+	defer fake.maxInFlightMutex.RUnlock()
+	return len(fake.maxInFlightArgsForCall)
+}
+
+func (fake *FakePipelineDB) MaxInFlightReturns(result1 int) {
+	fake.MaxInFlightStub = nil
+	fake.maxInFlightReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakePipelineDB) MaxInFlightReturnsOnCall(i int, result1 int) {
+	fake.MaxInFlightStub = nil
+	if fake.maxInFlightReturnsOnCall == nil {
+		fake.maxInFlightReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.maxInFlightReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
 func (fake *FakePipelineDB) ConfigVersion() db.ConfigVersion {
 	fake.configVersionMutex.Lock()
 	ret, specificReturn := fake.configVersionReturnsOnCall[len(fake.configVersionArgsForCall)]
@@ -2448,6 +2556,111 @@ func (fake *FakePipelineDB) GetNextPendingBuildBySerialGroupReturnsOnCall(i int,
 	}{result1, result2, result3}
 }
 
+func (fake *FakePipelineDB) GetNextEligibleBuildForSerialGroup(serialGroup string) (db.Build, bool, error) {
+	fake.getNextEligibleBuildForSerialGroupMutex.Lock()
+	ret, specificReturn := fake.getNextEligibleBuildForSerialGroupReturnsOnCall[len(fake.getNextEligibleBuildForSerialGroupArgsForCall)]
+	fake.getNextEligibleBuildForSerialGroupArgsForCall = append(fake.getNextEligibleBuildForSerialGroupArgsForCall, struct {
+		serialGroup string
+	}{serialGroup})
+	fake.recordInvocation("GetNextEligibleBuildForSerialGroup", []interface{}{serialGroup})
+	fake.getNextEligibleBuildForSerialGroupMutex.Unlock()
+	if fake.GetNextEligibleBuildForSerialGroupStub != nil {
+		return fake.GetNextEligibleBuildForSerialGroupStub(serialGroup)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getNextEligibleBuildForSerialGroupReturns.result1, fake.getNextEligibleBuildForSerialGroupReturns.result2, fake.getNextEligibleBuildForSerialGroupReturns.result3
+}
+
+func (fake *FakePipelineDB) GetNextEligibleBuildForSerialGroupCallCount() int {
+	fake.getNextEligibleBuildForSerialGroupMutex.RLock()
+	defer fake.getNextEligibleBuildForSerialGroupMutex.RUnlock()
+	return len(fake.getNextEligibleBuildForSerialGroupArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetNextEligibleBuildForSerialGroupArgsForCall(i int) string {
+	fake.getNextEligibleBuildForSerialGroupMutex.RLock()
+	defer fake.getNextEligibleBuildForSerialGroupMutex.RUnlock()
+	return fake.getNextEligibleBuildForSerialGroupArgsForCall[i].serialGroup
+}
+
+func (fake *FakePipelineDB) GetNextEligibleBuildForSerialGroupReturns(result1 db.Build, result2 bool, result3 error) {
+	fake.GetNextEligibleBuildForSerialGroupStub = nil
+	fake.getNextEligibleBuildForSerialGroupReturns = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetNextEligibleBuildForSerialGroupReturnsOnCall(i int, result1 db.Build, result2 bool, result3 error) {
+	fake.GetNextEligibleBuildForSerialGroupStub = nil
+	if fake.getNextEligibleBuildForSerialGroupReturnsOnCall == nil {
+		fake.getNextEligibleBuildForSerialGroupReturnsOnCall = make(map[int]struct {
+			result1 db.Build
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getNextEligibleBuildForSerialGroupReturnsOnCall[i] = struct {
+		result1 db.Build
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) CountRunningBuildsForPipeline(pipelineID int) (int, error) {
+	fake.countRunningBuildsForPipelineMutex.Lock()
+	ret, specificReturn := fake.countRunningBuildsForPipelineReturnsOnCall[len(fake.countRunningBuildsForPipelineArgsForCall)]
+	fake.countRunningBuildsForPipelineArgsForCall = append(fake.countRunningBuildsForPipelineArgsForCall, struct {
+		pipelineID int
+	}{pipelineID})
+	fake.recordInvocation("CountRunningBuildsForPipeline", []interface{}{pipelineID})
+	fake.countRunningBuildsForPipelineMutex.Unlock()
+	if fake.CountRunningBuildsForPipelineStub != nil {
+		return fake.CountRunningBuildsForPipelineStub(pipelineID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.countRunningBuildsForPipelineReturns.result1, fake.countRunningBuildsForPipelineReturns.result2
+}
+
+func (fake *FakePipelineDB) CountRunningBuildsForPipelineCallCount() int {
+	fake.countRunningBuildsForPipelineMutex.RLock()
+	defer fake.countRunningBuildsForPipelineMutex.RUnlock()
+	return len(fake.countRunningBuildsForPipelineArgsForCall)
+}
+
+func (fake *FakePipelineDB) CountRunningBuildsForPipelineArgsForCall(i int) int {
+	fake.countRunningBuildsForPipelineMutex.RLock()
+	defer fake.countRunningBuildsForPipelineMutex.RUnlock()
+	return fake.countRunningBuildsForPipelineArgsForCall[i].pipelineID
+}
+
+func (fake *FakePipelineDB) CountRunningBuildsForPipelineReturns(result1 int, result2 error) {
+	fake.CountRunningBuildsForPipelineStub = nil
+	fake.countRunningBuildsForPipelineReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) CountRunningBuildsForPipelineReturnsOnCall(i int, result1 int, result2 error) {
+	fake.CountRunningBuildsForPipelineStub = nil
+	if fake.countRunningBuildsForPipelineReturnsOnCall == nil {
+		fake.countRunningBuildsForPipelineReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countRunningBuildsForPipelineReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) GetJobFinishedAndNextBuild(job string) (db.Build, db.Build, error) {
 	fake.getJobFinishedAndNextBuildMutex.Lock()
 	ret, specificReturn := fake.getJobFinishedAndNextBuildReturnsOnCall[len(fake.getJobFinishedAndNextBuildArgsForCall)]
@@ -3022,6 +3235,64 @@ func (fake *FakePipelineDB) SaveOutputReturnsOnCall(i int, result1 db.SavedVersi
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) SaveOutputs(buildID int, vrs []db.VersionedResource, explicit bool) ([]db.SavedVersionedResource, error) {
+	var vrsCopy []db.VersionedResource
+	if vrs != nil {
+		vrsCopy = make([]db.VersionedResource, len(vrs))
+		copy(vrsCopy, vrs)
+	}
+	fake.saveOutputsMutex.Lock()
+	ret, specificReturn := fake.saveOutputsReturnsOnCall[len(fake.saveOutputsArgsForCall)]
+	fake.saveOutputsArgsForCall = append(fake.saveOutputsArgsForCall, struct {
+		buildID  int
+		vrs      []db.VersionedResource
+		explicit bool
+	}{buildID, vrsCopy, explicit})
+	fake.recordInvocation("SaveOutputs", []interface{}{buildID, vrsCopy, explicit})
+	fake.saveOutputsMutex.Unlock()
+	if fake.SaveOutputsStub != nil {
+		return fake.SaveOutputsStub(buildID, vrs, explicit)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.saveOutputsReturns.result1, fake.saveOutputsReturns.result2
+}
+
+func (fake *FakePipelineDB) SaveOutputsCallCount() int {
+	fake.saveOutputsMutex.RLock()
+	defer fake.saveOutputsMutex.RUnlock()
+	return len(fake.saveOutputsArgsForCall)
+}
+
+func (fake *FakePipelineDB) SaveOutputsArgsForCall(i int) (int, []db.VersionedResource, bool) {
+	fake.saveOutputsMutex.RLock()
+	defer fake.saveOutputsMutex.RUnlock()
+	return fake.saveOutputsArgsForCall[i].buildID, fake.saveOutputsArgsForCall[i].vrs, fake.saveOutputsArgsForCall[i].explicit
+}
+
+func (fake *FakePipelineDB) SaveOutputsReturns(result1 []db.SavedVersionedResource, result2 error) {
+	fake.SaveOutputsStub = nil
+	fake.saveOutputsReturns = struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineDB) SaveOutputsReturnsOnCall(i int, result1 []db.SavedVersionedResource, result2 error) {
+	fake.SaveOutputsStub = nil
+	if fake.saveOutputsReturnsOnCall == nil {
+		fake.saveOutputsReturnsOnCall = make(map[int]struct {
+			result1 []db.SavedVersionedResource
+			result2 error
+		})
+	}
+	fake.saveOutputsReturnsOnCall[i] = struct {
+		result1 []db.SavedVersionedResource
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipelineDB) GetBuildsWithVersionAsInput(versionedResourceID int) ([]db.Build, error) {
 	fake.getBuildsWithVersionAsInputMutex.Lock()
 	ret, specificReturn := fake.getBuildsWithVersionAsInputReturnsOnCall[len(fake.getBuildsWithVersionAsInputArgsForCall)]
@@ -3124,6 +3395,61 @@ func (fake *FakePipelineDB) GetBuildsWithVersionAsOutputReturnsOnCall(i int, res
 	}{result1, result2}
 }
 
+func (fake *FakePipelineDB) GetBuildsForResourceVersion(resourceName string, version atc.Version) (db.ResourceVersionBuilds, bool, error) {
+	fake.getBuildsForResourceVersionMutex.Lock()
+	ret, specificReturn := fake.getBuildsForResourceVersionReturnsOnCall[len(fake.getBuildsForResourceVersionArgsForCall)]
+	fake.getBuildsForResourceVersionArgsForCall = append(fake.getBuildsForResourceVersionArgsForCall, struct {
+		resourceName string
+		version      atc.Version
+	}{resourceName, version})
+	fake.recordInvocation("GetBuildsForResourceVersion", []interface{}{resourceName, version})
+	fake.getBuildsForResourceVersionMutex.Unlock()
+	if fake.GetBuildsForResourceVersionStub != nil {
+		return fake.GetBuildsForResourceVersionStub(resourceName, version)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getBuildsForResourceVersionReturns.result1, fake.getBuildsForResourceVersionReturns.result2, fake.getBuildsForResourceVersionReturns.result3
+}
+
+func (fake *FakePipelineDB) GetBuildsForResourceVersionCallCount() int {
+	fake.getBuildsForResourceVersionMutex.RLock()
+	defer fake.getBuildsForResourceVersionMutex.RUnlock()
+	return len(fake.getBuildsForResourceVersionArgsForCall)
+}
+
+func (fake *FakePipelineDB) GetBuildsForResourceVersionArgsForCall(i int) (string, atc.Version) {
+	fake.getBuildsForResourceVersionMutex.RLock()
+	defer fake.getBuildsForResourceVersionMutex.RUnlock()
+	return fake.getBuildsForResourceVersionArgsForCall[i].resourceName, fake.getBuildsForResourceVersionArgsForCall[i].version
+}
+
+func (fake *FakePipelineDB) GetBuildsForResourceVersionReturns(result1 db.ResourceVersionBuilds, result2 bool, result3 error) {
+	fake.GetBuildsForResourceVersionStub = nil
+	fake.getBuildsForResourceVersionReturns = struct {
+		result1 db.ResourceVersionBuilds
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipelineDB) GetBuildsForResourceVersionReturnsOnCall(i int, result1 db.ResourceVersionBuilds, result2 bool, result3 error) {
+	fake.GetBuildsForResourceVersionStub = nil
+	if fake.getBuildsForResourceVersionReturnsOnCall == nil {
+		fake.getBuildsForResourceVersionReturnsOnCall = make(map[int]struct {
+			result1 db.ResourceVersionBuilds
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getBuildsForResourceVersionReturnsOnCall[i] = struct {
+		result1 db.ResourceVersionBuilds
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakePipelineDB) GetDashboard() (db.Dashboard, atc.GroupConfigs, error) {
 	fake.getDashboardMutex.Lock()
 	ret, specificReturn := fake.getDashboardReturnsOnCall[len(fake.getDashboardArgsForCall)]
@@ -3265,6 +3591,8 @@ func (fake *FakePipelineDB) Invocations() map[string][][]interface{} {
 	defer fake.teamIDMutex.RUnlock()
 	fake.configMutex.RLock()
 	defer fake.configMutex.RUnlock()
+	fake.maxInFlightMutex.RLock()
+	defer fake.maxInFlightMutex.RUnlock()
 	fake.configVersionMutex.RLock()
 	defer fake.configVersionMutex.RUnlock()
 	fake.reloadMutex.RLock()
@@ -3327,6 +3655,10 @@ func (fake *FakePipelineDB) Invocations() map[string][][]interface{} {
 	defer fake.getRunningBuildsBySerialGroupMutex.RUnlock()
 	fake.getNextPendingBuildBySerialGroupMutex.RLock()
 	defer fake.getNextPendingBuildBySerialGroupMutex.RUnlock()
+	fake.getNextEligibleBuildForSerialGroupMutex.RLock()
+	defer fake.getNextEligibleBuildForSerialGroupMutex.RUnlock()
+	fake.countRunningBuildsForPipelineMutex.RLock()
+	defer fake.countRunningBuildsForPipelineMutex.RUnlock()
 	fake.getJobFinishedAndNextBuildMutex.RLock()
 	defer fake.getJobFinishedAndNextBuildMutex.RUnlock()
 	fake.getJobBuildsMutex.RLock()
@@ -3349,10 +3681,14 @@ func (fake *FakePipelineDB) Invocations() map[string][][]interface{} {
 	defer fake.saveInputMutex.RUnlock()
 	fake.saveOutputMutex.RLock()
 	defer fake.saveOutputMutex.RUnlock()
+	fake.saveOutputsMutex.RLock()
+	defer fake.saveOutputsMutex.RUnlock()
 	fake.getBuildsWithVersionAsInputMutex.RLock()
 	defer fake.getBuildsWithVersionAsInputMutex.RUnlock()
 	fake.getBuildsWithVersionAsOutputMutex.RLock()
 	defer fake.getBuildsWithVersionAsOutputMutex.RUnlock()
+	fake.getBuildsForResourceVersionMutex.RLock()
+	defer fake.getBuildsForResourceVersionMutex.RUnlock()
 	fake.getDashboardMutex.RLock()
 	defer fake.getDashboardMutex.RUnlock()
 	fake.exposeMutex.RLock()
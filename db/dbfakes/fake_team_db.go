@@ -24,6 +24,21 @@ type FakeTeamDB struct {
 		result2 bool
 		result3 error
 	}
+	GetPipelineByNameIgnoringCaseStub        func(pipelineName string) (db.SavedPipeline, bool, error)
+	getPipelineByNameIgnoringCaseMutex       sync.RWMutex
+	getPipelineByNameIgnoringCaseArgsForCall []struct {
+		pipelineName string
+	}
+	getPipelineByNameIgnoringCaseReturns struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}
+	getPipelineByNameIgnoringCaseReturnsOnCall map[int]struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}
 	GetTeamStub        func() (db.SavedTeam, bool, error)
 	getTeamMutex       sync.RWMutex
 	getTeamArgsForCall []struct{}
@@ -141,6 +156,60 @@ func (fake *FakeTeamDB) GetPipelineByNameReturnsOnCall(i int, result1 db.SavedPi
 	}{result1, result2, result3}
 }
 
+func (fake *FakeTeamDB) GetPipelineByNameIgnoringCase(pipelineName string) (db.SavedPipeline, bool, error) {
+	fake.getPipelineByNameIgnoringCaseMutex.Lock()
+	ret, specificReturn := fake.getPipelineByNameIgnoringCaseReturnsOnCall[len(fake.getPipelineByNameIgnoringCaseArgsForCall)]
+	fake.getPipelineByNameIgnoringCaseArgsForCall = append(fake.getPipelineByNameIgnoringCaseArgsForCall, struct {
+		pipelineName string
+	}{pipelineName})
+	fake.recordInvocation("GetPipelineByNameIgnoringCase", []interface{}{pipelineName})
+	fake.getPipelineByNameIgnoringCaseMutex.Unlock()
+	if fake.GetPipelineByNameIgnoringCaseStub != nil {
+		return fake.GetPipelineByNameIgnoringCaseStub(pipelineName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getPipelineByNameIgnoringCaseReturns.result1, fake.getPipelineByNameIgnoringCaseReturns.result2, fake.getPipelineByNameIgnoringCaseReturns.result3
+}
+
+func (fake *FakeTeamDB) GetPipelineByNameIgnoringCaseCallCount() int {
+	fake.getPipelineByNameIgnoringCaseMutex.RLock()
+	defer fake.getPipelineByNameIgnoringCaseMutex.RUnlock()
+	return len(fake.getPipelineByNameIgnoringCaseArgsForCall)
+}
+
+func (fake *FakeTeamDB) GetPipelineByNameIgnoringCaseArgsForCall(i int) string {
+	fake.getPipelineByNameIgnoringCaseMutex.RLock()
+	defer fake.getPipelineByNameIgnoringCaseMutex.RUnlock()
+	return fake.getPipelineByNameIgnoringCaseArgsForCall[i].pipelineName
+}
+
+func (fake *FakeTeamDB) GetPipelineByNameIgnoringCaseReturns(result1 db.SavedPipeline, result2 bool, result3 error) {
+	fake.GetPipelineByNameIgnoringCaseStub = nil
+	fake.getPipelineByNameIgnoringCaseReturns = struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeamDB) GetPipelineByNameIgnoringCaseReturnsOnCall(i int, result1 db.SavedPipeline, result2 bool, result3 error) {
+	fake.GetPipelineByNameIgnoringCaseStub = nil
+	if fake.getPipelineByNameIgnoringCaseReturnsOnCall == nil {
+		fake.getPipelineByNameIgnoringCaseReturnsOnCall = make(map[int]struct {
+			result1 db.SavedPipeline
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getPipelineByNameIgnoringCaseReturnsOnCall[i] = struct {
+		result1 db.SavedPipeline
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeTeamDB) GetTeam() (db.SavedTeam, bool, error) {
 	fake.getTeamMutex.Lock()
 	ret, specificReturn := fake.getTeamReturnsOnCall[len(fake.getTeamArgsForCall)]
@@ -349,6 +418,8 @@ func (fake *FakeTeamDB) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.getPipelineByNameMutex.RLock()
 	defer fake.getPipelineByNameMutex.RUnlock()
+	fake.getPipelineByNameIgnoringCaseMutex.RLock()
+	defer fake.getPipelineByNameIgnoringCaseMutex.RUnlock()
 	fake.getTeamMutex.RLock()
 	defer fake.getTeamMutex.RUnlock()
 	fake.getConfigMutex.RLock()
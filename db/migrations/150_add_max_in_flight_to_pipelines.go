@@ -0,0 +1,11 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddMaxInFlightToPipelines(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE pipelines
+		ADD COLUMN max_in_flight integer NOT NULL DEFAULT 0;
+`)
+	return err
+}
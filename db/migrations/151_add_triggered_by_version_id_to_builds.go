@@ -0,0 +1,11 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddTriggeredByVersionIDToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN triggered_by_version_id integer REFERENCES versioned_resources (id) ON DELETE SET NULL;
+`)
+	return err
+}
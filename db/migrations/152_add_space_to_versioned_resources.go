@@ -0,0 +1,30 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddSpaceToVersionedResources(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE versioned_resources
+		ADD COLUMN space text NOT NULL DEFAULT '';
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DROP INDEX versioned_resources_resource_id_type_version
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE UNIQUE INDEX versioned_resources_resource_id_type_version_space
+		ON versioned_resources (resource_id, type, version, space)
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddBuildMetadata(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_metadata (
+			id serial PRIMARY KEY,
+			build_id integer REFERENCES builds (id) ON DELETE CASCADE NOT NULL,
+			name text NOT NULL,
+			value text NOT NULL,
+			UNIQUE (build_id, name)
+		)
+`)
+	return err
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddPipelineVars(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE pipeline_vars (
+			id serial PRIMARY KEY,
+			pipeline_id integer REFERENCES pipelines (id) ON DELETE CASCADE NOT NULL,
+			key text NOT NULL,
+			value text NOT NULL,
+			UNIQUE (pipeline_id, key)
+		)
+`)
+	return err
+}
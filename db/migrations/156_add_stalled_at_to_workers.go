@@ -0,0 +1,22 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddStalledAtToWorkers(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE workers
+		ADD COLUMN stalled_at timestamp;
+`)
+	if err != nil {
+		return err
+	}
+
+	// workers that were already stalled start their grace period now, rather
+	// than never being reaped
+	_, err = tx.Exec(`
+		UPDATE workers
+		SET stalled_at = NOW()
+		WHERE state = 'stalled'
+`)
+	return err
+}
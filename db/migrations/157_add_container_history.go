@@ -0,0 +1,24 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddContainerHistory(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE container_history (
+			id serial PRIMARY KEY,
+			handle text NOT NULL,
+			worker_name text NOT NULL,
+			build_id integer REFERENCES builds (id) ON DELETE CASCADE NOT NULL,
+			plan_id text NOT NULL,
+			created_at timestamp NOT NULL DEFAULT NOW()
+		)
+`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX container_history_build_id ON container_history (build_id)
+`)
+	return err
+}
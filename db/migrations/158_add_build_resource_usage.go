@@ -0,0 +1,23 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddBuildResourceUsage(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE build_resource_usage (
+			id serial PRIMARY KEY,
+			build_id integer REFERENCES builds (id) ON DELETE CASCADE NOT NULL,
+			cpu_seconds double precision NOT NULL DEFAULT 0,
+			memory_byte_seconds double precision NOT NULL DEFAULT 0,
+			measured boolean NOT NULL DEFAULT true
+		)
+`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_resource_usage_build_id ON build_resource_usage (build_id)
+`)
+	return err
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddBuildEventCompactions(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN event_compactions integer NOT NULL DEFAULT 0
+`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE build_event_compactions (
+			build_id integer REFERENCES builds (id) ON DELETE CASCADE NOT NULL,
+			compaction integer NOT NULL,
+			old_offset integer NOT NULL,
+			new_offset integer NOT NULL
+		)
+`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE INDEX build_event_compactions_build_id_compaction ON build_event_compactions (build_id, compaction, old_offset)
+`)
+	return err
+}
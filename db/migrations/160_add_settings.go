@@ -0,0 +1,13 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddSettings(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE settings (
+			name text PRIMARY KEY,
+			value text NOT NULL
+		)
+`)
+	return err
+}
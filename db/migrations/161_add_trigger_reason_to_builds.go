@@ -0,0 +1,28 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddTriggerReasonToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds ADD COLUMN trigger_reason text
+`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE builds
+		SET trigger_reason = CASE
+			WHEN manually_triggered OR job_id IS NULL THEN 'manual'
+			ELSE 'scheduler'
+		END
+`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE builds ALTER COLUMN trigger_reason SET NOT NULL
+`)
+	return err
+}
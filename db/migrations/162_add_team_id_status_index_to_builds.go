@@ -0,0 +1,40 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+// AddTeamIDStatusIndexToBuilds speeds up looking up a team's active builds.
+// builds (team_id) is already indexed by AddIndexesToABunchMoreStuff.
+//
+// Migrations run in a transaction, so the index can't be created
+// CONCURRENTLY; building it locks builds against writes for the duration.
+func AddTeamIDStatusIndexToBuilds(tx migration.LimitedTx) error {
+	for _, index := range []struct {
+		name    string
+		columns string
+	}{
+		{"builds_team_id", "team_id"},
+		{"builds_team_id_status", "team_id, status"},
+	} {
+		var exists bool
+		err := tx.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1 FROM pg_indexes
+				WHERE tablename = 'builds' AND indexname = $1
+			)
+		`, index.name).Scan(&exists)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			continue
+		}
+
+		_, err = tx.Exec(`CREATE INDEX ` + index.name + ` ON builds (` + index.columns + `)`)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddLastUpdatedToPipelines(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE pipelines ADD COLUMN last_updated timestamp with time zone
+`)
+	return err
+}
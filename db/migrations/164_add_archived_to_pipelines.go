@@ -0,0 +1,10 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddArchivedToPipelines(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE pipelines ADD COLUMN archived boolean NOT NULL DEFAULT false
+`)
+	return err
+}
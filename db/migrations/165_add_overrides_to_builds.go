@@ -0,0 +1,12 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func AddOverridesToBuilds(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE builds
+		ADD COLUMN overrides text,
+		ADD COLUMN inputs_pinned boolean NOT NULL DEFAULT false
+`)
+	return err
+}
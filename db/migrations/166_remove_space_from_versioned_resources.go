@@ -0,0 +1,38 @@
+package migrations
+
+import "github.com/concourse/atc/dbng/migration"
+
+func RemoveSpaceFromVersionedResources(tx migration.LimitedTx) error {
+	_, err := tx.Exec(`
+		DELETE FROM versioned_resources
+		WHERE space != ''
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DROP INDEX versioned_resources_resource_id_type_version_space
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		ALTER TABLE versioned_resources
+		DROP COLUMN space
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		CREATE UNIQUE INDEX versioned_resources_resource_id_type_version
+		ON versioned_resources (resource_id, type, version)
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -158,4 +158,21 @@ var Migrations = []migration.Migrator{
 	AddAuthToTeams,
 	RemoveCertificatesPathToWorkers,
 	AddVersionToWorkers,
+	AddMaxInFlightToPipelines,
+	AddTriggeredByVersionIDToBuilds,
+	AddSpaceToVersionedResources,
+	AddCreateTimeToBuilds,
+	AddBuildMetadata,
+	AddPipelineVars,
+	AddStalledAtToWorkers,
+	AddContainerHistory,
+	AddBuildResourceUsage,
+	AddBuildEventCompactions,
+	AddSettings,
+	AddTriggerReasonToBuilds,
+	AddTeamIDStatusIndexToBuilds,
+	AddLastUpdatedToPipelines,
+	AddArchivedToPipelines,
+	AddOverridesToBuilds,
+	RemoveSpaceFromVersionedResources,
 }
@@ -1,6 +1,10 @@
 package db
 
-import "github.com/concourse/atc"
+import (
+	"time"
+
+	"github.com/concourse/atc"
+)
 
 type Pipeline struct {
 	Name    string
@@ -12,8 +16,12 @@ type SavedPipeline struct {
 	ID       int
 	Paused   bool
 	Public   bool
+	Archived bool
 	TeamID   int
 	TeamName string
 
+	LastUpdated time.Time
+	MaxInFlight int
+
 	Pipeline
 }
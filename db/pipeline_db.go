@@ -23,6 +23,7 @@ type PipelineDB interface {
 	ScopedName(string) string
 	TeamID() int
 	Config() atc.Config
+	MaxInFlight() int
 	ConfigVersion() ConfigVersion
 
 	Reload() (bool, error)
@@ -64,6 +65,8 @@ type PipelineDB interface {
 	DeleteNextInputMapping(jobName string) error
 	GetRunningBuildsBySerialGroup(jobName string, serialGroups []string) ([]Build, error)
 	GetNextPendingBuildBySerialGroup(jobName string, serialGroups []string) (Build, bool, error)
+	GetNextEligibleBuildForSerialGroup(serialGroup string) (Build, bool, error)
+	CountRunningBuildsForPipeline(pipelineID int) (int, error)
 	GetJobFinishedAndNextBuild(job string) (Build, Build, error)
 	GetJobBuilds(job string, page Page) ([]Build, Pagination, error)
 	GetAllJobBuilds(job string) ([]Build, error)
@@ -76,8 +79,10 @@ type PipelineDB interface {
 	UpdateBuildToScheduled(buildID int) (bool, error)
 	SaveInput(buildID int, input BuildInput) (SavedVersionedResource, error)
 	SaveOutput(buildID int, vr VersionedResource, explicit bool) (SavedVersionedResource, error)
+	SaveOutputs(buildID int, vrs []VersionedResource, explicit bool) ([]SavedVersionedResource, error)
 	GetBuildsWithVersionAsInput(versionedResourceID int) ([]Build, error)
 	GetBuildsWithVersionAsOutput(versionedResourceID int) ([]Build, error)
+	GetBuildsForResourceVersion(resourceName string, version atc.Version) (ResourceVersionBuilds, bool, error)
 
 	GetDashboard() (Dashboard, atc.GroupConfigs, error)
 
@@ -147,6 +152,10 @@ func (pdb *pipelineDB) Config() atc.Config {
 	return pdb.SavedPipeline.Config
 }
 
+func (pdb *pipelineDB) MaxInFlight() int {
+	return pdb.SavedPipeline.MaxInFlight
+}
+
 func (pdb *pipelineDB) ConfigVersion() ConfigVersion {
 	return pdb.SavedPipeline.Version
 }
@@ -574,7 +583,10 @@ func (pdb *pipelineDB) updatePaused(resource string, pause bool) error {
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update resource paused",
+		}
 	}
 
 	return tx.Commit()
@@ -688,7 +700,10 @@ func (pdb *pipelineDB) toggleVersionedResource(versionedResourceID int, enable b
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update versioned resource enabled",
+		}
 	}
 
 	return nil
@@ -1008,8 +1023,8 @@ func (pdb *pipelineDB) CreateJobBuild(jobName string) (Build, error) {
 	// We had to resort to sub-selects here because you can't paramaterize a
 	// RETURNING statement in lib/pq... sorry
 	build, _, err := pdb.buildFactory.ScanBuild(tx.QueryRow(`
-		INSERT INTO builds (name, job_id, team_id, status, manually_triggered)
-		VALUES ($1, $2, $3, 'pending', TRUE)
+		INSERT INTO builds (name, job_id, team_id, status, manually_triggered, trigger_reason)
+		VALUES ($1, $2, $3, 'pending', TRUE, 'manual')
 		RETURNING `+buildColumns+`,
 			(SELECT name FROM jobs WHERE id = $2),
 			(SELECT id FROM pipelines WHERE id = $4),
@@ -1107,6 +1122,90 @@ func (pdb *pipelineDB) GetBuildsWithVersionAsOutput(versionedResourceID int) ([]
 	return builds, err
 }
 
+type ResourceVersionBuilds struct {
+	Inputs  []Build
+	Outputs []Build
+}
+
+// GetBuildsForResourceVersion returns the builds that used the version of the
+// resource as an input or produced it as an output.
+func (pdb *pipelineDB) GetBuildsForResourceVersion(resourceName string, version atc.Version) (ResourceVersionBuilds, bool, error) {
+	versionJSON, err := json.Marshal(version)
+	if err != nil {
+		return ResourceVersionBuilds{}, false, err
+	}
+
+	var found bool
+	err = pdb.conn.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM versioned_resources v
+			JOIN resources r ON r.id = v.resource_id
+			WHERE v.version = $1
+				AND r.name = $2
+				AND r.pipeline_id = $3
+		)
+	`, string(versionJSON), resourceName, pdb.ID).Scan(&found)
+	if err != nil {
+		return ResourceVersionBuilds{}, false, err
+	}
+
+	if !found {
+		return ResourceVersionBuilds{}, false, nil
+	}
+
+	inputs, err := pdb.getBuildsWithVersion("build_inputs", resourceName, string(versionJSON))
+	if err != nil {
+		return ResourceVersionBuilds{}, false, err
+	}
+
+	outputs, err := pdb.getBuildsWithVersion("build_outputs", resourceName, string(versionJSON))
+	if err != nil {
+		return ResourceVersionBuilds{}, false, err
+	}
+
+	return ResourceVersionBuilds{
+		Inputs:  inputs,
+		Outputs: outputs,
+	}, true, nil
+}
+
+func (pdb *pipelineDB) getBuildsWithVersion(table string, resourceName string, versionJSON string) ([]Build, error) {
+	rows, err := pdb.conn.Query(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		WHERE b.id IN (
+			SELECT bv.build_id
+			FROM `+table+` bv
+			JOIN versioned_resources v ON v.id = bv.versioned_resource_id
+			JOIN resources r ON r.id = v.resource_id
+			WHERE v.version = $1
+				AND r.name = $2
+				AND r.pipeline_id = $3
+		)
+		ORDER BY b.id ASC
+	`, versionJSON, resourceName, pdb.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	builds := []Build{}
+	for rows.Next() {
+		build, _, err := pdb.buildFactory.ScanBuild(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		builds = append(builds, build)
+	}
+
+	return builds, rows.Err()
+}
+
 func (pdb *pipelineDB) SaveInput(buildID int, input BuildInput) (SavedVersionedResource, error) {
 	tx, err := pdb.conn.Begin()
 	if err != nil {
@@ -1172,6 +1271,44 @@ func (pdb *pipelineDB) SaveOutput(buildID int, vr VersionedResource, explicit bo
 
 	defer tx.Rollback()
 
+	svr, err := pdb.saveOutput(tx, buildID, vr, explicit)
+	if err != nil {
+		return SavedVersionedResource{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return SavedVersionedResource{}, err
+	}
+
+	return svr, nil
+}
+
+func (pdb *pipelineDB) SaveOutputs(buildID int, vrs []VersionedResource, explicit bool) ([]SavedVersionedResource, error) {
+	tx, err := pdb.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	svrs := make([]SavedVersionedResource, len(vrs))
+	for i, vr := range vrs {
+		svrs[i], err = pdb.saveOutput(tx, buildID, vr, explicit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return svrs, nil
+}
+
+func (pdb *pipelineDB) saveOutput(tx Tx, buildID int, vr VersionedResource, explicit bool) (SavedVersionedResource, error) {
 	savedResource, found, err := pdb.getResource(tx, vr.Resource)
 	if err != nil {
 		return SavedVersionedResource{}, err
@@ -1206,11 +1343,6 @@ func (pdb *pipelineDB) SaveOutput(buildID int, vr VersionedResource, explicit bo
 		return SavedVersionedResource{}, err
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		return SavedVersionedResource{}, err
-	}
-
 	return svr, nil
 }
 
@@ -1275,6 +1407,39 @@ func (pdb *pipelineDB) GetNextPendingBuildBySerialGroup(jobName string, serialGr
 	`, args...))
 }
 
+// GetNextEligibleBuildForSerialGroup returns the oldest pending build of any
+// job in the serial group, unless a build of one of those jobs is already
+// running or scheduled, in which case nothing may proceed.
+func (pdb *pipelineDB) GetNextEligibleBuildForSerialGroup(serialGroup string) (Build, bool, error) {
+	return pdb.buildFactory.ScanBuild(pdb.conn.QueryRow(`
+		SELECT `+qualifiedBuildColumns+`
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		INNER JOIN pipelines p ON j.pipeline_id = p.id
+		INNER JOIN teams t ON b.team_id = t.id
+		INNER JOIN jobs_serial_groups jsg ON j.id = jsg.job_id
+				AND jsg.serial_group = $2
+		WHERE b.status = 'pending'
+			AND j.inputs_determined = true
+			AND j.pipeline_id = $1
+			AND NOT EXISTS (
+				SELECT 1
+				FROM builds rb
+				INNER JOIN jobs rj ON rb.job_id = rj.id
+				INNER JOIN jobs_serial_groups rjsg ON rj.id = rjsg.job_id
+						AND rjsg.serial_group = $2
+				WHERE (
+						rb.status = 'started'
+						OR
+						(rb.scheduled = true AND rb.status = 'pending')
+					)
+					AND rj.pipeline_id = $1
+			)
+		ORDER BY b.id ASC
+		LIMIT 1
+	`, pdb.ID, serialGroup))
+}
+
 func (pdb *pipelineDB) GetRunningBuildsBySerialGroup(jobName string, serialGroups []string) ([]Build, error) {
 	pdb.updateSerialGroupsForJob(jobName, serialGroups)
 
@@ -1322,6 +1487,26 @@ func (pdb *pipelineDB) GetRunningBuildsBySerialGroup(jobName string, serialGroup
 	return bs, nil
 }
 
+func (pdb *pipelineDB) CountRunningBuildsForPipeline(pipelineID int) (int, error) {
+	var count int
+	err := pdb.conn.QueryRow(`
+		SELECT COUNT(1)
+		FROM builds b
+		INNER JOIN jobs j ON b.job_id = j.id
+		WHERE (
+				b.status = 'started'
+				OR
+				(b.scheduled = true AND b.status = 'pending')
+			)
+			AND j.pipeline_id = $1
+	`, pipelineID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (pdb *pipelineDB) IsPaused() (bool, error) {
 	var paused bool
 
@@ -1617,7 +1802,10 @@ func (pdb *pipelineDB) SetMaxInFlightReached(jobName string, reached bool) error
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update job max in flight reached",
+		}
 	}
 
 	return nil
@@ -1659,7 +1847,10 @@ func (pdb *pipelineDB) UpdateFirstLoggedBuildID(job string, newFirstLoggedBuildI
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update job first logged build id",
+		}
 	}
 
 	return tx.Commit()
@@ -1693,7 +1884,10 @@ func (pdb *pipelineDB) updatePausedJob(job string, pause bool) error {
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update job paused",
+		}
 	}
 
 	return tx.Commit()
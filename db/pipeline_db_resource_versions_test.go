@@ -336,6 +336,13 @@ var _ = Describe("Resource History", func() {
 		})
 	})
 
+	Context("DisableVersionedResource", func() {
+		It("names the failed update in the error when the version does not exist", func() {
+			err := pipelineDB.DisableVersionedResource(42)
+			Expect(err).To(MatchError("update versioned resource enabled: expected 1 row to be updated; got 0"))
+		})
+	})
+
 	Context("GetBuildsWithVersionAsInput", func() {
 		var savedVersionedResource db.SavedVersionedResource
 		var expectedBuilds []db.Build
@@ -469,4 +476,101 @@ var _ = Describe("Resource History", func() {
 			Expect(builds).To(Equal([]db.Build{}))
 		})
 	})
+
+	Context("GetBuildsForResourceVersion", func() {
+		var (
+			firstInputBuild   db.Build
+			outputBuild       db.Build
+			secondInputBuild  db.Build
+			otherVersionBuild db.Build
+		)
+
+		versionedResource := func(version string) db.VersionedResource {
+			return db.VersionedResource{
+				Resource:   "some-resource",
+				Type:       "some-type",
+				Version:    db.Version{"version": version},
+				PipelineID: savedPipeline.ID,
+			}
+		}
+
+		buildIDs := func(builds []db.Build) []int {
+			ids := []int{}
+			for _, build := range builds {
+				ids = append(ids, build.ID())
+			}
+			return ids
+		}
+
+		BeforeEach(func() {
+			var err error
+			firstInputBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			outputBuild, err = pipelineDB.CreateJobBuild("some-other-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			secondInputBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			otherVersionBuild, err = pipelineDB.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveOutput(outputBuild.ID(), versionedResource("v1"), true)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveInput(secondInputBuild.ID(), db.BuildInput{
+				Name:              "some-input",
+				VersionedResource: versionedResource("v1"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveInput(firstInputBuild.ID(), db.BuildInput{
+				Name:              "some-input",
+				VersionedResource: versionedResource("v1"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveInput(firstInputBuild.ID(), db.BuildInput{
+				Name:              "some-other-input",
+				VersionedResource: versionedResource("v1"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = pipelineDB.SaveInput(otherVersionBuild.ID(), db.BuildInput{
+				Name:              "some-input",
+				VersionedResource: versionedResource("v2"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("partitions the builds that used the version into inputs and outputs, ordered by id", func() {
+			builds, found, err := pipelineDB.GetBuildsForResourceVersion("some-resource", atc.Version{"version": "v1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(buildIDs(builds.Inputs)).To(Equal([]int{firstInputBuild.ID(), secondInputBuild.ID()}))
+			Expect(buildIDs(builds.Outputs)).To(Equal([]int{outputBuild.ID()}))
+		})
+
+		It("returns empty slices when the version was not used", func() {
+			err := pipelineDB.SaveResourceVersions(atc.ResourceConfig{
+				Name: "some-resource",
+				Type: "some-type",
+			}, []atc.Version{{"version": "v3"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, found, err := pipelineDB.GetBuildsForResourceVersion("some-resource", atc.Version{"version": "v3"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(builds.Inputs).To(BeEmpty())
+			Expect(builds.Outputs).To(BeEmpty())
+		})
+
+		It("returns false when the version does not exist", func() {
+			_, found, err := pipelineDB.GetBuildsForResourceVersion("some-resource", atc.Version{"version": "bogus"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
 })
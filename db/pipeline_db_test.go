@@ -1,6 +1,7 @@
 package db_test
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/concourse/atc"
@@ -293,6 +294,22 @@ var _ = Describe("PipelineDB", func() {
 			Expect(otherPipelineDB.Config()).To(Equal(updatedConfig))
 			Expect(otherPipelineDB.ConfigVersion()).NotTo(Equal(0))
 		})
+
+		It("returns the saved max in flight", func() {
+			Expect(pipelineDB.MaxInFlight()).To(BeZero())
+
+			updatedConfig := pipelineConfig
+			updatedConfig.MaxInFlight = 3
+
+			_, _, err := teamDB.SaveConfigToBeDeprecated("a-pipeline-name", updatedConfig, pipelineDB.ConfigVersion(), db.PipelineNoChange)
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := pipelineDB.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(pipelineDB.MaxInFlight()).To(Equal(3))
+		})
 	})
 
 	Context("Resources", func() {
@@ -720,6 +737,79 @@ var _ = Describe("PipelineDB", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("resource 'unknown-resource' not found"))
 			})
+
+			It("can save many outputs at once, in order", func() {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				svrs, err := pipelineDB.SaveOutputs(build.ID(), []db.VersionedResource{vr1, vr2}, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(svrs).To(HaveLen(2))
+				Expect(svrs[0].Resource).To(Equal("some-resource"))
+				Expect(svrs[0].Version).To(Equal(vr1.Version))
+				Expect(svrs[1].Resource).To(Equal("some-other-resource"))
+				Expect(svrs[1].Version).To(Equal(vr2.Version))
+
+				_, outputs, err := build.GetResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(outputs).To(ConsistOf([]db.BuildOutput{
+					{VersionedResource: vr1},
+					{VersionedResource: vr2},
+				}))
+			})
+
+			It("saves none of the outputs if any of them fails to save", func() {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				unknown := db.VersionedResource{
+					PipelineID: savedPipeline.ID,
+					Resource:   "unknown-resource",
+					Type:       "some-type",
+					Version:    db.Version{"ver": "3"},
+				}
+
+				_, err = pipelineDB.SaveOutputs(build.ID(), []db.VersionedResource{vr1, unknown, vr2}, true)
+				Expect(err).To(Equal(db.ResourceNotFoundError{Name: "unknown-resource"}))
+
+				_, outputs, err := build.GetResources()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(outputs).To(BeEmpty())
+
+				_, found, err := pipelineDB.GetVersionedResourceByVersion(atc.Version{"ver": "1"}, "some-resource")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			Measure("saving outputs in bulk compared to one at a time", func(b Benchmarker) {
+				build, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				outputs := func(prefix string) []db.VersionedResource {
+					vrs := make([]db.VersionedResource, 50)
+					for i := range vrs {
+						vrs[i] = db.VersionedResource{
+							PipelineID: savedPipeline.ID,
+							Resource:   "some-resource",
+							Type:       "some-type",
+							Version:    db.Version{"ver": fmt.Sprintf("%s-%d", prefix, i)},
+						}
+					}
+					return vrs
+				}
+
+				b.Time("one at a time", func() {
+					for _, vr := range outputs("single") {
+						_, err := pipelineDB.SaveOutput(build.ID(), vr, true)
+						Expect(err).NotTo(HaveOccurred())
+					}
+				})
+
+				b.Time("in bulk", func() {
+					_, err := pipelineDB.SaveOutputs(build.ID(), outputs("bulk"), true)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			}, 5)
 		})
 
 		Describe("pausing and unpausing jobs", func() {
@@ -908,6 +998,80 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("GetNextEligibleBuildForSerialGroup", func() {
+			var someJobBuild db.Build
+			var otherJobBuild db.Build
+
+			BeforeEach(func() {
+				var err error
+				someJobBuild, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				otherJobBuild, err = pipelineDB.CreateJobBuild("other-serial-group-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				err = pipelineDB.SaveNextInputMapping(nil, "some-job")
+				Expect(err).NotTo(HaveOccurred())
+				err = pipelineDB.SaveNextInputMapping(nil, "other-serial-group-job")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the oldest pending build across all jobs in the group", func() {
+				build, found, err := pipelineDB.GetNextEligibleBuildForSerialGroup("serial-group")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.ID()).To(Equal(someJobBuild.ID()))
+			})
+
+			It("only considers jobs in the given group", func() {
+				build, found, err := pipelineDB.GetNextEligibleBuildForSerialGroup("really-different-group")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.ID()).To(Equal(otherJobBuild.ID()))
+
+				_, found, err = pipelineDB.GetNextEligibleBuildForSerialGroup("different-serial-group")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			Context("when a build of another job in the group is running", func() {
+				BeforeEach(func() {
+					started, err := someJobBuild.Start("", "")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(started).To(BeTrue())
+				})
+
+				It("does not let any other build in the group proceed", func() {
+					_, found, err := pipelineDB.GetNextEligibleBuildForSerialGroup("serial-group")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+
+				It("lets the next build proceed once it finishes", func() {
+					Expect(someJobBuild.Finish(db.StatusSucceeded)).To(Succeed())
+
+					build, found, err := pipelineDB.GetNextEligibleBuildForSerialGroup("serial-group")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(build.ID()).To(Equal(otherJobBuild.ID()))
+				})
+			})
+
+			Context("when a build of another job in the group is scheduled", func() {
+				BeforeEach(func() {
+					scheduled, err := pipelineDB.UpdateBuildToScheduled(someJobBuild.ID())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(scheduled).To(BeTrue())
+				})
+
+				It("does not let any other build in the group proceed", func() {
+					_, found, err := pipelineDB.GetNextEligibleBuildForSerialGroup("serial-group")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
 		Describe("GetRunningBuildsBySerialGroup", func() {
 			Describe("same job", func() {
 				var startedBuild, scheduledBuild db.Build
@@ -991,6 +1155,53 @@ var _ = Describe("PipelineDB", func() {
 			})
 		})
 
+		Describe("CountRunningBuildsForPipeline", func() {
+			var startedBuild db.Build
+
+			BeforeEach(func() {
+				var err error
+				_, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				startedBuild, err = pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = startedBuild.Start("", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				scheduledBuild, err := pipelineDB.CreateJobBuild("some-other-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				scheduled, err := pipelineDB.UpdateBuildToScheduled(scheduledBuild.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scheduled).To(BeTrue())
+
+				finishedBuild, err := pipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				err = finishedBuild.Finish(db.StatusSucceeded)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherPipelineBuild, err := otherPipelineDB.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+				_, err = otherPipelineBuild.Start("", "")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("counts the started and scheduled builds of every job in the pipeline", func() {
+				count, err := pipelineDB.CountRunningBuildsForPipeline(pipelineDB.GetPipelineID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(2))
+			})
+
+			It("stops counting a build once it finishes", func() {
+				err := startedBuild.Finish(db.StatusSucceeded)
+				Expect(err).NotTo(HaveOccurred())
+
+				count, err := pipelineDB.CountRunningBuildsForPipeline(pipelineDB.GetPipelineID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(1))
+			})
+		})
+
 		It("can report a job's latest running and finished builds", func() {
 			finished, next, err := pipelineDB.GetJobFinishedAndNextBuild("some-job")
 			Expect(err).NotTo(HaveOccurred())
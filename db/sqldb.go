@@ -30,10 +30,18 @@ func NewSQL(
 
 type nonOneRowAffectedError struct {
 	RowsAffected int64
+
+	// Operation optionally names the statement that failed, e.g. "update job
+	// paused".
+	Operation string
 }
 
 func (err nonOneRowAffectedError) Error() string {
-	return fmt.Sprintf("expected 1 row to be updated; got %d", err.RowsAffected)
+	if err.Operation == "" {
+		return fmt.Sprintf("expected 1 row to be updated; got %d", err.RowsAffected)
+	}
+
+	return fmt.Sprintf("%s: expected 1 row to be updated; got %d", err.Operation, err.RowsAffected)
 }
 
 type scannable interface {
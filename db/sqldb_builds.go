@@ -3,6 +3,7 @@ package db
 import (
 	"strconv"
 	"strings"
+	"time"
 )
 
 func (db *SQLDB) DeleteBuildEventsByBuildIDs(buildIDs []int) error {
@@ -47,3 +48,77 @@ func (db *SQLDB) DeleteBuildEventsByBuildIDs(buildIDs []int) error {
 	err = tx.Commit()
 	return err
 }
+
+// BuildPruningPolicy describes which completed builds PruneBuilds may delete.
+// A zero value in either field disables that rule; a build matching any
+// enabled rule is pruned.
+type BuildPruningPolicy struct {
+	// OlderThan prunes builds that finished longer ago than this.
+	OlderThan time.Duration
+
+	// KeepLatest prunes job builds beyond the most recent N completed builds
+	// of their job. A job's latest successful build is always kept.
+	KeepLatest int
+}
+
+func (db *SQLDB) PruneBuilds(policy BuildPruningPolicy) (int, error) {
+	conditions := []string{}
+	params := []interface{}{}
+
+	if policy.KeepLatest > 0 {
+		params = append(params, policy.KeepLatest)
+		conditions = append(conditions, "(job_id IS NOT NULL AND position > $"+strconv.Itoa(len(params))+")")
+	}
+
+	if policy.OlderThan > 0 {
+		params = append(params, time.Now().Add(-policy.OlderThan))
+		conditions = append(conditions, "finished_at < $"+strconv.Itoa(len(params)))
+	}
+
+	if len(conditions) == 0 {
+		return 0, nil
+	}
+
+	// only completed builds are ranked, so a job's pending or running builds
+	// never push its latest finished build out of the ones kept
+	//
+	// a job's latest successful build is never pruned, as its inputs and
+	// outputs are what `passed` constraints of downstream jobs resolve against
+	//
+	// builds and their events are deleted in one statement so that both see
+	// the same snapshot; everything else referencing builds cascades
+	var pruned int
+	err := db.conn.QueryRow(`
+		WITH ranked AS (
+			SELECT id, job_id, completed, COALESCE(end_time, start_time) AS finished_at,
+				row_number() OVER (PARTITION BY job_id ORDER BY id DESC) AS position
+			FROM builds
+			WHERE completed
+		), latest_succeeded AS (
+			SELECT MAX(id) AS id
+			FROM builds
+			WHERE job_id IS NOT NULL
+			AND status = 'succeeded'
+			GROUP BY job_id
+		), pruned AS (
+			DELETE FROM builds
+			WHERE id IN (
+				SELECT id
+				FROM ranked
+				WHERE (job_id IS NULL OR position > 1)
+				AND id NOT IN (SELECT id FROM latest_succeeded)
+				AND (`+strings.Join(conditions, " OR ")+`)
+			)
+			RETURNING id
+		), pruned_events AS (
+			DELETE FROM build_events
+			WHERE build_id IN (SELECT id FROM pruned)
+		)
+		SELECT COUNT(*) FROM pruned
+	`, params...).Scan(&pruned)
+	if err != nil {
+		return 0, err
+	}
+
+	return pruned, nil
+}
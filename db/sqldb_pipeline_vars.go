@@ -0,0 +1,125 @@
+package db
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/concourse/atc"
+)
+
+// configRefPattern matches both ((var:key)) references to pipeline vars and
+// ((name)) references to credentials.
+var configRefPattern = regexp.MustCompile(`\(\((var:)?([^()]+)\)\)`)
+
+const redactedConfigRef = "((redacted))"
+
+func (db *SQLDB) SetPipelineVar(pipelineID int, key string, value string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE pipeline_vars
+		SET value = $3
+		WHERE pipeline_id = $1
+		AND key = $2
+	`, pipelineID, key, value)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO pipeline_vars (pipeline_id, key, value)
+			VALUES ($1, $2, $3)
+		`, pipelineID, key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (db *SQLDB) GetPipelineVars(pipelineID int) (map[string]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT key, value
+		FROM pipeline_vars
+		WHERE pipeline_id = $1
+	`, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	vars := map[string]string{}
+	for rows.Next() {
+		var key, value string
+		err := rows.Scan(&key, &value)
+		if err != nil {
+			return nil, err
+		}
+
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// GetEffectiveConfig returns the pipeline's config with its ((var:key))
+// references replaced by the pipeline's vars. References to vars that are not
+// set are left as they are. Any other ((name)) reference is to a credential,
+// which is resolved outside of the ATC, and is redacted.
+func (db *SQLDB) GetEffectiveConfig(pipelineID int) (atc.Config, error) {
+	var configBlob []byte
+	err := db.conn.QueryRow(`
+		SELECT config
+		FROM pipelines
+		WHERE id = $1
+	`, pipelineID).Scan(&configBlob)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	vars, err := db.GetPipelineVars(pipelineID)
+	if err != nil {
+		return atc.Config{}, err
+	}
+
+	resolved := configRefPattern.ReplaceAllFunc(configBlob, func(ref []byte) []byte {
+		match := configRefPattern.FindSubmatch(ref)
+		if len(match[1]) == 0 {
+			return []byte(redactedConfigRef)
+		}
+
+		value, found := vars[string(match[2])]
+		if !found {
+			return ref
+		}
+
+		// the reference is within a JSON string, so escape the value as one
+		escaped, err := json.Marshal(value)
+		if err != nil {
+			return ref
+		}
+
+		return escaped[1 : len(escaped)-1]
+	})
+
+	var config atc.Config
+	err = json.Unmarshal(resolved, &config)
+	if err != nil {
+		return atc.Config{}, atc.MalformedConfigError{UnmarshalError: err}
+	}
+
+	return config, nil
+}
@@ -1,7 +1,22 @@
 package db
 
-const pipelineColumns = "p.id, p.name, p.config, p.version, p.paused, p.team_id, p.public, t.name as team_name"
-const unqualifiedPipelineColumns = "id, name, config, version, paused, team_id, public"
+import (
+	"sort"
+
+	"github.com/concourse/atc"
+)
+
+const pipelineColumns = "p.id, p.name, p.config, p.version, p.paused, p.team_id, p.public, p.archived, p.last_updated, p.max_in_flight, t.name as team_name"
+const unqualifiedPipelineColumns = "id, name, config, version, paused, team_id, public, archived, last_updated, max_in_flight"
+
+// ResourceTypeUsage is the number of pipelines and jobs referencing a
+// resource type, either through a resource, a custom resource type, or a
+// task's inline image_resource.
+type ResourceTypeUsage struct {
+	Type      string
+	Pipelines int
+	Jobs      int
+}
 
 func (db *SQLDB) GetAllPipelines() ([]SavedPipeline, error) {
 	rows, err := db.conn.Query(`
@@ -18,3 +33,157 @@ func (db *SQLDB) GetAllPipelines() ([]SavedPipeline, error) {
 
 	return scanPipelines(rows)
 }
+
+// GetAllActivePipelines is GetAllPipelines without the archived pipelines,
+// i.e. every pipeline that should be scheduled unless it's paused.
+func (db *SQLDB) GetAllActivePipelines() ([]SavedPipeline, error) {
+	rows, err := db.conn.Query(`
+		SELECT ` + pipelineColumns + `
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE NOT p.archived
+		ORDER BY ordering
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return scanPipelines(rows)
+}
+
+// PauseAllPipelines pauses every pipeline belonging to the team, returning
+// how many pipelines were not already paused.
+func (db *SQLDB) PauseAllPipelines(teamName string) (int, error) {
+	return db.setAllPipelinesPaused(teamName, true)
+}
+
+// UnpauseAllPipelines unpauses every pipeline belonging to the team,
+// returning how many pipelines were paused.
+func (db *SQLDB) UnpauseAllPipelines(teamName string) (int, error) {
+	return db.setAllPipelinesPaused(teamName, false)
+}
+
+func (db *SQLDB) setAllPipelinesPaused(teamName string, paused bool) (int, error) {
+	result, err := db.conn.Exec(`
+		UPDATE pipelines
+		SET paused = $1
+		WHERE paused != $1
+		AND team_id = (
+			SELECT id FROM teams WHERE LOWER(name) = LOWER($2)
+		)
+	`, paused, teamName)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (db *SQLDB) GetReferencedResourceTypes(teamID int) ([]ResourceTypeUsage, error) {
+	rows, err := db.conn.Query(`
+		SELECT `+pipelineColumns+`
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE p.team_id = $1
+		AND p.paused = false
+		ORDER BY ordering
+	`, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	pipelines, err := scanPipelines(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := map[string]*ResourceTypeUsage{}
+	usage := func(resourceType string) *ResourceTypeUsage {
+		u, found := usages[resourceType]
+		if !found {
+			u = &ResourceTypeUsage{Type: resourceType}
+			usages[resourceType] = u
+		}
+
+		return u
+	}
+
+	for _, pipeline := range pipelines {
+		config := pipeline.Config
+
+		pipelineTypes := map[string]bool{}
+
+		for _, resource := range config.Resources {
+			pipelineTypes[resource.Type] = true
+		}
+
+		for _, resourceType := range config.ResourceTypes {
+			pipelineTypes[resourceType.Type] = true
+		}
+
+		for _, job := range config.Jobs {
+			jobTypes := referencedJobResourceTypes(job, config.Resources)
+
+			for resourceType := range jobTypes {
+				usage(resourceType).Jobs++
+				pipelineTypes[resourceType] = true
+			}
+		}
+
+		for resourceType := range pipelineTypes {
+			usage(resourceType).Pipelines++
+		}
+	}
+
+	result := []ResourceTypeUsage{}
+	for _, u := range usages {
+		result = append(result, *u)
+	}
+
+	sort.Sort(resourceTypeUsagesByType(result))
+
+	return result, nil
+}
+
+func referencedJobResourceTypes(job atc.JobConfig, resources atc.ResourceConfigs) map[string]bool {
+	types := map[string]bool{}
+
+	for _, plan := range job.Plans() {
+		resourceName := plan.Resource
+		if resourceName == "" {
+			resourceName = plan.Get
+		}
+
+		if resourceName == "" {
+			resourceName = plan.Put
+		}
+
+		if resourceName != "" {
+			resource, found := resources.Lookup(resourceName)
+			if found {
+				types[resource.Type] = true
+			}
+		}
+
+		if plan.TaskConfig != nil && plan.TaskConfig.ImageResource != nil {
+			types[plan.TaskConfig.ImageResource.Type] = true
+		}
+	}
+
+	return types
+}
+
+type resourceTypeUsagesByType []ResourceTypeUsage
+
+func (usages resourceTypeUsagesByType) Len() int           { return len(usages) }
+func (usages resourceTypeUsagesByType) Swap(i, j int)      { usages[i], usages[j] = usages[j], usages[i] }
+func (usages resourceTypeUsagesByType) Less(i, j int) bool { return usages[i].Type < usages[j].Type }
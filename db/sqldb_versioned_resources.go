@@ -0,0 +1,99 @@
+package db
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// orphanedVersionedResourceCondition matches versions whose resource has been
+// removed from its pipeline's config and which no build used as an input or
+// produced as an output. Deleting referenced versions would cascade into the
+// build history, so they are never considered orphaned.
+const orphanedVersionedResourceCondition = `
+	r.active = false
+	AND NOT EXISTS (
+		SELECT 1 FROM build_inputs bi WHERE bi.versioned_resource_id = vr.id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM build_outputs bo WHERE bo.versioned_resource_id = vr.id
+	)
+`
+
+func (db *SQLDB) GetOrphanedVersionedResources(limit int) ([]SavedVersionedResource, error) {
+	rows, err := db.conn.Query(`
+		SELECT vr.id, vr.enabled, vr.version, vr.metadata, vr.type, r.name, r.pipeline_id, vr.modified_time, vr.check_order
+		FROM versioned_resources vr
+		INNER JOIN resources r ON r.id = vr.resource_id
+		WHERE `+orphanedVersionedResourceCondition+`
+		ORDER BY vr.id ASC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	versionedResources := []SavedVersionedResource{}
+
+	for rows.Next() {
+		var versionedResource SavedVersionedResource
+		var versionJSON []byte
+		var metadataJSON []byte
+		err = rows.Scan(&versionedResource.ID, &versionedResource.Enabled, &versionJSON, &metadataJSON, &versionedResource.Type, &versionedResource.Resource, &versionedResource.PipelineID, &versionedResource.ModifiedTime, &versionedResource.CheckOrder)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(versionJSON, &versionedResource.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(metadataJSON, &versionedResource.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		versionedResources = append(versionedResources, versionedResource)
+	}
+
+	return versionedResources, nil
+}
+
+// DeleteVersionedResources deletes the given versions, skipping any that are
+// not orphaned (e.g. because a build has started using them since they were
+// found). It returns the number of versions actually deleted.
+func (db *SQLDB) DeleteVersionedResources(ids []int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	interfaceIDs := make([]interface{}, len(ids))
+	for i, id := range ids {
+		interfaceIDs[i] = id
+	}
+
+	indexStrings := make([]string, len(ids))
+	for i := range indexStrings {
+		indexStrings[i] = "$" + strconv.Itoa(i+1)
+	}
+
+	result, err := db.conn.Exec(`
+		DELETE FROM versioned_resources vr
+		USING resources r
+		WHERE r.id = vr.resource_id
+		AND vr.id IN (`+strings.Join(indexStrings, ",")+`)
+		AND `+orphanedVersionedResourceCondition, interfaceIDs...)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), nil
+}
@@ -5,14 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/concourse/atc"
+	"github.com/lib/pq"
 )
 
 //go:generate counterfeiter . TeamDB
 
 type TeamDB interface {
 	GetPipelineByName(pipelineName string) (SavedPipeline, bool, error)
+	GetPipelineByNameIgnoringCase(pipelineName string) (SavedPipeline, bool, error)
 
 	GetTeam() (SavedTeam, bool, error)
 	GetConfig(pipelineName string) (atc.Config, atc.RawConfig, ConfigVersion, error)
@@ -50,6 +53,64 @@ func (db *teamDB) GetPipelineByName(pipelineName string) (SavedPipeline, bool, e
 	return pipeline, true, nil
 }
 
+// GetPipelineByNameIgnoringCase looks the pipeline up by its exact name
+// first, falling back to a case-insensitive match. If more than one of the
+// team's pipelines match case-insensitively, an AmbiguousPipelineNameError is
+// returned rather than guessing.
+func (db *teamDB) GetPipelineByNameIgnoringCase(pipelineName string) (SavedPipeline, bool, error) {
+	pipeline, found, err := db.GetPipelineByName(pipelineName)
+	if err != nil || found {
+		return pipeline, found, err
+	}
+
+	rows, err := db.conn.Query(`
+		SELECT `+pipelineColumns+`
+		FROM pipelines p
+		INNER JOIN teams t ON t.id = p.team_id
+		WHERE LOWER(p.name) = LOWER($1)
+		AND p.team_id = (
+			SELECT id FROM teams WHERE LOWER(name) = LOWER($2)
+		)
+		ORDER BY p.id
+	`, pipelineName, db.teamName)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	defer rows.Close()
+
+	pipelines, err := scanPipelines(rows)
+	if err != nil {
+		return SavedPipeline{}, false, err
+	}
+
+	switch len(pipelines) {
+	case 0:
+		return SavedPipeline{}, false, nil
+	case 1:
+		return pipelines[0], true, nil
+	default:
+		matches := make([]string, len(pipelines))
+		for i, p := range pipelines {
+			matches[i] = p.Name
+		}
+
+		return SavedPipeline{}, false, AmbiguousPipelineNameError{
+			Name:    pipelineName,
+			Matches: matches,
+		}
+	}
+}
+
+type AmbiguousPipelineNameError struct {
+	Name    string
+	Matches []string
+}
+
+func (e AmbiguousPipelineNameError) Error() string {
+	return fmt.Sprintf("pipeline name '%s' is ambiguous; it matches %s", e.Name, strings.Join(e.Matches, ", "))
+}
+
 func (db *teamDB) GetConfig(pipelineName string) (atc.Config, atc.RawConfig, ConfigVersion, error) {
 	var configBlob []byte
 	var version int
@@ -123,20 +184,22 @@ func (db *teamDB) SaveConfigToBeDeprecated(
 		}
 
 		savedPipeline, err = scanPipeline(tx.QueryRow(`
-		INSERT INTO pipelines (name, config, version, ordering, paused, team_id)
+		INSERT INTO pipelines (name, config, version, ordering, paused, team_id, last_updated, max_in_flight)
 		VALUES (
 			$1,
 			$2,
 			nextval('config_version_seq'),
 			(SELECT COUNT(1) + 1 FROM pipelines),
 			$3,
-			$4
+			$4,
+			now(),
+			$5
 		)
 		RETURNING `+unqualifiedPipelineColumns+`,
 		(
 			SELECT t.name as team_name FROM teams t WHERE t.id = $4
 		)
-		`, pipelineName, payload, pausedState.Bool(), teamID))
+		`, pipelineName, payload, pausedState.Bool(), teamID, config.MaxInFlight))
 		if err != nil {
 			return SavedPipeline{}, false, err
 		}
@@ -168,7 +231,7 @@ func (db *teamDB) SaveConfigToBeDeprecated(
 		if pausedState == PipelineNoChange {
 			savedPipeline, err = scanPipeline(tx.QueryRow(`
 			UPDATE pipelines
-			SET config = $1, version = nextval('config_version_seq')
+			SET config = $1, version = nextval('config_version_seq'), last_updated = now(), max_in_flight = $5
 			WHERE name = $2
 			AND version = $3
 			AND team_id = $4
@@ -176,11 +239,11 @@ func (db *teamDB) SaveConfigToBeDeprecated(
 			(
 				SELECT t.name as team_name FROM teams t WHERE t.id = $4
 			)
-			`, payload, pipelineName, from, teamID))
+			`, payload, pipelineName, from, teamID, config.MaxInFlight))
 		} else {
 			savedPipeline, err = scanPipeline(tx.QueryRow(`
 			UPDATE pipelines
-			SET config = $1, version = nextval('config_version_seq'), paused = $2
+			SET config = $1, version = nextval('config_version_seq'), paused = $2, last_updated = now(), max_in_flight = $6
 			WHERE name = $3
 			AND version = $4
 			AND team_id = $5
@@ -188,7 +251,7 @@ func (db *teamDB) SaveConfigToBeDeprecated(
 			(
 				SELECT t.name as team_name FROM teams t WHERE t.id = $4
 			)
-			`, payload, pausedState.Bool(), pipelineName, from, teamID))
+			`, payload, pausedState.Bool(), pipelineName, from, teamID, config.MaxInFlight))
 		}
 
 		if err != nil && err != sql.ErrNoRows {
@@ -420,8 +483,8 @@ func (db *teamDB) CreateOneOffBuild() (Build, error) {
 	defer tx.Rollback()
 
 	build, _, err := db.buildFactory.ScanBuild(tx.QueryRow(`
-		INSERT INTO builds (name, team_id, status)
-		SELECT nextval('one_off_name'), t.id, 'pending'
+		INSERT INTO builds (name, team_id, status, trigger_reason)
+		SELECT nextval('one_off_name'), t.id, 'pending', 'manual'
 		FROM teams t WHERE LOWER(t.name) = LOWER($1)
 		RETURNING `+buildColumns+`, null, null, null,
 		(
@@ -452,10 +515,13 @@ func scanPipeline(rows scannable) (SavedPipeline, error) {
 	var version int
 	var paused bool
 	var public bool
+	var archived bool
 	var teamID int
+	var lastUpdated pq.NullTime
+	var maxInFlight int
 	var teamName string
 
-	err := rows.Scan(&id, &name, &configBlob, &version, &paused, &teamID, &public, &teamName)
+	err := rows.Scan(&id, &name, &configBlob, &version, &paused, &teamID, &public, &archived, &lastUpdated, &maxInFlight, &teamName)
 	if err != nil {
 		return SavedPipeline{}, err
 	}
@@ -470,8 +536,13 @@ func scanPipeline(rows scannable) (SavedPipeline, error) {
 		ID:       id,
 		Paused:   paused,
 		Public:   public,
+		Archived: archived,
 		TeamID:   teamID,
 		TeamName: teamName,
+
+		LastUpdated: lastUpdated.Time,
+		MaxInFlight: maxInFlight,
+
 		Pipeline: Pipeline{
 			Name:    name,
 			Config:  config,
@@ -84,6 +84,62 @@ var _ = Describe("TeamDB", func() {
 		})
 	})
 
+	Describe("GetPipelineByNameIgnoringCase", func() {
+		var savedPipeline db.SavedPipeline
+
+		BeforeEach(func() {
+			var err error
+			savedPipeline, _, err = teamDB.SaveConfigToBeDeprecated("Pipeline-Name", atc.Config{}, 0, db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, err = otherTeamDB.SaveConfigToBeDeprecated("pipeline-name", atc.Config{}, 0, db.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the pipeline with the exact name", func() {
+			actualPipeline, found, err := teamDB.GetPipelineByNameIgnoringCase("Pipeline-Name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(actualPipeline).To(Equal(savedPipeline))
+		})
+
+		It("returns the team's pipeline whose name differs only in case", func() {
+			actualPipeline, found, err := teamDB.GetPipelineByNameIgnoringCase("PIPELINE-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(actualPipeline).To(Equal(savedPipeline))
+		})
+
+		It("returns false when no pipeline matches", func() {
+			_, found, err := teamDB.GetPipelineByNameIgnoringCase("other-pipeline")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		Context("when several of the team's pipelines differ only in case", func() {
+			BeforeEach(func() {
+				_, _, err := teamDB.SaveConfigToBeDeprecated("PIPELINE-NAME", atc.Config{}, 0, db.PipelineUnpaused)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("still returns an exact match", func() {
+				actualPipeline, found, err := teamDB.GetPipelineByNameIgnoringCase("Pipeline-Name")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(actualPipeline).To(Equal(savedPipeline))
+			})
+
+			It("returns an error rather than guessing between them", func() {
+				_, found, err := teamDB.GetPipelineByNameIgnoringCase("pipeline-name")
+				Expect(found).To(BeFalse())
+				Expect(err).To(Equal(db.AmbiguousPipelineNameError{
+					Name:    "pipeline-name",
+					Matches: []string{"Pipeline-Name", "PIPELINE-NAME"},
+				}))
+			})
+		})
+	})
+
 	Describe("GetTeam", func() {
 		It("returns the saved team", func() {
 			actualTeam, found, err := teamDB.GetTeam()
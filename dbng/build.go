@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -12,6 +13,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/config"
+	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/db/lock"
 	"github.com/concourse/atc/event"
 	"github.com/lib/pq"
@@ -28,7 +30,27 @@ const (
 	BuildStatusErrored   BuildStatus = "errored"
 )
 
-var buildsQuery = psql.Select("b.id, b.name, b.job_id, b.team_id, b.status, b.manually_triggered, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.reap_time, j.name, p.id, p.name, t.name").
+// BuildTriggerReason records why a build was created.
+type BuildTriggerReason string
+
+const (
+	BuildTriggerReasonManual    BuildTriggerReason = "manual"
+	BuildTriggerReasonScheduler BuildTriggerReason = "scheduler"
+)
+
+// ResourceTriggerReason is the reason for a build triggered by a new version
+// of the named resource.
+func ResourceTriggerReason(resourceName string) BuildTriggerReason {
+	return BuildTriggerReason("resource:" + resourceName)
+}
+
+// UpstreamTriggerReason is the reason for a build triggered by a version
+// that passed through the named upstream job.
+func UpstreamTriggerReason(jobName string) BuildTriggerReason {
+	return BuildTriggerReason("upstream:" + jobName)
+}
+
+var buildsQuery = psql.Select("b.id, b.name, b.job_id, b.team_id, b.status, b.manually_triggered, b.trigger_reason, b.scheduled, b.engine, b.engine_metadata, b.start_time, b.end_time, b.reap_time, b.overrides, b.inputs_pinned, j.name, p.id, p.name, t.name").
 	From("builds b").
 	JoinClause("LEFT OUTER JOIN jobs j ON b.job_id = j.id").
 	JoinClause("LEFT OUTER JOIN pipelines p ON j.pipeline_id = p.id").
@@ -52,7 +74,10 @@ type Build interface {
 	EndTime() time.Time
 	ReapTime() time.Time
 	IsManuallyTriggered() bool
+	TriggerReason() BuildTriggerReason
 	IsScheduled() bool
+	Overrides() atc.BuildOverrides
+	InputsPinned() bool
 
 	IsRunning() bool
 
@@ -73,16 +98,22 @@ type Build interface {
 	SaveInput(input BuildInput) error
 	SaveOutput(vr VersionedResource, explicit bool) error
 	UseInputs(inputs []BuildInput) error
+	PinInputs(inputMapping algorithm.InputMapping) error
 
 	Resources() ([]BuildInput, []BuildOutput, error)
 	GetVersionedResources() (SavedVersionedResources, error)
 	SaveImageResourceVersion(planID atc.PlanID, resourceVersion atc.Version, resourceHash string) error
+	ImageResourceVersion(resourceHash string) (atc.Version, bool, error)
+
+	SetMetadata(name string, value json.RawMessage) error
+	Metadata() (map[string]json.RawMessage, error)
 
 	Pipeline() (Pipeline, bool, error)
 
 	Finish(s BuildStatus) error
 	Delete() (bool, error)
 	Abort() error
+	AbortPending(reason string) (bool, error)
 	AbortNotifier() (Notifier, error)
 	Schedule() (bool, error)
 }
@@ -102,6 +133,7 @@ type build struct {
 	jobName      string
 
 	isManuallyTriggered bool
+	triggerReason       BuildTriggerReason
 
 	engine         string
 	engineMetadata string
@@ -110,6 +142,9 @@ type build struct {
 	endTime   time.Time
 	reapTime  time.Time
 
+	overrides    atc.BuildOverrides
+	inputsPinned bool
+
 	conn        Conn
 	lockFactory lock.LockFactory
 }
@@ -133,6 +168,15 @@ func (b *build) ReapTime() time.Time       { return b.reapTime }
 func (b *build) Status() BuildStatus       { return b.status }
 func (b *build) IsScheduled() bool         { return b.scheduled }
 
+func (b *build) TriggerReason() BuildTriggerReason { return b.triggerReason }
+
+// Overrides returns the overrides the build was manually triggered with.
+func (b *build) Overrides() atc.BuildOverrides { return b.overrides }
+
+// InputsPinned reports whether the build's inputs were decided for it alone,
+// in which case they are its build inputs rather than the job's next inputs.
+func (b *build) InputsPinned() bool { return b.inputsPinned }
+
 func (b *build) IsRunning() bool {
 	switch b.status {
 	case BuildStatusPending, BuildStatusStarted:
@@ -310,17 +354,7 @@ func (b *build) Finish(s BuildStatus) error {
 		return err
 	}
 
-	err = b.saveEvent(tx, event.Status{
-		Status: atc.BuildStatus(s),
-		Time:   endTime.Unix(),
-	})
-	if err != nil {
-		return err
-	}
-
-	_, err = tx.Exec(fmt.Sprintf(`
-		DROP SEQUENCE %s
-	`, buildEventSeq(b.id)))
+	err = finishBuild(tx, b.teamID, b.pipelineID, b.id, s, endTime)
 	if err != nil {
 		return err
 	}
@@ -379,6 +413,63 @@ func (b *build) Abort() error {
 	return nil
 }
 
+// AbortPending aborts the build if it has not yet started, recording the
+// reason as an error event. False is returned if the build was no longer
+// pending, e.g. because it had just been started by the scheduler.
+func (b *build) AbortPending(reason string) (bool, error) {
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	var endTime time.Time
+
+	err = psql.Update("builds").
+		Set("status", string(BuildStatusAborted)).
+		Set("end_time", sq.Expr("now()")).
+		Set("completed", true).
+		Where(sq.Eq{
+			"id":     b.id,
+			"status": BuildStatusPending,
+		}).
+		Suffix("RETURNING end_time").
+		RunWith(tx).
+		QueryRow().
+		Scan(&endTime)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = b.saveEvent(tx, event.Error{
+		Message: reason,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = finishBuild(tx, b.teamID, b.pipelineID, b.id, BuildStatusAborted, endTime)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	err = b.conn.Bus().Notify(buildEventsChannel(b.id))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (b *build) AbortNotifier() (Notifier, error) {
 	return newConditionNotifier(b.conn.Bus(), buildAbortChannel(b.id), func() (bool, error) {
 		var aborted bool
@@ -462,6 +553,94 @@ func (b *build) SaveImageResourceVersion(planID atc.PlanID, resourceVersion atc.
 	)
 }
 
+// ImageResourceVersion returns the version of the image resource with the
+// given hash that was first saved by any plan of the build, if any. Retried
+// attempts of a step have their own plan IDs, so the version is looked up by
+// the image resource rather than the plan.
+func (b *build) ImageResourceVersion(resourceHash string) (atc.Version, bool, error) {
+	var versionJSON []byte
+	err := psql.Select("version").
+		From("image_resource_versions").
+		Where(sq.Eq{
+			"build_id":      b.id,
+			"resource_hash": resourceHash,
+		}).
+		OrderBy("id ASC").
+		Limit(1).
+		RunWith(b.conn).
+		QueryRow().
+		Scan(&versionJSON)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	var version atc.Version
+	err = json.Unmarshal(versionJSON, &version)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return version, true, nil
+}
+
+func (b *build) SetMetadata(name string, value json.RawMessage) error {
+	return safeCreateOrUpdate(
+		b.conn,
+		func(tx Tx) (sql.Result, error) {
+			return psql.Insert("build_metadata").
+				Columns("build_id", "name", "value").
+				Values(b.id, name, string(value)).
+				RunWith(tx).
+				Exec()
+		},
+		func(tx Tx) (sql.Result, error) {
+			return psql.Update("build_metadata").
+				Set("value", string(value)).
+				Where(sq.Eq{
+					"build_id": b.id,
+					"name":     name,
+				}).
+				RunWith(tx).
+				Exec()
+		},
+	)
+}
+
+func (b *build) Metadata() (map[string]json.RawMessage, error) {
+	rows, err := psql.Select("name", "value").
+		From("build_metadata").
+		Where(sq.Eq{"build_id": b.id}).
+		RunWith(b.conn).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	metadata := map[string]json.RawMessage{}
+	for rows.Next() {
+		var name, value string
+		err = rows.Scan(&name, &value)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata[name] = json.RawMessage(value)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
 func (b *build) AcquireTrackingLock(logger lager.Logger, interval time.Duration) (lock.Lock, bool, error) {
 	lock := b.lockFactory.NewLock(
 		logger.Session("lock", lager.Data{
@@ -647,14 +826,9 @@ func (b *build) Events(from uint) (EventSource, error) {
 		return nil, err
 	}
 
-	table := fmt.Sprintf("team_build_events_%d", b.teamID)
-	if b.pipelineID != 0 {
-		table = fmt.Sprintf("pipeline_build_events_%d", b.pipelineID)
-	}
-
 	return newBuildEventSource(
 		b.id,
-		table,
+		buildEventsTable(b.teamID, b.pipelineID),
 		b.conn,
 		notifier,
 		from,
@@ -662,6 +836,10 @@ func (b *build) Events(from uint) (EventSource, error) {
 }
 
 func (b *build) SaveEvent(event atc.Event) error {
+	pendingEventWrites := b.conn.pendingEventWrites()
+	atomic.AddInt64(pendingEventWrites, 1)
+	defer atomic.AddInt64(pendingEventWrites, -1)
+
 	tx, err := b.conn.Begin()
 	if err != nil {
 		return err
@@ -778,6 +956,59 @@ func (b *build) UseInputs(inputs []BuildInput) error {
 	return tx.Commit()
 }
 
+// PinInputs saves the input mapping as the build's inputs and marks them as
+// pinned, so that the build runs with them instead of the job's next inputs.
+func (b *build) PinInputs(inputMapping algorithm.InputMapping) error {
+	tx, err := b.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = pinBuildInputs(tx, b.id, inputMapping)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	b.inputsPinned = true
+
+	return nil
+}
+
+func pinBuildInputs(tx Tx, buildID int, inputMapping algorithm.InputMapping) error {
+	_, err := psql.Delete("build_inputs").
+		Where(sq.Eq{"build_id": buildID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	for inputName, inputVersion := range inputMapping {
+		_, err = psql.Insert("build_inputs").
+			Columns("build_id", "versioned_resource_id", "name").
+			Values(buildID, inputVersion.VersionID, inputName).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = psql.Update("builds").
+		Set("inputs_pinned", true).
+		Where(sq.Eq{"id": buildID}).
+		RunWith(tx).
+		Exec()
+	return err
+}
+
 func (b *build) Resources() ([]BuildInput, []BuildOutput, error) {
 	inputs := []BuildInput{}
 	outputs := []BuildOutput{}
@@ -962,19 +1193,20 @@ func buildEventSeq(buildid int) string {
 
 func scanBuild(b *build, row scannable) error {
 	var (
-		jobID, pipelineID                             sql.NullInt64
-		engine, engineMetadata, jobName, pipelineName sql.NullString
-		startTime, endTime, reapTime                  pq.NullTime
+		jobID, pipelineID                                        sql.NullInt64
+		engine, engineMetadata, jobName, pipelineName, overrides sql.NullString
+		startTime, endTime, reapTime                             pq.NullTime
 
-		status string
+		status, triggerReason string
 	)
 
-	err := row.Scan(&b.id, &b.name, &jobID, &b.teamID, &status, &b.isManuallyTriggered, &b.scheduled, &engine, &engineMetadata, &startTime, &endTime, &reapTime, &jobName, &pipelineID, &pipelineName, &b.teamName)
+	err := row.Scan(&b.id, &b.name, &jobID, &b.teamID, &status, &b.isManuallyTriggered, &triggerReason, &b.scheduled, &engine, &engineMetadata, &startTime, &endTime, &reapTime, &overrides, &b.inputsPinned, &jobName, &pipelineID, &pipelineName, &b.teamName)
 	if err != nil {
 		return err
 	}
 
 	b.status = BuildStatus(status)
+	b.triggerReason = BuildTriggerReason(triggerReason)
 	b.jobName = jobName.String
 	b.jobID = int(jobID.Int64)
 	b.pipelineName = pipelineName.String
@@ -985,22 +1217,30 @@ func scanBuild(b *build, row scannable) error {
 	b.endTime = endTime.Time
 	b.reapTime = reapTime.Time
 
+	b.overrides = atc.BuildOverrides{}
+	if overrides.Valid {
+		err = json.Unmarshal([]byte(overrides.String), &b.overrides)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (b *build) saveEvent(tx Tx, event atc.Event) error {
+	return saveBuildEvent(tx, b.teamID, b.pipelineID, b.id, event)
+}
+
+func saveBuildEvent(tx Tx, teamID int, pipelineID int, buildID int, event atc.Event) error {
 	payload, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
-	table := fmt.Sprintf("team_build_events_%d", b.teamID)
-	if b.pipelineID != 0 {
-		table = fmt.Sprintf("pipeline_build_events_%d", b.pipelineID)
-	}
-	_, err = psql.Insert(table).
+	_, err = psql.Insert(buildEventsTable(teamID, pipelineID)).
 		Columns("event_id", "build_id", "type", "version", "payload").
-		Values(sq.Expr("nextval('"+buildEventSeq(b.id)+"')"), b.id, string(event.EventType()), string(event.Version()), payload).
+		Values(sq.Expr("nextval('"+buildEventSeq(buildID)+"')"), buildID, string(event.EventType()), string(event.Version()), payload).
 		RunWith(tx).
 		Exec()
 	if err != nil {
@@ -1010,6 +1250,36 @@ func (b *build) saveEvent(tx Tx, event atc.Event) error {
 	return nil
 }
 
+// finishBuild saves the final status event of a build that has just been
+// marked as completed in tx, and drops its event sequence. The caller must
+// notify buildEventsChannel once tx is committed.
+func finishBuild(tx Tx, teamID int, pipelineID int, buildID int, status BuildStatus, endTime time.Time) error {
+	err := saveBuildEvent(tx, teamID, pipelineID, buildID, event.Status{
+		Status: atc.BuildStatus(status),
+		Time:   endTime.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf(`
+		DROP SEQUENCE %s
+	`, buildEventSeq(buildID)))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func buildEventsTable(teamID int, pipelineID int) string {
+	if pipelineID != 0 {
+		return fmt.Sprintf("pipeline_build_events_%d", pipelineID)
+	}
+
+	return fmt.Sprintf("team_build_events_%d", teamID)
+}
+
 func buildEventsChannel(buildID int) string {
 	return fmt.Sprintf("build_events_%d", buildID)
 }
@@ -1,6 +1,7 @@
 package dbng
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"sync"
@@ -51,6 +52,9 @@ type buildEventSource struct {
 	buildID int
 	table   string
 
+	compactionsKnown bool
+	compactions      int
+
 	conn     Conn
 	notifier Notifier
 
@@ -98,75 +102,52 @@ func (source *buildEventSource) collectEvents(cursor uint) {
 		default:
 		}
 
-		completed := false
-
-		err := source.conn.QueryRow(`
-			SELECT builds.completed
-			FROM builds
-			WHERE builds.id = $1
-		`, source.buildID).Scan(&completed)
+		batch, err := source.nextBatch(cursor, batchSize)
 		if err != nil {
 			source.err = err
 			close(source.events)
 			return
 		}
 
-		rows, err := source.conn.Query(`
-			SELECT type, version, payload
-			FROM `+source.table+`
-			WHERE build_id = $1
-			ORDER BY event_id ASC
-			OFFSET $2
-			LIMIT $3
-		`, source.buildID, cursor, batchSize)
-		if err != nil {
-			source.err = err
-			close(source.events)
-			return
-		}
-
-		rowsReturned := 0
-
-		for rows.Next() {
-			rowsReturned++
-
-			cursor++
-
-			var t, v, p string
-			err := rows.Scan(&t, &v, &p)
+		if batch.resynced {
+			payload, err := json.Marshal(event.Resynced{From: batch.cursor})
 			if err != nil {
-				rows.Close()
-
 				source.err = err
 				close(source.events)
 				return
 			}
 
-			data := json.RawMessage(p)
+			data := json.RawMessage(payload)
 
-			ev := event.Envelope{
+			batch.events = append([]event.Envelope{{
 				Data:    &data,
-				Event:   atc.EventType(t),
-				Version: atc.EventVersion(v),
+				Event:   event.EventTypeResynced,
+				Version: event.Resynced{}.Version(),
+			}}, batch.events...)
+		}
+
+		cursor = batch.cursor
+
+		for _, ev := range batch.events {
+			if ev.Event != event.EventTypeResynced {
+				cursor++
 			}
 
 			select {
 			case source.events <- ev:
 			case <-source.stop:
-				rows.Close()
-
 				source.err = ErrBuildEventStreamClosed
 				close(source.events)
 				return
 			}
 		}
 
-		if rowsReturned == batchSize {
+		if batch.rows == batchSize {
 			// still more events
 			continue
 		}
 
-		if completed {
+		if batch.completed {
 			source.err = ErrEndOfBuildEventStream
 			close(source.events)
 			return
@@ -181,3 +162,175 @@ func (source *buildEventSource) collectEvents(cursor uint) {
 		}
 	}
 }
+
+type eventBatch struct {
+	completed bool
+	resynced  bool
+	cursor    uint
+	rows      int
+	events    []event.Envelope
+}
+
+// nextBatch reads the events following the cursor. Only completed builds are
+// ever compacted, so while the build is running its events are read without
+// locking it.
+func (source *buildEventSource) nextBatch(cursor uint, limit int) (eventBatch, error) {
+	batch := eventBatch{cursor: cursor}
+
+	var compactions int
+	err := source.conn.QueryRow(`
+		SELECT builds.completed, builds.event_compactions
+		FROM builds
+		WHERE builds.id = $1
+	`, source.buildID).Scan(&batch.completed, &compactions)
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	if !source.compactionsKnown {
+		source.compactionsKnown = true
+		source.compactions = compactions
+	}
+
+	if batch.completed || compactions != source.compactions {
+		return source.nextCompletedBatch(cursor, limit)
+	}
+
+	// the build may have completed and been compacted since checking, so only
+	// read events if it still has the compactions this source has seen; if it
+	// doesn't, no events are read and the next batch remaps the cursor
+	rows, err := source.conn.Query(`
+		SELECT type, version, payload
+		FROM `+source.table+`
+		WHERE build_id = $1
+		AND EXISTS (
+			SELECT 1
+			FROM builds
+			WHERE builds.id = $1
+			AND builds.event_compactions = $4
+		)
+		ORDER BY event_id ASC
+		OFFSET $2
+		LIMIT $3
+	`, source.buildID, batch.cursor, limit, source.compactions)
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	err = batch.scanEvents(rows)
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	return batch, nil
+}
+
+// nextCompletedBatch reads the events of a completed build following the
+// cursor. The build's row is share locked while doing so, so that the events
+// can't be compacted between checking for compaction and reading them. If
+// they were compacted since the last batch, the cursor is first mapped onto
+// the renumbered events.
+func (source *buildEventSource) nextCompletedBatch(cursor uint, limit int) (eventBatch, error) {
+	batch := eventBatch{cursor: cursor}
+
+	tx, err := source.conn.Begin()
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	defer tx.Rollback()
+
+	var compactions int
+	err = tx.QueryRow(`
+		SELECT builds.completed, builds.event_compactions
+		FROM builds
+		WHERE builds.id = $1
+		FOR SHARE
+	`, source.buildID).Scan(&batch.completed, &compactions)
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	for ; source.compactions < compactions; source.compactions++ {
+		batch.cursor, err = source.remapCursor(tx, source.compactions+1, batch.cursor)
+		if err != nil {
+			return eventBatch{}, err
+		}
+
+		batch.resynced = true
+	}
+
+	rows, err := tx.Query(`
+		SELECT type, version, payload
+		FROM `+source.table+`
+		WHERE build_id = $1
+		ORDER BY event_id ASC
+		OFFSET $2
+		LIMIT $3
+	`, source.buildID, batch.cursor, limit)
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	err = batch.scanEvents(rows)
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return eventBatch{}, err
+	}
+
+	return batch, nil
+}
+
+func (batch *eventBatch) scanEvents(rows *sql.Rows) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		batch.rows++
+
+		var t, v, p string
+		err := rows.Scan(&t, &v, &p)
+		if err != nil {
+			return err
+		}
+
+		data := json.RawMessage(p)
+
+		batch.events = append(batch.events, event.Envelope{
+			Data:    &data,
+			Event:   atc.EventType(t),
+			Version: atc.EventVersion(v),
+		})
+	}
+
+	return rows.Close()
+}
+
+// remapCursor maps an offset into the events as they were before the given
+// compaction onto the compacted events. An offset pointing into the middle of
+// a run of coalesced events maps to the event they were coalesced into, which
+// is sent again in full.
+func (source *buildEventSource) remapCursor(tx Tx, compaction int, cursor uint) (uint, error) {
+	var newOffset uint
+	err := tx.QueryRow(`
+		SELECT new_offset
+		FROM build_event_compactions
+		WHERE build_id = $1
+		AND compaction = $2
+		AND old_offset <= $3
+		ORDER BY old_offset DESC
+		LIMIT 1
+	`, source.buildID, compaction, cursor).Scan(&newOffset)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return newOffset, nil
+}
@@ -2,9 +2,14 @@ package dbng
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/concourse/atc/db/lock"
+	"github.com/concourse/atc/event"
 )
 
 //go:generate counterfeiter . BuildFactory
@@ -13,11 +18,37 @@ type BuildFactory interface {
 	Build(int) (Build, bool, error)
 	PublicBuilds(Page) ([]Build, Pagination, error)
 	GetAllStartedBuilds() ([]Build, error)
+	GetZombieBuilds(startedBefore time.Time) ([]Build, error)
+	GetBuildQueueStats(teamID int, since time.Time) (QueueStats, error)
+	EventStoreBacklog() (int, error)
+	CompactBuildEvents(buildID int) error
+	GetBuildTimeline(buildID int) ([]StepTiming, error)
+	GetStepEvents(buildID int, stepName string, attempt []int, from uint) (EventSource, error)
+
+	RecordBuildResourceUsage(buildID int, cpuSeconds float64, memByteSeconds float64) error
+	RecordUnmeasuredBuildResourceUsage(buildID int) error
+	GetBuildResourceUsage(buildID int) (ResourceUsage, error)
 
 	// TODO: move to BuildLifecycle, new interface (see WorkerLifecycle)
 	MarkNonInterceptibleBuilds() error
 }
 
+// QueueStats describes how long builds waited between being created and
+// being started.
+type QueueStats struct {
+	Builds          int
+	AverageWaitTime time.Duration
+	P95WaitTime     time.Duration
+}
+
+// ErrBuildNotFinished is returned when compacting the events of a build that
+// may still be emitting them.
+var ErrBuildNotFinished = errors.New("build-not-finished")
+
+// maxCompactedLogSize bounds the payload of a single coalesced log event, so
+// that compaction doesn't produce rows too large to stream comfortably.
+const maxCompactedLogSize = 1024 * 1024
+
 type buildFactory struct {
 	conn        Conn
 	lockFactory lock.LockFactory
@@ -90,8 +121,20 @@ func (f *buildFactory) MarkNonInterceptibleBuilds() error {
 }
 
 func (f *buildFactory) GetAllStartedBuilds() ([]Build, error) {
-	rows, err := buildsQuery.
+	return f.getBuilds(buildsQuery.Where(sq.Eq{"b.status": BuildStatusStarted}))
+}
+
+// GetZombieBuilds returns builds that are still marked as started but began
+// running before the given time, e.g. because the ATC running them went away
+// before it could finish them.
+func (f *buildFactory) GetZombieBuilds(startedBefore time.Time) ([]Build, error) {
+	return f.getBuilds(buildsQuery.
 		Where(sq.Eq{"b.status": BuildStatusStarted}).
+		Where(sq.Lt{"b.start_time": startedBefore}))
+}
+
+func (f *buildFactory) getBuilds(query sq.SelectBuilder) ([]Build, error) {
+	rows, err := query.
 		RunWith(f.conn).
 		Query()
 	if err != nil {
@@ -115,6 +158,250 @@ func (f *buildFactory) GetAllStartedBuilds() ([]Build, error) {
 	return bs, nil
 }
 
+func (f *buildFactory) GetBuildQueueStats(teamID int, since time.Time) (QueueStats, error) {
+	var (
+		stats          QueueStats
+		averageSeconds float64
+		p95Seconds     float64
+	)
+
+	err := psql.Select(
+		"COUNT(*)",
+		"COALESCE(EXTRACT(EPOCH FROM AVG(start_time - create_time)), 0)",
+		"COALESCE(EXTRACT(EPOCH FROM percentile_cont(0.95) WITHIN GROUP (ORDER BY start_time - create_time)), 0)",
+	).
+		From("builds").
+		Where(sq.Eq{"team_id": teamID}).
+		Where(sq.NotEq{
+			"create_time": nil,
+			"start_time":  nil,
+		}).
+		Where(sq.GtOrEq{"create_time": since}).
+		RunWith(f.conn).
+		QueryRow().
+		Scan(&stats.Builds, &averageSeconds, &p95Seconds)
+	if err != nil {
+		return QueueStats{}, err
+	}
+
+	stats.AverageWaitTime = time.Duration(averageSeconds * float64(time.Second))
+	stats.P95WaitTime = time.Duration(p95Seconds * float64(time.Second))
+
+	return stats, nil
+}
+
+// EventStoreBacklog returns the number of build events saved through this
+// factory's Conn that haven't been written yet, including those waiting for a
+// database connection. A growing backlog means event writes are backing up.
+func (f *buildFactory) EventStoreBacklog() (int, error) {
+	return int(atomic.LoadInt64(f.conn.pendingEventWrites())), nil
+}
+
+// CompactBuildEvents coalesces runs of consecutive stdout/stderr log events
+// of a finished build into fewer, larger events, preserving their order and
+// content. The build's remaining events are renumbered so that their event
+// IDs stay contiguous.
+//
+// Each compaction bumps the build's compaction count and records where every
+// event now lives, so that event sources already tailing the build can map
+// their offset onto the renumbered events.
+func (f *buildFactory) CompactBuildEvents(buildID int) error {
+	tx, err := f.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var (
+		teamID     int
+		pipelineID sql.NullInt64
+		completed  bool
+	)
+
+	err = psql.Select("b.team_id", "j.pipeline_id", "b.completed").
+		From("builds b").
+		JoinClause("LEFT OUTER JOIN jobs j ON b.job_id = j.id").
+		Where(sq.Eq{"b.id": buildID}).
+		Suffix("FOR UPDATE OF b").
+		RunWith(tx).
+		QueryRow().
+		Scan(&teamID, &pipelineID, &completed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrBuildDisappeared
+		}
+		return err
+	}
+
+	if !completed {
+		return ErrBuildNotFinished
+	}
+
+	table := buildEventsTable(teamID, int(pipelineID.Int64))
+
+	rows, err := psql.Select("type", "version", "payload").
+		From(table).
+		Where(sq.Eq{"build_id": buildID}).
+		OrderBy("event_id ASC").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	var events []compactedEvent
+	var offset int
+	for ; rows.Next(); offset++ {
+		ev := compactedEvent{offset: offset}
+		err = rows.Scan(&ev.eventType, &ev.version, &ev.payload)
+		if err != nil {
+			return err
+		}
+
+		if len(events) > 0 && events[len(events)-1].absorb(ev) {
+			continue
+		}
+
+		events = append(events, ev)
+	}
+
+	err = rows.Close()
+	if err != nil {
+		return err
+	}
+
+	_, err = psql.Delete(table).
+		Where(sq.Eq{"build_id": buildID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	for i, ev := range events {
+		payload, err := ev.marshalPayload()
+		if err != nil {
+			return err
+		}
+
+		_, err = psql.Insert(table).
+			Columns("event_id", "build_id", "type", "version", "payload").
+			Values(i, buildID, ev.eventType, ev.version, payload).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	var compaction int
+	err = psql.Update("builds").
+		Set("event_compactions", sq.Expr("event_compactions + 1")).
+		Where(sq.Eq{"id": buildID}).
+		Suffix("RETURNING event_compactions").
+		RunWith(tx).
+		QueryRow().
+		Scan(&compaction)
+	if err != nil {
+		return err
+	}
+
+	for i, ev := range events {
+		err = insertEventCompaction(tx, buildID, compaction, ev.offset, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	// map the end of the stream too, so that a source which has already
+	// consumed every event resumes past the last compacted one
+	err = insertEventCompaction(tx, buildID, compaction, offset, len(events))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertEventCompaction(tx Tx, buildID int, compaction int, oldOffset int, newOffset int) error {
+	_, err := psql.Insert("build_event_compactions").
+		Columns("build_id", "compaction", "old_offset", "new_offset").
+		Values(buildID, compaction, oldOffset, newOffset).
+		RunWith(tx).
+		Exec()
+	return err
+}
+
+type compactedEvent struct {
+	offset int
+
+	eventType string
+	version   string
+	payload   string
+
+	log *event.Log
+}
+
+// absorb appends the next event's log output onto this event if both are
+// output from the same origin, returning false if they can't be combined.
+func (ev *compactedEvent) absorb(next compactedEvent) bool {
+	if ev.log == nil && !ev.parseLog() {
+		return false
+	}
+
+	if !next.parseLog() {
+		return false
+	}
+
+	if next.version != ev.version || next.log.Origin != ev.log.Origin {
+		return false
+	}
+
+	if len(ev.log.Payload)+len(next.log.Payload) > maxCompactedLogSize {
+		return false
+	}
+
+	ev.log.Payload += next.log.Payload
+
+	return true
+}
+
+func (ev *compactedEvent) parseLog() bool {
+	if ev.eventType != string(event.EventTypeLog) || ev.version != string(event.Log{}.Version()) {
+		return false
+	}
+
+	var log event.Log
+	err := json.Unmarshal([]byte(ev.payload), &log)
+	if err != nil {
+		return false
+	}
+
+	if log.Origin.Source != event.OriginSourceStdout && log.Origin.Source != event.OriginSourceStderr {
+		return false
+	}
+
+	ev.log = &log
+
+	return true
+}
+
+func (ev compactedEvent) marshalPayload() (string, error) {
+	if ev.log == nil {
+		return ev.payload, nil
+	}
+
+	payload, err := json.Marshal(ev.log)
+	if err != nil {
+		return "", err
+	}
+
+	return string(payload), nil
+}
+
 func getBuildsWithPagination(buildsQuery sq.SelectBuilder, page Page, conn Conn, lockFactory lock.LockFactory) ([]Build, Pagination, error) {
 	var rows *sql.Rows
 	var err error
@@ -129,7 +416,11 @@ func getBuildsWithPagination(buildsQuery sq.SelectBuilder, page Page, conn Conn,
 		buildsQuery = buildsQuery.Where(sq.Lt{"b.id": page.Since}).OrderBy("b.id DESC").Limit(uint64(page.Limit))
 	}
 
-	rows, err = buildsQuery.RunWith(conn).Query()
+	// listings are read-heavy, so read from the replica if there is one; the
+	// builds themselves still write through conn
+	readConn := conn.ReadConn()
+
+	rows, err = buildsQuery.RunWith(readConn).Query()
 	if err != nil {
 		return nil, Pagination{}, err
 	}
@@ -162,7 +453,7 @@ func getBuildsWithPagination(buildsQuery sq.SelectBuilder, page Page, conn Conn,
 	var maxID int
 	err = psql.Select("COALESCE(MAX(id), 0)", "COALESCE(MIN(id), 0)").
 		From("builds").
-		RunWith(conn).
+		RunWith(readConn).
 		QueryRow().
 		Scan(&maxID, &minID)
 	if err != nil {
@@ -1,9 +1,17 @@
 package dbng_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/concourse/atc/dbng"
+	"github.com/concourse/atc/event"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/concourse/atc"
+	"github.com/lib/pq"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -243,4 +251,810 @@ var _ = Describe("BuildFactory", func() {
 			Expect(builds).To(ConsistOf(build1DB, build2DB))
 		})
 	})
+
+	Describe("GetZombieBuilds", func() {
+		var (
+			zombieBuild  dbng.Build
+			runningBuild dbng.Build
+		)
+
+		startBuildAgo := func(ago time.Duration) dbng.Build {
+			build, err := team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			started, err := build.Start("some-engine", "so-meta")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			_, err = psql.Update("builds").
+				Set("start_time", sq.Expr("now() - ? * interval '1 second'", ago.Seconds())).
+				Where(sq.Eq{"id": build.ID()}).
+				RunWith(dbConn).
+				Exec()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = build.Reload()
+			Expect(err).NotTo(HaveOccurred())
+
+			return build
+		}
+
+		BeforeEach(func() {
+			zombieBuild = startBuildAgo(2 * time.Hour)
+			runningBuild = startBuildAgo(time.Minute)
+
+			finishedBuild := startBuildAgo(2 * time.Hour)
+			err := finishedBuild.Finish(dbng.BuildStatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns started builds that began before the cutoff", func() {
+			builds, err := buildFactory.GetZombieBuilds(time.Now().Add(-time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(ConsistOf(zombieBuild))
+		})
+
+		It("returns every started build when the cutoff is in the future", func() {
+			builds, err := buildFactory.GetZombieBuilds(time.Now().Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(ConsistOf(zombieBuild, runningBuild))
+		})
+
+		Context("when the zombie build is finished as errored", func() {
+			BeforeEach(func() {
+				err := zombieBuild.Finish(dbng.BuildStatusErrored)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("is no longer returned", func() {
+				builds, err := buildFactory.GetZombieBuilds(time.Now().Add(-time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(builds).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("GetBuildQueueStats", func() {
+		var (
+			pipeline dbng.Pipeline
+			since    time.Time
+		)
+
+		createBuildThatWaited := func(wait time.Duration) dbng.Build {
+			build, err := pipeline.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			started, err := build.Start("some-engine", "so-meta")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			_, err = psql.Update("builds").
+				Set("create_time", sq.Expr("start_time - ? * interval '1 second'", wait.Seconds())).
+				Where(sq.Eq{"id": build.ID()}).
+				RunWith(dbConn).
+				Exec()
+			Expect(err).NotTo(HaveOccurred())
+
+			return build
+		}
+
+		BeforeEach(func() {
+			var err error
+			pipeline, _, err = team.SavePipeline("some-pipeline", atc.Config{
+				Jobs: atc.JobConfigs{
+					{
+						Name: "some-job",
+					},
+				},
+			}, dbng.ConfigVersion(0), dbng.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			since = time.Now().Add(-time.Hour)
+		})
+
+		It("records when a build is created and when it starts", func() {
+			build, err := pipeline.CreateJobBuild("some-job")
+			Expect(err).NotTo(HaveOccurred())
+
+			var createTime, startTime pq.NullTime
+			err = psql.Select("create_time", "start_time").
+				From("builds").
+				Where(sq.Eq{"id": build.ID()}).
+				RunWith(dbConn).
+				QueryRow().
+				Scan(&createTime, &startTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(createTime.Valid).To(BeTrue())
+			Expect(startTime.Valid).To(BeFalse())
+
+			_, err = build.Start("some-engine", "so-meta")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = psql.Select("start_time").
+				From("builds").
+				Where(sq.Eq{"id": build.ID()}).
+				RunWith(dbConn).
+				QueryRow().
+				Scan(&startTime)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(startTime.Valid).To(BeTrue())
+			Expect(startTime.Time).To(BeTemporally(">=", createTime.Time))
+		})
+
+		Context("when builds have waited to start", func() {
+			BeforeEach(func() {
+				for i := 1; i <= 20; i++ {
+					createBuildThatWaited(time.Duration(i) * time.Second)
+				}
+
+				_, err := pipeline.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("computes the wait times from the create and start times of started builds", func() {
+				stats, err := buildFactory.GetBuildQueueStats(team.ID(), since)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats.Builds).To(Equal(20))
+				Expect(stats.AverageWaitTime).To(BeNumerically("~", 10500*time.Millisecond, time.Millisecond))
+				Expect(stats.P95WaitTime).To(BeNumerically("~", 19050*time.Millisecond, time.Millisecond))
+			})
+
+			It("does not include builds for other teams", func() {
+				otherTeam, err := teamFactory.CreateTeam(atc.Team{Name: "some-other-team"})
+				Expect(err).NotTo(HaveOccurred())
+
+				stats, err := buildFactory.GetBuildQueueStats(otherTeam.ID(), since)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats).To(Equal(dbng.QueueStats{}))
+			})
+
+			It("does not include builds created before the given time", func() {
+				stats, err := buildFactory.GetBuildQueueStats(team.ID(), time.Now().Add(time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stats).To(Equal(dbng.QueueStats{}))
+			})
+		})
+	})
+
+	Describe("EventStoreBacklog", func() {
+		var build dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("is empty when no events are being written", func() {
+			err := build.SaveEvent(event.Log{Payload: "hello"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(buildFactory.EventStoreBacklog()).To(BeZero())
+		})
+
+		Context("when event writes are blocked", func() {
+			var (
+				tx    dbng.Tx
+				saved chan error
+			)
+
+			BeforeEach(func() {
+				var err error
+				tx, err = dbConn.Begin()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = tx.Exec(fmt.Sprintf("LOCK TABLE team_build_events_%d IN EXCLUSIVE MODE", team.ID()))
+				Expect(err).NotTo(HaveOccurred())
+
+				saved = make(chan error, 1)
+				go func() {
+					defer GinkgoRecover()
+					saved <- build.SaveEvent(event.Log{Payload: "hello"})
+				}()
+			})
+
+			AfterEach(func() {
+				_ = tx.Rollback()
+			})
+
+			It("counts the writes that are waiting", func() {
+				Eventually(buildFactory.EventStoreBacklog).Should(Equal(1))
+
+				err := tx.Rollback()
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(saved).Should(Receive(BeNil()))
+				Eventually(buildFactory.EventStoreBacklog).Should(BeZero())
+			})
+
+			It("does not count writes made through another Conn", func() {
+				otherConn := postgresRunner.OpenConn()
+				defer otherConn.Close()
+
+				otherBuildFactory := dbng.NewBuildFactory(otherConn, lockFactory)
+
+				Eventually(buildFactory.EventStoreBacklog).Should(Equal(1))
+				Expect(otherBuildFactory.EventStoreBacklog()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("CompactBuildEvents", func() {
+		var build dbng.Build
+
+		stdout := func(id string, payload string) event.Log {
+			return event.Log{
+				Origin:  event.Origin{ID: event.OriginID(id), Source: event.OriginSourceStdout},
+				Payload: payload,
+			}
+		}
+
+		stderr := func(id string, payload string) event.Log {
+			return event.Log{
+				Origin:  event.Origin{ID: event.OriginID(id), Source: event.OriginSourceStderr},
+				Payload: payload,
+			}
+		}
+
+		replay := func() []event.Envelope {
+			events, err := build.Events(0)
+			Expect(err).NotTo(HaveOccurred())
+
+			defer events.Close()
+
+			envelopes := []event.Envelope{}
+			for {
+				ev, err := events.Next()
+				if err == dbng.ErrEndOfBuildEventStream {
+					return envelopes
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				envelopes = append(envelopes, ev)
+			}
+		}
+
+		// renders the log output per origin in order, so that replays can be
+		// compared regardless of how the output was split into events
+		render := func(envelopes []event.Envelope) []string {
+			rendered := []string{}
+			for _, envelope := range envelopes {
+				if envelope.Event != event.EventTypeLog {
+					rendered = append(rendered, string(envelope.Event))
+					continue
+				}
+
+				var log event.Log
+				err := json.Unmarshal(*envelope.Data, &log)
+				Expect(err).NotTo(HaveOccurred())
+
+				origin := string(log.Origin.ID) + "/" + string(log.Origin.Source) + ": "
+
+				last := len(rendered) - 1
+				if last >= 0 && strings.HasPrefix(rendered[last], origin) {
+					rendered[last] += log.Payload
+				} else {
+					rendered = append(rendered, origin+log.Payload)
+				}
+			}
+
+			return rendered
+		}
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			started, err := build.Start("some-engine", "so-meta")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			for _, ev := range []atc.Event{
+				stdout("a", "hello "),
+				stdout("a", "world\n"),
+				stderr("a", "oops\n"),
+				stderr("a", "more\n"),
+				stdout("a", "again\n"),
+				event.FinishTask{ExitStatus: 0, Origin: event.Origin{ID: "a"}},
+				stdout("b", "x"),
+				stdout("b", "y"),
+			} {
+				err = build.SaveEvent(ev)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		Context("when the build has finished", func() {
+			BeforeEach(func() {
+				err := build.Finish(dbng.BuildStatusSucceeded)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("coalesces consecutive output from the same origin", func() {
+				err := buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				envelopes := replay()
+				Expect(envelopes).To(HaveLen(7))
+				Expect(envelopes[1]).To(Equal(envelope(stdout("a", "hello world\n"))))
+				Expect(envelopes[2]).To(Equal(envelope(stderr("a", "oops\nmore\n"))))
+				Expect(envelopes[3]).To(Equal(envelope(stdout("a", "again\n"))))
+				Expect(envelopes[5]).To(Equal(envelope(stdout("b", "xy"))))
+			})
+
+			It("replays identical output", func() {
+				before := replay()
+
+				err := buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				after := replay()
+				Expect(len(after)).To(BeNumerically("<", len(before)))
+				Expect(render(after)).To(Equal(render(before)))
+				Expect(after[0]).To(Equal(before[0]))
+				Expect(after[len(after)-1]).To(Equal(before[len(before)-1]))
+			})
+
+			It("renumbers the remaining events contiguously", func() {
+				err := buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				rows, err := psql.Select("event_id").
+					From(fmt.Sprintf("team_build_events_%d", team.ID())).
+					Where(sq.Eq{"build_id": build.ID()}).
+					OrderBy("event_id ASC").
+					RunWith(dbConn).
+					Query()
+				Expect(err).NotTo(HaveOccurred())
+
+				defer rows.Close()
+
+				eventIDs := []int{}
+				for rows.Next() {
+					var eventID int
+					Expect(rows.Scan(&eventID)).To(Succeed())
+					eventIDs = append(eventIDs, eventID)
+				}
+
+				Expect(eventIDs).To(Equal([]int{0, 1, 2, 3, 4, 5, 6}))
+			})
+
+			It("is a no-op when run again", func() {
+				err := buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				compacted := replay()
+
+				err = buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(replay()).To(Equal(compacted))
+			})
+		})
+
+		Context("when the build has not finished", func() {
+			It("returns ErrBuildNotFinished and leaves the events alone", func() {
+				err := buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).To(Equal(dbng.ErrBuildNotFinished))
+
+				var count int
+				err = psql.Select("COUNT(*)").
+					From(fmt.Sprintf("team_build_events_%d", team.ID())).
+					Where(sq.Eq{"build_id": build.ID()}).
+					RunWith(dbConn).
+					QueryRow().
+					Scan(&count)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(count).To(Equal(9))
+			})
+
+			It("streams its events without locking the build", func() {
+				tx, err := dbConn.Begin()
+				Expect(err).NotTo(HaveOccurred())
+
+				defer tx.Rollback()
+
+				_, err = tx.Exec(`SELECT 1 FROM builds WHERE id = $1 FOR UPDATE`, build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				events, err := build.Events(0)
+				Expect(err).NotTo(HaveOccurred())
+
+				defer events.Close()
+
+				received := make(chan event.Envelope, 1)
+				go func() {
+					ev, err := events.Next()
+					if err == nil {
+						received <- ev
+					}
+				}()
+
+				Eventually(received).Should(Receive())
+			})
+		})
+
+		Context("when the events are compacted while being streamed", func() {
+			BeforeEach(func() {
+				insert := psql.Insert(fmt.Sprintf("team_build_events_%d", team.ID())).
+					Columns("event_id", "build_id", "type", "version", "payload")
+
+				// enough output that can't be coalesced for the events to be
+				// streamed in a few batches
+				for i := 0; i < 4000; i++ {
+					payload, err := json.Marshal(stdout(fmt.Sprintf("line-%d", i%2), "line\n"))
+					Expect(err).NotTo(HaveOccurred())
+
+					insert = insert.Values(
+						sq.Expr(fmt.Sprintf("nextval('build_event_id_seq_%d')", build.ID())),
+						build.ID(),
+						string(event.EventTypeLog),
+						string(event.Log{}.Version()),
+						string(payload),
+					)
+				}
+
+				_, err := insert.RunWith(dbConn).Exec()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = build.Finish(dbng.BuildStatusSucceeded)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("resumes after the events it already sent, signaling that it resynced", func() {
+				before := replay()
+
+				events, err := build.Events(0)
+				Expect(err).NotTo(HaveOccurred())
+
+				defer events.Close()
+
+				// ensure the first batch was read before compacting
+				first, err := events.Next()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = buildFactory.CompactBuildEvents(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				streamed := []event.Envelope{first}
+				resynced := 0
+				for {
+					ev, err := events.Next()
+					if err == dbng.ErrEndOfBuildEventStream {
+						break
+					}
+					Expect(err).NotTo(HaveOccurred())
+
+					if ev.Event == event.EventTypeResynced {
+						resynced++
+						continue
+					}
+
+					streamed = append(streamed, ev)
+				}
+
+				Expect(resynced).To(Equal(1))
+				Expect(streamed).To(Equal(before))
+			})
+		})
+	})
+
+	Describe("GetBuildTimeline", func() {
+		var build dbng.Build
+
+		task := func(id string, name string, attempts ...int) atc.Plan {
+			return atc.Plan{
+				ID:       atc.PlanID(id),
+				Attempts: attempts,
+				Task:     &atc.TaskPlan{Name: name},
+			}
+		}
+
+		origin := func(id string) event.Origin {
+			return event.Origin{ID: event.OriginID(id)}
+		}
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			plan := atc.Plan{
+				ID: "do",
+				Do: &atc.DoPlan{
+					{
+						ID: "retry",
+						Retry: &atc.RetryPlan{
+							task("unit-1", "unit", 1),
+							task("unit-2", "unit", 2),
+						},
+					},
+					task("integration", "integration"),
+				},
+			}
+
+			metadata, err := json.Marshal(map[string]atc.Plan{"Plan": plan})
+			Expect(err).NotTo(HaveOccurred())
+
+			started, err := build.Start("exec.v2", string(metadata))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			for _, ev := range []atc.Event{
+				event.TaskStage{Stage: "worker-chosen", Time: 100, Origin: origin("unit-1")},
+				event.StartTask{Time: 105, Origin: origin("unit-1")},
+				event.Log{Origin: event.Origin{ID: "unit-1", Source: event.OriginSourceStdout}, Payload: "FAIL\n"},
+				event.FinishTask{Time: 110, ExitStatus: 1, Origin: origin("unit-1")},
+				event.TaskStage{Stage: "worker-chosen", Time: 111, Origin: origin("unit-2")},
+				event.StartTask{Time: 112, Origin: origin("unit-2")},
+				event.FinishTask{Time: 120, ExitStatus: 0, Origin: origin("unit-2")},
+				event.StartTask{Time: 121, Origin: origin("integration")},
+			} {
+				err = build.SaveEvent(ev)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("reconstructs each step's timing from its events", func() {
+			timeline, err := buildFactory.GetBuildTimeline(build.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			finish := func(at int64) *time.Time {
+				t := time.Unix(at, 0)
+				return &t
+			}
+
+			Expect(timeline).To(Equal([]dbng.StepTiming{
+				{
+					PlanID:  "unit-1",
+					Name:    "unit",
+					Attempt: 1,
+					Start:   time.Unix(100, 0),
+					Finish:  finish(110),
+					Status:  dbng.BuildStatusFailed,
+				},
+				{
+					PlanID:  "unit-2",
+					Name:    "unit",
+					Attempt: 2,
+					Start:   time.Unix(111, 0),
+					Finish:  finish(120),
+					Status:  dbng.BuildStatusSucceeded,
+				},
+				{
+					PlanID:  "integration",
+					Name:    "integration",
+					Attempt: 1,
+					Start:   time.Unix(121, 0),
+					Status:  dbng.BuildStatusStarted,
+				},
+			}))
+		})
+
+		Context("when a running step errors", func() {
+			BeforeEach(func() {
+				err := build.SaveEvent(event.Error{Message: "boom", Origin: origin("integration")})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("marks the step as errored", func() {
+				timeline, err := buildFactory.GetBuildTimeline(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(timeline).To(HaveLen(3))
+				Expect(timeline[2].Status).To(Equal(dbng.BuildStatusErrored))
+				Expect(timeline[2].Finish).To(BeNil())
+			})
+		})
+
+		Context("when the build does not exist", func() {
+			It("returns ErrBuildDisappeared", func() {
+				_, err := buildFactory.GetBuildTimeline(build.ID() + 100)
+				Expect(err).To(Equal(dbng.ErrBuildDisappeared))
+			})
+		})
+	})
+
+	Describe("GetStepEvents", func() {
+		var build dbng.Build
+
+		task := func(id string, name string, attempts ...int) atc.Plan {
+			return atc.Plan{
+				ID:       atc.PlanID(id),
+				Attempts: attempts,
+				Task:     &atc.TaskPlan{Name: name},
+			}
+		}
+
+		stdout := func(id string, payload string) event.Log {
+			return event.Log{
+				Origin:  event.Origin{ID: event.OriginID(id), Source: event.OriginSourceStdout},
+				Payload: payload,
+			}
+		}
+
+		// renders each event as its origin and, for logs, its payload
+		stepEvents := func(stepName string, attempt []int, from uint) []string {
+			events, err := buildFactory.GetStepEvents(build.ID(), stepName, attempt, from)
+			Expect(err).NotTo(HaveOccurred())
+
+			defer events.Close()
+
+			rendered := []string{}
+			for {
+				ev, err := events.Next()
+				if err == dbng.ErrEndOfBuildEventStream {
+					return rendered
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				var payload struct {
+					Origin  event.Origin `json:"origin"`
+					Payload string       `json:"payload"`
+				}
+
+				err = json.Unmarshal(*ev.Data, &payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				rendered = append(rendered, fmt.Sprintf("%s %s %s", payload.Origin.ID, ev.Event, payload.Payload))
+			}
+		}
+
+		// starts the build with a plan of tasks and saves their events
+		runBuild := func(b dbng.Build) {
+			plan := atc.Plan{
+				ID: "do",
+				Do: &atc.DoPlan{
+					{
+						ID: "retry",
+						Retry: &atc.RetryPlan{
+							task("unit-1", "unit", 1),
+							task("unit-2", "unit", 2),
+						},
+					},
+					task("integration", "integration"),
+				},
+			}
+
+			metadata, err := json.Marshal(map[string]atc.Plan{"Plan": plan})
+			Expect(err).NotTo(HaveOccurred())
+
+			started, err := b.Start("exec.v2", string(metadata))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			for _, ev := range []atc.Event{
+				event.StartTask{Time: 100, Origin: event.Origin{ID: "unit-1"}},
+				stdout("unit-1", "unit 1\n"),
+				stdout("integration", "integration\n"),
+				stdout("unit-2", "unit 2\n"),
+				stdout("unit-1", "unit 1 again\n"),
+			} {
+				err = b.SaveEvent(ev)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err = b.Finish(dbng.BuildStatusSucceeded)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			runBuild(build)
+		})
+
+		It("streams only the events of the named step's attempts", func() {
+			Expect(stepEvents("unit", nil, 0)).To(Equal([]string{
+				"unit-1 start-task ",
+				"unit-1 log unit 1\n",
+				"unit-2 log unit 2\n",
+				"unit-1 log unit 1 again\n",
+			}))
+		})
+
+		It("streams only the given attempt's events", func() {
+			Expect(stepEvents("unit", []int{2}, 0)).To(Equal([]string{
+				"unit-2 log unit 2\n",
+			}))
+		})
+
+		It("resumes from an offset into the step's events", func() {
+			Expect(stepEvents("unit", []int{1}, 1)).To(Equal([]string{
+				"unit-1 log unit 1\n",
+				"unit-1 log unit 1 again\n",
+			}))
+		})
+
+		It("streams nothing for a step not in the plan", func() {
+			Expect(stepEvents("bogus", nil, 0)).To(BeEmpty())
+		})
+
+		Context("when the build belongs to a pipeline", func() {
+			BeforeEach(func() {
+				var err error
+				build, err = defaultPipeline.CreateJobBuild("some-job")
+				Expect(err).NotTo(HaveOccurred())
+
+				runBuild(build)
+			})
+
+			It("streams the step's events from the pipeline's events", func() {
+				Expect(stepEvents("unit", []int{2}, 0)).To(Equal([]string{
+					"unit-2 log unit 2\n",
+				}))
+			})
+		})
+
+		Context("when the build does not exist", func() {
+			It("returns ErrBuildDisappeared", func() {
+				_, err := buildFactory.GetStepEvents(build.ID()+100, "unit", nil, 0)
+				Expect(err).To(Equal(dbng.ErrBuildDisappeared))
+			})
+		})
+	})
+
+	Describe("GetBuildResourceUsage", func() {
+		var build dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns zero usage for a build with nothing recorded", func() {
+			usage, err := buildFactory.GetBuildResourceUsage(build.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage).To(Equal(dbng.ResourceUsage{}))
+		})
+
+		Context("when usage has been recorded for each container", func() {
+			BeforeEach(func() {
+				err := buildFactory.RecordBuildResourceUsage(build.ID(), 1.5, 1024)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = buildFactory.RecordBuildResourceUsage(build.ID(), 2, 2048)
+				Expect(err).NotTo(HaveOccurred())
+
+				otherBuild, err := team.CreateOneOffBuild()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = buildFactory.RecordBuildResourceUsage(otherBuild.ID(), 100, 100)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("totals the usage of the build's containers", func() {
+				usage, err := buildFactory.GetBuildResourceUsage(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(usage).To(Equal(dbng.ResourceUsage{
+					CPUSeconds:        3.5,
+					MemoryByteSeconds: 3072,
+				}))
+			})
+
+			Context("when one of the build's containers could not be measured", func() {
+				BeforeEach(func() {
+					err := buildFactory.RecordUnmeasuredBuildResourceUsage(build.ID())
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("flags the usage as incomplete without affecting the totals", func() {
+					usage, err := buildFactory.GetBuildResourceUsage(build.ID())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(usage).To(Equal(dbng.ResourceUsage{
+						CPUSeconds:        3.5,
+						MemoryByteSeconds: 3072,
+						Incomplete:        true,
+					}))
+				})
+			})
+		})
+	})
 })
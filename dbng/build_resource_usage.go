@@ -0,0 +1,60 @@
+package dbng
+
+import (
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ResourceUsage is the compute consumed by all of a build's containers.
+type ResourceUsage struct {
+	CPUSeconds        float64
+	MemoryByteSeconds float64
+
+	// Incomplete is true if any of the build's containers ran on a worker
+	// that could not report its usage, in which case it contributed nothing to
+	// the totals.
+	Incomplete bool
+}
+
+// RecordBuildResourceUsage adds the usage of one of the build's containers to
+// the build's totals.
+func (f *buildFactory) RecordBuildResourceUsage(buildID int, cpuSeconds float64, memByteSeconds float64) error {
+	_, err := psql.Insert("build_resource_usage").
+		Columns("build_id", "cpu_seconds", "memory_byte_seconds").
+		Values(buildID, cpuSeconds, memByteSeconds).
+		RunWith(f.conn).
+		Exec()
+	return err
+}
+
+// RecordUnmeasuredBuildResourceUsage notes that one of the build's containers
+// could not report its usage, so the build's totals are incomplete.
+func (f *buildFactory) RecordUnmeasuredBuildResourceUsage(buildID int) error {
+	_, err := psql.Insert("build_resource_usage").
+		Columns("build_id", "measured").
+		Values(buildID, false).
+		RunWith(f.conn).
+		Exec()
+	return err
+}
+
+// GetBuildResourceUsage totals the usage recorded for each of the build's
+// containers.
+func (f *buildFactory) GetBuildResourceUsage(buildID int) (ResourceUsage, error) {
+	var usage ResourceUsage
+
+	err := psql.Select(
+		"COALESCE(SUM(cpu_seconds), 0)",
+		"COALESCE(SUM(memory_byte_seconds), 0)",
+		"COALESCE(bool_or(NOT measured), false)",
+	).
+		From("build_resource_usage").
+		Where(sq.Eq{"build_id": buildID}).
+		RunWith(f.conn).
+		QueryRow().
+		Scan(&usage.CPUSeconds, &usage.MemoryByteSeconds, &usage.Incomplete)
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,163 @@
+package dbng
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+)
+
+// GetStepEvents streams the events of the steps in the build's plan with the
+// given name. If attempt is given, only the step run for that attempt is
+// included; otherwise every attempt is.
+//
+// From counts events of the matching steps only, so a client can resume the
+// filtered stream from the last event it saw.
+func (f *buildFactory) GetStepEvents(buildID int, stepName string, attempt []int, from uint) (EventSource, error) {
+	var (
+		teamID         int
+		pipelineID     sql.NullInt64
+		engineMetadata sql.NullString
+	)
+
+	err := psql.Select("b.team_id", "j.pipeline_id", "b.engine_metadata").
+		From("builds b").
+		JoinClause("LEFT OUTER JOIN jobs j ON b.job_id = j.id").
+		Where(sq.Eq{"b.id": buildID}).
+		RunWith(f.conn).
+		QueryRow().
+		Scan(&teamID, &pipelineID, &engineMetadata)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrBuildDisappeared
+		}
+		return nil, err
+	}
+
+	planSteps := map[atc.PlanID]atc.Plan{}
+
+	var metadata struct {
+		Plan atc.Plan
+	}
+
+	if json.Unmarshal([]byte(engineMetadata.String), &metadata) == nil {
+		collectPlanSteps(metadata.Plan, planSteps)
+	}
+
+	origins := map[event.OriginID]bool{}
+	for id, plan := range planSteps {
+		name := planStepName(plan)
+		if name == "" || name != stepName {
+			continue
+		}
+
+		if len(attempt) == 0 || sameAttempt(plan.Attempts, attempt) {
+			origins[event.OriginID(id)] = true
+		}
+	}
+
+	notifier, err := newConditionNotifier(f.conn.Bus(), buildEventsChannel(buildID), func() (bool, error) {
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stepEventSource{
+		source: newBuildEventSource(
+			buildID,
+			buildEventsTable(teamID, int(pipelineID.Int64)),
+			f.conn,
+			notifier,
+			0,
+		),
+		origins: origins,
+		skip:    from,
+	}, nil
+}
+
+type stepEventSource struct {
+	source  EventSource
+	origins map[event.OriginID]bool
+	skip    uint
+}
+
+func (source *stepEventSource) Next() (event.Envelope, error) {
+	for {
+		ev, err := source.source.Next()
+		if err != nil {
+			return event.Envelope{}, err
+		}
+
+		// offsets into the filtered stream don't correspond to the offsets
+		// the marker carries, so it's of no use to a client
+		if ev.Event == event.EventTypeResynced {
+			continue
+		}
+
+		if !source.matches(ev) {
+			continue
+		}
+
+		if source.skip > 0 {
+			source.skip--
+			continue
+		}
+
+		return ev, nil
+	}
+}
+
+func (source *stepEventSource) Close() error {
+	return source.source.Close()
+}
+
+func (source *stepEventSource) matches(ev event.Envelope) bool {
+	if ev.Data == nil {
+		return false
+	}
+
+	var payload struct {
+		Origin struct {
+			ID event.OriginID `json:"id"`
+		} `json:"origin"`
+	}
+
+	err := json.Unmarshal(*ev.Data, &payload)
+	if err != nil {
+		return false
+	}
+
+	return source.origins[payload.Origin.ID]
+}
+
+func planStepName(plan atc.Plan) string {
+	switch {
+	case plan.Task != nil:
+		return plan.Task.Name
+	case plan.Get != nil:
+		return plan.Get.Name
+	case plan.Put != nil:
+		return plan.Put.Name
+	case plan.DependentGet != nil:
+		return plan.DependentGet.Name
+	default:
+		return ""
+	}
+}
+
+func sameAttempt(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
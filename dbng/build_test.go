@@ -133,6 +133,62 @@ var _ = Describe("Build", func() {
 		})
 	})
 
+	Describe("AbortPending", func() {
+		var build dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the build is pending", func() {
+			It("aborts it, saving the reason as an error event", func() {
+				aborted, err := build.AbortPending("some-reason")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(aborted).To(BeTrue())
+
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.Status()).To(Equal(dbng.BuildStatusAborted))
+
+				events, err := build.Events(0)
+				Expect(err).NotTo(HaveOccurred())
+
+				defer events.Close()
+
+				Expect(events.Next()).To(Equal(envelope(event.Error{
+					Message: "some-reason",
+				})))
+
+				Expect(events.Next()).To(Equal(envelope(event.Status{
+					Status: atc.StatusAborted,
+					Time:   build.EndTime().Unix(),
+				})))
+			})
+		})
+
+		Context("when the build has already started", func() {
+			BeforeEach(func() {
+				started, err := build.Start("engine", "metadata")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(started).To(BeTrue())
+			})
+
+			It("leaves it alone", func() {
+				aborted, err := build.AbortPending("some-reason")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(aborted).To(BeFalse())
+
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.Status()).To(Equal(dbng.BuildStatusStarted))
+			})
+		})
+	})
+
 	Describe("Events", func() {
 		It("saves and emits status events", func() {
 			build, err := team.CreateOneOffBuild()
@@ -890,6 +946,100 @@ var _ = Describe("Build", func() {
 		})
 	})
 
+	Describe("ImageResourceVersion", func() {
+		var build dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the version first saved for the image resource by any plan", func() {
+			err := build.SaveImageResourceVersion(atc.PlanID("some-plan"), atc.Version{"ref": "v1"}, "some-hash")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveImageResourceVersion(atc.PlanID("some-other-plan"), atc.Version{"ref": "v2"}, "some-hash")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveImageResourceVersion(atc.PlanID("some-third-plan"), atc.Version{"ref": "v3"}, "some-other-hash")
+			Expect(err).NotTo(HaveOccurred())
+
+			version, found, err := build.ImageResourceVersion("some-hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(version).To(Equal(atc.Version{"ref": "v1"}))
+
+			version, found, err = build.ImageResourceVersion("some-other-hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(version).To(Equal(atc.Version{"ref": "v3"}))
+		})
+
+		It("returns false when no version was saved for the image resource", func() {
+			_, found, err := build.ImageResourceVersion("some-hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("does not return versions saved by other builds", func() {
+			otherBuild, err := team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = otherBuild.SaveImageResourceVersion(atc.PlanID("some-plan"), atc.Version{"ref": "v1"}, "some-hash")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := build.ImageResourceVersion("some-hash")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("SetMetadata", func() {
+		var build dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("saves the metadata", func() {
+			err := build.SetMetadata("some-output", json.RawMessage(`{"coverage":87.5}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			metadata, err := build.Metadata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata).To(HaveLen(1))
+			Expect(metadata["some-output"]).To(MatchJSON(`{"coverage":87.5}`))
+		})
+
+		It("replaces metadata previously saved under the same name", func() {
+			err := build.SetMetadata("some-output", json.RawMessage(`{"coverage":87.5}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SetMetadata("some-output", json.RawMessage(`{"coverage":90}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			metadata, err := build.Metadata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata).To(HaveLen(1))
+			Expect(metadata["some-output"]).To(MatchJSON(`{"coverage":90}`))
+		})
+
+		It("does not leak into other builds", func() {
+			err := build.SetMetadata("some-output", json.RawMessage(`{"coverage":87.5}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			otherBuild, err := team.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			metadata, err := otherBuild.Metadata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata).To(BeEmpty())
+		})
+	})
+
 	Describe("Resources", func() {
 		It("can get (no) resources from a one-off build", func() {
 			oneOffBuild, err := team.CreateOneOffBuild()
@@ -999,6 +1149,56 @@ var _ = Describe("Build", func() {
 		})
 	})
 
+	Describe("PinInputs", func() {
+		var build dbng.Build
+		var savedVersion dbng.SavedVersionedResource
+
+		BeforeEach(func() {
+			pipeline, _, err := team.SavePipeline("some-pipeline", atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "some-job"},
+				},
+				Resources: atc.ResourceConfigs{
+					{Name: "some-resource", Type: "some-type"},
+				},
+			}, dbng.ConfigVersion(1), dbng.PipelineUnpaused)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = pipeline.SaveResourceVersions(atc.ResourceConfig{
+				Name: "some-resource",
+				Type: "some-type",
+			}, []atc.Version{{"ref": "v1"}})
+			Expect(err).ToNot(HaveOccurred())
+
+			var found bool
+			savedVersion, found, err = pipeline.GetLatestVersionedResource("some-resource")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			build, err = pipeline.CreateJobBuild("some-job")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("saves the mapping as the build's inputs and marks them as pinned", func() {
+			err := build.PinInputs(algorithm.InputMapping{
+				"some-input": algorithm.InputVersion{VersionID: savedVersion.ID},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(build.InputsPinned()).To(BeTrue())
+
+			found, err := build.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(build.InputsPinned()).To(BeTrue())
+
+			inputs, _, err := build.Resources()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inputs).To(HaveLen(1))
+			Expect(inputs[0].Name).To(Equal("some-input"))
+			Expect(inputs[0].VersionedResource.Version).To(Equal(dbng.ResourceVersion{"ref": "v1"}))
+		})
+	})
+
 	Describe("MarkAsFailed", func() {
 		var cause error
 		var build dbng.Build
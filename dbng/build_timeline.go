@@ -0,0 +1,225 @@
+package dbng
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/event"
+)
+
+// StepTiming is when a single step of a build ran. Only task steps are
+// included, as get and put events do not carry timestamps.
+//
+// Start is the first time the step was seen doing anything, e.g. choosing a
+// worker. Finish is nil while the step is still running, and also for steps
+// that errored without their process ever exiting.
+type StepTiming struct {
+	PlanID  atc.PlanID
+	Name    string
+	Attempt int
+	Start   time.Time
+	Finish  *time.Time
+	Status  BuildStatus
+}
+
+func (f *buildFactory) GetBuildTimeline(buildID int) ([]StepTiming, error) {
+	// timelines are history lookups, so read from the replica if there is one
+	readConn := f.conn.ReadConn()
+
+	var (
+		teamID         int
+		pipelineID     sql.NullInt64
+		engineMetadata sql.NullString
+	)
+
+	err := psql.Select("b.team_id", "j.pipeline_id", "b.engine_metadata").
+		From("builds b").
+		JoinClause("LEFT OUTER JOIN jobs j ON b.job_id = j.id").
+		Where(sq.Eq{"b.id": buildID}).
+		RunWith(readConn).
+		QueryRow().
+		Scan(&teamID, &pipelineID, &engineMetadata)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrBuildDisappeared
+		}
+		return nil, err
+	}
+
+	planSteps := map[atc.PlanID]atc.Plan{}
+
+	var metadata struct {
+		Plan atc.Plan
+	}
+
+	// builds run by engines other than exec store no plan, in which case the
+	// steps are still timed but left unnamed
+	if json.Unmarshal([]byte(engineMetadata.String), &metadata) == nil {
+		collectPlanSteps(metadata.Plan, planSteps)
+	}
+
+	rows, err := psql.Select("type", "version", "payload").
+		From(buildEventsTable(teamID, int(pipelineID.Int64))).
+		Where(sq.Eq{"build_id": buildID}).
+		OrderBy("event_id ASC").
+		RunWith(readConn).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	timeline := &buildTimeline{
+		planSteps: planSteps,
+		steps:     map[event.OriginID]*StepTiming{},
+	}
+
+	for rows.Next() {
+		var eventType, version, payload string
+		err = rows.Scan(&eventType, &version, &payload)
+		if err != nil {
+			return nil, err
+		}
+
+		ev, err := event.ParseEvent(atc.EventVersion(version), atc.EventType(eventType), []byte(payload))
+		if err != nil {
+			// events from older versions have a different notion of origin
+			continue
+		}
+
+		timeline.observe(ev)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	timings := []StepTiming{}
+	for _, id := range timeline.order {
+		timings = append(timings, *timeline.steps[id])
+	}
+
+	return timings, nil
+}
+
+type buildTimeline struct {
+	planSteps map[atc.PlanID]atc.Plan
+
+	order []event.OriginID
+	steps map[event.OriginID]*StepTiming
+}
+
+func (timeline *buildTimeline) observe(ev atc.Event) {
+	switch e := ev.(type) {
+	case event.TaskStage:
+		timeline.started(e.Origin.ID, e.Time)
+
+	case event.StartTask:
+		timeline.started(e.Origin.ID, e.Time)
+
+	case event.FinishTask:
+		step := timeline.started(e.Origin.ID, e.Time)
+
+		finish := time.Unix(e.Time, 0)
+		step.Finish = &finish
+
+		if e.ExitStatus == 0 {
+			step.Status = BuildStatusSucceeded
+		} else {
+			step.Status = BuildStatusFailed
+		}
+
+	case event.Error:
+		step, found := timeline.steps[e.Origin.ID]
+		if found {
+			step.Status = BuildStatusErrored
+		}
+	}
+}
+
+func (timeline *buildTimeline) started(id event.OriginID, at int64) *StepTiming {
+	start := time.Unix(at, 0)
+
+	step, found := timeline.steps[id]
+	if found {
+		if start.Before(step.Start) {
+			step.Start = start
+		}
+
+		return step
+	}
+
+	step = &StepTiming{
+		PlanID:  atc.PlanID(id),
+		Attempt: 1,
+		Start:   start,
+		Status:  BuildStatusStarted,
+	}
+
+	plan, found := timeline.planSteps[atc.PlanID(id)]
+	if found {
+		if plan.Task != nil {
+			step.Name = plan.Task.Name
+		}
+
+		if len(plan.Attempts) > 0 {
+			step.Attempt = plan.Attempts[len(plan.Attempts)-1]
+		}
+	}
+
+	timeline.order = append(timeline.order, id)
+	timeline.steps[id] = step
+
+	return step
+}
+
+func collectPlanSteps(plan atc.Plan, steps map[atc.PlanID]atc.Plan) {
+	steps[plan.ID] = plan
+
+	var children []atc.Plan
+
+	if plan.Aggregate != nil {
+		children = append(children, *plan.Aggregate...)
+	}
+
+	if plan.Do != nil {
+		children = append(children, *plan.Do...)
+	}
+
+	if plan.Retry != nil {
+		children = append(children, *plan.Retry...)
+	}
+
+	if plan.Ensure != nil {
+		children = append(children, plan.Ensure.Step, plan.Ensure.Next)
+	}
+
+	if plan.OnSuccess != nil {
+		children = append(children, plan.OnSuccess.Step, plan.OnSuccess.Next)
+	}
+
+	if plan.OnFailure != nil {
+		children = append(children, plan.OnFailure.Step, plan.OnFailure.Next)
+	}
+
+	if plan.Try != nil {
+		children = append(children, plan.Try.Step)
+	}
+
+	if plan.Timeout != nil {
+		children = append(children, plan.Timeout.Step)
+	}
+
+	if plan.Staged != nil {
+		children = append(children, plan.Staged.Step)
+	}
+
+	for _, child := range children {
+		collectPlanSteps(child, steps)
+	}
+}
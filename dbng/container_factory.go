@@ -1,11 +1,40 @@
 package dbng
 
-import sq "github.com/Masterminds/squirrel"
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/concourse/atc"
+)
 
 //go:generate counterfeiter . ContainerFactory
 
 type ContainerFactory interface {
 	FindContainersForDeletion() ([]CreatingContainer, []CreatedContainer, []DestroyingContainer, error)
+	FindEvacuatableContainers(workerName string) ([]EvacuatableContainer, error)
+
+	RecordContainerPlacement(handle string, workerName string, buildID int, planID atc.PlanID) error
+	GetContainerHistory(buildID int) ([]ContainerPlacement, error)
+}
+
+// ContainerPlacement records which worker a build step's container was
+// created on. Placements outlive the containers themselves, so they can be
+// used to find where a build ran after its containers have been reaped.
+type ContainerPlacement struct {
+	Handle     string
+	WorkerName string
+	BuildID    int
+	PlanID     atc.PlanID
+	CreatedAt  time.Time
+}
+
+// EvacuatableContainer is a created task container along with the build step
+// it belongs to, which is needed to recreate it on another worker.
+type EvacuatableContainer struct {
+	Container CreatedContainer
+	TeamID    int
+	BuildID   int
+	PlanID    atc.PlanID
 }
 
 type containerFactory struct {
@@ -74,6 +103,83 @@ func (factory *containerFactory) FindContainersForDeletion() ([]CreatingContaine
 	return creatingContainers, createdContainers, destroyingContainers, nil
 }
 
+func (factory *containerFactory) FindEvacuatableContainers(workerName string) ([]EvacuatableContainer, error) {
+	query, args, err := selectContainers("c").
+		Columns("c.team_id", "c.build_id", "c.plan_id").
+		Where(sq.Eq{
+			"c.worker_name": workerName,
+			"c.state":       ContainerStateCreated,
+			"c.meta_type":   string(ContainerTypeTask),
+		}).
+		Where(sq.NotEq{
+			"c.build_id": nil,
+			"c.plan_id":  nil,
+		}).
+		Where(sq.Expr(
+			"EXISTS (SELECT 1 FROM volumes v WHERE v.container_id = c.id AND v.state = ? AND v.path != '/')",
+			VolumeStateCreated,
+		)).
+		OrderBy("c.id ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := factory.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	containers := []EvacuatableContainer{}
+
+	for rows.Next() {
+		var (
+			id              int
+			handle          string
+			containerWorker string
+			isDiscontinued  bool
+			isHijacked      bool
+			state           string
+			metadata        ContainerMetadata
+
+			teamID  int
+			buildID int
+			planID  string
+		)
+
+		columns := []interface{}{&id, &handle, &containerWorker, &isHijacked, &isDiscontinued, &state}
+		columns = append(columns, metadata.ScanTargets()...)
+		columns = append(columns, &teamID, &buildID, &planID)
+
+		err = rows.Scan(columns...)
+		if err != nil {
+			return nil, err
+		}
+
+		containers = append(containers, EvacuatableContainer{
+			Container: newCreatedContainer(
+				id,
+				handle,
+				containerWorker,
+				metadata,
+				isHijacked,
+				factory.conn,
+			),
+			TeamID:  teamID,
+			BuildID: buildID,
+			PlanID:  atc.PlanID(planID),
+		})
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return containers, nil
+}
+
 func selectContainers(asOptional ...string) sq.SelectBuilder {
 	columns := []string{"id", "handle", "worker_name", "hijacked", "discontinued", "state"}
 	columns = append(columns, containerMetadataColumns...)
@@ -143,3 +249,50 @@ func scanContainer(row sq.RowScanner, conn Conn) (CreatingContainer, CreatedCont
 
 	return nil, nil, nil, nil
 }
+
+func (factory *containerFactory) RecordContainerPlacement(handle string, workerName string, buildID int, planID atc.PlanID) error {
+	_, err := psql.Insert("container_history").
+		Columns("handle", "worker_name", "build_id", "plan_id").
+		Values(handle, workerName, buildID, string(planID)).
+		RunWith(factory.conn).
+		Exec()
+	return err
+}
+
+func (factory *containerFactory) GetContainerHistory(buildID int) ([]ContainerPlacement, error) {
+	// history lookups read from the replica if there is one
+	rows, err := psql.Select("handle", "worker_name", "build_id", "plan_id", "created_at").
+		From("container_history").
+		Where(sq.Eq{"build_id": buildID}).
+		OrderBy("id ASC").
+		RunWith(factory.conn.ReadConn()).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	placements := []ContainerPlacement{}
+
+	for rows.Next() {
+		var placement ContainerPlacement
+		var planID string
+
+		err = rows.Scan(&placement.Handle, &placement.WorkerName, &placement.BuildID, &planID, &placement.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		placement.PlanID = atc.PlanID(planID)
+
+		placements = append(placements, placement)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return placements, nil
+}
@@ -417,4 +417,66 @@ var _ = Describe("ContainerFactory", func() {
 			})
 		})
 	})
+
+	Describe("container history", func() {
+		var build dbng.Build
+		var otherBuild dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			build, err = defaultTeam.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			otherBuild, err = defaultTeam.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			creatingContainer, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), build.ID(), atc.PlanID("some-plan"), dbng.ContainerMetadata{Type: "task"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = containerFactory.RecordContainerPlacement(creatingContainer.Handle(), defaultWorker.Name(), build.ID(), atc.PlanID("some-plan"))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = containerFactory.RecordContainerPlacement("other-handle", "other-worker", build.ID(), atc.PlanID("other-plan"))
+			Expect(err).NotTo(HaveOccurred())
+
+			err = containerFactory.RecordContainerPlacement("other-build-handle", "other-worker", otherBuild.ID(), atc.PlanID("some-plan"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the build's placements in the order they were recorded", func() {
+			placements, err := containerFactory.GetContainerHistory(build.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(placements).To(HaveLen(2))
+
+			Expect(placements[0].WorkerName).To(Equal(defaultWorker.Name()))
+			Expect(placements[0].BuildID).To(Equal(build.ID()))
+			Expect(placements[0].PlanID).To(Equal(atc.PlanID("some-plan")))
+			Expect(placements[0].CreatedAt).NotTo(BeZero())
+
+			Expect(placements[1].Handle).To(Equal("other-handle"))
+			Expect(placements[1].WorkerName).To(Equal("other-worker"))
+			Expect(placements[1].PlanID).To(Equal(atc.PlanID("other-plan")))
+		})
+
+		It("keeps the placements after the containers are gone", func() {
+			_, err := psql.Delete("containers").
+				Where(sq.Eq{"build_id": build.ID()}).
+				RunWith(dbConn).
+				Exec()
+			Expect(err).NotTo(HaveOccurred())
+
+			placements, err := containerFactory.GetContainerHistory(build.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(placements).To(HaveLen(2))
+		})
+
+		It("returns nothing for a build without placements", func() {
+			build, err := defaultTeam.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			placements, err := containerFactory.GetContainerHistory(build.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(placements).To(BeEmpty())
+		})
+	})
 })
@@ -1,6 +1,10 @@
 package dbng
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type ContainerMetadata struct {
 	Type ContainerType
@@ -123,3 +127,82 @@ func (metadata *ContainerMetadata) ScanTargets() []interface{} {
 		&metadata.BuildName,
 	}
 }
+
+// Attempts returns the attempt the container was created for, e.g. []int{2, 1}
+// for the first attempt of a step nested in the second attempt of a retry. A
+// container that isn't part of a retry has no attempts.
+func (metadata ContainerMetadata) Attempts() ([]int, error) {
+	return ParseAttempt(metadata.Attempt)
+}
+
+func (metadata *ContainerMetadata) SetAttempts(attempts []int) {
+	metadata.Attempt = FormatAttempt(attempts)
+}
+
+type MalformedAttemptError struct {
+	Attempt string
+}
+
+func (err MalformedAttemptError) Error() string {
+	return fmt.Sprintf("malformed attempt '%s': expected dot-separated positive numbers, e.g. '2.1'", err.Attempt)
+}
+
+// FormatAttempt encodes attempts the way they're stored in meta_attempt, e.g.
+// "2.1".
+func FormatAttempt(attempts []int) string {
+	segments := make([]string, len(attempts))
+	for i, attempt := range attempts {
+		segments[i] = strconv.Itoa(attempt)
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// ParseAttempt decodes an attempt encoded by FormatAttempt. An empty attempt
+// decodes to no attempts.
+func ParseAttempt(attempt string) ([]int, error) {
+	attempts := []int{}
+	if attempt == "" {
+		return attempts, nil
+	}
+
+	for _, s := range strings.Split(attempt, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return nil, MalformedAttemptError{Attempt: attempt}
+		}
+
+		attempts = append(attempts, n)
+	}
+
+	return attempts, nil
+}
+
+// attemptLess reports whether attempt a (e.g. [1, 2]) came before attempt b.
+// Attempts are compared numerically, segment by segment, so that [10] sorts
+// after [9]. No attempt sorts before any other.
+func attemptLess(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// attemptHasPrefix reports whether attempt (e.g. [2, 1]) is the given attempt
+// or nested under it. An empty prefix matches every attempt.
+func attemptHasPrefix(attempt []int, prefix []int) bool {
+	if len(attempt) < len(prefix) {
+		return false
+	}
+
+	for i, n := range prefix {
+		if attempt[i] != n {
+			return false
+		}
+	}
+
+	return true
+}
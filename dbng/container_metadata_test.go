@@ -0,0 +1,66 @@
+package dbng_test
+
+import (
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/dbng"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContainerMetadata", func() {
+	Describe("Attempts", func() {
+		It("round-trips through SetAttempts", func() {
+			for _, attempts := range [][]int{{}, {1}, {2, 1}, {10, 3, 12}} {
+				var metadata dbng.ContainerMetadata
+				metadata.SetAttempts(attempts)
+
+				parsed, err := metadata.Attempts()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(attempts))
+			}
+		})
+
+		It("stores attempts in the existing dotted format", func() {
+			var metadata dbng.ContainerMetadata
+			metadata.SetAttempts([]int{2, 1})
+			Expect(metadata.Attempt).To(Equal("2.1"))
+		})
+
+		It("treats an empty attempt as no attempts", func() {
+			attempts, err := dbng.ContainerMetadata{}.Attempts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal([]int{}))
+		})
+
+		It("round-trips through the database", func() {
+			build, err := defaultTeam.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+
+			metadata := dbng.ContainerMetadata{Type: dbng.ContainerTypeTask, StepName: "some-task"}
+			metadata.SetAttempts([]int{3, 2})
+
+			creating, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), build.ID(), atc.PlanID("some-plan"), metadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = creating.Created()
+			Expect(err).NotTo(HaveOccurred())
+
+			container, found, err := defaultTeam.FindContainerByHandle(creating.Handle())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			attempts, err := container.Metadata().Attempts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts).To(Equal([]int{3, 2}))
+		})
+
+		It("returns a descriptive error for a malformed attempt", func() {
+			for _, malformed := range []string{"one", "1..2", "1.", "2.-1", "0", "[1,2]"} {
+				_, err := dbng.ContainerMetadata{Attempt: malformed}.Attempts()
+				Expect(err).To(Equal(dbng.MalformedAttemptError{Attempt: malformed}))
+				Expect(err.Error()).To(ContainSubstring("malformed attempt '" + malformed + "'"))
+			}
+		})
+	})
+})
@@ -2,11 +2,13 @@
 package dbngfakes
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
+	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/db/lock"
 	"github.com/concourse/atc/dbng"
 )
@@ -147,6 +149,15 @@ type FakeBuild struct {
 	isManuallyTriggeredReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	TriggerReasonStub        func() dbng.BuildTriggerReason
+	triggerReasonMutex       sync.RWMutex
+	triggerReasonArgsForCall []struct{}
+	triggerReasonReturns     struct {
+		result1 dbng.BuildTriggerReason
+	}
+	triggerReasonReturnsOnCall map[int]struct {
+		result1 dbng.BuildTriggerReason
+	}
 	IsScheduledStub        func() bool
 	isScheduledMutex       sync.RWMutex
 	isScheduledArgsForCall []struct{}
@@ -358,6 +369,29 @@ type FakeBuild struct {
 	saveImageResourceVersionReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SetMetadataStub        func(name string, value json.RawMessage) error
+	setMetadataMutex       sync.RWMutex
+	setMetadataArgsForCall []struct {
+		name  string
+		value json.RawMessage
+	}
+	setMetadataReturns struct {
+		result1 error
+	}
+	setMetadataReturnsOnCall map[int]struct {
+		result1 error
+	}
+	MetadataStub        func() (map[string]json.RawMessage, error)
+	metadataMutex       sync.RWMutex
+	metadataArgsForCall []struct{}
+	metadataReturns     struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}
+	metadataReturnsOnCall map[int]struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}
 	PipelineStub        func() (dbng.Pipeline, bool, error)
 	pipelineMutex       sync.RWMutex
 	pipelineArgsForCall []struct{}
@@ -402,6 +436,19 @@ type FakeBuild struct {
 	abortReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AbortPendingStub        func(reason string) (bool, error)
+	abortPendingMutex       sync.RWMutex
+	abortPendingArgsForCall []struct {
+		reason string
+	}
+	abortPendingReturns struct {
+		result1 bool
+		result2 error
+	}
+	abortPendingReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	AbortNotifierStub        func() (dbng.Notifier, error)
 	abortNotifierMutex       sync.RWMutex
 	abortNotifierArgsForCall []struct{}
@@ -424,6 +471,50 @@ type FakeBuild struct {
 		result1 bool
 		result2 error
 	}
+	OverridesStub        func() atc.BuildOverrides
+	overridesMutex       sync.RWMutex
+	overridesArgsForCall []struct{}
+	overridesReturns     struct {
+		result1 atc.BuildOverrides
+	}
+	overridesReturnsOnCall map[int]struct {
+		result1 atc.BuildOverrides
+	}
+	InputsPinnedStub        func() bool
+	inputsPinnedMutex       sync.RWMutex
+	inputsPinnedArgsForCall []struct{}
+	inputsPinnedReturns     struct {
+		result1 bool
+	}
+	inputsPinnedReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	PinInputsStub        func(inputMapping algorithm.InputMapping) error
+	pinInputsMutex       sync.RWMutex
+	pinInputsArgsForCall []struct {
+		inputMapping algorithm.InputMapping
+	}
+	pinInputsReturns struct {
+		result1 error
+	}
+	pinInputsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ImageResourceVersionStub        func(resourceHash string) (atc.Version, bool, error)
+	imageResourceVersionMutex       sync.RWMutex
+	imageResourceVersionArgsForCall []struct {
+		resourceHash string
+	}
+	imageResourceVersionReturns struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}
+	imageResourceVersionReturnsOnCall map[int]struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -1028,6 +1119,46 @@ func (fake *FakeBuild) IsManuallyTriggeredReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakeBuild) TriggerReason() dbng.BuildTriggerReason {
+	fake.triggerReasonMutex.Lock()
+	ret, specificReturn := fake.triggerReasonReturnsOnCall[len(fake.triggerReasonArgsForCall)]
+	fake.triggerReasonArgsForCall = append(fake.triggerReasonArgsForCall, struct{}{})
+	fake.recordInvocation("TriggerReason", []interface{}{})
+	fake.triggerReasonMutex.Unlock()
+	if fake.TriggerReasonStub != nil {
+		return fake.TriggerReasonStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.triggerReasonReturns.result1
+}
+
+func (fake *FakeBuild) TriggerReasonCallCount() int {
+	fake.triggerReasonMutex.RLock()
+	defer fake.triggerReasonMutex.RUnlock()
+	return len(fake.triggerReasonArgsForCall)
+}
+
+func (fake *FakeBuild) TriggerReasonReturns(result1 dbng.BuildTriggerReason) {
+	fake.TriggerReasonStub = nil
+	fake.triggerReasonReturns = struct {
+		result1 dbng.BuildTriggerReason
+	}{result1}
+}
+
+func (fake *FakeBuild) TriggerReasonReturnsOnCall(i int, result1 dbng.BuildTriggerReason) {
+	fake.TriggerReasonStub = nil
+	if fake.triggerReasonReturnsOnCall == nil {
+		fake.triggerReasonReturnsOnCall = make(map[int]struct {
+			result1 dbng.BuildTriggerReason
+		})
+	}
+	fake.triggerReasonReturnsOnCall[i] = struct {
+		result1 dbng.BuildTriggerReason
+	}{result1}
+}
+
 func (fake *FakeBuild) IsScheduled() bool {
 	fake.isScheduledMutex.Lock()
 	ret, specificReturn := fake.isScheduledReturnsOnCall[len(fake.isScheduledArgsForCall)]
@@ -1879,6 +2010,98 @@ func (fake *FakeBuild) SaveImageResourceVersionReturnsOnCall(i int, result1 erro
 	}{result1}
 }
 
+func (fake *FakeBuild) SetMetadata(name string, value json.RawMessage) error {
+	fake.setMetadataMutex.Lock()
+	ret, specificReturn := fake.setMetadataReturnsOnCall[len(fake.setMetadataArgsForCall)]
+	fake.setMetadataArgsForCall = append(fake.setMetadataArgsForCall, struct {
+		name  string
+		value json.RawMessage
+	}{name, value})
+	fake.recordInvocation("SetMetadata", []interface{}{name, value})
+	fake.setMetadataMutex.Unlock()
+	if fake.SetMetadataStub != nil {
+		return fake.SetMetadataStub(name, value)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setMetadataReturns.result1
+}
+
+func (fake *FakeBuild) SetMetadataCallCount() int {
+	fake.setMetadataMutex.RLock()
+	defer fake.setMetadataMutex.RUnlock()
+	return len(fake.setMetadataArgsForCall)
+}
+
+func (fake *FakeBuild) SetMetadataArgsForCall(i int) (string, json.RawMessage) {
+	fake.setMetadataMutex.RLock()
+	defer fake.setMetadataMutex.RUnlock()
+	return fake.setMetadataArgsForCall[i].name, fake.setMetadataArgsForCall[i].value
+}
+
+func (fake *FakeBuild) SetMetadataReturns(result1 error) {
+	fake.SetMetadataStub = nil
+	fake.setMetadataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) SetMetadataReturnsOnCall(i int, result1 error) {
+	fake.SetMetadataStub = nil
+	if fake.setMetadataReturnsOnCall == nil {
+		fake.setMetadataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setMetadataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) Metadata() (map[string]json.RawMessage, error) {
+	fake.metadataMutex.Lock()
+	ret, specificReturn := fake.metadataReturnsOnCall[len(fake.metadataArgsForCall)]
+	fake.metadataArgsForCall = append(fake.metadataArgsForCall, struct{}{})
+	fake.recordInvocation("Metadata", []interface{}{})
+	fake.metadataMutex.Unlock()
+	if fake.MetadataStub != nil {
+		return fake.MetadataStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.metadataReturns.result1, fake.metadataReturns.result2
+}
+
+func (fake *FakeBuild) MetadataCallCount() int {
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
+	return len(fake.metadataArgsForCall)
+}
+
+func (fake *FakeBuild) MetadataReturns(result1 map[string]json.RawMessage, result2 error) {
+	fake.MetadataStub = nil
+	fake.metadataReturns = struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) MetadataReturnsOnCall(i int, result1 map[string]json.RawMessage, result2 error) {
+	fake.MetadataStub = nil
+	if fake.metadataReturnsOnCall == nil {
+		fake.metadataReturnsOnCall = make(map[int]struct {
+			result1 map[string]json.RawMessage
+			result2 error
+		})
+	}
+	fake.metadataReturnsOnCall[i] = struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuild) Pipeline() (dbng.Pipeline, bool, error) {
 	fake.pipelineMutex.Lock()
 	ret, specificReturn := fake.pipelineReturnsOnCall[len(fake.pipelineArgsForCall)]
@@ -2056,6 +2279,57 @@ func (fake *FakeBuild) AbortReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeBuild) AbortPending(reason string) (bool, error) {
+	fake.abortPendingMutex.Lock()
+	ret, specificReturn := fake.abortPendingReturnsOnCall[len(fake.abortPendingArgsForCall)]
+	fake.abortPendingArgsForCall = append(fake.abortPendingArgsForCall, struct {
+		reason string
+	}{reason})
+	fake.recordInvocation("AbortPending", []interface{}{reason})
+	fake.abortPendingMutex.Unlock()
+	if fake.AbortPendingStub != nil {
+		return fake.AbortPendingStub(reason)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.abortPendingReturns.result1, fake.abortPendingReturns.result2
+}
+
+func (fake *FakeBuild) AbortPendingCallCount() int {
+	fake.abortPendingMutex.RLock()
+	defer fake.abortPendingMutex.RUnlock()
+	return len(fake.abortPendingArgsForCall)
+}
+
+func (fake *FakeBuild) AbortPendingArgsForCall(i int) string {
+	fake.abortPendingMutex.RLock()
+	defer fake.abortPendingMutex.RUnlock()
+	return fake.abortPendingArgsForCall[i].reason
+}
+
+func (fake *FakeBuild) AbortPendingReturns(result1 bool, result2 error) {
+	fake.AbortPendingStub = nil
+	fake.abortPendingReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuild) AbortPendingReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.AbortPendingStub = nil
+	if fake.abortPendingReturnsOnCall == nil {
+		fake.abortPendingReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.abortPendingReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuild) AbortNotifier() (dbng.Notifier, error) {
 	fake.abortNotifierMutex.Lock()
 	ret, specificReturn := fake.abortNotifierReturnsOnCall[len(fake.abortNotifierArgsForCall)]
@@ -2142,6 +2416,188 @@ func (fake *FakeBuild) ScheduleReturnsOnCall(i int, result1 bool, result2 error)
 	}{result1, result2}
 }
 
+func (fake *FakeBuild) Overrides() atc.BuildOverrides {
+	fake.overridesMutex.Lock()
+	ret, specificReturn := fake.overridesReturnsOnCall[len(fake.overridesArgsForCall)]
+	fake.overridesArgsForCall = append(fake.overridesArgsForCall, struct{}{})
+	fake.recordInvocation("Overrides", []interface{}{})
+	fake.overridesMutex.Unlock()
+	if fake.OverridesStub != nil {
+		return fake.OverridesStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.overridesReturns.result1
+}
+
+func (fake *FakeBuild) OverridesCallCount() int {
+	fake.overridesMutex.RLock()
+	defer fake.overridesMutex.RUnlock()
+	return len(fake.overridesArgsForCall)
+}
+
+func (fake *FakeBuild) OverridesReturns(result1 atc.BuildOverrides) {
+	fake.OverridesStub = nil
+	fake.overridesReturns = struct {
+		result1 atc.BuildOverrides
+	}{result1}
+}
+
+func (fake *FakeBuild) OverridesReturnsOnCall(i int, result1 atc.BuildOverrides) {
+	fake.OverridesStub = nil
+	if fake.overridesReturnsOnCall == nil {
+		fake.overridesReturnsOnCall = make(map[int]struct {
+			result1 atc.BuildOverrides
+		})
+	}
+	fake.overridesReturnsOnCall[i] = struct {
+		result1 atc.BuildOverrides
+	}{result1}
+}
+
+func (fake *FakeBuild) InputsPinned() bool {
+	fake.inputsPinnedMutex.Lock()
+	ret, specificReturn := fake.inputsPinnedReturnsOnCall[len(fake.inputsPinnedArgsForCall)]
+	fake.inputsPinnedArgsForCall = append(fake.inputsPinnedArgsForCall, struct{}{})
+	fake.recordInvocation("InputsPinned", []interface{}{})
+	fake.inputsPinnedMutex.Unlock()
+	if fake.InputsPinnedStub != nil {
+		return fake.InputsPinnedStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.inputsPinnedReturns.result1
+}
+
+func (fake *FakeBuild) InputsPinnedCallCount() int {
+	fake.inputsPinnedMutex.RLock()
+	defer fake.inputsPinnedMutex.RUnlock()
+	return len(fake.inputsPinnedArgsForCall)
+}
+
+func (fake *FakeBuild) InputsPinnedReturns(result1 bool) {
+	fake.InputsPinnedStub = nil
+	fake.inputsPinnedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeBuild) InputsPinnedReturnsOnCall(i int, result1 bool) {
+	fake.InputsPinnedStub = nil
+	if fake.inputsPinnedReturnsOnCall == nil {
+		fake.inputsPinnedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.inputsPinnedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeBuild) PinInputs(inputMapping algorithm.InputMapping) error {
+	fake.pinInputsMutex.Lock()
+	ret, specificReturn := fake.pinInputsReturnsOnCall[len(fake.pinInputsArgsForCall)]
+	fake.pinInputsArgsForCall = append(fake.pinInputsArgsForCall, struct {
+		inputMapping algorithm.InputMapping
+	}{inputMapping})
+	fake.recordInvocation("PinInputs", []interface{}{inputMapping})
+	fake.pinInputsMutex.Unlock()
+	if fake.PinInputsStub != nil {
+		return fake.PinInputsStub(inputMapping)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.pinInputsReturns.result1
+}
+
+func (fake *FakeBuild) PinInputsCallCount() int {
+	fake.pinInputsMutex.RLock()
+	defer fake.pinInputsMutex.RUnlock()
+	return len(fake.pinInputsArgsForCall)
+}
+
+func (fake *FakeBuild) PinInputsArgsForCall(i int) algorithm.InputMapping {
+	fake.pinInputsMutex.RLock()
+	defer fake.pinInputsMutex.RUnlock()
+	return fake.pinInputsArgsForCall[i].inputMapping
+}
+
+func (fake *FakeBuild) PinInputsReturns(result1 error) {
+	fake.PinInputsStub = nil
+	fake.pinInputsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) PinInputsReturnsOnCall(i int, result1 error) {
+	fake.PinInputsStub = nil
+	if fake.pinInputsReturnsOnCall == nil {
+		fake.pinInputsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.pinInputsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuild) ImageResourceVersion(resourceHash string) (atc.Version, bool, error) {
+	fake.imageResourceVersionMutex.Lock()
+	ret, specificReturn := fake.imageResourceVersionReturnsOnCall[len(fake.imageResourceVersionArgsForCall)]
+	fake.imageResourceVersionArgsForCall = append(fake.imageResourceVersionArgsForCall, struct {
+		resourceHash string
+	}{resourceHash})
+	fake.recordInvocation("ImageResourceVersion", []interface{}{resourceHash})
+	fake.imageResourceVersionMutex.Unlock()
+	if fake.ImageResourceVersionStub != nil {
+		return fake.ImageResourceVersionStub(resourceHash)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.imageResourceVersionReturns.result1, fake.imageResourceVersionReturns.result2, fake.imageResourceVersionReturns.result3
+}
+
+func (fake *FakeBuild) ImageResourceVersionCallCount() int {
+	fake.imageResourceVersionMutex.RLock()
+	defer fake.imageResourceVersionMutex.RUnlock()
+	return len(fake.imageResourceVersionArgsForCall)
+}
+
+func (fake *FakeBuild) ImageResourceVersionArgsForCall(i int) string {
+	fake.imageResourceVersionMutex.RLock()
+	defer fake.imageResourceVersionMutex.RUnlock()
+	return fake.imageResourceVersionArgsForCall[i].resourceHash
+}
+
+func (fake *FakeBuild) ImageResourceVersionReturns(result1 atc.Version, result2 bool, result3 error) {
+	fake.ImageResourceVersionStub = nil
+	fake.imageResourceVersionReturns = struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuild) ImageResourceVersionReturnsOnCall(i int, result1 atc.Version, result2 bool, result3 error) {
+	fake.ImageResourceVersionStub = nil
+	if fake.imageResourceVersionReturnsOnCall == nil {
+		fake.imageResourceVersionReturnsOnCall = make(map[int]struct {
+			result1 atc.Version
+			result2 bool
+			result3 error
+		})
+	}
+	fake.imageResourceVersionReturnsOnCall[i] = struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -2175,6 +2631,8 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.reapTimeMutex.RUnlock()
 	fake.isManuallyTriggeredMutex.RLock()
 	defer fake.isManuallyTriggeredMutex.RUnlock()
+	fake.triggerReasonMutex.RLock()
+	defer fake.triggerReasonMutex.RUnlock()
 	fake.isScheduledMutex.RLock()
 	defer fake.isScheduledMutex.RUnlock()
 	fake.isRunningMutex.RLock()
@@ -2211,6 +2669,10 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.getVersionedResourcesMutex.RUnlock()
 	fake.saveImageResourceVersionMutex.RLock()
 	defer fake.saveImageResourceVersionMutex.RUnlock()
+	fake.setMetadataMutex.RLock()
+	defer fake.setMetadataMutex.RUnlock()
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
 	fake.pipelineMutex.RLock()
 	defer fake.pipelineMutex.RUnlock()
 	fake.finishMutex.RLock()
@@ -2219,10 +2681,20 @@ func (fake *FakeBuild) Invocations() map[string][][]interface{} {
 	defer fake.deleteMutex.RUnlock()
 	fake.abortMutex.RLock()
 	defer fake.abortMutex.RUnlock()
+	fake.abortPendingMutex.RLock()
+	defer fake.abortPendingMutex.RUnlock()
 	fake.abortNotifierMutex.RLock()
 	defer fake.abortNotifierMutex.RUnlock()
 	fake.scheduleMutex.RLock()
 	defer fake.scheduleMutex.RUnlock()
+	fake.overridesMutex.RLock()
+	defer fake.overridesMutex.RUnlock()
+	fake.inputsPinnedMutex.RLock()
+	defer fake.inputsPinnedMutex.RUnlock()
+	fake.pinInputsMutex.RLock()
+	defer fake.pinInputsMutex.RUnlock()
+	fake.imageResourceVersionMutex.RLock()
+	defer fake.imageResourceVersionMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -3,6 +3,7 @@ package dbngfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/atc/dbng"
 )
@@ -49,6 +50,121 @@ type FakeBuildFactory struct {
 		result1 []dbng.Build
 		result2 error
 	}
+	GetZombieBuildsStub        func(startedBefore time.Time) ([]dbng.Build, error)
+	getZombieBuildsMutex       sync.RWMutex
+	getZombieBuildsArgsForCall []struct {
+		startedBefore time.Time
+	}
+	getZombieBuildsReturns struct {
+		result1 []dbng.Build
+		result2 error
+	}
+	getZombieBuildsReturnsOnCall map[int]struct {
+		result1 []dbng.Build
+		result2 error
+	}
+	GetBuildQueueStatsStub        func(teamID int, since time.Time) (dbng.QueueStats, error)
+	getBuildQueueStatsMutex       sync.RWMutex
+	getBuildQueueStatsArgsForCall []struct {
+		teamID int
+		since  time.Time
+	}
+	getBuildQueueStatsReturns struct {
+		result1 dbng.QueueStats
+		result2 error
+	}
+	getBuildQueueStatsReturnsOnCall map[int]struct {
+		result1 dbng.QueueStats
+		result2 error
+	}
+	EventStoreBacklogStub        func() (int, error)
+	eventStoreBacklogMutex       sync.RWMutex
+	eventStoreBacklogArgsForCall []struct{}
+	eventStoreBacklogReturns     struct {
+		result1 int
+		result2 error
+	}
+	eventStoreBacklogReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	CompactBuildEventsStub        func(buildID int) error
+	compactBuildEventsMutex       sync.RWMutex
+	compactBuildEventsArgsForCall []struct {
+		buildID int
+	}
+	compactBuildEventsReturns struct {
+		result1 error
+	}
+	compactBuildEventsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetBuildTimelineStub        func(buildID int) ([]dbng.StepTiming, error)
+	getBuildTimelineMutex       sync.RWMutex
+	getBuildTimelineArgsForCall []struct {
+		buildID int
+	}
+	getBuildTimelineReturns struct {
+		result1 []dbng.StepTiming
+		result2 error
+	}
+	getBuildTimelineReturnsOnCall map[int]struct {
+		result1 []dbng.StepTiming
+		result2 error
+	}
+	GetStepEventsStub        func(buildID int, stepName string, attempt []int, from uint) (dbng.EventSource, error)
+	getStepEventsMutex       sync.RWMutex
+	getStepEventsArgsForCall []struct {
+		buildID  int
+		stepName string
+		attempt  []int
+		from     uint
+	}
+	getStepEventsReturns struct {
+		result1 dbng.EventSource
+		result2 error
+	}
+	getStepEventsReturnsOnCall map[int]struct {
+		result1 dbng.EventSource
+		result2 error
+	}
+	RecordBuildResourceUsageStub        func(buildID int, cpuSeconds float64, memByteSeconds float64) error
+	recordBuildResourceUsageMutex       sync.RWMutex
+	recordBuildResourceUsageArgsForCall []struct {
+		buildID        int
+		cpuSeconds     float64
+		memByteSeconds float64
+	}
+	recordBuildResourceUsageReturns struct {
+		result1 error
+	}
+	recordBuildResourceUsageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RecordUnmeasuredBuildResourceUsageStub        func(buildID int) error
+	recordUnmeasuredBuildResourceUsageMutex       sync.RWMutex
+	recordUnmeasuredBuildResourceUsageArgsForCall []struct {
+		buildID int
+	}
+	recordUnmeasuredBuildResourceUsageReturns struct {
+		result1 error
+	}
+	recordUnmeasuredBuildResourceUsageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetBuildResourceUsageStub        func(buildID int) (dbng.ResourceUsage, error)
+	getBuildResourceUsageMutex       sync.RWMutex
+	getBuildResourceUsageArgsForCall []struct {
+		buildID int
+	}
+	getBuildResourceUsageReturns struct {
+		result1 dbng.ResourceUsage
+		result2 error
+	}
+	getBuildResourceUsageReturnsOnCall map[int]struct {
+		result1 dbng.ResourceUsage
+		result2 error
+	}
 	MarkNonInterceptibleBuildsStub        func() error
 	markNonInterceptibleBuildsMutex       sync.RWMutex
 	markNonInterceptibleBuildsArgsForCall []struct{}
@@ -213,6 +329,459 @@ func (fake *FakeBuildFactory) GetAllStartedBuildsReturnsOnCall(i int, result1 []
 	}{result1, result2}
 }
 
+func (fake *FakeBuildFactory) GetZombieBuilds(startedBefore time.Time) ([]dbng.Build, error) {
+	fake.getZombieBuildsMutex.Lock()
+	ret, specificReturn := fake.getZombieBuildsReturnsOnCall[len(fake.getZombieBuildsArgsForCall)]
+	fake.getZombieBuildsArgsForCall = append(fake.getZombieBuildsArgsForCall, struct {
+		startedBefore time.Time
+	}{startedBefore})
+	fake.recordInvocation("GetZombieBuilds", []interface{}{startedBefore})
+	fake.getZombieBuildsMutex.Unlock()
+	if fake.GetZombieBuildsStub != nil {
+		return fake.GetZombieBuildsStub(startedBefore)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getZombieBuildsReturns.result1, fake.getZombieBuildsReturns.result2
+}
+
+func (fake *FakeBuildFactory) GetZombieBuildsCallCount() int {
+	fake.getZombieBuildsMutex.RLock()
+	defer fake.getZombieBuildsMutex.RUnlock()
+	return len(fake.getZombieBuildsArgsForCall)
+}
+
+func (fake *FakeBuildFactory) GetZombieBuildsArgsForCall(i int) time.Time {
+	fake.getZombieBuildsMutex.RLock()
+	defer fake.getZombieBuildsMutex.RUnlock()
+	return fake.getZombieBuildsArgsForCall[i].startedBefore
+}
+
+func (fake *FakeBuildFactory) GetZombieBuildsReturns(result1 []dbng.Build, result2 error) {
+	fake.GetZombieBuildsStub = nil
+	fake.getZombieBuildsReturns = struct {
+		result1 []dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetZombieBuildsReturnsOnCall(i int, result1 []dbng.Build, result2 error) {
+	fake.GetZombieBuildsStub = nil
+	if fake.getZombieBuildsReturnsOnCall == nil {
+		fake.getZombieBuildsReturnsOnCall = make(map[int]struct {
+			result1 []dbng.Build
+			result2 error
+		})
+	}
+	fake.getZombieBuildsReturnsOnCall[i] = struct {
+		result1 []dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetBuildQueueStats(teamID int, since time.Time) (dbng.QueueStats, error) {
+	fake.getBuildQueueStatsMutex.Lock()
+	ret, specificReturn := fake.getBuildQueueStatsReturnsOnCall[len(fake.getBuildQueueStatsArgsForCall)]
+	fake.getBuildQueueStatsArgsForCall = append(fake.getBuildQueueStatsArgsForCall, struct {
+		teamID int
+		since  time.Time
+	}{teamID, since})
+	fake.recordInvocation("GetBuildQueueStats", []interface{}{teamID, since})
+	fake.getBuildQueueStatsMutex.Unlock()
+	if fake.GetBuildQueueStatsStub != nil {
+		return fake.GetBuildQueueStatsStub(teamID, since)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getBuildQueueStatsReturns.result1, fake.getBuildQueueStatsReturns.result2
+}
+
+func (fake *FakeBuildFactory) GetBuildQueueStatsCallCount() int {
+	fake.getBuildQueueStatsMutex.RLock()
+	defer fake.getBuildQueueStatsMutex.RUnlock()
+	return len(fake.getBuildQueueStatsArgsForCall)
+}
+
+func (fake *FakeBuildFactory) GetBuildQueueStatsArgsForCall(i int) (int, time.Time) {
+	fake.getBuildQueueStatsMutex.RLock()
+	defer fake.getBuildQueueStatsMutex.RUnlock()
+	return fake.getBuildQueueStatsArgsForCall[i].teamID, fake.getBuildQueueStatsArgsForCall[i].since
+}
+
+func (fake *FakeBuildFactory) GetBuildQueueStatsReturns(result1 dbng.QueueStats, result2 error) {
+	fake.GetBuildQueueStatsStub = nil
+	fake.getBuildQueueStatsReturns = struct {
+		result1 dbng.QueueStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetBuildQueueStatsReturnsOnCall(i int, result1 dbng.QueueStats, result2 error) {
+	fake.GetBuildQueueStatsStub = nil
+	if fake.getBuildQueueStatsReturnsOnCall == nil {
+		fake.getBuildQueueStatsReturnsOnCall = make(map[int]struct {
+			result1 dbng.QueueStats
+			result2 error
+		})
+	}
+	fake.getBuildQueueStatsReturnsOnCall[i] = struct {
+		result1 dbng.QueueStats
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) EventStoreBacklog() (int, error) {
+	fake.eventStoreBacklogMutex.Lock()
+	ret, specificReturn := fake.eventStoreBacklogReturnsOnCall[len(fake.eventStoreBacklogArgsForCall)]
+	fake.eventStoreBacklogArgsForCall = append(fake.eventStoreBacklogArgsForCall, struct{}{})
+	fake.recordInvocation("EventStoreBacklog", []interface{}{})
+	fake.eventStoreBacklogMutex.Unlock()
+	if fake.EventStoreBacklogStub != nil {
+		return fake.EventStoreBacklogStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.eventStoreBacklogReturns.result1, fake.eventStoreBacklogReturns.result2
+}
+
+func (fake *FakeBuildFactory) EventStoreBacklogCallCount() int {
+	fake.eventStoreBacklogMutex.RLock()
+	defer fake.eventStoreBacklogMutex.RUnlock()
+	return len(fake.eventStoreBacklogArgsForCall)
+}
+
+func (fake *FakeBuildFactory) EventStoreBacklogReturns(result1 int, result2 error) {
+	fake.EventStoreBacklogStub = nil
+	fake.eventStoreBacklogReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) EventStoreBacklogReturnsOnCall(i int, result1 int, result2 error) {
+	fake.EventStoreBacklogStub = nil
+	if fake.eventStoreBacklogReturnsOnCall == nil {
+		fake.eventStoreBacklogReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.eventStoreBacklogReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) CompactBuildEvents(buildID int) error {
+	fake.compactBuildEventsMutex.Lock()
+	ret, specificReturn := fake.compactBuildEventsReturnsOnCall[len(fake.compactBuildEventsArgsForCall)]
+	fake.compactBuildEventsArgsForCall = append(fake.compactBuildEventsArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("CompactBuildEvents", []interface{}{buildID})
+	fake.compactBuildEventsMutex.Unlock()
+	if fake.CompactBuildEventsStub != nil {
+		return fake.CompactBuildEventsStub(buildID)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.compactBuildEventsReturns.result1
+}
+
+func (fake *FakeBuildFactory) CompactBuildEventsCallCount() int {
+	fake.compactBuildEventsMutex.RLock()
+	defer fake.compactBuildEventsMutex.RUnlock()
+	return len(fake.compactBuildEventsArgsForCall)
+}
+
+func (fake *FakeBuildFactory) CompactBuildEventsArgsForCall(i int) int {
+	fake.compactBuildEventsMutex.RLock()
+	defer fake.compactBuildEventsMutex.RUnlock()
+	return fake.compactBuildEventsArgsForCall[i].buildID
+}
+
+func (fake *FakeBuildFactory) CompactBuildEventsReturns(result1 error) {
+	fake.CompactBuildEventsStub = nil
+	fake.compactBuildEventsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildFactory) CompactBuildEventsReturnsOnCall(i int, result1 error) {
+	fake.CompactBuildEventsStub = nil
+	if fake.compactBuildEventsReturnsOnCall == nil {
+		fake.compactBuildEventsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.compactBuildEventsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildFactory) GetBuildTimeline(buildID int) ([]dbng.StepTiming, error) {
+	fake.getBuildTimelineMutex.Lock()
+	ret, specificReturn := fake.getBuildTimelineReturnsOnCall[len(fake.getBuildTimelineArgsForCall)]
+	fake.getBuildTimelineArgsForCall = append(fake.getBuildTimelineArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("GetBuildTimeline", []interface{}{buildID})
+	fake.getBuildTimelineMutex.Unlock()
+	if fake.GetBuildTimelineStub != nil {
+		return fake.GetBuildTimelineStub(buildID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getBuildTimelineReturns.result1, fake.getBuildTimelineReturns.result2
+}
+
+func (fake *FakeBuildFactory) GetBuildTimelineCallCount() int {
+	fake.getBuildTimelineMutex.RLock()
+	defer fake.getBuildTimelineMutex.RUnlock()
+	return len(fake.getBuildTimelineArgsForCall)
+}
+
+func (fake *FakeBuildFactory) GetBuildTimelineArgsForCall(i int) int {
+	fake.getBuildTimelineMutex.RLock()
+	defer fake.getBuildTimelineMutex.RUnlock()
+	return fake.getBuildTimelineArgsForCall[i].buildID
+}
+
+func (fake *FakeBuildFactory) GetBuildTimelineReturns(result1 []dbng.StepTiming, result2 error) {
+	fake.GetBuildTimelineStub = nil
+	fake.getBuildTimelineReturns = struct {
+		result1 []dbng.StepTiming
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetBuildTimelineReturnsOnCall(i int, result1 []dbng.StepTiming, result2 error) {
+	fake.GetBuildTimelineStub = nil
+	if fake.getBuildTimelineReturnsOnCall == nil {
+		fake.getBuildTimelineReturnsOnCall = make(map[int]struct {
+			result1 []dbng.StepTiming
+			result2 error
+		})
+	}
+	fake.getBuildTimelineReturnsOnCall[i] = struct {
+		result1 []dbng.StepTiming
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetStepEvents(buildID int, stepName string, attempt []int, from uint) (dbng.EventSource, error) {
+	var attemptCopy []int
+	if attempt != nil {
+		attemptCopy = make([]int, len(attempt))
+		copy(attemptCopy, attempt)
+	}
+	fake.getStepEventsMutex.Lock()
+	ret, specificReturn := fake.getStepEventsReturnsOnCall[len(fake.getStepEventsArgsForCall)]
+	fake.getStepEventsArgsForCall = append(fake.getStepEventsArgsForCall, struct {
+		buildID  int
+		stepName string
+		attempt  []int
+		from     uint
+	}{buildID, stepName, attemptCopy, from})
+	fake.recordInvocation("GetStepEvents", []interface{}{buildID, stepName, attemptCopy, from})
+	fake.getStepEventsMutex.Unlock()
+	if fake.GetStepEventsStub != nil {
+		return fake.GetStepEventsStub(buildID, stepName, attempt, from)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getStepEventsReturns.result1, fake.getStepEventsReturns.result2
+}
+
+func (fake *FakeBuildFactory) GetStepEventsCallCount() int {
+	fake.getStepEventsMutex.RLock()
+	defer fake.getStepEventsMutex.RUnlock()
+	return len(fake.getStepEventsArgsForCall)
+}
+
+func (fake *FakeBuildFactory) GetStepEventsArgsForCall(i int) (int, string, []int, uint) {
+	fake.getStepEventsMutex.RLock()
+	defer fake.getStepEventsMutex.RUnlock()
+	return fake.getStepEventsArgsForCall[i].buildID, fake.getStepEventsArgsForCall[i].stepName, fake.getStepEventsArgsForCall[i].attempt, fake.getStepEventsArgsForCall[i].from
+}
+
+func (fake *FakeBuildFactory) GetStepEventsReturns(result1 dbng.EventSource, result2 error) {
+	fake.GetStepEventsStub = nil
+	fake.getStepEventsReturns = struct {
+		result1 dbng.EventSource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetStepEventsReturnsOnCall(i int, result1 dbng.EventSource, result2 error) {
+	fake.GetStepEventsStub = nil
+	if fake.getStepEventsReturnsOnCall == nil {
+		fake.getStepEventsReturnsOnCall = make(map[int]struct {
+			result1 dbng.EventSource
+			result2 error
+		})
+	}
+	fake.getStepEventsReturnsOnCall[i] = struct {
+		result1 dbng.EventSource
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) RecordBuildResourceUsage(buildID int, cpuSeconds float64, memByteSeconds float64) error {
+	fake.recordBuildResourceUsageMutex.Lock()
+	ret, specificReturn := fake.recordBuildResourceUsageReturnsOnCall[len(fake.recordBuildResourceUsageArgsForCall)]
+	fake.recordBuildResourceUsageArgsForCall = append(fake.recordBuildResourceUsageArgsForCall, struct {
+		buildID        int
+		cpuSeconds     float64
+		memByteSeconds float64
+	}{buildID, cpuSeconds, memByteSeconds})
+	fake.recordInvocation("RecordBuildResourceUsage", []interface{}{buildID, cpuSeconds, memByteSeconds})
+	fake.recordBuildResourceUsageMutex.Unlock()
+	if fake.RecordBuildResourceUsageStub != nil {
+		return fake.RecordBuildResourceUsageStub(buildID, cpuSeconds, memByteSeconds)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.recordBuildResourceUsageReturns.result1
+}
+
+func (fake *FakeBuildFactory) RecordBuildResourceUsageCallCount() int {
+	fake.recordBuildResourceUsageMutex.RLock()
+	defer fake.recordBuildResourceUsageMutex.RUnlock()
+	return len(fake.recordBuildResourceUsageArgsForCall)
+}
+
+func (fake *FakeBuildFactory) RecordBuildResourceUsageArgsForCall(i int) (int, float64, float64) {
+	fake.recordBuildResourceUsageMutex.RLock()
+	defer fake.recordBuildResourceUsageMutex.RUnlock()
+	return fake.recordBuildResourceUsageArgsForCall[i].buildID, fake.recordBuildResourceUsageArgsForCall[i].cpuSeconds, fake.recordBuildResourceUsageArgsForCall[i].memByteSeconds
+}
+
+func (fake *FakeBuildFactory) RecordBuildResourceUsageReturns(result1 error) {
+	fake.RecordBuildResourceUsageStub = nil
+	fake.recordBuildResourceUsageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildFactory) RecordBuildResourceUsageReturnsOnCall(i int, result1 error) {
+	fake.RecordBuildResourceUsageStub = nil
+	if fake.recordBuildResourceUsageReturnsOnCall == nil {
+		fake.recordBuildResourceUsageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordBuildResourceUsageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildFactory) RecordUnmeasuredBuildResourceUsage(buildID int) error {
+	fake.recordUnmeasuredBuildResourceUsageMutex.Lock()
+	ret, specificReturn := fake.recordUnmeasuredBuildResourceUsageReturnsOnCall[len(fake.recordUnmeasuredBuildResourceUsageArgsForCall)]
+	fake.recordUnmeasuredBuildResourceUsageArgsForCall = append(fake.recordUnmeasuredBuildResourceUsageArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("RecordUnmeasuredBuildResourceUsage", []interface{}{buildID})
+	fake.recordUnmeasuredBuildResourceUsageMutex.Unlock()
+	if fake.RecordUnmeasuredBuildResourceUsageStub != nil {
+		return fake.RecordUnmeasuredBuildResourceUsageStub(buildID)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.recordUnmeasuredBuildResourceUsageReturns.result1
+}
+
+func (fake *FakeBuildFactory) RecordUnmeasuredBuildResourceUsageCallCount() int {
+	fake.recordUnmeasuredBuildResourceUsageMutex.RLock()
+	defer fake.recordUnmeasuredBuildResourceUsageMutex.RUnlock()
+	return len(fake.recordUnmeasuredBuildResourceUsageArgsForCall)
+}
+
+func (fake *FakeBuildFactory) RecordUnmeasuredBuildResourceUsageArgsForCall(i int) int {
+	fake.recordUnmeasuredBuildResourceUsageMutex.RLock()
+	defer fake.recordUnmeasuredBuildResourceUsageMutex.RUnlock()
+	return fake.recordUnmeasuredBuildResourceUsageArgsForCall[i].buildID
+}
+
+func (fake *FakeBuildFactory) RecordUnmeasuredBuildResourceUsageReturns(result1 error) {
+	fake.RecordUnmeasuredBuildResourceUsageStub = nil
+	fake.recordUnmeasuredBuildResourceUsageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildFactory) RecordUnmeasuredBuildResourceUsageReturnsOnCall(i int, result1 error) {
+	fake.RecordUnmeasuredBuildResourceUsageStub = nil
+	if fake.recordUnmeasuredBuildResourceUsageReturnsOnCall == nil {
+		fake.recordUnmeasuredBuildResourceUsageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordUnmeasuredBuildResourceUsageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBuildFactory) GetBuildResourceUsage(buildID int) (dbng.ResourceUsage, error) {
+	fake.getBuildResourceUsageMutex.Lock()
+	ret, specificReturn := fake.getBuildResourceUsageReturnsOnCall[len(fake.getBuildResourceUsageArgsForCall)]
+	fake.getBuildResourceUsageArgsForCall = append(fake.getBuildResourceUsageArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("GetBuildResourceUsage", []interface{}{buildID})
+	fake.getBuildResourceUsageMutex.Unlock()
+	if fake.GetBuildResourceUsageStub != nil {
+		return fake.GetBuildResourceUsageStub(buildID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getBuildResourceUsageReturns.result1, fake.getBuildResourceUsageReturns.result2
+}
+
+func (fake *FakeBuildFactory) GetBuildResourceUsageCallCount() int {
+	fake.getBuildResourceUsageMutex.RLock()
+	defer fake.getBuildResourceUsageMutex.RUnlock()
+	return len(fake.getBuildResourceUsageArgsForCall)
+}
+
+func (fake *FakeBuildFactory) GetBuildResourceUsageArgsForCall(i int) int {
+	fake.getBuildResourceUsageMutex.RLock()
+	defer fake.getBuildResourceUsageMutex.RUnlock()
+	return fake.getBuildResourceUsageArgsForCall[i].buildID
+}
+
+func (fake *FakeBuildFactory) GetBuildResourceUsageReturns(result1 dbng.ResourceUsage, result2 error) {
+	fake.GetBuildResourceUsageStub = nil
+	fake.getBuildResourceUsageReturns = struct {
+		result1 dbng.ResourceUsage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildFactory) GetBuildResourceUsageReturnsOnCall(i int, result1 dbng.ResourceUsage, result2 error) {
+	fake.GetBuildResourceUsageStub = nil
+	if fake.getBuildResourceUsageReturnsOnCall == nil {
+		fake.getBuildResourceUsageReturnsOnCall = make(map[int]struct {
+			result1 dbng.ResourceUsage
+			result2 error
+		})
+	}
+	fake.getBuildResourceUsageReturnsOnCall[i] = struct {
+		result1 dbng.ResourceUsage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuildFactory) MarkNonInterceptibleBuilds() error {
 	fake.markNonInterceptibleBuildsMutex.Lock()
 	ret, specificReturn := fake.markNonInterceptibleBuildsReturnsOnCall[len(fake.markNonInterceptibleBuildsArgsForCall)]
@@ -262,6 +831,24 @@ func (fake *FakeBuildFactory) Invocations() map[string][][]interface{} {
 	defer fake.publicBuildsMutex.RUnlock()
 	fake.getAllStartedBuildsMutex.RLock()
 	defer fake.getAllStartedBuildsMutex.RUnlock()
+	fake.getZombieBuildsMutex.RLock()
+	defer fake.getZombieBuildsMutex.RUnlock()
+	fake.getBuildQueueStatsMutex.RLock()
+	defer fake.getBuildQueueStatsMutex.RUnlock()
+	fake.eventStoreBacklogMutex.RLock()
+	defer fake.eventStoreBacklogMutex.RUnlock()
+	fake.compactBuildEventsMutex.RLock()
+	defer fake.compactBuildEventsMutex.RUnlock()
+	fake.getBuildTimelineMutex.RLock()
+	defer fake.getBuildTimelineMutex.RUnlock()
+	fake.getStepEventsMutex.RLock()
+	defer fake.getStepEventsMutex.RUnlock()
+	fake.recordBuildResourceUsageMutex.RLock()
+	defer fake.recordBuildResourceUsageMutex.RUnlock()
+	fake.recordUnmeasuredBuildResourceUsageMutex.RLock()
+	defer fake.recordUnmeasuredBuildResourceUsageMutex.RUnlock()
+	fake.getBuildResourceUsageMutex.RLock()
+	defer fake.getBuildResourceUsageMutex.RUnlock()
 	fake.markNonInterceptibleBuildsMutex.RLock()
 	defer fake.markNonInterceptibleBuildsMutex.RUnlock()
 	return fake.invocations
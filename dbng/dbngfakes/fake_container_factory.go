@@ -4,6 +4,7 @@ package dbngfakes
 import (
 	"sync"
 
+	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
 )
 
@@ -23,6 +24,46 @@ type FakeContainerFactory struct {
 		result3 []dbng.DestroyingContainer
 		result4 error
 	}
+	FindEvacuatableContainersStub        func(string) ([]dbng.EvacuatableContainer, error)
+	findEvacuatableContainersMutex       sync.RWMutex
+	findEvacuatableContainersArgsForCall []struct {
+		workerName string
+	}
+	findEvacuatableContainersReturns struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}
+	findEvacuatableContainersReturnsOnCall map[int]struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}
+	RecordContainerPlacementStub        func(handle string, workerName string, buildID int, planID atc.PlanID) error
+	recordContainerPlacementMutex       sync.RWMutex
+	recordContainerPlacementArgsForCall []struct {
+		handle     string
+		workerName string
+		buildID    int
+		planID     atc.PlanID
+	}
+	recordContainerPlacementReturns struct {
+		result1 error
+	}
+	recordContainerPlacementReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetContainerHistoryStub        func(buildID int) ([]dbng.ContainerPlacement, error)
+	getContainerHistoryMutex       sync.RWMutex
+	getContainerHistoryArgsForCall []struct {
+		buildID int
+	}
+	getContainerHistoryReturns struct {
+		result1 []dbng.ContainerPlacement
+		result2 error
+	}
+	getContainerHistoryReturnsOnCall map[int]struct {
+		result1 []dbng.ContainerPlacement
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -76,11 +117,170 @@ func (fake *FakeContainerFactory) FindContainersForDeletionReturnsOnCall(i int,
 	}{result1, result2, result3, result4}
 }
 
+func (fake *FakeContainerFactory) FindEvacuatableContainers(workerName string) ([]dbng.EvacuatableContainer, error) {
+	fake.findEvacuatableContainersMutex.Lock()
+	ret, specificReturn := fake.findEvacuatableContainersReturnsOnCall[len(fake.findEvacuatableContainersArgsForCall)]
+	fake.findEvacuatableContainersArgsForCall = append(fake.findEvacuatableContainersArgsForCall, struct {
+		workerName string
+	}{workerName})
+	fake.recordInvocation("FindEvacuatableContainers", []interface{}{workerName})
+	fake.findEvacuatableContainersMutex.Unlock()
+	if fake.FindEvacuatableContainersStub != nil {
+		return fake.FindEvacuatableContainersStub(workerName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.findEvacuatableContainersReturns.result1, fake.findEvacuatableContainersReturns.result2
+}
+
+func (fake *FakeContainerFactory) FindEvacuatableContainersCallCount() int {
+	fake.findEvacuatableContainersMutex.RLock()
+	defer fake.findEvacuatableContainersMutex.RUnlock()
+	return len(fake.findEvacuatableContainersArgsForCall)
+}
+
+func (fake *FakeContainerFactory) FindEvacuatableContainersArgsForCall(i int) string {
+	fake.findEvacuatableContainersMutex.RLock()
+	defer fake.findEvacuatableContainersMutex.RUnlock()
+	return fake.findEvacuatableContainersArgsForCall[i].workerName
+}
+
+func (fake *FakeContainerFactory) FindEvacuatableContainersReturns(result1 []dbng.EvacuatableContainer, result2 error) {
+	fake.FindEvacuatableContainersStub = nil
+	fake.findEvacuatableContainersReturns = struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerFactory) FindEvacuatableContainersReturnsOnCall(i int, result1 []dbng.EvacuatableContainer, result2 error) {
+	fake.FindEvacuatableContainersStub = nil
+	if fake.findEvacuatableContainersReturnsOnCall == nil {
+		fake.findEvacuatableContainersReturnsOnCall = make(map[int]struct {
+			result1 []dbng.EvacuatableContainer
+			result2 error
+		})
+	}
+	fake.findEvacuatableContainersReturnsOnCall[i] = struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerFactory) RecordContainerPlacement(handle string, workerName string, buildID int, planID atc.PlanID) error {
+	fake.recordContainerPlacementMutex.Lock()
+	ret, specificReturn := fake.recordContainerPlacementReturnsOnCall[len(fake.recordContainerPlacementArgsForCall)]
+	fake.recordContainerPlacementArgsForCall = append(fake.recordContainerPlacementArgsForCall, struct {
+		handle     string
+		workerName string
+		buildID    int
+		planID     atc.PlanID
+	}{handle, workerName, buildID, planID})
+	fake.recordInvocation("RecordContainerPlacement", []interface{}{handle, workerName, buildID, planID})
+	fake.recordContainerPlacementMutex.Unlock()
+	if fake.RecordContainerPlacementStub != nil {
+		return fake.RecordContainerPlacementStub(handle, workerName, buildID, planID)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.recordContainerPlacementReturns.result1
+}
+
+func (fake *FakeContainerFactory) RecordContainerPlacementCallCount() int {
+	fake.recordContainerPlacementMutex.RLock()
+	defer fake.recordContainerPlacementMutex.RUnlock()
+	return len(fake.recordContainerPlacementArgsForCall)
+}
+
+func (fake *FakeContainerFactory) RecordContainerPlacementArgsForCall(i int) (string, string, int, atc.PlanID) {
+	fake.recordContainerPlacementMutex.RLock()
+	defer fake.recordContainerPlacementMutex.RUnlock()
+	return fake.recordContainerPlacementArgsForCall[i].handle, fake.recordContainerPlacementArgsForCall[i].workerName, fake.recordContainerPlacementArgsForCall[i].buildID, fake.recordContainerPlacementArgsForCall[i].planID
+}
+
+func (fake *FakeContainerFactory) RecordContainerPlacementReturns(result1 error) {
+	fake.RecordContainerPlacementStub = nil
+	fake.recordContainerPlacementReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainerFactory) RecordContainerPlacementReturnsOnCall(i int, result1 error) {
+	fake.RecordContainerPlacementStub = nil
+	if fake.recordContainerPlacementReturnsOnCall == nil {
+		fake.recordContainerPlacementReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.recordContainerPlacementReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainerFactory) GetContainerHistory(buildID int) ([]dbng.ContainerPlacement, error) {
+	fake.getContainerHistoryMutex.Lock()
+	ret, specificReturn := fake.getContainerHistoryReturnsOnCall[len(fake.getContainerHistoryArgsForCall)]
+	fake.getContainerHistoryArgsForCall = append(fake.getContainerHistoryArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("GetContainerHistory", []interface{}{buildID})
+	fake.getContainerHistoryMutex.Unlock()
+	if fake.GetContainerHistoryStub != nil {
+		return fake.GetContainerHistoryStub(buildID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getContainerHistoryReturns.result1, fake.getContainerHistoryReturns.result2
+}
+
+func (fake *FakeContainerFactory) GetContainerHistoryCallCount() int {
+	fake.getContainerHistoryMutex.RLock()
+	defer fake.getContainerHistoryMutex.RUnlock()
+	return len(fake.getContainerHistoryArgsForCall)
+}
+
+func (fake *FakeContainerFactory) GetContainerHistoryArgsForCall(i int) int {
+	fake.getContainerHistoryMutex.RLock()
+	defer fake.getContainerHistoryMutex.RUnlock()
+	return fake.getContainerHistoryArgsForCall[i].buildID
+}
+
+func (fake *FakeContainerFactory) GetContainerHistoryReturns(result1 []dbng.ContainerPlacement, result2 error) {
+	fake.GetContainerHistoryStub = nil
+	fake.getContainerHistoryReturns = struct {
+		result1 []dbng.ContainerPlacement
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerFactory) GetContainerHistoryReturnsOnCall(i int, result1 []dbng.ContainerPlacement, result2 error) {
+	fake.GetContainerHistoryStub = nil
+	if fake.getContainerHistoryReturnsOnCall == nil {
+		fake.getContainerHistoryReturnsOnCall = make(map[int]struct {
+			result1 []dbng.ContainerPlacement
+			result2 error
+		})
+	}
+	fake.getContainerHistoryReturnsOnCall[i] = struct {
+		result1 []dbng.ContainerPlacement
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeContainerFactory) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.findContainersForDeletionMutex.RLock()
 	defer fake.findContainersForDeletionMutex.RUnlock()
+	fake.findEvacuatableContainersMutex.RLock()
+	defer fake.findEvacuatableContainersMutex.RUnlock()
+	fake.recordContainerPlacementMutex.RLock()
+	defer fake.recordContainerPlacementMutex.RUnlock()
+	fake.getContainerHistoryMutex.RLock()
+	defer fake.getContainerHistoryMutex.RUnlock()
 	return fake.invocations
 }
 
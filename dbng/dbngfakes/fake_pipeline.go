@@ -2,6 +2,7 @@
 package dbngfakes
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
@@ -85,6 +86,24 @@ type FakePipeline struct {
 	pausedReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	ArchivedStub        func() bool
+	archivedMutex       sync.RWMutex
+	archivedArgsForCall []struct{}
+	archivedReturns     struct {
+		result1 bool
+	}
+	archivedReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	LastUpdatedStub        func() time.Time
+	lastUpdatedMutex       sync.RWMutex
+	lastUpdatedArgsForCall []struct{}
+	lastUpdatedReturns     struct {
+		result1 time.Time
+	}
+	lastUpdatedReturnsOnCall map[int]struct {
+		result1 time.Time
+	}
 	CheckPausedStub        func() (bool, error)
 	checkPausedMutex       sync.RWMutex
 	checkPausedArgsForCall []struct{}
@@ -165,6 +184,18 @@ type FakePipeline struct {
 	saveResourceVersionsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SaveResourceVersionsWithMetadataStub        func(config atc.ResourceConfig, versions []atc.VersionWithMetadata) error
+	saveResourceVersionsWithMetadataMutex       sync.RWMutex
+	saveResourceVersionsWithMetadataArgsForCall []struct {
+		config   atc.ResourceConfig
+		versions []atc.VersionWithMetadata
+	}
+	saveResourceVersionsWithMetadataReturns struct {
+		result1 error
+	}
+	saveResourceVersionsWithMetadataReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetResourceVersionsStub        func(resourceName string, page dbng.Page) ([]dbng.SavedVersionedResource, dbng.Pagination, bool, error)
 	getResourceVersionsMutex       sync.RWMutex
 	getResourceVersionsArgsForCall []struct {
@@ -214,6 +245,21 @@ type FakePipeline struct {
 		result2 bool
 		result3 error
 	}
+	GetTriggeringVersionStub        func(int) (*dbng.SavedVersionedResource, bool, error)
+	getTriggeringVersionMutex       sync.RWMutex
+	getTriggeringVersionArgsForCall []struct {
+		buildID int
+	}
+	getTriggeringVersionReturns struct {
+		result1 *dbng.SavedVersionedResource
+		result2 bool
+		result3 error
+	}
+	getTriggeringVersionReturnsOnCall map[int]struct {
+		result1 *dbng.SavedVersionedResource
+		result2 bool
+		result3 error
+	}
 	DisableVersionedResourceStub        func(versionedResourceID int) error
 	disableVersionedResourceMutex       sync.RWMutex
 	disableVersionedResourceArgsForCall []struct {
@@ -236,6 +282,21 @@ type FakePipeline struct {
 	enableVersionedResourceReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DisableVersionsWhereMetadataStub        func(resourceID int, key string, value string) (int, error)
+	disableVersionsWhereMetadataMutex       sync.RWMutex
+	disableVersionsWhereMetadataArgsForCall []struct {
+		resourceID int
+		key        string
+		value      string
+	}
+	disableVersionsWhereMetadataReturns struct {
+		result1 int
+		result2 error
+	}
+	disableVersionsWhereMetadataReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	SaveIndependentInputMappingStub        func(inputMapping algorithm.InputMapping, jobName string) error
 	saveIndependentInputMappingMutex       sync.RWMutex
 	saveIndependentInputMappingArgsForCall []struct {
@@ -299,10 +360,12 @@ type FakePipeline struct {
 	deleteNextInputMappingReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsurePendingBuildExistsStub        func(jobName string) error
+	EnsurePendingBuildExistsStub        func(string, int, dbng.BuildTriggerReason) error
 	ensurePendingBuildExistsMutex       sync.RWMutex
 	ensurePendingBuildExistsArgsForCall []struct {
-		jobName string
+		jobName              string
+		triggeredByVersionID int
+		reason               dbng.BuildTriggerReason
 	}
 	ensurePendingBuildExistsReturns struct {
 		result1 error
@@ -323,6 +386,19 @@ type FakePipeline struct {
 		result1 []dbng.Build
 		result2 error
 	}
+	GetPendingBuildsTriggeredByVersionStub        func(versionedResourceID int) ([]dbng.Build, error)
+	getPendingBuildsTriggeredByVersionMutex       sync.RWMutex
+	getPendingBuildsTriggeredByVersionArgsForCall []struct {
+		versionedResourceID int
+	}
+	getPendingBuildsTriggeredByVersionReturns struct {
+		result1 []dbng.Build
+		result2 error
+	}
+	getPendingBuildsTriggeredByVersionReturnsOnCall map[int]struct {
+		result1 []dbng.Build
+		result2 error
+	}
 	CreateJobBuildStub        func(jobName string) (dbng.Build, error)
 	createJobBuildMutex       sync.RWMutex
 	createJobBuildArgsForCall []struct {
@@ -505,6 +581,24 @@ type FakePipeline struct {
 	hideReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ArchiveStub        func() error
+	archiveMutex       sync.RWMutex
+	archiveArgsForCall []struct{}
+	archiveReturns     struct {
+		result1 error
+	}
+	archiveReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnarchiveStub        func() error
+	unarchiveMutex       sync.RWMutex
+	unarchiveArgsForCall []struct{}
+	unarchiveReturns     struct {
+		result1 error
+	}
+	unarchiveReturnsOnCall map[int]struct {
+		result1 error
+	}
 	PauseStub        func() error
 	pauseMutex       sync.RWMutex
 	pauseArgsForCall []struct{}
@@ -523,6 +617,17 @@ type FakePipeline struct {
 	unpauseReturnsOnCall map[int]struct {
 		result1 error
 	}
+	AbortAllBuildsStub        func() ([]int, error)
+	abortAllBuildsMutex       sync.RWMutex
+	abortAllBuildsArgsForCall []struct{}
+	abortAllBuildsReturns     struct {
+		result1 []int
+		result2 error
+	}
+	abortAllBuildsReturnsOnCall map[int]struct {
+		result1 []int
+		result2 error
+	}
 	DestroyStub        func() error
 	destroyMutex       sync.RWMutex
 	destroyArgsForCall []struct{}
@@ -543,6 +648,35 @@ type FakePipeline struct {
 	renameReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CreateJobBuildWithOverridesStub        func(jobName string, overrides atc.BuildOverrides) (dbng.Build, error)
+	createJobBuildWithOverridesMutex       sync.RWMutex
+	createJobBuildWithOverridesArgsForCall []struct {
+		jobName   string
+		overrides atc.BuildOverrides
+	}
+	createJobBuildWithOverridesReturns struct {
+		result1 dbng.Build
+		result2 error
+	}
+	createJobBuildWithOverridesReturnsOnCall map[int]struct {
+		result1 dbng.Build
+		result2 error
+	}
+	CreateRerunBuildStub        func(jobName string, sourceBuildID int, metadata map[string]json.RawMessage) (dbng.Build, error)
+	createRerunBuildMutex       sync.RWMutex
+	createRerunBuildArgsForCall []struct {
+		jobName       string
+		sourceBuildID int
+		metadata      map[string]json.RawMessage
+	}
+	createRerunBuildReturns struct {
+		result1 dbng.Build
+		result2 error
+	}
+	createRerunBuildReturnsOnCall map[int]struct {
+		result1 dbng.Build
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -867,6 +1001,86 @@ func (fake *FakePipeline) PausedReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *FakePipeline) Archived() bool {
+	fake.archivedMutex.Lock()
+	ret, specificReturn := fake.archivedReturnsOnCall[len(fake.archivedArgsForCall)]
+	fake.archivedArgsForCall = append(fake.archivedArgsForCall, struct{}{})
+	fake.recordInvocation("Archived", []interface{}{})
+	fake.archivedMutex.Unlock()
+	if fake.ArchivedStub != nil {
+		return fake.ArchivedStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.archivedReturns.result1
+}
+
+func (fake *FakePipeline) ArchivedCallCount() int {
+	fake.archivedMutex.RLock()
+	defer fake.archivedMutex.RUnlock()
+	return len(fake.archivedArgsForCall)
+}
+
+func (fake *FakePipeline) ArchivedReturns(result1 bool) {
+	fake.ArchivedStub = nil
+	fake.archivedReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePipeline) ArchivedReturnsOnCall(i int, result1 bool) {
+	fake.ArchivedStub = nil
+	if fake.archivedReturnsOnCall == nil {
+		fake.archivedReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.archivedReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePipeline) LastUpdated() time.Time {
+	fake.lastUpdatedMutex.Lock()
+	ret, specificReturn := fake.lastUpdatedReturnsOnCall[len(fake.lastUpdatedArgsForCall)]
+	fake.lastUpdatedArgsForCall = append(fake.lastUpdatedArgsForCall, struct{}{})
+	fake.recordInvocation("LastUpdated", []interface{}{})
+	fake.lastUpdatedMutex.Unlock()
+	if fake.LastUpdatedStub != nil {
+		return fake.LastUpdatedStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.lastUpdatedReturns.result1
+}
+
+func (fake *FakePipeline) LastUpdatedCallCount() int {
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
+	return len(fake.lastUpdatedArgsForCall)
+}
+
+func (fake *FakePipeline) LastUpdatedReturns(result1 time.Time) {
+	fake.LastUpdatedStub = nil
+	fake.lastUpdatedReturns = struct {
+		result1 time.Time
+	}{result1}
+}
+
+func (fake *FakePipeline) LastUpdatedReturnsOnCall(i int, result1 time.Time) {
+	fake.LastUpdatedStub = nil
+	if fake.lastUpdatedReturnsOnCall == nil {
+		fake.lastUpdatedReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+		})
+	}
+	fake.lastUpdatedReturnsOnCall[i] = struct {
+		result1 time.Time
+	}{result1}
+}
+
 func (fake *FakePipeline) CheckPaused() (bool, error) {
 	fake.checkPausedMutex.Lock()
 	ret, specificReturn := fake.checkPausedReturnsOnCall[len(fake.checkPausedArgsForCall)]
@@ -1196,6 +1410,60 @@ func (fake *FakePipeline) SaveResourceVersionsReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
+func (fake *FakePipeline) SaveResourceVersionsWithMetadata(config atc.ResourceConfig, versions []atc.VersionWithMetadata) error {
+	var versionsCopy []atc.VersionWithMetadata
+	if versions != nil {
+		versionsCopy = make([]atc.VersionWithMetadata, len(versions))
+		copy(versionsCopy, versions)
+	}
+	fake.saveResourceVersionsWithMetadataMutex.Lock()
+	ret, specificReturn := fake.saveResourceVersionsWithMetadataReturnsOnCall[len(fake.saveResourceVersionsWithMetadataArgsForCall)]
+	fake.saveResourceVersionsWithMetadataArgsForCall = append(fake.saveResourceVersionsWithMetadataArgsForCall, struct {
+		config   atc.ResourceConfig
+		versions []atc.VersionWithMetadata
+	}{config, versionsCopy})
+	fake.recordInvocation("SaveResourceVersionsWithMetadata", []interface{}{config, versionsCopy})
+	fake.saveResourceVersionsWithMetadataMutex.Unlock()
+	if fake.SaveResourceVersionsWithMetadataStub != nil {
+		return fake.SaveResourceVersionsWithMetadataStub(config, versions)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.saveResourceVersionsWithMetadataReturns.result1
+}
+
+func (fake *FakePipeline) SaveResourceVersionsWithMetadataCallCount() int {
+	fake.saveResourceVersionsWithMetadataMutex.RLock()
+	defer fake.saveResourceVersionsWithMetadataMutex.RUnlock()
+	return len(fake.saveResourceVersionsWithMetadataArgsForCall)
+}
+
+func (fake *FakePipeline) SaveResourceVersionsWithMetadataArgsForCall(i int) (atc.ResourceConfig, []atc.VersionWithMetadata) {
+	fake.saveResourceVersionsWithMetadataMutex.RLock()
+	defer fake.saveResourceVersionsWithMetadataMutex.RUnlock()
+	return fake.saveResourceVersionsWithMetadataArgsForCall[i].config, fake.saveResourceVersionsWithMetadataArgsForCall[i].versions
+}
+
+func (fake *FakePipeline) SaveResourceVersionsWithMetadataReturns(result1 error) {
+	fake.SaveResourceVersionsWithMetadataStub = nil
+	fake.saveResourceVersionsWithMetadataReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) SaveResourceVersionsWithMetadataReturnsOnCall(i int, result1 error) {
+	fake.SaveResourceVersionsWithMetadataStub = nil
+	if fake.saveResourceVersionsWithMetadataReturnsOnCall == nil {
+		fake.saveResourceVersionsWithMetadataReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveResourceVersionsWithMetadataReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) GetResourceVersions(resourceName string, page dbng.Page) ([]dbng.SavedVersionedResource, dbng.Pagination, bool, error) {
 	fake.getResourceVersionsMutex.Lock()
 	ret, specificReturn := fake.getResourceVersionsReturnsOnCall[len(fake.getResourceVersionsArgsForCall)]
@@ -1363,6 +1631,60 @@ func (fake *FakePipeline) GetVersionedResourceByVersionReturnsOnCall(i int, resu
 	}{result1, result2, result3}
 }
 
+func (fake *FakePipeline) GetTriggeringVersion(buildID int) (*dbng.SavedVersionedResource, bool, error) {
+	fake.getTriggeringVersionMutex.Lock()
+	ret, specificReturn := fake.getTriggeringVersionReturnsOnCall[len(fake.getTriggeringVersionArgsForCall)]
+	fake.getTriggeringVersionArgsForCall = append(fake.getTriggeringVersionArgsForCall, struct {
+		buildID int
+	}{buildID})
+	fake.recordInvocation("GetTriggeringVersion", []interface{}{buildID})
+	fake.getTriggeringVersionMutex.Unlock()
+	if fake.GetTriggeringVersionStub != nil {
+		return fake.GetTriggeringVersionStub(buildID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getTriggeringVersionReturns.result1, fake.getTriggeringVersionReturns.result2, fake.getTriggeringVersionReturns.result3
+}
+
+func (fake *FakePipeline) GetTriggeringVersionCallCount() int {
+	fake.getTriggeringVersionMutex.RLock()
+	defer fake.getTriggeringVersionMutex.RUnlock()
+	return len(fake.getTriggeringVersionArgsForCall)
+}
+
+func (fake *FakePipeline) GetTriggeringVersionArgsForCall(i int) int {
+	fake.getTriggeringVersionMutex.RLock()
+	defer fake.getTriggeringVersionMutex.RUnlock()
+	return fake.getTriggeringVersionArgsForCall[i].buildID
+}
+
+func (fake *FakePipeline) GetTriggeringVersionReturns(result1 *dbng.SavedVersionedResource, result2 bool, result3 error) {
+	fake.GetTriggeringVersionStub = nil
+	fake.getTriggeringVersionReturns = struct {
+		result1 *dbng.SavedVersionedResource
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakePipeline) GetTriggeringVersionReturnsOnCall(i int, result1 *dbng.SavedVersionedResource, result2 bool, result3 error) {
+	fake.GetTriggeringVersionStub = nil
+	if fake.getTriggeringVersionReturnsOnCall == nil {
+		fake.getTriggeringVersionReturnsOnCall = make(map[int]struct {
+			result1 *dbng.SavedVersionedResource
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getTriggeringVersionReturnsOnCall[i] = struct {
+		result1 *dbng.SavedVersionedResource
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakePipeline) DisableVersionedResource(versionedResourceID int) error {
 	fake.disableVersionedResourceMutex.Lock()
 	ret, specificReturn := fake.disableVersionedResourceReturnsOnCall[len(fake.disableVersionedResourceArgsForCall)]
@@ -1459,6 +1781,59 @@ func (fake *FakePipeline) EnableVersionedResourceReturnsOnCall(i int, result1 er
 	}{result1}
 }
 
+func (fake *FakePipeline) DisableVersionsWhereMetadata(resourceID int, key string, value string) (int, error) {
+	fake.disableVersionsWhereMetadataMutex.Lock()
+	ret, specificReturn := fake.disableVersionsWhereMetadataReturnsOnCall[len(fake.disableVersionsWhereMetadataArgsForCall)]
+	fake.disableVersionsWhereMetadataArgsForCall = append(fake.disableVersionsWhereMetadataArgsForCall, struct {
+		resourceID int
+		key        string
+		value      string
+	}{resourceID, key, value})
+	fake.recordInvocation("DisableVersionsWhereMetadata", []interface{}{resourceID, key, value})
+	fake.disableVersionsWhereMetadataMutex.Unlock()
+	if fake.DisableVersionsWhereMetadataStub != nil {
+		return fake.DisableVersionsWhereMetadataStub(resourceID, key, value)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.disableVersionsWhereMetadataReturns.result1, fake.disableVersionsWhereMetadataReturns.result2
+}
+
+func (fake *FakePipeline) DisableVersionsWhereMetadataCallCount() int {
+	fake.disableVersionsWhereMetadataMutex.RLock()
+	defer fake.disableVersionsWhereMetadataMutex.RUnlock()
+	return len(fake.disableVersionsWhereMetadataArgsForCall)
+}
+
+func (fake *FakePipeline) DisableVersionsWhereMetadataArgsForCall(i int) (int, string, string) {
+	fake.disableVersionsWhereMetadataMutex.RLock()
+	defer fake.disableVersionsWhereMetadataMutex.RUnlock()
+	return fake.disableVersionsWhereMetadataArgsForCall[i].resourceID, fake.disableVersionsWhereMetadataArgsForCall[i].key, fake.disableVersionsWhereMetadataArgsForCall[i].value
+}
+
+func (fake *FakePipeline) DisableVersionsWhereMetadataReturns(result1 int, result2 error) {
+	fake.DisableVersionsWhereMetadataStub = nil
+	fake.disableVersionsWhereMetadataReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipeline) DisableVersionsWhereMetadataReturnsOnCall(i int, result1 int, result2 error) {
+	fake.DisableVersionsWhereMetadataStub = nil
+	if fake.disableVersionsWhereMetadataReturnsOnCall == nil {
+		fake.disableVersionsWhereMetadataReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.disableVersionsWhereMetadataReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipeline) SaveIndependentInputMapping(inputMapping algorithm.InputMapping, jobName string) error {
 	fake.saveIndependentInputMappingMutex.Lock()
 	ret, specificReturn := fake.saveIndependentInputMappingReturnsOnCall[len(fake.saveIndependentInputMappingArgsForCall)]
@@ -1710,16 +2085,18 @@ func (fake *FakePipeline) DeleteNextInputMappingReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakePipeline) EnsurePendingBuildExists(jobName string) error {
+func (fake *FakePipeline) EnsurePendingBuildExists(jobName string, triggeredByVersionID int, reason dbng.BuildTriggerReason) error {
 	fake.ensurePendingBuildExistsMutex.Lock()
 	ret, specificReturn := fake.ensurePendingBuildExistsReturnsOnCall[len(fake.ensurePendingBuildExistsArgsForCall)]
 	fake.ensurePendingBuildExistsArgsForCall = append(fake.ensurePendingBuildExistsArgsForCall, struct {
-		jobName string
-	}{jobName})
-	fake.recordInvocation("EnsurePendingBuildExists", []interface{}{jobName})
+		jobName              string
+		triggeredByVersionID int
+		reason               dbng.BuildTriggerReason
+	}{jobName, triggeredByVersionID, reason})
+	fake.recordInvocation("EnsurePendingBuildExists", []interface{}{jobName, triggeredByVersionID, reason})
 	fake.ensurePendingBuildExistsMutex.Unlock()
 	if fake.EnsurePendingBuildExistsStub != nil {
-		return fake.EnsurePendingBuildExistsStub(jobName)
+		return fake.EnsurePendingBuildExistsStub(jobName, triggeredByVersionID, reason)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1733,10 +2110,10 @@ func (fake *FakePipeline) EnsurePendingBuildExistsCallCount() int {
 	return len(fake.ensurePendingBuildExistsArgsForCall)
 }
 
-func (fake *FakePipeline) EnsurePendingBuildExistsArgsForCall(i int) string {
+func (fake *FakePipeline) EnsurePendingBuildExistsArgsForCall(i int) (string, int, dbng.BuildTriggerReason) {
 	fake.ensurePendingBuildExistsMutex.RLock()
 	defer fake.ensurePendingBuildExistsMutex.RUnlock()
-	return fake.ensurePendingBuildExistsArgsForCall[i].jobName
+	return fake.ensurePendingBuildExistsArgsForCall[i].jobName, fake.ensurePendingBuildExistsArgsForCall[i].triggeredByVersionID, fake.ensurePendingBuildExistsArgsForCall[i].reason
 }
 
 func (fake *FakePipeline) EnsurePendingBuildExistsReturns(result1 error) {
@@ -1809,6 +2186,57 @@ func (fake *FakePipeline) GetPendingBuildsForJobReturnsOnCall(i int, result1 []d
 	}{result1, result2}
 }
 
+func (fake *FakePipeline) GetPendingBuildsTriggeredByVersion(versionedResourceID int) ([]dbng.Build, error) {
+	fake.getPendingBuildsTriggeredByVersionMutex.Lock()
+	ret, specificReturn := fake.getPendingBuildsTriggeredByVersionReturnsOnCall[len(fake.getPendingBuildsTriggeredByVersionArgsForCall)]
+	fake.getPendingBuildsTriggeredByVersionArgsForCall = append(fake.getPendingBuildsTriggeredByVersionArgsForCall, struct {
+		versionedResourceID int
+	}{versionedResourceID})
+	fake.recordInvocation("GetPendingBuildsTriggeredByVersion", []interface{}{versionedResourceID})
+	fake.getPendingBuildsTriggeredByVersionMutex.Unlock()
+	if fake.GetPendingBuildsTriggeredByVersionStub != nil {
+		return fake.GetPendingBuildsTriggeredByVersionStub(versionedResourceID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPendingBuildsTriggeredByVersionReturns.result1, fake.getPendingBuildsTriggeredByVersionReturns.result2
+}
+
+func (fake *FakePipeline) GetPendingBuildsTriggeredByVersionCallCount() int {
+	fake.getPendingBuildsTriggeredByVersionMutex.RLock()
+	defer fake.getPendingBuildsTriggeredByVersionMutex.RUnlock()
+	return len(fake.getPendingBuildsTriggeredByVersionArgsForCall)
+}
+
+func (fake *FakePipeline) GetPendingBuildsTriggeredByVersionArgsForCall(i int) int {
+	fake.getPendingBuildsTriggeredByVersionMutex.RLock()
+	defer fake.getPendingBuildsTriggeredByVersionMutex.RUnlock()
+	return fake.getPendingBuildsTriggeredByVersionArgsForCall[i].versionedResourceID
+}
+
+func (fake *FakePipeline) GetPendingBuildsTriggeredByVersionReturns(result1 []dbng.Build, result2 error) {
+	fake.GetPendingBuildsTriggeredByVersionStub = nil
+	fake.getPendingBuildsTriggeredByVersionReturns = struct {
+		result1 []dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipeline) GetPendingBuildsTriggeredByVersionReturnsOnCall(i int, result1 []dbng.Build, result2 error) {
+	fake.GetPendingBuildsTriggeredByVersionStub = nil
+	if fake.getPendingBuildsTriggeredByVersionReturnsOnCall == nil {
+		fake.getPendingBuildsTriggeredByVersionReturnsOnCall = make(map[int]struct {
+			result1 []dbng.Build
+			result2 error
+		})
+	}
+	fake.getPendingBuildsTriggeredByVersionReturnsOnCall[i] = struct {
+		result1 []dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipeline) CreateJobBuild(jobName string) (dbng.Build, error) {
 	fake.createJobBuildMutex.Lock()
 	ret, specificReturn := fake.createJobBuildReturnsOnCall[len(fake.createJobBuildArgsForCall)]
@@ -2495,6 +2923,86 @@ func (fake *FakePipeline) HideReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipeline) Archive() error {
+	fake.archiveMutex.Lock()
+	ret, specificReturn := fake.archiveReturnsOnCall[len(fake.archiveArgsForCall)]
+	fake.archiveArgsForCall = append(fake.archiveArgsForCall, struct{}{})
+	fake.recordInvocation("Archive", []interface{}{})
+	fake.archiveMutex.Unlock()
+	if fake.ArchiveStub != nil {
+		return fake.ArchiveStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.archiveReturns.result1
+}
+
+func (fake *FakePipeline) ArchiveCallCount() int {
+	fake.archiveMutex.RLock()
+	defer fake.archiveMutex.RUnlock()
+	return len(fake.archiveArgsForCall)
+}
+
+func (fake *FakePipeline) ArchiveReturns(result1 error) {
+	fake.ArchiveStub = nil
+	fake.archiveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) ArchiveReturnsOnCall(i int, result1 error) {
+	fake.ArchiveStub = nil
+	if fake.archiveReturnsOnCall == nil {
+		fake.archiveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.archiveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) Unarchive() error {
+	fake.unarchiveMutex.Lock()
+	ret, specificReturn := fake.unarchiveReturnsOnCall[len(fake.unarchiveArgsForCall)]
+	fake.unarchiveArgsForCall = append(fake.unarchiveArgsForCall, struct{}{})
+	fake.recordInvocation("Unarchive", []interface{}{})
+	fake.unarchiveMutex.Unlock()
+	if fake.UnarchiveStub != nil {
+		return fake.UnarchiveStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.unarchiveReturns.result1
+}
+
+func (fake *FakePipeline) UnarchiveCallCount() int {
+	fake.unarchiveMutex.RLock()
+	defer fake.unarchiveMutex.RUnlock()
+	return len(fake.unarchiveArgsForCall)
+}
+
+func (fake *FakePipeline) UnarchiveReturns(result1 error) {
+	fake.UnarchiveStub = nil
+	fake.unarchiveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePipeline) UnarchiveReturnsOnCall(i int, result1 error) {
+	fake.UnarchiveStub = nil
+	if fake.unarchiveReturnsOnCall == nil {
+		fake.unarchiveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unarchiveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePipeline) Pause() error {
 	fake.pauseMutex.Lock()
 	ret, specificReturn := fake.pauseReturnsOnCall[len(fake.pauseArgsForCall)]
@@ -2575,6 +3083,49 @@ func (fake *FakePipeline) UnpauseReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipeline) AbortAllBuilds() ([]int, error) {
+	fake.abortAllBuildsMutex.Lock()
+	ret, specificReturn := fake.abortAllBuildsReturnsOnCall[len(fake.abortAllBuildsArgsForCall)]
+	fake.abortAllBuildsArgsForCall = append(fake.abortAllBuildsArgsForCall, struct{}{})
+	fake.recordInvocation("AbortAllBuilds", []interface{}{})
+	fake.abortAllBuildsMutex.Unlock()
+	if fake.AbortAllBuildsStub != nil {
+		return fake.AbortAllBuildsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.abortAllBuildsReturns.result1, fake.abortAllBuildsReturns.result2
+}
+
+func (fake *FakePipeline) AbortAllBuildsCallCount() int {
+	fake.abortAllBuildsMutex.RLock()
+	defer fake.abortAllBuildsMutex.RUnlock()
+	return len(fake.abortAllBuildsArgsForCall)
+}
+
+func (fake *FakePipeline) AbortAllBuildsReturns(result1 []int, result2 error) {
+	fake.AbortAllBuildsStub = nil
+	fake.abortAllBuildsReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipeline) AbortAllBuildsReturnsOnCall(i int, result1 []int, result2 error) {
+	fake.AbortAllBuildsStub = nil
+	if fake.abortAllBuildsReturnsOnCall == nil {
+		fake.abortAllBuildsReturnsOnCall = make(map[int]struct {
+			result1 []int
+			result2 error
+		})
+	}
+	fake.abortAllBuildsReturnsOnCall[i] = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipeline) Destroy() error {
 	fake.destroyMutex.Lock()
 	ret, specificReturn := fake.destroyReturnsOnCall[len(fake.destroyArgsForCall)]
@@ -2663,6 +3214,111 @@ func (fake *FakePipeline) RenameReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakePipeline) CreateJobBuildWithOverrides(jobName string, overrides atc.BuildOverrides) (dbng.Build, error) {
+	fake.createJobBuildWithOverridesMutex.Lock()
+	ret, specificReturn := fake.createJobBuildWithOverridesReturnsOnCall[len(fake.createJobBuildWithOverridesArgsForCall)]
+	fake.createJobBuildWithOverridesArgsForCall = append(fake.createJobBuildWithOverridesArgsForCall, struct {
+		jobName   string
+		overrides atc.BuildOverrides
+	}{jobName, overrides})
+	fake.recordInvocation("CreateJobBuildWithOverrides", []interface{}{jobName, overrides})
+	fake.createJobBuildWithOverridesMutex.Unlock()
+	if fake.CreateJobBuildWithOverridesStub != nil {
+		return fake.CreateJobBuildWithOverridesStub(jobName, overrides)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.createJobBuildWithOverridesReturns.result1, fake.createJobBuildWithOverridesReturns.result2
+}
+
+func (fake *FakePipeline) CreateJobBuildWithOverridesCallCount() int {
+	fake.createJobBuildWithOverridesMutex.RLock()
+	defer fake.createJobBuildWithOverridesMutex.RUnlock()
+	return len(fake.createJobBuildWithOverridesArgsForCall)
+}
+
+func (fake *FakePipeline) CreateJobBuildWithOverridesArgsForCall(i int) (string, atc.BuildOverrides) {
+	fake.createJobBuildWithOverridesMutex.RLock()
+	defer fake.createJobBuildWithOverridesMutex.RUnlock()
+	return fake.createJobBuildWithOverridesArgsForCall[i].jobName, fake.createJobBuildWithOverridesArgsForCall[i].overrides
+}
+
+func (fake *FakePipeline) CreateJobBuildWithOverridesReturns(result1 dbng.Build, result2 error) {
+	fake.CreateJobBuildWithOverridesStub = nil
+	fake.createJobBuildWithOverridesReturns = struct {
+		result1 dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipeline) CreateJobBuildWithOverridesReturnsOnCall(i int, result1 dbng.Build, result2 error) {
+	fake.CreateJobBuildWithOverridesStub = nil
+	if fake.createJobBuildWithOverridesReturnsOnCall == nil {
+		fake.createJobBuildWithOverridesReturnsOnCall = make(map[int]struct {
+			result1 dbng.Build
+			result2 error
+		})
+	}
+	fake.createJobBuildWithOverridesReturnsOnCall[i] = struct {
+		result1 dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipeline) CreateRerunBuild(jobName string, sourceBuildID int, metadata map[string]json.RawMessage) (dbng.Build, error) {
+	fake.createRerunBuildMutex.Lock()
+	ret, specificReturn := fake.createRerunBuildReturnsOnCall[len(fake.createRerunBuildArgsForCall)]
+	fake.createRerunBuildArgsForCall = append(fake.createRerunBuildArgsForCall, struct {
+		jobName       string
+		sourceBuildID int
+		metadata      map[string]json.RawMessage
+	}{jobName, sourceBuildID, metadata})
+	fake.recordInvocation("CreateRerunBuild", []interface{}{jobName, sourceBuildID, metadata})
+	fake.createRerunBuildMutex.Unlock()
+	if fake.CreateRerunBuildStub != nil {
+		return fake.CreateRerunBuildStub(jobName, sourceBuildID, metadata)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.createRerunBuildReturns.result1, fake.createRerunBuildReturns.result2
+}
+
+func (fake *FakePipeline) CreateRerunBuildCallCount() int {
+	fake.createRerunBuildMutex.RLock()
+	defer fake.createRerunBuildMutex.RUnlock()
+	return len(fake.createRerunBuildArgsForCall)
+}
+
+func (fake *FakePipeline) CreateRerunBuildArgsForCall(i int) (string, int, map[string]json.RawMessage) {
+	fake.createRerunBuildMutex.RLock()
+	defer fake.createRerunBuildMutex.RUnlock()
+	return fake.createRerunBuildArgsForCall[i].jobName, fake.createRerunBuildArgsForCall[i].sourceBuildID, fake.createRerunBuildArgsForCall[i].metadata
+}
+
+func (fake *FakePipeline) CreateRerunBuildReturns(result1 dbng.Build, result2 error) {
+	fake.CreateRerunBuildStub = nil
+	fake.createRerunBuildReturns = struct {
+		result1 dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipeline) CreateRerunBuildReturnsOnCall(i int, result1 dbng.Build, result2 error) {
+	fake.CreateRerunBuildStub = nil
+	if fake.createRerunBuildReturnsOnCall == nil {
+		fake.createRerunBuildReturnsOnCall = make(map[int]struct {
+			result1 dbng.Build
+			result2 error
+		})
+	}
+	fake.createRerunBuildReturnsOnCall[i] = struct {
+		result1 dbng.Build
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -2682,6 +3338,10 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.publicMutex.RUnlock()
 	fake.pausedMutex.RLock()
 	defer fake.pausedMutex.RUnlock()
+	fake.archivedMutex.RLock()
+	defer fake.archivedMutex.RUnlock()
+	fake.lastUpdatedMutex.RLock()
+	defer fake.lastUpdatedMutex.RUnlock()
 	fake.checkPausedMutex.RLock()
 	defer fake.checkPausedMutex.RUnlock()
 	fake.reloadMutex.RLock()
@@ -2696,16 +3356,22 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.getAllPendingBuildsMutex.RUnlock()
 	fake.saveResourceVersionsMutex.RLock()
 	defer fake.saveResourceVersionsMutex.RUnlock()
+	fake.saveResourceVersionsWithMetadataMutex.RLock()
+	defer fake.saveResourceVersionsWithMetadataMutex.RUnlock()
 	fake.getResourceVersionsMutex.RLock()
 	defer fake.getResourceVersionsMutex.RUnlock()
 	fake.getLatestVersionedResourceMutex.RLock()
 	defer fake.getLatestVersionedResourceMutex.RUnlock()
 	fake.getVersionedResourceByVersionMutex.RLock()
 	defer fake.getVersionedResourceByVersionMutex.RUnlock()
+	fake.getTriggeringVersionMutex.RLock()
+	defer fake.getTriggeringVersionMutex.RUnlock()
 	fake.disableVersionedResourceMutex.RLock()
 	defer fake.disableVersionedResourceMutex.RUnlock()
 	fake.enableVersionedResourceMutex.RLock()
 	defer fake.enableVersionedResourceMutex.RUnlock()
+	fake.disableVersionsWhereMetadataMutex.RLock()
+	defer fake.disableVersionsWhereMetadataMutex.RUnlock()
 	fake.saveIndependentInputMappingMutex.RLock()
 	defer fake.saveIndependentInputMappingMutex.RUnlock()
 	fake.saveNextInputMappingMutex.RLock()
@@ -2720,6 +3386,8 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.ensurePendingBuildExistsMutex.RUnlock()
 	fake.getPendingBuildsForJobMutex.RLock()
 	defer fake.getPendingBuildsForJobMutex.RUnlock()
+	fake.getPendingBuildsTriggeredByVersionMutex.RLock()
+	defer fake.getPendingBuildsTriggeredByVersionMutex.RUnlock()
 	fake.createJobBuildMutex.RLock()
 	defer fake.createJobBuildMutex.RUnlock()
 	fake.nextBuildInputsMutex.RLock()
@@ -2748,14 +3416,24 @@ func (fake *FakePipeline) Invocations() map[string][][]interface{} {
 	defer fake.exposeMutex.RUnlock()
 	fake.hideMutex.RLock()
 	defer fake.hideMutex.RUnlock()
+	fake.archiveMutex.RLock()
+	defer fake.archiveMutex.RUnlock()
+	fake.unarchiveMutex.RLock()
+	defer fake.unarchiveMutex.RUnlock()
 	fake.pauseMutex.RLock()
 	defer fake.pauseMutex.RUnlock()
 	fake.unpauseMutex.RLock()
 	defer fake.unpauseMutex.RUnlock()
+	fake.abortAllBuildsMutex.RLock()
+	defer fake.abortAllBuildsMutex.RUnlock()
 	fake.destroyMutex.RLock()
 	defer fake.destroyMutex.RUnlock()
 	fake.renameMutex.RLock()
 	defer fake.renameMutex.RUnlock()
+	fake.createJobBuildWithOverridesMutex.RLock()
+	defer fake.createJobBuildWithOverridesMutex.RUnlock()
+	fake.createRerunBuildMutex.RLock()
+	defer fake.createRerunBuildMutex.RUnlock()
 	return fake.invocations
 }
 
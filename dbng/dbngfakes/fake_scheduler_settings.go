@@ -0,0 +1,150 @@
+// This file was generated by counterfeiter
+package dbngfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/dbng"
+)
+
+type FakeSchedulerSettings struct {
+	SetSchedulerPausedStub        func(bool) error
+	setSchedulerPausedMutex       sync.RWMutex
+	setSchedulerPausedArgsForCall []struct {
+		arg1 bool
+	}
+	setSchedulerPausedReturns struct {
+		result1 error
+	}
+	setSchedulerPausedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	IsSchedulerPausedStub        func() (bool, error)
+	isSchedulerPausedMutex       sync.RWMutex
+	isSchedulerPausedArgsForCall []struct{}
+	isSchedulerPausedReturns     struct {
+		result1 bool
+		result2 error
+	}
+	isSchedulerPausedReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSchedulerSettings) SetSchedulerPaused(arg1 bool) error {
+	fake.setSchedulerPausedMutex.Lock()
+	ret, specificReturn := fake.setSchedulerPausedReturnsOnCall[len(fake.setSchedulerPausedArgsForCall)]
+	fake.setSchedulerPausedArgsForCall = append(fake.setSchedulerPausedArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	fake.recordInvocation("SetSchedulerPaused", []interface{}{arg1})
+	fake.setSchedulerPausedMutex.Unlock()
+	if fake.SetSchedulerPausedStub != nil {
+		return fake.SetSchedulerPausedStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setSchedulerPausedReturns.result1
+}
+
+func (fake *FakeSchedulerSettings) SetSchedulerPausedCallCount() int {
+	fake.setSchedulerPausedMutex.RLock()
+	defer fake.setSchedulerPausedMutex.RUnlock()
+	return len(fake.setSchedulerPausedArgsForCall)
+}
+
+func (fake *FakeSchedulerSettings) SetSchedulerPausedArgsForCall(i int) bool {
+	fake.setSchedulerPausedMutex.RLock()
+	defer fake.setSchedulerPausedMutex.RUnlock()
+	return fake.setSchedulerPausedArgsForCall[i].arg1
+}
+
+func (fake *FakeSchedulerSettings) SetSchedulerPausedReturns(result1 error) {
+	fake.SetSchedulerPausedStub = nil
+	fake.setSchedulerPausedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSchedulerSettings) SetSchedulerPausedReturnsOnCall(i int, result1 error) {
+	fake.SetSchedulerPausedStub = nil
+	if fake.setSchedulerPausedReturnsOnCall == nil {
+		fake.setSchedulerPausedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setSchedulerPausedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSchedulerSettings) IsSchedulerPaused() (bool, error) {
+	fake.isSchedulerPausedMutex.Lock()
+	ret, specificReturn := fake.isSchedulerPausedReturnsOnCall[len(fake.isSchedulerPausedArgsForCall)]
+	fake.isSchedulerPausedArgsForCall = append(fake.isSchedulerPausedArgsForCall, struct{}{})
+	fake.recordInvocation("IsSchedulerPaused", []interface{}{})
+	fake.isSchedulerPausedMutex.Unlock()
+	if fake.IsSchedulerPausedStub != nil {
+		return fake.IsSchedulerPausedStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.isSchedulerPausedReturns.result1, fake.isSchedulerPausedReturns.result2
+}
+
+func (fake *FakeSchedulerSettings) IsSchedulerPausedCallCount() int {
+	fake.isSchedulerPausedMutex.RLock()
+	defer fake.isSchedulerPausedMutex.RUnlock()
+	return len(fake.isSchedulerPausedArgsForCall)
+}
+
+func (fake *FakeSchedulerSettings) IsSchedulerPausedReturns(result1 bool, result2 error) {
+	fake.IsSchedulerPausedStub = nil
+	fake.isSchedulerPausedReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSchedulerSettings) IsSchedulerPausedReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.IsSchedulerPausedStub = nil
+	if fake.isSchedulerPausedReturnsOnCall == nil {
+		fake.isSchedulerPausedReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.isSchedulerPausedReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSchedulerSettings) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.setSchedulerPausedMutex.RLock()
+	defer fake.setSchedulerPausedMutex.RUnlock()
+	fake.isSchedulerPausedMutex.RLock()
+	defer fake.isSchedulerPausedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeSchedulerSettings) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ dbng.SchedulerSettings = new(FakeSchedulerSettings)
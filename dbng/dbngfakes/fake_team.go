@@ -99,6 +99,23 @@ type FakeTeam struct {
 		result2 bool
 		result3 error
 	}
+	PipelineConfigStub        func(pipelineName string) (atc.Config, atc.RawConfig, dbng.ConfigVersion, error)
+	pipelineConfigMutex       sync.RWMutex
+	pipelineConfigArgsForCall []struct {
+		pipelineName string
+	}
+	pipelineConfigReturns struct {
+		result1 atc.Config
+		result2 atc.RawConfig
+		result3 dbng.ConfigVersion
+		result4 error
+	}
+	pipelineConfigReturnsOnCall map[int]struct {
+		result1 atc.Config
+		result2 atc.RawConfig
+		result3 dbng.ConfigVersion
+		result4 error
+	}
 	PipelinesStub        func() ([]dbng.Pipeline, error)
 	pipelinesMutex       sync.RWMutex
 	pipelinesArgsForCall []struct{}
@@ -377,6 +394,41 @@ type FakeTeam struct {
 		result1 dbng.CreatingContainer
 		result2 error
 	}
+	FindLatestAttemptBuildContainerStub        func(int, string) (dbng.CreatedContainer, bool, error)
+	findLatestAttemptBuildContainerMutex       sync.RWMutex
+	findLatestAttemptBuildContainerArgsForCall []struct {
+		buildID  int
+		stepName string
+	}
+	findLatestAttemptBuildContainerReturns struct {
+		result1 dbng.CreatedContainer
+		result2 bool
+		result3 error
+	}
+	findLatestAttemptBuildContainerReturnsOnCall map[int]struct {
+		result1 dbng.CreatedContainer
+		result2 bool
+		result3 error
+	}
+	FindInterceptTargetStub        func(buildID int, stepName string, attempt []int) (string, string, bool, error)
+	findInterceptTargetMutex       sync.RWMutex
+	findInterceptTargetArgsForCall []struct {
+		buildID  int
+		stepName string
+		attempt  []int
+	}
+	findInterceptTargetReturns struct {
+		result1 string
+		result2 string
+		result3 bool
+		result4 error
+	}
+	findInterceptTargetReturnsOnCall map[int]struct {
+		result1 string
+		result2 string
+		result3 bool
+		result4 error
+	}
 	UpdateBasicAuthStub        func(basicAuth *atc.BasicAuth) error
 	updateBasicAuthMutex       sync.RWMutex
 	updateBasicAuthArgsForCall []struct {
@@ -754,6 +806,63 @@ func (fake *FakeTeam) PipelineReturnsOnCall(i int, result1 dbng.Pipeline, result
 	}{result1, result2, result3}
 }
 
+func (fake *FakeTeam) PipelineConfig(pipelineName string) (atc.Config, atc.RawConfig, dbng.ConfigVersion, error) {
+	fake.pipelineConfigMutex.Lock()
+	ret, specificReturn := fake.pipelineConfigReturnsOnCall[len(fake.pipelineConfigArgsForCall)]
+	fake.pipelineConfigArgsForCall = append(fake.pipelineConfigArgsForCall, struct {
+		pipelineName string
+	}{pipelineName})
+	fake.recordInvocation("PipelineConfig", []interface{}{pipelineName})
+	fake.pipelineConfigMutex.Unlock()
+	if fake.PipelineConfigStub != nil {
+		return fake.PipelineConfigStub(pipelineName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	return fake.pipelineConfigReturns.result1, fake.pipelineConfigReturns.result2, fake.pipelineConfigReturns.result3, fake.pipelineConfigReturns.result4
+}
+
+func (fake *FakeTeam) PipelineConfigCallCount() int {
+	fake.pipelineConfigMutex.RLock()
+	defer fake.pipelineConfigMutex.RUnlock()
+	return len(fake.pipelineConfigArgsForCall)
+}
+
+func (fake *FakeTeam) PipelineConfigArgsForCall(i int) string {
+	fake.pipelineConfigMutex.RLock()
+	defer fake.pipelineConfigMutex.RUnlock()
+	return fake.pipelineConfigArgsForCall[i].pipelineName
+}
+
+func (fake *FakeTeam) PipelineConfigReturns(result1 atc.Config, result2 atc.RawConfig, result3 dbng.ConfigVersion, result4 error) {
+	fake.PipelineConfigStub = nil
+	fake.pipelineConfigReturns = struct {
+		result1 atc.Config
+		result2 atc.RawConfig
+		result3 dbng.ConfigVersion
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeTeam) PipelineConfigReturnsOnCall(i int, result1 atc.Config, result2 atc.RawConfig, result3 dbng.ConfigVersion, result4 error) {
+	fake.PipelineConfigStub = nil
+	if fake.pipelineConfigReturnsOnCall == nil {
+		fake.pipelineConfigReturnsOnCall = make(map[int]struct {
+			result1 atc.Config
+			result2 atc.RawConfig
+			result3 dbng.ConfigVersion
+			result4 error
+		})
+	}
+	fake.pipelineConfigReturnsOnCall[i] = struct {
+		result1 atc.Config
+		result2 atc.RawConfig
+		result3 dbng.ConfigVersion
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
 func (fake *FakeTeam) Pipelines() ([]dbng.Pipeline, error) {
 	fake.pipelinesMutex.Lock()
 	ret, specificReturn := fake.pipelinesReturnsOnCall[len(fake.pipelinesArgsForCall)]
@@ -1774,6 +1883,125 @@ func (fake *FakeTeam) CreateBuildContainerReturnsOnCall(i int, result1 dbng.Crea
 	}{result1, result2}
 }
 
+func (fake *FakeTeam) FindLatestAttemptBuildContainer(buildID int, stepName string) (dbng.CreatedContainer, bool, error) {
+	fake.findLatestAttemptBuildContainerMutex.Lock()
+	ret, specificReturn := fake.findLatestAttemptBuildContainerReturnsOnCall[len(fake.findLatestAttemptBuildContainerArgsForCall)]
+	fake.findLatestAttemptBuildContainerArgsForCall = append(fake.findLatestAttemptBuildContainerArgsForCall, struct {
+		buildID  int
+		stepName string
+	}{buildID, stepName})
+	fake.recordInvocation("FindLatestAttemptBuildContainer", []interface{}{buildID, stepName})
+	fake.findLatestAttemptBuildContainerMutex.Unlock()
+	if fake.FindLatestAttemptBuildContainerStub != nil {
+		return fake.FindLatestAttemptBuildContainerStub(buildID, stepName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.findLatestAttemptBuildContainerReturns.result1, fake.findLatestAttemptBuildContainerReturns.result2, fake.findLatestAttemptBuildContainerReturns.result3
+}
+
+func (fake *FakeTeam) FindLatestAttemptBuildContainerCallCount() int {
+	fake.findLatestAttemptBuildContainerMutex.RLock()
+	defer fake.findLatestAttemptBuildContainerMutex.RUnlock()
+	return len(fake.findLatestAttemptBuildContainerArgsForCall)
+}
+
+func (fake *FakeTeam) FindLatestAttemptBuildContainerArgsForCall(i int) (int, string) {
+	fake.findLatestAttemptBuildContainerMutex.RLock()
+	defer fake.findLatestAttemptBuildContainerMutex.RUnlock()
+	return fake.findLatestAttemptBuildContainerArgsForCall[i].buildID, fake.findLatestAttemptBuildContainerArgsForCall[i].stepName
+}
+
+func (fake *FakeTeam) FindLatestAttemptBuildContainerReturns(result1 dbng.CreatedContainer, result2 bool, result3 error) {
+	fake.FindLatestAttemptBuildContainerStub = nil
+	fake.findLatestAttemptBuildContainerReturns = struct {
+		result1 dbng.CreatedContainer
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeam) FindLatestAttemptBuildContainerReturnsOnCall(i int, result1 dbng.CreatedContainer, result2 bool, result3 error) {
+	fake.FindLatestAttemptBuildContainerStub = nil
+	if fake.findLatestAttemptBuildContainerReturnsOnCall == nil {
+		fake.findLatestAttemptBuildContainerReturnsOnCall = make(map[int]struct {
+			result1 dbng.CreatedContainer
+			result2 bool
+			result3 error
+		})
+	}
+	fake.findLatestAttemptBuildContainerReturnsOnCall[i] = struct {
+		result1 dbng.CreatedContainer
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTeam) FindInterceptTarget(buildID int, stepName string, attempt []int) (string, string, bool, error) {
+	var attemptCopy []int
+	if attempt != nil {
+		attemptCopy = make([]int, len(attempt))
+		copy(attemptCopy, attempt)
+	}
+	fake.findInterceptTargetMutex.Lock()
+	ret, specificReturn := fake.findInterceptTargetReturnsOnCall[len(fake.findInterceptTargetArgsForCall)]
+	fake.findInterceptTargetArgsForCall = append(fake.findInterceptTargetArgsForCall, struct {
+		buildID  int
+		stepName string
+		attempt  []int
+	}{buildID, stepName, attemptCopy})
+	fake.recordInvocation("FindInterceptTarget", []interface{}{buildID, stepName, attemptCopy})
+	fake.findInterceptTargetMutex.Unlock()
+	if fake.FindInterceptTargetStub != nil {
+		return fake.FindInterceptTargetStub(buildID, stepName, attempt)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	return fake.findInterceptTargetReturns.result1, fake.findInterceptTargetReturns.result2, fake.findInterceptTargetReturns.result3, fake.findInterceptTargetReturns.result4
+}
+
+func (fake *FakeTeam) FindInterceptTargetCallCount() int {
+	fake.findInterceptTargetMutex.RLock()
+	defer fake.findInterceptTargetMutex.RUnlock()
+	return len(fake.findInterceptTargetArgsForCall)
+}
+
+func (fake *FakeTeam) FindInterceptTargetArgsForCall(i int) (int, string, []int) {
+	fake.findInterceptTargetMutex.RLock()
+	defer fake.findInterceptTargetMutex.RUnlock()
+	return fake.findInterceptTargetArgsForCall[i].buildID, fake.findInterceptTargetArgsForCall[i].stepName, fake.findInterceptTargetArgsForCall[i].attempt
+}
+
+func (fake *FakeTeam) FindInterceptTargetReturns(result1 string, result2 string, result3 bool, result4 error) {
+	fake.FindInterceptTargetStub = nil
+	fake.findInterceptTargetReturns = struct {
+		result1 string
+		result2 string
+		result3 bool
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *FakeTeam) FindInterceptTargetReturnsOnCall(i int, result1 string, result2 string, result3 bool, result4 error) {
+	fake.FindInterceptTargetStub = nil
+	if fake.findInterceptTargetReturnsOnCall == nil {
+		fake.findInterceptTargetReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 string
+			result3 bool
+			result4 error
+		})
+	}
+	fake.findInterceptTargetReturnsOnCall[i] = struct {
+		result1 string
+		result2 string
+		result3 bool
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
 func (fake *FakeTeam) UpdateBasicAuth(basicAuth *atc.BasicAuth) error {
 	fake.updateBasicAuthMutex.Lock()
 	ret, specificReturn := fake.updateBasicAuthReturnsOnCall[len(fake.updateBasicAuthArgsForCall)]
@@ -1889,6 +2117,8 @@ func (fake *FakeTeam) Invocations() map[string][][]interface{} {
 	defer fake.savePipelineMutex.RUnlock()
 	fake.pipelineMutex.RLock()
 	defer fake.pipelineMutex.RUnlock()
+	fake.pipelineConfigMutex.RLock()
+	defer fake.pipelineConfigMutex.RUnlock()
 	fake.pipelinesMutex.RLock()
 	defer fake.pipelinesMutex.RUnlock()
 	fake.publicPipelinesMutex.RLock()
@@ -1929,6 +2159,10 @@ func (fake *FakeTeam) Invocations() map[string][][]interface{} {
 	defer fake.findBuildContainerOnWorkerMutex.RUnlock()
 	fake.createBuildContainerMutex.RLock()
 	defer fake.createBuildContainerMutex.RUnlock()
+	fake.findLatestAttemptBuildContainerMutex.RLock()
+	defer fake.findLatestAttemptBuildContainerMutex.RUnlock()
+	fake.findInterceptTargetMutex.RLock()
+	defer fake.findInterceptTargetMutex.RUnlock()
 	fake.updateBasicAuthMutex.RLock()
 	defer fake.updateBasicAuthMutex.RUnlock()
 	fake.updateProviderAuthMutex.RLock()
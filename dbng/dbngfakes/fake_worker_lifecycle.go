@@ -3,6 +3,7 @@ package dbngfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/concourse/atc/dbng"
 )
@@ -41,6 +42,19 @@ type FakeWorkerLifecycle struct {
 		result1 []string
 		result2 error
 	}
+	DeleteUnresponsiveStalledWorkersStub        func(gracePeriod time.Duration) ([]string, error)
+	deleteUnresponsiveStalledWorkersMutex       sync.RWMutex
+	deleteUnresponsiveStalledWorkersArgsForCall []struct {
+		gracePeriod time.Duration
+	}
+	deleteUnresponsiveStalledWorkersReturns struct {
+		result1 []string
+		result2 error
+	}
+	deleteUnresponsiveStalledWorkersReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -174,6 +188,57 @@ func (fake *FakeWorkerLifecycle) DeleteFinishedRetiringWorkersReturnsOnCall(i in
 	}{result1, result2}
 }
 
+func (fake *FakeWorkerLifecycle) DeleteUnresponsiveStalledWorkers(gracePeriod time.Duration) ([]string, error) {
+	fake.deleteUnresponsiveStalledWorkersMutex.Lock()
+	ret, specificReturn := fake.deleteUnresponsiveStalledWorkersReturnsOnCall[len(fake.deleteUnresponsiveStalledWorkersArgsForCall)]
+	fake.deleteUnresponsiveStalledWorkersArgsForCall = append(fake.deleteUnresponsiveStalledWorkersArgsForCall, struct {
+		gracePeriod time.Duration
+	}{gracePeriod})
+	fake.recordInvocation("DeleteUnresponsiveStalledWorkers", []interface{}{gracePeriod})
+	fake.deleteUnresponsiveStalledWorkersMutex.Unlock()
+	if fake.DeleteUnresponsiveStalledWorkersStub != nil {
+		return fake.DeleteUnresponsiveStalledWorkersStub(gracePeriod)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.deleteUnresponsiveStalledWorkersReturns.result1, fake.deleteUnresponsiveStalledWorkersReturns.result2
+}
+
+func (fake *FakeWorkerLifecycle) DeleteUnresponsiveStalledWorkersCallCount() int {
+	fake.deleteUnresponsiveStalledWorkersMutex.RLock()
+	defer fake.deleteUnresponsiveStalledWorkersMutex.RUnlock()
+	return len(fake.deleteUnresponsiveStalledWorkersArgsForCall)
+}
+
+func (fake *FakeWorkerLifecycle) DeleteUnresponsiveStalledWorkersArgsForCall(i int) time.Duration {
+	fake.deleteUnresponsiveStalledWorkersMutex.RLock()
+	defer fake.deleteUnresponsiveStalledWorkersMutex.RUnlock()
+	return fake.deleteUnresponsiveStalledWorkersArgsForCall[i].gracePeriod
+}
+
+func (fake *FakeWorkerLifecycle) DeleteUnresponsiveStalledWorkersReturns(result1 []string, result2 error) {
+	fake.DeleteUnresponsiveStalledWorkersStub = nil
+	fake.deleteUnresponsiveStalledWorkersReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeWorkerLifecycle) DeleteUnresponsiveStalledWorkersReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.DeleteUnresponsiveStalledWorkersStub = nil
+	if fake.deleteUnresponsiveStalledWorkersReturnsOnCall == nil {
+		fake.deleteUnresponsiveStalledWorkersReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.deleteUnresponsiveStalledWorkersReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeWorkerLifecycle) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -183,6 +248,8 @@ func (fake *FakeWorkerLifecycle) Invocations() map[string][][]interface{} {
 	defer fake.landFinishedLandingWorkersMutex.RUnlock()
 	fake.deleteFinishedRetiringWorkersMutex.RLock()
 	defer fake.deleteFinishedRetiringWorkersMutex.RUnlock()
+	fake.deleteUnresponsiveStalledWorkersMutex.RLock()
+	defer fake.deleteUnresponsiveStalledWorkersMutex.RUnlock()
 	return fake.invocations
 }
 
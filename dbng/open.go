@@ -1,10 +1,11 @@
 package dbng
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
-	"strings"
+	"net"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -20,61 +21,187 @@ type Conn interface {
 	Bus() NotificationsBus
 	Close() error
 
+	// ReadConn returns a connection to the read replica, if one is configured,
+	// or else this connection. It is owned by this Conn and must not be closed
+	// separately.
+	ReadConn() Conn
+
 	Begin() (Tx, error)
 	Driver() driver.Driver
 	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	Ping() error
 	Prepare(query string) (*sql.Stmt, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) squirrel.RowScanner
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner
 	SetMaxIdleConns(n int)
 	SetMaxOpenConns(n int)
 	Stats() sql.DBStats
+
+	// pendingEventWrites counts the build events saved through this Conn that
+	// haven't been written yet.
+	pendingEventWrites() *int64
 }
 
 type Tx interface {
 	Commit() error
 	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	Prepare(query string) (*sql.Stmt, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) squirrel.RowScanner
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner
 	Rollback() error
 	Stmt(stmt *sql.Stmt) *sql.Stmt
 }
 
-func Open(logger lager.Logger, sqlDriver string, sqlDataSource string) (Conn, error) {
-	for {
+// RetryPolicy controls how Open retries when the database is not reachable
+// yet. Backoff starts at InitialBackoff and doubles after every attempt up to
+// MaxBackoff. A MaxAttempts of 0 retries forever.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    0,
+	InitialBackoff: time.Second,
+	MaxBackoff:     5 * time.Second,
+}
+
+// Open migrates and connects to the database at sqlDataSource. If
+// replicaDataSource is not empty, read-only queries made through ReadConn are
+// sent to that replica instead.
+func Open(logger lager.Logger, sqlDriver string, sqlDataSource string, replicaDataSource string, retryPolicy RetryPolicy) (Conn, error) {
+	backoff := retryPolicy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
 		sqlDb, err := migration.Open(sqlDriver, sqlDataSource, migrations.Migrations)
 		if err != nil {
-			if strings.Contains(err.Error(), " dial ") {
-				logger.Error("failed-to-open-db-retrying", err)
-				time.Sleep(5 * time.Second)
-				continue
+			if !isRecoverableOpenError(err) {
+				return nil, err
 			}
 
-			return nil, err
+			if retryPolicy.MaxAttempts > 0 && attempt >= retryPolicy.MaxAttempts {
+				logger.Error("failed-to-open-db-giving-up", err, lager.Data{"attempts": attempt})
+				return nil, err
+			}
+
+			logger.Error("failed-to-open-db-retrying", err, lager.Data{"attempt": attempt, "backoff": backoff.String()})
+			time.Sleep(backoff)
+
+			backoff *= 2
+			if backoff > retryPolicy.MaxBackoff {
+				backoff = retryPolicy.MaxBackoff
+			}
+
+			continue
+		}
+
+		var replicaDb *sql.DB
+		if replicaDataSource != "" {
+			replicaDb, err = sql.Open(sqlDriver, replicaDataSource)
+			if err != nil {
+				sqlDb.Close()
+				return nil, err
+			}
 		}
 
 		listener := pq.NewListener(sqlDataSource, time.Second, time.Minute, nil)
+		bus := NewNotificationsBus(listener, sqlDb)
 
-		return &db{
+		conn := &db{
 			DB: sqlDb,
 
-			bus: NewNotificationsBus(listener, sqlDb),
-		}, nil
+			bus: bus,
+
+			eventWrites: new(int64),
+		}
+
+		if replicaDb != nil {
+			conn.replica = &replicaDB{
+				db: &db{
+					DB: replicaDb,
+
+					bus: bus,
+
+					eventWrites: conn.eventWrites,
+				},
+			}
+		}
+
+		return conn, nil
+	}
+}
+
+// isRecoverableOpenError reports whether err is likely to go away on its own,
+// e.g. because Postgres is still starting up or not listening yet.
+func isRecoverableOpenError(err error) bool {
+	switch e := err.(type) {
+	case *pq.Error:
+		switch e.Code {
+		case "57P03", // cannot_connect_now, i.e. the database system is starting up
+			"53300": // too_many_connections
+			return true
+		}
+
+		// connection_exception
+		return e.Code.Class() == "08"
+	case net.Error:
+		return true
+	default:
+		return false
 	}
 }
 
 type db struct {
 	*sql.DB
 
+	replica *replicaDB
+
 	bus NotificationsBus
+
+	eventWrites *int64
 }
 
 func (db *db) Bus() NotificationsBus {
 	return db.bus
 }
 
+func (db *db) pendingEventWrites() *int64 {
+	return db.eventWrites
+}
+
+func (db *db) ReadConn() Conn {
+	if db.replica == nil {
+		return db
+	}
+
+	return db.replica
+}
+
+// SetMaxOpenConns caps the replica's pool along with the primary's.
+func (db *db) SetMaxOpenConns(n int) {
+	db.DB.SetMaxOpenConns(n)
+
+	if db.replica != nil {
+		db.replica.DB.SetMaxOpenConns(n)
+	}
+}
+
+// SetMaxIdleConns caps the replica's pool along with the primary's.
+func (db *db) SetMaxIdleConns(n int) {
+	db.DB.SetMaxIdleConns(n)
+
+	if db.replica != nil {
+		db.replica.DB.SetMaxIdleConns(n)
+	}
+}
+
 func (db *db) Close() error {
 	var errs error
 	dbErr := db.DB.Close()
@@ -82,6 +209,13 @@ func (db *db) Close() error {
 		errs = multierror.Append(errs, dbErr)
 	}
 
+	if db.replica != nil {
+		replicaErr := db.replica.DB.Close()
+		if replicaErr != nil {
+			errs = multierror.Append(errs, replicaErr)
+		}
+	}
+
 	busErr := db.bus.Close()
 	if busErr != nil {
 		errs = multierror.Append(errs, busErr)
@@ -104,6 +238,24 @@ func (db *db) QueryRow(query string, args ...interface{}) squirrel.RowScanner {
 	return db.DB.QueryRow(query, args...)
 }
 
+func (db *db) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// replicaDB shares the primary's notification bus and is closed along with
+// the primary, so closing it directly does nothing.
+type replicaDB struct {
+	*db
+}
+
+func (replica *replicaDB) ReadConn() Conn {
+	return replica
+}
+
+func (replica *replicaDB) Close() error {
+	return nil
+}
+
 type dbTx struct {
 	*sql.Tx
 }
@@ -113,10 +265,22 @@ func (tx *dbTx) QueryRow(query string, args ...interface{}) squirrel.RowScanner
 	return tx.Tx.QueryRow(query, args...)
 }
 
+func (tx *dbTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) squirrel.RowScanner {
+	return tx.Tx.QueryRowContext(ctx, query, args...)
+}
+
 type nonOneRowAffectedError struct {
 	RowsAffected int64
+
+	// Operation optionally names the statement that failed, e.g. "update job
+	// paused".
+	Operation string
 }
 
 func (err nonOneRowAffectedError) Error() string {
-	return fmt.Sprintf("expected 1 row to be updated; got %d", err.RowsAffected)
+	if err.Operation == "" {
+		return fmt.Sprintf("expected 1 row to be updated; got %d", err.RowsAffected)
+	}
+
+	return fmt.Sprintf("%s: expected 1 row to be updated; got %d", err.Operation, err.RowsAffected)
 }
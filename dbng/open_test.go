@@ -0,0 +1,240 @@
+package dbng_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc/dbng"
+	"github.com/lib/pq"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// flakyDriver fails to open connections with the configured errors, in order,
+// before handing off to the real postgres driver.
+type flakyDriver struct {
+	lock     sync.Mutex
+	errs     []error
+	attempts int
+}
+
+func (d *flakyDriver) Open(dsn string) (driver.Conn, error) {
+	d.lock.Lock()
+	d.attempts++
+	if len(d.errs) > 0 {
+		err := d.errs[0]
+		d.errs = d.errs[1:]
+		d.lock.Unlock()
+		return nil, err
+	}
+	d.lock.Unlock()
+
+	return pq.Driver{}.Open(dsn)
+}
+
+func (d *flakyDriver) failWith(errs ...error) {
+	d.lock.Lock()
+	d.errs = errs
+	d.attempts = 0
+	d.lock.Unlock()
+}
+
+func (d *flakyDriver) Attempts() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.attempts
+}
+
+var fakeDriver = &flakyDriver{}
+
+func init() {
+	sql.Register("flaky-postgres", fakeDriver)
+}
+
+var _ = Describe("Open", func() {
+	var (
+		retryPolicy dbng.RetryPolicy
+
+		conn    dbng.Conn
+		openErr error
+	)
+
+	startingUp := &pq.Error{Code: "57P03", Message: "the database system is starting up"}
+
+	BeforeEach(func() {
+		retryPolicy = dbng.RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+		}
+	})
+
+	JustBeforeEach(func() {
+		conn, openErr = dbng.Open(lagertest.NewTestLogger("test"), "flaky-postgres", postgresRunner.DataSourceName(), "", retryPolicy)
+	})
+
+	AfterEach(func() {
+		if conn != nil {
+			Expect(conn.Close()).To(Succeed())
+		}
+	})
+
+	Context("when the database is starting up", func() {
+		BeforeEach(func() {
+			fakeDriver.failWith(startingUp, startingUp)
+		})
+
+		It("retries until it can connect", func() {
+			Expect(openErr).NotTo(HaveOccurred())
+			Expect(conn.Ping()).To(Succeed())
+			Expect(fakeDriver.Attempts()).To(BeNumerically(">=", 3))
+		})
+
+		Context("for longer than the policy allows", func() {
+			BeforeEach(func() {
+				retryPolicy.MaxAttempts = 2
+			})
+
+			It("gives up and returns the error", func() {
+				Expect(openErr).To(Equal(startingUp))
+				Expect(fakeDriver.Attempts()).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when the connection is refused", func() {
+		BeforeEach(func() {
+			fakeDriver.failWith(&pq.Error{Code: "08006", Message: "connection failure"})
+		})
+
+		It("retries", func() {
+			Expect(openErr).NotTo(HaveOccurred())
+			Expect(fakeDriver.Attempts()).To(BeNumerically(">=", 2))
+		})
+	})
+
+	Context("when the error is not recoverable", func() {
+		invalidPassword := &pq.Error{Code: "28P01", Message: "password authentication failed"}
+
+		BeforeEach(func() {
+			fakeDriver.failWith(invalidPassword)
+		})
+
+		It("returns the error immediately", func() {
+			Expect(openErr).To(Equal(invalidPassword))
+			Expect(fakeDriver.Attempts()).To(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("Conn", func() {
+	Describe("QueryRowContext", func() {
+		It("runs the query", func() {
+			var one int
+			err := dbConn.QueryRowContext(context.Background(), "SELECT 1").Scan(&one)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(one).To(Equal(1))
+		})
+
+		It("aborts a slow query when the context is done", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			started := time.Now()
+			err := dbConn.QueryRowContext(ctx, "SELECT pg_sleep(10)").Scan(new(string))
+			Expect(err).To(HaveOccurred())
+			Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+
+	Describe("ExecContext in a transaction", func() {
+		It("aborts a slow statement when the context is cancelled", func() {
+			tx, err := dbConn.Begin()
+			Expect(err).NotTo(HaveOccurred())
+			defer tx.Rollback()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			started := time.Now()
+			_, err = tx.ExecContext(ctx, "SELECT pg_sleep(10)")
+			Expect(err).To(HaveOccurred())
+			Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+})
+
+var _ = Describe("ReadConn", func() {
+	var (
+		replicaDataSource string
+		conn              dbng.Conn
+	)
+
+	currentDatabase := func(conn dbng.Conn) string {
+		var name string
+		err := conn.QueryRow("SELECT current_database()").Scan(&name)
+		Expect(err).NotTo(HaveOccurred())
+		return name
+	}
+
+	JustBeforeEach(func() {
+		var err error
+		conn, err = dbng.Open(lagertest.NewTestLogger("test"), "postgres", postgresRunner.DataSourceName(), replicaDataSource, dbng.DefaultRetryPolicy)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(conn.Close()).To(Succeed())
+	})
+
+	Context("when no replica is configured", func() {
+		BeforeEach(func() {
+			replicaDataSource = ""
+		})
+
+		It("falls back to the primary", func() {
+			Expect(conn.ReadConn()).To(BeIdenticalTo(conn))
+			Expect(currentDatabase(conn.ReadConn())).To(Equal("testdb"))
+		})
+	})
+
+	Context("when a replica is configured", func() {
+		BeforeEach(func() {
+			replicaDataSource = fmt.Sprintf("user=postgres dbname=postgres sslmode=disable port=%d", 5433+GinkgoParallelNode())
+		})
+
+		It("sends reads to the replica and everything else to the primary", func() {
+			Expect(currentDatabase(conn.ReadConn())).To(Equal("postgres"))
+			Expect(currentDatabase(conn)).To(Equal("testdb"))
+		})
+
+		It("leaves the replica open until the primary is closed", func() {
+			Expect(conn.ReadConn().Close()).To(Succeed())
+			Expect(conn.ReadConn().Ping()).To(Succeed())
+		})
+
+		It("caps the replica's pool along with the primary's", func() {
+			conn.SetMaxOpenConns(1)
+
+			tx, err := conn.ReadConn().Begin()
+			Expect(err).NotTo(HaveOccurred())
+			defer tx.Rollback()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			err = conn.ReadConn().QueryRowContext(ctx, "SELECT 1").Scan(new(int))
+			Expect(err).To(Equal(context.DeadlineExceeded))
+		})
+
+		It("shares the primary's notification bus", func() {
+			Expect(conn.ReadConn().Bus()).To(BeIdenticalTo(conn.Bus()))
+		})
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	"code.cloudfoundry.org/lager"
@@ -25,6 +26,8 @@ type Pipeline interface {
 	Config() atc.Config
 	Public() bool
 	Paused() bool
+	Archived() bool
+	LastUpdated() time.Time
 
 	CheckPaused() (bool, error)
 	Reload() (bool, error)
@@ -37,11 +40,14 @@ type Pipeline interface {
 	GetAllPendingBuilds() (map[string][]Build, error)
 
 	SaveResourceVersions(atc.ResourceConfig, []atc.Version) error
+	SaveResourceVersionsWithMetadata(config atc.ResourceConfig, versions []atc.VersionWithMetadata) error
 	GetResourceVersions(resourceName string, page Page) ([]SavedVersionedResource, Pagination, bool, error)
 	GetLatestVersionedResource(resourceName string) (SavedVersionedResource, bool, error)
 	GetVersionedResourceByVersion(atcVersion atc.Version, resourceName string) (SavedVersionedResource, bool, error)
+	GetTriggeringVersion(buildID int) (*SavedVersionedResource, bool, error)
 	DisableVersionedResource(versionedResourceID int) error
 	EnableVersionedResource(versionedResourceID int) error
+	DisableVersionsWhereMetadata(resourceID int, key string, value string) (int, error)
 
 	SaveIndependentInputMapping(inputMapping algorithm.InputMapping, jobName string) error
 	SaveNextInputMapping(inputMapping algorithm.InputMapping, jobName string) error
@@ -50,9 +56,12 @@ type Pipeline interface {
 	GetIndependentBuildInputs(jobName string) ([]BuildInput, error)
 	GetNextBuildInputs(jobName string) ([]BuildInput, bool, error)
 	DeleteNextInputMapping(jobName string) error
-	EnsurePendingBuildExists(jobName string) error
+	EnsurePendingBuildExists(jobName string, triggeredByVersionID int, reason BuildTriggerReason) error
 	GetPendingBuildsForJob(jobName string) ([]Build, error)
+	GetPendingBuildsTriggeredByVersion(versionedResourceID int) ([]Build, error)
 	CreateJobBuild(jobName string) (Build, error)
+	CreateJobBuildWithOverrides(jobName string, overrides atc.BuildOverrides) (Build, error)
+	CreateRerunBuild(jobName string, sourceBuildID int, metadata map[string]json.RawMessage) (Build, error)
 	NextBuildInputs(jobName string) ([]BuildInput, bool, error)
 	PauseJob(job string) error
 	UnpauseJob(job string) error
@@ -84,9 +93,14 @@ type Pipeline interface {
 	Expose() error
 	Hide() error
 
+	Archive() error
+	Unarchive() error
+
 	Pause() error
 	Unpause() error
 
+	AbortAllBuilds() ([]int, error)
+
 	Destroy() error
 	Rename(string) error
 }
@@ -100,6 +114,8 @@ type pipeline struct {
 	config        atc.Config
 	paused        bool
 	public        bool
+	archived      bool
+	lastUpdated   time.Time
 
 	cachedAt   time.Time
 	versionsDB *algorithm.VersionsDB
@@ -121,7 +137,9 @@ var pipelinesQuery = psql.Select(`
 		t.name,
 		p.config,
 		p.paused,
-		p.public
+		p.public,
+		p.archived,
+		p.last_updated
 	`).
 	From("pipelines p").
 	LeftJoin("teams t ON p.team_id = t.id")
@@ -163,6 +181,8 @@ func (p *pipeline) ConfigVersion() ConfigVersion { return p.configVersion }
 func (p *pipeline) Config() atc.Config           { return p.config }
 func (p *pipeline) Public() bool                 { return p.public }
 func (p *pipeline) Paused() bool                 { return p.paused }
+func (p *pipeline) Archived() bool               { return p.archived }
+func (p *pipeline) LastUpdated() time.Time       { return p.lastUpdated }
 
 // Write test
 func (p *pipeline) CheckPaused() (bool, error) {
@@ -198,6 +218,12 @@ func (p *pipeline) Reload() (bool, error) {
 }
 
 func (p *pipeline) CreateJobBuild(jobName string) (Build, error) {
+	return p.CreateJobBuildWithOverrides(jobName, atc.BuildOverrides{})
+}
+
+// CreateJobBuildWithOverrides creates a manually triggered build that keeps
+// the overrides, so that they are applied whenever the build is scheduled.
+func (p *pipeline) CreateJobBuildWithOverrides(jobName string, overrides atc.BuildOverrides) (Build, error) {
 	tx, err := p.conn.Begin()
 	if err != nil {
 		return nil, err
@@ -205,25 +231,102 @@ func (p *pipeline) CreateJobBuild(jobName string) (Build, error) {
 
 	defer tx.Rollback()
 
-	buildName, jobID, err := getNewBuildNameForJob(tx, jobName, p.id)
+	buildID, err := p.createManualJobBuild(tx, jobName, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.commitCreatedBuild(tx, buildID)
+}
+
+// CreateRerunBuild creates a manually triggered build that runs with the
+// same inputs as the source build, even if their versions have since been
+// disabled. The metadata is saved with the build.
+func (p *pipeline) CreateRerunBuild(jobName string, sourceBuildID int, metadata map[string]json.RawMessage) (Build, error) {
+	tx, err := p.conn.Begin()
 	if err != nil {
 		return nil, err
 	}
 
+	defer tx.Rollback()
+
+	buildID, err := p.createManualJobBuild(tx, jobName, atc.BuildOverrides{})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO build_inputs (build_id, versioned_resource_id, name)
+		SELECT $1, versioned_resource_id, name
+		FROM build_inputs
+		WHERE build_id = $2
+	`, buildID, sourceBuildID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = psql.Update("builds").
+		Set("inputs_pinned", true).
+		Where(sq.Eq{"id": buildID}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range metadata {
+		_, err = psql.Insert("build_metadata").
+			Columns("build_id", "name", "value").
+			Values(buildID, name, string(value)).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.commitCreatedBuild(tx, buildID)
+}
+
+func (p *pipeline) createManualJobBuild(tx Tx, jobName string, overrides atc.BuildOverrides) (int, error) {
+	var overridesJSON sql.NullString
+	if !overrides.IsEmpty() {
+		payload, err := json.Marshal(overrides)
+		if err != nil {
+			return 0, err
+		}
+
+		overridesJSON = sql.NullString{String: string(payload), Valid: true}
+	}
+
+	buildName, jobID, err := getNewBuildNameForJob(tx, jobName, p.id)
+	if err != nil {
+		return 0, err
+	}
+
 	var buildID int
 	err = psql.Insert("builds").
-		Columns("name", "job_id", "team_id", "status", "manually_triggered").
-		Values(buildName, jobID, p.teamID, "pending", true).
+		Columns("name", "job_id", "team_id", "status", "manually_triggered", "trigger_reason", "overrides").
+		Values(buildName, jobID, p.teamID, "pending", true, BuildTriggerReasonManual, overridesJSON).
 		Suffix("RETURNING id").
 		RunWith(tx).
 		QueryRow().
 		Scan(&buildID)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+
+	err = createBuildEventSeq(tx, buildID)
+	if err != nil {
+		return 0, err
 	}
 
+	return buildID, nil
+}
+
+func (p *pipeline) commitCreatedBuild(tx Tx, buildID int) (Build, error) {
 	build := &build{conn: p.conn, lockFactory: p.lockFactory}
-	err = scanBuild(build, buildsQuery.
+	err := scanBuild(build, buildsQuery.
 		Where(sq.Eq{"b.id": buildID}).
 		RunWith(tx).
 		QueryRow(),
@@ -232,11 +335,6 @@ func (p *pipeline) CreateJobBuild(jobName string) (Build, error) {
 		return nil, err
 	}
 
-	err = createBuildEventSeq(tx, buildID)
-	if err != nil {
-		return nil, err
-	}
-
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
@@ -332,6 +430,36 @@ func (p *pipeline) GetPendingBuildsForJob(jobName string) ([]Build, error) {
 	return builds, nil
 }
 
+func (p *pipeline) GetPendingBuildsTriggeredByVersion(versionedResourceID int) ([]Build, error) {
+	rows, err := buildsQuery.
+		Where(sq.Eq{
+			"b.status":                  BuildStatusPending,
+			"b.triggered_by_version_id": versionedResourceID,
+			"p.id":                      p.id,
+		}).
+		OrderBy("b.id ASC").
+		RunWith(p.conn).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	builds := []Build{}
+	for rows.Next() {
+		build := &build{conn: p.conn, lockFactory: p.lockFactory}
+		err = scanBuild(build, rows)
+		if err != nil {
+			return nil, err
+		}
+
+		builds = append(builds, build)
+	}
+
+	return builds, nil
+}
+
 func (p *pipeline) GetAllPendingBuilds() (map[string][]Build, error) {
 	builds := map[string][]Build{}
 
@@ -363,7 +491,7 @@ func (p *pipeline) GetAllPendingBuilds() (map[string][]Build, error) {
 	return builds, nil
 }
 
-func (p *pipeline) EnsurePendingBuildExists(jobName string) error {
+func (p *pipeline) EnsurePendingBuildExists(jobName string, triggeredByVersionID int, reason BuildTriggerReason) error {
 	tx, err := p.conn.Begin()
 	if err != nil {
 		return err
@@ -377,12 +505,12 @@ func (p *pipeline) EnsurePendingBuildExists(jobName string) error {
 	}
 
 	rows, err := tx.Query(`
-		INSERT INTO builds (name, job_id, team_id, status)
-		SELECT $1, $2, $3, 'pending'
+		INSERT INTO builds (name, job_id, team_id, status, triggered_by_version_id, trigger_reason)
+		SELECT $1, $2, $3, 'pending', $4, $5
 		WHERE NOT EXISTS
 			(SELECT id FROM builds WHERE job_id = $2 AND status = 'pending')
 		RETURNING id
-	`, buildName, jobID, p.teamID)
+	`, buildName, jobID, p.teamID, triggeredByVersionID, string(reason))
 	if err != nil {
 		return err
 	}
@@ -410,6 +538,23 @@ func (p *pipeline) EnsurePendingBuildExists(jobName string) error {
 }
 
 func (p *pipeline) SaveResourceVersions(config atc.ResourceConfig, versions []atc.Version) error {
+	versionsWithMetadata := make([]atc.VersionWithMetadata, len(versions))
+	for i, version := range versions {
+		versionsWithMetadata[i] = atc.VersionWithMetadata{Version: version}
+	}
+
+	return p.saveResourceVersions(config, versionsWithMetadata)
+}
+
+// SaveResourceVersionsWithMetadata saves the versions along with their
+// metadata in a single transaction, so that the metadata is present as soon
+// as the versions are. The metadata of versions that were already saved is
+// replaced, unless it is empty.
+func (p *pipeline) SaveResourceVersionsWithMetadata(config atc.ResourceConfig, versions []atc.VersionWithMetadata) error {
+	return p.saveResourceVersions(config, versions)
+}
+
+func (p *pipeline) saveResourceVersions(config atc.ResourceConfig, versions []atc.VersionWithMetadata) error {
 	tx, err := p.conn.Begin()
 	if err != nil {
 		return err
@@ -421,7 +566,8 @@ func (p *pipeline) SaveResourceVersions(config atc.ResourceConfig, versions []at
 		vr := VersionedResource{
 			Resource: config.Name,
 			Type:     config.Type,
-			Version:  ResourceVersion(version),
+			Version:  ResourceVersion(version.Version),
+			Metadata: resourceMetadataFields(version.Metadata),
 		}
 
 		versionJSON, err := json.Marshal(vr.Version)
@@ -459,14 +605,33 @@ func (p *pipeline) SaveResourceVersions(config atc.ResourceConfig, versions []at
 	return nil
 }
 
+func resourceMetadataFields(fields []atc.MetadataField) []ResourceMetadataField {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	metadata := make([]ResourceMetadataField, len(fields))
+	for i, field := range fields {
+		metadata[i] = ResourceMetadataField{
+			Name:  field.Name,
+			Value: field.Value,
+		}
+	}
+
+	return metadata
+}
+
 func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedVersionedResource, Pagination, bool, error) {
+	// version history is read-heavy, so read from the replica if there is one
+	readConn := p.conn.ReadConn()
+
 	var resourceID int
 	err := psql.Select("id").
 		From("resources").
 		Where(sq.Eq{
 			"name":        resourceName,
 			"pipeline_id": p.id,
-		}).RunWith(p.conn).QueryRow().Scan(&resourceID)
+		}).RunWith(readConn).QueryRow().Scan(&resourceID)
 	if err != nil {
 		return []SavedVersionedResource{}, Pagination{}, false, err
 	}
@@ -480,7 +645,7 @@ func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedV
 
 	var rows *sql.Rows
 	if page.Until != 0 {
-		rows, err = p.conn.Query(fmt.Sprintf(`
+		rows, err = readConn.Query(fmt.Sprintf(`
 			SELECT sub.*
 				FROM (
 						%s
@@ -494,7 +659,7 @@ func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedV
 			return nil, Pagination{}, false, err
 		}
 	} else if page.Since != 0 {
-		rows, err = p.conn.Query(fmt.Sprintf(`
+		rows, err = readConn.Query(fmt.Sprintf(`
 			%s
 				AND v.check_order < (SELECT check_order FROM versioned_resources WHERE id = $2)
 			ORDER BY v.check_order DESC
@@ -504,7 +669,7 @@ func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedV
 			return nil, Pagination{}, false, err
 		}
 	} else if page.To != 0 {
-		rows, err = p.conn.Query(fmt.Sprintf(`
+		rows, err = readConn.Query(fmt.Sprintf(`
 			SELECT sub.*
 				FROM (
 						%s
@@ -518,7 +683,7 @@ func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedV
 			return nil, Pagination{}, false, err
 		}
 	} else if page.From != 0 {
-		rows, err = p.conn.Query(fmt.Sprintf(`
+		rows, err = readConn.Query(fmt.Sprintf(`
 			%s
 				AND v.check_order <= (SELECT check_order FROM versioned_resources WHERE id = $2)
 			ORDER BY v.check_order DESC
@@ -528,7 +693,7 @@ func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedV
 			return nil, Pagination{}, false, err
 		}
 	} else {
-		rows, err = p.conn.Query(fmt.Sprintf(`
+		rows, err = readConn.Query(fmt.Sprintf(`
 			%s
 			ORDER BY v.check_order DESC
 			LIMIT $2
@@ -579,7 +744,7 @@ func (p *pipeline) GetResourceVersions(resourceName string, page Page) ([]SavedV
 	var minCheckOrder int
 	var maxCheckOrder int
 
-	err = p.conn.QueryRow(`
+	err = readConn.QueryRow(`
 		SELECT COALESCE(MAX(v.check_order), 0) as maxCheckOrder,
 			COALESCE(MIN(v.check_order), 0) as minCheckOrder
 		FROM versioned_resources v
@@ -700,6 +865,43 @@ func (p *pipeline) GetVersionedResourceByVersion(atcVersion atc.Version, resourc
 	return svr, true, nil
 }
 
+func (p *pipeline) GetTriggeringVersion(buildID int) (*SavedVersionedResource, bool, error) {
+	var versionBytes, metadataBytes string
+
+	svr := &SavedVersionedResource{}
+
+	err := psql.Select("v.id", "v.enabled", "v.type", "v.version", "v.metadata", "v.modified_time", "v.check_order", "r.name").
+		From("builds b").
+		Join("versioned_resources v ON v.id = b.triggered_by_version_id").
+		Join("resources r ON r.id = v.resource_id").
+		Where(sq.Eq{
+			"b.id":          buildID,
+			"r.pipeline_id": p.id,
+		}).
+		RunWith(p.conn).
+		QueryRow().
+		Scan(&svr.ID, &svr.Enabled, &svr.Type, &versionBytes, &metadataBytes, &svr.ModifiedTime, &svr.CheckOrder, &svr.Resource)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	err = json.Unmarshal([]byte(versionBytes), &svr.Version)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = json.Unmarshal([]byte(metadataBytes), &svr.Metadata)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return svr, true, nil
+}
+
 func (p *pipeline) DisableVersionedResource(versionedResourceID int) error {
 	return p.toggleVersionedResource(versionedResourceID, false)
 }
@@ -708,6 +910,35 @@ func (p *pipeline) EnableVersionedResource(versionedResourceID int) error {
 	return p.toggleVersionedResource(versionedResourceID, true)
 }
 
+func (p *pipeline) DisableVersionsWhereMetadata(resourceID int, key string, value string) (int, error) {
+	metadataJSON, err := json.Marshal([]ResourceMetadataField{{Name: key, Value: value}})
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := psql.Update("versioned_resources").
+		Set("enabled", false).
+		Set("modified_time", sq.Expr("now()")).
+		Where(sq.Eq{
+			"resource_id": resourceID,
+			"enabled":     true,
+		}).
+		Where(sq.Expr("resource_id IN (SELECT id FROM resources WHERE pipeline_id = ?)", p.id)).
+		Where(sq.Expr("metadata::jsonb @> ?::jsonb", string(metadataJSON))).
+		RunWith(p.conn).
+		Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
 func (p *pipeline) SaveIndependentInputMapping(inputMapping algorithm.InputMapping, jobName string) error {
 	return p.saveJobInputMapping("independent_build_inputs", inputMapping, jobName)
 }
@@ -881,7 +1112,10 @@ func (p *pipeline) SetMaxInFlightReached(jobName string, reached bool) error {
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update job max in flight reached",
+		}
 	}
 
 	return nil
@@ -973,6 +1207,127 @@ func (p *pipeline) Unpause() error {
 	return err
 }
 
+// AbortAllBuilds aborts every pending and started build of the pipeline's jobs
+// in a single transaction, so that no build can be scheduled in between, and
+// returns the IDs of the builds it aborted. The IDs are returned along with
+// the error if the builds were aborted but could not all be notified.
+//
+// No engine will ever pick up the pending builds, so they are completed here
+// with a status event, as with Build.AbortPending. The started builds are
+// notified so that their engine aborts them.
+func (p *pipeline) AbortAllBuilds() ([]int, error) {
+	tx, err := p.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	inPipeline := sq.Expr("job_id IN (SELECT id FROM jobs WHERE pipeline_id = ?)", p.id)
+
+	rows, err := psql.Update("builds").
+		Set("status", BuildStatusAborted).
+		Set("end_time", sq.Expr("now()")).
+		Set("completed", true).
+		Where(inPipeline).
+		Where(sq.Eq{"status": BuildStatusPending}).
+		Suffix("RETURNING id, end_time").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	endTimes := map[int]time.Time{}
+	for rows.Next() {
+		var buildID int
+		var endTime time.Time
+		err = rows.Scan(&buildID, &endTime)
+		if err != nil {
+			return nil, err
+		}
+
+		endTimes[buildID] = endTime
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	pendingIDs := []int{}
+	for buildID, endTime := range endTimes {
+		err = finishBuild(tx, p.teamID, p.id, buildID, BuildStatusAborted, endTime)
+		if err != nil {
+			return nil, err
+		}
+
+		pendingIDs = append(pendingIDs, buildID)
+	}
+
+	startedRows, err := psql.Update("builds").
+		Set("status", BuildStatusAborted).
+		Where(inPipeline).
+		Where(sq.Eq{"status": BuildStatusStarted}).
+		Suffix("RETURNING id").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer startedRows.Close()
+
+	startedIDs := []int{}
+	for startedRows.Next() {
+		var buildID int
+		err = startedRows.Scan(&buildID)
+		if err != nil {
+			return nil, err
+		}
+
+		startedIDs = append(startedIDs, buildID)
+	}
+
+	err = startedRows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	// the builds are aborted once committed, so their IDs are returned even
+	// if notifying fails
+	buildIDs := append(append([]int{}, pendingIDs...), startedIDs...)
+	sort.Ints(buildIDs)
+
+	for _, buildID := range pendingIDs {
+		err = p.conn.Bus().Notify(buildEventsChannel(buildID))
+		if err != nil {
+			return buildIDs, err
+		}
+	}
+
+	for _, buildID := range startedIDs {
+		err = p.conn.Bus().Notify(buildAbortChannel(buildID))
+		if err != nil {
+			return buildIDs, err
+		}
+	}
+
+	return buildIDs, nil
+}
+
 func (p *pipeline) Hide() error {
 	_, err := psql.Update("pipelines").
 		Set("public", false).
@@ -997,6 +1352,32 @@ func (p *pipeline) Expose() error {
 	return err
 }
 
+// Archive hides the pipeline without deleting it. Archived pipelines are not
+// scheduled.
+func (p *pipeline) Archive() error {
+	_, err := psql.Update("pipelines").
+		Set("archived", true).
+		Where(sq.Eq{
+			"id": p.id,
+		}).
+		RunWith(p.conn).
+		Exec()
+
+	return err
+}
+
+func (p *pipeline) Unarchive() error {
+	_, err := psql.Update("pipelines").
+		Set("archived", false).
+		Where(sq.Eq{
+			"id": p.id,
+		}).
+		RunWith(p.conn).
+		Exec()
+
+	return err
+}
+
 func (p *pipeline) Rename(name string) error {
 	_, err := psql.Update("pipelines").
 		Set("name", name).
@@ -1562,7 +1943,10 @@ func (p *pipeline) toggleVersionedResource(versionedResourceID int, enable bool)
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update versioned resource enabled",
+		}
 	}
 
 	return nil
@@ -1604,7 +1988,10 @@ func (p *pipeline) updatePausedJob(jobName string, pause bool) error {
 	}
 
 	if rowsAffected != 1 {
-		return nonOneRowAffectedError{rowsAffected}
+		return nonOneRowAffectedError{
+			RowsAffected: rowsAffected,
+			Operation:    "update job paused",
+		}
 	}
 
 	return tx.Commit()
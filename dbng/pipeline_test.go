@@ -1,9 +1,11 @@
 package dbng_test
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/dbng"
@@ -46,6 +48,33 @@ var _ = Describe("Pipeline", func() {
 		Expect(created).To(BeTrue())
 	})
 
+	Describe("LastUpdated", func() {
+		It("is set when the pipeline is created", func() {
+			Expect(pipeline.LastUpdated()).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("is bumped when the config is saved again", func() {
+			_, err := psql.Update("pipelines").
+				Set("last_updated", time.Now().Add(-time.Hour)).
+				Where(sq.Eq{"id": pipeline.ID()}).
+				RunWith(dbConn).
+				Exec()
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := pipeline.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			stale := pipeline.LastUpdated()
+			Expect(stale).To(BeTemporally("~", time.Now().Add(-time.Hour), time.Minute))
+
+			savedPipeline, _, err := team.SavePipeline("fake-pipeline", pipeline.Config(), pipeline.ConfigVersion(), dbng.PipelineNoChange)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(savedPipeline.LastUpdated()).To(BeTemporally(">", stale))
+			Expect(savedPipeline.LastUpdated()).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+	})
+
 	Describe("CheckPaused", func() {
 		var paused bool
 		JustBeforeEach(func() {
@@ -115,6 +144,137 @@ var _ = Describe("Pipeline", func() {
 		})
 	})
 
+	Describe("Archive and Unarchive", func() {
+		reload := func() {
+			found, err := pipeline.Reload()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+		}
+
+		It("is not archived by default", func() {
+			Expect(pipeline.Archived()).To(BeFalse())
+		})
+
+		It("archives and unarchives the pipeline", func() {
+			Expect(pipeline.Archive()).To(Succeed())
+			reload()
+			Expect(pipeline.Archived()).To(BeTrue())
+
+			Expect(pipeline.Unarchive()).To(Succeed())
+			reload()
+			Expect(pipeline.Archived()).To(BeFalse())
+		})
+
+		It("keeps the pipeline in the team's listing", func() {
+			Expect(pipeline.Archive()).To(Succeed())
+
+			pipelines, err := team.Pipelines()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pipelines).To(HaveLen(1))
+			Expect(pipelines[0].Archived()).To(BeTrue())
+		})
+	})
+
+	Describe("AbortAllBuilds", func() {
+		var (
+			pendingBuild       dbng.Build
+			startedBuild       dbng.Build
+			finishedBuild      dbng.Build
+			otherPipelineBuild dbng.Build
+		)
+
+		BeforeEach(func() {
+			var err error
+			finishedBuild, err = pipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(finishedBuild.Finish(dbng.BuildStatusSucceeded)).To(Succeed())
+
+			startedBuild, err = pipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+			started, err := startedBuild.Start("some-engine", "some-metadata")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			pendingBuild, err = pipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			otherPipeline, _, err := team.SavePipeline("other-pipeline", atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "job-name"},
+				},
+			}, dbng.ConfigVersion(0), dbng.PipelineUnpaused)
+			Expect(err).NotTo(HaveOccurred())
+
+			otherPipelineBuild, err = otherPipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("aborts only the pending and started builds of the pipeline", func() {
+			abortedIDs, err := pipeline.AbortAllBuilds()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(abortedIDs).To(Equal([]int{startedBuild.ID(), pendingBuild.ID()}))
+
+			for _, build := range []dbng.Build{startedBuild, pendingBuild} {
+				found, err := build.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(build.Status()).To(Equal(dbng.BuildStatusAborted))
+			}
+
+			found, err := finishedBuild.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(finishedBuild.Status()).To(Equal(dbng.BuildStatusSucceeded))
+
+			found, err = otherPipelineBuild.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(otherPipelineBuild.Status()).To(Equal(dbng.BuildStatusPending))
+		})
+
+		It("completes the pending builds, ending their event streams", func() {
+			_, err := pipeline.AbortAllBuilds()
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := pendingBuild.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(pendingBuild.IsRunning()).To(BeFalse())
+			Expect(pendingBuild.EndTime()).NotTo(BeZero())
+
+			events, err := pendingBuild.Events(0)
+			Expect(err).NotTo(HaveOccurred())
+			defer events.Close()
+
+			ev, err := events.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ev.Event).To(Equal(atc.EventType("status")))
+
+			_, err = events.Next()
+			Expect(err).To(Equal(dbng.ErrEndOfBuildEventStream))
+		})
+
+		It("notifies the aborted builds", func() {
+			notifier, err := startedBuild.AbortNotifier()
+			Expect(err).NotTo(HaveOccurred())
+			defer notifier.Close()
+
+			_, err = pipeline.AbortAllBuilds()
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(notifier.Notify()).Should(Receive())
+		})
+
+		It("aborts nothing when run again", func() {
+			_, err := pipeline.AbortAllBuilds()
+			Expect(err).NotTo(HaveOccurred())
+
+			abortedIDs, err := pipeline.AbortAllBuilds()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(abortedIDs).To(BeEmpty())
+		})
+	})
+
 	Describe("Rename", func() {
 		JustBeforeEach(func() {
 			Expect(pipeline.Rename("oopsies")).To(Succeed())
@@ -196,6 +356,85 @@ var _ = Describe("Pipeline", func() {
 		})
 	})
 
+	Describe("SaveResourceVersionsWithMetadata", func() {
+		var resourceConfig atc.ResourceConfig
+
+		BeforeEach(func() {
+			var err error
+			pipeline, _, err = team.SavePipeline("some-pipeline", atc.Config{
+				Resources: atc.ResourceConfigs{
+					{
+						Name:   "some-resource",
+						Type:   "some-type",
+						Source: atc.Source{"source-config": "some-value"},
+					},
+				},
+			}, dbng.ConfigVersion(1), dbng.PipelineUnpaused)
+			Expect(err).ToNot(HaveOccurred())
+
+			resourceConfig = atc.ResourceConfig{
+				Name:   "some-resource",
+				Type:   "some-type",
+				Source: atc.Source{"source-config": "some-value"},
+			}
+
+			err = pipeline.SaveResourceVersionsWithMetadata(resourceConfig, []atc.VersionWithMetadata{
+				{
+					Version: atc.Version{"ref": "v1"},
+				},
+				{
+					Version: atc.Version{"ref": "v2"},
+					Metadata: []atc.MetadataField{
+						{Name: "author", Value: "some-author"},
+						{Name: "message", Value: "some-message"},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("saves the versions with their metadata", func() {
+			latestVR, found, err := pipeline.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(latestVR.Version).To(Equal(dbng.ResourceVersion{"ref": "v2"}))
+			Expect(latestVR.Metadata).To(Equal([]dbng.ResourceMetadataField{
+				{Name: "author", Value: "some-author"},
+				{Name: "message", Value: "some-message"},
+			}))
+
+			savedVR, found, err := pipeline.GetVersionedResourceByVersion(atc.Version{"ref": "v1"}, "some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(savedVR.Metadata).To(BeEmpty())
+		})
+
+		Context("when a version is saved again without metadata", func() {
+			BeforeEach(func() {
+				err := pipeline.SaveResourceVersions(resourceConfig, []atc.Version{{"ref": "v2"}})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("keeps the metadata it was saved with", func() {
+				latestVR, found, err := pipeline.GetLatestVersionedResource("some-resource")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(latestVR.Metadata).To(HaveLen(2))
+			})
+		})
+
+		Context("when the resource does not exist", func() {
+			It("returns an error", func() {
+				err := pipeline.SaveResourceVersionsWithMetadata(atc.ResourceConfig{
+					Name: "bogus-resource",
+					Type: "some-type",
+				}, []atc.VersionWithMetadata{{Version: atc.Version{"ref": "v3"}}})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
 	Describe("SaveResourceVersions", func() {
 		var (
 			originalVersionSlice []atc.Version
@@ -1577,6 +1816,11 @@ var _ = Describe("Pipeline", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("names the failed update in the error", func() {
+				err := dbngPipeline.DisableVersionedResource(42)
+				Expect(err).To(MatchError("update versioned resource enabled: expected 1 row to be updated; got 0"))
+			})
+
 			It("does not affect explicitly fetching the latest version", func() {
 				err := dbngPipeline.SaveResourceVersions(atc.ResourceConfig{
 					Name:   "some-resource",
@@ -1951,6 +2195,121 @@ var _ = Describe("Pipeline", func() {
 		})
 	})
 
+	Describe("DisableVersionsWhereMetadata", func() {
+		var (
+			resource      dbng.Resource
+			otherResource dbng.Resource
+		)
+
+		saveVersion := func(resourceName string, version string, metadata ...dbng.ResourceMetadataField) {
+			build, err := pipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = build.SaveOutput(dbng.VersionedResource{
+				Resource: resourceName,
+				Type:     "some-type",
+				Version:  dbng.ResourceVersion{"version": version},
+				Metadata: metadata,
+			}, false)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		lookupVersion := func(resourceName string, version string) (int, bool) {
+			var id int
+			var enabled bool
+			err := psql.Select("v.id", "v.enabled").
+				From("versioned_resources v").
+				Join("resources r ON r.id = v.resource_id").
+				Where(sq.Eq{
+					"r.name":        resourceName,
+					"r.pipeline_id": pipeline.ID(),
+					"v.version":     `{"version":"` + version + `"}`,
+				}).
+				RunWith(dbConn).
+				QueryRow().
+				Scan(&id, &enabled)
+			Expect(err).NotTo(HaveOccurred())
+
+			return id, enabled
+		}
+
+		isEnabled := func(resourceName string, version string) bool {
+			_, enabled := lookupVersion(resourceName, version)
+			return enabled
+		}
+
+		BeforeEach(func() {
+			var found bool
+			var err error
+			resource, found, err = pipeline.Resource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			otherResource, found, err = pipeline.Resource("some-other-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			saveVersion("some-resource", "v1",
+				dbng.ResourceMetadataField{Name: "vulnerable", Value: "true"},
+				dbng.ResourceMetadataField{Name: "commit", Value: "abc"},
+			)
+			saveVersion("some-resource", "v2",
+				dbng.ResourceMetadataField{Name: "vulnerable", Value: "false"},
+			)
+			saveVersion("some-resource", "v3",
+				dbng.ResourceMetadataField{Name: "commit", Value: "def"},
+				dbng.ResourceMetadataField{Name: "vulnerable", Value: "true"},
+			)
+			saveVersion("some-resource", "v4")
+			saveVersion("some-other-resource", "v1",
+				dbng.ResourceMetadataField{Name: "vulnerable", Value: "true"},
+			)
+		})
+
+		It("disables only the resource's versions with matching metadata", func() {
+			disabled, err := pipeline.DisableVersionsWhereMetadata(resource.ID(), "vulnerable", "true")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(disabled).To(Equal(2))
+
+			Expect(isEnabled("some-resource", "v1")).To(BeFalse())
+			Expect(isEnabled("some-resource", "v2")).To(BeTrue())
+			Expect(isEnabled("some-resource", "v3")).To(BeFalse())
+			Expect(isEnabled("some-resource", "v4")).To(BeTrue())
+			Expect(isEnabled("some-other-resource", "v1")).To(BeTrue())
+		})
+
+		It("does not count versions that were already disabled", func() {
+			_, err := pipeline.DisableVersionsWhereMetadata(resource.ID(), "commit", "abc")
+			Expect(err).NotTo(HaveOccurred())
+
+			disabled, err := pipeline.DisableVersionsWhereMetadata(resource.ID(), "vulnerable", "true")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(disabled).To(Equal(1))
+		})
+
+		It("leaves disabled versions re-enableable one at a time", func() {
+			_, err := pipeline.DisableVersionsWhereMetadata(resource.ID(), "vulnerable", "true")
+			Expect(err).NotTo(HaveOccurred())
+
+			versionID, _ := lookupVersion("some-resource", "v1")
+
+			err = pipeline.EnableVersionedResource(versionID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(isEnabled("some-resource", "v1")).To(BeTrue())
+			Expect(isEnabled("some-resource", "v3")).To(BeFalse())
+		})
+
+		Context("when no versions match", func() {
+			It("disables nothing", func() {
+				disabled, err := pipeline.DisableVersionsWhereMetadata(otherResource.ID(), "vulnerable", "false")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(disabled).To(BeZero())
+				Expect(isEnabled("some-other-resource", "v1")).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("Destroy", func() {
 		It("removes the pipeline and all of its data", func() {
 			By("populating resources table")
@@ -2014,7 +2373,103 @@ var _ = Describe("Pipeline", func() {
 		})
 	})
 
+	Describe("CreateJobBuild", func() {
+		It("creates a pending, manually triggered build", func() {
+			build, err := pipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(build.Status()).To(Equal(dbng.BuildStatusPending))
+			Expect(build.IsManuallyTriggered()).To(BeTrue())
+			Expect(build.TriggerReason()).To(Equal(dbng.BuildTriggerReasonManual))
+			Expect(build.Overrides().IsEmpty()).To(BeTrue())
+			Expect(build.InputsPinned()).To(BeFalse())
+		})
+	})
+
+	Describe("CreateJobBuildWithOverrides", func() {
+		It("keeps the overrides with the build", func() {
+			overrides := atc.BuildOverrides{
+				Versions: map[string]atc.Version{"some-input": {"ref": "abc"}},
+				Params:   map[string]atc.Params{"some-task": {"some": "param"}},
+			}
+
+			build, err := pipeline.CreateJobBuildWithOverrides("job-name", overrides)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(build.IsManuallyTriggered()).To(BeTrue())
+			Expect(build.Overrides()).To(Equal(overrides))
+
+			pendingBuilds, err := pipeline.GetPendingBuildsForJob("job-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pendingBuilds).To(HaveLen(1))
+			Expect(pendingBuilds[0].Overrides()).To(Equal(overrides))
+		})
+	})
+
+	Describe("CreateRerunBuild", func() {
+		var sourceBuild dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			sourceBuild, err = pipeline.CreateJobBuild("job-name")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = sourceBuild.SaveInput(dbng.BuildInput{
+				Name: "some-input",
+				VersionedResource: dbng.VersionedResource{
+					Resource: "some-resource",
+					Type:     "some-type",
+					Version:  dbng.ResourceVersion{"ref": "v1"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("creates a build with the source build's inputs pinned and the metadata set", func() {
+			savedVersion, found, err := pipeline.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			// disabled versions are still rerun with
+			err = pipeline.DisableVersionedResource(savedVersion.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			build, err := pipeline.CreateRerunBuild("job-name", sourceBuild.ID(), map[string]json.RawMessage{
+				"rerun-of": json.RawMessage(`{"build_id":1}`),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(build.Status()).To(Equal(dbng.BuildStatusPending))
+			Expect(build.IsManuallyTriggered()).To(BeTrue())
+			Expect(build.InputsPinned()).To(BeTrue())
+
+			inputs, _, err := build.Resources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inputs).To(HaveLen(1))
+			Expect(inputs[0].Name).To(Equal("some-input"))
+			Expect(inputs[0].VersionedResource.Version).To(Equal(dbng.ResourceVersion{"ref": "v1"}))
+
+			metadata, err := build.Metadata()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata["rerun-of"]).To(MatchJSON(`{"build_id":1}`))
+		})
+	})
+
 	Describe("EnsurePendingBuildExists", func() {
+		var triggeringVersion dbng.SavedVersionedResource
+
+		BeforeEach(func() {
+			err := pipeline.SaveResourceVersions(atc.ResourceConfig{
+				Name: "some-resource",
+				Type: "some-type",
+			}, []atc.Version{{"ref": "v1"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			var found bool
+			triggeringVersion, found, err = pipeline.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
 		Context("when only a started build exists", func() {
 			BeforeEach(func() {
 				build1, err := pipeline.CreateJobBuild("job-name")
@@ -2026,7 +2481,7 @@ var _ = Describe("Pipeline", func() {
 			})
 
 			It("creates a build", func() {
-				err := pipeline.EnsurePendingBuildExists("job-name")
+				err := pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.ResourceTriggerReason("some-resource"))
 				Expect(err).NotTo(HaveOccurred())
 
 				pendingBuildsForJob, err := pipeline.GetPendingBuildsForJob("job-name")
@@ -2034,11 +2489,21 @@ var _ = Describe("Pipeline", func() {
 				Expect(pendingBuildsForJob).To(HaveLen(1))
 			})
 
+			It("records why the build was triggered", func() {
+				err := pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.UpstreamTriggerReason("upstream-job"))
+				Expect(err).NotTo(HaveOccurred())
+
+				pendingBuildsForJob, err := pipeline.GetPendingBuildsForJob("job-name")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pendingBuildsForJob).To(HaveLen(1))
+				Expect(pendingBuildsForJob[0].TriggerReason()).To(Equal(dbng.BuildTriggerReason("upstream:upstream-job")))
+			})
+
 			It("doesn't create another build the second time it's called", func() {
-				err := pipeline.EnsurePendingBuildExists("job-name")
+				err := pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.ResourceTriggerReason("some-resource"))
 				Expect(err).NotTo(HaveOccurred())
 
-				err = pipeline.EnsurePendingBuildExists("job-name")
+				err = pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.ResourceTriggerReason("some-resource"))
 				Expect(err).NotTo(HaveOccurred())
 
 				builds2, err := pipeline.GetPendingBuildsForJob("job-name")
@@ -2056,6 +2521,130 @@ var _ = Describe("Pipeline", func() {
 		})
 	})
 
+	Describe("GetPendingBuildsTriggeredByVersion", func() {
+		var (
+			triggeringVersion dbng.SavedVersionedResource
+			otherVersion      dbng.SavedVersionedResource
+		)
+
+		BeforeEach(func() {
+			err := pipeline.SaveResourceVersions(atc.ResourceConfig{
+				Name: "some-resource",
+				Type: "some-type",
+			}, []atc.Version{{"ref": "v1"}, {"ref": "v2"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			var found bool
+			otherVersion, found, err = pipeline.GetVersionedResourceByVersion(atc.Version{"ref": "v1"}, "some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			triggeringVersion, found, err = pipeline.GetVersionedResourceByVersion(atc.Version{"ref": "v2"}, "some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
+		It("returns the pending builds triggered by the version", func() {
+			err := pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.ResourceTriggerReason("some-resource"))
+			Expect(err).NotTo(HaveOccurred())
+
+			pendingBuilds, err := pipeline.GetPendingBuildsForJob("job-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pendingBuilds).To(HaveLen(1))
+
+			builds, err := pipeline.GetPendingBuildsTriggeredByVersion(triggeringVersion.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(HaveLen(1))
+			Expect(builds[0].ID()).To(Equal(pendingBuilds[0].ID()))
+		})
+
+		It("does not return builds triggered by other versions", func() {
+			err := pipeline.EnsurePendingBuildExists("job-name", otherVersion.ID, dbng.ResourceTriggerReason("some-resource"))
+			Expect(err).NotTo(HaveOccurred())
+
+			builds, err := pipeline.GetPendingBuildsTriggeredByVersion(triggeringVersion.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(BeEmpty())
+		})
+
+		It("does not return builds which have started", func() {
+			err := pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.ResourceTriggerReason("some-resource"))
+			Expect(err).NotTo(HaveOccurred())
+
+			pendingBuilds, err := pipeline.GetPendingBuildsForJob("job-name")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pendingBuilds).To(HaveLen(1))
+
+			started, err := pendingBuilds[0].Start("some-engine", "some-metadata")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(started).To(BeTrue())
+
+			builds, err := pipeline.GetPendingBuildsTriggeredByVersion(triggeringVersion.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(builds).To(BeEmpty())
+		})
+	})
+
+	Describe("GetTriggeringVersion", func() {
+		var triggeringVersion dbng.SavedVersionedResource
+
+		BeforeEach(func() {
+			err := pipeline.SaveResourceVersions(atc.ResourceConfig{
+				Name: "some-resource",
+				Type: "some-type",
+			}, []atc.Version{{"ref": "abc123"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			var found bool
+			triggeringVersion, found, err = pipeline.GetLatestVersionedResource("some-resource")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+		})
+
+		Context("when the build was triggered by a new version", func() {
+			var build dbng.Build
+
+			BeforeEach(func() {
+				err := pipeline.EnsurePendingBuildExists("job-name", triggeringVersion.ID, dbng.ResourceTriggerReason("some-resource"))
+				Expect(err).NotTo(HaveOccurred())
+
+				pendingBuilds, err := pipeline.GetPendingBuildsForJob("job-name")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pendingBuilds).To(HaveLen(1))
+
+				build = pendingBuilds[0]
+			})
+
+			It("returns the version that triggered it", func() {
+				version, found, err := pipeline.GetTriggeringVersion(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				Expect(version.ID).To(Equal(triggeringVersion.ID))
+				Expect(version.Resource).To(Equal("some-resource"))
+				Expect(version.Type).To(Equal("some-type"))
+				Expect(version.Version).To(Equal(dbng.ResourceVersion{"ref": "abc123"}))
+			})
+		})
+
+		Context("when the build was triggered manually", func() {
+			var build dbng.Build
+
+			BeforeEach(func() {
+				var err error
+				build, err = pipeline.CreateJobBuild("job-name")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not find a triggering version", func() {
+				version, found, err := pipeline.GetTriggeringVersion(build.ID())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+				Expect(version).To(BeNil())
+			})
+		})
+	})
+
 	Describe("GetPendingBuildsForJob/GetAllPendingBuilds", func() {
 		Context("when a build is created", func() {
 			BeforeEach(func() {
@@ -0,0 +1,48 @@
+package dbng
+
+import (
+	"database/sql"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+type PoolStatsSource interface {
+	Stats() sql.DBStats
+}
+
+// PoolStatsReporter periodically logs the connection pool stats of a Conn, so
+// that pool exhaustion can be seen coming.
+type PoolStatsReporter struct {
+	Logger   lager.Logger
+	Source   PoolStatsSource
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+func (reporter PoolStatsReporter) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	ticker := reporter.Clock.NewTicker(reporter.Interval)
+	defer ticker.Stop()
+
+	close(ready)
+
+	for {
+		select {
+		case <-ticker.C():
+			reporter.report()
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+func (reporter PoolStatsReporter) report() {
+	stats := reporter.Source.Stats()
+
+	// the rest of sql.DBStats is only available from Go 1.11
+	reporter.Logger.Info("pool-stats", lager.Data{
+		"open-connections": stats.OpenConnections,
+	})
+}
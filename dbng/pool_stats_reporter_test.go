@@ -0,0 +1,60 @@
+package dbng_test
+
+import (
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc/dbng"
+	"github.com/tedsuo/ifrit"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PoolStatsReporter", func() {
+	var (
+		fakeClock    *fakeclock.FakeClock
+		reportLogger *lagertest.TestLogger
+		process      ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		reportLogger = lagertest.NewTestLogger("test")
+
+		process = ifrit.Invoke(dbng.PoolStatsReporter{
+			Logger:   reportLogger,
+			Source:   dbConn,
+			Interval: time.Minute,
+			Clock:    fakeClock,
+		})
+	})
+
+	AfterEach(func() {
+		process.Signal(os.Interrupt)
+		<-process.Wait()
+	})
+
+	It("logs the pool stats on every tick", func() {
+		Consistently(reportLogger.LogMessages).Should(BeEmpty())
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(reportLogger.LogMessages).Should(ConsistOf("test.pool-stats"))
+
+		logs := reportLogger.Logs()
+		Expect(logs[0].Data).To(HaveKey("open-connections"))
+
+		fakeClock.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(reportLogger.LogMessages).Should(HaveLen(2))
+	})
+
+	It("exits when signalled", func() {
+		process.Signal(os.Interrupt)
+		Eventually(process.Wait()).Should(Receive(BeNil()))
+
+		fakeClock.Increment(time.Minute)
+		Consistently(reportLogger.LogMessages).Should(BeEmpty())
+	})
+})
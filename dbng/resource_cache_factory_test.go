@@ -26,6 +26,9 @@ var _ = Describe("ResourceCacheFactory", func() {
 		resourceTypeUsingBogusBaseType atc.VersionedResourceType
 		resourceTypeOverridingBaseType atc.VersionedResourceType
 
+		pipelineWithTypes dbng.Pipeline
+		pipelineConfig    atc.Config
+
 		logger *lagertest.TestLogger
 		build  dbng.Build
 	)
@@ -105,17 +108,19 @@ var _ = Describe("ResourceCacheFactory", func() {
 			Version: atc.Version{"some-image-type": "version"},
 		}
 
-		pipelineWithTypes, _, err := defaultTeam.SavePipeline(
-			"pipeline-with-types",
-			atc.Config{
-				ResourceTypes: atc.ResourceTypes{
-					resourceType1.ResourceType,
-					resourceType2.ResourceType,
-					resourceType3.ResourceType,
-					resourceTypeUsingBogusBaseType.ResourceType,
-					resourceTypeOverridingBaseType.ResourceType,
-				},
+		pipelineConfig = atc.Config{
+			ResourceTypes: atc.ResourceTypes{
+				resourceType1.ResourceType,
+				resourceType2.ResourceType,
+				resourceType3.ResourceType,
+				resourceTypeUsingBogusBaseType.ResourceType,
+				resourceTypeOverridingBaseType.ResourceType,
 			},
+		}
+
+		pipelineWithTypes, _, err = defaultTeam.SavePipeline(
+			"pipeline-with-types",
+			pipelineConfig,
 			dbng.ConfigVersion(0),
 			dbng.PipelineUnpaused,
 		)
@@ -260,6 +265,69 @@ var _ = Describe("ResourceCacheFactory", func() {
 			Expect(usedResourceCache.ResourceConfig.CreatedByResourceCache.ResourceConfig.CreatedByBaseResourceType.ID).To(Equal(usedImageBaseResourceType.ID))
 		})
 
+		Context("when the pipeline config is reloaded without changing a custom type", func() {
+			customTypes := func() atc.VersionedResourceTypes {
+				found, err := pipelineWithTypes.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+
+				dbTypes, err := pipelineWithTypes.ResourceTypes()
+				Expect(err).NotTo(HaveOccurred())
+
+				return dbTypes.Deserialize()
+			}
+
+			BeforeEach(func() {
+				for _, rt := range []atc.VersionedResourceType{resourceType1, resourceType2} {
+					dbType, found, err := pipelineWithTypes.ResourceType(rt.Name)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+
+					err = dbType.SaveVersion(rt.Version)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			})
+
+			It("reuses the custom type's resource cache", func() {
+				beforeReload, err := resourceCacheFactory.FindOrCreateResourceCache(
+					logger,
+					dbng.ForBuild(build.ID()),
+					"some-type",
+					atc.Version{"some": "version"},
+					atc.Source{"some": "source"},
+					atc.Params{"some": "params"},
+					customTypes(),
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, err = defaultTeam.SavePipeline(
+					"pipeline-with-types",
+					pipelineConfig,
+					pipelineWithTypes.ConfigVersion(),
+					dbng.PipelineUnpaused,
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = resourceCacheFactory.CleanUsesForInactiveResourceTypes()
+				Expect(err).ToNot(HaveOccurred())
+
+				afterReload, err := resourceCacheFactory.FindOrCreateResourceCache(
+					logger,
+					dbng.ForBuild(build.ID()),
+					"some-type",
+					atc.Version{"some": "version"},
+					atc.Source{"some": "source"},
+					atc.Params{"some": "params"},
+					customTypes(),
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(afterReload.ID).To(Equal(beforeReload.ID))
+				Expect(afterReload.ResourceConfig.ID).To(Equal(beforeReload.ResourceConfig.ID))
+				Expect(afterReload.ResourceConfig.CreatedByResourceCache.ID).To(Equal(beforeReload.ResourceConfig.CreatedByResourceCache.ID))
+			})
+		})
+
 		Context("when the user no longer exists", func() {
 			BeforeEach(func() {
 				Expect(defaultTeam.Delete()).To(Succeed())
@@ -0,0 +1,84 @@
+package dbng
+
+import (
+	"database/sql"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const schedulerPausedSetting = "scheduler_paused"
+
+//go:generate counterfeiter . SchedulerSettings
+
+type SchedulerSettings interface {
+	SetSchedulerPaused(bool) error
+	IsSchedulerPaused() (bool, error)
+}
+
+type schedulerSettings struct {
+	conn Conn
+}
+
+func NewSchedulerSettings(conn Conn) SchedulerSettings {
+	return &schedulerSettings{
+		conn: conn,
+	}
+}
+
+func (s *schedulerSettings) SetSchedulerPaused(paused bool) error {
+	tx, err := s.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	value := strconv.FormatBool(paused)
+
+	result, err := psql.Update("settings").
+		Set("value", value).
+		Where(sq.Eq{"name": schedulerPausedSetting}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		_, err = psql.Insert("settings").
+			Columns("name", "value").
+			Values(schedulerPausedSetting, value).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *schedulerSettings) IsSchedulerPaused() (bool, error) {
+	var value string
+	err := psql.Select("value").
+		From("settings").
+		Where(sq.Eq{"name": schedulerPausedSetting}).
+		RunWith(s.conn).
+		QueryRow().
+		Scan(&value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,51 @@
+package dbng_test
+
+import (
+	"github.com/concourse/atc/dbng"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SchedulerSettings", func() {
+	var schedulerSettings dbng.SchedulerSettings
+
+	BeforeEach(func() {
+		schedulerSettings = dbng.NewSchedulerSettings(dbConn)
+	})
+
+	Describe("IsSchedulerPaused", func() {
+		Context("when the flag has never been set", func() {
+			It("returns false", func() {
+				paused, err := schedulerSettings.IsSchedulerPaused()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paused).To(BeFalse())
+			})
+		})
+
+		Context("when the scheduler has been paused", func() {
+			BeforeEach(func() {
+				err := schedulerSettings.SetSchedulerPaused(true)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns true", func() {
+				paused, err := schedulerSettings.IsSchedulerPaused()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(paused).To(BeTrue())
+			})
+
+			Context("and then unpaused", func() {
+				BeforeEach(func() {
+					err := schedulerSettings.SetSchedulerPaused(false)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("returns false", func() {
+					paused, err := schedulerSettings.IsSchedulerPaused()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(paused).To(BeFalse())
+				})
+			})
+		})
+	})
+})
@@ -42,6 +42,7 @@ type Team interface {
 	) (Pipeline, bool, error)
 
 	Pipeline(pipelineName string) (Pipeline, bool, error)
+	PipelineConfig(pipelineName string) (atc.Config, atc.RawConfig, ConfigVersion, error)
 	Pipelines() ([]Pipeline, error)
 	PublicPipelines() ([]Pipeline, error)
 	VisiblePipelines() ([]Pipeline, error)
@@ -69,6 +70,8 @@ type Team interface {
 	FindWorkerForBuildContainer(buildID int, planID atc.PlanID) (Worker, bool, error)
 	FindBuildContainerOnWorker(workerName string, buildID int, planID atc.PlanID) (CreatingContainer, CreatedContainer, error)
 	CreateBuildContainer(workerName string, buildID int, planID atc.PlanID, meta ContainerMetadata) (CreatingContainer, error)
+	FindLatestAttemptBuildContainer(buildID int, stepName string) (CreatedContainer, bool, error)
+	FindInterceptTarget(buildID int, stepName string, attempt []int) (string, string, bool, error)
 
 	UpdateBasicAuth(basicAuth *atc.BasicAuth) error
 	UpdateProviderAuth(auth map[string]*json.RawMessage) error
@@ -311,6 +314,7 @@ func (t *team) FindWorkerForBuildContainer(
 		sq.Eq{"c.build_id": buildID},
 		sq.Eq{"c.plan_id": string(planID)},
 		sq.Eq{"c.team_id": t.id},
+		sq.NotEq{"c.state": ContainerStateDestroying},
 	}))
 }
 
@@ -373,6 +377,165 @@ func (t *team) CreateBuildContainer(
 	), nil
 }
 
+func (t *team) FindLatestAttemptBuildContainer(
+	buildID int,
+	stepName string,
+) (CreatedContainer, bool, error) {
+	rows, err := selectContainers().
+		Where(sq.Eq{
+			"build_id":       buildID,
+			"meta_step_name": stepName,
+			"state":          ContainerStateCreated,
+			"discontinued":   false,
+			"team_id":        t.id,
+		}).
+		Where(sq.Expr(
+			"worker_name IN (SELECT name FROM workers WHERE state NOT IN (?, ?))",
+			string(WorkerStateStalled),
+			string(WorkerStateLanded),
+		)).
+		RunWith(t.conn).
+		Query()
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer rows.Close()
+
+	var latest CreatedContainer
+	var latestAttempt []int
+	for rows.Next() {
+		_, created, _, err := scanContainer(rows, t.conn)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if created == nil {
+			continue
+		}
+
+		attempt, err := ParseAttempt(created.Metadata().Attempt)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if latest == nil || attemptLess(latestAttempt, attempt) {
+			latest = created
+			latestAttempt = attempt
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if latest == nil {
+		return nil, false, nil
+	}
+
+	return latest, true, nil
+}
+
+// FindInterceptTarget returns the handle and worker address of the container
+// best matching the given build step. Containers for a step named exactly
+// stepName are preferred; otherwise step names are matched case-insensitively
+// by prefix. If attempt is given, only that attempt and its nested attempts
+// are considered. Among the matches, the latest attempt wins.
+func (t *team) FindInterceptTarget(
+	buildID int,
+	stepName string,
+	attempt []int,
+) (string, string, bool, error) {
+	rows, err := selectContainers().
+		Where(sq.Eq{
+			"build_id":     buildID,
+			"state":        ContainerStateCreated,
+			"discontinued": false,
+			"team_id":      t.id,
+		}).
+		Where(sq.Expr(
+			"worker_name IN (SELECT name FROM workers WHERE state NOT IN (?, ?))",
+			string(WorkerStateStalled),
+			string(WorkerStateLanded),
+		)).
+		OrderBy("id ASC").
+		RunWith(t.conn).
+		Query()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	defer rows.Close()
+
+	var exactMatch, fuzzyMatch CreatedContainer
+	var exactAttempt, fuzzyAttempt []int
+	for rows.Next() {
+		_, created, _, err := scanContainer(rows, t.conn)
+		if err != nil {
+			return "", "", false, err
+		}
+
+		if created == nil {
+			continue
+		}
+
+		metadata := created.Metadata()
+
+		containerAttempt, err := ParseAttempt(metadata.Attempt)
+		if err != nil {
+			return "", "", false, err
+		}
+
+		if !attemptHasPrefix(containerAttempt, attempt) {
+			continue
+		}
+
+		if metadata.StepName == stepName {
+			if exactMatch == nil || !attemptLess(containerAttempt, exactAttempt) {
+				exactMatch = created
+				exactAttempt = containerAttempt
+			}
+		} else if strings.HasPrefix(strings.ToLower(metadata.StepName), strings.ToLower(stepName)) {
+			if fuzzyMatch == nil || !attemptLess(containerAttempt, fuzzyAttempt) {
+				fuzzyMatch = created
+				fuzzyAttempt = containerAttempt
+			}
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	target := exactMatch
+	if target == nil {
+		target = fuzzyMatch
+	}
+
+	if target == nil {
+		return "", "", false, nil
+	}
+
+	var workerAddr sql.NullString
+	err = psql.Select("addr").
+		From("workers").
+		Where(sq.Eq{"name": target.WorkerName()}).
+		RunWith(t.conn).
+		QueryRow().
+		Scan(&workerAddr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", false, nil
+		}
+
+		return "", "", false, err
+	}
+
+	return target.Handle(), workerAddr.String, true, nil
+}
+
 func (t *team) FindContainerByHandle(
 	handle string,
 ) (Container, bool, error) {
@@ -555,12 +718,14 @@ func (t *team) SavePipeline(
 
 		err = psql.Insert("pipelines").
 			SetMap(map[string]interface{}{
-				"name":     pipelineName,
-				"config":   payload,
-				"version":  sq.Expr("nextval('config_version_seq')"),
-				"ordering": sq.Expr("(SELECT COUNT(1) + 1 FROM pipelines)"),
-				"paused":   pausedState.Bool(),
-				"team_id":  t.id,
+				"name":          pipelineName,
+				"config":        payload,
+				"version":       sq.Expr("nextval('config_version_seq')"),
+				"ordering":      sq.Expr("(SELECT COUNT(1) + 1 FROM pipelines)"),
+				"paused":        pausedState.Bool(),
+				"team_id":       t.id,
+				"max_in_flight": config.MaxInFlight,
+				"last_updated":  sq.Expr("now()"),
 			}).
 			Suffix("RETURNING id").
 			RunWith(tx).
@@ -596,6 +761,8 @@ func (t *team) SavePipeline(
 		update := psql.Update("pipelines").
 			Set("config", payload).
 			Set("version", sq.Expr("nextval('config_version_seq')")).
+			Set("max_in_flight", config.MaxInFlight).
+			Set("last_updated", sq.Expr("now()")).
 			Where(sq.Eq{
 				"name":    pipelineName,
 				"version": from,
@@ -728,6 +895,36 @@ func (t *team) Pipeline(pipelineName string) (Pipeline, bool, error) {
 	return pipeline, true, nil
 }
 
+// PipelineConfig returns the named pipeline's config, or an empty config if
+// there is no such pipeline. It reads from the replica if there is one.
+func (t *team) PipelineConfig(pipelineName string) (atc.Config, atc.RawConfig, ConfigVersion, error) {
+	var configBlob []byte
+	var version int
+	err := psql.Select("config", "version").
+		From("pipelines").
+		Where(sq.Eq{
+			"team_id": t.id,
+			"name":    pipelineName,
+		}).
+		RunWith(t.conn.ReadConn()).
+		QueryRow().
+		Scan(&configBlob, &version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return atc.Config{}, atc.RawConfig(""), 0, nil
+		}
+		return atc.Config{}, atc.RawConfig(""), 0, err
+	}
+
+	var config atc.Config
+	err = json.Unmarshal(configBlob, &config)
+	if err != nil {
+		return atc.Config{}, atc.RawConfig(string(configBlob)), ConfigVersion(version), atc.MalformedConfigError{UnmarshalError: err}
+	}
+
+	return config, atc.RawConfig(string(configBlob)), ConfigVersion(version), nil
+}
+
 func (t *team) Pipelines() ([]Pipeline, error) {
 	rows, err := pipelinesQuery.
 		Where(sq.Eq{
@@ -858,8 +1055,8 @@ func (t *team) CreateOneOffBuild() (Build, error) {
 
 	var buildID int
 	err = psql.Insert("builds").
-		Columns("team_id", "name", "status").
-		Values(t.id, sq.Expr("nextval('one_off_name')"), "pending").
+		Columns("team_id", "name", "status", "trigger_reason").
+		Values(t.id, sq.Expr("nextval('one_off_name')"), "pending", BuildTriggerReasonManual).
 		Suffix("RETURNING id").
 		RunWith(tx).
 		QueryRow().
@@ -1112,12 +1309,15 @@ func (t *team) findContainer(whereClause sq.Sqlizer) (CreatingContainer, Created
 
 func scanPipeline(p *pipeline, scan scannable) error {
 	var configBlob []byte
+	var lastUpdated pq.NullTime
 
-	err := scan.Scan(&p.id, &p.name, &p.configVersion, &p.teamID, &p.teamName, &configBlob, &p.paused, &p.public)
+	err := scan.Scan(&p.id, &p.name, &p.configVersion, &p.teamID, &p.teamName, &configBlob, &p.paused, &p.public, &p.archived, &lastUpdated)
 	if err != nil {
 		return err
 	}
 
+	p.lastUpdated = lastUpdated.Time
+
 	var config atc.Config
 	err = json.Unmarshal(configBlob, &config)
 	if err != nil {
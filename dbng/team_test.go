@@ -798,6 +798,25 @@ var _ = Describe("Team", func() {
 				Expect(found).To(BeFalse())
 				Expect(worker).To(BeNil())
 			})
+
+			Context("when the worker has stalled", func() {
+				BeforeEach(func() {
+					_, err := dbConn.Exec(`UPDATE workers SET expires = NOW() - INTERVAL '1 minute' WHERE name = $1`, defaultWorker.Name())
+					Expect(err).NotTo(HaveOccurred())
+
+					stalledWorkers, err := workerLifecycle.StallUnresponsiveWorkers()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(stalledWorkers).To(ContainElement(defaultWorker.Name()))
+				})
+
+				It("still returns it so the container can be re-attached", func() {
+					worker, found, err := defaultTeam.FindWorkerForBuildContainer(defaultBuild.ID(), "some-plan")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(worker.Name()).To(Equal(defaultWorker.Name()))
+					Expect(worker.State()).To(Equal(dbng.WorkerStateStalled))
+				})
+			})
 		})
 
 		Context("when there is no container", func() {
@@ -878,6 +897,273 @@ var _ = Describe("Team", func() {
 		})
 	})
 
+	Describe("FindLatestAttemptBuildContainer", func() {
+		var defaultBuild dbng.Build
+
+		BeforeEach(func() {
+			var err error
+			defaultBuild, err = defaultTeam.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the step has containers for multiple attempts", func() {
+			var firstAttemptContainer, secondAttemptContainer dbng.CreatedContainer
+
+			BeforeEach(func() {
+				firstAttempt, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), defaultBuild.ID(), "some-plan-1", dbng.ContainerMetadata{
+					Type:     "task",
+					StepName: "some-task",
+					Attempt:  "9",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				firstAttemptContainer, err = firstAttempt.Created()
+				Expect(err).NotTo(HaveOccurred())
+
+				secondAttempt, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), defaultBuild.ID(), "some-plan-2", dbng.ContainerMetadata{
+					Type:     "task",
+					StepName: "some-task",
+					Attempt:  "10",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				secondAttemptContainer, err = secondAttempt.Created()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns the container for the highest attempt", func() {
+				container, found, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(container.Handle()).To(Equal(secondAttemptContainer.Handle()))
+				Expect(container.Metadata().Attempt).To(Equal("10"))
+			})
+
+			It("does not find containers for another team", func() {
+				container, found, err := otherTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+				Expect(container).To(BeNil())
+			})
+
+			It("does not find containers for another step", func() {
+				container, found, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-other-task")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+				Expect(container).To(BeNil())
+			})
+
+			Context("when the latest attempt's container is discontinued", func() {
+				BeforeEach(func() {
+					_, err := secondAttemptContainer.Discontinue()
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("falls back to the latest live attempt", func() {
+					container, found, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(container.Handle()).To(Equal(firstAttemptContainer.Handle()))
+				})
+			})
+
+			for _, state := range []dbng.WorkerState{dbng.WorkerStateStalled, dbng.WorkerStateLanded} {
+				state := state
+
+				Context("when the worker is "+string(state), func() {
+					BeforeEach(func() {
+						_, err := psql.Update("workers").
+							Set("state", string(state)).
+							Where(sq.Eq{"name": defaultWorker.Name()}).
+							RunWith(dbConn).Exec()
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("returns false", func() {
+						_, found, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(found).To(BeFalse())
+					})
+				})
+			}
+		})
+
+		Context("when a container's attempt is malformed", func() {
+			BeforeEach(func() {
+				creating, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), defaultBuild.ID(), "some-plan", dbng.ContainerMetadata{
+					Type:     "task",
+					StepName: "some-task",
+					Attempt:  "1.x",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = creating.Created()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error rather than treating it as attempt 0", func() {
+				_, _, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+				Expect(err).To(Equal(dbng.MalformedAttemptError{Attempt: "1.x"}))
+			})
+		})
+
+		Context("when the only container is still being created", func() {
+			BeforeEach(func() {
+				_, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), defaultBuild.ID(), "some-plan", dbng.ContainerMetadata{
+					Type:     "task",
+					StepName: "some-task",
+					Attempt:  "1",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns false", func() {
+				_, found, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when there is no container", func() {
+			It("returns false", func() {
+				container, found, err := defaultTeam.FindLatestAttemptBuildContainer(defaultBuild.ID(), "some-task")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+				Expect(container).To(BeNil())
+			})
+		})
+	})
+
+	Describe("FindInterceptTarget", func() {
+		var defaultBuild dbng.Build
+
+		createContainer := func(planID atc.PlanID, stepName string, attempt string) dbng.CreatedContainer {
+			creating, err := defaultTeam.CreateBuildContainer(defaultWorker.Name(), defaultBuild.ID(), planID, dbng.ContainerMetadata{
+				Type:     "task",
+				StepName: stepName,
+				Attempt:  attempt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			created, err := creating.Created()
+			Expect(err).NotTo(HaveOccurred())
+
+			return created
+		}
+
+		BeforeEach(func() {
+			var err error
+			defaultBuild, err = defaultTeam.CreateOneOffBuild()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the step has containers for multiple attempts", func() {
+			var (
+				nestedAttemptContainer dbng.CreatedContainer
+				secondAttemptContainer dbng.CreatedContainer
+			)
+
+			BeforeEach(func() {
+				createContainer("some-plan-1", "some-task", "1")
+				nestedAttemptContainer = createContainer("some-plan-2", "some-task", "1.2")
+				secondAttemptContainer = createContainer("some-plan-3", "some-task", "2")
+			})
+
+			It("returns the latest attempt's container and its worker", func() {
+				handle, workerAddr, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(handle).To(Equal(secondAttemptContainer.Handle()))
+				Expect(workerAddr).To(Equal("1.2.3.4:7777"))
+			})
+
+			It("returns the latest container within the given attempt", func() {
+				handle, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", []int{1})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(handle).To(Equal(nestedAttemptContainer.Handle()))
+			})
+
+			It("returns the container for an exact attempt", func() {
+				handle, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", []int{1, 2})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(handle).To(Equal(nestedAttemptContainer.Handle()))
+			})
+
+			It("returns false for an attempt that has no container", func() {
+				_, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", []int{3})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			It("does not find containers for another team", func() {
+				_, _, found, err := otherTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			Context("when the latest attempt's container is discontinued", func() {
+				BeforeEach(func() {
+					_, err := secondAttemptContainer.Discontinue()
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("falls back to the latest live attempt", func() {
+					handle, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(handle).To(Equal(nestedAttemptContainer.Handle()))
+				})
+			})
+
+			Context("when the worker has stalled", func() {
+				BeforeEach(func() {
+					_, err := psql.Update("workers").
+						Set("state", string(dbng.WorkerStateStalled)).
+						Where(sq.Eq{"name": defaultWorker.Name()}).
+						RunWith(dbConn).Exec()
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns false", func() {
+					_, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "some-task", nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
+		Context("when step names only partially match", func() {
+			var (
+				exactContainer  dbng.CreatedContainer
+				prefixContainer dbng.CreatedContainer
+			)
+
+			BeforeEach(func() {
+				exactContainer = createContainer("some-plan-1", "unit", "1")
+				prefixContainer = createContainer("some-plan-2", "Unit-Tests", "2")
+			})
+
+			It("prefers an exact step name match over a later fuzzy match", func() {
+				handle, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "unit", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(handle).To(Equal(exactContainer.Handle()))
+			})
+
+			It("matches step names case-insensitively by prefix", func() {
+				handle, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "unit-t", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(handle).To(Equal(prefixContainer.Handle()))
+			})
+
+			It("returns false when no step matches", func() {
+				_, _, found, err := defaultTeam.FindInterceptTarget(defaultBuild.ID(), "integration", nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
 	Describe("Updating Auth", func() {
 		var (
 			basicAuth    *atc.BasicAuth
@@ -997,6 +1283,53 @@ var _ = Describe("Team", func() {
 		})
 	})
 
+	Describe("PipelineConfig", func() {
+		var config atc.Config
+
+		BeforeEach(func() {
+			config = atc.Config{
+				Jobs: atc.JobConfigs{
+					{Name: "job-name"},
+				},
+			}
+		})
+
+		Context("when the pipeline exists", func() {
+			var pipeline dbng.Pipeline
+
+			BeforeEach(func() {
+				var err error
+				pipeline, _, err = team.SavePipeline("fake-pipeline", config, dbng.ConfigVersion(1), dbng.PipelineUnpaused)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns the config and its version", func() {
+				actualConfig, rawConfig, version, err := team.PipelineConfig("fake-pipeline")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actualConfig).To(Equal(config))
+				Expect(rawConfig).NotTo(BeEmpty())
+				Expect(version).To(Equal(pipeline.ConfigVersion()))
+			})
+
+			It("does not return another team's pipeline", func() {
+				actualConfig, _, version, err := otherTeam.PipelineConfig("fake-pipeline")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actualConfig).To(Equal(atc.Config{}))
+				Expect(version).To(BeZero())
+			})
+		})
+
+		Context("when the pipeline does not exist", func() {
+			It("returns an empty config", func() {
+				actualConfig, rawConfig, version, err := team.PipelineConfig("bogus-pipeline")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actualConfig).To(Equal(atc.Config{}))
+				Expect(rawConfig).To(BeEmpty())
+				Expect(version).To(BeZero())
+			})
+		})
+	})
+
 	Describe("PublicPipelines", func() {
 		var (
 			pipelines []dbng.Pipeline
@@ -1168,6 +1501,7 @@ var _ = Describe("Team", func() {
 			Expect(oneOffBuild.Name()).To(Equal(strconv.Itoa(oneOffBuild.ID())))
 			Expect(oneOffBuild.TeamName()).To(Equal(team.Name()))
 			Expect(oneOffBuild.Status()).To(Equal(dbng.BuildStatusPending))
+			Expect(oneOffBuild.TriggerReason()).To(Equal(dbng.BuildTriggerReasonManual))
 		})
 	})
 
@@ -247,6 +247,7 @@ func (f *workerFactory) HeartbeatWorker(atcWorker atc.Worker, ttl time.Duration)
 		Set("baggageclaim_url", sq.Expr("("+bcSql+")")).
 		Set("active_containers", atcWorker.ActiveContainers).
 		Set("state", sq.Expr("("+cSql+")")).
+		Set("stalled_at", nil).
 		Where(sq.Eq{"name": atcWorker.Name}).
 		RunWith(tx).
 		Exec()
@@ -399,6 +400,7 @@ func saveWorker(tx Tx, atcWorker atc.Worker, teamID *int, ttl time.Duration, con
 			Set("version", workerVersion).
 			Set("start_time", atcWorker.StartTime).
 			Set("state", string(workerState)).
+			Set("stalled_at", nil).
 			Where(sq.Eq{
 				"name": atcWorker.Name,
 			}).
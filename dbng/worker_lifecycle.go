@@ -2,6 +2,7 @@ package dbng
 
 import (
 	"database/sql"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -10,6 +11,7 @@ import (
 
 type WorkerLifecycle interface {
 	StallUnresponsiveWorkers() ([]string, error)
+	DeleteUnresponsiveStalledWorkers(gracePeriod time.Duration) ([]string, error)
 	LandFinishedLandingWorkers() ([]string, error)
 	DeleteFinishedRetiringWorkers() ([]string, error)
 }
@@ -31,6 +33,7 @@ func (lifecycle *workerLifecycle) StallUnresponsiveWorkers() ([]string, error) {
 			"addr":             nil,
 			"baggageclaim_url": nil,
 			"expires":          nil,
+			"stalled_at":       sq.Expr("NOW()"),
 		}).
 		Where(sq.Eq{"state": string(WorkerStateRunning)}).
 		Where(sq.Expr("expires < NOW()")).
@@ -48,6 +51,22 @@ func (lifecycle *workerLifecycle) StallUnresponsiveWorkers() ([]string, error) {
 	return workersAffected(rows)
 }
 
+// DeleteUnresponsiveStalledWorkers deletes workers that have stayed stalled
+// for longer than the grace period without heartbeating again.
+func (lifecycle *workerLifecycle) DeleteUnresponsiveStalledWorkers(gracePeriod time.Duration) ([]string, error) {
+	rows, err := psql.Delete("workers").
+		Where(sq.Eq{"state": string(WorkerStateStalled)}).
+		Where(sq.Expr("stalled_at < NOW() - ? * INTERVAL '1 second'", gracePeriod.Seconds())).
+		Suffix("RETURNING name").
+		RunWith(lifecycle.conn).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return workersAffected(rows)
+}
+
 func (lifecycle *workerLifecycle) DeleteFinishedRetiringWorkers() ([]string, error) {
 	// Squirrel does not have default support for subqueries in where clauses.
 	// We hacked together a way to do it
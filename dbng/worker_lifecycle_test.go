@@ -73,6 +73,65 @@ var _ = Describe("Worker Lifecycle", func() {
 		})
 	})
 
+	Describe("DeleteUnresponsiveStalledWorkers", func() {
+		BeforeEach(func() {
+			_, err := workerFactory.SaveWorker(atcWorker, -1*time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			stalledWorkers, err := workerLifecycle.StallUnresponsiveWorkers()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stalledWorkers).To(Equal([]string{"some-name"}))
+		})
+
+		Context("when the worker has been stalled for less than the grace period", func() {
+			It("leaves the worker alone", func() {
+				deletedWorkers, err := workerLifecycle.DeleteUnresponsiveStalledWorkers(time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deletedWorkers).To(BeEmpty())
+
+				worker, found, err := workerFactory.GetWorker(atcWorker.Name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(worker.State()).To(Equal(dbng.WorkerStateStalled))
+			})
+		})
+
+		Context("when the worker has been stalled for longer than the grace period", func() {
+			BeforeEach(func() {
+				_, err := dbConn.Exec(`UPDATE workers SET stalled_at = NOW() - INTERVAL '2 hours' WHERE name = $1`, atcWorker.Name)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("deletes the worker", func() {
+				deletedWorkers, err := workerLifecycle.DeleteUnresponsiveStalledWorkers(time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deletedWorkers).To(Equal([]string{"some-name"}))
+
+				_, found, err := workerFactory.GetWorker(atcWorker.Name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			Context("when the worker heartbeats again before being deleted", func() {
+				BeforeEach(func() {
+					_, err := workerFactory.HeartbeatWorker(atcWorker, 5*time.Minute)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("recovers the worker to running and keeps it", func() {
+					deletedWorkers, err := workerLifecycle.DeleteUnresponsiveStalledWorkers(time.Hour)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(deletedWorkers).To(BeEmpty())
+
+					worker, found, err := workerFactory.GetWorker(atcWorker.Name)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(worker.State()).To(Equal(dbng.WorkerStateRunning))
+				})
+			})
+		})
+	})
+
 	Describe("DeleteFinishedRetiringWorkers", func() {
 		var (
 			dbWorker dbng.Worker
@@ -55,6 +55,13 @@ func (build *execBuild) buildTryStep(logger lager.Logger, plan atc.Plan) exec.St
 	return exec.Try(step)
 }
 
+func (build *execBuild) buildStagedStep(logger lager.Logger, plan atc.Plan) exec.StepFactory {
+	innerPlan := plan.Staged.Step
+	innerPlan.Attempts = plan.Attempts
+	step := build.buildStepFactory(logger, innerPlan)
+	return exec.Staged(step)
+}
+
 func (build *execBuild) buildOnSuccessStep(logger lager.Logger, plan atc.Plan) exec.StepFactory {
 	plan.OnSuccess.Step.Attempts = plan.Attempts
 	step := build.buildStepFactory(logger, plan.OnSuccess.Step)
@@ -96,6 +103,22 @@ func (build *execBuild) buildTaskStep(logger lager.Logger, plan atc.Plan) exec.S
 		return exec.Identity{}
 	}
 
+	configSource = exec.PipelineVarsConfigSource{
+		ConfigSource: configSource,
+		PipelineID:   build.pipelineID,
+		Vars:         build.pipelineVars,
+	}
+
+	configSource = exec.TaskResultsConfigSource{
+		ConfigSource: configSource,
+		Results:      build.delegate,
+	}
+
+	configSource = exec.BuildEnvConfigSource{
+		ConfigSource: configSource,
+		Env:          build.delegate,
+	}
+
 	configSource = exec.ValidatingConfigSource{configSource}
 
 	workerMetadata := build.workerMetadata(
@@ -121,6 +144,10 @@ func (build *execBuild) buildTaskStep(logger lager.Logger, plan atc.Plan) exec.S
 		plan.Task.InputMapping,
 		plan.Task.OutputMapping,
 		plan.Task.ImageArtifactName,
+		plan.Task.RetryableExitCodes,
+		plan.Task.ScriptTimeout,
+		plan.Task.StopTimeout,
+		plan.Task.Dir,
 		clock,
 	)
 }
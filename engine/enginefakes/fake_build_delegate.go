@@ -2,6 +2,7 @@
 package enginefakes
 
 import (
+	"encoding/json"
 	"sync"
 
 	"code.cloudfoundry.org/lager"
@@ -59,6 +60,28 @@ type FakeBuildDelegate struct {
 		arg3 exec.Success
 		arg4 bool
 	}
+	ExportedEnvStub        func() (map[string]string, error)
+	exportedEnvMutex       sync.RWMutex
+	exportedEnvArgsForCall []struct{}
+	exportedEnvReturns     struct {
+		result1 map[string]string
+		result2 error
+	}
+	exportedEnvReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	TaskResultsStub        func() (map[string]json.RawMessage, error)
+	taskResultsMutex       sync.RWMutex
+	taskResultsArgsForCall []struct{}
+	taskResultsReturns     struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}
+	taskResultsReturnsOnCall map[int]struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -240,6 +263,92 @@ func (fake *FakeBuildDelegate) FinishArgsForCall(i int) (lager.Logger, error, ex
 	return fake.finishArgsForCall[i].arg1, fake.finishArgsForCall[i].arg2, fake.finishArgsForCall[i].arg3, fake.finishArgsForCall[i].arg4
 }
 
+func (fake *FakeBuildDelegate) ExportedEnv() (map[string]string, error) {
+	fake.exportedEnvMutex.Lock()
+	ret, specificReturn := fake.exportedEnvReturnsOnCall[len(fake.exportedEnvArgsForCall)]
+	fake.exportedEnvArgsForCall = append(fake.exportedEnvArgsForCall, struct{}{})
+	fake.recordInvocation("ExportedEnv", []interface{}{})
+	fake.exportedEnvMutex.Unlock()
+	if fake.ExportedEnvStub != nil {
+		return fake.ExportedEnvStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.exportedEnvReturns.result1, fake.exportedEnvReturns.result2
+}
+
+func (fake *FakeBuildDelegate) ExportedEnvCallCount() int {
+	fake.exportedEnvMutex.RLock()
+	defer fake.exportedEnvMutex.RUnlock()
+	return len(fake.exportedEnvArgsForCall)
+}
+
+func (fake *FakeBuildDelegate) ExportedEnvReturns(result1 map[string]string, result2 error) {
+	fake.ExportedEnvStub = nil
+	fake.exportedEnvReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildDelegate) ExportedEnvReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.ExportedEnvStub = nil
+	if fake.exportedEnvReturnsOnCall == nil {
+		fake.exportedEnvReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.exportedEnvReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildDelegate) TaskResults() (map[string]json.RawMessage, error) {
+	fake.taskResultsMutex.Lock()
+	ret, specificReturn := fake.taskResultsReturnsOnCall[len(fake.taskResultsArgsForCall)]
+	fake.taskResultsArgsForCall = append(fake.taskResultsArgsForCall, struct{}{})
+	fake.recordInvocation("TaskResults", []interface{}{})
+	fake.taskResultsMutex.Unlock()
+	if fake.TaskResultsStub != nil {
+		return fake.TaskResultsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.taskResultsReturns.result1, fake.taskResultsReturns.result2
+}
+
+func (fake *FakeBuildDelegate) TaskResultsCallCount() int {
+	fake.taskResultsMutex.RLock()
+	defer fake.taskResultsMutex.RUnlock()
+	return len(fake.taskResultsArgsForCall)
+}
+
+func (fake *FakeBuildDelegate) TaskResultsReturns(result1 map[string]json.RawMessage, result2 error) {
+	fake.TaskResultsStub = nil
+	fake.taskResultsReturns = struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildDelegate) TaskResultsReturnsOnCall(i int, result1 map[string]json.RawMessage, result2 error) {
+	fake.TaskResultsStub = nil
+	if fake.taskResultsReturnsOnCall == nil {
+		fake.taskResultsReturnsOnCall = make(map[int]struct {
+			result1 map[string]json.RawMessage
+			result2 error
+		})
+	}
+	fake.taskResultsReturnsOnCall[i] = struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeBuildDelegate) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -251,6 +360,10 @@ func (fake *FakeBuildDelegate) Invocations() map[string][][]interface{} {
 	defer fake.outputDelegateMutex.RUnlock()
 	fake.finishMutex.RLock()
 	defer fake.finishMutex.RUnlock()
+	fake.exportedEnvMutex.RLock()
+	defer fake.exportedEnvMutex.RUnlock()
+	fake.taskResultsMutex.RLock()
+	defer fake.taskResultsMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -3,8 +3,6 @@ package engine
 import (
 	"encoding/json"
 	"errors"
-	"strconv"
-	"strings"
 
 	"os"
 
@@ -27,6 +25,7 @@ type execEngine struct {
 	factory         exec.Factory
 	delegateFactory BuildDelegateFactory
 	teamDBFactory   db.TeamDBFactory
+	pipelineVars    exec.PipelineVars
 	externalURL     string
 	releaseCh       chan struct{}
 }
@@ -35,12 +34,14 @@ func NewExecEngine(
 	factory exec.Factory,
 	delegateFactory BuildDelegateFactory,
 	teamDBFactory db.TeamDBFactory,
+	pipelineVars exec.PipelineVars,
 	externalURL string,
 ) Engine {
 	return &execEngine{
 		factory:         factory,
 		delegateFactory: delegateFactory,
 		teamDBFactory:   teamDBFactory,
+		pipelineVars:    pipelineVars,
 		externalURL:     externalURL,
 		releaseCh:       make(chan struct{}),
 	}
@@ -63,8 +64,9 @@ func (engine *execEngine) CreateBuild(logger lager.Logger, build dbng.Build, pla
 
 		stepMetadata: buildMetadata(build, engine.externalURL),
 
-		factory:  engine.factory,
-		delegate: engine.delegateFactory.Delegate(build),
+		factory:      engine.factory,
+		delegate:     engine.delegateFactory.Delegate(build),
+		pipelineVars: engine.pipelineVars,
 		metadata: execMetadata{
 			Plan: plan,
 		},
@@ -94,9 +96,10 @@ func (engine *execEngine) LookupBuild(logger lager.Logger, build dbng.Build) (Bu
 
 		stepMetadata: buildMetadata(build, engine.externalURL),
 
-		factory:  engine.factory,
-		delegate: engine.delegateFactory.Delegate(build),
-		metadata: metadata,
+		factory:      engine.factory,
+		delegate:     engine.delegateFactory.Delegate(build),
+		pipelineVars: engine.pipelineVars,
+		metadata:     metadata,
 
 		releaseCh: engine.releaseCh,
 		signals:   make(chan os.Signal, 1),
@@ -131,8 +134,9 @@ type execBuild struct {
 
 	stepMetadata StepMetadata
 
-	factory  exec.Factory
-	delegate BuildDelegate
+	factory      exec.Factory
+	delegate     BuildDelegate
+	pipelineVars exec.PipelineVars
 
 	signals   chan os.Signal
 	releaseCh chan struct{}
@@ -215,6 +219,10 @@ func (build *execBuild) buildStepFactory(logger lager.Logger, plan atc.Plan) exe
 		return build.buildTryStep(logger, plan)
 	}
 
+	if plan.Staged != nil {
+		return build.buildStagedStep(logger, plan)
+	}
+
 	if plan.OnSuccess != nil {
 		return build.buildOnSuccessStep(logger, plan)
 	}
@@ -255,11 +263,6 @@ func (build *execBuild) workerMetadata(
 	stepName string,
 	attempts []int,
 ) dbng.ContainerMetadata {
-	attemptStrs := []string{}
-	for _, a := range attempts {
-		attemptStrs = append(attemptStrs, strconv.Itoa(a))
-	}
-
 	return dbng.ContainerMetadata{
 		Type: containerType,
 
@@ -272,6 +275,6 @@ func (build *execBuild) workerMetadata(
 		BuildName:    build.buildName,
 
 		StepName: stepName,
-		Attempt:  strings.Join(attemptStrs, "."),
+		Attempt:  dbng.FormatAttempt(attempts),
 	}
 }
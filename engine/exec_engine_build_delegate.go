@@ -1,7 +1,9 @@
 package engine
 
 import (
+	"encoding/json"
 	"io"
+	"strings"
 	"sync"
 	"time"
 	"unicode/utf8"
@@ -14,6 +16,14 @@ import (
 	"github.com/concourse/atc/worker"
 )
 
+// Variables exported by tasks are stored as build metadata under this prefix,
+// so that a later export of the same variable replaces the earlier one.
+const exportedEnvMetadataPrefix = "env:"
+
+// Task results are stored as build metadata under this prefix, followed by
+// the name of the output that carried them.
+const taskResultMetadataPrefix = "result:"
+
 type implicitOutput struct {
 	plan atc.GetPlan
 	info exec.VersionInfo
@@ -27,6 +37,9 @@ type BuildDelegate interface {
 	OutputDelegate(lager.Logger, atc.PutPlan, event.OriginID) exec.PutDelegate
 
 	Finish(lager.Logger, error, exec.Success, bool)
+
+	ExportedEnv() (map[string]string, error)
+	TaskResults() (map[string]json.RawMessage, error)
 }
 
 //go:generate counterfeiter . BuildDelegateFactory
@@ -35,27 +48,33 @@ type BuildDelegateFactory interface {
 	Delegate(dbng.Build) BuildDelegate
 }
 
-type buildDelegateFactory struct{}
+type buildDelegateFactory struct {
+	buildFactory dbng.BuildFactory
+}
 
-func NewBuildDelegateFactory() BuildDelegateFactory {
-	return buildDelegateFactory{}
+func NewBuildDelegateFactory(buildFactory dbng.BuildFactory) BuildDelegateFactory {
+	return buildDelegateFactory{
+		buildFactory: buildFactory,
+	}
 }
 
 func (factory buildDelegateFactory) Delegate(build dbng.Build) BuildDelegate {
-	return newBuildDelegate(build)
+	return newBuildDelegate(build, factory.buildFactory)
 }
 
 type delegate struct {
-	build dbng.Build
+	build        dbng.Build
+	buildFactory dbng.BuildFactory
 
 	implicitOutputs map[string]implicitOutput
 
 	lock sync.Mutex
 }
 
-func newBuildDelegate(build dbng.Build) BuildDelegate {
+func newBuildDelegate(build dbng.Build, buildFactory dbng.BuildFactory) BuildDelegate {
 	return &delegate{
-		build: build,
+		build:        build,
+		buildFactory: buildFactory,
 
 		implicitOutputs: make(map[string]implicitOutput),
 	}
@@ -117,6 +136,48 @@ func (delegate *delegate) Finish(logger lager.Logger, err error, succeeded exec.
 	}
 }
 
+func (delegate *delegate) ExportedEnv() (map[string]string, error) {
+	metadata, err := delegate.build.Metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for name, value := range metadata {
+		if !strings.HasPrefix(name, exportedEnvMetadataPrefix) {
+			continue
+		}
+
+		var val string
+		err = json.Unmarshal(value, &val)
+		if err != nil {
+			return nil, err
+		}
+
+		env[strings.TrimPrefix(name, exportedEnvMetadataPrefix)] = val
+	}
+
+	return env, nil
+}
+
+func (delegate *delegate) TaskResults() (map[string]json.RawMessage, error) {
+	metadata, err := delegate.build.Metadata()
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[string]json.RawMessage{}
+	for name, value := range metadata {
+		if !strings.HasPrefix(name, taskResultMetadataPrefix) {
+			continue
+		}
+
+		results[strings.TrimPrefix(name, taskResultMetadataPrefix)] = value
+	}
+
+	return results, nil
+}
+
 func (delegate *delegate) registerImplicitOutput(resource string, output implicitOutput) {
 	delegate.lock.Lock()
 	delegate.implicitOutputs[resource] = output
@@ -167,6 +228,17 @@ func (delegate *delegate) saveStart(logger lager.Logger, origin event.Origin) {
 	}
 }
 
+func (delegate *delegate) saveTaskStage(logger lager.Logger, stage string, at time.Time, origin event.Origin) {
+	err := delegate.build.SaveEvent(event.TaskStage{
+		Stage:  stage,
+		Time:   at.Unix(),
+		Origin: origin,
+	})
+	if err != nil {
+		logger.Error("failed-to-save-task-stage-event", err)
+	}
+}
+
 func (delegate *delegate) saveFinish(logger lager.Logger, status exec.ExitStatus, origin event.Origin) {
 	err := delegate.build.SaveEvent(event.FinishTask{
 		ExitStatus: int(status),
@@ -418,6 +490,14 @@ func (execution *executionDelegate) Started() {
 	execution.logger.Info("started")
 }
 
+func (execution *executionDelegate) Stage(stage string, at time.Time) {
+	execution.delegate.saveTaskStage(execution.logger, stage, at, event.Origin{
+		ID: execution.id,
+	})
+
+	execution.logger.Debug("stage", lager.Data{"stage": stage})
+}
+
 func (execution *executionDelegate) Finished(status exec.ExitStatus) {
 	execution.delegate.saveFinish(execution.logger, status, event.Origin{
 		ID: execution.id,
@@ -438,6 +518,41 @@ func (execution *executionDelegate) ImageVersionDetermined(resourceCacheIdentifi
 	return execution.delegate.build.SaveImageResourceVersion(atc.PlanID(execution.id), resourceCacheIdentifier.ResourceVersion, resourceCacheIdentifier.ResourceHash)
 }
 
+// PinnedImageVersion returns the version of the task's image resource saved
+// earlier in the build, for example by a previous attempt of the task, if
+// any.
+func (execution *executionDelegate) PinnedImageVersion(resourceHash string) (atc.Version, bool, error) {
+	return execution.delegate.build.ImageResourceVersion(resourceHash)
+}
+
+func (execution *executionDelegate) ResultDetermined(output string, result json.RawMessage) error {
+	return execution.delegate.build.SetMetadata(taskResultMetadataPrefix+output, result)
+}
+
+func (execution *executionDelegate) EnvExported(env map[string]string) error {
+	for name, value := range env {
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		err = execution.delegate.build.SetMetadata(exportedEnvMetadataPrefix+name, payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (execution *executionDelegate) ResourceUsageMeasured(usage exec.ResourceUsage) error {
+	if !usage.Measured {
+		return execution.delegate.buildFactory.RecordUnmeasuredBuildResourceUsage(execution.delegate.build.ID())
+	}
+
+	return execution.delegate.buildFactory.RecordBuildResourceUsage(execution.delegate.build.ID(), usage.CPUSeconds, usage.MemoryByteSeconds)
+}
+
 func (execution *executionDelegate) Stdout() io.Writer {
 	return execution.delegate.eventWriter(event.Origin{
 		Source: event.OriginSourceStdout,
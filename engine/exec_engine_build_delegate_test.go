@@ -1,6 +1,7 @@
 package engine_test
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"time"
@@ -22,7 +23,8 @@ var _ = Describe("BuildDelegate", func() {
 	var (
 		factory BuildDelegateFactory
 
-		fakeBuild *dbngfakes.FakeBuild
+		fakeBuild        *dbngfakes.FakeBuild
+		fakeBuildFactory *dbngfakes.FakeBuildFactory
 
 		delegate BuildDelegate
 
@@ -32,9 +34,11 @@ var _ = Describe("BuildDelegate", func() {
 	)
 
 	BeforeEach(func() {
-		factory = NewBuildDelegateFactory()
+		fakeBuildFactory = new(dbngfakes.FakeBuildFactory)
+		factory = NewBuildDelegateFactory(fakeBuildFactory)
 
 		fakeBuild = new(dbngfakes.FakeBuild)
+		fakeBuild.IDReturns(42)
 		delegate = factory.Delegate(fakeBuild)
 
 		logger = lagertest.NewTestLogger("test")
@@ -537,6 +541,25 @@ var _ = Describe("BuildDelegate", func() {
 			})
 		})
 
+		Describe("Stage", func() {
+			JustBeforeEach(func() {
+				executionDelegate.Stage(exec.TaskStageWorkerChosen, time.Unix(123, 0))
+			})
+
+			It("saves a task stage event", func() {
+				Expect(fakeBuild.SaveEventCallCount()).To(Equal(1))
+
+				savedEvent := fakeBuild.SaveEventArgsForCall(0)
+				Expect(savedEvent).To(Equal(event.TaskStage{
+					Stage: "worker-chosen",
+					Time:  123,
+					Origin: event.Origin{
+						ID: originID,
+					},
+				}))
+			})
+		})
+
 		Describe("Finished", func() {
 			var exitStatus exec.ExitStatus
 
@@ -681,6 +704,102 @@ var _ = Describe("BuildDelegate", func() {
 			})
 		})
 
+		Describe("PinnedImageVersion", func() {
+			It("looks up the version saved in the build for the image resource", func() {
+				fakeBuild.ImageResourceVersionReturns(atc.Version{"ref": "asdf"}, true, nil)
+
+				version, found, err := executionDelegate.PinnedImageVersion("some-resource-hash")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(version).To(Equal(atc.Version{"ref": "asdf"}))
+
+				Expect(fakeBuild.ImageResourceVersionCallCount()).To(Equal(1))
+				Expect(fakeBuild.ImageResourceVersionArgsForCall(0)).To(Equal("some-resource-hash"))
+			})
+
+			It("propagates errors", func() {
+				disaster := errors.New("sorry mate")
+				fakeBuild.ImageResourceVersionReturns(nil, false, disaster)
+
+				_, _, err := executionDelegate.PinnedImageVersion("some-resource-hash")
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
+		Describe("ResultDetermined", func() {
+			It("saves the result as build metadata", func() {
+				err := executionDelegate.ResultDetermined("some-output", json.RawMessage(`{"coverage":87.5}`))
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeBuild.SetMetadataCallCount()).To(Equal(1))
+				name, value := fakeBuild.SetMetadataArgsForCall(0)
+				Expect(name).To(Equal("result:some-output"))
+				Expect(value).To(MatchJSON(`{"coverage":87.5}`))
+			})
+
+			It("propagates errors", func() {
+				disaster := errors.New("nope")
+				fakeBuild.SetMetadataReturns(disaster)
+
+				err := executionDelegate.ResultDetermined("some-output", json.RawMessage(`{}`))
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
+		Describe("EnvExported", func() {
+			It("saves each variable as build metadata", func() {
+				err := executionDelegate.EnvExported(map[string]string{"VERSION": "1.2.3"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeBuild.SetMetadataCallCount()).To(Equal(1))
+				name, value := fakeBuild.SetMetadataArgsForCall(0)
+				Expect(name).To(Equal("env:VERSION"))
+				Expect(value).To(MatchJSON(`"1.2.3"`))
+			})
+
+			It("propagates errors", func() {
+				disaster := errors.New("nope")
+				fakeBuild.SetMetadataReturns(disaster)
+
+				err := executionDelegate.EnvExported(map[string]string{"VERSION": "1.2.3"})
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
+		Describe("ResourceUsageMeasured", func() {
+			It("records the usage against the build", func() {
+				err := executionDelegate.ResourceUsageMeasured(exec.ResourceUsage{
+					CPUSeconds:        1.5,
+					MemoryByteSeconds: 1024,
+					Measured:          true,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeBuildFactory.RecordBuildResourceUsageCallCount()).To(Equal(1))
+				buildID, cpuSeconds, memByteSeconds := fakeBuildFactory.RecordBuildResourceUsageArgsForCall(0)
+				Expect(buildID).To(Equal(42))
+				Expect(cpuSeconds).To(Equal(1.5))
+				Expect(memByteSeconds).To(Equal(float64(1024)))
+			})
+
+			It("flags usage that could not be measured", func() {
+				err := executionDelegate.ResourceUsageMeasured(exec.ResourceUsage{})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeBuildFactory.RecordBuildResourceUsageCallCount()).To(BeZero())
+				Expect(fakeBuildFactory.RecordUnmeasuredBuildResourceUsageCallCount()).To(Equal(1))
+				Expect(fakeBuildFactory.RecordUnmeasuredBuildResourceUsageArgsForCall(0)).To(Equal(42))
+			})
+
+			It("propagates errors", func() {
+				disaster := errors.New("nope")
+				fakeBuildFactory.RecordBuildResourceUsageReturns(disaster)
+
+				err := executionDelegate.ResourceUsageMeasured(exec.ResourceUsage{Measured: true})
+				Expect(err).To(Equal(disaster))
+			})
+		})
+
 		Describe("Stdout", func() {
 			var writer io.Writer
 
@@ -1091,4 +1210,119 @@ var _ = Describe("BuildDelegate", func() {
 			})
 		})
 	})
+
+	Describe("ExportedEnv", func() {
+		var metadata map[string]json.RawMessage
+
+		BeforeEach(func() {
+			metadata = map[string]json.RawMessage{
+				"result:some-output": json.RawMessage(`{"coverage":87.5}`),
+			}
+
+			fakeBuild.SetMetadataStub = func(name string, value json.RawMessage) error {
+				metadata[name] = value
+				return nil
+			}
+
+			fakeBuild.MetadataStub = func() (map[string]json.RawMessage, error) {
+				return metadata, nil
+			}
+		})
+
+		It("returns the variables exported by the build's tasks so far", func() {
+			firstTask := delegate.ExecutionDelegate(logger, atc.TaskPlan{Name: "first-task"}, "first-origin")
+			err := firstTask.EnvExported(map[string]string{
+				"VERSION": "1.2.3",
+				"GIT_SHA": "abc123",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			env, err := delegate.ExportedEnv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env).To(Equal(map[string]string{
+				"VERSION": "1.2.3",
+				"GIT_SHA": "abc123",
+			}))
+
+			secondTask := delegate.ExecutionDelegate(logger, atc.TaskPlan{Name: "second-task"}, "second-origin")
+			err = secondTask.EnvExported(map[string]string{
+				"VERSION": "1.2.4",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("letting later tasks override variables exported by earlier ones")
+			env, err = delegate.ExportedEnv()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env).To(Equal(map[string]string{
+				"VERSION": "1.2.4",
+				"GIT_SHA": "abc123",
+			}))
+		})
+
+		Context("when nothing has been exported", func() {
+			It("returns no variables", func() {
+				env, err := delegate.ExportedEnv()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(env).To(BeEmpty())
+			})
+		})
+
+		Context("when looking up the build's metadata fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeBuild.MetadataStub = nil
+				fakeBuild.MetadataReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := delegate.ExportedEnv()
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("TaskResults", func() {
+		var metadata map[string]json.RawMessage
+
+		BeforeEach(func() {
+			metadata = map[string]json.RawMessage{
+				"env:VERSION": json.RawMessage(`"1.2.3"`),
+			}
+
+			fakeBuild.SetMetadataStub = func(name string, value json.RawMessage) error {
+				metadata[name] = value
+				return nil
+			}
+
+			fakeBuild.MetadataStub = func() (map[string]json.RawMessage, error) {
+				return metadata, nil
+			}
+		})
+
+		It("returns the results recorded by the build's tasks so far, by output", func() {
+			task := delegate.ExecutionDelegate(logger, atc.TaskPlan{Name: "some-task"}, "some-origin")
+			err := task.ResultDetermined("some-output", json.RawMessage(`{"coverage":87.5}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := delegate.TaskResults()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results["some-output"]).To(MatchJSON(`{"coverage":87.5}`))
+		})
+
+		Context("when looking up the build's metadata fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeBuild.MetadataStub = nil
+				fakeBuild.MetadataReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := delegate.TaskResults()
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
 })
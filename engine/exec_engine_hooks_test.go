@@ -45,6 +45,7 @@ var _ = Describe("Exec Engine With Hooks", func() {
 			fakeFactory,
 			fakeDelegateFactory,
 			fakeTeamDBFactory,
+			new(execfakes.FakePipelineVars),
 			"http://example.com",
 		)
 
@@ -213,7 +214,7 @@ var _ = Describe("Exec Engine With Hooks", func() {
 
 				It("constructs the completion hook correctly", func() {
 					Expect(fakeFactory.TaskCallCount()).To(Equal(4))
-					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(2)
+					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(2)
 					Expect(logger).NotTo(BeNil())
 					Expect(teamID).To(Equal(expectedTeamID))
 					Expect(buildID).To(Equal(expectedBuildID))
@@ -238,7 +239,7 @@ var _ = Describe("Exec Engine With Hooks", func() {
 
 				It("constructs the failure hook correctly", func() {
 					Expect(fakeFactory.TaskCallCount()).To(Equal(4))
-					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
+					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 					Expect(logger).NotTo(BeNil())
 					Expect(teamID).To(Equal(expectedTeamID))
 					Expect(buildID).To(Equal(expectedBuildID))
@@ -263,7 +264,7 @@ var _ = Describe("Exec Engine With Hooks", func() {
 
 				It("constructs the success hook correctly", func() {
 					Expect(fakeFactory.TaskCallCount()).To(Equal(4))
-					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(1)
+					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(1)
 					Expect(logger).NotTo(BeNil())
 					Expect(teamID).To(Equal(expectedTeamID))
 					Expect(buildID).To(Equal(expectedBuildID))
@@ -288,7 +289,7 @@ var _ = Describe("Exec Engine With Hooks", func() {
 
 				It("constructs the next step correctly", func() {
 					Expect(fakeFactory.TaskCallCount()).To(Equal(4))
-					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(3)
+					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, _, _, _, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(3)
 					Expect(logger).NotTo(BeNil())
 					Expect(teamID).To(Equal(expectedTeamID))
 					Expect(buildID).To(Equal(expectedBuildID))
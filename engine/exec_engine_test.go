@@ -23,6 +23,7 @@ var _ = Describe("ExecEngine", func() {
 		fakeFactory         *execfakes.FakeFactory
 		fakeTeamDB          *dbfakes.FakeTeamDB
 		fakeDelegateFactory *enginefakes.FakeBuildDelegateFactory
+		fakePipelineVars    *execfakes.FakePipelineVars
 		logger              *lagertest.TestLogger
 
 		execEngine engine.Engine
@@ -36,6 +37,7 @@ var _ = Describe("ExecEngine", func() {
 	BeforeEach(func() {
 		fakeFactory = new(execfakes.FakeFactory)
 		fakeDelegateFactory = new(enginefakes.FakeBuildDelegateFactory)
+		fakePipelineVars = new(execfakes.FakePipelineVars)
 		logger = lagertest.NewTestLogger("test")
 
 		fakeTeamDBFactory := new(dbfakes.FakeTeamDBFactory)
@@ -45,6 +47,7 @@ var _ = Describe("ExecEngine", func() {
 			fakeFactory,
 			fakeDelegateFactory,
 			fakeTeamDBFactory,
+			fakePipelineVars,
 			"http://example.com",
 		)
 	})
@@ -430,7 +433,7 @@ var _ = Describe("ExecEngine", func() {
 			})
 
 			It("constructs nested steps correctly", func() {
-				logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, privileged, tags, configSource, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
+				logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, privileged, tags, configSource, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 				Expect(logger).NotTo(BeNil())
 				Expect(teamID).To(Equal(expectedTeamID))
 				Expect(buildID).To(Equal(expectedBuildID))
@@ -450,9 +453,21 @@ var _ = Describe("ExecEngine", func() {
 				Expect(delegate).To(Equal(fakeExecutionDelegate))
 				Expect(privileged).To(Equal(exec.Privileged(false)))
 				Expect(tags).To(Equal(atc.Tags{"some", "task", "tags"}))
-				Expect(configSource).To(Equal(exec.ValidatingConfigSource{exec.FileConfigSource{"some-config-path"}}))
+				Expect(configSource).To(Equal(exec.ValidatingConfigSource{
+					ConfigSource: exec.BuildEnvConfigSource{
+						ConfigSource: exec.TaskResultsConfigSource{
+							ConfigSource: exec.PipelineVarsConfigSource{
+								ConfigSource: exec.FileConfigSource{"some-config-path"},
+								PipelineID:   expectedPipelineID,
+								Vars:         fakePipelineVars,
+							},
+							Results: fakeDelegate,
+						},
+						Env: fakeDelegate,
+					},
+				}))
 
-				logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, privileged, tags, configSource, _, _, _, _, _ = fakeFactory.TaskArgsForCall(1)
+				logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, privileged, tags, configSource, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(1)
 				Expect(logger).NotTo(BeNil())
 				Expect(teamID).To(Equal(expectedTeamID))
 				Expect(buildID).To(Equal(expectedBuildID))
@@ -472,7 +487,19 @@ var _ = Describe("ExecEngine", func() {
 				Expect(delegate).To(Equal(fakeExecutionDelegate))
 				Expect(privileged).To(Equal(exec.Privileged(false)))
 				Expect(tags).To(Equal(atc.Tags{"some", "task", "tags"}))
-				Expect(configSource).To(Equal(exec.ValidatingConfigSource{exec.FileConfigSource{"some-config-path"}}))
+				Expect(configSource).To(Equal(exec.ValidatingConfigSource{
+					ConfigSource: exec.BuildEnvConfigSource{
+						ConfigSource: exec.TaskResultsConfigSource{
+							ConfigSource: exec.PipelineVarsConfigSource{
+								ConfigSource: exec.FileConfigSource{"some-config-path"},
+								PipelineID:   expectedPipelineID,
+								Vars:         fakePipelineVars,
+							},
+							Results: fakeDelegate,
+						},
+						Env: fakeDelegate,
+					},
+				}))
 			})
 		})
 
@@ -519,13 +546,13 @@ var _ = Describe("ExecEngine", func() {
 			})
 
 			It("constructs nested steps correctly", func() {
-				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
+				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 				Expect(workerMetadata.Attempt).To(Equal("1"))
-				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(1)
+				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(1)
 				Expect(workerMetadata.Attempt).To(Equal("1"))
-				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(2)
+				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(2)
 				Expect(workerMetadata.Attempt).To(Equal("1"))
-				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(3)
+				_, _, _, _, _, workerMetadata, _, _, _, _, _, _, _, _, _, _, _, _, _ = fakeFactory.TaskArgsForCall(3)
 				Expect(workerMetadata.Attempt).To(Equal("1"))
 			})
 		})
@@ -615,7 +642,7 @@ var _ = Describe("ExecEngine", func() {
 					build.Resume(logger)
 					Expect(fakeFactory.TaskCallCount()).To(Equal(1))
 
-					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, privileged, tags, configSource, _, actualInputMapping, actualOutputMapping, _, _ := fakeFactory.TaskArgsForCall(0)
+					logger, teamID, buildID, planID, sourceName, workerMetadata, delegate, privileged, tags, configSource, _, actualInputMapping, actualOutputMapping, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 					Expect(logger).NotTo(BeNil())
 					Expect(teamID).To(Equal(expectedTeamID))
 					Expect(buildID).To(Equal(expectedBuildID))
@@ -654,7 +681,7 @@ var _ = Describe("ExecEngine", func() {
 						build.Resume(logger)
 						Expect(fakeFactory.TaskCallCount()).To(Equal(1))
 
-						_, _, _, _, _, _, _, _, _, _, _, _, _, actualImageArtifactName, _ := fakeFactory.TaskArgsForCall(0)
+						_, _, _, _, _, _, _, _, _, _, _, _, _, actualImageArtifactName, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 						Expect(actualImageArtifactName).To(Equal("some-image-artifact-name"))
 					})
 				})
@@ -666,7 +693,7 @@ var _ = Describe("ExecEngine", func() {
 						}
 					})
 
-					It("creates the task with a MergedConfigSource wrapped in a ValidatingConfigSource", func() {
+					It("creates the task with a MergedConfigSource wrapped in a PipelineVarsConfigSource, a TaskResultsConfigSource, a BuildEnvConfigSource and a ValidatingConfigSource", func() {
 						var err error
 						build, err = execEngine.CreateBuild(logger, dbBuild, plan)
 						Expect(err).NotTo(HaveOccurred())
@@ -674,10 +701,16 @@ var _ = Describe("ExecEngine", func() {
 						build.Resume(logger)
 						Expect(fakeFactory.TaskCallCount()).To(Equal(1))
 
-						_, _, _, _, _, _, _, _, _, configSource, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
+						_, _, _, _, _, _, _, _, _, configSource, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 						vcs, ok := configSource.(exec.ValidatingConfigSource)
 						Expect(ok).To(BeTrue())
-						_, ok = vcs.ConfigSource.(exec.MergedConfigSource)
+						becs, ok := vcs.ConfigSource.(exec.BuildEnvConfigSource)
+						Expect(ok).To(BeTrue())
+						trcs, ok := becs.ConfigSource.(exec.TaskResultsConfigSource)
+						Expect(ok).To(BeTrue())
+						pvcs, ok := trcs.ConfigSource.(exec.PipelineVarsConfigSource)
+						Expect(ok).To(BeTrue())
+						_, ok = pvcs.ConfigSource.(exec.MergedConfigSource)
 						Expect(ok).To(BeTrue())
 					})
 				})
@@ -691,7 +724,7 @@ var _ = Describe("ExecEngine", func() {
 						}
 					})
 
-					It("creates the task with a MergedConfigSource wrapped in a ValidatingConfigSource", func() {
+					It("creates the task with a MergedConfigSource wrapped in a PipelineVarsConfigSource, a TaskResultsConfigSource, a BuildEnvConfigSource and a ValidatingConfigSource", func() {
 						var err error
 						build, err = execEngine.CreateBuild(logger, dbBuild, plan)
 						Expect(err).NotTo(HaveOccurred())
@@ -699,10 +732,16 @@ var _ = Describe("ExecEngine", func() {
 						build.Resume(logger)
 						Expect(fakeFactory.TaskCallCount()).To(Equal(1))
 
-						_, _, _, _, _, _, _, _, _, configSource, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
+						_, _, _, _, _, _, _, _, _, configSource, _, _, _, _, _, _, _, _, _ := fakeFactory.TaskArgsForCall(0)
 						vcs, ok := configSource.(exec.ValidatingConfigSource)
 						Expect(ok).To(BeTrue())
-						_, ok = vcs.ConfigSource.(exec.MergedConfigSource)
+						becs, ok := vcs.ConfigSource.(exec.BuildEnvConfigSource)
+						Expect(ok).To(BeTrue())
+						trcs, ok := becs.ConfigSource.(exec.TaskResultsConfigSource)
+						Expect(ok).To(BeTrue())
+						pvcs, ok := trcs.ConfigSource.(exec.PipelineVarsConfigSource)
+						Expect(ok).To(BeTrue())
+						_, ok = pvcs.ConfigSource.(exec.MergedConfigSource)
 						Expect(ok).To(BeTrue())
 					})
 				})
@@ -45,6 +45,7 @@ var _ = Describe("Exec Engine with Try", func() {
 			fakeFactory,
 			fakeDelegateFactory,
 			fakeTeamDBFactory,
+			new(execfakes.FakePipelineVars),
 			"http://example.com",
 		)
 
@@ -77,6 +77,15 @@ type StartTask struct {
 func (StartTask) EventType() atc.EventType  { return EventTypeStartTask }
 func (StartTask) Version() atc.EventVersion { return "4.0" }
 
+type TaskStage struct {
+	Stage  string `json:"stage"`
+	Time   int64  `json:"time"`
+	Origin Origin `json:"origin"`
+}
+
+func (TaskStage) EventType() atc.EventType  { return EventTypeTaskStage }
+func (TaskStage) Version() atc.EventVersion { return "1.0" }
+
 type Status struct {
 	Status atc.BuildStatus `json:"status"`
 	Time   int64           `json:"time"`
@@ -85,6 +94,13 @@ type Status struct {
 func (Status) EventType() atc.EventType  { return EventTypeStatus }
 func (Status) Version() atc.EventVersion { return "1.0" }
 
+type Resynced struct {
+	From uint `json:"from"`
+}
+
+func (Resynced) EventType() atc.EventType  { return EventTypeResynced }
+func (Resynced) Version() atc.EventVersion { return "1.0" }
+
 type Log struct {
 	Origin  Origin `json:"origin"`
 	Payload string `json:"payload"`
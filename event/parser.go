@@ -36,6 +36,7 @@ func init() {
 	registerEvent(InitializeTask{})
 	registerEvent(StartTask{})
 	registerEvent(FinishTask{})
+	registerEvent(TaskStage{})
 	registerEvent(InitializeGet{})
 	registerEvent(FinishGet{})
 	registerEvent(InitializePut{})
@@ -43,6 +44,7 @@ func init() {
 	registerEvent(Status{})
 	registerEvent(Log{})
 	registerEvent(Error{})
+	registerEvent(Resynced{})
 
 	// deprecated:
 	registerEvent(FinishV10{})
@@ -18,6 +18,9 @@ const (
 	// task execution finished
 	EventTypeFinishTask atc.EventType = "finish-task"
 
+	// task transitioned to a new stage (e.g. 'worker-chosen')
+	EventTypeTaskStage atc.EventType = "task-stage"
+
 	// get step initializing
 	EventTypeInitializeGet atc.EventType = "initialize-get"
 
@@ -32,4 +35,7 @@ const (
 
 	// error occurred
 	EventTypeError atc.EventType = "error"
+
+	// events were compacted while streaming; stream resumed at a new offset
+	EventTypeResynced atc.EventType = "resynced"
 )
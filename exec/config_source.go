@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -218,6 +219,270 @@ func (configSource ValidatingConfigSource) Warnings() []string {
 	return configSource.ConfigSource.Warnings()
 }
 
+//go:generate counterfeiter . PipelineVars
+
+// PipelineVars looks up the non-sensitive variables configured on a pipeline.
+type PipelineVars interface {
+	GetPipelineVars(pipelineID int) (map[string]string, error)
+}
+
+var pipelineVarPattern = regexp.MustCompile(`\(\(var:([^()]+)\)\)`)
+
+// PipelineVarsConfigSource delegates to another ConfigSource, and resolves
+// ((var:key)) references in its task config's params using the vars set on
+// the build's pipeline.
+type PipelineVarsConfigSource struct {
+	ConfigSource TaskConfigSource
+
+	PipelineID int
+	Vars       PipelineVars
+}
+
+// FetchConfig fetches the config using the underlying ConfigSource, and
+// replaces any var references in its params. The pipeline's vars are only
+// looked up if a param references one.
+//
+// If a param references a var that is not set, UndefinedPipelineVarError is
+// returned.
+func (configSource PipelineVarsConfigSource) FetchConfig(source *worker.ArtifactRepository) (atc.TaskConfig, error) {
+	config, err := configSource.ConfigSource.FetchConfig(source)
+	if err != nil {
+		return atc.TaskConfig{}, err
+	}
+
+	var vars map[string]string
+
+	params := make(map[string]string, len(config.Params))
+	for name, value := range config.Params {
+		if !pipelineVarPattern.MatchString(value) {
+			params[name] = value
+			continue
+		}
+
+		if vars == nil {
+			vars, err = configSource.lookupVars()
+			if err != nil {
+				return atc.TaskConfig{}, err
+			}
+		}
+
+		var undefined []string
+		params[name] = pipelineVarPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			key := pipelineVarPattern.FindStringSubmatch(ref)[1]
+
+			val, found := vars[key]
+			if !found {
+				undefined = append(undefined, key)
+			}
+
+			return val
+		})
+
+		if len(undefined) > 0 {
+			return atc.TaskConfig{}, UndefinedPipelineVarError{Param: name, Key: undefined[0]}
+		}
+	}
+
+	if config.Params != nil {
+		config.Params = params
+	}
+
+	return config, nil
+}
+
+func (configSource PipelineVarsConfigSource) lookupVars() (map[string]string, error) {
+	if configSource.PipelineID == 0 {
+		return map[string]string{}, nil
+	}
+
+	return configSource.Vars.GetPipelineVars(configSource.PipelineID)
+}
+
+func (configSource PipelineVarsConfigSource) Warnings() []string {
+	return configSource.ConfigSource.Warnings()
+}
+
+//go:generate counterfeiter . BuildEnv
+
+// BuildEnv looks up the environment variables exported by the tasks that
+// have run so far in a build.
+type BuildEnv interface {
+	ExportedEnv() (map[string]string, error)
+}
+
+// BuildEnvConfigSource delegates to another ConfigSource, and adds the
+// variables exported by earlier tasks in the build to its task config's
+// params.
+type BuildEnvConfigSource struct {
+	ConfigSource TaskConfigSource
+
+	Env BuildEnv
+}
+
+// FetchConfig fetches the config using the underlying ConfigSource, and adds
+// the build's exported variables to its params. Params configured on the task
+// itself take precedence over exported variables of the same name.
+func (configSource BuildEnvConfigSource) FetchConfig(source *worker.ArtifactRepository) (atc.TaskConfig, error) {
+	config, err := configSource.ConfigSource.FetchConfig(source)
+	if err != nil {
+		return atc.TaskConfig{}, err
+	}
+
+	env, err := configSource.Env.ExportedEnv()
+	if err != nil {
+		return atc.TaskConfig{}, err
+	}
+
+	if len(env) == 0 {
+		return config, nil
+	}
+
+	params := make(map[string]string, len(env)+len(config.Params))
+	for name, value := range env {
+		params[name] = value
+	}
+
+	for name, value := range config.Params {
+		params[name] = value
+	}
+
+	config.Params = params
+
+	return config, nil
+}
+
+func (configSource BuildEnvConfigSource) Warnings() []string {
+	return configSource.ConfigSource.Warnings()
+}
+
+var taskResultPattern = regexp.MustCompile(`\(\(result:([^().]+)\.([^()]+)\)\)`)
+
+//go:generate counterfeiter . BuildResults
+
+// BuildResults looks up the results recorded by the tasks that have run so
+// far in a build, keyed by the name of the output that carried them.
+type BuildResults interface {
+	TaskResults() (map[string]json.RawMessage, error)
+}
+
+// TaskResultsConfigSource delegates to another ConfigSource, and resolves
+// ((result:output.key)) references in its task config's params using the
+// results of earlier tasks in the build.
+type TaskResultsConfigSource struct {
+	ConfigSource TaskConfigSource
+
+	Results BuildResults
+}
+
+// FetchConfig fetches the config using the underlying ConfigSource, and
+// replaces any result references in its params. String values are substituted
+// as-is; any other value is substituted as JSON. The build's results are only
+// looked up if a param references one.
+//
+// If a param references a result that has not been recorded,
+// UndefinedTaskResultError is returned.
+func (configSource TaskResultsConfigSource) FetchConfig(source *worker.ArtifactRepository) (atc.TaskConfig, error) {
+	config, err := configSource.ConfigSource.FetchConfig(source)
+	if err != nil {
+		return atc.TaskConfig{}, err
+	}
+
+	var results map[string]json.RawMessage
+
+	params := make(map[string]string, len(config.Params))
+	for name, value := range config.Params {
+		if !taskResultPattern.MatchString(value) {
+			params[name] = value
+			continue
+		}
+
+		if results == nil {
+			results, err = configSource.Results.TaskResults()
+			if err != nil {
+				return atc.TaskConfig{}, err
+			}
+		}
+
+		var undefined *UndefinedTaskResultError
+		params[name] = taskResultPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			match := taskResultPattern.FindStringSubmatch(ref)
+			output, key := match[1], match[2]
+
+			val, found := lookupTaskResult(results, output, key)
+			if !found && undefined == nil {
+				undefined = &UndefinedTaskResultError{Param: name, Output: output, Key: key}
+			}
+
+			return val
+		})
+
+		if undefined != nil {
+			return atc.TaskConfig{}, *undefined
+		}
+	}
+
+	if config.Params != nil {
+		config.Params = params
+	}
+
+	return config, nil
+}
+
+func (configSource TaskResultsConfigSource) Warnings() []string {
+	return configSource.ConfigSource.Warnings()
+}
+
+func lookupTaskResult(results map[string]json.RawMessage, output string, key string) (string, bool) {
+	result, found := results[output]
+	if !found {
+		return "", false
+	}
+
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(result, &fields)
+	if err != nil {
+		return "", false
+	}
+
+	field, found := fields[key]
+	if !found {
+		return "", false
+	}
+
+	var str string
+	err = json.Unmarshal(field, &str)
+	if err == nil {
+		return str, true
+	}
+
+	return string(field), true
+}
+
+// UndefinedPipelineVarError is returned when a task param references a var
+// that is not set on the pipeline.
+type UndefinedPipelineVarError struct {
+	Param string
+	Key   string
+}
+
+// Error returns a human-friendly error message.
+func (err UndefinedPipelineVarError) Error() string {
+	return fmt.Sprintf("param '%s' references undefined pipeline var '%s'", err.Param, err.Key)
+}
+
+// UndefinedTaskResultError is returned when a task param references a result
+// that no earlier task in the build has recorded.
+type UndefinedTaskResultError struct {
+	Param  string
+	Output string
+	Key    string
+}
+
+// Error returns a human-friendly error message.
+func (err UndefinedTaskResultError) Error() string {
+	return fmt.Sprintf("param '%s' references undefined result '%s' of output '%s'", err.Param, err.Key, err.Output)
+}
+
 // UnknownArtifactSourceError is returned when the worker.ArtifactName specified by the
 // path does not exist in the worker.ArtifactRepository.
 type UnknownArtifactSourceError struct {
@@ -1,6 +1,7 @@
 package exec_test
 
 import (
+	"encoding/json"
 	"errors"
 
 	"github.com/concourse/atc"
@@ -549,4 +550,352 @@ run: {path: a/file}
 			})
 		})
 	})
+
+	Describe("PipelineVarsConfigSource", func() {
+		var (
+			fakeConfigSource *execfakes.FakeTaskConfigSource
+			fakePipelineVars *execfakes.FakePipelineVars
+
+			pipelineID   int
+			configSource TaskConfigSource
+
+			fetchedConfig atc.TaskConfig
+			fetchErr      error
+		)
+
+		BeforeEach(func() {
+			fakeConfigSource = new(execfakes.FakeTaskConfigSource)
+			fakePipelineVars = new(execfakes.FakePipelineVars)
+			fakePipelineVars.GetPipelineVarsReturns(map[string]string{
+				"region": "us-east-1",
+				"bucket": "some-bucket",
+			}, nil)
+
+			pipelineID = 42
+		})
+
+		JustBeforeEach(func() {
+			configSource = PipelineVarsConfigSource{
+				ConfigSource: fakeConfigSource,
+				PipelineID:   pipelineID,
+				Vars:         fakePipelineVars,
+			}
+
+			fetchedConfig, fetchErr = configSource.FetchConfig(repo)
+		})
+
+		Context("when params reference pipeline vars", func() {
+			var params map[string]string
+
+			BeforeEach(func() {
+				params = map[string]string{
+					"REGION":      "((var:region))",
+					"BUCKET_PATH": "s3://((var:bucket))/((var:region))/artifacts",
+					"PLAIN":       "some-value",
+				}
+
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{
+					Platform: "some-platform",
+					Params:   params,
+				}, nil)
+			})
+
+			It("resolves them from the build's pipeline", func() {
+				Expect(fetchErr).NotTo(HaveOccurred())
+
+				Expect(fakePipelineVars.GetPipelineVarsCallCount()).To(Equal(1))
+				Expect(fakePipelineVars.GetPipelineVarsArgsForCall(0)).To(Equal(42))
+
+				Expect(fetchedConfig.Params).To(Equal(map[string]string{
+					"REGION":      "us-east-1",
+					"BUCKET_PATH": "s3://some-bucket/us-east-1/artifacts",
+					"PLAIN":       "some-value",
+				}))
+			})
+
+			It("does not modify the underlying config's params", func() {
+				Expect(params["REGION"]).To(Equal("((var:region))"))
+			})
+
+			Context("when a referenced var is not set", func() {
+				BeforeEach(func() {
+					fakePipelineVars.GetPipelineVarsReturns(map[string]string{
+						"region": "us-east-1",
+					}, nil)
+				})
+
+				It("returns an error", func() {
+					Expect(fetchErr).To(Equal(UndefinedPipelineVarError{
+						Param: "BUCKET_PATH",
+						Key:   "bucket",
+					}))
+				})
+			})
+
+			Context("when looking up the vars fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakePipelineVars.GetPipelineVarsReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(fetchErr).To(Equal(disaster))
+				})
+			})
+
+			Context("when the build does not belong to a pipeline", func() {
+				BeforeEach(func() {
+					pipelineID = 0
+				})
+
+				It("fails without looking up any vars", func() {
+					Expect(fetchErr).To(BeAssignableToTypeOf(UndefinedPipelineVarError{}))
+					Expect(fakePipelineVars.GetPipelineVarsCallCount()).To(BeZero())
+				})
+			})
+		})
+
+		Context("when no params reference pipeline vars", func() {
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{
+					Platform: "some-platform",
+					Params:   map[string]string{"PLAIN": "some-value"},
+				}, nil)
+			})
+
+			It("returns the config without looking up any vars", func() {
+				Expect(fetchErr).NotTo(HaveOccurred())
+				Expect(fetchedConfig.Params).To(Equal(map[string]string{"PLAIN": "some-value"}))
+				Expect(fakePipelineVars.GetPipelineVarsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when fetching the config fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{}, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(fetchErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("TaskResultsConfigSource", func() {
+		var (
+			fakeConfigSource *execfakes.FakeTaskConfigSource
+			fakeBuildResults *execfakes.FakeBuildResults
+
+			configSource TaskConfigSource
+
+			fetchedConfig atc.TaskConfig
+			fetchErr      error
+		)
+
+		BeforeEach(func() {
+			fakeConfigSource = new(execfakes.FakeTaskConfigSource)
+			fakeBuildResults = new(execfakes.FakeBuildResults)
+			fakeBuildResults.TaskResultsReturns(map[string]json.RawMessage{
+				"version": json.RawMessage(`{"number":"1.2.3","coverage":87.5}`),
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			configSource = TaskResultsConfigSource{
+				ConfigSource: fakeConfigSource,
+				Results:      fakeBuildResults,
+			}
+
+			fetchedConfig, fetchErr = configSource.FetchConfig(repo)
+		})
+
+		Context("when params reference task results", func() {
+			var params map[string]string
+
+			BeforeEach(func() {
+				params = map[string]string{
+					"VERSION":  "((result:version.number))",
+					"COVERAGE": "coverage: ((result:version.coverage))%",
+					"PLAIN":    "some-value",
+				}
+
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{
+					Platform: "some-platform",
+					Params:   params,
+				}, nil)
+			})
+
+			It("resolves them from the build's results", func() {
+				Expect(fetchErr).NotTo(HaveOccurred())
+
+				Expect(fakeBuildResults.TaskResultsCallCount()).To(Equal(1))
+
+				Expect(fetchedConfig.Params).To(Equal(map[string]string{
+					"VERSION":  "1.2.3",
+					"COVERAGE": "coverage: 87.5%",
+					"PLAIN":    "some-value",
+				}))
+			})
+
+			It("does not modify the underlying config's params", func() {
+				Expect(params["VERSION"]).To(Equal("((result:version.number))"))
+			})
+
+			Context("when a referenced result has not been recorded", func() {
+				BeforeEach(func() {
+					fakeBuildResults.TaskResultsReturns(map[string]json.RawMessage{
+						"version": json.RawMessage(`{"number":"1.2.3"}`),
+					}, nil)
+				})
+
+				It("returns an error", func() {
+					Expect(fetchErr).To(Equal(UndefinedTaskResultError{
+						Param:  "COVERAGE",
+						Output: "version",
+						Key:    "coverage",
+					}))
+				})
+			})
+
+			Context("when looking up the results fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeBuildResults.TaskResultsReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(fetchErr).To(Equal(disaster))
+				})
+			})
+		})
+
+		Context("when no params reference task results", func() {
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{
+					Platform: "some-platform",
+					Params:   map[string]string{"PLAIN": "some-value"},
+				}, nil)
+			})
+
+			It("returns the config without looking up any results", func() {
+				Expect(fetchErr).NotTo(HaveOccurred())
+				Expect(fetchedConfig.Params).To(Equal(map[string]string{"PLAIN": "some-value"}))
+				Expect(fakeBuildResults.TaskResultsCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when fetching the config fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{}, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(fetchErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("BuildEnvConfigSource", func() {
+		var (
+			fakeConfigSource *execfakes.FakeTaskConfigSource
+			fakeBuildEnv     *execfakes.FakeBuildEnv
+
+			params       map[string]string
+			configSource TaskConfigSource
+
+			fetchedConfig atc.TaskConfig
+			fetchErr      error
+		)
+
+		BeforeEach(func() {
+			fakeConfigSource = new(execfakes.FakeTaskConfigSource)
+			fakeBuildEnv = new(execfakes.FakeBuildEnv)
+
+			params = map[string]string{
+				"VERSION": "from-params",
+				"PLAIN":   "some-value",
+			}
+
+			fakeConfigSource.FetchConfigReturns(atc.TaskConfig{
+				Platform: "some-platform",
+				Params:   params,
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			configSource = BuildEnvConfigSource{
+				ConfigSource: fakeConfigSource,
+				Env:          fakeBuildEnv,
+			}
+
+			fetchedConfig, fetchErr = configSource.FetchConfig(repo)
+		})
+
+		Context("when earlier tasks have exported variables", func() {
+			BeforeEach(func() {
+				fakeBuildEnv.ExportedEnvReturns(map[string]string{
+					"VERSION":  "from-env",
+					"GIT_SHA":  "abc123",
+					"EXPORTED": "some-exported-value",
+				}, nil)
+			})
+
+			It("adds them to the params, without overriding the task's own", func() {
+				Expect(fetchErr).NotTo(HaveOccurred())
+
+				Expect(fetchedConfig.Params).To(Equal(map[string]string{
+					"VERSION":  "from-params",
+					"PLAIN":    "some-value",
+					"GIT_SHA":  "abc123",
+					"EXPORTED": "some-exported-value",
+				}))
+			})
+
+			It("does not modify the underlying config's params", func() {
+				Expect(params).To(HaveLen(2))
+			})
+		})
+
+		Context("when no variables have been exported", func() {
+			BeforeEach(func() {
+				fakeBuildEnv.ExportedEnvReturns(map[string]string{}, nil)
+			})
+
+			It("returns the config as-is", func() {
+				Expect(fetchErr).NotTo(HaveOccurred())
+				Expect(fetchedConfig.Params).To(Equal(params))
+			})
+		})
+
+		Context("when looking up the exported variables fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeBuildEnv.ExportedEnvReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(fetchErr).To(Equal(disaster))
+			})
+		})
+
+		Context("when fetching the config fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeConfigSource.FetchConfigReturns(atc.TaskConfig{}, disaster)
+			})
+
+			It("returns the error without looking up any variables", func() {
+				Expect(fetchErr).To(Equal(disaster))
+				Expect(fakeBuildEnv.ExportedEnvCallCount()).To(BeZero())
+			})
+		})
+	})
 })
@@ -34,3 +34,22 @@ func successResult(result Success) func(dest interface{}) bool {
 type testMetadata []string
 
 func (m testMetadata) Env() []string { return m }
+
+func retryableResult(result Retryable) func(dest interface{}) bool {
+	return func(dest interface{}) bool {
+		defer GinkgoRecover()
+
+		switch x := dest.(type) {
+		case *Success:
+			*x = false
+			return true
+
+		case *Retryable:
+			*x = result
+			return true
+
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// This file was generated by counterfeiter
+package execfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/exec"
+)
+
+type FakeBuildEnv struct {
+	ExportedEnvStub        func() (map[string]string, error)
+	exportedEnvMutex       sync.RWMutex
+	exportedEnvArgsForCall []struct{}
+	exportedEnvReturns     struct {
+		result1 map[string]string
+		result2 error
+	}
+	exportedEnvReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBuildEnv) ExportedEnv() (map[string]string, error) {
+	fake.exportedEnvMutex.Lock()
+	ret, specificReturn := fake.exportedEnvReturnsOnCall[len(fake.exportedEnvArgsForCall)]
+	fake.exportedEnvArgsForCall = append(fake.exportedEnvArgsForCall, struct{}{})
+	fake.recordInvocation("ExportedEnv", []interface{}{})
+	fake.exportedEnvMutex.Unlock()
+	if fake.ExportedEnvStub != nil {
+		return fake.ExportedEnvStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.exportedEnvReturns.result1, fake.exportedEnvReturns.result2
+}
+
+func (fake *FakeBuildEnv) ExportedEnvCallCount() int {
+	fake.exportedEnvMutex.RLock()
+	defer fake.exportedEnvMutex.RUnlock()
+	return len(fake.exportedEnvArgsForCall)
+}
+
+func (fake *FakeBuildEnv) ExportedEnvReturns(result1 map[string]string, result2 error) {
+	fake.ExportedEnvStub = nil
+	fake.exportedEnvReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildEnv) ExportedEnvReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.ExportedEnvStub = nil
+	if fake.exportedEnvReturnsOnCall == nil {
+		fake.exportedEnvReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.exportedEnvReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildEnv) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.exportedEnvMutex.RLock()
+	defer fake.exportedEnvMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBuildEnv) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ exec.BuildEnv = new(FakeBuildEnv)
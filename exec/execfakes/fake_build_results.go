@@ -0,0 +1,90 @@
+// This file was generated by counterfeiter
+package execfakes
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/concourse/atc/exec"
+)
+
+type FakeBuildResults struct {
+	TaskResultsStub        func() (map[string]json.RawMessage, error)
+	taskResultsMutex       sync.RWMutex
+	taskResultsArgsForCall []struct{}
+	taskResultsReturns     struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}
+	taskResultsReturnsOnCall map[int]struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBuildResults) TaskResults() (map[string]json.RawMessage, error) {
+	fake.taskResultsMutex.Lock()
+	ret, specificReturn := fake.taskResultsReturnsOnCall[len(fake.taskResultsArgsForCall)]
+	fake.taskResultsArgsForCall = append(fake.taskResultsArgsForCall, struct{}{})
+	fake.recordInvocation("TaskResults", []interface{}{})
+	fake.taskResultsMutex.Unlock()
+	if fake.TaskResultsStub != nil {
+		return fake.TaskResultsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.taskResultsReturns.result1, fake.taskResultsReturns.result2
+}
+
+func (fake *FakeBuildResults) TaskResultsCallCount() int {
+	fake.taskResultsMutex.RLock()
+	defer fake.taskResultsMutex.RUnlock()
+	return len(fake.taskResultsArgsForCall)
+}
+
+func (fake *FakeBuildResults) TaskResultsReturns(result1 map[string]json.RawMessage, result2 error) {
+	fake.TaskResultsStub = nil
+	fake.taskResultsReturns = struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildResults) TaskResultsReturnsOnCall(i int, result1 map[string]json.RawMessage, result2 error) {
+	fake.TaskResultsStub = nil
+	if fake.taskResultsReturnsOnCall == nil {
+		fake.taskResultsReturnsOnCall = make(map[int]struct {
+			result1 map[string]json.RawMessage
+			result2 error
+		})
+	}
+	fake.taskResultsReturnsOnCall[i] = struct {
+		result1 map[string]json.RawMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBuildResults) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.taskResultsMutex.RLock()
+	defer fake.taskResultsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBuildResults) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ exec.BuildResults = new(FakeBuildResults)
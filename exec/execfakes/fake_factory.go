@@ -79,7 +79,7 @@ type FakeFactory struct {
 	dependentGetReturnsOnCall map[int]struct {
 		result1 exec.StepFactory
 	}
-	TaskStub        func(lager.Logger, int, int, atc.PlanID, worker.ArtifactName, dbng.ContainerMetadata, exec.TaskDelegate, exec.Privileged, atc.Tags, exec.TaskConfigSource, atc.VersionedResourceTypes, map[string]string, map[string]string, string, clock.Clock) exec.StepFactory
+	TaskStub        func(lager.Logger, int, int, atc.PlanID, worker.ArtifactName, dbng.ContainerMetadata, exec.TaskDelegate, exec.Privileged, atc.Tags, exec.TaskConfigSource, atc.VersionedResourceTypes, map[string]string, map[string]string, string, []int, string, string, string, clock.Clock) exec.StepFactory
 	taskMutex       sync.RWMutex
 	taskArgsForCall []struct {
 		arg1  lager.Logger
@@ -96,7 +96,11 @@ type FakeFactory struct {
 		arg12 map[string]string
 		arg13 map[string]string
 		arg14 string
-		arg15 clock.Clock
+		arg15 []int
+		arg16 string
+		arg17 string
+		arg18 string
+		arg19 clock.Clock
 	}
 	taskReturns struct {
 		result1 exec.StepFactory
@@ -285,7 +289,12 @@ func (fake *FakeFactory) DependentGetReturnsOnCall(i int, result1 exec.StepFacto
 	}{result1}
 }
 
-func (fake *FakeFactory) Task(arg1 lager.Logger, arg2 int, arg3 int, arg4 atc.PlanID, arg5 worker.ArtifactName, arg6 dbng.ContainerMetadata, arg7 exec.TaskDelegate, arg8 exec.Privileged, arg9 atc.Tags, arg10 exec.TaskConfigSource, arg11 atc.VersionedResourceTypes, arg12 map[string]string, arg13 map[string]string, arg14 string, arg15 clock.Clock) exec.StepFactory {
+func (fake *FakeFactory) Task(arg1 lager.Logger, arg2 int, arg3 int, arg4 atc.PlanID, arg5 worker.ArtifactName, arg6 dbng.ContainerMetadata, arg7 exec.TaskDelegate, arg8 exec.Privileged, arg9 atc.Tags, arg10 exec.TaskConfigSource, arg11 atc.VersionedResourceTypes, arg12 map[string]string, arg13 map[string]string, arg14 string, arg15 []int, arg16 string, arg17 string, arg18 string, arg19 clock.Clock) exec.StepFactory {
+	var arg15Copy []int
+	if arg15 != nil {
+		arg15Copy = make([]int, len(arg15))
+		copy(arg15Copy, arg15)
+	}
 	fake.taskMutex.Lock()
 	ret, specificReturn := fake.taskReturnsOnCall[len(fake.taskArgsForCall)]
 	fake.taskArgsForCall = append(fake.taskArgsForCall, struct {
@@ -303,12 +312,16 @@ func (fake *FakeFactory) Task(arg1 lager.Logger, arg2 int, arg3 int, arg4 atc.Pl
 		arg12 map[string]string
 		arg13 map[string]string
 		arg14 string
-		arg15 clock.Clock
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15})
-	fake.recordInvocation("Task", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15})
+		arg15 []int
+		arg16 string
+		arg17 string
+		arg18 string
+		arg19 clock.Clock
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15Copy, arg16, arg17, arg18, arg19})
+	fake.recordInvocation("Task", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15Copy, arg16, arg17, arg18, arg19})
 	fake.taskMutex.Unlock()
 	if fake.TaskStub != nil {
-		return fake.TaskStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15)
+		return fake.TaskStub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15, arg16, arg17, arg18, arg19)
 	}
 	if specificReturn {
 		return ret.result1
@@ -322,10 +335,10 @@ func (fake *FakeFactory) TaskCallCount() int {
 	return len(fake.taskArgsForCall)
 }
 
-func (fake *FakeFactory) TaskArgsForCall(i int) (lager.Logger, int, int, atc.PlanID, worker.ArtifactName, dbng.ContainerMetadata, exec.TaskDelegate, exec.Privileged, atc.Tags, exec.TaskConfigSource, atc.VersionedResourceTypes, map[string]string, map[string]string, string, clock.Clock) {
+func (fake *FakeFactory) TaskArgsForCall(i int) (lager.Logger, int, int, atc.PlanID, worker.ArtifactName, dbng.ContainerMetadata, exec.TaskDelegate, exec.Privileged, atc.Tags, exec.TaskConfigSource, atc.VersionedResourceTypes, map[string]string, map[string]string, string, []int, string, string, string, clock.Clock) {
 	fake.taskMutex.RLock()
 	defer fake.taskMutex.RUnlock()
-	return fake.taskArgsForCall[i].arg1, fake.taskArgsForCall[i].arg2, fake.taskArgsForCall[i].arg3, fake.taskArgsForCall[i].arg4, fake.taskArgsForCall[i].arg5, fake.taskArgsForCall[i].arg6, fake.taskArgsForCall[i].arg7, fake.taskArgsForCall[i].arg8, fake.taskArgsForCall[i].arg9, fake.taskArgsForCall[i].arg10, fake.taskArgsForCall[i].arg11, fake.taskArgsForCall[i].arg12, fake.taskArgsForCall[i].arg13, fake.taskArgsForCall[i].arg14, fake.taskArgsForCall[i].arg15
+	return fake.taskArgsForCall[i].arg1, fake.taskArgsForCall[i].arg2, fake.taskArgsForCall[i].arg3, fake.taskArgsForCall[i].arg4, fake.taskArgsForCall[i].arg5, fake.taskArgsForCall[i].arg6, fake.taskArgsForCall[i].arg7, fake.taskArgsForCall[i].arg8, fake.taskArgsForCall[i].arg9, fake.taskArgsForCall[i].arg10, fake.taskArgsForCall[i].arg11, fake.taskArgsForCall[i].arg12, fake.taskArgsForCall[i].arg13, fake.taskArgsForCall[i].arg14, fake.taskArgsForCall[i].arg15, fake.taskArgsForCall[i].arg16, fake.taskArgsForCall[i].arg17, fake.taskArgsForCall[i].arg18, fake.taskArgsForCall[i].arg19
 }
 
 func (fake *FakeFactory) TaskReturns(result1 exec.StepFactory) {
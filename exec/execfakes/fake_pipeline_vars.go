@@ -0,0 +1,99 @@
+// This file was generated by counterfeiter
+package execfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/exec"
+)
+
+type FakePipelineVars struct {
+	GetPipelineVarsStub        func(pipelineID int) (map[string]string, error)
+	getPipelineVarsMutex       sync.RWMutex
+	getPipelineVarsArgsForCall []struct {
+		pipelineID int
+	}
+	getPipelineVarsReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	getPipelineVarsReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePipelineVars) GetPipelineVars(pipelineID int) (map[string]string, error) {
+	fake.getPipelineVarsMutex.Lock()
+	ret, specificReturn := fake.getPipelineVarsReturnsOnCall[len(fake.getPipelineVarsArgsForCall)]
+	fake.getPipelineVarsArgsForCall = append(fake.getPipelineVarsArgsForCall, struct {
+		pipelineID int
+	}{pipelineID})
+	fake.recordInvocation("GetPipelineVars", []interface{}{pipelineID})
+	fake.getPipelineVarsMutex.Unlock()
+	if fake.GetPipelineVarsStub != nil {
+		return fake.GetPipelineVarsStub(pipelineID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getPipelineVarsReturns.result1, fake.getPipelineVarsReturns.result2
+}
+
+func (fake *FakePipelineVars) GetPipelineVarsCallCount() int {
+	fake.getPipelineVarsMutex.RLock()
+	defer fake.getPipelineVarsMutex.RUnlock()
+	return len(fake.getPipelineVarsArgsForCall)
+}
+
+func (fake *FakePipelineVars) GetPipelineVarsArgsForCall(i int) int {
+	fake.getPipelineVarsMutex.RLock()
+	defer fake.getPipelineVarsMutex.RUnlock()
+	return fake.getPipelineVarsArgsForCall[i].pipelineID
+}
+
+func (fake *FakePipelineVars) GetPipelineVarsReturns(result1 map[string]string, result2 error) {
+	fake.GetPipelineVarsStub = nil
+	fake.getPipelineVarsReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineVars) GetPipelineVarsReturnsOnCall(i int, result1 map[string]string, result2 error) {
+	fake.GetPipelineVarsStub = nil
+	if fake.getPipelineVarsReturnsOnCall == nil {
+		fake.getPipelineVarsReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 error
+		})
+	}
+	fake.getPipelineVarsReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePipelineVars) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getPipelineVarsMutex.RLock()
+	defer fake.getPipelineVarsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakePipelineVars) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ exec.PipelineVars = new(FakePipelineVars)
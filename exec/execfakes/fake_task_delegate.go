@@ -2,8 +2,10 @@
 package execfakes
 
 import (
+	"encoding/json"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/exec"
@@ -16,9 +18,15 @@ type FakeTaskDelegate struct {
 	initializingArgsForCall []struct {
 		arg1 atc.TaskConfig
 	}
-	StartedStub         func()
-	startedMutex        sync.RWMutex
-	startedArgsForCall  []struct{}
+	StartedStub        func()
+	startedMutex       sync.RWMutex
+	startedArgsForCall []struct{}
+	StageStub          func(string, time.Time)
+	stageMutex         sync.RWMutex
+	stageArgsForCall   []struct {
+		stage string
+		at    time.Time
+	}
 	FinishedStub        func(exec.ExitStatus)
 	finishedMutex       sync.RWMutex
 	finishedArgsForCall []struct {
@@ -40,6 +48,40 @@ type FakeTaskDelegate struct {
 	imageVersionDeterminedReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ResultDeterminedStub        func(output string, result json.RawMessage) error
+	resultDeterminedMutex       sync.RWMutex
+	resultDeterminedArgsForCall []struct {
+		output string
+		result json.RawMessage
+	}
+	resultDeterminedReturns struct {
+		result1 error
+	}
+	resultDeterminedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EnvExportedStub        func(env map[string]string) error
+	envExportedMutex       sync.RWMutex
+	envExportedArgsForCall []struct {
+		env map[string]string
+	}
+	envExportedReturns struct {
+		result1 error
+	}
+	envExportedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ResourceUsageMeasuredStub        func(exec.ResourceUsage) error
+	resourceUsageMeasuredMutex       sync.RWMutex
+	resourceUsageMeasuredArgsForCall []struct {
+		arg1 exec.ResourceUsage
+	}
+	resourceUsageMeasuredReturns struct {
+		result1 error
+	}
+	resourceUsageMeasuredReturnsOnCall map[int]struct {
+		result1 error
+	}
 	StdoutStub        func() io.Writer
 	stdoutMutex       sync.RWMutex
 	stdoutArgsForCall []struct{}
@@ -58,6 +100,21 @@ type FakeTaskDelegate struct {
 	stderrReturnsOnCall map[int]struct {
 		result1 io.Writer
 	}
+	PinnedImageVersionStub        func(resourceHash string) (atc.Version, bool, error)
+	pinnedImageVersionMutex       sync.RWMutex
+	pinnedImageVersionArgsForCall []struct {
+		resourceHash string
+	}
+	pinnedImageVersionReturns struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}
+	pinnedImageVersionReturnsOnCall map[int]struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -102,6 +159,31 @@ func (fake *FakeTaskDelegate) StartedCallCount() int {
 	return len(fake.startedArgsForCall)
 }
 
+func (fake *FakeTaskDelegate) Stage(stage string, at time.Time) {
+	fake.stageMutex.Lock()
+	fake.stageArgsForCall = append(fake.stageArgsForCall, struct {
+		stage string
+		at    time.Time
+	}{stage, at})
+	fake.recordInvocation("Stage", []interface{}{stage, at})
+	fake.stageMutex.Unlock()
+	if fake.StageStub != nil {
+		fake.StageStub(stage, at)
+	}
+}
+
+func (fake *FakeTaskDelegate) StageCallCount() int {
+	fake.stageMutex.RLock()
+	defer fake.stageMutex.RUnlock()
+	return len(fake.stageArgsForCall)
+}
+
+func (fake *FakeTaskDelegate) StageArgsForCall(i int) (string, time.Time) {
+	fake.stageMutex.RLock()
+	defer fake.stageMutex.RUnlock()
+	return fake.stageArgsForCall[i].stage, fake.stageArgsForCall[i].at
+}
+
 func (fake *FakeTaskDelegate) Finished(arg1 exec.ExitStatus) {
 	fake.finishedMutex.Lock()
 	fake.finishedArgsForCall = append(fake.finishedArgsForCall, struct {
@@ -198,6 +280,151 @@ func (fake *FakeTaskDelegate) ImageVersionDeterminedReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeTaskDelegate) ResultDetermined(output string, result json.RawMessage) error {
+	fake.resultDeterminedMutex.Lock()
+	ret, specificReturn := fake.resultDeterminedReturnsOnCall[len(fake.resultDeterminedArgsForCall)]
+	fake.resultDeterminedArgsForCall = append(fake.resultDeterminedArgsForCall, struct {
+		output string
+		result json.RawMessage
+	}{output, result})
+	fake.recordInvocation("ResultDetermined", []interface{}{output, result})
+	fake.resultDeterminedMutex.Unlock()
+	if fake.ResultDeterminedStub != nil {
+		return fake.ResultDeterminedStub(output, result)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.resultDeterminedReturns.result1
+}
+
+func (fake *FakeTaskDelegate) ResultDeterminedCallCount() int {
+	fake.resultDeterminedMutex.RLock()
+	defer fake.resultDeterminedMutex.RUnlock()
+	return len(fake.resultDeterminedArgsForCall)
+}
+
+func (fake *FakeTaskDelegate) ResultDeterminedArgsForCall(i int) (string, json.RawMessage) {
+	fake.resultDeterminedMutex.RLock()
+	defer fake.resultDeterminedMutex.RUnlock()
+	return fake.resultDeterminedArgsForCall[i].output, fake.resultDeterminedArgsForCall[i].result
+}
+
+func (fake *FakeTaskDelegate) ResultDeterminedReturns(result1 error) {
+	fake.ResultDeterminedStub = nil
+	fake.resultDeterminedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskDelegate) ResultDeterminedReturnsOnCall(i int, result1 error) {
+	fake.ResultDeterminedStub = nil
+	if fake.resultDeterminedReturnsOnCall == nil {
+		fake.resultDeterminedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resultDeterminedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskDelegate) EnvExported(env map[string]string) error {
+	fake.envExportedMutex.Lock()
+	ret, specificReturn := fake.envExportedReturnsOnCall[len(fake.envExportedArgsForCall)]
+	fake.envExportedArgsForCall = append(fake.envExportedArgsForCall, struct {
+		env map[string]string
+	}{env})
+	fake.recordInvocation("EnvExported", []interface{}{env})
+	fake.envExportedMutex.Unlock()
+	if fake.EnvExportedStub != nil {
+		return fake.EnvExportedStub(env)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.envExportedReturns.result1
+}
+
+func (fake *FakeTaskDelegate) EnvExportedCallCount() int {
+	fake.envExportedMutex.RLock()
+	defer fake.envExportedMutex.RUnlock()
+	return len(fake.envExportedArgsForCall)
+}
+
+func (fake *FakeTaskDelegate) EnvExportedArgsForCall(i int) map[string]string {
+	fake.envExportedMutex.RLock()
+	defer fake.envExportedMutex.RUnlock()
+	return fake.envExportedArgsForCall[i].env
+}
+
+func (fake *FakeTaskDelegate) EnvExportedReturns(result1 error) {
+	fake.EnvExportedStub = nil
+	fake.envExportedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskDelegate) EnvExportedReturnsOnCall(i int, result1 error) {
+	fake.EnvExportedStub = nil
+	if fake.envExportedReturnsOnCall == nil {
+		fake.envExportedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.envExportedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasured(arg1 exec.ResourceUsage) error {
+	fake.resourceUsageMeasuredMutex.Lock()
+	ret, specificReturn := fake.resourceUsageMeasuredReturnsOnCall[len(fake.resourceUsageMeasuredArgsForCall)]
+	fake.resourceUsageMeasuredArgsForCall = append(fake.resourceUsageMeasuredArgsForCall, struct {
+		arg1 exec.ResourceUsage
+	}{arg1})
+	fake.recordInvocation("ResourceUsageMeasured", []interface{}{arg1})
+	fake.resourceUsageMeasuredMutex.Unlock()
+	if fake.ResourceUsageMeasuredStub != nil {
+		return fake.ResourceUsageMeasuredStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.resourceUsageMeasuredReturns.result1
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasuredCallCount() int {
+	fake.resourceUsageMeasuredMutex.RLock()
+	defer fake.resourceUsageMeasuredMutex.RUnlock()
+	return len(fake.resourceUsageMeasuredArgsForCall)
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasuredArgsForCall(i int) exec.ResourceUsage {
+	fake.resourceUsageMeasuredMutex.RLock()
+	defer fake.resourceUsageMeasuredMutex.RUnlock()
+	return fake.resourceUsageMeasuredArgsForCall[i].arg1
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasuredReturns(result1 error) {
+	fake.ResourceUsageMeasuredStub = nil
+	fake.resourceUsageMeasuredReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaskDelegate) ResourceUsageMeasuredReturnsOnCall(i int, result1 error) {
+	fake.ResourceUsageMeasuredStub = nil
+	if fake.resourceUsageMeasuredReturnsOnCall == nil {
+		fake.resourceUsageMeasuredReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resourceUsageMeasuredReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeTaskDelegate) Stdout() io.Writer {
 	fake.stdoutMutex.Lock()
 	ret, specificReturn := fake.stdoutReturnsOnCall[len(fake.stdoutArgsForCall)]
@@ -278,6 +505,60 @@ func (fake *FakeTaskDelegate) StderrReturnsOnCall(i int, result1 io.Writer) {
 	}{result1}
 }
 
+func (fake *FakeTaskDelegate) PinnedImageVersion(resourceHash string) (atc.Version, bool, error) {
+	fake.pinnedImageVersionMutex.Lock()
+	ret, specificReturn := fake.pinnedImageVersionReturnsOnCall[len(fake.pinnedImageVersionArgsForCall)]
+	fake.pinnedImageVersionArgsForCall = append(fake.pinnedImageVersionArgsForCall, struct {
+		resourceHash string
+	}{resourceHash})
+	fake.recordInvocation("PinnedImageVersion", []interface{}{resourceHash})
+	fake.pinnedImageVersionMutex.Unlock()
+	if fake.PinnedImageVersionStub != nil {
+		return fake.PinnedImageVersionStub(resourceHash)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.pinnedImageVersionReturns.result1, fake.pinnedImageVersionReturns.result2, fake.pinnedImageVersionReturns.result3
+}
+
+func (fake *FakeTaskDelegate) PinnedImageVersionCallCount() int {
+	fake.pinnedImageVersionMutex.RLock()
+	defer fake.pinnedImageVersionMutex.RUnlock()
+	return len(fake.pinnedImageVersionArgsForCall)
+}
+
+func (fake *FakeTaskDelegate) PinnedImageVersionArgsForCall(i int) string {
+	fake.pinnedImageVersionMutex.RLock()
+	defer fake.pinnedImageVersionMutex.RUnlock()
+	return fake.pinnedImageVersionArgsForCall[i].resourceHash
+}
+
+func (fake *FakeTaskDelegate) PinnedImageVersionReturns(result1 atc.Version, result2 bool, result3 error) {
+	fake.PinnedImageVersionStub = nil
+	fake.pinnedImageVersionReturns = struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeTaskDelegate) PinnedImageVersionReturnsOnCall(i int, result1 atc.Version, result2 bool, result3 error) {
+	fake.PinnedImageVersionStub = nil
+	if fake.pinnedImageVersionReturnsOnCall == nil {
+		fake.pinnedImageVersionReturnsOnCall = make(map[int]struct {
+			result1 atc.Version
+			result2 bool
+			result3 error
+		})
+	}
+	fake.pinnedImageVersionReturnsOnCall[i] = struct {
+		result1 atc.Version
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeTaskDelegate) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -285,16 +566,26 @@ func (fake *FakeTaskDelegate) Invocations() map[string][][]interface{} {
 	defer fake.initializingMutex.RUnlock()
 	fake.startedMutex.RLock()
 	defer fake.startedMutex.RUnlock()
+	fake.stageMutex.RLock()
+	defer fake.stageMutex.RUnlock()
 	fake.finishedMutex.RLock()
 	defer fake.finishedMutex.RUnlock()
 	fake.failedMutex.RLock()
 	defer fake.failedMutex.RUnlock()
 	fake.imageVersionDeterminedMutex.RLock()
 	defer fake.imageVersionDeterminedMutex.RUnlock()
+	fake.resultDeterminedMutex.RLock()
+	defer fake.resultDeterminedMutex.RUnlock()
+	fake.envExportedMutex.RLock()
+	defer fake.envExportedMutex.RUnlock()
+	fake.resourceUsageMeasuredMutex.RLock()
+	defer fake.resourceUsageMeasuredMutex.RUnlock()
 	fake.stdoutMutex.RLock()
 	defer fake.stdoutMutex.RUnlock()
 	fake.stderrMutex.RLock()
 	defer fake.stderrMutex.RUnlock()
+	fake.pinnedImageVersionMutex.RLock()
+	defer fake.pinnedImageVersionMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -1,7 +1,9 @@
 package exec
 
 import (
+	"encoding/json"
 	"io"
+	"time"
 
 	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/lager"
@@ -79,6 +81,10 @@ type Factory interface {
 		map[string]string,
 		map[string]string,
 		string,
+		[]int, // retryableExitCodes
+		string, // scriptTimeout
+		string, // stopTimeout
+		string, // dir
 		clock.Clock,
 	) StepFactory
 }
@@ -96,11 +102,16 @@ type StepMetadata interface {
 type TaskDelegate interface {
 	Initializing(atc.TaskConfig)
 	Started()
+	Stage(stage string, at time.Time)
 
 	Finished(ExitStatus)
 	Failed(error)
 
 	ImageVersionDetermined(worker.ResourceCacheIdentifier) error
+	PinnedImageVersion(resourceHash string) (atc.Version, bool, error)
+	ResultDetermined(output string, result json.RawMessage) error
+	EnvExported(env map[string]string) error
+	ResourceUsageMeasured(ResourceUsage) error
 
 	Stdout() io.Writer
 	Stderr() io.Writer
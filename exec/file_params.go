@@ -0,0 +1,66 @@
+package exec
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/concourse/atc/worker"
+)
+
+// fileParamRegexp matches param values of the form ((file:some-input/path)),
+// whose value is read from the file in the task's container once its inputs
+// have been streamed.
+var fileParamRegexp = regexp.MustCompile(`^\(\(file:([^()]+)\)\)$`)
+
+// FileParamError is returned when the file referenced by a param could not be
+// read from the task's container.
+type FileParamError struct {
+	Param string
+	Path  string
+	Err   error
+}
+
+// Error prints a human-friendly message naming the param and the file.
+func (err FileParamError) Error() string {
+	return fmt.Sprintf("failed to read param '%s' from file '%s': %s", err.Param, err.Path, err.Err)
+}
+
+func fileParamPath(value string) (string, bool) {
+	match := fileParamRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(match[1]), true
+}
+
+// envForFileParams reads the files referenced by params from the container,
+// relative to the task's working directory, and returns them as KEY=value
+// strings with their contents trimmed.
+func (step *TaskStep) envForFileParams(params map[string]string, container worker.Container) ([]string, error) {
+	var names []string
+	for name, value := range params {
+		if _, ok := fileParamPath(value); ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var env []string
+	for _, name := range names {
+		filePath, _ := fileParamPath(params[name])
+
+		contents, err := readContainerFile(container, path.Join(step.artifactsRoot, filePath))
+		if err != nil {
+			return nil, FileParamError{Param: name, Path: filePath, Err: err}
+		}
+
+		env = append(env, name+"="+strings.TrimSpace(string(contents)))
+	}
+
+	return env, nil
+}
@@ -19,6 +19,7 @@ type gardenFactory struct {
 	resourceFetcher        resource.Fetcher
 	resourceFactory        resource.ResourceFactory
 	dbResourceCacheFactory dbng.ResourceCacheFactory
+	dedupeTaskOutputs      bool
 }
 
 func NewGardenFactory(
@@ -26,12 +27,14 @@ func NewGardenFactory(
 	resourceFetcher resource.Fetcher,
 	resourceFactory resource.ResourceFactory,
 	dbResourceCacheFactory dbng.ResourceCacheFactory,
+	dedupeTaskOutputs bool,
 ) Factory {
 	return &gardenFactory{
 		workerClient:           workerClient,
 		resourceFetcher:        resourceFetcher,
 		resourceFactory:        resourceFactory,
 		dbResourceCacheFactory: dbResourceCacheFactory,
+		dedupeTaskOutputs:      dedupeTaskOutputs,
 	}
 }
 
@@ -158,6 +161,10 @@ func (factory *gardenFactory) Task(
 	inputMapping map[string]string,
 	outputMapping map[string]string,
 	imageArtifactName string,
+	retryableExitCodes []int,
+	scriptTimeout string,
+	stopTimeout string,
+	dir string,
 	clock clock.Clock,
 ) StepFactory {
 	workingDirectory := factory.taskWorkingDirectory(sourceName)
@@ -178,6 +185,11 @@ func (factory *gardenFactory) Task(
 		inputMapping,
 		outputMapping,
 		imageArtifactName,
+		retryableExitCodes,
+		scriptTimeout,
+		stopTimeout,
+		dir,
+		factory.dedupeTaskOutputs,
 		clock,
 	)
 }
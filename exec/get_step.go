@@ -177,9 +177,18 @@ func (step *GetStep) VolumeOn(worker worker.Worker) (worker.Volume, bool, error)
 	return step.resourceInstance.FindInitializedOn(step.logger.Session("volume-on"), worker)
 }
 
+// CreateCacheOn creates a volume for the cache of the GetStep's resource and
+// version on the given worker.
+func (step *GetStep) CreateCacheOn(logger lager.Logger, w worker.Worker) (worker.Volume, error) {
+	return step.resourceInstance.CreateOn(logger, w, worker.WorkerSpec{
+		Tags:   step.tags,
+		TeamID: step.teamID,
+	})
+}
+
 // StreamTo streams the resource's data to the destination.
 func (step *GetStep) StreamTo(destination worker.ArtifactDestination) error {
-	out, err := step.fetchSource.VersionedSource().StreamOut(".")
+	out, err := step.StreamOut()
 	if err != nil {
 		return err
 	}
@@ -189,6 +198,11 @@ func (step *GetStep) StreamTo(destination worker.ArtifactDestination) error {
 	return destination.StreamIn(".", out)
 }
 
+// StreamOut opens a fresh stream of the resource's data.
+func (step *GetStep) StreamOut() (io.ReadCloser, error) {
+	return step.fetchSource.VersionedSource().StreamOut(".")
+}
+
 // StreamFile streams a single file out of the resource.
 func (step *GetStep) StreamFile(path string) (io.ReadCloser, error) {
 	out, err := step.fetchSource.VersionedSource().StreamOut(path)
@@ -115,7 +115,7 @@ var _ = Describe("Get", func() {
 
 		fakeDBResourceCacheFactory = new(dbngfakes.FakeResourceCacheFactory)
 
-		factory = NewGardenFactory(fakeWorkerClient, fakeResourceFetcher, fakeResourceFactory, fakeDBResourceCacheFactory)
+		factory = NewGardenFactory(fakeWorkerClient, fakeResourceFetcher, fakeResourceFactory, fakeDBResourceCacheFactory, false)
 	})
 
 	JustBeforeEach(func() {
@@ -0,0 +1,130 @@
+package exec
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/worker"
+)
+
+// registerGlobOutput registers each directory in the output's volume that
+// matches the last element of its path as its own artifact, named after the
+// output and the directory.
+func (step *TaskStep) registerGlobOutput(outputName string, output atc.TaskOutputConfig, volume worker.Volume) error {
+	outputPath := output.Path
+	if outputPath == "" {
+		outputPath = output.Name
+	}
+
+	matches, err := matchingDirectories(volume, path.Base(outputPath))
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		return NoGlobMatchesError{Output: output.Name, Path: outputPath}
+	}
+
+	for _, match := range matches {
+		name := worker.ArtifactName(path.Join(outputName, match))
+
+		step.logger.Debug("registering-glob-match", lager.Data{"output": outputName, "match": match})
+
+		step.repo.RegisterSource(name, &volumeSubdirectorySource{
+			volume:       volume,
+			subdirectory: match,
+		})
+	}
+
+	return nil
+}
+
+// matchingDirectories lists the top-level directories in the volume whose
+// names match the pattern.
+func matchingDirectories(volume worker.Volume, pattern string) ([]string, error) {
+	out, err := volume.StreamOut(".")
+	if err != nil {
+		return nil, err
+	}
+
+	defer out.Close()
+
+	tarReader := tar.NewReader(out)
+
+	var matches []string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(header.Name)
+		if header.Typeflag != tar.TypeDir || name == "." || strings.Contains(name, "/") {
+			continue
+		}
+
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			matches = append(matches, name)
+		}
+	}
+
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
+// volumeSubdirectorySource is a directory within a volume. The directory
+// can't be mounted on its own, so it is always streamed.
+type volumeSubdirectorySource struct {
+	volume       worker.Volume
+	subdirectory string
+}
+
+func (src *volumeSubdirectorySource) StreamTo(destination worker.ArtifactDestination) error {
+	out, err := src.volume.StreamOut(src.subdirectory)
+	if err != nil {
+		return err
+	}
+
+	defer out.Close()
+
+	return destination.StreamIn(".", out)
+}
+
+func (src *volumeSubdirectorySource) StreamFile(filename string) (io.ReadCloser, error) {
+	out, err := src.volume.StreamOut(path.Join(src.subdirectory, filename))
+	if err != nil {
+		return nil, err
+	}
+
+	tarReader := tar.NewReader(out)
+
+	_, err = tarReader.Next()
+	if err != nil {
+		out.Close()
+		return nil, FileNotFoundError{Path: filename}
+	}
+
+	return fileReadCloser{
+		Reader: tarReader,
+		Closer: out,
+	}, nil
+}
+
+func (src *volumeSubdirectorySource) VolumeOn(w worker.Worker) (worker.Volume, bool, error) {
+	return nil, false, nil
+}
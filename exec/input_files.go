@@ -0,0 +1,98 @@
+package exec
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/concourse/atc/worker"
+)
+
+// fileFilteringSource wraps a task input's source so that, when the input has
+// to be streamed to the task's worker, only the files the task declared for it
+// are transferred. Volumes found on the worker are used as-is.
+type fileFilteringSource struct {
+	worker.ArtifactSource
+
+	files []string
+}
+
+func (src fileFilteringSource) StreamTo(destination worker.ArtifactDestination) error {
+	return src.ArtifactSource.StreamTo(fileFilteringDestination{
+		destination: destination,
+		files:       src.files,
+	})
+}
+
+type fileFilteringDestination struct {
+	destination worker.ArtifactDestination
+	files       []string
+}
+
+func (dest fileFilteringDestination) StreamIn(dst string, tarStream io.Reader) error {
+	filtered, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(filterTar(tarStream, writer, dst, dest.files))
+	}()
+
+	err := dest.destination.StreamIn(dst, filtered)
+
+	// unblock the filter if the destination stopped reading early
+	filtered.Close()
+
+	return err
+}
+
+// filterTar copies the entries of the tar stream that are allowed by files,
+// relative to dst, to out. Directories leading up to an allowed file are kept
+// so that their modes are preserved.
+func filterTar(in io.Reader, out io.Writer, dst string, files []string) error {
+	tarReader := tar.NewReader(in)
+	tarWriter := tar.NewWriter(out)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !fileAllowed(path.Join(dst, header.Name), files) {
+			continue
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(tarWriter, tarReader)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+func fileAllowed(name string, files []string) bool {
+	name = path.Clean(name)
+	if name == "." {
+		return true
+	}
+
+	for _, file := range files {
+		file = path.Clean(file)
+
+		if name == file || strings.HasPrefix(name, file+"/") || strings.HasPrefix(file, name+"/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package exec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/worker"
+)
+
+// outputReferenceSource is registered in place of a task output whose
+// contents are identical to an output registered earlier in the build. It
+// resolves to the earlier output, so that only one copy of the contents is
+// streamed and reused by later steps.
+type outputReferenceSource struct {
+	name   worker.ArtifactName
+	source *volumeSource
+}
+
+func (src outputReferenceSource) StreamTo(destination worker.ArtifactDestination) error {
+	return src.source.StreamTo(destination)
+}
+
+func (src outputReferenceSource) StreamFile(filename string) (io.ReadCloser, error) {
+	return src.source.StreamFile(filename)
+}
+
+func (src outputReferenceSource) VolumeOn(w worker.Worker) (worker.Volume, bool, error) {
+	return src.source.VolumeOn(w)
+}
+
+// outputHashScript prints a digest of the files under the directory given as
+// its argument. The digest covers each file's path, type, mode, link target
+// and contents, but not its ownership or modification time. It fails rather
+// than printing a digest if the image lacks any of the tools it needs.
+const outputHashScript = `set -e
+for tool in find stat sha256sum sort; do command -v "$tool" >/dev/null; done
+cd "$1"
+listing=$(find . -exec stat -c '%n %f' {} + && find . -type l -exec stat -c '%N' {} + && find . -type f -exec sha256sum {} +)
+printf '%s\n' "$listing" | LC_ALL=C sort | sha256sum`
+
+// hashOutput computes the digest of an output by running outputHashScript in
+// the task's container, so that the output's contents never leave the worker.
+func (step *TaskStep) hashOutput(container worker.Container, outputPath string) (string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	process, err := container.Run(garden.ProcessSpec{
+		Path: "sh",
+		Args: []string{"-c", outputHashScript, "sh", outputPath},
+		User: "root",
+	}, garden.ProcessIO{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	status, err := process.Wait()
+	if err != nil {
+		return "", err
+	}
+
+	if status != 0 {
+		return "", fmt.Errorf("hashing output exited with status %d: %s", status, strings.TrimSpace(stderr.String()))
+	}
+
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("malformed output digest: %q", stdout.String())
+	}
+
+	return fields[0], nil
+}
+
+// dedupeOutput returns a reference to one of the given earlier outputs if it
+// is on the same worker and its contents are identical to the output's, or
+// the output itself otherwise. Outputs that could not be hashed are never
+// deduplicated.
+func (step *TaskStep) dedupeOutput(
+	name worker.ArtifactName,
+	output *volumeSource,
+	earlier map[worker.ArtifactName]worker.ArtifactSource,
+) worker.ArtifactSource {
+	if output.digest == "" {
+		return output
+	}
+
+	logger := step.logger.Session("dedupe-output", lager.Data{"output": name})
+
+	for earlierName, earlierSource := range earlier {
+		earlierOutput, ok := earlierSource.(*volumeSource)
+		if !ok || earlierName == name || earlierOutput.digest == "" {
+			continue
+		}
+
+		if earlierOutput.workerName != output.workerName || earlierOutput.volume.Handle() == output.volume.Handle() {
+			continue
+		}
+
+		if earlierOutput.digest == output.digest {
+			logger.Debug("deduplicated", lager.Data{"earlier-output": earlierName})
+			return outputReferenceSource{name: earlierName, source: earlierOutput}
+		}
+	}
+
+	return output
+}
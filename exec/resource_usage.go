@@ -0,0 +1,67 @@
+package exec
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// How often a running task's container is sampled for its memory usage.
+const resourceUsageSampleInterval = 10 * time.Second
+
+// ResourceUsage is the compute consumed by a step's container. If Measured is
+// false, the container's worker could not report metrics and the usage is
+// zero.
+type ResourceUsage struct {
+	CPUSeconds        float64
+	MemoryByteSeconds float64
+	Measured          bool
+}
+
+// resourceUsageMeter accumulates a container's usage from periodic samples of
+// its metrics. CPU usage is reported cumulatively by the container, so only
+// the latest sample matters; memory usage is a point-in-time value, and is
+// integrated over the time since the previous sample.
+type resourceUsageMeter struct {
+	container garden.Container
+
+	lastSampled time.Time
+	failed      bool
+
+	usage ResourceUsage
+}
+
+func newResourceUsageMeter(container garden.Container, now time.Time) *resourceUsageMeter {
+	return &resourceUsageMeter{
+		container:   container,
+		lastSampled: now,
+	}
+}
+
+func (meter *resourceUsageMeter) sample(now time.Time) {
+	if meter.failed {
+		return
+	}
+
+	metrics, err := meter.container.Metrics()
+	if err != nil {
+		meter.failed = true
+		return
+	}
+
+	elapsed := now.Sub(meter.lastSampled).Seconds()
+	meter.lastSampled = now
+
+	meter.usage.CPUSeconds = float64(metrics.CPUStat.Usage) / float64(time.Second)
+	meter.usage.MemoryByteSeconds += float64(metrics.MemoryStat.TotalUsageTowardLimit) * elapsed
+}
+
+func (meter *resourceUsageMeter) total() ResourceUsage {
+	if meter.failed {
+		return ResourceUsage{}
+	}
+
+	usage := meter.usage
+	usage.Measured = true
+	return usage
+}
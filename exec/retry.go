@@ -28,8 +28,9 @@ type RetryStep struct {
 	LastAttempt Step
 }
 
-// Run iterates through each step, stopping once a step succeeds. If all steps
-// fail, the RetryStep will fail.
+// Run iterates through each step, stopping once a step succeeds, or once a
+// step fails in a way it indicates is not Retryable. If all steps fail, the
+// RetryStep will fail.
 func (step *RetryStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
 	close(ready)
 
@@ -51,6 +52,11 @@ func (step *RetryStep) Run(signals <-chan os.Signal, ready chan<- struct{}) erro
 		if attempt.Result(&succeeded) && bool(succeeded) {
 			break
 		}
+
+		var retryable Retryable
+		if attempt.Result(&retryable) && !bool(retryable) {
+			break
+		}
 	}
 
 	return attemptErr
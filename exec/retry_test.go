@@ -125,6 +125,65 @@ var _ = Describe("Retry Step", func() {
 		})
 	})
 
+	Context("when attempt 1 fails in a way that is not retryable", func() {
+		BeforeEach(func() {
+			attempt1Step.ResultStub = retryableResult(false)
+		})
+
+		Describe("Run", func() {
+			var process ifrit.Process
+
+			JustBeforeEach(func() {
+				process = ifrit.Invoke(step)
+			})
+
+			It("returns nil having only run the first attempt", func() {
+				Expect(<-process.Wait()).ToNot(HaveOccurred())
+
+				Expect(attempt1Step.RunCallCount()).To(Equal(1))
+				Expect(attempt2Step.RunCallCount()).To(Equal(0))
+				Expect(attempt3Step.RunCallCount()).To(Equal(0))
+			})
+
+			Describe("Result", func() {
+				It("delegates to attempt 1", func() {
+					<-process.Wait()
+
+					attempt1Step.ResultReturns(true)
+
+					var foo interface{}
+					destination := &foo
+					Expect(step.Result(destination)).To(BeTrue())
+
+					Expect(attempt1Step.ResultArgsForCall(attempt1Step.ResultCallCount() - 1)).To(Equal(destination))
+				})
+			})
+		})
+	})
+
+	Context("when attempt 1 fails in a way that is retryable, and attempt 2 succeeds", func() {
+		BeforeEach(func() {
+			attempt1Step.ResultStub = retryableResult(true)
+			attempt2Step.ResultStub = successResult(true)
+		})
+
+		Describe("Run", func() {
+			var process ifrit.Process
+
+			JustBeforeEach(func() {
+				process = ifrit.Invoke(step)
+			})
+
+			It("returns nil having only run the first and second attempts", func() {
+				Expect(<-process.Wait()).ToNot(HaveOccurred())
+
+				Expect(attempt1Step.RunCallCount()).To(Equal(1))
+				Expect(attempt2Step.RunCallCount()).To(Equal(1))
+				Expect(attempt3Step.RunCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("when attempt 1 errors, and attempt 2 succeeds", func() {
 		BeforeEach(func() {
 			attempt1Step.RunReturns(errors.New("nope"))
@@ -0,0 +1,53 @@
+package exec
+
+import (
+	"os"
+
+	"github.com/concourse/atc/worker"
+)
+
+// StagedStep runs a step against a staged ArtifactRepository, only committing
+// the artifacts it produced to the build's repository if it succeeds.
+type StagedStep struct {
+	step    StepFactory
+	runStep Step
+	repo    *worker.ArtifactRepository
+}
+
+// Staged constructs a StagedStep factory.
+func Staged(step StepFactory) StagedStep {
+	return StagedStep{
+		step: step,
+	}
+}
+
+// Using constructs a *StagedStep, staging a new ArtifactRepository on top of
+// the given one for the nested step to register its artifacts with.
+func (ss StagedStep) Using(prev Step, repo *worker.ArtifactRepository) Step {
+	ss.repo = repo.Stage()
+	ss.runStep = ss.step.Using(prev, ss.repo)
+	return &ss
+}
+
+// Run runs the nested step. If it returns no error and its Result indicates
+// Success as true, the staged artifacts are committed; otherwise they are
+// rolled back.
+//
+// The nested step's error is returned.
+func (ss *StagedStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	err := ss.runStep.Run(signals, ready)
+
+	var succeeded Success
+	if err == nil && ss.runStep.Result(&succeeded) && bool(succeeded) {
+		ss.repo.Commit()
+	} else {
+		ss.repo.Rollback()
+	}
+
+	return err
+}
+
+// Result delegates to the nested step.
+func (ss *StagedStep) Result(x interface{}) bool {
+	return ss.runStep.Result(x)
+}
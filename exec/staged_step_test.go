@@ -0,0 +1,121 @@
+package exec_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/concourse/atc/exec"
+	"github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+	"github.com/tedsuo/ifrit"
+
+	"github.com/concourse/atc/exec/execfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Staged Step", func() {
+	var (
+		fakeStepFactory *execfakes.FakeStepFactory
+		runStep         *execfakes.FakeStep
+
+		repo         *worker.ArtifactRepository
+		stagedSource *workerfakes.FakeArtifactSource
+
+		staged StepFactory
+		step   Step
+
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeStepFactory = new(execfakes.FakeStepFactory)
+		runStep = new(execfakes.FakeStep)
+		fakeStepFactory.UsingReturns(runStep)
+
+		repo = worker.NewArtifactRepository()
+		stagedSource = new(workerfakes.FakeArtifactSource)
+
+		runStep.RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			close(ready)
+			_, stagedRepo := fakeStepFactory.UsingArgsForCall(0)
+			stagedRepo.RegisterSource("some-output", stagedSource)
+			return nil
+		}
+
+		staged = Staged(fakeStepFactory)
+		step = staged.Using(nil, repo)
+	})
+
+	JustBeforeEach(func() {
+		process = ifrit.Invoke(step)
+	})
+
+	It("gives the nested step a staged repository", func() {
+		Expect(fakeStepFactory.UsingCallCount()).To(Equal(1))
+		_, stagedRepo := fakeStepFactory.UsingArgsForCall(0)
+		Expect(stagedRepo).ToNot(BeIdenticalTo(repo))
+	})
+
+	Context("when the nested step succeeds", func() {
+		BeforeEach(func() {
+			runStep.ResultStub = successResult(true)
+		})
+
+		It("commits the staged artifacts", func() {
+			Expect(<-process.Wait()).ToNot(HaveOccurred())
+
+			source, found := repo.SourceFor("some-output")
+			Expect(found).To(BeTrue())
+			Expect(source).To(Equal(stagedSource))
+		})
+	})
+
+	Context("when the nested step fails", func() {
+		BeforeEach(func() {
+			runStep.ResultStub = successResult(false)
+		})
+
+		It("rolls back the staged artifacts", func() {
+			Expect(<-process.Wait()).ToNot(HaveOccurred())
+
+			_, found := repo.SourceFor("some-output")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("when the nested step errors", func() {
+		disaster := errors.New("nope")
+
+		BeforeEach(func() {
+			runStep.ResultStub = successResult(true)
+			runStep.RunStub = func(signals <-chan os.Signal, ready chan<- struct{}) error {
+				close(ready)
+				_, stagedRepo := fakeStepFactory.UsingArgsForCall(0)
+				stagedRepo.RegisterSource("some-output", stagedSource)
+				return disaster
+			}
+		})
+
+		It("rolls back the staged artifacts and returns the error", func() {
+			Expect(<-process.Wait()).To(Equal(disaster))
+
+			_, found := repo.SourceFor("some-output")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("Result", func() {
+		BeforeEach(func() {
+			runStep.ResultReturns(true)
+		})
+
+		It("delegates to the nested step", func() {
+			<-process.Wait()
+
+			x := new(ExitStatus)
+			Expect(step.Result(x)).To(BeTrue())
+			Expect(runStep.ResultArgsForCall(runStep.ResultCallCount() - 1)).To(Equal(x))
+		})
+	})
+})
@@ -67,6 +67,10 @@ type Success bool
 // Typically if the ExitStatus result is 0, the Success result is true.
 type ExitStatus int
 
+// Retryable indicates whether a failed step may succeed if it is run again.
+// Steps only provide it if they can tell, e.g. from their exit status.
+type Retryable bool
+
 // VersionInfo is the version and metadata of a resource that was fetched or
 // produced. It is used by Put, Get, and DependentGet.
 type VersionInfo struct {
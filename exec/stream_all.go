@@ -0,0 +1,55 @@
+package exec
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/worker"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ArtifactTransfer pairs an artifact with the destination it should be
+// streamed to, e.g. a registered output and the volume of a step consuming it.
+type ArtifactTransfer struct {
+	Source      worker.ArtifactSource
+	Destination worker.ArtifactDestination
+}
+
+// StreamAll streams each transfer's source to its destination concurrently,
+// with at most maxInFlight streams running at once. A maxInFlight of 0 or
+// less streams everything at once.
+//
+// Every transfer is attempted even if some fail. Their errors (if any) are
+// returned together as a single error, in the order of the transfers.
+func StreamAll(transfers []ArtifactTransfer, maxInFlight int) error {
+	if maxInFlight <= 0 || maxInFlight > len(transfers) {
+		maxInFlight = len(transfers)
+	}
+
+	slots := make(chan struct{}, maxInFlight)
+	errs := make([]error, len(transfers))
+
+	wg := new(sync.WaitGroup)
+
+	for i, transfer := range transfers {
+		slots <- struct{}{}
+
+		wg.Add(1)
+		go func(i int, transfer ArtifactTransfer) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			errs[i] = transfer.Source.StreamTo(transfer.Destination)
+		}(i, transfer)
+	}
+
+	wg.Wait()
+
+	var result error
+	for _, err := range errs {
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	return result
+}
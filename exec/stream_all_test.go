@@ -0,0 +1,148 @@
+package exec_test
+
+import (
+	"errors"
+	"sync"
+
+	. "github.com/concourse/atc/exec"
+	"github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+	"github.com/hashicorp/go-multierror"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StreamAll", func() {
+	var (
+		transfers   []ArtifactTransfer
+		sources     []*workerfakes.FakeArtifactSource
+		maxInFlight int
+
+		lock      *sync.Mutex
+		inFlight  int
+		maxSeen   int
+		release   chan struct{}
+		streamErr chan error
+	)
+
+	BeforeEach(func() {
+		lock = new(sync.Mutex)
+		inFlight = 0
+		maxSeen = 0
+		release = make(chan struct{})
+		maxInFlight = 2
+
+		transfers = nil
+		sources = nil
+
+		for i := 0; i < 5; i++ {
+			source := new(workerfakes.FakeArtifactSource)
+			source.StreamToStub = func(worker.ArtifactDestination) error {
+				lock.Lock()
+				inFlight++
+				if inFlight > maxSeen {
+					maxSeen = inFlight
+				}
+				lock.Unlock()
+
+				<-release
+
+				lock.Lock()
+				inFlight--
+				lock.Unlock()
+
+				return nil
+			}
+
+			sources = append(sources, source)
+			transfers = append(transfers, ArtifactTransfer{
+				Source:      source,
+				Destination: new(workerfakes.FakeArtifactDestination),
+			})
+		}
+	})
+
+	JustBeforeEach(func() {
+		streamErr = make(chan error, 1)
+
+		go func() {
+			streamErr <- StreamAll(transfers, maxInFlight)
+		}()
+	})
+
+	currentlyInFlight := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return inFlight
+	}
+
+	It("streams concurrently up to the limit", func() {
+		Eventually(currentlyInFlight).Should(Equal(2))
+		Consistently(currentlyInFlight).Should(Equal(2))
+
+		close(release)
+
+		Eventually(streamErr).Should(Receive(BeNil()))
+
+		lock.Lock()
+		Expect(maxSeen).To(Equal(2))
+		lock.Unlock()
+
+		for i, source := range sources {
+			Expect(source.StreamToCallCount()).To(Equal(1))
+			Expect(source.StreamToArgsForCall(0)).To(Equal(transfers[i].Destination))
+		}
+	})
+
+	Context("when there is no limit", func() {
+		BeforeEach(func() {
+			maxInFlight = 0
+		})
+
+		It("streams everything at once", func() {
+			Eventually(currentlyInFlight).Should(Equal(5))
+
+			close(release)
+
+			Eventually(streamErr).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("when some of the streams fail", func() {
+		disaster := errors.New("nope")
+		otherDisaster := errors.New("also nope")
+
+		BeforeEach(func() {
+			close(release)
+
+			sources[1].StreamToStub = nil
+			sources[1].StreamToReturns(disaster)
+
+			sources[3].StreamToStub = nil
+			sources[3].StreamToReturns(otherDisaster)
+		})
+
+		It("attempts every stream and returns all of the errors in order", func() {
+			var err error
+			Eventually(streamErr).Should(Receive(&err))
+
+			Expect(err).To(BeAssignableToTypeOf(&multierror.Error{}))
+			Expect(err.(*multierror.Error).Errors).To(Equal([]error{disaster, otherDisaster}))
+
+			for _, source := range sources {
+				Expect(source.StreamToCallCount()).To(Equal(1))
+			}
+		})
+	})
+
+	Context("when there is nothing to stream", func() {
+		BeforeEach(func() {
+			transfers = nil
+		})
+
+		It("succeeds", func() {
+			Eventually(streamErr).Should(Receive(BeNil()))
+		})
+	})
+})
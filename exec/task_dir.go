@@ -0,0 +1,79 @@
+package exec
+
+import (
+	"fmt"
+	"path"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc"
+	"github.com/concourse/atc/worker"
+)
+
+// TaskDirNotFoundError is returned when a task is configured to run from a
+// directory that does not exist in its container.
+type TaskDirNotFoundError struct {
+	Dir string
+}
+
+// Error prints a human-friendly message naming the directory.
+func (err TaskDirNotFoundError) Error() string {
+	return fmt.Sprintf("task working directory does not exist in the container: %s", err.Dir)
+}
+
+// processDir determines the directory the task's script runs from. Without a
+// dir configured on the step, it is the task's run.dir within the artifacts
+// root. Otherwise the configured dir is used, relative to the artifacts root
+// unless it's absolute. It must either be where an input or output is mounted
+// or already exist in the container.
+func (step *TaskStep) processDir(config atc.TaskConfig, container worker.Container) (string, error) {
+	if step.dir == "" {
+		return path.Join(step.artifactsRoot, config.Run.Dir), nil
+	}
+
+	dir := step.dir
+	if !path.IsAbs(dir) {
+		dir = path.Join(step.artifactsRoot, dir)
+	}
+
+	dir = path.Clean(dir)
+
+	for _, mountPath := range step.mountPaths(config) {
+		if dir == path.Clean(mountPath) {
+			return dir, nil
+		}
+	}
+
+	out, err := container.StreamOut(garden.StreamOutSpec{
+		Path: dir,
+		User: "root",
+	})
+	if err != nil {
+		step.logger.Info("working-directory-not-found", lager.Data{"dir": dir, "error": err.Error()})
+		return "", TaskDirNotFoundError{Dir: dir}
+	}
+
+	out.Close()
+
+	return dir, nil
+}
+
+func (step *TaskStep) mountPaths(config atc.TaskConfig) []string {
+	mountPaths := []string{step.artifactsRoot}
+
+	inputs := append([]atc.TaskInputConfig{}, config.Inputs...)
+	for _, group := range config.InputGroups {
+		inputs = append(inputs, group.Inputs...)
+	}
+
+	for _, input := range inputs {
+		source := taskInputSource{config: input, artifactsRoot: step.artifactsRoot}
+		mountPaths = append(mountPaths, source.DestinationPath())
+	}
+
+	for _, output := range config.Outputs {
+		mountPaths = append(mountPaths, artifactsPath(output, step.artifactsRoot))
+	}
+
+	return mountPaths
+}
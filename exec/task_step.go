@@ -2,24 +2,69 @@ package exec
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.cloudfoundry.org/clock"
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
+	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/worker"
 )
 
-const taskProcessID = "task"
-const taskProcessPropertyName = "concourse:task-process"
-const taskExitStatusPropertyName = "concourse:exit-status"
+const taskProcessID = worker.TaskProcessID
+const taskProcessPropertyName = worker.TaskProcessPropertyName
+const taskExitStatusPropertyName = worker.TaskExitStatusPropertyName
+const taskResultFile = "result.json"
+const taskEnvFile = "env"
+
+// Variables with this prefix describe the build itself, and may not be
+// exported by a task.
+const reservedEnvPrefix = "BUILD_"
+
+// Stages reported to the TaskDelegate as the TaskStep runs. The same stages
+// are reported whether the task's container was freshly created or found
+// again after e.g. an ATC restart.
+const (
+	// The worker to run the task on has been chosen.
+	TaskStageWorkerChosen = worker.ContainerStageWorkerChosen
+
+	// The task's image has been fetched onto the worker.
+	TaskStageImageResolved = worker.ContainerStageImageResolved
+
+	// The task's inputs are present in the container.
+	TaskStageInputsStreamed = worker.ContainerStageInputsStreamed
+
+	// The task's process has been spawned or re-attached to.
+	TaskStageProcessLaunched = "process-launched"
+
+	// The task's process has exited and its exit status is known.
+	TaskStageProcessExited = "process-exited"
+)
+
+// containerStageDelegate reports the stages of finding or creating the task's
+// container to the TaskDelegate as the worker passes each of them.
+type containerStageDelegate struct {
+	TaskDelegate
+
+	clock clock.Clock
+}
+
+func (delegate containerStageDelegate) ContainerStage(stage string) {
+	delegate.Stage(stage, delegate.clock.Now())
+}
 
 // MissingInputsError is returned when any of the task's required inputs are
 // missing.
@@ -32,6 +77,44 @@ func (err MissingInputsError) Error() string {
 	return fmt.Sprintf("missing inputs: %s", strings.Join(err.Inputs, ", "))
 }
 
+// IncompleteInputGroupError is returned when some, but not all, of the inputs
+// in one of the task's input groups are present.
+type IncompleteInputGroupError struct {
+	Group         string
+	MissingInputs []string
+}
+
+// Error prints a human-friendly message naming the group and the inputs that
+// were missing from it.
+func (err IncompleteInputGroupError) Error() string {
+	return fmt.Sprintf("incomplete input group '%s': missing inputs: %s", err.Group, strings.Join(err.MissingInputs, ", "))
+}
+
+// OutputNotMountedError is returned when a task exits successfully but some
+// of its declared outputs have no volume mounted in its container, e.g.
+// because the volume failed to attach.
+type OutputNotMountedError struct {
+	Outputs []string
+}
+
+// Error prints a human-friendly message listing the outputs that were not
+// mounted.
+func (err OutputNotMountedError) Error() string {
+	return fmt.Sprintf("outputs not mounted: %s", strings.Join(err.Outputs, ", "))
+}
+
+// NoGlobMatchesError is returned when a task exits successfully but no
+// directories match the path of one of its glob outputs.
+type NoGlobMatchesError struct {
+	Output string
+	Path   string
+}
+
+// Error prints a human-friendly message naming the output and its pattern.
+func (err NoGlobMatchesError) Error() string {
+	return fmt.Sprintf("output '%s' matched no directories: %s", err.Output, err.Path)
+}
+
 type MissingTaskImageSourceError struct {
 	SourceName string
 }
@@ -41,30 +124,40 @@ func (err MissingTaskImageSourceError) Error() string {
 make sure there's a corresponding 'get' step, or a task that produces it as an output`, err.SourceName)
 }
 
+// ErrTaskTimeout is returned when a task's script runs for longer than its
+// script timeout.
+var ErrTaskTimeout = errors.New("task timed out")
+
 // TaskStep executes a TaskConfig, whose inputs will be fetched from the
 // worker.ArtifactRepository and outputs will be added to the worker.ArtifactRepository.
 type TaskStep struct {
-	logger            lager.Logger
-	metadata          dbng.ContainerMetadata
-	tags              atc.Tags
-	teamID            int
-	buildID           int
-	planID            atc.PlanID
-	delegate          TaskDelegate
-	privileged        Privileged
-	configSource      TaskConfigSource
-	workerPool        worker.Client
-	artifactsRoot     string
-	resourceTypes     atc.VersionedResourceTypes
-	inputMapping      map[string]string
-	outputMapping     map[string]string
-	imageArtifactName string
-	clock             clock.Clock
-	repo              *worker.ArtifactRepository
+	logger             lager.Logger
+	metadata           dbng.ContainerMetadata
+	tags               atc.Tags
+	teamID             int
+	buildID            int
+	planID             atc.PlanID
+	delegate           TaskDelegate
+	privileged         Privileged
+	configSource       TaskConfigSource
+	workerPool         worker.Client
+	artifactsRoot      string
+	resourceTypes      atc.VersionedResourceTypes
+	inputMapping       map[string]string
+	outputMapping      map[string]string
+	imageArtifactName  string
+	retryableExitCodes []int
+	scriptTimeout      string
+	stopTimeout        string
+	dir                string
+	dedupeOutputs      bool
+	clock              clock.Clock
+	repo               *worker.ArtifactRepository
 
 	process garden.Process
 
 	exitStatus int
+	timedOut   bool
 }
 
 func newTaskStep(
@@ -83,25 +176,35 @@ func newTaskStep(
 	inputMapping map[string]string,
 	outputMapping map[string]string,
 	imageArtifactName string,
+	retryableExitCodes []int,
+	scriptTimeout string,
+	stopTimeout string,
+	dir string,
+	dedupeOutputs bool,
 	clock clock.Clock,
 ) TaskStep {
 	return TaskStep{
-		logger:            logger,
-		metadata:          metadata,
-		tags:              tags,
-		teamID:            teamID,
-		buildID:           buildID,
-		planID:            planID,
-		delegate:          delegate,
-		privileged:        privileged,
-		configSource:      configSource,
-		workerPool:        workerPool,
-		artifactsRoot:     artifactsRoot,
-		resourceTypes:     resourceTypes,
-		inputMapping:      inputMapping,
-		outputMapping:     outputMapping,
-		imageArtifactName: imageArtifactName,
-		clock:             clock,
+		logger:             logger,
+		metadata:           metadata,
+		tags:               tags,
+		teamID:             teamID,
+		buildID:            buildID,
+		planID:             planID,
+		delegate:           delegate,
+		privileged:         privileged,
+		configSource:       configSource,
+		workerPool:         workerPool,
+		artifactsRoot:      artifactsRoot,
+		resourceTypes:      resourceTypes,
+		inputMapping:       inputMapping,
+		outputMapping:      outputMapping,
+		imageArtifactName:  imageArtifactName,
+		retryableExitCodes: retryableExitCodes,
+		scriptTimeout:      scriptTimeout,
+		stopTimeout:        stopTimeout,
+		dir:                dir,
+		dedupeOutputs:      dedupeOutputs,
+		clock:              clock,
 	}
 }
 
@@ -121,18 +224,65 @@ func (step TaskStep) Using(prev Step, repo *worker.ArtifactRepository) Step {
 // of volumes for the TaskConfig's inputs. Inputs that did not have volumes
 // available on the worker will be streamed in to the container.
 //
-// If any inputs are not available in the worker.ArtifactRepository, MissingInputsError
-// is returned.
+// If any required inputs are not available in the worker.ArtifactRepository,
+// MissingInputsError is returned. Missing optional inputs are skipped.
+//
+// Any CA certificates in the TaskConfig are appended to the container's trust
+// store before the task's script is spawned. Params of the form
+// ((file:some-input/path)) are then read from the streamed inputs and set in
+// the script's environment; if a file can't be read, FileParamError is
+// returned.
+//
+// If the build already saved a version of the task's image resource for this
+// plan, the image is pinned to it rather than checking for a newer one.
+//
+// If the TaskStep has a dir, the script is run from it instead of the
+// TaskConfig's run.dir. It must be an input or output's path or exist in the
+// container, otherwise TaskDirNotFoundError is returned.
 //
 // Once all the inputs are satisfies, the task's script will be executed, and
 // the RunStep indicates that it's ready, and any signals will be forwarded to
 // the script.
 //
+// While the script runs its container's resource usage is sampled, and the
+// total is reported to the delegate once it exits.
+//
+// If the TaskStep has a script timeout and the script is still running once
+// it elapses, the container is stopped and ErrTaskTimeout is returned. The
+// timeout starts once the script is spawned or reattached to, so fetching the
+// image and streaming inputs do not count towards it.
+//
+// When the container is stopped, the script is first asked to exit. If the
+// TaskStep has a stop timeout and the script is still running once it
+// elapses, the script is killed.
+//
 // If the script exits successfully, the outputs specified in the TaskConfig
 // are registered with the worker.ArtifactRepository. If no outputs are specified, the
 // task's entire working directory is registered as an ArtifactSource under the
 // name of the task.
+//
+// If deduplicating outputs is enabled, each output is hashed in the container
+// as it is registered. An output with the same contents as an earlier output
+// on the same worker is registered as a reference to the earlier one.
 func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	var scriptTimeout time.Duration
+	if step.scriptTimeout != "" {
+		var err error
+		scriptTimeout, err = time.ParseDuration(step.scriptTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	var stopTimeout time.Duration
+	if step.stopTimeout != "" {
+		var err error
+		stopTimeout, err = time.ParseDuration(step.stopTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
 	processIO := garden.ProcessIO{
 		Stdout: step.delegate.Stdout(),
 		Stderr: step.delegate.Stderr(),
@@ -158,13 +308,17 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 	container, err := step.workerPool.FindOrCreateBuildContainer(
 		step.logger,
 		signals,
-		step.delegate,
+		containerStageDelegate{TaskDelegate: step.delegate, clock: step.clock},
 		step.buildID,
 		step.planID,
 		step.metadata,
 		containerSpec,
 		step.resourceTypes,
 	)
+	if err == resource.ErrInterrupted || err == worker.ErrStreamInInterrupted {
+		return ErrInterrupted
+	}
+
 	if err != nil {
 		return err
 	}
@@ -178,7 +332,13 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 			return err
 		}
 
-		step.registerSource(config, container)
+		step.delegate.Stage(TaskStageProcessExited, step.clock.Now())
+
+		err = step.registerSource(config, container)
+		if err != nil && step.exitStatus == 0 {
+			return err
+		}
+
 		return nil
 	}
 
@@ -196,6 +356,21 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 	} else {
 		step.logger.Info("spawning")
 
+		err = step.injectCACerts(config, container)
+		if err != nil {
+			return err
+		}
+
+		fileEnv, err := step.envForFileParams(config.Params, container)
+		if err != nil {
+			return err
+		}
+
+		dir, err := step.processDir(config, container)
+		if err != nil {
+			return err
+		}
+
 		step.delegate.Started()
 
 		step.process, err = container.Run(garden.ProcessSpec{
@@ -203,8 +378,9 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 
 			Path: config.Run.Path,
 			Args: config.Run.Args,
+			Env:  fileEnv,
 
-			Dir: path.Join(step.artifactsRoot, config.Run.Dir),
+			Dir: dir,
 			TTY: &garden.TTYSpec{},
 		}, processIO)
 	}
@@ -214,6 +390,8 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 
 	step.logger.Info("attached")
 
+	step.delegate.Stage(TaskStageProcessLaunched, step.clock.Now())
+
 	close(ready)
 
 	exited := make(chan struct{})
@@ -225,37 +403,182 @@ func (step *TaskStep) Run(signals <-chan os.Signal, ready chan<- struct{}) error
 		close(exited)
 	}()
 
-	select {
-	case <-signals:
-		step.registerSource(config, container)
+	meter := newResourceUsageMeter(container, step.clock.Now())
 
-		err = container.Stop(false)
-		if err != nil {
-			step.logger.Error("stopping-container", err)
-		}
+	ticker := step.clock.NewTicker(resourceUsageSampleInterval)
+	defer ticker.Stop()
 
-		<-exited
+	var timedOut <-chan time.Time
+	if scriptTimeout > 0 {
+		timer := step.clock.NewTimer(scriptTimeout)
+		defer timer.Stop()
 
-		return ErrInterrupted
+		timedOut = timer.C()
+	}
 
-	case <-exited:
-		if processErr != nil {
-			return processErr
+	for {
+		select {
+		case <-ticker.C():
+			meter.sample(step.clock.Now())
+
+		case <-timedOut:
+			step.logger.Info("timed-out", lager.Data{"timeout": step.scriptTimeout})
+
+			step.timedOut = true
+
+			err = step.registerSource(config, container)
+			if err != nil {
+				step.logger.Error("registering-outputs", err)
+			}
+
+			step.stopContainer(container, exited, stopTimeout)
+
+			step.reportResourceUsage(meter)
+
+			return ErrTaskTimeout
+
+		case <-signals:
+			err = step.registerSource(config, container)
+			if err != nil {
+				step.logger.Error("registering-outputs", err)
+			}
+
+			step.stopContainer(container, exited, stopTimeout)
+
+			step.reportResourceUsage(meter)
+
+			return ErrInterrupted
+
+		case <-exited:
+			step.reportResourceUsage(meter)
+
+			if processErr != nil {
+				return processErr
+			}
+
+			err = step.registerSource(config, container)
+			if err != nil && processStatus == 0 {
+				return err
+			}
+
+			step.exitStatus = processStatus
+
+			step.delegate.Stage(TaskStageProcessExited, step.clock.Now())
+
+			err := container.SetProperty(taskExitStatusPropertyName, fmt.Sprintf("%d", processStatus))
+			if err != nil {
+				return err
+			}
+
+			step.delegate.Finished(ExitStatus(processStatus))
+
+			return nil
 		}
+	}
+}
 
-		step.registerSource(config, container)
+// reportResourceUsage takes a final sample of the container's usage and
+// reports the total to the delegate. Failing to record it is logged but does
+// not fail the step.
+func (step *TaskStep) reportResourceUsage(meter *resourceUsageMeter) {
+	meter.sample(step.clock.Now())
 
-		step.exitStatus = processStatus
+	usage := meter.total()
+	if !usage.Measured {
+		step.logger.Info("resource-usage-unavailable")
+	}
 
-		err := container.SetProperty(taskExitStatusPropertyName, fmt.Sprintf("%d", processStatus))
-		if err != nil {
+	err := step.delegate.ResourceUsageMeasured(usage)
+	if err != nil {
+		step.logger.Error("failed-to-record-resource-usage", err)
+	}
+}
+
+// injectCACerts appends the TaskConfig's CA certificates to the trust store in
+// the container, creating it if the image does not have one.
+func (step *TaskStep) injectCACerts(config atc.TaskConfig, container worker.Container) error {
+	if len(config.CACerts) == 0 {
+		return nil
+	}
+
+	bundlePath := config.CACertsPath
+	if bundlePath == "" {
+		bundlePath = atc.DefaultCACertsPath
+	}
+
+	logger := step.logger.Session("inject-ca-certs", lager.Data{"path": bundlePath})
+
+	bundle, err := readContainerFile(container, bundlePath)
+	if err != nil {
+		if _, ok := err.(FileNotFoundError); !ok {
+			logger.Error("failed-to-read-existing-bundle", err)
 			return err
 		}
 
-		step.delegate.Finished(ExitStatus(processStatus))
+		logger.Info("no-existing-bundle")
+		bundle = nil
+	}
 
-		return nil
+	for _, cert := range config.CACerts {
+		if len(bundle) > 0 && bundle[len(bundle)-1] != '\n' {
+			bundle = append(bundle, '\n')
+		}
+
+		bundle = append(bundle, cert...)
+	}
+
+	if len(bundle) > 0 && bundle[len(bundle)-1] != '\n' {
+		bundle = append(bundle, '\n')
+	}
+
+	tarBuffer := new(bytes.Buffer)
+	tarWriter := tar.NewWriter(tarBuffer)
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name: path.Base(bundlePath),
+		Mode: 0644,
+		Size: int64(len(bundle)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(bundle)
+	if err != nil {
+		return err
 	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return container.StreamIn(garden.StreamInSpec{
+		Path:      path.Dir(bundlePath),
+		User:      "root",
+		TarStream: tarBuffer,
+	})
+}
+
+func readContainerFile(container worker.Container, filePath string) ([]byte, error) {
+	out, err := container.StreamOut(garden.StreamOutSpec{
+		Path: filePath,
+		User: "root",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer out.Close()
+
+	tarReader := tar.NewReader(out)
+
+	_, err = tarReader.Next()
+	if err != nil {
+		return nil, FileNotFoundError{Path: filePath}
+	}
+
+	return ioutil.ReadAll(tarReader)
 }
 
 func (step *TaskStep) containerSpec(config atc.TaskConfig) (worker.ContainerSpec, error) {
@@ -273,6 +596,19 @@ func (step *TaskStep) containerSpec(config atc.TaskConfig) (worker.ContainerSpec
 	} else {
 		imageSpec.ImageURL = config.RootFsUri
 		imageSpec.ImageResource = config.ImageResource
+
+		if config.ImageResource != nil {
+			version, found, err := step.delegate.PinnedImageVersion(
+				resource.GenerateResourceHash(config.ImageResource.Source, config.ImageResource.Type),
+			)
+			if err != nil {
+				return worker.ContainerSpec{}, err
+			}
+
+			if found {
+				imageSpec.ImageResourceVersion = version
+			}
+		}
 	}
 
 	containerSpec := worker.ContainerSpec{
@@ -297,7 +633,10 @@ func (step *TaskStep) containerSpec(config atc.TaskConfig) (worker.ContainerSpec
 
 		source, found := step.repo.SourceFor(worker.ArtifactName(inputName))
 		if !found {
-			missingInputs = append(missingInputs, inputName)
+			if !input.Optional {
+				missingInputs = append(missingInputs, inputName)
+			}
+
 			continue
 		}
 
@@ -313,6 +652,44 @@ func (step *TaskStep) containerSpec(config atc.TaskConfig) (worker.ContainerSpec
 		return worker.ContainerSpec{}, MissingInputsError{missingInputs}
 	}
 
+	for _, group := range config.InputGroups {
+		var groupInputs []worker.InputSource
+		var missingGroupInputs []string
+
+		for _, input := range group.Inputs {
+			inputName := input.Name
+			if sourceName, ok := step.inputMapping[inputName]; ok {
+				inputName = sourceName
+			}
+
+			source, found := step.repo.SourceFor(worker.ArtifactName(inputName))
+			if !found {
+				missingGroupInputs = append(missingGroupInputs, inputName)
+				continue
+			}
+
+			groupInputs = append(groupInputs, &taskInputSource{
+				name:          worker.ArtifactName(inputName),
+				config:        input,
+				source:        source,
+				artifactsRoot: step.artifactsRoot,
+			})
+		}
+
+		if len(groupInputs) == 0 {
+			continue
+		}
+
+		if len(missingGroupInputs) > 0 {
+			return worker.ContainerSpec{}, IncompleteInputGroupError{
+				Group:         group.Name,
+				MissingInputs: missingGroupInputs,
+			}
+		}
+
+		containerSpec.Inputs = append(containerSpec.Inputs, groupInputs...)
+	}
+
 	for _, output := range config.Outputs {
 		path := artifactsPath(output, step.artifactsRoot)
 		containerSpec.Outputs[output.Name] = path
@@ -321,11 +698,59 @@ func (step *TaskStep) containerSpec(config atc.TaskConfig) (worker.ContainerSpec
 	return containerSpec, nil
 }
 
-func (step *TaskStep) registerSource(config atc.TaskConfig, container worker.Container) {
+// stopContainer asks the container's processes to exit and waits for the
+// script to exit. If the script is still running once stopTimeout elapses,
+// the processes are killed. A zero stopTimeout waits for the script
+// indefinitely.
+func (step *TaskStep) stopContainer(container worker.Container, exited <-chan struct{}, stopTimeout time.Duration) {
+	if stopTimeout == 0 {
+		err := container.Stop(false)
+		if err != nil {
+			step.logger.Error("stopping-container", err)
+		}
+
+		<-exited
+
+		return
+	}
+
+	timer := step.clock.NewTimer(stopTimeout)
+	defer timer.Stop()
+
+	// stopping gracefully may block until the processes exit, so it must not
+	// hold up killing them
+	go func() {
+		err := container.Stop(false)
+		if err != nil {
+			step.logger.Error("stopping-container", err)
+		}
+	}()
+
+	select {
+	case <-exited:
+	case <-timer.C():
+		step.logger.Info("killing-container", lager.Data{"timeout": step.stopTimeout})
+
+		err := container.Stop(true)
+		if err != nil {
+			step.logger.Error("killing-container", err)
+		}
+
+		<-exited
+	}
+}
+
+func (step *TaskStep) registerSource(config atc.TaskConfig, container worker.Container) error {
 	volumeMounts := container.VolumeMounts()
 
 	step.logger.Debug("registering-outputs", lager.Data{"config": config})
 
+	var notMounted []string
+
+	// outputs with the same contents as ones registered before this task's are
+	// registered as references to them
+	earlierOutputs := step.repo.AsMap()
+
 	for _, output := range config.Outputs {
 		outputName := output.Name
 		if destinationName, ok := step.outputMapping[output.Name]; ok {
@@ -334,39 +759,210 @@ func (step *TaskStep) registerSource(config atc.TaskConfig, container worker.Con
 
 		outputPath := artifactsPath(output, step.artifactsRoot)
 
+		mounted := false
 		for _, mount := range volumeMounts {
 			if mount.MountPath == outputPath {
+				mounted = true
+
+				if output.Glob {
+					err := step.registerGlobOutput(outputName, output, mount.Volume)
+					if err != nil {
+						return err
+					}
+
+					continue
+				}
+
 				source := newVolumeSource(step.logger, mount.Volume)
-				step.repo.RegisterSource(worker.ArtifactName(outputName), source)
+
+				if step.dedupeOutputs {
+					source.workerName = container.WorkerName()
+
+					digest, err := step.hashOutput(container, outputPath)
+					if err != nil {
+						step.logger.Info("failed-to-hash-output", lager.Data{"output": outputName, "error": err.Error()})
+					}
+
+					source.digest = digest
+				}
+
+				step.repo.RegisterSource(
+					worker.ArtifactName(outputName),
+					step.dedupeOutput(worker.ArtifactName(outputName), source, earlierOutputs),
+				)
+
+				if output.Result {
+					step.recordResult(outputName, source)
+				}
+
+				if output.Env {
+					step.exportEnv(outputName, source)
+				}
 			}
 		}
+
+		if !mounted {
+			notMounted = append(notMounted, output.Name)
+		}
+	}
+
+	if len(notMounted) > 0 {
+		return OutputNotMountedError{Outputs: notMounted}
 	}
+
+	return nil
+}
+
+// recordResult reads the result.json file from an output marked as carrying
+// the task's result and saves it as metadata on the build. A missing or
+// malformed file is reported to the user but does not fail the step.
+func (step *TaskStep) recordResult(outputName string, source *volumeSource) {
+	logger := step.logger.Session("record-result", lager.Data{"output": outputName})
+
+	result, err := readTaskResult(source)
+	if err != nil {
+		logger.Info("failed-to-read-result", lager.Data{"error": err.Error()})
+		fmt.Fprintf(step.delegate.Stderr(), "\x1b[1;33mWARNING: ignoring result of output '%s': %s\x1b[0m\n", outputName, err)
+		return
+	}
+
+	err = step.delegate.ResultDetermined(outputName, result)
+	if err != nil {
+		logger.Error("failed-to-save-result", err)
+		fmt.Fprintf(step.delegate.Stderr(), "\x1b[1;33mWARNING: failed to save result of output '%s'\x1b[0m\n", outputName)
+	}
+}
+
+func readTaskResult(source *volumeSource) (json.RawMessage, error) {
+	file, err := source.StreamFile(taskResultFile)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var result map[string]json.RawMessage
+	err = json.NewDecoder(file).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("malformed %s: %s", taskResultFile, err)
+	}
+
+	return json.Marshal(result)
+}
+
+// exportEnv reads the env file from an output marked as exporting environment
+// variables and records them on the build, so that they are set for the
+// build's subsequent tasks. Reserved variables are skipped, and as with
+// results, a missing or malformed file does not fail the step.
+func (step *TaskStep) exportEnv(outputName string, source *volumeSource) {
+	logger := step.logger.Session("export-env", lager.Data{"output": outputName})
+
+	env, err := readTaskEnv(source)
+	if err != nil {
+		logger.Info("failed-to-read-env", lager.Data{"error": err.Error()})
+		fmt.Fprintf(step.delegate.Stderr(), "\x1b[1;33mWARNING: ignoring env of output '%s': %s\x1b[0m\n", outputName, err)
+		return
+	}
+
+	for name := range env {
+		if strings.HasPrefix(name, reservedEnvPrefix) {
+			fmt.Fprintf(step.delegate.Stderr(), "\x1b[1;33mWARNING: not exporting reserved variable '%s'\x1b[0m\n", name)
+			delete(env, name)
+		}
+	}
+
+	if len(env) == 0 {
+		return
+	}
+
+	err = step.delegate.EnvExported(env)
+	if err != nil {
+		logger.Error("failed-to-save-env", err)
+		fmt.Fprintf(step.delegate.Stderr(), "\x1b[1;33mWARNING: failed to export env of output '%s'\x1b[0m\n", outputName)
+	}
+}
+
+func readTaskEnv(source *volumeSource) (map[string]string, error) {
+	file, err := source.StreamFile(taskEnvFile)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	env := map[string]string{}
+
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		segs := strings.SplitN(line, "=", 2)
+		if len(segs) != 2 || strings.TrimSpace(segs[0]) == "" {
+			return nil, fmt.Errorf("malformed %s: line %d is not of the form KEY=value", taskEnvFile, lineNum)
+		}
+
+		env[strings.TrimSpace(segs[0])] = segs[1]
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("malformed %s: %s", taskEnvFile, err)
+	}
+
+	return env, nil
 }
 
 // Result indicates Success as true if the script's exit status was 0.
 //
 // It also indicates ExitStatus as the exit status of the script.
 //
+// If the task was configured with retryable exit codes, it indicates
+// Retryable as true if the script failed with one of them.
+//
 // All other types are ignored.
 func (step *TaskStep) Result(x interface{}) bool {
 	switch v := x.(type) {
 	case *Success:
-		*v = step.exitStatus == 0
+		*v = Success(step.exitStatus == 0 && !step.timedOut)
 		return true
 
 	case *ExitStatus:
 		*v = ExitStatus(step.exitStatus)
 		return true
 
+	case *Retryable:
+		if len(step.retryableExitCodes) == 0 {
+			return false
+		}
+
+		*v = false
+		for _, code := range step.retryableExitCodes {
+			if step.exitStatus == code && code != 0 {
+				*v = true
+				break
+			}
+		}
+
+		return true
+
 	default:
 		return false
 	}
 }
 
+// envForParams returns the params as KEY=value strings, leaving out params
+// read from files, which are set when the script is spawned.
 func (TaskStep) envForParams(params map[string]string) []string {
 	env := make([]string, 0, len(params))
 
 	for k, v := range params {
+		if _, ok := fileParamPath(v); ok {
+			continue
+		}
+
 		env = append(env, k+"="+v)
 	}
 
@@ -376,6 +972,11 @@ func (TaskStep) envForParams(params map[string]string) []string {
 type volumeSource struct {
 	logger lager.Logger
 	volume worker.Volume
+
+	// workerName and digest are only set for task outputs when deduplicating
+	// outputs is enabled
+	workerName string
+	digest     string
 }
 
 func newVolumeSource(
@@ -389,7 +990,7 @@ func newVolumeSource(
 }
 
 func (src *volumeSource) StreamTo(destination worker.ArtifactDestination) error {
-	out, err := src.volume.StreamOut(".")
+	out, err := src.StreamOut()
 	if err != nil {
 		return err
 	}
@@ -399,6 +1000,11 @@ func (src *volumeSource) StreamTo(destination worker.ArtifactDestination) error
 	return destination.StreamIn(".", out)
 }
 
+// StreamOut opens a fresh stream of the volume's contents.
+func (src *volumeSource) StreamOut() (io.ReadCloser, error) {
+	return src.volume.StreamOut(".")
+}
+
 func (src *volumeSource) StreamFile(filename string) (io.ReadCloser, error) {
 	out, err := src.volume.StreamOut(filename)
 	if err != nil {
@@ -429,8 +1035,18 @@ type taskInputSource struct {
 	artifactsRoot string
 }
 
-func (s *taskInputSource) Name() worker.ArtifactName     { return s.name }
-func (s *taskInputSource) Source() worker.ArtifactSource { return s.source }
+func (s *taskInputSource) Name() worker.ArtifactName { return s.name }
+
+func (s *taskInputSource) Source() worker.ArtifactSource {
+	if len(s.config.Files) == 0 {
+		return s.source
+	}
+
+	return fileFilteringSource{
+		ArtifactSource: s.source,
+		files:          s.config.Files,
+	}
+}
 
 func (s *taskInputSource) DestinationPath() string {
 	subdir := s.config.Path
@@ -447,5 +1063,10 @@ func artifactsPath(outputConfig atc.TaskOutputConfig, artifactsRoot string) stri
 		outputSrc = outputConfig.Name
 	}
 
+	// glob outputs mount the directory containing the matches
+	if outputConfig.Glob {
+		outputSrc = path.Dir(path.Clean(outputSrc))
+	}
+
 	return path.Join(artifactsRoot, outputSrc) + "/"
 }
@@ -2,6 +2,7 @@ package exec_test
 
 import (
 	"archive/tar"
+	"bytes"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -12,12 +13,14 @@ import (
 	"code.cloudfoundry.org/clock/fakeclock"
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/dbng/dbngfakes"
 	. "github.com/concourse/atc/exec"
 	"github.com/concourse/atc/exec/execfakes"
+	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/resource/resourcefakes"
 	"github.com/concourse/atc/worker"
 	"github.com/concourse/atc/worker/workerfakes"
@@ -38,9 +41,13 @@ var _ = Describe("GardenFactory", func() {
 		stderrBuf *gbytes.Buffer
 		fakeClock *fakeclock.FakeClock
 
-		sourceName        worker.ArtifactName = "some-source-name"
-		imageArtifactName string
-		workerMetadata    dbng.ContainerMetadata
+		sourceName         worker.ArtifactName = "some-source-name"
+		imageArtifactName  string
+		retryableExitCodes []int
+		scriptTimeout      string
+		stopTimeout        string
+		dir                string
+		workerMetadata     dbng.ContainerMetadata
 	)
 
 	BeforeEach(func() {
@@ -48,7 +55,7 @@ var _ = Describe("GardenFactory", func() {
 		fakeResourceFactory := new(resourcefakes.FakeResourceFactory)
 		fakeResourceFetcher := new(resourcefakes.FakeFetcher)
 		fakeDBResourceCacheFactory = new(dbngfakes.FakeResourceCacheFactory)
-		factory = NewGardenFactory(fakeWorkerClient, fakeResourceFetcher, fakeResourceFactory, fakeDBResourceCacheFactory)
+		factory = NewGardenFactory(fakeWorkerClient, fakeResourceFetcher, fakeResourceFactory, fakeDBResourceCacheFactory, false)
 
 		stdoutBuf = gbytes.NewBuffer()
 		stderrBuf = gbytes.NewBuffer()
@@ -99,6 +106,10 @@ var _ = Describe("GardenFactory", func() {
 			inputMapping = nil
 			outputMapping = nil
 			imageArtifactName = ""
+			retryableExitCodes = nil
+			scriptTimeout = ""
+			stopTimeout = ""
+			dir = ""
 			fakeClock = fakeclock.NewFakeClock(time.Unix(0, 123))
 
 			workerMetadata = dbng.ContainerMetadata{
@@ -123,6 +134,10 @@ var _ = Describe("GardenFactory", func() {
 				inputMapping,
 				outputMapping,
 				imageArtifactName,
+				retryableExitCodes,
+				scriptTimeout,
+				stopTimeout,
+				dir,
 				fakeClock,
 			).Using(inStep, repo)
 
@@ -158,7 +173,30 @@ var _ = Describe("GardenFactory", func() {
 				BeforeEach(func() {
 					fakeContainer = new(workerfakes.FakeContainer)
 					fakeContainer.HandleReturns("some-handle")
-					fakeWorkerClient.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+					fakeWorkerClient.FindOrCreateBuildContainerStub = func(
+						_ lager.Logger,
+						_ <-chan os.Signal,
+						delegate worker.ImageFetchingDelegate,
+						_ int,
+						_ atc.PlanID,
+						_ dbng.ContainerMetadata,
+						_ worker.ContainerSpec,
+						_ atc.VersionedResourceTypes,
+					) (worker.Container, error) {
+						stageDelegate, ok := delegate.(worker.ContainerStageDelegate)
+						Expect(ok).To(BeTrue())
+
+						for _, stage := range []string{
+							worker.ContainerStageWorkerChosen,
+							worker.ContainerStageImageResolved,
+							worker.ContainerStageInputsStreamed,
+						} {
+							stageDelegate.ContainerStage(stage)
+							fakeClock.Increment(time.Second)
+						}
+
+						return fakeContainer, nil
+					}
 				})
 
 				It("gets the config from the input artifact source", func() {
@@ -178,7 +216,10 @@ var _ = Describe("GardenFactory", func() {
 						WorkingDirectory: "/tmp/build/a1f5c0c1",
 					}))
 
-					Expect(delegate).To(Equal(taskDelegate))
+					identifier := worker.ResourceCacheIdentifier{ResourceVersion: atc.Version{"some": "version"}}
+					Expect(delegate.ImageVersionDetermined(identifier)).To(Succeed())
+					Expect(taskDelegate.ImageVersionDeterminedCallCount()).To(Equal(1))
+					Expect(taskDelegate.ImageVersionDeterminedArgsForCall(0)).To(Equal(identifier))
 
 					Expect(spec).To(Equal(worker.ContainerSpec{
 						Platform: "some-platform",
@@ -215,6 +256,142 @@ var _ = Describe("GardenFactory", func() {
 					})
 				})
 
+				It("checks for the latest version of the image resource", func() {
+					Eventually(process.Wait()).Should(Receive(BeNil()))
+
+					_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+					Expect(spec.ImageSpec.ImageResourceVersion).To(BeNil())
+				})
+
+				Context("when the build saved a version of the image resource", func() {
+					BeforeEach(func() {
+						taskDelegate.PinnedImageVersionReturns(atc.Version{"some": "version"}, true, nil)
+					})
+
+					It("looks the version up by the image resource", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						Expect(taskDelegate.PinnedImageVersionCallCount()).To(Equal(1))
+						Expect(taskDelegate.PinnedImageVersionArgsForCall(0)).To(Equal(resource.GenerateResourceHash(atc.Source{"some": "source"}, "docker")))
+					})
+
+					It("pins the image resource to the saved version", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+						Expect(spec.ImageSpec.ImageResource).To(Equal(&atc.ImageResource{
+							Type:   "docker",
+							Source: atc.Source{"some": "source"},
+						}))
+						Expect(spec.ImageSpec.ImageResourceVersion).To(Equal(atc.Version{"some": "version"}))
+					})
+				})
+
+				Context("when the task is run again by a later attempt", func() {
+					var (
+						checks         int
+						secondAttempt  ifrit.Process
+						savedVersions  map[string]atc.Version
+						checkedVersion atc.Version
+					)
+
+					BeforeEach(func() {
+						checks = 0
+						savedVersions = map[string]atc.Version{}
+						checkedVersion = atc.Version{"some": "version"}
+
+						taskDelegate.ImageVersionDeterminedStub = func(identifier worker.ResourceCacheIdentifier) error {
+							if _, found := savedVersions[identifier.ResourceHash]; !found {
+								savedVersions[identifier.ResourceHash] = identifier.ResourceVersion
+							}
+
+							return nil
+						}
+
+						taskDelegate.PinnedImageVersionStub = func(resourceHash string) (atc.Version, bool, error) {
+							version, found := savedVersions[resourceHash]
+							return version, found, nil
+						}
+
+						createContainer := fakeWorkerClient.FindOrCreateBuildContainerStub
+						fakeWorkerClient.FindOrCreateBuildContainerStub = func(
+							logger lager.Logger,
+							signals <-chan os.Signal,
+							delegate worker.ImageFetchingDelegate,
+							buildID int,
+							planID atc.PlanID,
+							metadata dbng.ContainerMetadata,
+							spec worker.ContainerSpec,
+							resourceTypes atc.VersionedResourceTypes,
+						) (worker.Container, error) {
+							version := spec.ImageSpec.ImageResourceVersion
+							if version == nil {
+								checks++
+								version = checkedVersion
+							}
+
+							err := delegate.ImageVersionDetermined(worker.ResourceCacheIdentifier{
+								ResourceVersion: version,
+								ResourceHash:    resource.GenerateResourceHash(spec.ImageSpec.ImageResource.Source, spec.ImageSpec.ImageResource.Type),
+							})
+							Expect(err).NotTo(HaveOccurred())
+
+							return createContainer(logger, signals, delegate, buildID, planID, metadata, spec, resourceTypes)
+						}
+					})
+
+					JustBeforeEach(func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						checkedVersion = atc.Version{"some": "newer-version"}
+
+						secondAttempt = ifrit.Invoke(factory.Task(
+							lagertest.NewTestLogger("test"),
+							teamID,
+							1234,
+							atc.PlanID("some-other-plan-id"),
+							sourceName,
+							workerMetadata,
+							taskDelegate,
+							privileged,
+							tags,
+							configSource,
+							resourceTypes,
+							inputMapping,
+							outputMapping,
+							imageArtifactName,
+							retryableExitCodes,
+							scriptTimeout,
+							stopTimeout,
+							dir,
+							fakeClock,
+						).Using(inStep, repo))
+					})
+
+					It("does not check for a newer image version", func() {
+						Eventually(secondAttempt.Wait()).Should(Receive(BeNil()))
+
+						Expect(checks).To(Equal(1))
+
+						Expect(fakeWorkerClient.FindOrCreateBuildContainerCallCount()).To(Equal(2))
+						_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(1)
+						Expect(spec.ImageSpec.ImageResourceVersion).To(Equal(atc.Version{"some": "version"}))
+					})
+				})
+
+				Context("when looking up the saved image resource version fails", func() {
+					disaster := errors.New("nope")
+
+					BeforeEach(func() {
+						taskDelegate.PinnedImageVersionReturns(nil, false, disaster)
+					})
+
+					It("exits with the error without creating a container", func() {
+						Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+						Expect(fakeWorkerClient.FindOrCreateBuildContainerCallCount()).To(BeZero())
+					})
+				})
+
 				Context("when an exit status is already saved off", func() {
 					BeforeEach(func() {
 						fakeContainer.PropertyStub = func(name string) (string, error) {
@@ -263,6 +440,33 @@ var _ = Describe("GardenFactory", func() {
 						Expect(taskDelegate.FinishedCallCount()).To(BeZero())
 					})
 
+					It("reports the container's stages followed by the process having exited", func() {
+						Eventually(process.Wait()).Should(Receive(BeNil()))
+
+						stages := []string{}
+						times := []time.Time{}
+						for i := 0; i < taskDelegate.StageCallCount(); i++ {
+							stage, at := taskDelegate.StageArgsForCall(i)
+							stages = append(stages, stage)
+							times = append(times, at)
+						}
+
+						Expect(stages).To(Equal([]string{
+							TaskStageWorkerChosen,
+							TaskStageImageResolved,
+							TaskStageInputsStreamed,
+							TaskStageProcessExited,
+						}))
+
+						start := time.Unix(0, 123)
+						Expect(times).To(Equal([]time.Time{
+							start,
+							start.Add(time.Second),
+							start.Add(2 * time.Second),
+							start.Add(3 * time.Second),
+						}))
+					})
+
 					Context("when outputs are configured and present on the container", func() {
 						var (
 							fakeMountPath1 string = "/tmp/build/a1f5c0c1/some-output-configured-path/"
@@ -405,6 +609,24 @@ var _ = Describe("GardenFactory", func() {
 					It("does not invoke the delegate's Started callback", func() {
 						Expect(taskDelegate.StartedCallCount()).To(BeZero())
 					})
+
+					It("reports the same stages as when the process is spawned", func() {
+						Eventually(process.Wait()).Should(Receive())
+
+						stages := []string{}
+						for i := 0; i < taskDelegate.StageCallCount(); i++ {
+							stage, _ := taskDelegate.StageArgsForCall(i)
+							stages = append(stages, stage)
+						}
+
+						Expect(stages).To(Equal([]string{
+							TaskStageWorkerChosen,
+							TaskStageImageResolved,
+							TaskStageInputsStreamed,
+							TaskStageProcessLaunched,
+							TaskStageProcessExited,
+						}))
+					})
 				})
 
 				Context("when the process is not already running or exited", func() {
@@ -442,6 +664,33 @@ var _ = Describe("GardenFactory", func() {
 						Expect(taskDelegate.StartedCallCount()).To(Equal(1))
 					})
 
+					It("reports each stage through to the process exiting", func() {
+						Eventually(process.Wait()).Should(Receive())
+
+						stages := []string{}
+						times := []time.Time{}
+						for i := 0; i < taskDelegate.StageCallCount(); i++ {
+							stage, at := taskDelegate.StageArgsForCall(i)
+							stages = append(stages, stage)
+							times = append(times, at)
+						}
+
+						start := time.Unix(0, 123)
+						Expect(times[:3]).To(Equal([]time.Time{
+							start,
+							start.Add(time.Second),
+							start.Add(2 * time.Second),
+						}))
+
+						Expect(stages).To(Equal([]string{
+							TaskStageWorkerChosen,
+							TaskStageImageResolved,
+							TaskStageInputsStreamed,
+							TaskStageProcessLaunched,
+							TaskStageProcessExited,
+						}))
+					})
+
 					Context("when privileged", func() {
 						BeforeEach(func() {
 							privileged = true
@@ -467,6 +716,279 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when a param is read from a file", func() {
+						BeforeEach(func() {
+							fetchedConfig.Params = map[string]string{
+								"SOME":  "params",
+								"TOKEN": "((file:some-input/token))",
+							}
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+
+							fakeContainer.StreamOutStub = func(spec garden.StreamOutSpec) (io.ReadCloser, error) {
+								tarBuffer := gbytes.NewBuffer()
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								contents := "  some-secret\n"
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name: "token",
+									Mode: 0644,
+									Size: int64(len(contents)),
+								})
+								Expect(err).NotTo(HaveOccurred())
+
+								_, err = tarWriter.Write([]byte(contents))
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(tarWriter.Close()).To(Succeed())
+
+								return tarBuffer, nil
+							}
+						})
+
+						It("reads the file from the container relative to the build directory", func() {
+							Expect(fakeContainer.StreamOutCallCount()).To(Equal(1))
+
+							spec := fakeContainer.StreamOutArgsForCall(0)
+							Expect(spec.Path).To(Equal("/tmp/build/a1f5c0c1/some-input/token"))
+							Expect(spec.User).To(Equal("root"))
+						})
+
+						It("sets the file's trimmed contents in the process's environment", func() {
+							Expect(fakeContainer.RunCallCount()).To(Equal(1))
+
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Expect(spec.Env).To(Equal([]string{"TOKEN=some-secret"}))
+						})
+
+						It("leaves the param out of the container's environment", func() {
+							_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+							Expect(spec.Env).To(ConsistOf("SOME=params"))
+						})
+
+						Context("when the file cannot be read", func() {
+							var disaster error
+
+							BeforeEach(func() {
+								disaster = errors.New("no such file")
+
+								fakeContainer.StreamOutStub = nil
+								fakeContainer.StreamOutReturns(nil, disaster)
+							})
+
+							It("exits with an error naming the param and the file", func() {
+								Eventually(process.Wait()).Should(Receive(Equal(FileParamError{
+									Param: "TOKEN",
+									Path:  "some-input/token",
+									Err:   disaster,
+								})))
+							})
+
+							It("does not run the process", func() {
+								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+								Expect(fakeContainer.RunCallCount()).To(BeZero())
+							})
+						})
+					})
+
+					Context("when the configuration specifies CA certs", func() {
+						var runCallsBeforeStreamIn int
+
+						BeforeEach(func() {
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								CACerts: []string{"some-cert", "some-other-cert\n"},
+							}, nil)
+
+							runCallsBeforeStreamIn = -1
+							fakeContainer.StreamInStub = func(garden.StreamInSpec) error {
+								runCallsBeforeStreamIn = fakeContainer.RunCallCount()
+								return nil
+							}
+						})
+
+						streamedBundle := func() (string, string) {
+							Expect(fakeContainer.StreamInCallCount()).To(Equal(1))
+
+							spec := fakeContainer.StreamInArgsForCall(0)
+							Expect(spec.User).To(Equal("root"))
+
+							tarReader := tar.NewReader(spec.TarStream)
+
+							header, err := tarReader.Next()
+							Expect(err).NotTo(HaveOccurred())
+
+							contents, err := ioutil.ReadAll(tarReader)
+							Expect(err).NotTo(HaveOccurred())
+
+							return spec.Path + "/" + header.Name, string(contents)
+						}
+
+						Context("when the image has a trust store", func() {
+							BeforeEach(func() {
+								tarBuffer := gbytes.NewBuffer()
+
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								existing := "existing-cert"
+								err := tarWriter.WriteHeader(&tar.Header{
+									Name: "ca-certificates.crt",
+									Mode: 0644,
+									Size: int64(len(existing)),
+								})
+								Expect(err).NotTo(HaveOccurred())
+
+								_, err = tarWriter.Write([]byte(existing))
+								Expect(err).NotTo(HaveOccurred())
+
+								fakeContainer.StreamOutReturns(tarBuffer, nil)
+							})
+
+							It("appends the certs to the default trust store before running the process", func() {
+								Eventually(process.Wait()).Should(Receive())
+
+								Expect(fakeContainer.StreamOutCallCount()).To(Equal(1))
+								Expect(fakeContainer.StreamOutArgsForCall(0).Path).To(Equal("/etc/ssl/certs/ca-certificates.crt"))
+
+								path, contents := streamedBundle()
+								Expect(path).To(Equal("/etc/ssl/certs/ca-certificates.crt"))
+								Expect(contents).To(Equal("existing-cert\nsome-cert\nsome-other-cert\n"))
+
+								Expect(runCallsBeforeStreamIn).To(Equal(0))
+								Expect(fakeContainer.RunCallCount()).To(Equal(1))
+							})
+						})
+
+						Context("when the image has no trust store", func() {
+							BeforeEach(func() {
+								fakeContainer.StreamOutReturns(gbytes.NewBuffer(), nil)
+							})
+
+							It("creates it with just the certs", func() {
+								Eventually(process.Wait()).Should(Receive())
+
+								_, contents := streamedBundle()
+								Expect(contents).To(Equal("some-cert\nsome-other-cert\n"))
+							})
+						})
+
+						Context("when a trust store path is configured", func() {
+							BeforeEach(func() {
+								configSource.FetchConfigReturns(atc.TaskConfig{
+									Platform:  "some-platform",
+									RootFsUri: "some-image",
+									Run: atc.TaskRunConfig{
+										Path: "ls",
+									},
+									CACerts:     []string{"some-cert"},
+									CACertsPath: "/etc/pki/tls/certs/ca-bundle.crt",
+								}, nil)
+
+								fakeContainer.StreamOutReturns(gbytes.NewBuffer(), nil)
+							})
+
+							It("streams the bundle to that path", func() {
+								Eventually(process.Wait()).Should(Receive())
+
+								Expect(fakeContainer.StreamOutArgsForCall(0).Path).To(Equal("/etc/pki/tls/certs/ca-bundle.crt"))
+
+								path, _ := streamedBundle()
+								Expect(path).To(Equal("/etc/pki/tls/certs/ca-bundle.crt"))
+							})
+						})
+
+						Context("when reading the existing bundle fails", func() {
+							disaster := errors.New("nope")
+
+							BeforeEach(func() {
+								fakeContainer.StreamOutReturns(nil, disaster)
+							})
+
+							It("exits with the error without replacing the trust store", func() {
+								Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+								Expect(fakeContainer.StreamInCallCount()).To(BeZero())
+								Expect(fakeContainer.RunCallCount()).To(BeZero())
+							})
+						})
+
+						Context("when streaming in the bundle fails", func() {
+							disaster := errors.New("nope")
+
+							BeforeEach(func() {
+								fakeContainer.StreamOutReturns(gbytes.NewBuffer(), nil)
+								fakeContainer.StreamInStub = nil
+								fakeContainer.StreamInReturns(disaster)
+							})
+
+							It("exits with the error without running the process", func() {
+								Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+								Expect(fakeContainer.RunCallCount()).To(BeZero())
+							})
+						})
+					})
+
+					Context("when the worker reports the container's metrics", func() {
+						var waitForExit chan struct{}
+
+						BeforeEach(func() {
+							fakeContainer.MetricsReturns(garden.Metrics{
+								CPUStat:    garden.ContainerCPUStat{Usage: uint64(2 * time.Second)},
+								MemoryStat: garden.ContainerMemoryStat{TotalUsageTowardLimit: 1024},
+							}, nil)
+
+							waitForExit = make(chan struct{})
+							fakeProcess.WaitStub = func() (int, error) {
+								<-waitForExit
+								return 0, nil
+							}
+						})
+
+						It("reports the container's usage once the process exits", func() {
+							fakeClock.WaitForWatcherAndIncrement(10 * time.Second)
+							Eventually(fakeContainer.MetricsCallCount).Should(Equal(1))
+
+							close(waitForExit)
+							Eventually(process.Wait()).Should(Receive())
+
+							Expect(taskDelegate.ResourceUsageMeasuredCallCount()).To(Equal(1))
+							Expect(taskDelegate.ResourceUsageMeasuredArgsForCall(0)).To(Equal(ResourceUsage{
+								CPUSeconds:        2,
+								MemoryByteSeconds: 10 * 1024,
+								Measured:          true,
+							}))
+						})
+					})
+
+					Context("when waiting on the process fails", func() {
+						disaster := errors.New("nope")
+
+						BeforeEach(func() {
+							fakeProcess.WaitReturns(0, disaster)
+						})
+
+						It("still reports the container's usage", func() {
+							Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+
+							Expect(taskDelegate.ResourceUsageMeasuredCallCount()).To(Equal(1))
+						})
+					})
+
+					Context("when the worker cannot report the container's metrics", func() {
+						BeforeEach(func() {
+							fakeContainer.MetricsReturns(garden.Metrics{}, errors.New("not supported"))
+						})
+
+						It("reports the usage as unmeasured", func() {
+							Eventually(process.Wait()).Should(Receive())
+
+							Expect(taskDelegate.ResourceUsageMeasuredCallCount()).To(Equal(1))
+							Expect(taskDelegate.ResourceUsageMeasuredArgsForCall(0)).To(Equal(ResourceUsage{}))
+						})
+					})
+
 					Context("when the configuration specifies paths for inputs", func() {
 						var inputSource *workerfakes.FakeArtifactSource
 						var otherInputSource *workerfakes.FakeArtifactSource
@@ -538,77 +1060,553 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
-					Context("when input is remapped", func() {
-						var remappedInputSource *workerfakes.FakeArtifactSource
+					Context("when the configuration specifies optional inputs", func() {
+						var inputSource *workerfakes.FakeArtifactSource
 
 						BeforeEach(func() {
-							remappedInputSource = new(workerfakes.FakeArtifactSource)
-							inputMapping = map[string]string{"remapped-input": "remapped-input-src"}
+							inputSource = new(workerfakes.FakeArtifactSource)
 
 							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
 								Run: atc.TaskRunConfig{
 									Path: "ls",
 								},
 								Inputs: []atc.TaskInputConfig{
-									{Name: "remapped-input"},
+									{Name: "some-required-input"},
+									{Name: "some-optional-input", Optional: true},
 								},
 							}, nil)
 						})
 
-						Context("when all inputs are present in the in source repository", func() {
+						Context("when an optional input is missing and the required input is present", func() {
 							BeforeEach(func() {
-								repo.RegisterSource("remapped-input-src", remappedInputSource)
+								repo.RegisterSource("some-required-input", inputSource)
 							})
 
-							It("uses remapped input", func() {
+							It("creates the container with only the present input", func() {
+								Expect(fakeWorkerClient.FindOrCreateBuildContainerCallCount()).To(Equal(1))
 								_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
 								Expect(spec.Inputs).To(HaveLen(1))
-								Expect(spec.Inputs[0].Name()).To(Equal(worker.ArtifactName("remapped-input-src")))
-								Expect(spec.Inputs[0].Source()).To(Equal(remappedInputSource))
-								Expect(spec.Inputs[0].DestinationPath()).To(Equal("/tmp/build/a1f5c0c1/remapped-input"))
-								Eventually(process.Wait()).Should(Receive(BeNil()))
+								Expect(spec.Inputs[0].Name()).To(Equal(worker.ArtifactName("some-required-input")))
+								Expect(spec.Inputs[0].Source()).To(Equal(inputSource))
+							})
+
+							It("does not invoke the delegate's Failed callback", func() {
+								Expect(taskDelegate.FailedCallCount()).To(BeZero())
 							})
 						})
 
-						Context("when any of the inputs are missing", func() {
-							It("exits with failure", func() {
+						Context("when the required input is missing too", func() {
+							It("exits with failure listing only the required input", func() {
 								var err error
 								Eventually(process.Wait()).Should(Receive(&err))
 								Expect(err).To(BeAssignableToTypeOf(MissingInputsError{}))
-								Expect(err.(MissingInputsError).Inputs).To(ConsistOf("remapped-input-src"))
+								Expect(err.(MissingInputsError).Inputs).To(ConsistOf("some-required-input"))
 							})
+						})
+					})
 
-							It("invokes the delegate's Failed callback", func() {
-								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+					Context("when the configuration specifies files for an input", func() {
+						var inputSource *workerfakes.FakeArtifactSource
+						var otherInputSource *workerfakes.FakeArtifactSource
 
-								Expect(taskDelegate.FailedCallCount()).To(Equal(1))
+						inputTar := func() io.Reader {
+							tarBuffer := new(bytes.Buffer)
+							tarWriter := tar.NewWriter(tarBuffer)
 
-								err := taskDelegate.FailedArgsForCall(0)
-								Expect(err).To(BeAssignableToTypeOf(MissingInputsError{}))
-								Expect(err.(MissingInputsError).Inputs).To(ConsistOf("remapped-input-src"))
-							})
-						})
-					})
+							for _, dir := range []string{"./", "./some-dir/", "./other-dir/"} {
+								err := tarWriter.WriteHeader(&tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0755})
+								Expect(err).NotTo(HaveOccurred())
+							}
 
-					Context("when the configuration specifies paths for outputs", func() {
-						BeforeEach(func() {
-							configSource.FetchConfigReturns(atc.TaskConfig{
-								Platform:  "some-platform",
-								RootFsUri: "some-image",
-								Params:    map[string]string{"SOME": "params"},
-								Run: atc.TaskRunConfig{
-									Path: "ls",
-									Args: []string{"some", "args"},
-								},
-								Outputs: []atc.TaskOutputConfig{
-									{Name: "some-output", Path: "some-output-configured-path"},
-									{Name: "some-other-output"},
-									{Name: "some-trailing-slash-output", Path: "some-output-configured-path-with-trailing-slash/"},
-								},
-							}, nil)
-						})
+							for _, file := range []string{"./some-dir/some-file", "./some-dir/other-file", "./other-dir/some-file", "./top-file"} {
+								err := tarWriter.WriteHeader(&tar.Header{Name: file, Mode: 0644, Size: int64(len(file))})
+								Expect(err).NotTo(HaveOccurred())
 
-						It("configures them appropriately in the container spec", func() {
+								_, err = tarWriter.Write([]byte(file))
+								Expect(err).NotTo(HaveOccurred())
+							}
+
+							Expect(tarWriter.Close()).To(Succeed())
+
+							return tarBuffer
+						}
+
+						streamedNames := func(source worker.ArtifactSource) []string {
+							fakeDestination := new(workerfakes.FakeArtifactDestination)
+
+							var names []string
+							fakeDestination.StreamInStub = func(dst string, in io.Reader) error {
+								tarReader := tar.NewReader(in)
+								for {
+									header, err := tarReader.Next()
+									if err == io.EOF {
+										return nil
+									}
+
+									Expect(err).NotTo(HaveOccurred())
+
+									contents, err := ioutil.ReadAll(tarReader)
+									Expect(err).NotTo(HaveOccurred())
+
+									if header.Typeflag != tar.TypeDir {
+										Expect(string(contents)).To(Equal(header.Name))
+									}
+
+									names = append(names, header.Name)
+								}
+							}
+
+							Expect(source.StreamTo(fakeDestination)).To(Succeed())
+							Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+
+							return names
+						}
+
+						BeforeEach(func() {
+							inputSource = new(workerfakes.FakeArtifactSource)
+							otherInputSource = new(workerfakes.FakeArtifactSource)
+
+							for _, source := range []*workerfakes.FakeArtifactSource{inputSource, otherInputSource} {
+								source.StreamToStub = func(dest worker.ArtifactDestination) error {
+									return dest.StreamIn(".", inputTar())
+								}
+							}
+
+							repo.RegisterSource("some-input", inputSource)
+							repo.RegisterSource("some-other-input", otherInputSource)
+
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Inputs: []atc.TaskInputConfig{
+									{Name: "some-input", Files: []string{"some-dir/some-file", "top-file"}},
+									{Name: "some-other-input"},
+								},
+							}, nil)
+						})
+
+						It("streams only the declared files and their directories for that input", func() {
+							_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+							Expect(spec.Inputs).To(HaveLen(2))
+
+							for _, input := range spec.Inputs {
+								switch input.Name() {
+								case "some-input":
+									Expect(streamedNames(input.Source())).To(Equal([]string{
+										"./",
+										"./some-dir/",
+										"./some-dir/some-file",
+										"./top-file",
+									}))
+								case "some-other-input":
+									Expect(streamedNames(input.Source())).To(Equal([]string{
+										"./",
+										"./some-dir/",
+										"./other-dir/",
+										"./some-dir/some-file",
+										"./some-dir/other-file",
+										"./other-dir/some-file",
+										"./top-file",
+									}))
+								default:
+									panic("unknown input: " + input.Name())
+								}
+							}
+						})
+
+						It("still looks up the whole input's volume on workers", func() {
+							_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+
+							fakeVolume := new(workerfakes.FakeVolume)
+							inputSource.VolumeOnReturns(fakeVolume, true, nil)
+
+							for _, input := range spec.Inputs {
+								if input.Name() == "some-input" {
+									volume, found, err := input.Source().VolumeOn(new(workerfakes.FakeWorker))
+									Expect(err).NotTo(HaveOccurred())
+									Expect(found).To(BeTrue())
+									Expect(volume).To(Equal(fakeVolume))
+								}
+							}
+						})
+					})
+
+					Context("when the configuration specifies an input group", func() {
+						var certSource *workerfakes.FakeArtifactSource
+						var keySource *workerfakes.FakeArtifactSource
+
+						BeforeEach(func() {
+							certSource = new(workerfakes.FakeArtifactSource)
+							keySource = new(workerfakes.FakeArtifactSource)
+
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								InputGroups: []atc.TaskInputGroupConfig{
+									{
+										Name: "tls",
+										Inputs: []atc.TaskInputConfig{
+											{Name: "cert"},
+											{Name: "key", Path: "some-key-path"},
+										},
+									},
+								},
+							}, nil)
+						})
+
+						Context("when all of the group's inputs are present", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("cert", certSource)
+								repo.RegisterSource("key", keySource)
+							})
+
+							It("creates the container with all of the group's inputs", func() {
+								_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+								Expect(spec.Inputs).To(HaveLen(2))
+								Expect(spec.Inputs[0].Name()).To(Equal(worker.ArtifactName("cert")))
+								Expect(spec.Inputs[0].Source()).To(Equal(certSource))
+								Expect(spec.Inputs[0].DestinationPath()).To(Equal("/tmp/build/a1f5c0c1/cert"))
+								Expect(spec.Inputs[1].Name()).To(Equal(worker.ArtifactName("key")))
+								Expect(spec.Inputs[1].Source()).To(Equal(keySource))
+								Expect(spec.Inputs[1].DestinationPath()).To(Equal("/tmp/build/a1f5c0c1/some-key-path"))
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+							})
+						})
+
+						Context("when only some of the group's inputs are present", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("cert", certSource)
+							})
+
+							It("exits with failure", func() {
+								var err error
+								Eventually(process.Wait()).Should(Receive(&err))
+								Expect(err).To(Equal(IncompleteInputGroupError{
+									Group:         "tls",
+									MissingInputs: []string{"key"},
+								}))
+							})
+
+							It("does not create a container", func() {
+								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+								Expect(fakeWorkerClient.FindOrCreateBuildContainerCallCount()).To(BeZero())
+							})
+
+							It("invokes the delegate's Failed callback", func() {
+								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+								Expect(taskDelegate.FailedCallCount()).To(Equal(1))
+								Expect(taskDelegate.FailedArgsForCall(0)).To(BeAssignableToTypeOf(IncompleteInputGroupError{}))
+							})
+						})
+
+						Context("when none of the group's inputs are present", func() {
+							It("creates the container without the group", func() {
+								_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+								Expect(spec.Inputs).To(BeEmpty())
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+							})
+						})
+					})
+
+					Context("when input is remapped", func() {
+						var remappedInputSource *workerfakes.FakeArtifactSource
+
+						BeforeEach(func() {
+							remappedInputSource = new(workerfakes.FakeArtifactSource)
+							inputMapping = map[string]string{"remapped-input": "remapped-input-src"}
+
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Inputs: []atc.TaskInputConfig{
+									{Name: "remapped-input"},
+								},
+							}, nil)
+						})
+
+						Context("when all inputs are present in the in source repository", func() {
+							BeforeEach(func() {
+								repo.RegisterSource("remapped-input-src", remappedInputSource)
+							})
+
+							It("uses remapped input", func() {
+								_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+								Expect(spec.Inputs).To(HaveLen(1))
+								Expect(spec.Inputs[0].Name()).To(Equal(worker.ArtifactName("remapped-input-src")))
+								Expect(spec.Inputs[0].Source()).To(Equal(remappedInputSource))
+								Expect(spec.Inputs[0].DestinationPath()).To(Equal("/tmp/build/a1f5c0c1/remapped-input"))
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+							})
+						})
+
+						Context("when any of the inputs are missing", func() {
+							It("exits with failure", func() {
+								var err error
+								Eventually(process.Wait()).Should(Receive(&err))
+								Expect(err).To(BeAssignableToTypeOf(MissingInputsError{}))
+								Expect(err.(MissingInputsError).Inputs).To(ConsistOf("remapped-input-src"))
+							})
+
+							It("invokes the delegate's Failed callback", func() {
+								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+								Expect(taskDelegate.FailedCallCount()).To(Equal(1))
+
+								err := taskDelegate.FailedArgsForCall(0)
+								Expect(err).To(BeAssignableToTypeOf(MissingInputsError{}))
+								Expect(err.(MissingInputsError).Inputs).To(ConsistOf("remapped-input-src"))
+							})
+						})
+					})
+
+					Context("when an output is marked as carrying the task's result", func() {
+						var (
+							fakeResultVolume *workerfakes.FakeVolume
+							tarBuffer        *gbytes.Buffer
+						)
+
+						writeResult := func(content string) {
+							tarWriter := tar.NewWriter(tarBuffer)
+
+							err := tarWriter.WriteHeader(&tar.Header{
+								Name: "result.json",
+								Mode: 0644,
+								Size: int64(len(content)),
+							})
+							Expect(err).NotTo(HaveOccurred())
+
+							_, err = tarWriter.Write([]byte(content))
+							Expect(err).NotTo(HaveOccurred())
+						}
+
+						BeforeEach(func() {
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Outputs: []atc.TaskOutputConfig{
+									{Name: "some-result", Result: true},
+								},
+							}, nil)
+
+							tarBuffer = gbytes.NewBuffer()
+
+							fakeResultVolume = new(workerfakes.FakeVolume)
+							fakeResultVolume.StreamOutReturns(tarBuffer, nil)
+
+							fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+								{
+									Volume:    fakeResultVolume,
+									MountPath: "/tmp/build/a1f5c0c1/some-result/",
+								},
+							})
+
+							fakeProcess.WaitReturns(0, nil)
+						})
+
+						Context("when the output contains a valid result.json", func() {
+							BeforeEach(func() {
+								writeResult(`{"coverage": 87.5, "flaky": ["some-test"]}`)
+							})
+
+							It("saves the result via the delegate", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(fakeResultVolume.StreamOutArgsForCall(0)).To(Equal("result.json"))
+
+								Expect(taskDelegate.ResultDeterminedCallCount()).To(Equal(1))
+								output, result := taskDelegate.ResultDeterminedArgsForCall(0)
+								Expect(output).To(Equal("some-result"))
+								Expect(result).To(MatchJSON(`{"coverage":87.5,"flaky":["some-test"]}`))
+							})
+
+							Context("when saving the result fails", func() {
+								BeforeEach(func() {
+									taskDelegate.ResultDeterminedReturns(errors.New("nope"))
+								})
+
+								It("warns but still succeeds", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+									Expect(stderrBuf).To(gbytes.Say("WARNING: failed to save result of output 'some-result'"))
+								})
+							})
+						})
+
+						Context("when the output contains a malformed result.json", func() {
+							BeforeEach(func() {
+								writeResult(`["not", "an", "object"]`)
+							})
+
+							It("warns without saving the result or failing", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(taskDelegate.ResultDeterminedCallCount()).To(BeZero())
+								Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
+								Expect(stderrBuf).To(gbytes.Say("WARNING: ignoring result of output 'some-result': malformed result.json"))
+							})
+						})
+
+						Context("when the output does not contain a result.json", func() {
+							It("warns without saving the result or failing", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(taskDelegate.ResultDeterminedCallCount()).To(BeZero())
+								Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
+								Expect(stderrBuf).To(gbytes.Say("WARNING: ignoring result of output 'some-result': file not found: result.json"))
+							})
+						})
+					})
+
+					Context("when an output is marked as exporting env", func() {
+						var (
+							fakeEnvVolume *workerfakes.FakeVolume
+							tarBuffer     *gbytes.Buffer
+						)
+
+						writeEnv := func(content string) {
+							tarWriter := tar.NewWriter(tarBuffer)
+
+							err := tarWriter.WriteHeader(&tar.Header{
+								Name: "env",
+								Mode: 0644,
+								Size: int64(len(content)),
+							})
+							Expect(err).NotTo(HaveOccurred())
+
+							_, err = tarWriter.Write([]byte(content))
+							Expect(err).NotTo(HaveOccurred())
+						}
+
+						BeforeEach(func() {
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Outputs: []atc.TaskOutputConfig{
+									{Name: "some-env", Env: true},
+								},
+							}, nil)
+
+							tarBuffer = gbytes.NewBuffer()
+
+							fakeEnvVolume = new(workerfakes.FakeVolume)
+							fakeEnvVolume.StreamOutReturns(tarBuffer, nil)
+
+							fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+								{
+									Volume:    fakeEnvVolume,
+									MountPath: "/tmp/build/a1f5c0c1/some-env/",
+								},
+							})
+
+							fakeProcess.WaitReturns(0, nil)
+						})
+
+						Context("when the output contains a valid env file", func() {
+							BeforeEach(func() {
+								writeEnv("# some comment\nVERSION=1.2.3\n\nFLAGS=--foo=bar\n")
+							})
+
+							It("exports the variables via the delegate", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(fakeEnvVolume.StreamOutArgsForCall(0)).To(Equal("env"))
+
+								Expect(taskDelegate.EnvExportedCallCount()).To(Equal(1))
+								Expect(taskDelegate.EnvExportedArgsForCall(0)).To(Equal(map[string]string{
+									"VERSION": "1.2.3",
+									"FLAGS":   "--foo=bar",
+								}))
+							})
+
+							Context("when exporting the variables fails", func() {
+								BeforeEach(func() {
+									taskDelegate.EnvExportedReturns(errors.New("nope"))
+								})
+
+								It("warns but still succeeds", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+									Expect(stderrBuf).To(gbytes.Say("WARNING: failed to export env of output 'some-env'"))
+								})
+							})
+						})
+
+						Context("when the env file sets reserved variables", func() {
+							BeforeEach(func() {
+								writeEnv("BUILD_ID=123\nVERSION=1.2.3\n")
+							})
+
+							It("warns and exports only the other variables", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(stderrBuf).To(gbytes.Say("WARNING: not exporting reserved variable 'BUILD_ID'"))
+
+								Expect(taskDelegate.EnvExportedCallCount()).To(Equal(1))
+								Expect(taskDelegate.EnvExportedArgsForCall(0)).To(Equal(map[string]string{
+									"VERSION": "1.2.3",
+								}))
+							})
+						})
+
+						Context("when the env file only sets reserved variables", func() {
+							BeforeEach(func() {
+								writeEnv("BUILD_NAME=42\n")
+							})
+
+							It("exports nothing", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+								Expect(taskDelegate.EnvExportedCallCount()).To(BeZero())
+							})
+						})
+
+						Context("when the env file is malformed", func() {
+							BeforeEach(func() {
+								writeEnv("VERSION=1.2.3\nnot-an-assignment\n")
+							})
+
+							It("warns without exporting anything or failing", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								Expect(taskDelegate.EnvExportedCallCount()).To(BeZero())
+								Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
+								Expect(stderrBuf).To(gbytes.Say("WARNING: ignoring env of output 'some-env': malformed env: line 2 is not of the form KEY=value"))
+							})
+						})
+					})
+
+					Context("when the configuration specifies paths for outputs", func() {
+						BeforeEach(func() {
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Platform:  "some-platform",
+								RootFsUri: "some-image",
+								Params:    map[string]string{"SOME": "params"},
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+									Args: []string{"some", "args"},
+								},
+								Outputs: []atc.TaskOutputConfig{
+									{Name: "some-output", Path: "some-output-configured-path"},
+									{Name: "some-other-output"},
+									{Name: "some-trailing-slash-output", Path: "some-output-configured-path-with-trailing-slash/"},
+								},
+							}, nil)
+						})
+
+						It("configures them appropriately in the container spec", func() {
 							_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
 							Expect(spec.Outputs).To(Equal(worker.OutputPaths{
 								"some-output":                "/tmp/build/a1f5c0c1/some-output-configured-path/",
@@ -620,6 +1618,57 @@ var _ = Describe("GardenFactory", func() {
 						Context("when the process exits 0", func() {
 							BeforeEach(func() {
 								fakeProcess.WaitReturns(0, nil)
+
+								fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+									{
+										Volume:    new(workerfakes.FakeVolume),
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path/",
+									},
+									{
+										Volume:    new(workerfakes.FakeVolume),
+										MountPath: "/tmp/build/a1f5c0c1/some-other-output/",
+									},
+									{
+										Volume:    new(workerfakes.FakeVolume),
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+									},
+								})
+							})
+
+							Context("when an output's volume is not mounted", func() {
+								BeforeEach(func() {
+									fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+										{
+											Volume:    new(workerfakes.FakeVolume),
+											MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path/",
+										},
+										{
+											Volume:    new(workerfakes.FakeVolume),
+											MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+										},
+									})
+								})
+
+								It("exits with an error naming the output", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(OutputNotMountedError{
+										Outputs: []string{"some-other-output"},
+									})))
+								})
+
+								It("still registers the mounted outputs", func() {
+									Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+									_, found := repo.SourceFor("some-output")
+									Expect(found).To(BeTrue())
+
+									_, found = repo.SourceFor("some-other-output")
+									Expect(found).To(BeFalse())
+								})
+
+								It("does not invoke the delegate's Finished callback", func() {
+									Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+									Expect(taskDelegate.FinishedCallCount()).To(BeZero())
+								})
 							})
 
 							Describe("the registered sources", func() {
@@ -743,110 +1792,514 @@ var _ = Describe("GardenFactory", func() {
 											BeforeEach(func() {
 												tarWriter := tar.NewWriter(tarBuffer)
 
-												err := tarWriter.WriteHeader(&tar.Header{
-													Name: "some-file",
-													Mode: 0644,
-													Size: int64(len(fileContent)),
-												})
-												Expect(err).NotTo(HaveOccurred())
+												err := tarWriter.WriteHeader(&tar.Header{
+													Name: "some-file",
+													Mode: 0644,
+													Size: int64(len(fileContent)),
+												})
+												Expect(err).NotTo(HaveOccurred())
+
+												_, err = tarWriter.Write([]byte(fileContent))
+												Expect(err).NotTo(HaveOccurred())
+											})
+
+											It("streams out the given path", func() {
+												reader, err := artifactSource1.StreamFile("some-path")
+												Expect(err).NotTo(HaveOccurred())
+
+												Expect(ioutil.ReadAll(reader)).To(Equal([]byte(fileContent)))
+
+												path := fakeVolume1.StreamOutArgsForCall(0)
+												Expect(path).To(Equal("some-path"))
+											})
+
+											Describe("closing the stream", func() {
+												It("closes the stream from the versioned source", func() {
+													reader, err := artifactSource1.StreamFile("some-path")
+													Expect(err).NotTo(HaveOccurred())
+
+													Expect(tarBuffer.Closed()).To(BeFalse())
+
+													err = reader.Close()
+													Expect(err).NotTo(HaveOccurred())
+
+													Expect(tarBuffer.Closed()).To(BeTrue())
+												})
+											})
+										})
+
+										Context("but the stream is empty", func() {
+											It("returns ErrFileNotFound", func() {
+												_, err := artifactSource1.StreamFile("some-path")
+												Expect(err).To(MatchError(FileNotFoundError{Path: "some-path"}))
+											})
+										})
+									})
+
+									Context("when the volume cannot stream out", func() {
+										disaster := errors.New("nope")
+
+										BeforeEach(func() {
+											fakeVolume1.StreamOutReturns(nil, disaster)
+										})
+
+										It("returns the error", func() {
+											_, err := artifactSource1.StreamFile("some-path")
+											Expect(err).To(Equal(disaster))
+										})
+									})
+								})
+							})
+
+							Context("when saving the exit status succeeds", func() {
+								BeforeEach(func() {
+									fakeContainer.SetPropertyReturns(nil)
+								})
+
+								It("exits successfully", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+								})
+
+								It("invokes the delegate's Finished callback", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
+									Expect(taskDelegate.FinishedArgsForCall(0)).To(Equal(ExitStatus(0)))
+								})
+							})
+
+							Context("when saving the exit status fails", func() {
+								disaster := errors.New("nope")
+
+								BeforeEach(func() {
+									fakeContainer.SetPropertyStub = func(name string, value string) error {
+										defer GinkgoRecover()
+
+										if name == "concourse:exit-status" {
+											return disaster
+										}
+
+										return nil
+									}
+								})
+
+								It("exits with the error", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+								})
+
+								It("invokes the delegate's Failed callback", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+									Expect(taskDelegate.FailedCallCount()).To(Equal(1))
+									Expect(taskDelegate.FailedArgsForCall(0)).To(Equal(disaster))
+								})
+
+								It("does not invoke the delegate's Finished callback", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+									Expect(taskDelegate.FinishedCallCount()).To(Equal(0))
+								})
+							})
+						})
+
+						Context("when deduplicating outputs is enabled and an earlier step registered outputs", func() {
+							var (
+								earlierVolume *workerfakes.FakeVolume
+
+								identicalVolume *workerfakes.FakeVolume
+								differentVolume *workerfakes.FakeVolume
+								unhashedVolume  *workerfakes.FakeVolume
+
+								digests map[string]string
+							)
+
+							BeforeEach(func() {
+								factory = NewGardenFactory(fakeWorkerClient, new(resourcefakes.FakeFetcher), new(resourcefakes.FakeResourceFactory), fakeDBResourceCacheFactory, true)
+
+								fakeProcess.WaitReturns(0, nil)
+								fakeContainer.WorkerNameReturns("some-worker")
+
+								fakeContainer.RunStub = func(spec garden.ProcessSpec, processIO garden.ProcessIO) (garden.Process, error) {
+									if spec.ID == "task" {
+										return fakeProcess, nil
+									}
+
+									hashProcess := new(gardenfakes.FakeProcess)
+
+									digest, found := digests[spec.Args[len(spec.Args)-1]]
+									if !found {
+										hashProcess.WaitReturns(1, nil)
+										return hashProcess, nil
+									}
+
+									_, err := io.WriteString(processIO.Stdout, digest+"  -\n")
+									Expect(err).NotTo(HaveOccurred())
+
+									return hashProcess, nil
+								}
+
+								earlierVolume = new(workerfakes.FakeVolume)
+								earlierVolume.HandleReturns("earlier-handle")
+
+								otherEarlierVolume := new(workerfakes.FakeVolume)
+								otherEarlierVolume.HandleReturns("other-earlier-handle")
+
+								lastEarlierVolume := new(workerfakes.FakeVolume)
+								lastEarlierVolume.HandleReturns("last-earlier-handle")
+
+								fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+									{
+										Volume:    earlierVolume,
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path/",
+									},
+									{
+										Volume:    otherEarlierVolume,
+										MountPath: "/tmp/build/a1f5c0c1/some-other-output/",
+									},
+									{
+										Volume:    lastEarlierVolume,
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+									},
+								})
+
+								digests = map[string]string{
+									"/tmp/build/a1f5c0c1/some-output-configured-path/":                     strings.Repeat("a", 64),
+									"/tmp/build/a1f5c0c1/some-other-output/":                               strings.Repeat("b", 64),
+									"/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/": strings.Repeat("c", 64),
+								}
+
+								earlierStep := factory.Task(
+									lagertest.NewTestLogger("test"),
+									teamID,
+									1234,
+									atc.PlanID("some-earlier-plan-id"),
+									sourceName,
+									workerMetadata,
+									taskDelegate,
+									privileged,
+									tags,
+									configSource,
+									resourceTypes,
+									inputMapping,
+									map[string]string{
+										"some-output":                "earlier-output",
+										"some-other-output":          "other-earlier-output",
+										"some-trailing-slash-output": "last-earlier-output",
+									},
+									imageArtifactName,
+									retryableExitCodes,
+									scriptTimeout,
+									stopTimeout,
+									dir,
+									fakeClock,
+								).Using(inStep, repo)
+
+								Expect(earlierStep.Run(nil, make(chan struct{}))).To(Succeed())
+
+								identicalVolume = new(workerfakes.FakeVolume)
+								identicalVolume.HandleReturns("identical-handle")
+
+								differentVolume = new(workerfakes.FakeVolume)
+								differentVolume.HandleReturns("different-handle")
+
+								unhashedVolume = new(workerfakes.FakeVolume)
+								unhashedVolume.HandleReturns("unhashed-handle")
+
+								fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+									{
+										Volume:    identicalVolume,
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path/",
+									},
+									{
+										Volume:    differentVolume,
+										MountPath: "/tmp/build/a1f5c0c1/some-other-output/",
+									},
+									{
+										Volume:    unhashedVolume,
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+									},
+								})
+
+								digests = map[string]string{
+									"/tmp/build/a1f5c0c1/some-output-configured-path/": strings.Repeat("a", 64),
+									"/tmp/build/a1f5c0c1/some-other-output/":           strings.Repeat("d", 64),
+								}
+							})
+
+							It("hashes each output in the container rather than streaming it out", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								var hashedPaths []string
+								for i := 0; i < fakeContainer.RunCallCount(); i++ {
+									spec, _ := fakeContainer.RunArgsForCall(i)
+									if spec.ID == "task" {
+										continue
+									}
+
+									Expect(spec.Path).To(Equal("sh"))
+									Expect(spec.User).To(Equal("root"))
+									hashedPaths = append(hashedPaths, spec.Args[len(spec.Args)-1])
+								}
+
+								Expect(hashedPaths).To(ConsistOf(
+									"/tmp/build/a1f5c0c1/some-output-configured-path/",
+									"/tmp/build/a1f5c0c1/some-other-output/",
+									"/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+									"/tmp/build/a1f5c0c1/some-output-configured-path/",
+									"/tmp/build/a1f5c0c1/some-other-output/",
+									"/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+								))
+
+								Expect(identicalVolume.StreamOutCallCount()).To(BeZero())
+								Expect(earlierVolume.StreamOutCallCount()).To(BeZero())
+							})
+
+							It("registers an output with identical contents as a reference to the earlier one", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								source, found := repo.SourceFor("some-output")
+								Expect(found).To(BeTrue())
+
+								fakeWorker := new(workerfakes.FakeWorker)
+								fakeWorker.LookupVolumeReturns(earlierVolume, true, nil)
+
+								volume, found, err := source.VolumeOn(fakeWorker)
+								Expect(err).NotTo(HaveOccurred())
+								Expect(found).To(BeTrue())
+								Expect(volume).To(Equal(earlierVolume))
+
+								_, handle := fakeWorker.LookupVolumeArgsForCall(0)
+								Expect(handle).To(Equal("earlier-handle"))
+							})
+
+							It("streams the earlier output in place of an identical one", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								source, found := repo.SourceFor("some-output")
+								Expect(found).To(BeTrue())
+
+								fakeDestination := new(workerfakes.FakeArtifactDestination)
+								Expect(source.StreamTo(fakeDestination)).To(Succeed())
+
+								Expect(earlierVolume.StreamOutCallCount()).To(Equal(1))
+								Expect(identicalVolume.StreamOutCallCount()).To(BeZero())
+								Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+							})
+
+							It("registers outputs with differing contents or that could not be hashed as they are", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								fakeWorker := new(workerfakes.FakeWorker)
+
+								source, found := repo.SourceFor("some-other-output")
+								Expect(found).To(BeTrue())
+
+								_, _, err := source.VolumeOn(fakeWorker)
+								Expect(err).NotTo(HaveOccurred())
+
+								source, found = repo.SourceFor("some-trailing-slash-output")
+								Expect(found).To(BeTrue())
+
+								_, _, err = source.VolumeOn(fakeWorker)
+								Expect(err).NotTo(HaveOccurred())
+
+								Expect(fakeWorker.LookupVolumeCallCount()).To(Equal(2))
+								_, handle := fakeWorker.LookupVolumeArgsForCall(0)
+								Expect(handle).To(Equal("different-handle"))
+								_, handle = fakeWorker.LookupVolumeArgsForCall(1)
+								Expect(handle).To(Equal("unhashed-handle"))
+							})
+
+							It("keeps the earlier outputs", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								_, found := repo.SourceFor("earlier-output")
+								Expect(found).To(BeTrue())
+
+								_, found = repo.SourceFor("other-earlier-output")
+								Expect(found).To(BeTrue())
+
+								_, found = repo.SourceFor("last-earlier-output")
+								Expect(found).To(BeTrue())
+							})
+
+							Context("when the output is on a different worker than the earlier one", func() {
+								BeforeEach(func() {
+									fakeContainer.WorkerNameReturns("some-other-worker")
+								})
+
+								It("registers the output as it is", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									source, found := repo.SourceFor("some-output")
+									Expect(found).To(BeTrue())
+
+									fakeWorker := new(workerfakes.FakeWorker)
+
+									_, _, err := source.VolumeOn(fakeWorker)
+									Expect(err).NotTo(HaveOccurred())
+
+									_, handle := fakeWorker.LookupVolumeArgsForCall(0)
+									Expect(handle).To(Equal("identical-handle"))
+								})
+							})
+
+							Context("when deduplicating outputs is disabled for the later step", func() {
+								BeforeEach(func() {
+									factory = NewGardenFactory(fakeWorkerClient, new(resourcefakes.FakeFetcher), new(resourcefakes.FakeResourceFactory), fakeDBResourceCacheFactory, false)
+								})
+
+								It("only hashes the earlier step's outputs", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									hashed := 0
+									for i := 0; i < fakeContainer.RunCallCount(); i++ {
+										spec, _ := fakeContainer.RunArgsForCall(i)
+										if spec.ID != "task" {
+											hashed++
+										}
+									}
+
+									Expect(hashed).To(Equal(3))
+								})
+
+								It("registers the output as it is", func() {
+									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									source, found := repo.SourceFor("some-output")
+									Expect(found).To(BeTrue())
+
+									fakeWorker := new(workerfakes.FakeWorker)
+
+									_, _, err := source.VolumeOn(fakeWorker)
+									Expect(err).NotTo(HaveOccurred())
+
+									_, handle := fakeWorker.LookupVolumeArgsForCall(0)
+									Expect(handle).To(Equal("identical-handle"))
+								})
+							})
+						})
+
+						Context("when the task has a script timeout", func() {
+							var stopped chan struct{}
+
+							BeforeEach(func() {
+								scriptTimeout = "1m"
+
+								stopped = make(chan struct{})
+
+								fakeProcess.WaitStub = func() (int, error) {
+									<-stopped
+									return 128 + 15, nil
+								}
+
+								fakeContainer.StopStub = func(bool) error {
+									close(stopped)
+									return nil
+								}
+
+								fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+									{
+										Volume:    new(workerfakes.FakeVolume),
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path/",
+									},
+									{
+										Volume:    new(workerfakes.FakeVolume),
+										MountPath: "/tmp/build/a1f5c0c1/some-other-output/",
+									},
+									{
+										Volume:    new(workerfakes.FakeVolume),
+										MountPath: "/tmp/build/a1f5c0c1/some-output-configured-path-with-trailing-slash/",
+									},
+								})
+							})
 
-												_, err = tarWriter.Write([]byte(fileContent))
-												Expect(err).NotTo(HaveOccurred())
-											})
+							Context("when the script is still running once it elapses", func() {
+								JustBeforeEach(func() {
+									// the resource usage ticker and the timeout
+									Eventually(fakeClock.WatcherCount).Should(Equal(2))
+									fakeClock.Increment(time.Minute)
+								})
 
-											It("streams out the given path", func() {
-												reader, err := artifactSource1.StreamFile("some-path")
-												Expect(err).NotTo(HaveOccurred())
+								It("stops the container and exits with ErrTaskTimeout", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(ErrTaskTimeout)))
 
-												Expect(ioutil.ReadAll(reader)).To(Equal([]byte(fileContent)))
+									Expect(fakeContainer.StopCallCount()).To(Equal(1))
+									Expect(fakeContainer.StopArgsForCall(0)).To(BeFalse())
+								})
 
-												path := fakeVolume1.StreamOutArgsForCall(0)
-												Expect(path).To(Equal("some-path"))
-											})
+								It("registers the outputs produced so far", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(ErrTaskTimeout)))
 
-											Describe("closing the stream", func() {
-												It("closes the stream from the versioned source", func() {
-													reader, err := artifactSource1.StreamFile("some-path")
-													Expect(err).NotTo(HaveOccurred())
+									_, found := repo.SourceFor("some-output")
+									Expect(found).To(BeTrue())
 
-													Expect(tarBuffer.Closed()).To(BeFalse())
+									_, found = repo.SourceFor("some-other-output")
+									Expect(found).To(BeTrue())
 
-													err = reader.Close()
-													Expect(err).NotTo(HaveOccurred())
+									_, found = repo.SourceFor("some-trailing-slash-output")
+									Expect(found).To(BeTrue())
+								})
 
-													Expect(tarBuffer.Closed()).To(BeTrue())
-												})
-											})
-										})
+								It("does not finish the task", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(ErrTaskTimeout)))
 
-										Context("but the stream is empty", func() {
-											It("returns ErrFileNotFound", func() {
-												_, err := artifactSource1.StreamFile("some-path")
-												Expect(err).To(MatchError(FileNotFoundError{Path: "some-path"}))
-											})
-										})
-									})
+									Expect(taskDelegate.FinishedCallCount()).To(BeZero())
+								})
 
-									Context("when the volume cannot stream out", func() {
-										disaster := errors.New("nope")
+								It("is not successful", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(ErrTaskTimeout)))
 
-										BeforeEach(func() {
-											fakeVolume1.StreamOutReturns(nil, disaster)
-										})
+									var success Success
+									Expect(step.Result(&success)).To(BeTrue())
+									Expect(bool(success)).To(BeFalse())
+								})
 
-										It("returns the error", func() {
-											_, err := artifactSource1.StreamFile("some-path")
-											Expect(err).To(Equal(disaster))
-										})
-									})
+								It("stops the timer", func() {
+									Eventually(process.Wait()).Should(Receive(Equal(ErrTaskTimeout)))
+
+									Expect(fakeClock.WatcherCount()).To(BeZero())
 								})
 							})
 
-							Context("when saving the exit status succeeds", func() {
+							Context("when the script exits before it elapses", func() {
 								BeforeEach(func() {
-									fakeContainer.SetPropertyReturns(nil)
+									fakeProcess.WaitStub = nil
+									fakeProcess.WaitReturns(0, nil)
 								})
 
-								It("exits successfully", func() {
+								It("finishes the task without stopping the container", func() {
 									Eventually(process.Wait()).Should(Receive(BeNil()))
+
+									Expect(fakeContainer.StopCallCount()).To(BeZero())
+									Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
 								})
 
-								It("invokes the delegate's Finished callback", func() {
+								It("stops the timer", func() {
 									Eventually(process.Wait()).Should(Receive(BeNil()))
 
-									Expect(taskDelegate.FinishedCallCount()).To(Equal(1))
-									Expect(taskDelegate.FinishedArgsForCall(0)).To(Equal(ExitStatus(0)))
+									Expect(fakeClock.WatcherCount()).To(BeZero())
 								})
 							})
 
-							Context("when saving the exit status fails", func() {
-								disaster := errors.New("nope")
-
-								BeforeEach(func() {
-									fakeContainer.SetPropertyStub = func(name string, value string) error {
-										defer GinkgoRecover()
+							Context("when the process is interrupted", func() {
+								It("stops the timer", func() {
+									Eventually(fakeClock.WatcherCount).Should(Equal(2))
 
-										if name == "concourse:exit-status" {
-											return disaster
-										}
+									process.Signal(os.Interrupt)
+									Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
 
-										return nil
-									}
+									Expect(fakeClock.WatcherCount()).To(BeZero())
 								})
+							})
 
-								It("exits with the error", func() {
-									Eventually(process.Wait()).Should(Receive(Equal(disaster)))
+							Context("when the script timeout is not a duration", func() {
+								BeforeEach(func() {
+									scriptTimeout = "forever"
 								})
 
-								It("invokes the delegate's Failed callback", func() {
-									Eventually(process.Wait()).Should(Receive(Equal(disaster)))
-									Expect(taskDelegate.FailedCallCount()).To(Equal(1))
-									Expect(taskDelegate.FailedArgsForCall(0)).To(Equal(disaster))
-								})
+								It("exits with an error before creating the container", func() {
+									Eventually(process.Wait()).Should(Receive(HaveOccurred()))
 
-								It("does not invoke the delegate's Finished callback", func() {
-									Eventually(process.Wait()).Should(Receive(Equal(disaster)))
-									Expect(taskDelegate.FinishedCallCount()).To(Equal(0))
+									Expect(fakeWorkerClient.FindOrCreateBuildContainerCallCount()).To(BeZero())
 								})
 							})
 						})
@@ -876,6 +2329,70 @@ var _ = Describe("GardenFactory", func() {
 								Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
 							})
 
+							Context("when the task has a stop timeout", func() {
+								BeforeEach(func() {
+									stopTimeout = "10s"
+								})
+
+								Context("when the script ignores the graceful stop", func() {
+									BeforeEach(func() {
+										fakeContainer.StopStub = func(kill bool) error {
+											if kill {
+												close(stopped)
+											}
+
+											return nil
+										}
+									})
+
+									It("kills the container once the stop timeout elapses", func() {
+										process.Signal(os.Interrupt)
+
+										Eventually(fakeContainer.StopCallCount).Should(Equal(1))
+										Expect(fakeContainer.StopArgsForCall(0)).To(BeFalse())
+
+										// the resource usage ticker and the stop timeout
+										Eventually(fakeClock.WatcherCount).Should(Equal(2))
+										Consistently(process.Wait()).ShouldNot(Receive())
+
+										fakeClock.Increment(10 * time.Second)
+
+										Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
+										Expect(fakeContainer.StopCallCount()).To(Equal(2))
+										Expect(fakeContainer.StopArgsForCall(1)).To(BeTrue())
+									})
+								})
+
+								Context("when the script exits before the stop timeout elapses", func() {
+									It("does not kill the container", func() {
+										process.Signal(os.Interrupt)
+										Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
+
+										Expect(fakeContainer.StopCallCount()).To(Equal(1))
+										Expect(fakeContainer.StopArgsForCall(0)).To(BeFalse())
+									})
+
+									It("stops the timer", func() {
+										process.Signal(os.Interrupt)
+										Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
+
+										Expect(fakeClock.WatcherCount()).To(BeZero())
+									})
+								})
+
+								Context("when the stop timeout is not a duration", func() {
+									BeforeEach(func() {
+										stopTimeout = "forever"
+									})
+
+									It("exits with an error before creating the container", func() {
+										Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+										Expect(fakeWorkerClient.FindOrCreateBuildContainerCallCount()).To(BeZero())
+									})
+								})
+							})
+
 							Context("when container.stop returns an error", func() {
 								var disaster error
 
@@ -960,6 +2477,126 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when the configuration specifies a glob output", func() {
+						var fakeVolume *workerfakes.FakeVolume
+						var entries []string
+
+						BeforeEach(func() {
+							configSource.FetchConfigReturns(atc.TaskConfig{
+								Run: atc.TaskRunConfig{
+									Path: "ls",
+								},
+								Outputs: []atc.TaskOutputConfig{
+									{Name: "some-results", Path: "results/cell-*", Glob: true},
+								},
+							}, nil)
+
+							fakeProcess.WaitReturns(0, nil)
+
+							entries = []string{"./", "./cell-2/", "./cell-1/", "./cell-1/nested/", "./other/", "./cell-3"}
+
+							fakeVolume = new(workerfakes.FakeVolume)
+							fakeVolume.HandleReturns("some-handle")
+							fakeVolume.StreamOutStub = func(path string) (io.ReadCloser, error) {
+								tarBuffer := gbytes.NewBuffer()
+								tarWriter := tar.NewWriter(tarBuffer)
+
+								for _, entry := range entries {
+									header := &tar.Header{Name: entry, Typeflag: tar.TypeDir, Mode: 0755}
+									if !strings.HasSuffix(entry, "/") {
+										header = &tar.Header{Name: entry, Mode: 0644}
+									}
+
+									err := tarWriter.WriteHeader(header)
+									Expect(err).NotTo(HaveOccurred())
+								}
+
+								Expect(tarWriter.Close()).To(Succeed())
+
+								return tarBuffer, nil
+							}
+
+							fakeContainer.VolumeMountsReturns([]worker.VolumeMount{
+								{
+									Volume:    fakeVolume,
+									MountPath: "/tmp/build/a1f5c0c1/results/",
+								},
+							})
+						})
+
+						It("mounts the directory containing the matches as the output", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+							Expect(spec.Outputs).To(Equal(worker.OutputPaths{
+								"some-results": "/tmp/build/a1f5c0c1/results/",
+							}))
+						})
+
+						It("registers each matching directory as its own source", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							Expect(repo.AsMap()).To(HaveLen(2))
+
+							_, found := repo.SourceFor("some-results/cell-1")
+							Expect(found).To(BeTrue())
+
+							_, found = repo.SourceFor("some-results/cell-2")
+							Expect(found).To(BeTrue())
+						})
+
+						It("streams only the matching directory from each source", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							source, found := repo.SourceFor("some-results/cell-2")
+							Expect(found).To(BeTrue())
+
+							fakeDestination := new(workerfakes.FakeArtifactDestination)
+							Expect(source.StreamTo(fakeDestination)).To(Succeed())
+
+							Expect(fakeVolume.StreamOutArgsForCall(fakeVolume.StreamOutCallCount() - 1)).To(Equal("cell-2"))
+
+							Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+							dest, _ := fakeDestination.StreamInArgsForCall(0)
+							Expect(dest).To(Equal("."))
+						})
+
+						It("closes the stream when a file is not found in a matching directory", func() {
+							Eventually(process.Wait()).Should(Receive(BeNil()))
+
+							source, found := repo.SourceFor("some-results/cell-2")
+							Expect(found).To(BeTrue())
+
+							emptyStream := gbytes.NewBuffer()
+							fakeVolume.StreamOutStub = nil
+							fakeVolume.StreamOutReturns(emptyStream, nil)
+
+							_, err := source.StreamFile("some-file")
+							Expect(err).To(Equal(FileNotFoundError{Path: "some-file"}))
+
+							Expect(emptyStream.Closed()).To(BeTrue())
+						})
+
+						Context("when no directories match", func() {
+							BeforeEach(func() {
+								entries = []string{"./", "./other/", "./cell-3"}
+							})
+
+							It("exits with an error naming the output and its path", func() {
+								Eventually(process.Wait()).Should(Receive(Equal(NoGlobMatchesError{
+									Output: "some-results",
+									Path:   "results/cell-*",
+								})))
+							})
+
+							It("does not register any sources", func() {
+								Eventually(process.Wait()).Should(Receive(HaveOccurred()))
+
+								Expect(repo.AsMap()).To(BeEmpty())
+							})
+						})
+					})
+
 					Context("when output is remapped", func() {
 						var (
 							fakeMountPath string = "/tmp/build/a1f5c0c1/generic-remapped-output/"
@@ -1138,6 +2775,83 @@ var _ = Describe("GardenFactory", func() {
 						})
 					})
 
+					Context("when the step specifies a dir", func() {
+						BeforeEach(func() {
+							fetchedConfig.Run.Dir = "/some/dir"
+							fetchedConfig.Inputs = []atc.TaskInputConfig{
+								{Name: "some-input", Path: "some-input-path"},
+							}
+							fetchedConfig.Outputs = []atc.TaskOutputConfig{
+								{Name: "some-output"},
+							}
+							configSource.FetchConfigReturns(fetchedConfig, nil)
+
+							inputSource := new(workerfakes.FakeArtifactSource)
+							repo.RegisterSource("some-input", inputSource)
+
+							dir = "/workspace"
+							fakeContainer.StreamOutReturns(gbytes.NewBuffer(), nil)
+						})
+
+						It("runs the process from the dir instead of the run dir", func() {
+							spec, _ := fakeContainer.RunArgsForCall(0)
+							Expect(spec.Dir).To(Equal("/workspace"))
+						})
+
+						It("checks that the dir exists in the container", func() {
+							Expect(fakeContainer.StreamOutCallCount()).To(Equal(1))
+							Expect(fakeContainer.StreamOutArgsForCall(0)).To(Equal(garden.StreamOutSpec{
+								Path: "/workspace",
+								User: "root",
+							}))
+						})
+
+						It("leaves the inputs where they are", func() {
+							_, _, _, _, _, _, spec, _ := fakeWorkerClient.FindOrCreateBuildContainerArgsForCall(0)
+							Expect(spec.Dir).To(Equal("/tmp/build/a1f5c0c1"))
+							Expect(spec.Inputs).To(HaveLen(1))
+							Expect(spec.Inputs[0].DestinationPath()).To(Equal("/tmp/build/a1f5c0c1/some-input-path"))
+						})
+
+						Context("when the dir does not exist", func() {
+							BeforeEach(func() {
+								fakeContainer.StreamOutReturns(nil, errors.New("no such file"))
+							})
+
+							It("exits with TaskDirNotFoundError without running the process", func() {
+								Eventually(process.Wait()).Should(Receive(Equal(TaskDirNotFoundError{Dir: "/workspace"})))
+								Expect(fakeContainer.RunCallCount()).To(BeZero())
+							})
+						})
+
+						Context("when the dir is relative", func() {
+							BeforeEach(func() {
+								dir = "some-input-path/"
+							})
+
+							It("runs the process from within the artifacts root", func() {
+								spec, _ := fakeContainer.RunArgsForCall(0)
+								Expect(spec.Dir).To(Equal("/tmp/build/a1f5c0c1/some-input-path"))
+							})
+
+							It("does not check for the input's path in the container", func() {
+								Expect(fakeContainer.StreamOutCallCount()).To(BeZero())
+							})
+						})
+
+						Context("when the dir is an output's path", func() {
+							BeforeEach(func() {
+								dir = "/tmp/build/a1f5c0c1/some-output"
+							})
+
+							It("does not check for it in the container", func() {
+								spec, _ := fakeContainer.RunArgsForCall(0)
+								Expect(spec.Dir).To(Equal("/tmp/build/a1f5c0c1/some-output"))
+								Expect(fakeContainer.StreamOutCallCount()).To(BeZero())
+							})
+						})
+					})
+
 					Context("when a run user is specified", func() {
 						BeforeEach(func() {
 							fetchedConfig.Run.User = "some-user"
@@ -1273,6 +2987,43 @@ var _ = Describe("GardenFactory", func() {
 							Expect(status).To(Equal(ExitStatus(1)))
 						})
 
+						Context("when no retryable exit codes are configured", func() {
+							It("does not indicate whether it is retryable", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								var retryable Retryable
+								Expect(step.Result(&retryable)).To(BeFalse())
+							})
+						})
+
+						Context("when the exit status is one of the retryable exit codes", func() {
+							BeforeEach(func() {
+								retryableExitCodes = []int{1, 75}
+							})
+
+							It("is retryable", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								var retryable Retryable
+								Expect(step.Result(&retryable)).To(BeTrue())
+								Expect(bool(retryable)).To(BeTrue())
+							})
+						})
+
+						Context("when the exit status is not one of the retryable exit codes", func() {
+							BeforeEach(func() {
+								retryableExitCodes = []int{75}
+							})
+
+							It("is not retryable", func() {
+								Eventually(process.Wait()).Should(Receive(BeNil()))
+
+								var retryable Retryable
+								Expect(step.Result(&retryable)).To(BeTrue())
+								Expect(bool(retryable)).To(BeFalse())
+							})
+						})
+
 						Context("when saving the exit status succeeds", func() {
 							BeforeEach(func() {
 								fakeContainer.SetPropertyReturns(nil)
@@ -1413,6 +3164,31 @@ var _ = Describe("GardenFactory", func() {
 				})
 			})
 
+			Context("when the step is aborted while the image is being fetched", func() {
+				BeforeEach(func() {
+					fakeWorkerClient.FindOrCreateBuildContainerStub = func(
+						_ lager.Logger,
+						signals <-chan os.Signal,
+						_ worker.ImageFetchingDelegate,
+						_ int,
+						_ atc.PlanID,
+						_ dbng.ContainerMetadata,
+						_ worker.ContainerSpec,
+						_ atc.VersionedResourceTypes,
+					) (worker.Container, error) {
+						<-signals
+						return nil, resource.ErrInterrupted
+					}
+				})
+
+				It("forwards the abort to the image fetch and exits with ErrInterrupted", func() {
+					Eventually(fakeWorkerClient.FindOrCreateBuildContainerCallCount).Should(Equal(1))
+
+					process.Signal(os.Interrupt)
+					Eventually(process.Wait()).Should(Receive(Equal(ErrInterrupted)))
+				})
+			})
+
 			Context("when creating the container fails", func() {
 				disaster := errors.New("nope")
 
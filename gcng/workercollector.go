@@ -1,22 +1,27 @@
 package gcng
 
 import (
+	"time"
+
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc/dbng"
 )
 
 type workerCollector struct {
-	logger          lager.Logger
-	workerLifecycle dbng.WorkerLifecycle
+	logger                   lager.Logger
+	workerLifecycle          dbng.WorkerLifecycle
+	stalledWorkerGracePeriod time.Duration
 }
 
 func NewWorkerCollector(
 	logger lager.Logger,
 	workerLifecycle dbng.WorkerLifecycle,
+	stalledWorkerGracePeriod time.Duration,
 ) Collector {
 	return &workerCollector{
-		logger:          logger,
-		workerLifecycle: workerLifecycle,
+		logger:                   logger,
+		workerLifecycle:          workerLifecycle,
+		stalledWorkerGracePeriod: stalledWorkerGracePeriod,
 	}
 }
 
@@ -36,6 +41,18 @@ func (wc *workerCollector) Run() error {
 		logger.Debug("stalled", lager.Data{"count": len(affected), "workers": affected})
 	}
 
+	if wc.stalledWorkerGracePeriod > 0 {
+		affected, err = wc.workerLifecycle.DeleteUnresponsiveStalledWorkers(wc.stalledWorkerGracePeriod)
+		if err != nil {
+			logger.Error("failed-to-delete-unresponsive-stalled-workers", err)
+			return err
+		}
+
+		if len(affected) > 0 {
+			logger.Debug("deleted-stalled", lager.Data{"count": len(affected), "workers": affected})
+		}
+	}
+
 	affected, err = wc.workerLifecycle.DeleteFinishedRetiringWorkers()
 	if err != nil {
 		logger.Error("failed-to-delete-finished-retiring-workers", err)
@@ -1,6 +1,8 @@
 package gcng_test
 
 import (
+	"time"
+
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc/gcng"
 
@@ -15,20 +17,27 @@ var _ = Describe("WorkerCollector", func() {
 	var (
 		workerCollector     gcng.Collector
 		fakeWorkerLifecycle *dbngfakes.FakeWorkerLifecycle
+		gracePeriod         time.Duration
 	)
 
 	BeforeEach(func() {
-		logger := lagertest.NewTestLogger("volume-collector")
 		fakeWorkerLifecycle = new(dbngfakes.FakeWorkerLifecycle)
+		gracePeriod = 10 * time.Minute
+
+		fakeWorkerLifecycle.StallUnresponsiveWorkersReturns(nil, nil)
+		fakeWorkerLifecycle.DeleteUnresponsiveStalledWorkersReturns(nil, nil)
+		fakeWorkerLifecycle.DeleteFinishedRetiringWorkersReturns(nil, nil)
+		fakeWorkerLifecycle.LandFinishedLandingWorkersReturns(nil, nil)
+	})
+
+	JustBeforeEach(func() {
+		logger := lagertest.NewTestLogger("volume-collector")
 
 		workerCollector = gcng.NewWorkerCollector(
 			logger,
 			fakeWorkerLifecycle,
+			gracePeriod,
 		)
-
-		fakeWorkerLifecycle.StallUnresponsiveWorkersReturns(nil, nil)
-		fakeWorkerLifecycle.DeleteFinishedRetiringWorkersReturns(nil, nil)
-		fakeWorkerLifecycle.LandFinishedLandingWorkersReturns(nil, nil)
 	})
 
 	Describe("Run", func() {
@@ -39,6 +48,27 @@ var _ = Describe("WorkerCollector", func() {
 			Expect(fakeWorkerLifecycle.StallUnresponsiveWorkersCallCount()).To(Equal(1))
 		})
 
+		It("tells the worker factory to delete workers stalled past the grace period", func() {
+			err := workerCollector.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeWorkerLifecycle.DeleteUnresponsiveStalledWorkersCallCount()).To(Equal(1))
+			Expect(fakeWorkerLifecycle.DeleteUnresponsiveStalledWorkersArgsForCall(0)).To(Equal(10 * time.Minute))
+		})
+
+		Context("when the grace period is 0", func() {
+			BeforeEach(func() {
+				gracePeriod = 0
+			})
+
+			It("does not delete stalled workers", func() {
+				err := workerCollector.Run()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeWorkerLifecycle.DeleteUnresponsiveStalledWorkersCallCount()).To(BeZero())
+			})
+		})
+
 		It("tells the worker factory to delete finished retiring workers", func() {
 			err := workerCollector.Run()
 			Expect(err).NotTo(HaveOccurred())
@@ -61,6 +91,14 @@ var _ = Describe("WorkerCollector", func() {
 			Expect(err).To(MatchError(returnedErr))
 		})
 
+		It("returns an error if deleting stalled workers fails", func() {
+			returnedErr := errors.New("some-error")
+			fakeWorkerLifecycle.DeleteUnresponsiveStalledWorkersReturns(nil, returnedErr)
+
+			err := workerCollector.Run()
+			Expect(err).To(MatchError(returnedErr))
+		})
+
 		It("returns an error if deleting finished retiring workers fails", func() {
 			returnedErr := errors.New("some-error")
 			fakeWorkerLifecycle.DeleteFinishedRetiringWorkersReturns(nil, returnedErr)
@@ -190,6 +190,28 @@ func (event BuildFinished) Emit(logger lager.Logger) {
 	)
 }
 
+type EventStoreBacklog struct {
+	Backlog int
+	Limit   int
+}
+
+func (event EventStoreBacklog) Emit(logger lager.Logger) {
+	state := EventStateOK
+
+	if event.Limit > 0 && event.Backlog > event.Limit {
+		state = EventStateWarning
+	}
+
+	emit(
+		logger.Session("event-store-backlog"),
+		Event{
+			Name:  "event store backlog",
+			Value: event.Backlog,
+			State: state,
+		},
+	)
+}
+
 func ms(duration time.Duration) float64 {
 	return float64(duration) / 1000000
 }
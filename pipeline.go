@@ -1,15 +1,23 @@
 package atc
 
 type Pipeline struct {
-	ID       int          `json:"id"`
-	Name     string       `json:"name"`
-	URL      string       `json:"url"`
-	Paused   bool         `json:"paused"`
-	Public   bool         `json:"public"`
-	Groups   GroupConfigs `json:"groups,omitempty"`
-	TeamName string       `json:"team_name"`
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	URL         string       `json:"url"`
+	Paused      bool         `json:"paused"`
+	Public      bool         `json:"public"`
+	Archived    bool         `json:"archived"`
+	Groups      GroupConfigs `json:"groups,omitempty"`
+	TeamName    string       `json:"team_name"`
+	LastUpdated int64        `json:"last_updated,omitempty"`
 }
 
 type RenameRequest struct {
 	NewName string `json:"name"`
 }
+
+// PipelinesPausedState is returned when pausing or unpausing all of a team's
+// pipelines at once. Count is the number of pipelines whose state changed.
+type PipelinesPausedState struct {
+	Count int `json:"count"`
+}
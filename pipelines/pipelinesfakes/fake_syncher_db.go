@@ -9,14 +9,14 @@ import (
 )
 
 type FakeSyncherDB struct {
-	GetAllPipelinesStub        func() ([]db.SavedPipeline, error)
-	getAllPipelinesMutex       sync.RWMutex
-	getAllPipelinesArgsForCall []struct{}
-	getAllPipelinesReturns     struct {
+	GetAllActivePipelinesStub        func() ([]db.SavedPipeline, error)
+	getAllActivePipelinesMutex       sync.RWMutex
+	getAllActivePipelinesArgsForCall []struct{}
+	getAllActivePipelinesReturns     struct {
 		result1 []db.SavedPipeline
 		result2 error
 	}
-	getAllPipelinesReturnsOnCall map[int]struct {
+	getAllActivePipelinesReturnsOnCall map[int]struct {
 		result1 []db.SavedPipeline
 		result2 error
 	}
@@ -24,44 +24,44 @@ type FakeSyncherDB struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeSyncherDB) GetAllPipelines() ([]db.SavedPipeline, error) {
-	fake.getAllPipelinesMutex.Lock()
-	ret, specificReturn := fake.getAllPipelinesReturnsOnCall[len(fake.getAllPipelinesArgsForCall)]
-	fake.getAllPipelinesArgsForCall = append(fake.getAllPipelinesArgsForCall, struct{}{})
-	fake.recordInvocation("GetAllPipelines", []interface{}{})
-	fake.getAllPipelinesMutex.Unlock()
-	if fake.GetAllPipelinesStub != nil {
-		return fake.GetAllPipelinesStub()
+func (fake *FakeSyncherDB) GetAllActivePipelines() ([]db.SavedPipeline, error) {
+	fake.getAllActivePipelinesMutex.Lock()
+	ret, specificReturn := fake.getAllActivePipelinesReturnsOnCall[len(fake.getAllActivePipelinesArgsForCall)]
+	fake.getAllActivePipelinesArgsForCall = append(fake.getAllActivePipelinesArgsForCall, struct{}{})
+	fake.recordInvocation("GetAllActivePipelines", []interface{}{})
+	fake.getAllActivePipelinesMutex.Unlock()
+	if fake.GetAllActivePipelinesStub != nil {
+		return fake.GetAllActivePipelinesStub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getAllPipelinesReturns.result1, fake.getAllPipelinesReturns.result2
+	return fake.getAllActivePipelinesReturns.result1, fake.getAllActivePipelinesReturns.result2
 }
 
-func (fake *FakeSyncherDB) GetAllPipelinesCallCount() int {
-	fake.getAllPipelinesMutex.RLock()
-	defer fake.getAllPipelinesMutex.RUnlock()
-	return len(fake.getAllPipelinesArgsForCall)
+func (fake *FakeSyncherDB) GetAllActivePipelinesCallCount() int {
+	fake.getAllActivePipelinesMutex.RLock()
+	defer fake.getAllActivePipelinesMutex.RUnlock()
+	return len(fake.getAllActivePipelinesArgsForCall)
 }
 
-func (fake *FakeSyncherDB) GetAllPipelinesReturns(result1 []db.SavedPipeline, result2 error) {
-	fake.GetAllPipelinesStub = nil
-	fake.getAllPipelinesReturns = struct {
+func (fake *FakeSyncherDB) GetAllActivePipelinesReturns(result1 []db.SavedPipeline, result2 error) {
+	fake.GetAllActivePipelinesStub = nil
+	fake.getAllActivePipelinesReturns = struct {
 		result1 []db.SavedPipeline
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeSyncherDB) GetAllPipelinesReturnsOnCall(i int, result1 []db.SavedPipeline, result2 error) {
-	fake.GetAllPipelinesStub = nil
-	if fake.getAllPipelinesReturnsOnCall == nil {
-		fake.getAllPipelinesReturnsOnCall = make(map[int]struct {
+func (fake *FakeSyncherDB) GetAllActivePipelinesReturnsOnCall(i int, result1 []db.SavedPipeline, result2 error) {
+	fake.GetAllActivePipelinesStub = nil
+	if fake.getAllActivePipelinesReturnsOnCall == nil {
+		fake.getAllActivePipelinesReturnsOnCall = make(map[int]struct {
 			result1 []db.SavedPipeline
 			result2 error
 		})
 	}
-	fake.getAllPipelinesReturnsOnCall[i] = struct {
+	fake.getAllActivePipelinesReturnsOnCall[i] = struct {
 		result1 []db.SavedPipeline
 		result2 error
 	}{result1, result2}
@@ -70,8 +70,8 @@ func (fake *FakeSyncherDB) GetAllPipelinesReturnsOnCall(i int, result1 []db.Save
 func (fake *FakeSyncherDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.getAllPipelinesMutex.RLock()
-	defer fake.getAllPipelinesMutex.RUnlock()
+	fake.getAllActivePipelinesMutex.RLock()
+	defer fake.getAllActivePipelinesMutex.RUnlock()
 	return fake.invocations
 }
 
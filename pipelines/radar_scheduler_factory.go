@@ -28,17 +28,26 @@ type radarSchedulerFactory struct {
 	resourceFactory resource.ResourceFactory
 	interval        time.Duration
 	engine          engine.Engine
+	settings        dbng.SchedulerSettings
+	eventStore      scheduler.EventStore
+	maxBacklog      int
 }
 
 func NewRadarSchedulerFactory(
 	resourceFactory resource.ResourceFactory,
 	interval time.Duration,
 	engine engine.Engine,
+	settings dbng.SchedulerSettings,
+	eventStore scheduler.EventStore,
+	maxBacklog int,
 ) RadarSchedulerFactory {
 	return &radarSchedulerFactory{
 		resourceFactory: resourceFactory,
 		interval:        interval,
 		engine:          engine,
+		settings:        settings,
+		eventStore:      eventStore,
+		maxBacklog:      maxBacklog,
 	}
 }
 
@@ -73,6 +82,9 @@ func (rsf *radarSchedulerFactory) BuildScheduler(pipelineDB db.PipelineDB, dbPip
 			inputMapper,
 			rsf.engine,
 		),
-		Scanner: scanner,
+		Scanner:              scanner,
+		Settings:             rsf.settings,
+		EventStore:           rsf.eventStore,
+		MaxEventStoreBacklog: rsf.maxBacklog,
 	}
 }
@@ -12,7 +12,7 @@ import (
 //go:generate counterfeiter . SyncherDB
 
 type SyncherDB interface {
-	GetAllPipelines() ([]db.SavedPipeline, error)
+	GetAllActivePipelines() ([]db.SavedPipeline, error)
 }
 
 type PipelineRunnerFactory func(db.PipelineDB, dbng.Pipeline) ifrit.Runner
@@ -55,7 +55,7 @@ func NewSyncer(
 }
 
 func (syncer *Syncer) Sync() {
-	pipelines, err := syncer.syncherDB.GetAllPipelines()
+	pipelines, err := syncer.syncherDB.GetAllActivePipelines()
 	if err != nil {
 		syncer.logger.Error("failed-to-get-pipelines", err)
 		return
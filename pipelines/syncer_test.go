@@ -80,7 +80,7 @@ var _ = Describe("Pipelines Syncer", func() {
 			return <-exitChan
 		}
 
-		syncherDB.GetAllPipelinesReturns([]db.SavedPipeline{
+		syncherDB.GetAllActivePipelinesReturns([]db.SavedPipeline{
 			{
 				ID: 1,
 				Pipeline: db.Pipeline{
@@ -125,7 +125,7 @@ var _ = Describe("Pipelines Syncer", func() {
 			Eventually(fakeRunner.RunCallCount).Should(Equal(1))
 			Eventually(otherFakeRunner.RunCallCount).Should(Equal(1))
 
-			syncherDB.GetAllPipelinesReturns([]db.SavedPipeline{
+			syncherDB.GetAllActivePipelinesReturns([]db.SavedPipeline{
 				{
 					ID: 2,
 					Pipeline: db.Pipeline{
@@ -145,7 +145,7 @@ var _ = Describe("Pipelines Syncer", func() {
 				Eventually(fakeRunner.RunCallCount).Should(Equal(1))
 				Eventually(otherFakeRunner.RunCallCount).Should(Equal(1))
 
-				syncherDB.GetAllPipelinesReturns([]db.SavedPipeline{
+				syncherDB.GetAllActivePipelinesReturns([]db.SavedPipeline{
 					{
 						ID: 2,
 						Pipeline: db.Pipeline{
@@ -174,7 +174,7 @@ var _ = Describe("Pipelines Syncer", func() {
 				Eventually(fakeRunner.RunCallCount).Should(Equal(1))
 				Eventually(otherFakeRunner.RunCallCount).Should(Equal(1))
 
-				syncherDB.GetAllPipelinesReturns([]db.SavedPipeline{
+				syncherDB.GetAllActivePipelinesReturns([]db.SavedPipeline{
 					{
 						ID: 1,
 						Pipeline: db.Pipeline{
@@ -220,7 +220,7 @@ var _ = Describe("Pipelines Syncer", func() {
 			Eventually(fakeRunner.RunCallCount).Should(Equal(1))
 			Eventually(otherFakeRunner.RunCallCount).Should(Equal(1))
 
-			syncherDB.GetAllPipelinesReturns(pipelines, nil)
+			syncherDB.GetAllActivePipelinesReturns(pipelines, nil)
 
 			syncer.Sync()
 		})
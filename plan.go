@@ -16,6 +16,7 @@ type Plan struct {
 	DependentGet *DependentGetPlan `json:"dependent_get,omitempty"`
 	Timeout      *TimeoutPlan      `json:"timeout,omitempty"`
 	Retry        *RetryPlan        `json:"retry,omitempty"`
+	Staged       *StagedPlan       `json:"staged,omitempty"`
 }
 
 type PlanID string
@@ -68,6 +69,12 @@ type TryPlan struct {
 	Step Plan `json:"step"`
 }
 
+// StagedPlan holds back the artifacts produced by its step from the rest of
+// the build until the step has succeeded.
+type StagedPlan struct {
+	Step Plan `json:"step"`
+}
+
 type AggregatePlan []Plan
 
 type DoPlan []Plan
@@ -109,6 +116,23 @@ type TaskPlan struct {
 	OutputMapping     map[string]string `json:"output_mapping,omitempty"`
 	ImageArtifactName string            `json:"image,omitempty"`
 
+	// RetryableExitCodes limits retrying the task to when it exits with one of
+	// these codes. If empty, the task is retried on any failure.
+	RetryableExitCodes []int `json:"retryable_exit_codes,omitempty"`
+
+	// ScriptTimeout limits how long the task's script may run, not counting
+	// the time spent fetching its image or streaming its inputs.
+	ScriptTimeout string `json:"script_timeout,omitempty"`
+
+	// StopTimeout is how long the task's script is given to exit once it has
+	// been asked to stop before it is killed. If empty, it is never killed.
+	StopTimeout string `json:"stop_timeout,omitempty"`
+
+	// Dir is the working directory of the task's script. A relative path is
+	// relative to the directory the inputs are placed in. If empty, the
+	// task's own run.dir is used.
+	Dir string `json:"dir,omitempty"`
+
 	VersionedResourceTypes VersionedResourceTypes `json:"resource_types,omitempty"`
 }
 
@@ -116,6 +116,8 @@ func (runner *Runner) OpenConn() dbng.Conn {
 		lagertest.NewTestLogger("postgres-runner"),
 		"postgres",
 		runner.DataSourceName(),
+		"",
+		dbng.DefaultRetryPolicy,
 	)
 	Expect(err).NotTo(HaveOccurred())
 
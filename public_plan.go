@@ -18,6 +18,7 @@ func (plan Plan) Public() *json.RawMessage {
 		DependentGet *json.RawMessage `json:"dependent_get,omitempty"`
 		Timeout      *json.RawMessage `json:"timeout,omitempty"`
 		Retry        *json.RawMessage `json:"retry,omitempty"`
+		Staged       *json.RawMessage `json:"staged,omitempty"`
 	}
 
 	public.ID = plan.ID
@@ -189,6 +190,14 @@ func (plan TryPlan) Public() *json.RawMessage {
 	})
 }
 
+func (plan StagedPlan) Public() *json.RawMessage {
+	return enc(struct {
+		Step *json.RawMessage `json:"step"`
+	}{
+		Step: plan.Step.Public(),
+	})
+}
+
 func (plan RetryPlan) Public() *json.RawMessage {
 	public := make([]*json.RawMessage, len(plan))
 
@@ -16,7 +16,7 @@ type ResourceInstance interface {
 	ResourceUser() dbng.ResourceUser
 
 	FindInitializedOn(lager.Logger, worker.Client) (worker.Volume, bool, error)
-	CreateOn(lager.Logger, worker.Client) (worker.Volume, error)
+	CreateOn(lager.Logger, worker.Client, worker.WorkerSpec) (worker.Volume, error)
 
 	ResourceCacheIdentifier() worker.ResourceCacheIdentifier
 }
@@ -55,7 +55,9 @@ func (instance resourceInstance) ResourceUser() dbng.ResourceUser {
 	return instance.resourceUser
 }
 
-func (instance resourceInstance) CreateOn(logger lager.Logger, workerClient worker.Client) (worker.Volume, error) {
+// CreateOn creates the cache volume on a worker from workerClient satisfying
+// workerSpec. The spec's resource type is always the instance's.
+func (instance resourceInstance) CreateOn(logger lager.Logger, workerClient worker.Client, workerSpec worker.WorkerSpec) (worker.Volume, error) {
 	resourceCache, err := instance.dbResourceCacheFactory.FindOrCreateResourceCache(
 		logger,
 		instance.resourceUser,
@@ -69,8 +71,12 @@ func (instance resourceInstance) CreateOn(logger lager.Logger, workerClient work
 		return nil, err
 	}
 
+	workerSpec.ResourceType = string(instance.resourceTypeName)
+
 	return workerClient.CreateVolumeForResourceCache(
 		logger,
+		workerSpec,
+		instance.resourceTypes,
 		worker.VolumeSpec{
 			Strategy:   baggageclaim.EmptyStrategy{},
 			Privileged: true,
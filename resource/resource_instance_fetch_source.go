@@ -89,7 +89,10 @@ func (s *resourceInstanceFetchSource) Initialize(signals <-chan os.Signal, ready
 		return nil
 	}
 
-	volume, err = s.resourceInstance.CreateOn(sLog, s.worker)
+	volume, err = s.resourceInstance.CreateOn(sLog, s.worker, worker.WorkerSpec{
+		Tags:   s.tags,
+		TeamID: s.teamID,
+	})
 	if err != nil {
 		sLog.Error("failed-to-create-cache", err)
 		return err
@@ -134,8 +134,10 @@ var _ = Describe("VolumeFetchSource", func() {
 			It("creates volume for resource instance on provided worker", func() {
 				Expect(initErr).NotTo(HaveOccurred())
 				Expect(fakeResourceInstance.CreateOnCallCount()).To(Equal(1))
-				_, worker := fakeResourceInstance.CreateOnArgsForCall(0)
+				_, worker, workerSpec := fakeResourceInstance.CreateOnArgsForCall(0)
 				Expect(worker).To(Equal(fakeWorker))
+				Expect(workerSpec.Tags).To(Equal([]string{}))
+				Expect(workerSpec.TeamID).To(Equal(42))
 			})
 
 			It("creates container with volume and worker", func() {
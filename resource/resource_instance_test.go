@@ -114,7 +114,10 @@ var _ = Describe("ResourceInstance", func() {
 		var createErr error
 
 		JustBeforeEach(func() {
-			createdVolume, createErr = resourceInstance.CreateOn(logger, fakeWorkerClient)
+			createdVolume, createErr = resourceInstance.CreateOn(logger, fakeWorkerClient, worker.WorkerSpec{
+				Tags:   []string{"some-tag"},
+				TeamID: 42,
+			})
 		})
 
 		Context("when creating the volume succeeds", func() {
@@ -133,8 +136,18 @@ var _ = Describe("ResourceInstance", func() {
 				Expect(createdVolume).To(Equal(volume))
 			})
 
+			It("creates it on a worker for the resource type", func() {
+				_, workerSpec, resourceTypes, _, _ := fakeWorkerClient.CreateVolumeForResourceCacheArgsForCall(0)
+				Expect(workerSpec).To(Equal(worker.WorkerSpec{
+					ResourceType: "some-resource-type",
+					Tags:         []string{"some-tag"},
+					TeamID:       42,
+				}))
+				Expect(resourceTypes).To(Equal(atc.VersionedResourceTypes{}))
+			})
+
 			It("created with the right strategy and privileges", func() {
-				_, spec, _ := fakeWorkerClient.CreateVolumeForResourceCacheArgsForCall(0)
+				_, _, _, spec, _ := fakeWorkerClient.CreateVolumeForResourceCacheArgsForCall(0)
 				Expect(spec).To(Equal(worker.VolumeSpec{
 					Strategy:   baggageclaim.EmptyStrategy{},
 					Privileged: true,
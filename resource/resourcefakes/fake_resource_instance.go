@@ -36,11 +36,12 @@ type FakeResourceInstance struct {
 		result2 bool
 		result3 error
 	}
-	CreateOnStub        func(lager.Logger, worker.Client) (worker.Volume, error)
+	CreateOnStub        func(lager.Logger, worker.Client, worker.WorkerSpec) (worker.Volume, error)
 	createOnMutex       sync.RWMutex
 	createOnArgsForCall []struct {
 		arg1 lager.Logger
 		arg2 worker.Client
+		arg3 worker.WorkerSpec
 	}
 	createOnReturns struct {
 		result1 worker.Volume
@@ -158,17 +159,18 @@ func (fake *FakeResourceInstance) FindInitializedOnReturnsOnCall(i int, result1
 	}{result1, result2, result3}
 }
 
-func (fake *FakeResourceInstance) CreateOn(arg1 lager.Logger, arg2 worker.Client) (worker.Volume, error) {
+func (fake *FakeResourceInstance) CreateOn(arg1 lager.Logger, arg2 worker.Client, arg3 worker.WorkerSpec) (worker.Volume, error) {
 	fake.createOnMutex.Lock()
 	ret, specificReturn := fake.createOnReturnsOnCall[len(fake.createOnArgsForCall)]
 	fake.createOnArgsForCall = append(fake.createOnArgsForCall, struct {
 		arg1 lager.Logger
 		arg2 worker.Client
-	}{arg1, arg2})
-	fake.recordInvocation("CreateOn", []interface{}{arg1, arg2})
+		arg3 worker.WorkerSpec
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("CreateOn", []interface{}{arg1, arg2, arg3})
 	fake.createOnMutex.Unlock()
 	if fake.CreateOnStub != nil {
-		return fake.CreateOnStub(arg1, arg2)
+		return fake.CreateOnStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -182,10 +184,10 @@ func (fake *FakeResourceInstance) CreateOnCallCount() int {
 	return len(fake.createOnArgsForCall)
 }
 
-func (fake *FakeResourceInstance) CreateOnArgsForCall(i int) (lager.Logger, worker.Client) {
+func (fake *FakeResourceInstance) CreateOnArgsForCall(i int) (lager.Logger, worker.Client, worker.WorkerSpec) {
 	fake.createOnMutex.RLock()
 	defer fake.createOnMutex.RUnlock()
-	return fake.createOnArgsForCall[i].arg1, fake.createOnArgsForCall[i].arg2
+	return fake.createOnArgsForCall[i].arg1, fake.createOnArgsForCall[i].arg2, fake.createOnArgsForCall[i].arg3
 }
 
 func (fake *FakeResourceInstance) CreateOnReturns(result1 worker.Volume, result2 error) {
@@ -5,3 +5,10 @@ type Source map[string]interface{}
 type Params map[string]interface{}
 
 type Version map[string]string
+
+// VersionWithMetadata is a version of a resource along with the metadata
+// describing it, e.g. the author and message of a git commit.
+type VersionWithMetadata struct {
+	Version  Version         `json:"version"`
+	Metadata []MetadataField `json:"metadata,omitempty"`
+}
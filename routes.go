@@ -11,6 +11,7 @@ const (
 	CreateBuild         = "CreateBuild"
 	ListBuilds          = "ListBuilds"
 	BuildEvents         = "BuildEvents"
+	BuildStepEvents     = "BuildStepEvents"
 	BuildResources      = "BuildResources"
 	AbortBuild          = "AbortBuild"
 	GetBuildPreparation = "GetBuildPreparation"
@@ -21,6 +22,7 @@ const (
 	ListJobBuilds  = "ListJobBuilds"
 	ListJobInputs  = "ListJobInputs"
 	GetJobBuild    = "GetJobBuild"
+	RerunJobBuild  = "RerunJobBuild"
 	PauseJob       = "PauseJob"
 	UnpauseJob     = "UnpauseJob"
 	GetVersionsDB  = "GetVersionsDB"
@@ -40,16 +42,18 @@ const (
 	ListBuildsWithVersionAsInput  = "ListBuildsWithVersionAsInput"
 	ListBuildsWithVersionAsOutput = "ListBuildsWithVersionAsOutput"
 
-	ListAllPipelines = "ListAllPipelines"
-	ListPipelines    = "ListPipelines"
-	GetPipeline      = "GetPipeline"
-	DeletePipeline   = "DeletePipeline"
-	OrderPipelines   = "OrderPipelines"
-	PausePipeline    = "PausePipeline"
-	UnpausePipeline  = "UnpausePipeline"
-	ExposePipeline   = "ExposePipeline"
-	HidePipeline     = "HidePipeline"
-	RenamePipeline   = "RenamePipeline"
+	ListAllPipelines    = "ListAllPipelines"
+	ListPipelines       = "ListPipelines"
+	GetPipeline         = "GetPipeline"
+	DeletePipeline      = "DeletePipeline"
+	OrderPipelines      = "OrderPipelines"
+	PauseAllPipelines   = "PauseAllPipelines"
+	UnpauseAllPipelines = "UnpauseAllPipelines"
+	PausePipeline       = "PausePipeline"
+	UnpausePipeline     = "UnpausePipeline"
+	ExposePipeline      = "ExposePipeline"
+	HidePipeline        = "HidePipeline"
+	RenamePipeline      = "RenamePipeline"
 
 	CreatePipe = "CreatePipe"
 	WritePipe  = "WritePipe"
@@ -93,6 +97,7 @@ var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/builds/:build_id", Method: "GET", Name: GetBuild},
 	{Path: "/api/v1/builds/:build_id/plan", Method: "GET", Name: GetBuildPlan},
 	{Path: "/api/v1/builds/:build_id/events", Method: "GET", Name: BuildEvents},
+	{Path: "/api/v1/builds/:build_id/steps/:step_name/events", Method: "GET", Name: BuildStepEvents},
 	{Path: "/api/v1/builds/:build_id/resources", Method: "GET", Name: BuildResources},
 	{Path: "/api/v1/builds/:build_id/abort", Method: "PUT", Name: AbortBuild},
 	{Path: "/api/v1/builds/:build_id/preparation", Method: "GET", Name: GetBuildPreparation},
@@ -103,6 +108,7 @@ var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds", Method: "POST", Name: CreateJobBuild},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/inputs", Method: "GET", Name: ListJobInputs},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name", Method: "GET", Name: GetJobBuild},
+	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/builds/:build_name/rerun", Method: "POST", Name: RerunJobBuild},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/pause", Method: "PUT", Name: PauseJob},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/unpause", Method: "PUT", Name: UnpauseJob},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/badge", Method: "GET", Name: JobBadge},
@@ -113,6 +119,8 @@ var Routes = rata.Routes([]rata.Route{
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name", Method: "GET", Name: GetPipeline},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name", Method: "DELETE", Name: DeletePipeline},
 	{Path: "/api/v1/teams/:team_name/pipelines/ordering", Method: "PUT", Name: OrderPipelines},
+	{Path: "/api/v1/teams/:team_name/pipelines/pause", Method: "PUT", Name: PauseAllPipelines},
+	{Path: "/api/v1/teams/:team_name/pipelines/unpause", Method: "PUT", Name: UnpauseAllPipelines},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/pause", Method: "PUT", Name: PausePipeline},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/unpause", Method: "PUT", Name: UnpausePipeline},
 	{Path: "/api/v1/teams/:team_name/pipelines/:pipeline_name/expose", Method: "PUT", Name: ExposePipeline},
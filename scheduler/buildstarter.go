@@ -96,7 +96,22 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, nil
 	}
 
-	if nextPendingBuild.IsManuallyTriggered() {
+	overrides := nextPendingBuild.Overrides()
+	if !overrides.IsEmpty() {
+		jobConfig, err = overrides.Apply(jobConfig)
+		if err != nil {
+			// the job has changed since the build was triggered
+			logger.Error("failed-to-apply-overrides", err)
+
+			err := nextPendingBuild.Finish(dbng.BuildStatusErrored)
+			if err != nil {
+				logger.Error("failed-to-mark-build-as-errored", err)
+			}
+			return false, nil
+		}
+	}
+
+	if nextPendingBuild.IsManuallyTriggered() && !nextPendingBuild.InputsPinned() {
 		jobBuildInputs := config.JobInputs(jobConfig)
 		for _, input := range jobBuildInputs {
 			scanLog := logger.Session("scan", lager.Data{
@@ -116,19 +131,55 @@ func (s *buildStarter) tryStartNextPendingBuild(
 			return false, err
 		}
 
-		_, err = s.inputMapper.SaveNextInputMapping(logger, versions, jobConfig)
-		if err != nil {
-			return false, err
+		if len(overrides.Versions) > 0 {
+			// versions pinned for this build alone must not become the job's
+			// next inputs, so they are resolved and kept with the build
+			inputMapping, resolved, err := s.inputMapper.ResolveInputMapping(logger, versions, jobConfig)
+			if err != nil {
+				return false, err
+			}
+			if !resolved {
+				// the inputs were just checked, so a pinned version that
+				// cannot be resolved now is never going to be
+				logger.Info("pinned-inputs-not-resolved")
+
+				err := nextPendingBuild.Finish(dbng.BuildStatusErrored)
+				if err != nil {
+					logger.Error("failed-to-mark-build-as-errored", err)
+				}
+				return false, nil
+			}
+
+			err = nextPendingBuild.PinInputs(inputMapping)
+			if err != nil {
+				logger.Error("failed-to-pin-build-inputs", err)
+				return false, err
+			}
+		} else {
+			_, err = s.inputMapper.SaveNextInputMapping(logger, versions, jobConfig)
+			if err != nil {
+				return false, err
+			}
 		}
 	}
 
-	buildInputs, found, err := s.pipeline.GetNextBuildInputs(nextPendingBuild.JobName())
-	if err != nil {
-		logger.Error("failed-to-get-next-build-inputs", err)
-		return false, err
-	}
-	if !found {
-		return false, nil
+	var buildInputs []dbng.BuildInput
+	if nextPendingBuild.InputsPinned() {
+		buildInputs, _, err = nextPendingBuild.Resources()
+		if err != nil {
+			logger.Error("failed-to-get-pinned-build-inputs", err)
+			return false, err
+		}
+	} else {
+		var found bool
+		buildInputs, found, err = s.pipeline.GetNextBuildInputs(nextPendingBuild.JobName())
+		if err != nil {
+			logger.Error("failed-to-get-next-build-inputs", err)
+			return false, err
+		}
+		if !found {
+			return false, nil
+		}
 	}
 
 	pipelinePaused, err := s.pipeline.CheckPaused()
@@ -140,6 +191,10 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, nil
 	}
 
+	if s.pipeline.Archived() {
+		return false, nil
+	}
+
 	job, found, err := s.pipeline.Job(nextPendingBuild.JobName())
 	if err != nil {
 		logger.Error("failed-to-check-if-job-is-paused", err)
@@ -164,9 +219,11 @@ func (s *buildStarter) tryStartNextPendingBuild(
 		return false, nil
 	}
 
-	err = nextPendingBuild.UseInputs(buildInputs)
-	if err != nil {
-		return false, err
+	if !nextPendingBuild.InputsPinned() {
+		err = nextPendingBuild.UseInputs(buildInputs)
+		if err != nil {
+			return false, err
+		}
 	}
 
 	plan, err := s.factory.Create(jobConfig, resourceConfigs, resourceTypes, buildInputs)
@@ -228,6 +228,114 @@ var _ = Describe("I'm a BuildStarter", func() {
 						})
 					})
 				})
+
+				Context("when the build was triggered with pinned versions", func() {
+					var inputMapping algorithm.InputMapping
+
+					BeforeEach(func() {
+						createdBuild.OverridesReturns(atc.BuildOverrides{
+							Versions: map[string]atc.Version{"input-1": {"ref": "abc"}},
+						})
+
+						inputMapping = algorithm.InputMapping{
+							"input-1": algorithm.InputVersion{VersionID: 1},
+							"input-2": algorithm.InputVersion{VersionID: 2},
+						}
+					})
+
+					It("resolves the inputs with the versions pinned", func() {
+						Expect(fakeInputMapper.ResolveInputMappingCallCount()).To(Equal(1))
+						_, _, actualJobConfig := fakeInputMapper.ResolveInputMappingArgsForCall(0)
+						Expect(actualJobConfig).To(Equal(atc.JobConfig{
+							Name: "some-job",
+							Plan: atc.PlanSequence{
+								{Get: "input-1", Version: &atc.VersionConfig{Pinned: atc.Version{"ref": "abc"}}},
+								{Get: "input-2"},
+							},
+						}))
+					})
+
+					It("does not save them as the job's next inputs", func() {
+						Expect(fakeInputMapper.SaveNextInputMappingCallCount()).To(BeZero())
+					})
+
+					Context("when the inputs resolve", func() {
+						BeforeEach(func() {
+							fakeInputMapper.ResolveInputMappingReturns(inputMapping, true, nil)
+							createdBuild.PinInputsStub = func(algorithm.InputMapping) error {
+								createdBuild.InputsPinnedReturns(true)
+								return nil
+							}
+						})
+
+						It("pins them to the build", func() {
+							Expect(createdBuild.PinInputsCallCount()).To(Equal(1))
+							Expect(createdBuild.PinInputsArgsForCall(0)).To(Equal(inputMapping))
+						})
+
+						It("uses the build's own inputs rather than the job's next inputs", func() {
+							Expect(createdBuild.ResourcesCallCount()).To(Equal(1))
+							Expect(fakePipeline.GetNextBuildInputsCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when the inputs do not resolve", func() {
+						BeforeEach(func() {
+							fakeInputMapper.ResolveInputMappingReturns(nil, false, nil)
+						})
+
+						It("marks the build as errored rather than leaving it pending", func() {
+							Expect(tryStartErr).NotTo(HaveOccurred())
+							Expect(createdBuild.PinInputsCallCount()).To(BeZero())
+							Expect(createdBuild.ScheduleCallCount()).To(BeZero())
+
+							Expect(createdBuild.FinishCallCount()).To(Equal(1))
+							Expect(createdBuild.FinishArgsForCall(0)).To(Equal(dbng.BuildStatusErrored))
+						})
+					})
+
+					Context("when resolving the inputs fails", func() {
+						BeforeEach(func() {
+							fakeInputMapper.ResolveInputMappingReturns(nil, false, disaster)
+						})
+
+						It("returns the error", func() {
+							Expect(tryStartErr).To(Equal(disaster))
+						})
+					})
+				})
+
+				Context("when the build's inputs are already pinned", func() {
+					BeforeEach(func() {
+						createdBuild.InputsPinnedReturns(true)
+					})
+
+					It("does not check or map the inputs again", func() {
+						Expect(fakeScanner.ScanCallCount()).To(BeZero())
+						Expect(fakeInputMapper.SaveNextInputMappingCallCount()).To(BeZero())
+						Expect(fakeInputMapper.ResolveInputMappingCallCount()).To(BeZero())
+					})
+
+					It("uses the build's own inputs", func() {
+						Expect(createdBuild.ResourcesCallCount()).To(Equal(1))
+						Expect(fakePipeline.GetNextBuildInputsCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the build's overrides name a step that is no longer in the job", func() {
+					BeforeEach(func() {
+						createdBuild.OverridesReturns(atc.BuildOverrides{
+							Params: map[string]atc.Params{"removed-step": {"some": "param"}},
+						})
+					})
+
+					It("errors the build without starting it", func() {
+						Expect(tryStartErr).NotTo(HaveOccurred())
+						Expect(createdBuild.FinishCallCount()).To(Equal(1))
+						Expect(createdBuild.FinishArgsForCall(0)).To(Equal(dbng.BuildStatusErrored))
+						Expect(fakeScanner.ScanCallCount()).To(BeZero())
+					})
+				})
 			})
 		})
 
@@ -553,6 +661,15 @@ var _ = Describe("I'm a BuildStarter", func() {
 						itUpdatedMaxInFlightForTheFirstBuild()
 					})
 
+					Context("when the pipeline is archived", func() {
+						BeforeEach(func() {
+							fakePipeline.ArchivedReturns(true)
+						})
+
+						itDoesntReturnAnErrorOrMarkTheBuildAsScheduled()
+						itUpdatedMaxInFlightForTheFirstBuild()
+					})
+
 					Context("when getting the job fails", func() {
 						BeforeEach(func() {
 							fakePipeline.JobReturns(nil, false, disaster)
@@ -236,6 +236,11 @@ func (factory *buildFactory) constructUnhookedPlan(
 			OutputMapping:     planConfig.OutputMapping,
 			ImageArtifactName: planConfig.ImageArtifactName,
 
+			RetryableExitCodes: planConfig.RetryableExitCodes,
+			ScriptTimeout:      planConfig.ScriptTimeout,
+			StopTimeout:        planConfig.StopTimeout,
+			Dir:                planConfig.Dir,
+
 			VersionedResourceTypes: resourceTypes,
 		})
 	case planConfig.Try != nil:
@@ -280,6 +285,12 @@ func (factory *buildFactory) constructUnhookedPlan(
 		})
 	}
 
+	if planConfig.StageOutputs {
+		plan = factory.planFactory.NewPlan(atc.StagedPlan{
+			Step: plan,
+		})
+	}
+
 	return plan, nil
 }
 
@@ -16,6 +16,12 @@ type InputMapper interface {
 		versions *algorithm.VersionsDB,
 		job atc.JobConfig,
 	) (algorithm.InputMapping, error)
+
+	ResolveInputMapping(
+		logger lager.Logger,
+		versions *algorithm.VersionsDB,
+		job atc.JobConfig,
+	) (algorithm.InputMapping, bool, error)
 }
 
 //go:generate counterfeiter . InputMapperDB
@@ -92,3 +98,34 @@ func (i *inputMapper) SaveNextInputMapping(
 
 	return resolvedMapping, nil
 }
+
+// ResolveInputMapping determines the inputs for a single build of the job
+// without saving them as the job's next inputs, e.g. for a build triggered
+// with pinned versions that shouldn't affect the job's other builds.
+func (i *inputMapper) ResolveInputMapping(
+	logger lager.Logger,
+	versions *algorithm.VersionsDB,
+	job atc.JobConfig,
+) (algorithm.InputMapping, bool, error) {
+	logger = logger.Session("resolve-input-mapping")
+
+	inputConfigs := config.JobInputs(job)
+
+	algorithmInputConfigs, err := i.transformer.TransformInputConfigs(versions, job.Name, inputConfigs)
+	if err != nil {
+		logger.Error("failed-to-get-algorithm-input-configs", err)
+		return nil, false, err
+	}
+
+	// inputs pinned to a missing version are left out by the transformer
+	if len(algorithmInputConfigs) < len(inputConfigs) {
+		return nil, false, nil
+	}
+
+	resolvedMapping, ok := algorithmInputConfigs.Resolve(versions)
+	if !ok {
+		return nil, false, nil
+	}
+
+	return resolvedMapping, true, nil
+}
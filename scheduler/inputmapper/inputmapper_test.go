@@ -384,4 +384,88 @@ var _ = Describe("Inputmapper", func() {
 			})
 		})
 	})
+
+	Describe("ResolveInputMapping", func() {
+		var (
+			versionsDB   *algorithm.VersionsDB
+			jobConfig    atc.JobConfig
+			inputMapping algorithm.InputMapping
+			resolved     bool
+			resolveErr   error
+		)
+
+		BeforeEach(func() {
+			versionsDB = &algorithm.VersionsDB{
+				JobIDs:      map[string]int{"some-job": 1},
+				ResourceIDs: map[string]int{"a": 11, "b": 12},
+				ResourceVersions: []algorithm.ResourceVersion{
+					{VersionID: 1, ResourceID: 11, CheckOrder: 1},
+					{VersionID: 2, ResourceID: 12, CheckOrder: 1},
+				},
+			}
+
+			jobConfig = atc.JobConfig{
+				Name: "some-job",
+				Plan: atc.PlanSequence{
+					{Get: "a", Version: &atc.VersionConfig{Pinned: atc.Version{"some": "version"}}},
+					{Get: "b"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			inputMapping, resolved, resolveErr = inputMapper.ResolveInputMapping(
+				lagertest.NewTestLogger("test"),
+				versionsDB,
+				jobConfig,
+			)
+		})
+
+		Context("when the inputs resolve", func() {
+			BeforeEach(func() {
+				fakeTransformer.TransformInputConfigsReturns(algorithm.InputConfigs{
+					{Name: "a", ResourceID: 11, PinnedVersionID: 1, Passed: algorithm.JobSet{}, JobID: 1},
+					{Name: "b", ResourceID: 12, Passed: algorithm.JobSet{}, JobID: 1},
+				}, nil)
+			})
+
+			It("returns the mapping", func() {
+				Expect(resolveErr).NotTo(HaveOccurred())
+				Expect(resolved).To(BeTrue())
+				Expect(inputMapping).To(Equal(algorithm.InputMapping{
+					"a": algorithm.InputVersion{VersionID: 1, FirstOccurrence: true},
+					"b": algorithm.InputVersion{VersionID: 2, FirstOccurrence: true},
+				}))
+			})
+
+			It("does not save anything", func() {
+				Expect(fakeDB.SaveIndependentInputMappingCallCount()).To(BeZero())
+				Expect(fakeDB.SaveNextInputMappingCallCount()).To(BeZero())
+				Expect(fakeDB.DeleteNextInputMappingCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when a pinned version is missing", func() {
+			BeforeEach(func() {
+				fakeTransformer.TransformInputConfigsReturns(algorithm.InputConfigs{
+					{Name: "b", ResourceID: 12, Passed: algorithm.JobSet{}, JobID: 1},
+				}, nil)
+			})
+
+			It("does not resolve", func() {
+				Expect(resolveErr).NotTo(HaveOccurred())
+				Expect(resolved).To(BeFalse())
+			})
+		})
+
+		Context("when transforming the input configs fails", func() {
+			BeforeEach(func() {
+				fakeTransformer.TransformInputConfigsReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(resolveErr).To(Equal(disaster))
+			})
+		})
+	})
 })
@@ -26,6 +26,23 @@ type FakeInputMapper struct {
 		result1 algorithm.InputMapping
 		result2 error
 	}
+	ResolveInputMappingStub        func(logger lager.Logger, versions *algorithm.VersionsDB, job atc.JobConfig) (algorithm.InputMapping, bool, error)
+	resolveInputMappingMutex       sync.RWMutex
+	resolveInputMappingArgsForCall []struct {
+		logger   lager.Logger
+		versions *algorithm.VersionsDB
+		job      atc.JobConfig
+	}
+	resolveInputMappingReturns struct {
+		result1 algorithm.InputMapping
+		result2 bool
+		result3 error
+	}
+	resolveInputMappingReturnsOnCall map[int]struct {
+		result1 algorithm.InputMapping
+		result2 bool
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -83,11 +100,69 @@ func (fake *FakeInputMapper) SaveNextInputMappingReturnsOnCall(i int, result1 al
 	}{result1, result2}
 }
 
+func (fake *FakeInputMapper) ResolveInputMapping(logger lager.Logger, versions *algorithm.VersionsDB, job atc.JobConfig) (algorithm.InputMapping, bool, error) {
+	fake.resolveInputMappingMutex.Lock()
+	ret, specificReturn := fake.resolveInputMappingReturnsOnCall[len(fake.resolveInputMappingArgsForCall)]
+	fake.resolveInputMappingArgsForCall = append(fake.resolveInputMappingArgsForCall, struct {
+		logger   lager.Logger
+		versions *algorithm.VersionsDB
+		job      atc.JobConfig
+	}{logger, versions, job})
+	fake.recordInvocation("ResolveInputMapping", []interface{}{logger, versions, job})
+	fake.resolveInputMappingMutex.Unlock()
+	if fake.ResolveInputMappingStub != nil {
+		return fake.ResolveInputMappingStub(logger, versions, job)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.resolveInputMappingReturns.result1, fake.resolveInputMappingReturns.result2, fake.resolveInputMappingReturns.result3
+}
+
+func (fake *FakeInputMapper) ResolveInputMappingCallCount() int {
+	fake.resolveInputMappingMutex.RLock()
+	defer fake.resolveInputMappingMutex.RUnlock()
+	return len(fake.resolveInputMappingArgsForCall)
+}
+
+func (fake *FakeInputMapper) ResolveInputMappingArgsForCall(i int) (lager.Logger, *algorithm.VersionsDB, atc.JobConfig) {
+	fake.resolveInputMappingMutex.RLock()
+	defer fake.resolveInputMappingMutex.RUnlock()
+	return fake.resolveInputMappingArgsForCall[i].logger, fake.resolveInputMappingArgsForCall[i].versions, fake.resolveInputMappingArgsForCall[i].job
+}
+
+func (fake *FakeInputMapper) ResolveInputMappingReturns(result1 algorithm.InputMapping, result2 bool, result3 error) {
+	fake.ResolveInputMappingStub = nil
+	fake.resolveInputMappingReturns = struct {
+		result1 algorithm.InputMapping
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeInputMapper) ResolveInputMappingReturnsOnCall(i int, result1 algorithm.InputMapping, result2 bool, result3 error) {
+	fake.ResolveInputMappingStub = nil
+	if fake.resolveInputMappingReturnsOnCall == nil {
+		fake.resolveInputMappingReturnsOnCall = make(map[int]struct {
+			result1 algorithm.InputMapping
+			result2 bool
+			result3 error
+		})
+	}
+	fake.resolveInputMappingReturnsOnCall[i] = struct {
+		result1 algorithm.InputMapping
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeInputMapper) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.saveNextInputMappingMutex.RLock()
 	defer fake.saveNextInputMappingMutex.RUnlock()
+	fake.resolveInputMappingMutex.RLock()
+	defer fake.resolveInputMappingMutex.RUnlock()
 	return fake.invocations
 }
 
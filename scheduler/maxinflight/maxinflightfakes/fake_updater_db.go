@@ -9,6 +9,37 @@ import (
 )
 
 type FakeUpdaterDB struct {
+	GetPipelineIDStub        func() int
+	getPipelineIDMutex       sync.RWMutex
+	getPipelineIDArgsForCall []struct{}
+	getPipelineIDReturns     struct {
+		result1 int
+	}
+	getPipelineIDReturnsOnCall map[int]struct {
+		result1 int
+	}
+	MaxInFlightStub        func() int
+	maxInFlightMutex       sync.RWMutex
+	maxInFlightArgsForCall []struct{}
+	maxInFlightReturns     struct {
+		result1 int
+	}
+	maxInFlightReturnsOnCall map[int]struct {
+		result1 int
+	}
+	CountRunningBuildsForPipelineStub        func(int) (int, error)
+	countRunningBuildsForPipelineMutex       sync.RWMutex
+	countRunningBuildsForPipelineArgsForCall []struct {
+		pipelineID int
+	}
+	countRunningBuildsForPipelineReturns struct {
+		result1 int
+		result2 error
+	}
+	countRunningBuildsForPipelineReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	GetRunningBuildsBySerialGroupStub        func(jobName string, serialGroups []string) ([]db.Build, error)
 	getRunningBuildsBySerialGroupMutex       sync.RWMutex
 	getRunningBuildsBySerialGroupArgsForCall []struct {
@@ -55,6 +86,137 @@ type FakeUpdaterDB struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeUpdaterDB) GetPipelineID() int {
+	fake.getPipelineIDMutex.Lock()
+	ret, specificReturn := fake.getPipelineIDReturnsOnCall[len(fake.getPipelineIDArgsForCall)]
+	fake.getPipelineIDArgsForCall = append(fake.getPipelineIDArgsForCall, struct{}{})
+	fake.recordInvocation("GetPipelineID", []interface{}{})
+	fake.getPipelineIDMutex.Unlock()
+	if fake.GetPipelineIDStub != nil {
+		return fake.GetPipelineIDStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.getPipelineIDReturns.result1
+}
+
+func (fake *FakeUpdaterDB) GetPipelineIDCallCount() int {
+	fake.getPipelineIDMutex.RLock()
+	defer fake.getPipelineIDMutex.RUnlock()
+	return len(fake.getPipelineIDArgsForCall)
+}
+
+func (fake *FakeUpdaterDB) GetPipelineIDReturns(result1 int) {
+	fake.GetPipelineIDStub = nil
+	fake.getPipelineIDReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeUpdaterDB) GetPipelineIDReturnsOnCall(i int, result1 int) {
+	fake.GetPipelineIDStub = nil
+	if fake.getPipelineIDReturnsOnCall == nil {
+		fake.getPipelineIDReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.getPipelineIDReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeUpdaterDB) MaxInFlight() int {
+	fake.maxInFlightMutex.Lock()
+	ret, specificReturn := fake.maxInFlightReturnsOnCall[len(fake.maxInFlightArgsForCall)]
+	fake.maxInFlightArgsForCall = append(fake.maxInFlightArgsForCall, struct{}{})
+	fake.recordInvocation("MaxInFlight", []interface{}{})
+	fake.maxInFlightMutex.Unlock()
+	if fake.MaxInFlightStub != nil {
+		return fake.MaxInFlightStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.maxInFlightReturns.result1
+}
+
+func (fake *FakeUpdaterDB) MaxInFlightCallCount() int {
+	fake.maxInFlightMutex.RLock()
+	defer fake.maxInFlightMutex.RUnlock()
+	return len(fake.maxInFlightArgsForCall)
+}
+
+func (fake *FakeUpdaterDB) MaxInFlightReturns(result1 int) {
+	fake.MaxInFlightStub = nil
+	fake.maxInFlightReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeUpdaterDB) MaxInFlightReturnsOnCall(i int, result1 int) {
+	fake.MaxInFlightStub = nil
+	if fake.maxInFlightReturnsOnCall == nil {
+		fake.maxInFlightReturnsOnCall = make(map[int]struct {
+			result1 int
+		})
+	}
+	fake.maxInFlightReturnsOnCall[i] = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeUpdaterDB) CountRunningBuildsForPipeline(pipelineID int) (int, error) {
+	fake.countRunningBuildsForPipelineMutex.Lock()
+	ret, specificReturn := fake.countRunningBuildsForPipelineReturnsOnCall[len(fake.countRunningBuildsForPipelineArgsForCall)]
+	fake.countRunningBuildsForPipelineArgsForCall = append(fake.countRunningBuildsForPipelineArgsForCall, struct {
+		pipelineID int
+	}{pipelineID})
+	fake.recordInvocation("CountRunningBuildsForPipeline", []interface{}{pipelineID})
+	fake.countRunningBuildsForPipelineMutex.Unlock()
+	if fake.CountRunningBuildsForPipelineStub != nil {
+		return fake.CountRunningBuildsForPipelineStub(pipelineID)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.countRunningBuildsForPipelineReturns.result1, fake.countRunningBuildsForPipelineReturns.result2
+}
+
+func (fake *FakeUpdaterDB) CountRunningBuildsForPipelineCallCount() int {
+	fake.countRunningBuildsForPipelineMutex.RLock()
+	defer fake.countRunningBuildsForPipelineMutex.RUnlock()
+	return len(fake.countRunningBuildsForPipelineArgsForCall)
+}
+
+func (fake *FakeUpdaterDB) CountRunningBuildsForPipelineArgsForCall(i int) int {
+	fake.countRunningBuildsForPipelineMutex.RLock()
+	defer fake.countRunningBuildsForPipelineMutex.RUnlock()
+	return fake.countRunningBuildsForPipelineArgsForCall[i].pipelineID
+}
+
+func (fake *FakeUpdaterDB) CountRunningBuildsForPipelineReturns(result1 int, result2 error) {
+	fake.CountRunningBuildsForPipelineStub = nil
+	fake.countRunningBuildsForPipelineReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeUpdaterDB) CountRunningBuildsForPipelineReturnsOnCall(i int, result1 int, result2 error) {
+	fake.CountRunningBuildsForPipelineStub = nil
+	if fake.countRunningBuildsForPipelineReturnsOnCall == nil {
+		fake.countRunningBuildsForPipelineReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countRunningBuildsForPipelineReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeUpdaterDB) GetRunningBuildsBySerialGroup(jobName string, serialGroups []string) ([]db.Build, error) {
 	var serialGroupsCopy []string
 	if serialGroups != nil {
@@ -224,6 +386,12 @@ func (fake *FakeUpdaterDB) SetMaxInFlightReachedReturnsOnCall(i int, result1 err
 func (fake *FakeUpdaterDB) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getPipelineIDMutex.RLock()
+	defer fake.getPipelineIDMutex.RUnlock()
+	fake.maxInFlightMutex.RLock()
+	defer fake.maxInFlightMutex.RUnlock()
+	fake.countRunningBuildsForPipelineMutex.RLock()
+	defer fake.countRunningBuildsForPipelineMutex.RUnlock()
 	fake.getRunningBuildsBySerialGroupMutex.RLock()
 	defer fake.getRunningBuildsBySerialGroupMutex.RUnlock()
 	fake.getNextPendingBuildBySerialGroupMutex.RLock()
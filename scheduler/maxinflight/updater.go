@@ -15,6 +15,9 @@ type Updater interface {
 //go:generate counterfeiter . UpdaterDB
 
 type UpdaterDB interface {
+	GetPipelineID() int
+	MaxInFlight() int
+	CountRunningBuildsForPipeline(pipelineID int) (int, error)
 	GetRunningBuildsBySerialGroup(jobName string, serialGroups []string) ([]db.Build, error)
 	GetNextPendingBuildBySerialGroup(jobName string, serialGroups []string) (db.Build, bool, error)
 	SetMaxInFlightReached(jobName string, reached bool) error
@@ -45,6 +48,15 @@ func (u *updater) UpdateMaxInFlightReached(logger lager.Logger, jobConfig atc.Jo
 }
 
 func (u *updater) isMaxInFlightReached(logger lager.Logger, jobConfig atc.JobConfig, buildID int) (bool, error) {
+	pipelineReached, err := u.isPipelineMaxInFlightReached(logger)
+	if err != nil {
+		return false, err
+	}
+
+	if pipelineReached {
+		return true, nil
+	}
+
 	maxInFlight := jobConfig.MaxInFlight()
 
 	if maxInFlight == 0 {
@@ -74,3 +86,19 @@ func (u *updater) isMaxInFlightReached(logger lager.Logger, jobConfig atc.JobCon
 
 	return nextMostPendingBuild.ID() != buildID, nil
 }
+
+func (u *updater) isPipelineMaxInFlightReached(logger lager.Logger) (bool, error) {
+	maxInFlight := u.db.MaxInFlight()
+
+	if maxInFlight == 0 {
+		return false, nil
+	}
+
+	runningBuilds, err := u.db.CountRunningBuildsForPipeline(u.db.GetPipelineID())
+	if err != nil {
+		logger.Error("failed-to-count-running-builds-for-pipeline", err)
+		return false, err
+	}
+
+	return runningBuilds >= maxInFlight, nil
+}
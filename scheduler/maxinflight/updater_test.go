@@ -189,6 +189,85 @@ var _ = Describe("Updater", func() {
 			})
 		})
 
+		Context("when the pipeline config specifies max in flight = 2", func() {
+			BeforeEach(func() {
+				rawMaxInFlight = 0
+				serialGroups = []string{}
+
+				fakeDB.GetPipelineIDReturns(42)
+				fakeDB.MaxInFlightReturns(2)
+			})
+
+			Context("when counting the running builds fails", func() {
+				BeforeEach(func() {
+					fakeDB.CountRunningBuildsForPipelineReturns(0, disaster)
+				})
+
+				itReturnsTheError()
+			})
+
+			Context("when there are 2 builds of the pipeline running", func() {
+				BeforeEach(func() {
+					fakeDB.CountRunningBuildsForPipelineReturns(2, nil)
+				})
+
+				itReturnsTrueAndNoError()
+
+				It("counted the running builds for the pipeline", func() {
+					Expect(fakeDB.CountRunningBuildsForPipelineCallCount()).To(Equal(1))
+					Expect(fakeDB.CountRunningBuildsForPipelineArgsForCall(0)).To(Equal(42))
+				})
+
+				It("doesn't look at the job's serial groups", func() {
+					Expect(fakeDB.GetRunningBuildsBySerialGroupCallCount()).To(BeZero())
+					Expect(fakeDB.GetNextPendingBuildBySerialGroupCallCount()).To(BeZero())
+				})
+
+				Context("when one of the running builds finishes", func() {
+					JustBeforeEach(func() {
+						fakeDB.CountRunningBuildsForPipelineReturns(1, nil)
+
+						reached, updateErr = updater.UpdateMaxInFlightReached(
+							lagertest.NewTestLogger("test"),
+							atc.JobConfig{Name: "some-job"},
+							57,
+						)
+					})
+
+					It("unblocks the pending build", func() {
+						Expect(updateErr).NotTo(HaveOccurred())
+						Expect(reached).To(BeFalse())
+
+						Expect(fakeDB.SetMaxInFlightReachedCallCount()).To(Equal(2))
+						_, actualReached := fakeDB.SetMaxInFlightReachedArgsForCall(1)
+						Expect(actualReached).To(BeFalse())
+					})
+				})
+			})
+
+			Context("when there is 1 build of the pipeline running", func() {
+				BeforeEach(func() {
+					fakeDB.CountRunningBuildsForPipelineReturns(1, nil)
+				})
+
+				itReturnsFalseAndNoError()
+
+				Context("when the job config also specifies max in flight = 1", func() {
+					BeforeEach(func() {
+						rawMaxInFlight = 1
+					})
+
+					Context("when a build of the job is running", func() {
+						BeforeEach(func() {
+							fakeDB.GetRunningBuildsBySerialGroupReturns([]db.Build{nil}, nil)
+						})
+
+						itReturnsTrueAndNoError()
+					})
+				})
+			})
+		})
+
 		Context("when the job is in serial groups", func() {
 			BeforeEach(func() {
 				rawMaxInFlight = 0
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
 	"time"
@@ -29,6 +30,16 @@ type BuildScheduler interface {
 		jobConfig atc.JobConfig,
 		resourceConfigs atc.ResourceConfigs,
 		resourceTypes atc.VersionedResourceTypes,
+		overrides atc.BuildOverrides,
+	) (dbng.Build, Waiter, error)
+
+	RerunImmediately(
+		logger lager.Logger,
+		jobConfig atc.JobConfig,
+		resourceConfigs atc.ResourceConfigs,
+		resourceTypes atc.VersionedResourceTypes,
+		sourceBuildID int,
+		metadata map[string]json.RawMessage,
 	) (dbng.Build, Waiter, error)
 
 	SaveNextInputMapping(logger lager.Logger, job atc.JobConfig) error
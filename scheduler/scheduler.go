@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/concourse/atc/config"
 	"github.com/concourse/atc/db/algorithm"
 	"github.com/concourse/atc/dbng"
+	"github.com/concourse/atc/metric"
 	"github.com/concourse/atc/scheduler/inputmapper"
 )
 
@@ -17,6 +20,14 @@ type Scheduler struct {
 	InputMapper  inputmapper.InputMapper
 	BuildStarter BuildStarter
 	Scanner      Scanner
+	Settings     dbng.SchedulerSettings
+
+	// EventStore and MaxEventStoreBacklog defer starting builds while more
+	// than MaxEventStoreBacklog build events are waiting to be written. A
+	// MaxEventStoreBacklog of 0 disables the limit; the backlog is still
+	// emitted as a metric.
+	EventStore           EventStore
+	MaxEventStoreBacklog int
 }
 
 //go:generate counterfeiter . Scanner
@@ -25,6 +36,13 @@ type Scanner interface {
 	Scan(lager.Logger, string) error
 }
 
+//go:generate counterfeiter . EventStore
+
+// EventStore reports how far behind writing build events is.
+type EventStore interface {
+	EventStoreBacklog() (int, error)
+}
+
 func (s *Scheduler) Schedule(
 	logger lager.Logger,
 	versions *algorithm.VersionsDB,
@@ -44,6 +62,21 @@ func (s *Scheduler) Schedule(
 		}
 	}
 
+	paused, err := s.Settings.IsSchedulerPaused()
+	if err != nil {
+		logger.Error("failed-to-check-if-scheduler-is-paused", err)
+		return jobSchedulingTime, err
+	}
+
+	if paused {
+		logger.Debug("scheduler-paused")
+		return jobSchedulingTime, nil
+	}
+
+	if s.eventStoreOverloaded(logger) {
+		return jobSchedulingTime, nil
+	}
+
 	nextPendingBuilds, err := s.Pipeline.GetAllPendingBuilds()
 	if err != nil {
 		logger.Error("failed-to-get-all-next-pending-builds", err)
@@ -83,7 +116,7 @@ func (s *Scheduler) ensurePendingBuildExists(
 
 		//trigger: true, and the version has not been used
 		if ok && inputVersion.FirstOccurrence && inputConfig.Trigger {
-			err := s.Pipeline.EnsurePendingBuildExists(jobConfig.Name)
+			err := s.Pipeline.EnsurePendingBuildExists(jobConfig.Name, inputVersion.VersionID, triggerReason(inputConfig))
 			if err != nil {
 				logger.Error("failed-to-ensure-pending-build-exists", err)
 				return err
@@ -96,29 +129,124 @@ func (s *Scheduler) ensurePendingBuildExists(
 	return nil
 }
 
+// triggerReason describes a build triggered by a new version of the input.
+// Versions constrained by passed came through the upstream jobs.
+func triggerReason(input config.JobInput) dbng.BuildTriggerReason {
+	if len(input.Passed) > 0 {
+		return dbng.UpstreamTriggerReason(strings.Join(input.Passed, ","))
+	}
+
+	return dbng.ResourceTriggerReason(input.Resource)
+}
+
+// eventStoreOverloaded emits the event store backlog and reports whether
+// starting builds should be deferred because build events aren't being written
+// fast enough. Deferred builds stay pending and are started by a later tick
+// once the backlog has drained. If the backlog can't be determined, builds are
+// started as usual.
+func (s *Scheduler) eventStoreOverloaded(logger lager.Logger) bool {
+	if s.EventStore == nil {
+		return false
+	}
+
+	backlog, err := s.EventStore.EventStoreBacklog()
+	if err != nil {
+		logger.Error("failed-to-get-event-store-backlog", err)
+		return false
+	}
+
+	metric.EventStoreBacklog{
+		Backlog: backlog,
+		Limit:   s.MaxEventStoreBacklog,
+	}.Emit(logger)
+
+	if s.MaxEventStoreBacklog > 0 && backlog > s.MaxEventStoreBacklog {
+		logger.Info("event-store-overloaded", lager.Data{
+			"backlog": backlog,
+			"limit":   s.MaxEventStoreBacklog,
+		})
+		return true
+	}
+
+	return false
+}
+
 type Waiter interface {
 	Wait()
 }
 
+// TriggerImmediately creates a manually triggered build of the job and tries
+// to start it. The overrides are kept with the build and applied when it is
+// started, which may be by a later tick if it can't start right away.
 func (s *Scheduler) TriggerImmediately(
 	logger lager.Logger,
 	jobConfig atc.JobConfig,
 	resourceConfigs atc.ResourceConfigs,
 	resourceTypes atc.VersionedResourceTypes,
+	overrides atc.BuildOverrides,
 ) (dbng.Build, Waiter, error) {
 	logger = logger.Session("trigger-immediately", lager.Data{"job_name": jobConfig.Name})
 
-	build, err := s.Pipeline.CreateJobBuild(jobConfig.Name)
+	build, err := s.Pipeline.CreateJobBuildWithOverrides(jobConfig.Name, overrides)
 	if err != nil {
 		logger.Error("failed-to-create-job-build", err)
 		return nil, nil, err
 	}
+
+	return build, s.startPendingBuilds(logger, jobConfig, resourceConfigs, resourceTypes), nil
+}
+
+// RerunImmediately creates a manually triggered build of the job that runs
+// with the inputs of the source build, and tries to start it.
+func (s *Scheduler) RerunImmediately(
+	logger lager.Logger,
+	jobConfig atc.JobConfig,
+	resourceConfigs atc.ResourceConfigs,
+	resourceTypes atc.VersionedResourceTypes,
+	sourceBuildID int,
+	metadata map[string]json.RawMessage,
+) (dbng.Build, Waiter, error) {
+	logger = logger.Session("rerun-immediately", lager.Data{
+		"job_name":        jobConfig.Name,
+		"source_build_id": sourceBuildID,
+	})
+
+	build, err := s.Pipeline.CreateRerunBuild(jobConfig.Name, sourceBuildID, metadata)
+	if err != nil {
+		logger.Error("failed-to-create-rerun-build", err)
+		return nil, nil, err
+	}
+
+	return build, s.startPendingBuilds(logger, jobConfig, resourceConfigs, resourceTypes), nil
+}
+
+func (s *Scheduler) startPendingBuilds(
+	logger lager.Logger,
+	jobConfig atc.JobConfig,
+	resourceConfigs atc.ResourceConfigs,
+	resourceTypes atc.VersionedResourceTypes,
+) Waiter {
 	wg := new(sync.WaitGroup)
 	wg.Add(1)
 
 	go func() {
 		defer wg.Done()
 
+		paused, err := s.Settings.IsSchedulerPaused()
+		if err != nil {
+			logger.Error("failed-to-check-if-scheduler-is-paused", err)
+			return
+		}
+
+		if paused {
+			logger.Info("scheduler-paused")
+			return
+		}
+
+		if s.eventStoreOverloaded(logger) {
+			return
+		}
+
 		nextPendingBuilds, err := s.Pipeline.GetPendingBuildsForJob(jobConfig.Name)
 		if err != nil {
 			logger.Error("failed-to-get-next-pending-build-for-job", err)
@@ -132,7 +260,7 @@ func (s *Scheduler) TriggerImmediately(
 		}
 	}()
 
-	return build, wg, nil
+	return wg
 }
 
 func (s *Scheduler) SaveNextInputMapping(logger lager.Logger, job atc.JobConfig) error {
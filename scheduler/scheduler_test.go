@@ -1,6 +1,7 @@
 package scheduler_test
 
 import (
+	"encoding/json"
 	"errors"
 
 	"code.cloudfoundry.org/lager/lagertest"
@@ -21,6 +22,7 @@ var _ = Describe("Scheduler", func() {
 		fakeInputMapper  *inputmapperfakes.FakeInputMapper
 		fakeBuildStarter *schedulerfakes.FakeBuildStarter
 		fakeScanner      *schedulerfakes.FakeScanner
+		fakeSettings     *dbngfakes.FakeSchedulerSettings
 
 		scheduler *Scheduler
 
@@ -32,12 +34,14 @@ var _ = Describe("Scheduler", func() {
 		fakeInputMapper = new(inputmapperfakes.FakeInputMapper)
 		fakeBuildStarter = new(schedulerfakes.FakeBuildStarter)
 		fakeScanner = new(schedulerfakes.FakeScanner)
+		fakeSettings = new(dbngfakes.FakeSchedulerSettings)
 
 		scheduler = &Scheduler{
 			Pipeline:     fakePipeline,
 			InputMapper:  fakeInputMapper,
 			BuildStarter: fakeBuildStarter,
 			Scanner:      fakeScanner,
+			Settings:     fakeSettings,
 		}
 
 		disaster = errors.New("bad thing")
@@ -154,6 +158,105 @@ var _ = Describe("Scheduler", func() {
 						Expect(fakePipeline.EnsurePendingBuildExistsCallCount()).To(BeZero())
 					})
 				})
+
+				Context("when checking if the scheduler is paused fails", func() {
+					BeforeEach(func() {
+						fakeSettings.IsSchedulerPausedReturns(false, disaster)
+					})
+
+					It("returns the error", func() {
+						Expect(scheduleErr).To(Equal(disaster))
+					})
+
+					It("does not start any pending builds", func() {
+						Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when the scheduler is paused", func() {
+					BeforeEach(func() {
+						fakeSettings.IsSchedulerPausedReturns(true, nil)
+					})
+
+					It("returns no error", func() {
+						Expect(scheduleErr).NotTo(HaveOccurred())
+					})
+
+					It("still saves the next input mappings", func() {
+						Expect(fakeInputMapper.SaveNextInputMappingCallCount()).To(Equal(2))
+					})
+
+					It("does not start any pending builds", func() {
+						Expect(fakePipeline.GetAllPendingBuildsCallCount()).To(BeZero())
+						Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when there is a max event store backlog", func() {
+					var fakeEventStore *schedulerfakes.FakeEventStore
+
+					BeforeEach(func() {
+						fakeEventStore = new(schedulerfakes.FakeEventStore)
+						scheduler.EventStore = fakeEventStore
+						scheduler.MaxEventStoreBacklog = 100
+					})
+
+					Context("when the backlog is above the max", func() {
+						BeforeEach(func() {
+							fakeEventStore.EventStoreBacklogReturns(101, nil)
+						})
+
+						It("returns no error", func() {
+							Expect(scheduleErr).NotTo(HaveOccurred())
+						})
+
+						It("still saves the next input mappings", func() {
+							Expect(fakeInputMapper.SaveNextInputMappingCallCount()).To(Equal(2))
+						})
+
+						It("leaves the pending builds pending", func() {
+							Expect(fakePipeline.GetAllPendingBuildsCallCount()).To(BeZero())
+							Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(BeZero())
+						})
+					})
+
+					Context("when the backlog is at or below the max", func() {
+						BeforeEach(func() {
+							fakeEventStore.EventStoreBacklogReturns(100, nil)
+						})
+
+						It("starts the pending builds", func() {
+							Expect(scheduleErr).NotTo(HaveOccurred())
+							Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(Equal(2))
+						})
+					})
+
+					Context("when getting the backlog fails", func() {
+						BeforeEach(func() {
+							fakeEventStore.EventStoreBacklogReturns(0, disaster)
+						})
+
+						It("starts the pending builds anyway", func() {
+							Expect(scheduleErr).NotTo(HaveOccurred())
+							Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(Equal(2))
+						})
+					})
+
+					Context("when the max is 0", func() {
+						BeforeEach(func() {
+							scheduler.MaxEventStoreBacklog = 0
+							fakeEventStore.EventStoreBacklogReturns(1000, nil)
+						})
+
+						It("still measures the backlog", func() {
+							Expect(fakeEventStore.EventStoreBacklogCallCount()).To(Equal(1))
+						})
+
+						It("starts the pending builds", func() {
+							Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(Equal(2))
+						})
+					})
+				})
 			})
 		})
 
@@ -224,7 +327,10 @@ var _ = Describe("Scheduler", func() {
 
 					It("created a pending build for the right job", func() {
 						Expect(fakePipeline.EnsurePendingBuildExistsCallCount()).To(Equal(1))
-						Expect(fakePipeline.EnsurePendingBuildExistsArgsForCall(0)).To(Equal("some-job"))
+						actualJobName, actualVersionID, actualReason := fakePipeline.EnsurePendingBuildExistsArgsForCall(0)
+						Expect(actualJobName).To(Equal("some-job"))
+						Expect(actualVersionID).To(Equal(1))
+						Expect(actualReason).To(Equal(dbng.ResourceTriggerReason("a")))
 					})
 				})
 
@@ -239,6 +345,29 @@ var _ = Describe("Scheduler", func() {
 					})
 				})
 			})
+
+			Context("when the triggering input has passed constraints", func() {
+				BeforeEach(func() {
+					jobConfigs = atc.JobConfigs{
+						{
+							Name: "some-job",
+							Plan: atc.PlanSequence{
+								{Get: "a", Trigger: true, Passed: []string{"upstream-job"}},
+							},
+						},
+					}
+
+					fakeInputMapper.SaveNextInputMappingReturns(algorithm.InputMapping{
+						"a": algorithm.InputVersion{VersionID: 1, FirstOccurrence: true},
+					}, nil)
+				})
+
+				It("creates a pending build triggered by the upstream job", func() {
+					Expect(fakePipeline.EnsurePendingBuildExistsCallCount()).To(Equal(1))
+					_, _, actualReason := fakePipeline.EnsurePendingBuildExistsArgsForCall(0)
+					Expect(actualReason).To(Equal(dbng.UpstreamTriggerReason("upstream-job")))
+				})
+			})
 		})
 	})
 
@@ -264,6 +393,9 @@ var _ = Describe("Scheduler", func() {
 						Version:      atc.Version{"some": "version"},
 					},
 				},
+				atc.BuildOverrides{
+					Params: map[string]atc.Params{"input-1": {"some": "param"}},
+				},
 			)
 			if waiter != nil {
 				waiter.Wait()
@@ -272,7 +404,7 @@ var _ = Describe("Scheduler", func() {
 
 		Context("when creating the build fails", func() {
 			BeforeEach(func() {
-				fakePipeline.CreateJobBuildReturns(nil, disaster)
+				fakePipeline.CreateJobBuildWithOverridesReturns(nil, disaster)
 			})
 
 			It("returns the error", func() {
@@ -286,12 +418,62 @@ var _ = Describe("Scheduler", func() {
 			BeforeEach(func() {
 				createdBuild = new(dbngfakes.FakeBuild)
 				createdBuild.IsManuallyTriggeredReturns(true)
-				fakePipeline.CreateJobBuildReturns(createdBuild, nil)
+				fakePipeline.CreateJobBuildWithOverridesReturns(createdBuild, nil)
+			})
+
+			It("tried to create a build for the right job with the overrides", func() {
+				Expect(fakePipeline.CreateJobBuildWithOverridesCallCount()).To(Equal(1))
+
+				jobName, overrides := fakePipeline.CreateJobBuildWithOverridesArgsForCall(0)
+				Expect(jobName).To(Equal("some-job"))
+				Expect(overrides).To(Equal(atc.BuildOverrides{
+					Params: map[string]atc.Params{"input-1": {"some": "param"}},
+				}))
+			})
+
+			Context("when the scheduler is paused", func() {
+				BeforeEach(func() {
+					fakeSettings.IsSchedulerPausedReturns(true, nil)
+				})
+
+				It("returns the created build without error", func() {
+					Expect(triggerErr).NotTo(HaveOccurred())
+					Expect(triggeredBuild).To(Equal(createdBuild))
+				})
+
+				It("leaves the build pending rather than starting it", func() {
+					Expect(fakePipeline.GetPendingBuildsForJobCallCount()).To(BeZero())
+					Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(BeZero())
+				})
 			})
 
-			It("tried to create a build for the right job", func() {
-				Expect(fakePipeline.CreateJobBuildCallCount()).To(Equal(1))
-				Expect(fakePipeline.CreateJobBuildArgsForCall(0)).To(Equal("some-job"))
+			Context("when the event store backlog is above the max", func() {
+				BeforeEach(func() {
+					fakeEventStore := new(schedulerfakes.FakeEventStore)
+					fakeEventStore.EventStoreBacklogReturns(101, nil)
+					scheduler.EventStore = fakeEventStore
+					scheduler.MaxEventStoreBacklog = 100
+				})
+
+				It("returns the created build without error", func() {
+					Expect(triggerErr).NotTo(HaveOccurred())
+					Expect(triggeredBuild).To(Equal(createdBuild))
+				})
+
+				It("leaves the build pending rather than starting it", func() {
+					Expect(fakePipeline.GetPendingBuildsForJobCallCount()).To(BeZero())
+					Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(BeZero())
+				})
+			})
+
+			Context("when checking if the scheduler is paused fails", func() {
+				BeforeEach(func() {
+					fakeSettings.IsSchedulerPausedReturns(false, disaster)
+				})
+
+				It("does not try to start pending builds for job", func() {
+					Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(BeZero())
+				})
 			})
 
 			Context("when get pending builds for job fails", func() {
@@ -330,6 +512,72 @@ var _ = Describe("Scheduler", func() {
 		})
 	})
 
+	Describe("RerunImmediately", func() {
+		var (
+			rerunBuild dbng.Build
+			rerunErr   error
+			metadata   map[string]json.RawMessage
+		)
+
+		BeforeEach(func() {
+			metadata = map[string]json.RawMessage{"rerun-of": json.RawMessage(`{"build_id":7}`)}
+		})
+
+		JustBeforeEach(func() {
+			var waiter Waiter
+			rerunBuild, waiter, rerunErr = scheduler.RerunImmediately(
+				lagertest.NewTestLogger("test"),
+				atc.JobConfig{Name: "some-job", Plan: atc.PlanSequence{{Get: "input-1"}}},
+				atc.ResourceConfigs{{Name: "some-resource"}},
+				atc.VersionedResourceTypes{},
+				7,
+				metadata,
+			)
+			if waiter != nil {
+				waiter.Wait()
+			}
+		})
+
+		Context("when creating the build fails", func() {
+			BeforeEach(func() {
+				fakePipeline.CreateRerunBuildReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(rerunErr).To(Equal(disaster))
+			})
+		})
+
+		Context("when creating the build succeeds", func() {
+			var createdBuild *dbngfakes.FakeBuild
+
+			BeforeEach(func() {
+				createdBuild = new(dbngfakes.FakeBuild)
+				fakePipeline.CreateRerunBuildReturns(createdBuild, nil)
+
+				fakePipeline.GetPendingBuildsForJobReturns([]dbng.Build{createdBuild}, nil)
+			})
+
+			It("creates the build from the source build with the metadata", func() {
+				Expect(fakePipeline.CreateRerunBuildCallCount()).To(Equal(1))
+
+				jobName, sourceBuildID, actualMetadata := fakePipeline.CreateRerunBuildArgsForCall(0)
+				Expect(jobName).To(Equal("some-job"))
+				Expect(sourceBuildID).To(Equal(7))
+				Expect(actualMetadata).To(Equal(metadata))
+			})
+
+			It("returns the build and tries to start it", func() {
+				Expect(rerunErr).NotTo(HaveOccurred())
+				Expect(rerunBuild).To(Equal(createdBuild))
+
+				Expect(fakeBuildStarter.TryStartPendingBuildsForJobCallCount()).To(Equal(1))
+				_, _, _, _, b := fakeBuildStarter.TryStartPendingBuildsForJobArgsForCall(0)
+				Expect(b).To(Equal([]dbng.Build{createdBuild}))
+			})
+		})
+	})
+
 	Describe("SaveNextInputMapping", func() {
 		var saveErr error
 
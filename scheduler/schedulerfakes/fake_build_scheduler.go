@@ -2,6 +2,7 @@
 package schedulerfakes
 
 import (
+	"encoding/json"
 	"sync"
 	"time"
 
@@ -30,13 +31,14 @@ type FakeBuildScheduler struct {
 		result1 map[string]time.Duration
 		result2 error
 	}
-	TriggerImmediatelyStub        func(logger lager.Logger, jobConfig atc.JobConfig, resourceConfigs atc.ResourceConfigs, resourceTypes atc.VersionedResourceTypes) (dbng.Build, scheduler.Waiter, error)
+	TriggerImmediatelyStub        func(logger lager.Logger, jobConfig atc.JobConfig, resourceConfigs atc.ResourceConfigs, resourceTypes atc.VersionedResourceTypes, overrides atc.BuildOverrides) (dbng.Build, scheduler.Waiter, error)
 	triggerImmediatelyMutex       sync.RWMutex
 	triggerImmediatelyArgsForCall []struct {
 		logger          lager.Logger
 		jobConfig       atc.JobConfig
 		resourceConfigs atc.ResourceConfigs
 		resourceTypes   atc.VersionedResourceTypes
+		overrides       atc.BuildOverrides
 	}
 	triggerImmediatelyReturns struct {
 		result1 dbng.Build
@@ -60,6 +62,26 @@ type FakeBuildScheduler struct {
 	saveNextInputMappingReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RerunImmediatelyStub        func(logger lager.Logger, jobConfig atc.JobConfig, resourceConfigs atc.ResourceConfigs, resourceTypes atc.VersionedResourceTypes, sourceBuildID int, metadata map[string]json.RawMessage) (dbng.Build, scheduler.Waiter, error)
+	rerunImmediatelyMutex       sync.RWMutex
+	rerunImmediatelyArgsForCall []struct {
+		logger          lager.Logger
+		jobConfig       atc.JobConfig
+		resourceConfigs atc.ResourceConfigs
+		resourceTypes   atc.VersionedResourceTypes
+		sourceBuildID   int
+		metadata        map[string]json.RawMessage
+	}
+	rerunImmediatelyReturns struct {
+		result1 dbng.Build
+		result2 scheduler.Waiter
+		result3 error
+	}
+	rerunImmediatelyReturnsOnCall map[int]struct {
+		result1 dbng.Build
+		result2 scheduler.Waiter
+		result3 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -119,7 +141,7 @@ func (fake *FakeBuildScheduler) ScheduleReturnsOnCall(i int, result1 map[string]
 	}{result1, result2}
 }
 
-func (fake *FakeBuildScheduler) TriggerImmediately(logger lager.Logger, jobConfig atc.JobConfig, resourceConfigs atc.ResourceConfigs, resourceTypes atc.VersionedResourceTypes) (dbng.Build, scheduler.Waiter, error) {
+func (fake *FakeBuildScheduler) TriggerImmediately(logger lager.Logger, jobConfig atc.JobConfig, resourceConfigs atc.ResourceConfigs, resourceTypes atc.VersionedResourceTypes, overrides atc.BuildOverrides) (dbng.Build, scheduler.Waiter, error) {
 	fake.triggerImmediatelyMutex.Lock()
 	ret, specificReturn := fake.triggerImmediatelyReturnsOnCall[len(fake.triggerImmediatelyArgsForCall)]
 	fake.triggerImmediatelyArgsForCall = append(fake.triggerImmediatelyArgsForCall, struct {
@@ -127,11 +149,12 @@ func (fake *FakeBuildScheduler) TriggerImmediately(logger lager.Logger, jobConfi
 		jobConfig       atc.JobConfig
 		resourceConfigs atc.ResourceConfigs
 		resourceTypes   atc.VersionedResourceTypes
-	}{logger, jobConfig, resourceConfigs, resourceTypes})
-	fake.recordInvocation("TriggerImmediately", []interface{}{logger, jobConfig, resourceConfigs, resourceTypes})
+		overrides       atc.BuildOverrides
+	}{logger, jobConfig, resourceConfigs, resourceTypes, overrides})
+	fake.recordInvocation("TriggerImmediately", []interface{}{logger, jobConfig, resourceConfigs, resourceTypes, overrides})
 	fake.triggerImmediatelyMutex.Unlock()
 	if fake.TriggerImmediatelyStub != nil {
-		return fake.TriggerImmediatelyStub(logger, jobConfig, resourceConfigs, resourceTypes)
+		return fake.TriggerImmediatelyStub(logger, jobConfig, resourceConfigs, resourceTypes, overrides)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -145,10 +168,10 @@ func (fake *FakeBuildScheduler) TriggerImmediatelyCallCount() int {
 	return len(fake.triggerImmediatelyArgsForCall)
 }
 
-func (fake *FakeBuildScheduler) TriggerImmediatelyArgsForCall(i int) (lager.Logger, atc.JobConfig, atc.ResourceConfigs, atc.VersionedResourceTypes) {
+func (fake *FakeBuildScheduler) TriggerImmediatelyArgsForCall(i int) (lager.Logger, atc.JobConfig, atc.ResourceConfigs, atc.VersionedResourceTypes, atc.BuildOverrides) {
 	fake.triggerImmediatelyMutex.RLock()
 	defer fake.triggerImmediatelyMutex.RUnlock()
-	return fake.triggerImmediatelyArgsForCall[i].logger, fake.triggerImmediatelyArgsForCall[i].jobConfig, fake.triggerImmediatelyArgsForCall[i].resourceConfigs, fake.triggerImmediatelyArgsForCall[i].resourceTypes
+	return fake.triggerImmediatelyArgsForCall[i].logger, fake.triggerImmediatelyArgsForCall[i].jobConfig, fake.triggerImmediatelyArgsForCall[i].resourceConfigs, fake.triggerImmediatelyArgsForCall[i].resourceTypes, fake.triggerImmediatelyArgsForCall[i].overrides
 }
 
 func (fake *FakeBuildScheduler) TriggerImmediatelyReturns(result1 dbng.Build, result2 scheduler.Waiter, result3 error) {
@@ -225,6 +248,65 @@ func (fake *FakeBuildScheduler) SaveNextInputMappingReturnsOnCall(i int, result1
 	}{result1}
 }
 
+func (fake *FakeBuildScheduler) RerunImmediately(logger lager.Logger, jobConfig atc.JobConfig, resourceConfigs atc.ResourceConfigs, resourceTypes atc.VersionedResourceTypes, sourceBuildID int, metadata map[string]json.RawMessage) (dbng.Build, scheduler.Waiter, error) {
+	fake.rerunImmediatelyMutex.Lock()
+	ret, specificReturn := fake.rerunImmediatelyReturnsOnCall[len(fake.rerunImmediatelyArgsForCall)]
+	fake.rerunImmediatelyArgsForCall = append(fake.rerunImmediatelyArgsForCall, struct {
+		logger          lager.Logger
+		jobConfig       atc.JobConfig
+		resourceConfigs atc.ResourceConfigs
+		resourceTypes   atc.VersionedResourceTypes
+		sourceBuildID   int
+		metadata        map[string]json.RawMessage
+	}{logger, jobConfig, resourceConfigs, resourceTypes, sourceBuildID, metadata})
+	fake.recordInvocation("RerunImmediately", []interface{}{logger, jobConfig, resourceConfigs, resourceTypes, sourceBuildID, metadata})
+	fake.rerunImmediatelyMutex.Unlock()
+	if fake.RerunImmediatelyStub != nil {
+		return fake.RerunImmediatelyStub(logger, jobConfig, resourceConfigs, resourceTypes, sourceBuildID, metadata)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.rerunImmediatelyReturns.result1, fake.rerunImmediatelyReturns.result2, fake.rerunImmediatelyReturns.result3
+}
+
+func (fake *FakeBuildScheduler) RerunImmediatelyCallCount() int {
+	fake.rerunImmediatelyMutex.RLock()
+	defer fake.rerunImmediatelyMutex.RUnlock()
+	return len(fake.rerunImmediatelyArgsForCall)
+}
+
+func (fake *FakeBuildScheduler) RerunImmediatelyArgsForCall(i int) (lager.Logger, atc.JobConfig, atc.ResourceConfigs, atc.VersionedResourceTypes, int, map[string]json.RawMessage) {
+	fake.rerunImmediatelyMutex.RLock()
+	defer fake.rerunImmediatelyMutex.RUnlock()
+	return fake.rerunImmediatelyArgsForCall[i].logger, fake.rerunImmediatelyArgsForCall[i].jobConfig, fake.rerunImmediatelyArgsForCall[i].resourceConfigs, fake.rerunImmediatelyArgsForCall[i].resourceTypes, fake.rerunImmediatelyArgsForCall[i].sourceBuildID, fake.rerunImmediatelyArgsForCall[i].metadata
+}
+
+func (fake *FakeBuildScheduler) RerunImmediatelyReturns(result1 dbng.Build, result2 scheduler.Waiter, result3 error) {
+	fake.RerunImmediatelyStub = nil
+	fake.rerunImmediatelyReturns = struct {
+		result1 dbng.Build
+		result2 scheduler.Waiter
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeBuildScheduler) RerunImmediatelyReturnsOnCall(i int, result1 dbng.Build, result2 scheduler.Waiter, result3 error) {
+	fake.RerunImmediatelyStub = nil
+	if fake.rerunImmediatelyReturnsOnCall == nil {
+		fake.rerunImmediatelyReturnsOnCall = make(map[int]struct {
+			result1 dbng.Build
+			result2 scheduler.Waiter
+			result3 error
+		})
+	}
+	fake.rerunImmediatelyReturnsOnCall[i] = struct {
+		result1 dbng.Build
+		result2 scheduler.Waiter
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeBuildScheduler) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -234,6 +316,8 @@ func (fake *FakeBuildScheduler) Invocations() map[string][][]interface{} {
 	defer fake.triggerImmediatelyMutex.RUnlock()
 	fake.saveNextInputMappingMutex.RLock()
 	defer fake.saveNextInputMappingMutex.RUnlock()
+	fake.rerunImmediatelyMutex.RLock()
+	defer fake.rerunImmediatelyMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,89 @@
+// This file was generated by counterfeiter
+package schedulerfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/scheduler"
+)
+
+type FakeEventStore struct {
+	EventStoreBacklogStub        func() (int, error)
+	eventStoreBacklogMutex       sync.RWMutex
+	eventStoreBacklogArgsForCall []struct{}
+	eventStoreBacklogReturns     struct {
+		result1 int
+		result2 error
+	}
+	eventStoreBacklogReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEventStore) EventStoreBacklog() (int, error) {
+	fake.eventStoreBacklogMutex.Lock()
+	ret, specificReturn := fake.eventStoreBacklogReturnsOnCall[len(fake.eventStoreBacklogArgsForCall)]
+	fake.eventStoreBacklogArgsForCall = append(fake.eventStoreBacklogArgsForCall, struct{}{})
+	fake.recordInvocation("EventStoreBacklog", []interface{}{})
+	fake.eventStoreBacklogMutex.Unlock()
+	if fake.EventStoreBacklogStub != nil {
+		return fake.EventStoreBacklogStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.eventStoreBacklogReturns.result1, fake.eventStoreBacklogReturns.result2
+}
+
+func (fake *FakeEventStore) EventStoreBacklogCallCount() int {
+	fake.eventStoreBacklogMutex.RLock()
+	defer fake.eventStoreBacklogMutex.RUnlock()
+	return len(fake.eventStoreBacklogArgsForCall)
+}
+
+func (fake *FakeEventStore) EventStoreBacklogReturns(result1 int, result2 error) {
+	fake.EventStoreBacklogStub = nil
+	fake.eventStoreBacklogReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEventStore) EventStoreBacklogReturnsOnCall(i int, result1 int, result2 error) {
+	fake.EventStoreBacklogStub = nil
+	if fake.eventStoreBacklogReturnsOnCall == nil {
+		fake.eventStoreBacklogReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.eventStoreBacklogReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEventStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.eventStoreBacklogMutex.RLock()
+	defer fake.eventStoreBacklogMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeEventStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ scheduler.EventStore = new(FakeEventStore)
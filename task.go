@@ -2,6 +2,7 @@ package atc
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -29,10 +30,26 @@ type TaskConfig struct {
 	// The set of (logical, name-only) inputs required by the task.
 	Inputs []TaskInputConfig `json:"inputs,omitempty" yaml:"inputs,omitempty" mapstructure:"inputs"`
 
+	// Groups of inputs that must either all be provided or all be absent.
+	InputGroups []TaskInputGroupConfig `json:"input_groups,omitempty" yaml:"input_groups,omitempty" mapstructure:"input_groups"`
+
 	// The set of (logical, name-only) outputs provided by the task.
 	Outputs []TaskOutputConfig `json:"outputs,omitempty" yaml:"outputs,omitempty" mapstructure:"outputs"`
+
+	// PEM-encoded certificates to append to the trust store in the task's
+	// container before the task runs.
+	CACerts []string `json:"ca_certs,omitempty" yaml:"ca_certs,omitempty" mapstructure:"ca_certs"`
+
+	// Absolute path to the trust store in the task's image. Defaults to
+	// DefaultCACertsPath.
+	CACertsPath string `json:"ca_certs_path,omitempty" yaml:"ca_certs_path,omitempty" mapstructure:"ca_certs_path"`
 }
 
+// DefaultCACertsPath is where Debian/Ubuntu and Alpine based images keep their
+// trust store. Images with a different layout (e.g. /etc/pki/tls/certs/ on
+// CentOS) must configure ca_certs_path.
+const DefaultCACertsPath = "/etc/ssl/certs/ca-certificates.crt"
+
 type ImageResource struct {
 	Type   string `yaml:"type" json:"type" mapstructure:"type"`
 	Source Source `yaml:"source" json:"source" mapstructure:"source"`
@@ -106,10 +123,22 @@ func (config TaskConfig) Merge(other TaskConfig) TaskConfig {
 		config.Inputs = other.Inputs
 	}
 
+	if len(other.InputGroups) != 0 {
+		config.InputGroups = other.InputGroups
+	}
+
 	if other.Run.Path != "" {
 		config.Run = other.Run
 	}
 
+	if len(other.CACerts) != 0 {
+		config.CACerts = other.CACerts
+	}
+
+	if other.CACertsPath != "" {
+		config.CACertsPath = other.CACertsPath
+	}
+
 	return config
 }
 
@@ -126,6 +155,10 @@ func (config TaskConfig) Validate() error {
 
 	messages = append(messages, config.validateInputsAndOutputs()...)
 
+	if config.CACertsPath != "" && !path.IsAbs(config.CACertsPath) {
+		messages = append(messages, "  ca_certs_path must be an absolute path")
+	}
+
 	if len(messages) > 0 {
 		return fmt.Errorf("invalid task configuration:\n%s", strings.Join(messages, "\n"))
 	}
@@ -137,7 +170,9 @@ func (config TaskConfig) validateInputsAndOutputs() []string {
 	messages := []string{}
 
 	messages = append(messages, config.validateInputContainsNames()...)
+	messages = append(messages, config.validateInputGroups()...)
 	messages = append(messages, config.validateOutputContainsNames()...)
+	messages = append(messages, config.validateOutputGlobs()...)
 	messages = append(messages, config.validateDotPath()...)
 	messages = append(messages, config.validateOverlappingPaths()...)
 
@@ -150,7 +185,7 @@ func (config TaskConfig) validateDotPath() []string {
 	pathCount := 0
 	dotPath := false
 
-	for _, input := range config.Inputs {
+	for _, input := range config.allInputs() {
 		path := strings.TrimPrefix(input.resolvePath(), "./")
 
 		if path == "." {
@@ -161,7 +196,7 @@ func (config TaskConfig) validateDotPath() []string {
 	}
 
 	for _, output := range config.Outputs {
-		path := strings.TrimPrefix(output.resolvePath(), "./")
+		path := strings.TrimPrefix(output.mountPath(), "./")
 
 		if path == "." {
 			dotPath = true
@@ -200,7 +235,7 @@ func (counter *pathCounter) registerInput(input TaskInputConfig) {
 }
 
 func (counter *pathCounter) registerOutput(output TaskOutputConfig) {
-	path := strings.TrimPrefix(output.resolvePath(), "./")
+	path := strings.TrimPrefix(output.mountPath(), "./")
 
 	if val, found := counter.outputCount[path]; !found {
 		counter.outputCount[path] = 1
@@ -287,7 +322,7 @@ func (config TaskConfig) countInputOutputPaths() pathCounter {
 		outputCount: make(map[string]int),
 	}
 
-	for _, input := range config.Inputs {
+	for _, input := range config.allInputs() {
 		counter.registerInput(input)
 	}
 
@@ -315,6 +350,34 @@ func (config TaskConfig) validateOutputContainsNames() []string {
 	return messages
 }
 
+func (config TaskConfig) validateOutputGlobs() []string {
+	messages := []string{}
+
+	for _, output := range config.Outputs {
+		if !output.Glob {
+			continue
+		}
+
+		dir, pattern := path.Split(path.Clean(output.resolvePath()))
+
+		if _, err := path.Match(pattern, ""); err != nil || strings.ContainsAny(dir, "*?[") {
+			messages = append(messages, fmt.Sprintf("  output '%s' has an invalid glob path '%s'", output.Name, output.resolvePath()))
+		}
+
+		// a glob without a parent directory would mount, and match within, the
+		// task's whole working directory
+		if mountDir := output.mountPath(); mountDir == "." || mountDir == "/" {
+			messages = append(messages, fmt.Sprintf("  output '%s' has a glob path '%s' with no directory to match within", output.Name, output.resolvePath()))
+		}
+
+		if output.Result || output.Env {
+			messages = append(messages, fmt.Sprintf("  output '%s' cannot be a glob and carry a result or env file", output.Name))
+		}
+	}
+
+	return messages
+}
+
 func (config TaskConfig) validateInputContainsNames() []string {
 	messages := []string{}
 
@@ -327,6 +390,43 @@ func (config TaskConfig) validateInputContainsNames() []string {
 	return messages
 }
 
+func (config TaskConfig) validateInputGroups() []string {
+	messages := []string{}
+
+	for i, group := range config.InputGroups {
+		identifier := fmt.Sprintf("input group in position %d", i)
+		if group.Name == "" {
+			messages = append(messages, fmt.Sprintf("  %s is missing a name", identifier))
+		} else {
+			identifier = fmt.Sprintf("input group '%s'", group.Name)
+		}
+
+		if len(group.Inputs) == 0 {
+			messages = append(messages, fmt.Sprintf("  %s has no inputs", identifier))
+		}
+
+		for j, input := range group.Inputs {
+			if input.Name == "" {
+				messages = append(messages, fmt.Sprintf("  input in position %d of %s is missing a name", j, identifier))
+			}
+		}
+	}
+
+	return messages
+}
+
+// allInputs returns the task's inputs followed by the members of each of its
+// input groups.
+func (config TaskConfig) allInputs() []TaskInputConfig {
+	inputs := append([]TaskInputConfig{}, config.Inputs...)
+
+	for _, group := range config.InputGroups {
+		inputs = append(inputs, group.Inputs...)
+	}
+
+	return inputs
+}
+
 type TaskRunConfig struct {
 	Path string   `json:"path" yaml:"path"`
 	Args []string `json:"args,omitempty" yaml:"args"`
@@ -337,8 +437,10 @@ type TaskRunConfig struct {
 }
 
 type TaskInputConfig struct {
-	Name string `json:"name" yaml:"name"`
-	Path string `json:"path,omitempty" yaml:"path"`
+	Name     string   `json:"name" yaml:"name"`
+	Path     string   `json:"path,omitempty" yaml:"path"`
+	Optional bool     `json:"optional,omitempty" yaml:"optional,omitempty"`
+	Files    []string `json:"files,omitempty" yaml:"files,omitempty"`
 }
 
 func (input TaskInputConfig) resolvePath() string {
@@ -348,9 +450,31 @@ func (input TaskInputConfig) resolvePath() string {
 	return input.Name
 }
 
+// TaskInputGroupConfig is a set of related inputs that are only meaningful
+// together, e.g. a certificate and its key. If any of them are provided, all
+// of them must be.
+type TaskInputGroupConfig struct {
+	Name   string            `json:"name" yaml:"name"`
+	Inputs []TaskInputConfig `json:"inputs" yaml:"inputs"`
+}
+
 type TaskOutputConfig struct {
 	Name string `json:"name" yaml:"name"`
 	Path string `json:"path,omitempty" yaml:"path"`
+
+	// Result marks the output as carrying a result.json file whose contents
+	// are recorded as metadata on the build. Later tasks can reference its
+	// fields in their params as ((result:output-name.field)).
+	Result bool `json:"result,omitempty" yaml:"result,omitempty"`
+
+	// Env marks the output as carrying an env file of KEY=value lines which
+	// are set in the environment of the build's subsequent tasks.
+	Env bool `json:"env,omitempty" yaml:"env,omitempty"`
+
+	// Glob treats the last element of the output's path as a pattern. Each
+	// directory matching it is registered as its own artifact, named after
+	// the output and the directory, e.g. 'results/cell-1'.
+	Glob bool `json:"glob,omitempty" yaml:"glob,omitempty"`
 }
 
 func (output TaskOutputConfig) resolvePath() string {
@@ -360,6 +484,15 @@ func (output TaskOutputConfig) resolvePath() string {
 	return output.Name
 }
 
+// mountPath is the path the output's volume is mounted at. A glob output is
+// mounted at the directory containing its matches.
+func (output TaskOutputConfig) mountPath() string {
+	if output.Glob {
+		return path.Dir(path.Clean(output.resolvePath()))
+	}
+	return output.resolvePath()
+}
+
 type MetadataField struct {
 	Name  string `json:"name"`
 	Value string `json:"value"`
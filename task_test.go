@@ -232,6 +232,162 @@ run: {path: a/file}
 					Expect(err).To(MatchError(ContainSubstring("  output in position 2 is missing a name")))
 				})
 			})
+
+			Context("when an output is a glob", func() {
+				It("is valid", func() {
+					validConfig.Outputs = append(validConfig.Outputs, TaskOutputConfig{Name: "results", Path: "results/*", Glob: true})
+					Expect(validConfig.Validate()).ToNot(HaveOccurred())
+				})
+
+				Context("when the pattern is malformed", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "results/[", Glob: true})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  output 'results' has an invalid glob path 'results/['")))
+					})
+				})
+
+				Context("when the pattern is not in the last element of the path", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "*/cell", Glob: true})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  output 'results' has an invalid glob path '*/cell'")))
+					})
+				})
+
+				Context("when the pattern has no directory", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "results-*", Glob: true})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  output 'results' has a glob path 'results-*' with no directory to match within")))
+					})
+				})
+
+				Context("when the path is only a pattern", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "*", Glob: true})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  output 'results' has a glob path '*' with no directory to match within")))
+					})
+				})
+
+				Context("when the pattern is directly under the root", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "/*", Glob: true})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  output 'results' has a glob path '/*' with no directory to match within")))
+					})
+				})
+
+				Context("when its directory is another output's path", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs,
+							TaskOutputConfig{Name: "results", Path: "reports/*", Glob: true},
+							TaskOutputConfig{Name: "reports"},
+						)
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  cannot have more than one output using the same path 'reports'")))
+					})
+				})
+
+				Context("when its directory is nested under an input", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "some-input/reports/*", Glob: true})
+						invalidConfig.Inputs = append(invalidConfig.Inputs, TaskInputConfig{Name: "some-input"})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  cannot nest outputs within inputs: 'some-input/reports' is nested under input directory 'some-input'")))
+					})
+				})
+
+				Context("when it also carries a result", func() {
+					BeforeEach(func() {
+						invalidConfig.Outputs = append(invalidConfig.Outputs, TaskOutputConfig{Name: "results", Path: "results/*", Glob: true, Result: true})
+					})
+
+					It("returns an error", func() {
+						Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  output 'results' cannot be a glob and carry a result or env file")))
+					})
+				})
+			})
+		})
+
+		Context("when the task has input groups", func() {
+			BeforeEach(func() {
+				validConfig.InputGroups = append(validConfig.InputGroups, TaskInputGroupConfig{
+					Name: "tls",
+					Inputs: []TaskInputConfig{
+						{Name: "cert"},
+						{Name: "key"},
+					},
+				})
+			})
+
+			It("is valid", func() {
+				Expect(validConfig.Validate()).ToNot(HaveOccurred())
+			})
+
+			Context("when the group's name is missing", func() {
+				BeforeEach(func() {
+					invalidConfig.InputGroups = append(invalidConfig.InputGroups, TaskInputGroupConfig{
+						Inputs: []TaskInputConfig{{Name: "cert"}},
+					})
+				})
+
+				It("returns an error", func() {
+					Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  input group in position 0 is missing a name")))
+				})
+			})
+
+			Context("when the group has no inputs", func() {
+				BeforeEach(func() {
+					invalidConfig.InputGroups = append(invalidConfig.InputGroups, TaskInputGroupConfig{Name: "tls"})
+				})
+
+				It("returns an error", func() {
+					Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  input group 'tls' has no inputs")))
+				})
+			})
+
+			Context("when a group member's name is missing", func() {
+				BeforeEach(func() {
+					invalidConfig.InputGroups = append(invalidConfig.InputGroups, TaskInputGroupConfig{
+						Name:   "tls",
+						Inputs: []TaskInputConfig{{Name: "cert"}, {Name: ""}},
+					})
+				})
+
+				It("returns an error", func() {
+					Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  input in position 1 of input group 'tls' is missing a name")))
+				})
+			})
+
+			Context("when a group member has the same path as an input", func() {
+				BeforeEach(func() {
+					invalidConfig.Inputs = append(invalidConfig.Inputs, TaskInputConfig{Name: "cert"})
+					invalidConfig.InputGroups = append(invalidConfig.InputGroups, TaskInputGroupConfig{
+						Name:   "tls",
+						Inputs: []TaskInputConfig{{Name: "cert"}, {Name: "key"}},
+					})
+				})
+
+				It("returns an error", func() {
+					Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  cannot have more than one input using the same path 'cert'")))
+				})
+			})
 		})
 
 		Context("when run is missing", func() {
@@ -244,6 +400,16 @@ run: {path: a/file}
 			})
 		})
 
+		Context("when ca_certs_path is relative", func() {
+			BeforeEach(func() {
+				invalidConfig.CACertsPath = "etc/ssl/certs/ca-certificates.crt"
+			})
+
+			It("returns an error", func() {
+				Expect(invalidConfig.Validate()).To(MatchError(ContainSubstring("  ca_certs_path must be an absolute path")))
+			})
+		})
+
 		Describe("input overlapping checks", func() {
 			Context("when two inputs have the same name", func() {
 				BeforeEach(func() {
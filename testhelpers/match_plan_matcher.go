@@ -133,5 +133,10 @@ func stripIDs(plan atc.Plan) (atc.Plan, []string) {
 		ids = append(ids, subIDs...)
 	}
 
+	if plan.Staged != nil {
+		plan.Staged.Step, subIDs = stripIDs(plan.Staged.Step)
+		ids = append(ids, subIDs...)
+	}
+
 	return plan, ids
 }
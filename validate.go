@@ -56,6 +56,10 @@ func (c Config) Validate() ([]Warning, []string) {
 	}
 	warnings = append(warnings, jobWarnings...)
 
+	if c.MaxInFlight < 0 {
+		errorMessages = append(errorMessages, formatErr("pipeline", fmt.Errorf("negative max_in_flight: %d", c.MaxInFlight)))
+	}
+
 	return warnings, errorMessages
 }
 
@@ -486,6 +490,22 @@ func validatePlan(c Config, identifier string, plan PlanConfig) ([]Warning, []st
 		}
 	}
 
+	if plan.ScriptTimeout != "" {
+		_, err := time.ParseDuration(plan.ScriptTimeout)
+		if err != nil {
+			subIdentifier := fmt.Sprintf("%s.script_timeout", identifier)
+			errorMessages = append(errorMessages, subIdentifier+fmt.Sprintf(" refers to a duration that could not be parsed ('%s')", plan.ScriptTimeout))
+		}
+	}
+
+	if plan.StopTimeout != "" {
+		_, err := time.ParseDuration(plan.StopTimeout)
+		if err != nil {
+			subIdentifier := fmt.Sprintf("%s.stop_timeout", identifier)
+			errorMessages = append(errorMessages, subIdentifier+fmt.Sprintf(" refers to a duration that could not be parsed ('%s')", plan.StopTimeout))
+		}
+	}
+
 	if plan.Attempts < 0 {
 		subIdentifier := fmt.Sprintf("%s.attempts", identifier)
 		errorMessages = append(errorMessages, subIdentifier+fmt.Sprintf(" has an invalid number of attempts (%d)", plan.Attempts))
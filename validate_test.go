@@ -90,6 +90,18 @@ var _ = Describe("ValidateConfig", func() {
 		})
 	})
 
+	Context("when the pipeline has a negative max_in_flight", func() {
+		BeforeEach(func() {
+			config.MaxInFlight = -1
+		})
+
+		It("returns an error", func() {
+			Expect(errorMessages).To(HaveLen(1))
+			Expect(errorMessages[0]).To(ContainSubstring("invalid pipeline:"))
+			Expect(errorMessages[0]).To(ContainSubstring("negative max_in_flight: -1"))
+		})
+	})
+
 	Describe("invalid groups", func() {
 		Context("when the groups reference a bogus resource", func() {
 			BeforeEach(func() {
@@ -958,6 +970,40 @@ var _ = Describe("ValidateConfig", func() {
 				})
 			})
 
+			Context("when a plan has an invalid script timeout in a task", func() {
+				BeforeEach(func() {
+					job.Plan = append(job.Plan, PlanConfig{
+						Task:           "some-task",
+						TaskConfigPath: "some/config/path.yml",
+						ScriptTimeout:  "nope",
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("throws a validation error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan[0].task.some-task.script_timeout refers to a duration that could not be parsed ('nope')"))
+				})
+			})
+
+			Context("when a plan has an invalid stop timeout in a task", func() {
+				BeforeEach(func() {
+					job.Plan = append(job.Plan, PlanConfig{
+						Task:           "some-task",
+						TaskConfigPath: "some/config/path.yml",
+						StopTimeout:    "nope",
+					})
+
+					config.Jobs = append(config.Jobs, job)
+				})
+
+				It("throws a validation error", func() {
+					Expect(errorMessages).To(HaveLen(1))
+					Expect(errorMessages[0]).To(ContainSubstring("jobs.some-other-job.plan[0].task.some-task.stop_timeout refers to a duration that could not be parsed ('nope')"))
+				})
+			})
+
 			Context("when a plan has an invalid step within a try", func() {
 				BeforeEach(func() {
 					job.Plan = append(job.Plan, PlanConfig{
@@ -25,6 +25,8 @@ type ArtifactName string
 type ArtifactRepository struct {
 	repo  map[ArtifactName]ArtifactSource
 	repoL sync.RWMutex
+
+	parent *ArtifactRepository
 }
 
 // NewArtifactRepository constructs a new repository.
@@ -45,13 +47,58 @@ func (repo *ArtifactRepository) RegisterSource(name ArtifactName, source Artifac
 
 // SourceFor looks up an Source for the given ArtifactName. Consumers of
 // artifacts, e.g. the Task step, will call this to locate their dependencies.
+//
+// A staged repository falls back to its parent for any name it has not
+// registered itself.
 func (repo *ArtifactRepository) SourceFor(name ArtifactName) (ArtifactSource, bool) {
 	repo.repoL.RLock()
 	source, found := repo.repo[name]
 	repo.repoL.RUnlock()
+
+	if !found && repo.parent != nil {
+		return repo.parent.SourceFor(name)
+	}
+
 	return source, found
 }
 
+// Stage returns a new ArtifactRepository layered on top of this one. Sources
+// registered with the staged repository are visible through it, but are not
+// registered with this repository until Commit is called.
+//
+// This is used to hold back the artifacts produced within a scope of the build
+// plan until the whole scope has succeeded.
+func (repo *ArtifactRepository) Stage() *ArtifactRepository {
+	staged := NewArtifactRepository()
+	staged.parent = repo
+	return staged
+}
+
+// Commit registers every source staged so far with the parent repository. It
+// does nothing for a repository that was not constructed with Stage.
+func (repo *ArtifactRepository) Commit() {
+	if repo.parent == nil {
+		return
+	}
+
+	repo.repoL.Lock()
+	staged := repo.repo
+	repo.repo = make(map[ArtifactName]ArtifactSource)
+	repo.repoL.Unlock()
+
+	for name, source := range staged {
+		repo.parent.RegisterSource(name, source)
+	}
+}
+
+// Rollback discards every source staged so far, leaving the parent repository
+// untouched.
+func (repo *ArtifactRepository) Rollback() {
+	repo.repoL.Lock()
+	repo.repo = make(map[ArtifactName]ArtifactSource)
+	repo.repoL.Unlock()
+}
+
 // StreamTo will stream all currently registered artifacts to the destination.
 // This is used by the Put step, which currently does not have an explicit set
 // of dependencies, and instead just pulls in everything.
@@ -59,13 +106,7 @@ func (repo *ArtifactRepository) SourceFor(name ArtifactName) (ArtifactSource, bo
 // Each ArtifactSource will be streamed to a subdirectory matching its
 // ArtifactName.
 func (repo *ArtifactRepository) StreamTo(dest ArtifactDestination) error {
-	sources := map[ArtifactName]ArtifactSource{}
-
-	repo.repoL.RLock()
-	for k, v := range repo.repo {
-		sources[k] = v
-	}
-	repo.repoL.RUnlock()
+	sources := repo.AsMap()
 
 	for name, src := range sources {
 		err := src.StreamTo(subdirectoryDestination{dest, string(name)})
@@ -85,13 +126,7 @@ func (repo *ArtifactRepository) StreamTo(dest ArtifactDestination) error {
 // If the ArtifactSource determined by the path is not present,
 // FileNotFoundError will be returned.
 func (repo *ArtifactRepository) StreamFile(path string) (io.ReadCloser, error) {
-	sources := map[ArtifactName]ArtifactSource{}
-
-	repo.repoL.RLock()
-	for k, v := range repo.repo {
-		sources[k] = v
-	}
-	repo.repoL.RUnlock()
+	sources := repo.AsMap()
 
 	for name, src := range sources {
 		if strings.HasPrefix(path, string(name)+"/") {
@@ -129,9 +164,16 @@ func (repo *ArtifactRepository) ScopedTo(names ...ArtifactName) (*ArtifactReposi
 // AsMap extracts the current contents of the ArtifactRepository into a new map
 // and returns it. Changes to the returned map or the ArtifactRepository will not
 // affect each other.
+//
+// For a staged repository this includes the contents of its parent, with any
+// staged sources taking precedence.
 func (repo *ArtifactRepository) AsMap() map[ArtifactName]ArtifactSource {
 	result := make(map[ArtifactName]ArtifactSource)
 
+	if repo.parent != nil {
+		result = repo.parent.AsMap()
+	}
+
 	repo.repoL.RLock()
 	for name, source := range repo.repo {
 		result[name] = source
@@ -181,5 +181,75 @@ var _ = Describe("ArtifactRepository", func() {
 				})
 			})
 		})
+
+		Describe("Stage", func() {
+			var (
+				staged       *ArtifactRepository
+				stagedSource *workerfakes.FakeArtifactSource
+			)
+
+			BeforeEach(func() {
+				staged = repo.Stage()
+
+				stagedSource = new(workerfakes.FakeArtifactSource)
+				staged.RegisterSource("staged-source", stagedSource)
+			})
+
+			It("yields sources from the parent repository", func() {
+				source, found := staged.SourceFor("first-source")
+				Expect(source).To(Equal(firstSource))
+				Expect(found).To(BeTrue())
+			})
+
+			It("yields staged sources", func() {
+				source, found := staged.SourceFor("staged-source")
+				Expect(source).To(Equal(stagedSource))
+				Expect(found).To(BeTrue())
+			})
+
+			It("includes both in its map", func() {
+				Expect(staged.AsMap()).To(Equal(map[ArtifactName]ArtifactSource{
+					"first-source":  firstSource,
+					"staged-source": stagedSource,
+				}))
+			})
+
+			It("does not register staged sources with the parent", func() {
+				_, found := repo.SourceFor("staged-source")
+				Expect(found).To(BeFalse())
+			})
+
+			Describe("Commit", func() {
+				BeforeEach(func() {
+					staged.Commit()
+				})
+
+				It("registers the staged sources with the parent", func() {
+					source, found := repo.SourceFor("staged-source")
+					Expect(source).To(Equal(stagedSource))
+					Expect(found).To(BeTrue())
+				})
+			})
+
+			Describe("Rollback", func() {
+				BeforeEach(func() {
+					staged.Rollback()
+				})
+
+				It("discards the staged sources", func() {
+					_, found := staged.SourceFor("staged-source")
+					Expect(found).To(BeFalse())
+
+					_, found = repo.SourceFor("staged-source")
+					Expect(found).To(BeFalse())
+				})
+
+				It("leaves the parent's sources in place", func() {
+					source, found := repo.SourceFor("first-source")
+					Expect(source).To(Equal(firstSource))
+					Expect(found).To(BeTrue())
+				})
+			})
+		})
 	})
 })
@@ -2,6 +2,8 @@ package worker
 
 import (
 	"io"
+
+	"code.cloudfoundry.org/lager"
 )
 
 //go:generate counterfeiter . ArtifactSource
@@ -25,3 +27,17 @@ type ArtifactSource interface {
 	// `StreamTo` will be used to copy the data to the destination instead.
 	VolumeOn(Worker) (Volume, bool, error)
 }
+
+//go:generate counterfeiter . CacheableArtifactSource
+
+// CacheableArtifactSource is an ArtifactSource that can have a cache of its
+// data placed on a worker ahead of time, such that VolumeOn will find it
+// there once it has been populated.
+type CacheableArtifactSource interface {
+	ArtifactSource
+
+	// CreateCacheOn creates an empty, uninitialized volume on the worker. Once
+	// the source's data has been streamed in and the volume initialized,
+	// VolumeOn for the worker should return it.
+	CreateCacheOn(lager.Logger, Worker) (Volume, error)
+}
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"io"
 	"os"
 	"time"
 
@@ -54,6 +55,8 @@ type Client interface {
 
 	CreateVolumeForResourceCache(
 		logger lager.Logger,
+		spec WorkerSpec,
+		resourceTypes atc.VersionedResourceTypes,
 		vs VolumeSpec,
 		resourceCache *dbng.UsedResourceCache,
 	) (Volume, error)
@@ -64,12 +67,19 @@ type Client interface {
 	) (Volume, bool, error)
 
 	FindContainerByHandle(lager.Logger, int, string) (Container, bool, error)
+	RunInContainer(logger lager.Logger, teamID int, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
+	StreamContainerOutput(logger lager.Logger, teamID int, handle string) (io.ReadCloser, error)
+	StreamFileFromLatestAttempt(logger lager.Logger, teamID int, buildID int, stepName string, path string) (io.ReadCloser, error)
+	EvacuateWorker(logger lager.Logger, workerName string) error
+	WarmInputs(logger lager.Logger, workerName string, sources []ArtifactSource) error
 	FindResourceTypeByPath(path string) (atc.WorkerResourceType, bool)
 	LookupVolume(lager.Logger, string) (Volume, bool, error)
 
 	Satisfying(lager.Logger, WorkerSpec, atc.VersionedResourceTypes) (Worker, error)
 	AllSatisfying(lager.Logger, WorkerSpec, atc.VersionedResourceTypes) ([]Worker, error)
+	CanEverSatisfy(lager.Logger, WorkerSpec, atc.VersionedResourceTypes) (bool, string, error)
 	RunningWorkers(lager.Logger) ([]Worker, error)
+	ClusterCapacity(lager.Logger) (ClusterCapacity, error)
 }
 
 //go:generate counterfeiter . InputSource
@@ -1,8 +1,10 @@
 package worker
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"code.cloudfoundry.org/clock"
@@ -11,6 +13,7 @@ import (
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/baggageclaim"
+	"github.com/concourse/retryhttp"
 )
 
 const creatingContainerRetryDelay = 1 * time.Second
@@ -30,6 +33,7 @@ type containerProviderFactory struct {
 	dbResourceCacheFactory  dbng.ResourceCacheFactory
 	dbResourceConfigFactory dbng.ResourceConfigFactory
 	dbTeamFactory           dbng.TeamFactory
+	dbContainerFactory      dbng.ContainerFactory
 
 	lockDB LockDB
 
@@ -37,6 +41,8 @@ type containerProviderFactory struct {
 	httpsProxyURL string
 	noProxy       string
 
+	inputStreamPolicy StreamInRetryPolicy
+
 	clock clock.Clock
 }
 
@@ -49,10 +55,12 @@ func NewContainerProviderFactory(
 	dbResourceCacheFactory dbng.ResourceCacheFactory,
 	dbResourceConfigFactory dbng.ResourceConfigFactory,
 	dbTeamFactory dbng.TeamFactory,
+	dbContainerFactory dbng.ContainerFactory,
 	lockDB LockDB,
 	httpProxyURL string,
 	httpsProxyURL string,
 	noProxy string,
+	inputStreamPolicy StreamInRetryPolicy,
 	clock clock.Clock,
 ) ContainerProviderFactory {
 	return &containerProviderFactory{
@@ -64,10 +72,12 @@ func NewContainerProviderFactory(
 		dbResourceCacheFactory:  dbResourceCacheFactory,
 		dbResourceConfigFactory: dbResourceConfigFactory,
 		dbTeamFactory:           dbTeamFactory,
+		dbContainerFactory:      dbContainerFactory,
 		lockDB:                  lockDB,
 		httpProxyURL:            httpProxyURL,
 		httpsProxyURL:           httpsProxyURL,
 		noProxy:                 noProxy,
+		inputStreamPolicy:       inputStreamPolicy,
 		clock:                   clock,
 	}
 }
@@ -82,10 +92,12 @@ func (f *containerProviderFactory) ContainerProviderFor(worker Worker) Container
 		dbResourceCacheFactory:  f.dbResourceCacheFactory,
 		dbResourceConfigFactory: f.dbResourceConfigFactory,
 		dbTeamFactory:           f.dbTeamFactory,
+		dbContainerFactory:      f.dbContainerFactory,
 		lockDB:                  f.lockDB,
 		httpProxyURL:            f.httpProxyURL,
 		httpsProxyURL:           f.httpsProxyURL,
 		noProxy:                 f.noProxy,
+		inputStreamPolicy:       f.inputStreamPolicy,
 		clock:                   f.clock,
 		worker:                  worker,
 	}
@@ -147,6 +159,7 @@ type containerProvider struct {
 	dbResourceCacheFactory  dbng.ResourceCacheFactory
 	dbResourceConfigFactory dbng.ResourceConfigFactory
 	dbTeamFactory           dbng.TeamFactory
+	dbContainerFactory      dbng.ContainerFactory
 
 	lockDB   LockDB
 	provider WorkerProvider
@@ -156,6 +169,8 @@ type containerProvider struct {
 	httpsProxyURL string
 	noProxy       string
 
+	inputStreamPolicy StreamInRetryPolicy
+
 	clock clock.Clock
 }
 
@@ -184,12 +199,29 @@ func (p *containerProvider) FindOrCreateBuildContainer(
 			)
 		},
 		func() (dbng.CreatingContainer, error) {
-			return p.dbTeamFactory.GetByID(spec.TeamID).CreateBuildContainer(
+			creatingContainer, err := p.dbTeamFactory.GetByID(spec.TeamID).CreateBuildContainer(
 				p.worker.Name(),
 				buildID,
 				planID,
 				metadata,
 			)
+			if err != nil {
+				return nil, err
+			}
+
+			// the placement is only kept for post-mortems, so failing to record
+			// it should not fail the build
+			err = p.dbContainerFactory.RecordContainerPlacement(
+				creatingContainer.Handle(),
+				p.worker.Name(),
+				buildID,
+				planID,
+			)
+			if err != nil {
+				logger.Error("failed-to-record-container-placement", err)
+			}
+
+			return creatingContainer, nil
 		},
 	)
 }
@@ -357,6 +389,8 @@ func (p *containerProvider) findOrCreateContainer(
 	findContainerFunc func() (dbng.CreatingContainer, dbng.CreatedContainer, error),
 	createContainerFunc func() (dbng.CreatingContainer, error),
 ) (Container, error) {
+	reportContainerStages(delegate, ContainerStageWorkerChosen)
+
 	for {
 		var gardenContainer garden.Container
 
@@ -377,6 +411,8 @@ func (p *containerProvider) findOrCreateContainer(
 				return nil, err
 			}
 
+			reportContainerStages(delegate, ContainerStageImageResolved, ContainerStageInputsStreamed)
+
 			return p.constructGardenWorkerContainer(
 				logger,
 				createdContainer,
@@ -401,6 +437,8 @@ func (p *containerProvider) findOrCreateContainer(
 
 		if gardenContainer != nil {
 			logger.Debug("found-created-container-in-garden")
+
+			reportContainerStages(delegate, ContainerStageImageResolved, ContainerStageInputsStreamed)
 		} else {
 			image, err := p.imageFactory.GetImage(
 				logger,
@@ -452,20 +490,26 @@ func (p *containerProvider) findOrCreateContainer(
 				return nil, err
 			}
 
+			reportContainerStages(delegate, ContainerStageImageResolved)
+
 			logger.Debug("creating-container-in-garden")
 
 			gardenContainer, err = p.createGardenContainer(
 				logger,
+				cancel,
 				creatingContainer,
 				spec,
 				fetchedImage.Metadata,
 				fetchedImage.URL,
+				delegate,
 			)
 			if err != nil {
 				logger.Error("failed-to-create-container-in-garden", err)
 				return nil, err
 			}
 
+			reportContainerStages(delegate, ContainerStageInputsStreamed)
+
 			logger.Debug("created-container-in-garden")
 		}
 
@@ -510,10 +554,12 @@ func (p *containerProvider) constructGardenWorkerContainer(
 
 func (p *containerProvider) createGardenContainer(
 	logger lager.Logger,
+	cancel <-chan os.Signal,
 	creatingContainer dbng.CreatingContainer,
 	spec ContainerSpec,
 	imageMetadata ImageMetadata,
 	imageURL string,
+	delegate ImageFetchingDelegate,
 ) (garden.Container, error) {
 	volumeMounts := []VolumeMount{}
 
@@ -576,7 +622,20 @@ func (p *containerProvider) createGardenContainer(
 				return nil, err
 			}
 
-			err = inputSource.Source().StreamTo(inputVolume)
+			destination := NewRetryingDestination(
+				newProgressDestination(
+					inputVolume,
+					inputSource.Name(),
+					delegate.Stderr(),
+					p.clock,
+				),
+				&retryhttp.DefaultRetryer{},
+				p.inputStreamPolicy,
+				p.clock,
+				cancel,
+			)
+
+			err = destination.StreamFrom(inputSource.Source())
 			if err != nil {
 				return nil, err
 			}
@@ -647,6 +706,15 @@ func (p *containerProvider) createGardenContainer(
 		env = append(env, fmt.Sprintf("no_proxy=%s", p.noProxy))
 	}
 
+	envJSON, err := json.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+
+	gardenProperties[privilegedPropertyName] = strconv.FormatBool(spec.ImageSpec.Privileged)
+	gardenProperties[envPropertyName] = string(envJSON)
+	gardenProperties[dirPropertyName] = spec.Dir
+
 	return p.gardenClient.Create(garden.ContainerSpec{
 		BindMounts: bindMounts,
 		Privileged: spec.ImageSpec.Privileged,
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/concourse/baggageclaim/baggageclaimfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("ContainerProvider", func() {
@@ -43,6 +45,7 @@ var _ = Describe("ContainerProvider", func() {
 		fakeDBVolumeFactory         *dbngfakes.FakeVolumeFactory
 		fakeDBResourceCacheFactory  *dbngfakes.FakeResourceCacheFactory
 		fakeDBResourceConfigFactory *dbngfakes.FakeResourceConfigFactory
+		fakeDBContainerFactory      *dbngfakes.FakeContainerFactory
 		fakeLockDB                  *workerfakes.FakeLockDB
 		fakeWorker                  *workerfakes.FakeWorker
 
@@ -58,6 +61,8 @@ var _ = Describe("ContainerProvider", func() {
 		fakeLocalCOWVolume             *workerfakes.FakeVolume
 		fakeResourceCacheVolume        *workerfakes.FakeVolume
 
+		fakeClock *fakeclock.FakeClock
+
 		cancel            <-chan os.Signal
 		containerSpec     ContainerSpec
 		resourceUser      dbng.ResourceUser
@@ -94,9 +99,10 @@ var _ = Describe("ContainerProvider", func() {
 		fakeDBTeam = new(dbngfakes.FakeTeam)
 		fakeDBTeamFactory.GetByIDReturns(fakeDBTeam)
 		fakeDBVolumeFactory = new(dbngfakes.FakeVolumeFactory)
-		fakeClock := fakeclock.NewFakeClock(time.Unix(0, 123))
+		fakeClock = fakeclock.NewFakeClock(time.Unix(0, 123))
 		fakeDBResourceCacheFactory = new(dbngfakes.FakeResourceCacheFactory)
 		fakeDBResourceConfigFactory = new(dbngfakes.FakeResourceConfigFactory)
+		fakeDBContainerFactory = new(dbngfakes.FakeContainerFactory)
 		fakeGardenContainer = new(gardenfakes.FakeContainer)
 		fakeGardenClient.CreateReturns(fakeGardenContainer, nil)
 
@@ -109,10 +115,16 @@ var _ = Describe("ContainerProvider", func() {
 			fakeDBResourceCacheFactory,
 			fakeDBResourceConfigFactory,
 			fakeDBTeamFactory,
+			fakeDBContainerFactory,
 			fakeLockDB,
 			"http://proxy.com",
 			"https://proxy.com",
 			"http://noproxy.com",
+			StreamInRetryPolicy{
+				BufferLimit: 10 * 1024 * 1024,
+				Attempts:    3,
+				Backoff:     time.Second,
+			},
 			fakeClock,
 		)
 
@@ -363,7 +375,12 @@ var _ = Describe("ContainerProvider", func() {
 			Expect(actualSpec).To(Equal(garden.ContainerSpec{
 				Handle:     "some-handle",
 				Privileged: true,
-				Properties: garden.Properties{"user": "some-user"},
+				Properties: garden.Properties{
+					"user":                 "some-user",
+					"concourse:privileged": "true",
+					"concourse:env":        `["SOME=ENV","http_proxy=http://proxy.com","https_proxy=https://proxy.com","no_proxy=http://noproxy.com"]`,
+					"concourse:dir":        "/some/work-dir",
+				},
 				BindMounts: []garden.BindMount{
 					{
 						SrcPath: "/fake/work-dir/volume",
@@ -414,6 +431,41 @@ var _ = Describe("ContainerProvider", func() {
 			Expect(ioutil.ReadAll(from)).To(Equal([]byte("some-stream")))
 		})
 
+		Context("while a remote input is being streamed", func() {
+			var stderr *gbytes.Buffer
+
+			BeforeEach(func() {
+				stderr = gbytes.NewBuffer()
+				fakeImageFetchingDelegate.StderrReturns(stderr)
+			})
+
+			It("reports its progress to the delegate at most once a second", func() {
+				disaster := errors.New("nope")
+
+				fakeRemoteInputContainerVolume.StreamInStub = func(dst string, from io.Reader) error {
+					defer GinkgoRecover()
+
+					_, err := io.CopyN(ioutil.Discard, from, 2048)
+					Expect(err).NotTo(HaveOccurred())
+
+					Consistently(stderr.Contents).Should(BeEmpty())
+
+					fakeClock.WaitForWatcherAndIncrement(time.Second)
+					Eventually(stderr).Should(gbytes.Say(`streaming input remote-input: 2.0 KiB transferred \(1s elapsed\)`))
+
+					return disaster
+				}
+
+				ad := fakeRemoteInputAS.StreamToArgsForCall(0)
+
+				err := ad.StreamIn(".", bytes.NewReader(make([]byte, 4096)))
+				Expect(err).To(Equal(disaster))
+
+				fakeClock.Increment(time.Minute)
+				Consistently(stderr).ShouldNot(gbytes.Say("streaming input"))
+			})
+		})
+
 		It("marks container as created", func() {
 			Expect(fakeCreatingContainer.CreatedCallCount()).To(Equal(1))
 		})
@@ -530,6 +582,89 @@ var _ = Describe("ContainerProvider", func() {
 			ItHandlesNonExistentContainer(func() int {
 				return fakeDBTeam.CreateBuildContainerCallCount()
 			})
+
+			Context("when the delegate listens for container stages", func() {
+				var stageDelegate *stageRecordingDelegate
+
+				BeforeEach(func() {
+					stageDelegate = &stageRecordingDelegate{FakeImageFetchingDelegate: fakeImageFetchingDelegate}
+				})
+
+				It("reports each stage as the container is created", func() {
+					_, err := containerProvider.FindOrCreateBuildContainer(
+						logger,
+						cancel,
+						stageDelegate,
+						42,
+						atc.PlanID("some-plan-id"),
+						containerMetadata,
+						containerSpec,
+						resourceTypes,
+					)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(stageDelegate.stages).To(Equal([]string{
+						ContainerStageWorkerChosen,
+						ContainerStageImageResolved,
+						ContainerStageInputsStreamed,
+					}))
+				})
+			})
+
+			It("records which worker the container was placed on", func() {
+				Expect(fakeDBContainerFactory.RecordContainerPlacementCallCount()).To(Equal(1))
+
+				handle, workerName, buildID, planID := fakeDBContainerFactory.RecordContainerPlacementArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(workerName).To(Equal(fakeWorker.Name()))
+				Expect(buildID).To(Equal(42))
+				Expect(planID).To(Equal(atc.PlanID("some-plan-id")))
+			})
+
+			Context("when recording the placement fails", func() {
+				BeforeEach(func() {
+					fakeDBContainerFactory.RecordContainerPlacementReturns(disasterErr)
+				})
+
+				It("still creates the container", func() {
+					Expect(findOrCreateErr).NotTo(HaveOccurred())
+					Expect(findOrCreateContainer).NotTo(BeNil())
+				})
+			})
+		})
+
+		Context("when the container already exists in the database", func() {
+			BeforeEach(func() {
+				fakeDBTeam.FindBuildContainerOnWorkerReturns(nil, fakeCreatedContainer, nil)
+			})
+
+			It("does not record another placement", func() {
+				Expect(fakeDBContainerFactory.RecordContainerPlacementCallCount()).To(BeZero())
+			})
+
+			It("reports every stage at once to a delegate that listens for them", func() {
+				fakeGardenClient.LookupReturns(fakeGardenContainer, nil)
+
+				stageDelegate := &stageRecordingDelegate{FakeImageFetchingDelegate: fakeImageFetchingDelegate}
+
+				_, err := containerProvider.FindOrCreateBuildContainer(
+					logger,
+					cancel,
+					stageDelegate,
+					42,
+					atc.PlanID("some-plan-id"),
+					containerMetadata,
+					containerSpec,
+					resourceTypes,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stageDelegate.stages).To(Equal([]string{
+					ContainerStageWorkerChosen,
+					ContainerStageImageResolved,
+					ContainerStageInputsStreamed,
+				}))
+			})
 		})
 	})
 
@@ -821,3 +956,13 @@ var _ = Describe("ContainerProvider", func() {
 	})
 
 })
+
+type stageRecordingDelegate struct {
+	*workerfakes.FakeImageFetchingDelegate
+
+	stages []string
+}
+
+func (delegate *stageRecordingDelegate) ContainerStage(stage string) {
+	delegate.stages = append(delegate.stages, stage)
+}
@@ -49,6 +49,10 @@ type ImageSpec struct {
 	ImageArtifactSource ArtifactSource
 	ImageArtifactName   ArtifactName
 	Privileged          bool
+
+	// ImageResourceVersion pins the version of ImageResource to fetch, rather
+	// than checking for the latest one.
+	ImageResourceVersion atc.Version
 }
 
 func (spec ContainerSpec) WorkerSpec() WorkerSpec {
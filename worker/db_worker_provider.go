@@ -35,9 +35,11 @@ type dbWorkerProvider struct {
 	dbResourceConfigFactory         dbng.ResourceConfigFactory
 	dbWorkerBaseResourceTypeFactory dbng.WorkerBaseResourceTypeFactory
 	dbVolumeFactory                 dbng.VolumeFactory
+	dbContainerFactory              dbng.ContainerFactory
 	dbTeamFactory                   dbng.TeamFactory
 	dbWorkerFactory                 dbng.WorkerFactory
 	workerVersion                   *version.Version
+	inputStreamPolicy               StreamInRetryPolicy
 }
 
 func NewDBWorkerProvider(
@@ -48,9 +50,11 @@ func NewDBWorkerProvider(
 	dbResourceConfigFactory dbng.ResourceConfigFactory,
 	dbWorkerBaseResourceTypeFactory dbng.WorkerBaseResourceTypeFactory,
 	dbVolumeFactory dbng.VolumeFactory,
+	dbContainerFactory dbng.ContainerFactory,
 	dbTeamFactory dbng.TeamFactory,
 	workerFactory dbng.WorkerFactory,
 	workerVersion *version.Version,
+	inputStreamPolicy StreamInRetryPolicy,
 ) WorkerProvider {
 	return &dbWorkerProvider{
 		lockDB:                          lockDB,
@@ -60,9 +64,11 @@ func NewDBWorkerProvider(
 		dbResourceConfigFactory:         dbResourceConfigFactory,
 		dbWorkerBaseResourceTypeFactory: dbWorkerBaseResourceTypeFactory,
 		dbVolumeFactory:                 dbVolumeFactory,
+		dbContainerFactory:              dbContainerFactory,
 		dbTeamFactory:                   dbTeamFactory,
 		dbWorkerFactory:                 workerFactory,
 		workerVersion:                   workerVersion,
+		inputStreamPolicy:               inputStreamPolicy,
 	}
 }
 
@@ -175,6 +181,33 @@ func (provider *dbWorkerProvider) FindWorkerForResourceCheckContainer(
 	return worker, true, err
 }
 
+func (provider *dbWorkerProvider) FindLatestAttemptContainerHandle(
+	logger lager.Logger,
+	teamID int,
+	buildID int,
+	stepName string,
+) (string, bool, error) {
+	team := provider.dbTeamFactory.GetByID(teamID)
+
+	container, found, err := team.FindLatestAttemptBuildContainer(buildID, stepName)
+	if err != nil {
+		return "", false, err
+	}
+
+	if !found {
+		return "", false, nil
+	}
+
+	return container.Handle(), true, nil
+}
+
+func (provider *dbWorkerProvider) FindEvacuatableContainers(
+	logger lager.Logger,
+	workerName string,
+) ([]dbng.EvacuatableContainer, error) {
+	return provider.dbContainerFactory.FindEvacuatableContainers(workerName)
+}
+
 func (provider *dbWorkerProvider) newGardenWorker(logger lager.Logger, tikTok clock.Clock, savedWorker dbng.Worker) Worker {
 	gcf := NewGardenConnectionFactory(
 		provider.dbWorkerFactory,
@@ -214,10 +247,12 @@ func (provider *dbWorkerProvider) newGardenWorker(logger lager.Logger, tikTok cl
 		provider.dbResourceCacheFactory,
 		provider.dbResourceConfigFactory,
 		provider.dbTeamFactory,
+		provider.dbContainerFactory,
 		provider.lockDB,
 		savedWorker.HTTPProxyURL(),
 		savedWorker.HTTPSProxyURL(),
 		savedWorker.NoProxy(),
+		provider.inputStreamPolicy,
 		clock.NewClock(),
 	)
 
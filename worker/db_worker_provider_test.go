@@ -41,6 +41,7 @@ var _ = Describe("DBProvider", func() {
 		fakeImageFactory                    *workerfakes.FakeImageFactory
 		fakeImageFetchingDelegate           *workerfakes.FakeImageFetchingDelegate
 		fakeDBVolumeFactory                 *dbngfakes.FakeVolumeFactory
+		fakeDBContainerFactory              *dbngfakes.FakeContainerFactory
 		fakeDBWorkerFactory                 *dbngfakes.FakeWorkerFactory
 		fakeDBTeamFactory                   *dbngfakes.FakeTeamFactory
 		fakeDBWorkerBaseResourceTypeFactory *dbngfakes.FakeWorkerBaseResourceTypeFactory
@@ -120,6 +121,7 @@ var _ = Describe("DBProvider", func() {
 		fakeDBTeam = new(dbngfakes.FakeTeam)
 		fakeDBTeamFactory.GetByIDReturns(fakeDBTeam)
 		fakeDBVolumeFactory = new(dbngfakes.FakeVolumeFactory)
+		fakeDBContainerFactory = new(dbngfakes.FakeContainerFactory)
 
 		fakeBackOffFactory := new(retryhttpfakes.FakeBackOffFactory)
 		fakeBackOff := new(retryhttpfakes.FakeBackOff)
@@ -145,9 +147,11 @@ var _ = Describe("DBProvider", func() {
 			fakeDBResourceConfigFactory,
 			fakeDBWorkerBaseResourceTypeFactory,
 			fakeDBVolumeFactory,
+			fakeDBContainerFactory,
 			fakeDBTeamFactory,
 			fakeDBWorkerFactory,
 			&wantWorkerVersion,
+			StreamInRetryPolicy{},
 		)
 		baggageclaimURL = baggageclaimServer.URL()
 	})
@@ -185,7 +189,7 @@ var _ = Describe("DBProvider", func() {
 				Expect(workers).To(HaveLen(2))
 			})
 
-			Context("when some of the workers returned are stalled or landing", func() {
+			Context("when some of the workers returned are stalled, landing or retiring", func() {
 				BeforeEach(func() {
 					landingWorker := new(dbngfakes.FakeWorker)
 					landingWorker.NameReturns("landing-worker")
@@ -205,11 +209,21 @@ var _ = Describe("DBProvider", func() {
 					stalledWorker.ResourceTypesReturns([]atc.WorkerResourceType{
 						{Type: "some-resource-b", Image: "some-image-b"}})
 
+					retiringWorker := new(dbngfakes.FakeWorker)
+					retiringWorker.NameReturns("retiring-worker")
+					retiringWorker.GardenAddrReturns(&gardenAddr)
+					retiringWorker.BaggageclaimURLReturns(&baggageclaimURL)
+					retiringWorker.StateReturns(dbng.WorkerStateRetiring)
+					retiringWorker.ActiveContainersReturns(3)
+					retiringWorker.ResourceTypesReturns([]atc.WorkerResourceType{
+						{Type: "some-resource-b", Image: "some-image-b"}})
+
 					fakeDBWorkerFactory.WorkersReturns(
 						[]dbng.Worker{
 							fakeWorker1,
 							stalledWorker,
 							landingWorker,
+							retiringWorker,
 						}, nil)
 				})
 
@@ -614,6 +628,18 @@ var _ = Describe("DBProvider", func() {
 				Expect(actualTeam).To(Equal(345278))
 			})
 
+			Context("when the worker is being drained", func() {
+				BeforeEach(func() {
+					fakeExistingWorker.StateReturns(dbng.WorkerStateRetiring)
+				})
+
+				It("still returns it, so that its containers can be reattached to", func() {
+					Expect(findErr).ToNot(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(foundWorker.Name()).To(Equal("some-worker"))
+				})
+			})
+
 			Context("when the worker version is outdated", func() {
 				BeforeEach(func() {
 					fakeExistingWorker.VersionReturns(nil)
@@ -77,6 +77,7 @@ func (f *imageFactory) GetImage(
 			resourceUser,
 			imageResource.Type,
 			imageResource.Source,
+			imageSpec.ImageResourceVersion,
 			worker.Tags(),
 			teamID,
 			resourceTypes,
@@ -16,6 +16,7 @@ import (
 	"github.com/concourse/atc/dbng"
 	"github.com/concourse/atc/resource"
 	"github.com/concourse/atc/worker"
+	"github.com/concourse/retryhttp"
 )
 
 const ImageMetadataFile = "metadata.json"
@@ -26,6 +27,10 @@ var ErrImageUnavailable = errors.New("no versions of image available")
 
 var ErrImageGetDidNotProduceVolume = errors.New("fetching the image did not produce a volume")
 
+// imageFetchRetryInterval is how long to wait before the first retry of a
+// failed check or get of the image. It doubles with each retry.
+const imageFetchRetryInterval = time.Second
+
 //go:generate counterfeiter . ImageResourceFetcherFactory
 
 type ImageResourceFetcherFactory interface {
@@ -41,6 +46,7 @@ type ImageResourceFetcher interface {
 		resourceUser dbng.ResourceUser,
 		imageResourceType string,
 		imageResourceSource atc.Source,
+		imageResourceVersion atc.Version,
 		tags atc.Tags,
 		teamID int,
 		customTypes atc.VersionedResourceTypes,
@@ -54,6 +60,7 @@ type imageResourceFetcherFactory struct {
 	resourceFactoryFactory  resource.ResourceFactoryFactory
 	dbResourceCacheFactory  dbng.ResourceCacheFactory
 	dbResourceConfigFactory dbng.ResourceConfigFactory
+	retries                 int
 	clock                   clock.Clock
 }
 
@@ -62,6 +69,7 @@ func NewImageResourceFetcherFactory(
 	resourceFactoryFactory resource.ResourceFactoryFactory,
 	dbResourceCacheFactory dbng.ResourceCacheFactory,
 	dbResourceConfigFactory dbng.ResourceConfigFactory,
+	retries int,
 	clock clock.Clock,
 ) ImageResourceFetcherFactory {
 	return &imageResourceFetcherFactory{
@@ -69,7 +77,8 @@ func NewImageResourceFetcherFactory(
 		resourceFactoryFactory:  resourceFactoryFactory,
 		dbResourceCacheFactory:  dbResourceCacheFactory,
 		dbResourceConfigFactory: dbResourceConfigFactory,
-		clock: clock,
+		retries:                 retries,
+		clock:                   clock,
 	}
 }
 
@@ -79,7 +88,9 @@ func (f *imageResourceFetcherFactory) ImageResourceFetcherFor(worker worker.Work
 		resourceFactory:         f.resourceFactoryFactory.FactoryFor(worker),
 		dbResourceCacheFactory:  f.dbResourceCacheFactory,
 		dbResourceConfigFactory: f.dbResourceConfigFactory,
-		clock: f.clock,
+		retries:                 f.retries,
+		retryer:                 &retryhttp.DefaultRetryer{},
+		clock:                   f.clock,
 	}
 }
 
@@ -88,6 +99,8 @@ type imageResourceFetcher struct {
 	resourceFactory         resource.ResourceFactory
 	dbResourceCacheFactory  dbng.ResourceCacheFactory
 	dbResourceConfigFactory dbng.ResourceConfigFactory
+	retries                 int
+	retryer                 retryhttp.Retryer
 	clock                   clock.Clock
 }
 
@@ -97,16 +110,23 @@ func (i *imageResourceFetcher) Fetch(
 	resourceUser dbng.ResourceUser,
 	imageResourceType string,
 	imageResourceSource atc.Source,
+	imageResourceVersion atc.Version,
 	tags atc.Tags,
 	teamID int,
 	customTypes atc.VersionedResourceTypes,
 	imageFetchingDelegate worker.ImageFetchingDelegate,
 	privileged bool,
 ) (worker.Volume, io.ReadCloser, atc.Version, error) {
-	version, err := i.getLatestVersion(logger, signals, resourceUser, imageResourceType, imageResourceSource, tags, teamID, customTypes, imageFetchingDelegate)
-	if err != nil {
-		logger.Error("failed-to-get-latest-image-version", err)
-		return nil, nil, nil, err
+	version := imageResourceVersion
+	if version == nil {
+		var err error
+		version, err = i.getLatestVersion(logger, signals, resourceUser, imageResourceType, imageResourceSource, tags, teamID, customTypes, imageFetchingDelegate)
+		if err != nil {
+			logger.Error("failed-to-get-latest-image-version", err)
+			return nil, nil, nil, err
+		}
+	} else {
+		logger.Debug("using-pinned-image-version", lager.Data{"version": version})
 	}
 
 	resourceInstance := resource.NewResourceInstance(
@@ -119,7 +139,7 @@ func (i *imageResourceFetcher) Fetch(
 		i.dbResourceCacheFactory,
 	)
 
-	err = imageFetchingDelegate.ImageVersionDetermined(
+	err := imageFetchingDelegate.ImageVersionDetermined(
 		resourceInstance.ResourceCacheIdentifier(),
 	)
 	if err != nil {
@@ -142,19 +162,24 @@ func (i *imageResourceFetcher) Fetch(
 	}
 
 	// we need resource cache for build
-	fetchSource, err := i.resourceFetcher.Fetch(
-		logger.Session("init-image"),
-		getSess,
-		tags,
-		teamID,
-		customTypes,
-		resourceInstance,
-		resource.EmptyMetadata{},
-		imageFetchingDelegate,
-		resourceOptions,
-		signals,
-		make(chan struct{}),
-	)
+	var fetchSource resource.FetchSource
+	err = i.retry(logger, signals, imageFetchingDelegate, "fetch", func() error {
+		var err error
+		fetchSource, err = i.resourceFetcher.Fetch(
+			logger.Session("init-image"),
+			getSess,
+			tags,
+			teamID,
+			customTypes,
+			resourceInstance,
+			resource.EmptyMetadata{},
+			imageFetchingDelegate,
+			resourceOptions,
+			signals,
+			make(chan struct{}),
+		)
+		return err
+	})
 	if err != nil {
 		logger.Error("failed-to-fetch-image", err)
 		return nil, nil, nil, err
@@ -224,8 +249,13 @@ func (i *imageResourceFetcher) getLatestVersion(
 
 		if !acquired {
 			logger.Debug("did-not-get-lock")
-			i.clock.Sleep(time.Second)
-			continue
+
+			select {
+			case <-i.clock.After(time.Second):
+				continue
+			case <-signals:
+				return nil, resource.ErrInterrupted
+			}
 		}
 
 		defer lock.Release()
@@ -250,7 +280,12 @@ func (i *imageResourceFetcher) getLatestVersion(
 		return nil, err
 	}
 
-	versions, err := checkingResource.Check(imageResourceSource, nil)
+	var versions []atc.Version
+	err = i.retry(logger, signals, imageFetchingDelegate, "check", func() error {
+		var err error
+		versions, err = checkingResource.Check(imageResourceSource, nil)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +297,39 @@ func (i *imageResourceFetcher) getLatestVersion(
 	return versions[0], nil
 }
 
+// retry runs the action, running it again up to the configured number of
+// retries if it fails with a transient error, e.g. the registry being
+// unreachable. Each retry is announced on the delegate's stderr and waits
+// twice as long as the last. Waiting is abandoned if the build is aborted.
+func (i *imageResourceFetcher) retry(
+	logger lager.Logger,
+	signals <-chan os.Signal,
+	imageFetchingDelegate worker.ImageFetchingDelegate,
+	action string,
+	run func() error,
+) error {
+	interval := imageFetchRetryInterval
+
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil || err == ErrImageUnavailable || attempt > i.retries || !i.retryer.IsRetryable(err) {
+			return err
+		}
+
+		logger.Info("retrying-image-"+action, lager.Data{"attempt": attempt, "error": err.Error()})
+
+		fmt.Fprintf(imageFetchingDelegate.Stderr(), "failed to %s image: %s; retrying in %s\n", action, err, interval)
+
+		select {
+		case <-i.clock.After(interval):
+		case <-signals:
+			return resource.ErrInterrupted
+		}
+
+		interval *= 2
+	}
+}
+
 type leaseID struct {
 	Type       resource.ResourceType `json:"type"`
 	Version    atc.Version           `json:"version"`
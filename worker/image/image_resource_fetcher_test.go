@@ -56,6 +56,8 @@ var _ = Describe("Image", func() {
 	var fetchedVersion atc.Version
 	var fetchErr error
 	var teamID int
+	var pinnedVersion atc.Version
+	var imageFetchRetries int
 
 	BeforeEach(func() {
 		fakeResourceFactory = new(rfakes.FakeResourceFactory)
@@ -79,6 +81,8 @@ var _ = Describe("Image", func() {
 		fakeImageFetchingDelegate.StderrReturns(stderrBuf)
 		fakeWorker = new(wfakes.FakeWorker)
 		teamID = 123
+		imageFetchRetries = 2
+		pinnedVersion = nil
 
 		customTypes = atc.VersionedResourceTypes{
 			{
@@ -106,6 +110,7 @@ var _ = Describe("Image", func() {
 			fakeResourceFactoryFactory,
 			fakeResourceCacheFactory,
 			fakeResourceConfigFactory,
+			imageFetchRetries,
 			fakeClock,
 		).ImageResourceFetcherFor(fakeWorker)
 	})
@@ -117,6 +122,7 @@ var _ = Describe("Image", func() {
 			dbng.ForBuild(42),
 			imageResource.Type,
 			imageResource.Source,
+			pinnedVersion,
 			atc.Tags{"worker", "tags"},
 			teamID,
 			customTypes,
@@ -180,6 +186,22 @@ var _ = Describe("Image", func() {
 							fakeFetchSource.VersionedSourceReturns(fakeVersionedSource)
 						})
 
+						Context("when the image version is pinned", func() {
+							BeforeEach(func() {
+								pinnedVersion = atc.Version{"v": "0"}
+							})
+
+							It("does not check for the latest version", func() {
+								Expect(fakeResourceFactory.NewCheckResourceCallCount()).To(BeZero())
+								Expect(fakeCheckResource.CheckCallCount()).To(BeZero())
+							})
+
+							It("fetches the pinned version", func() {
+								Expect(fetchErr).NotTo(HaveOccurred())
+								Expect(fetchedVersion).To(Equal(atc.Version{"v": "0"}))
+							})
+						})
+
 						Context("when the resource has a volume", func() {
 							var (
 								fakeVolume *wfakes.FakeVolume
@@ -414,6 +436,98 @@ var _ = Describe("Image", func() {
 					Expect(fakeResourceFetcher.FetchCallCount()).To(Equal(0))
 				})
 			})
+
+			Context("when check fails with a transient error", func() {
+				var (
+					transientErr error
+					failures     int
+				)
+
+				BeforeEach(func() {
+					transientErr = errors.New("dial tcp 10.0.0.1:443: getsockopt: connection refused")
+					failures = 2
+
+					checks := 0
+					fakeCheckResource.CheckStub = func(atc.Source, atc.Version) ([]atc.Version, error) {
+						checks++
+
+						if checks <= failures {
+							go fakeClock.WaitForWatcherAndIncrement(time.Minute)
+							return nil, transientErr
+						}
+
+						return []atc.Version{{"v": "1"}}, nil
+					}
+
+					fakeResourceFetcher.FetchReturns(nil, resource.ErrInterrupted)
+				})
+
+				It("retries the check until it succeeds", func() {
+					Expect(fakeCheckResource.CheckCallCount()).To(Equal(3))
+					Expect(fakeResourceFetcher.FetchCallCount()).To(Equal(1))
+				})
+
+				It("says so on stderr before each retry, backing off", func() {
+					Expect(stderrBuf).To(gbytes.Say("failed to check image: .*connection refused; retrying in 1s"))
+					Expect(stderrBuf).To(gbytes.Say("failed to check image: .*connection refused; retrying in 2s"))
+				})
+
+				Context("when it keeps failing", func() {
+					BeforeEach(func() {
+						failures = 100
+					})
+
+					It("gives up after the configured number of retries", func() {
+						Expect(fetchErr).To(Equal(transientErr))
+						Expect(fakeCheckResource.CheckCallCount()).To(Equal(3))
+						Expect(fakeResourceFetcher.FetchCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when aborted while waiting to retry", func() {
+					BeforeEach(func() {
+						abort := make(chan os.Signal, 1)
+						abort <- os.Interrupt
+						signals = abort
+
+						fakeCheckResource.CheckReturns(nil, transientErr)
+						fakeCheckResource.CheckStub = nil
+					})
+
+					It("returns ErrInterrupted without retrying", func() {
+						Expect(fetchErr).To(Equal(resource.ErrInterrupted))
+						Expect(fakeCheckResource.CheckCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			Context("when fetching the image fails with a transient error", func() {
+				var transientErr error
+
+				BeforeEach(func() {
+					transientErr = errors.New("read tcp 10.0.0.1:443: i/o timeout")
+
+					fakeCheckResource.CheckReturns([]atc.Version{{"v": "1"}}, nil)
+
+					fetches := 0
+					fakeResourceFetcher.FetchStub = func(lager.Logger, resource.Session, atc.Tags, int, atc.VersionedResourceTypes, resource.ResourceInstance, resource.Metadata, worker.ImageFetchingDelegate, resource.ResourceOptions, <-chan os.Signal, chan<- struct{}) (resource.FetchSource, error) {
+						fetches++
+
+						if fetches == 1 {
+							go fakeClock.WaitForWatcherAndIncrement(time.Minute)
+							return nil, transientErr
+						}
+
+						return nil, resource.ErrInterrupted
+					}
+				})
+
+				It("retries the fetch", func() {
+					Expect(fakeResourceFetcher.FetchCallCount()).To(Equal(2))
+					Expect(fetchErr).To(Equal(resource.ErrInterrupted))
+					Expect(stderrBuf).To(gbytes.Say("failed to fetch image: .*i/o timeout; retrying in 1s"))
+				})
+			})
 		})
 
 		Context("when initializing the Check resource fails", func() {
@@ -467,6 +581,29 @@ var _ = Describe("Image", func() {
 		})
 	})
 
+	Context("when aborted while waiting for the lock", func() {
+		BeforeEach(func() {
+			abort := make(chan os.Signal, 1)
+			abort <- os.Interrupt
+			signals = abort
+
+			fakeResourceConfigFactory.AcquireResourceCheckingLockReturns(nil, false, nil)
+		})
+
+		It("returns ErrInterrupted", func() {
+			Expect(fetchErr).To(Equal(resource.ErrInterrupted))
+		})
+
+		It("stops retrying the lock", func() {
+			Expect(fakeResourceConfigFactory.AcquireResourceCheckingLockCallCount()).To(Equal(1))
+		})
+
+		It("does not check or fetch the image", func() {
+			Expect(fakeResourceFactory.NewCheckResourceCallCount()).To(BeZero())
+			Expect(fakeResourceFetcher.FetchCallCount()).To(BeZero())
+		})
+	})
+
 	Context("when acquiring resource checking lock fails", func() {
 		var disaster = errors.New("disaster")
 
@@ -14,7 +14,7 @@ import (
 )
 
 type FakeImageResourceFetcher struct {
-	FetchStub        func(logger lager.Logger, signals <-chan os.Signal, resourceUser dbng.ResourceUser, imageResourceType string, imageResourceSource atc.Source, tags atc.Tags, teamID int, customTypes atc.VersionedResourceTypes, imageFetchingDelegate worker.ImageFetchingDelegate, privileged bool) (worker.Volume, io.ReadCloser, atc.Version, error)
+	FetchStub        func(logger lager.Logger, signals <-chan os.Signal, resourceUser dbng.ResourceUser, imageResourceType string, imageResourceSource atc.Source, imageResourceVersion atc.Version, tags atc.Tags, teamID int, customTypes atc.VersionedResourceTypes, imageFetchingDelegate worker.ImageFetchingDelegate, privileged bool) (worker.Volume, io.ReadCloser, atc.Version, error)
 	fetchMutex       sync.RWMutex
 	fetchArgsForCall []struct {
 		logger                lager.Logger
@@ -22,6 +22,7 @@ type FakeImageResourceFetcher struct {
 		resourceUser          dbng.ResourceUser
 		imageResourceType     string
 		imageResourceSource   atc.Source
+		imageResourceVersion  atc.Version
 		tags                  atc.Tags
 		teamID                int
 		customTypes           atc.VersionedResourceTypes
@@ -44,7 +45,7 @@ type FakeImageResourceFetcher struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeImageResourceFetcher) Fetch(logger lager.Logger, signals <-chan os.Signal, resourceUser dbng.ResourceUser, imageResourceType string, imageResourceSource atc.Source, tags atc.Tags, teamID int, customTypes atc.VersionedResourceTypes, imageFetchingDelegate worker.ImageFetchingDelegate, privileged bool) (worker.Volume, io.ReadCloser, atc.Version, error) {
+func (fake *FakeImageResourceFetcher) Fetch(logger lager.Logger, signals <-chan os.Signal, resourceUser dbng.ResourceUser, imageResourceType string, imageResourceSource atc.Source, imageResourceVersion atc.Version, tags atc.Tags, teamID int, customTypes atc.VersionedResourceTypes, imageFetchingDelegate worker.ImageFetchingDelegate, privileged bool) (worker.Volume, io.ReadCloser, atc.Version, error) {
 	fake.fetchMutex.Lock()
 	ret, specificReturn := fake.fetchReturnsOnCall[len(fake.fetchArgsForCall)]
 	fake.fetchArgsForCall = append(fake.fetchArgsForCall, struct {
@@ -53,16 +54,17 @@ func (fake *FakeImageResourceFetcher) Fetch(logger lager.Logger, signals <-chan
 		resourceUser          dbng.ResourceUser
 		imageResourceType     string
 		imageResourceSource   atc.Source
+		imageResourceVersion  atc.Version
 		tags                  atc.Tags
 		teamID                int
 		customTypes           atc.VersionedResourceTypes
 		imageFetchingDelegate worker.ImageFetchingDelegate
 		privileged            bool
-	}{logger, signals, resourceUser, imageResourceType, imageResourceSource, tags, teamID, customTypes, imageFetchingDelegate, privileged})
-	fake.recordInvocation("Fetch", []interface{}{logger, signals, resourceUser, imageResourceType, imageResourceSource, tags, teamID, customTypes, imageFetchingDelegate, privileged})
+	}{logger, signals, resourceUser, imageResourceType, imageResourceSource, imageResourceVersion, tags, teamID, customTypes, imageFetchingDelegate, privileged})
+	fake.recordInvocation("Fetch", []interface{}{logger, signals, resourceUser, imageResourceType, imageResourceSource, imageResourceVersion, tags, teamID, customTypes, imageFetchingDelegate, privileged})
 	fake.fetchMutex.Unlock()
 	if fake.FetchStub != nil {
-		return fake.FetchStub(logger, signals, resourceUser, imageResourceType, imageResourceSource, tags, teamID, customTypes, imageFetchingDelegate, privileged)
+		return fake.FetchStub(logger, signals, resourceUser, imageResourceType, imageResourceSource, imageResourceVersion, tags, teamID, customTypes, imageFetchingDelegate, privileged)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3, ret.result4
@@ -76,10 +78,10 @@ func (fake *FakeImageResourceFetcher) FetchCallCount() int {
 	return len(fake.fetchArgsForCall)
 }
 
-func (fake *FakeImageResourceFetcher) FetchArgsForCall(i int) (lager.Logger, <-chan os.Signal, dbng.ResourceUser, string, atc.Source, atc.Tags, int, atc.VersionedResourceTypes, worker.ImageFetchingDelegate, bool) {
+func (fake *FakeImageResourceFetcher) FetchArgsForCall(i int) (lager.Logger, <-chan os.Signal, dbng.ResourceUser, string, atc.Source, atc.Version, atc.Tags, int, atc.VersionedResourceTypes, worker.ImageFetchingDelegate, bool) {
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
-	return fake.fetchArgsForCall[i].logger, fake.fetchArgsForCall[i].signals, fake.fetchArgsForCall[i].resourceUser, fake.fetchArgsForCall[i].imageResourceType, fake.fetchArgsForCall[i].imageResourceSource, fake.fetchArgsForCall[i].tags, fake.fetchArgsForCall[i].teamID, fake.fetchArgsForCall[i].customTypes, fake.fetchArgsForCall[i].imageFetchingDelegate, fake.fetchArgsForCall[i].privileged
+	return fake.fetchArgsForCall[i].logger, fake.fetchArgsForCall[i].signals, fake.fetchArgsForCall[i].resourceUser, fake.fetchArgsForCall[i].imageResourceType, fake.fetchArgsForCall[i].imageResourceSource, fake.fetchArgsForCall[i].imageResourceVersion, fake.fetchArgsForCall[i].tags, fake.fetchArgsForCall[i].teamID, fake.fetchArgsForCall[i].customTypes, fake.fetchArgsForCall[i].imageFetchingDelegate, fake.fetchArgsForCall[i].privileged
 }
 
 func (fake *FakeImageResourceFetcher) FetchReturns(result1 worker.Volume, result2 io.ReadCloser, result3 atc.Version, result4 error) {
@@ -48,6 +48,30 @@ type ImageFetchingDelegate interface {
 	ImageVersionDetermined(ResourceCacheIdentifier) error
 }
 
+// Stages of finding or creating a container, reported to an
+// ImageFetchingDelegate which is also a ContainerStageDelegate. A container
+// that already exists passes all of them at once.
+const (
+	ContainerStageWorkerChosen   = "worker-chosen"
+	ContainerStageImageResolved  = "image-resolved"
+	ContainerStageInputsStreamed = "inputs-streamed"
+)
+
+type ContainerStageDelegate interface {
+	ContainerStage(stage string)
+}
+
+func reportContainerStages(delegate ImageFetchingDelegate, stages ...string) {
+	stageDelegate, ok := delegate.(ContainerStageDelegate)
+	if !ok {
+		return
+	}
+
+	for _, stage := range stages {
+		stageDelegate.ContainerStage(stage)
+	}
+}
+
 type ImageMetadata struct {
 	Env  []string `json:"env"`
 	User string   `json:"user"`
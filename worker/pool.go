@@ -1,16 +1,22 @@
 package worker
 
 import (
+	"archive/tar"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
+	"io"
 	"os"
 	"path/filepath"
-	"time"
+	"sort"
 
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
+	"github.com/concourse/retryhttp"
+	"github.com/hashicorp/go-multierror"
 	"strings"
 )
 
@@ -40,6 +46,18 @@ type WorkerProvider interface {
 		buildID int,
 		planID atc.PlanID,
 	) (Worker, bool, error)
+
+	FindLatestAttemptContainerHandle(
+		logger lager.Logger,
+		teamID int,
+		buildID int,
+		stepName string,
+	) (string, bool, error)
+
+	FindEvacuatableContainers(
+		logger lager.Logger,
+		workerName string,
+	) ([]dbng.EvacuatableContainer, error)
 }
 
 var (
@@ -48,8 +66,9 @@ var (
 )
 
 type NoCompatibleWorkersError struct {
-	Spec    WorkerSpec
-	Workers []Worker
+	Spec       WorkerSpec
+	Workers    []Worker
+	Mismatches []WorkerMismatch
 }
 
 func (err NoCompatibleWorkersError) Error() string {
@@ -58,23 +77,169 @@ func (err NoCompatibleWorkersError) Error() string {
 		availableWorkers += "\n  - " + worker.Description()
 	}
 
-	return fmt.Sprintf(
+	message := fmt.Sprintf(
 		"no workers satisfying: %s\n\navailable workers: %s",
 		err.Spec.Description(),
 		availableWorkers,
 	)
+
+	if len(err.Mismatches) > 0 {
+		message += "\n\nrejected workers:"
+		for _, mismatch := range err.Mismatches {
+			message += "\n  - " + mismatch.String()
+		}
+	}
+
+	return message
+}
+
+// WorkerMismatch records why a worker can't satisfy a WorkerSpec. Reason is
+// the error returned by the worker's Satisfying, e.g. ErrIncompatiblePlatform,
+// and Detail describes the mismatch in terms of the worker and the spec.
+type WorkerMismatch struct {
+	Worker string
+	Reason error
+	Detail string
+}
+
+func (mismatch WorkerMismatch) String() string {
+	return fmt.Sprintf("worker %s rejected: %s", mismatch.Worker, mismatch.Detail)
 }
 
+func newWorkerMismatch(worker Worker, spec WorkerSpec, reason error) WorkerMismatch {
+	var detail string
+
+	switch reason {
+	case ErrTeamMismatch:
+		detail = "worker belongs to another team"
+	case ErrUnsupportedResourceType:
+		detail = fmt.Sprintf("resource type '%s' is not supported", spec.ResourceType)
+	case ErrIncompatiblePlatform:
+		detail = fmt.Sprintf("platform %s != %s", worker.Platform(), spec.Platform)
+	case ErrMismatchedTags:
+		detail = fmt.Sprintf("tags [%s] do not match [%s]", strings.Join(worker.Tags(), ", "), strings.Join(spec.Tags, ", "))
+	default:
+		detail = reason.Error()
+	}
+
+	return WorkerMismatch{
+		Worker: worker.Name(),
+		Reason: reason,
+		Detail: detail,
+	}
+}
+
+// WorkerNotCreatedError records why a container could not be created on a
+// particular worker.
+type WorkerNotCreatedError struct {
+	Worker string
+	Err    error
+}
+
+func (err WorkerNotCreatedError) Error() string {
+	return fmt.Sprintf("worker %s: %s", err.Worker, err.Err)
+}
+
+// AllWorkersFailedError is returned when every compatible worker was tried
+// and none of them could create the container.
+type AllWorkersFailedError struct {
+	Failures []WorkerNotCreatedError
+}
+
+func (err AllWorkersFailedError) Error() string {
+	message := "failed to create container on all compatible workers"
+	for _, failure := range err.Failures {
+		message += "\n  - " + failure.Error()
+	}
+
+	return message
+}
+
+// NoAttemptContainerError is returned when a build step has no container for
+// any of its attempts, e.g. because it never ran or has since been reaped.
+type NoAttemptContainerError struct {
+	BuildID  int
+	StepName string
+}
+
+func (err NoAttemptContainerError) Error() string {
+	return fmt.Sprintf("no container found for latest attempt of step '%s' in build %d", err.StepName, err.BuildID)
+}
+
+// ClusterCapacity summarizes how many containers the running workers can
+// hold and how many they are currently running.
+//
+// Workers whose capacity cannot be read are left out of the totals and named
+// in UnavailableWorkers, and Partial is set so that the totals are not
+// mistaken for the whole cluster.
+type ClusterCapacity struct {
+	Workers          int
+	MaxContainers    int
+	ActiveContainers int
+	FreeContainers   int
+
+	Platforms map[string]PlatformCapacity
+	PerWorker map[string]WorkerCapacity
+
+	Partial            bool
+	UnavailableWorkers []string
+}
+
+// PlatformCapacity is the share of ClusterCapacity held by workers of a
+// single platform.
+type PlatformCapacity struct {
+	Workers          int
+	MaxContainers    int
+	ActiveContainers int
+	FreeContainers   int
+}
+
+// WorkerCapacity is the share of ClusterCapacity held by a single worker, as
+// last reported by the worker itself.
+type WorkerCapacity struct {
+	Platform         string
+	MaxContainers    int
+	ActiveContainers int
+	FreeContainers   int
+}
+
+// TeamWorkerAffinity determines how strongly a team's containers are tied to
+// the workers the team owns.
+type TeamWorkerAffinity string
+
+const (
+	// TeamWorkerAffinityStrict places a team's containers only on its own
+	// workers, if it has any satisfying the spec.
+	TeamWorkerAffinityStrict TeamWorkerAffinity = "strict"
+
+	// TeamWorkerAffinityPreferred places a team's containers on its own
+	// workers while they have capacity left, and on general workers once they
+	// are all at maxContainersPerWorker.
+	TeamWorkerAffinityPreferred TeamWorkerAffinity = "preferred"
+)
+
 type pool struct {
-	provider WorkerProvider
+	provider   WorkerProvider
+	strategy   SelectionStrategy
+	constraint SelectionConstraint
 
-	rand *rand.Rand
+	maxContainersPerWorker int
+	teamAffinity           TeamWorkerAffinity
+	inputStreamPolicy      StreamInRetryPolicy
 }
 
-func NewPool(provider WorkerProvider) Client {
+// NewPool constructs a Client that places containers on the workers returned
+// by the provider. The constraint is optional; if nil, all of the workers
+// satisfying a spec are considered.
+func NewPool(provider WorkerProvider, strategy SelectionStrategy, constraint SelectionConstraint, maxContainersPerWorker int, teamAffinity TeamWorkerAffinity, inputStreamPolicy StreamInRetryPolicy) Client {
 	return &pool{
-		provider: provider,
-		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		provider:   provider,
+		strategy:   strategy,
+		constraint: constraint,
+
+		maxContainersPerWorker: maxContainersPerWorker,
+		teamAffinity:           teamAffinity,
+		inputStreamPolicy:      inputStreamPolicy,
 	}
 }
 
@@ -82,6 +247,58 @@ func (pool *pool) RunningWorkers(logger lager.Logger) ([]Worker, error) {
 	return pool.provider.RunningWorkers(logger)
 }
 
+func (pool *pool) ClusterCapacity(logger lager.Logger) (ClusterCapacity, error) {
+	workers, err := pool.provider.RunningWorkers(logger)
+	if err != nil {
+		return ClusterCapacity{}, err
+	}
+
+	capacity := ClusterCapacity{
+		Platforms: map[string]PlatformCapacity{},
+		PerWorker: map[string]WorkerCapacity{},
+	}
+
+	for _, worker := range workers {
+		active := worker.ActiveContainers()
+		if active < 0 {
+			logger.Info("skipping-worker-with-invalid-container-count", lager.Data{
+				"worker":            worker.Name(),
+				"active-containers": active,
+			})
+
+			capacity.Partial = true
+			capacity.UnavailableWorkers = append(capacity.UnavailableWorkers, worker.Name())
+			continue
+		}
+
+		free := pool.maxContainersPerWorker - active
+		if free < 0 {
+			free = 0
+		}
+
+		capacity.Workers++
+		capacity.MaxContainers += pool.maxContainersPerWorker
+		capacity.ActiveContainers += active
+		capacity.FreeContainers += free
+
+		platform := capacity.Platforms[worker.Platform()]
+		platform.Workers++
+		platform.MaxContainers += pool.maxContainersPerWorker
+		platform.ActiveContainers += active
+		platform.FreeContainers += free
+		capacity.Platforms[worker.Platform()] = platform
+
+		capacity.PerWorker[worker.Name()] = WorkerCapacity{
+			Platform:         worker.Platform(),
+			MaxContainers:    pool.maxContainersPerWorker,
+			ActiveContainers: active,
+			FreeContainers:   free,
+		}
+	}
+
+	return capacity, nil
+}
+
 func (pool *pool) AllSatisfying(logger lager.Logger, spec WorkerSpec, resourceTypes atc.VersionedResourceTypes) ([]Worker, error) {
 	workers, err := pool.provider.RunningWorkers(logger)
 	if err != nil {
@@ -94,29 +311,96 @@ func (pool *pool) AllSatisfying(logger lager.Logger, spec WorkerSpec, resourceTy
 
 	compatibleTeamWorkers := []Worker{}
 	compatibleGeneralWorkers := []Worker{}
+	mismatches := []WorkerMismatch{}
 	for _, worker := range workers {
-		satisfyingWorker, err := worker.Satisfying(logger, spec, resourceTypes)
-		if err == nil {
-			if worker.IsOwnedByTeam() {
-				compatibleTeamWorkers = append(compatibleTeamWorkers, satisfyingWorker)
-			} else {
-				compatibleGeneralWorkers = append(compatibleGeneralWorkers, satisfyingWorker)
-			}
+		satisfyingWorker, err := worker.Satisfying(logger, pool.constrainedSpec(worker, spec), resourceTypes)
+		if err != nil {
+			mismatches = append(mismatches, newWorkerMismatch(worker, spec, err))
+			continue
+		}
+
+		if worker.IsOwnedByTeam() {
+			compatibleTeamWorkers = append(compatibleTeamWorkers, satisfyingWorker)
+		} else {
+			compatibleGeneralWorkers = append(compatibleGeneralWorkers, satisfyingWorker)
 		}
 	}
 
 	if len(compatibleTeamWorkers) != 0 {
-		return compatibleTeamWorkers, nil
+		if pool.teamAffinity != TeamWorkerAffinityPreferred || len(compatibleGeneralWorkers) == 0 {
+			return pool.constrain(logger, compatibleTeamWorkers), nil
+		}
+
+		teamWorkersWithCapacity := pool.withCapacity(compatibleTeamWorkers)
+		if len(teamWorkersWithCapacity) != 0 {
+			return pool.constrain(logger, teamWorkersWithCapacity), nil
+		}
+
+		logger.Debug("team-workers-saturated")
 	}
 
 	if len(compatibleGeneralWorkers) != 0 {
-		return compatibleGeneralWorkers, nil
+		return pool.constrain(logger, compatibleGeneralWorkers), nil
 	}
 
 	return nil, NoCompatibleWorkersError{
-		Spec:    spec,
-		Workers: workers,
+		Spec:       spec,
+		Workers:    workers,
+		Mismatches: mismatches,
+	}
+}
+
+func (pool *pool) withCapacity(workers []Worker) []Worker {
+	if pool.maxContainersPerWorker <= 0 {
+		return workers
 	}
+
+	withCapacity := []Worker{}
+	for _, worker := range workers {
+		if worker.ActiveContainers() < pool.maxContainersPerWorker {
+			withCapacity = append(withCapacity, worker)
+		}
+	}
+
+	return withCapacity
+}
+
+// constrainedSpec lets a worker tagged only with the constraint's tags
+// satisfy an untagged spec, so that it is not ruled out by tag matching before
+// the constraint has had a chance to prefer it. A worker with any other tag
+// still only satisfies specs asking for its tags.
+func (pool *pool) constrainedSpec(worker Worker, spec WorkerSpec) WorkerSpec {
+	if pool.constraint == nil || len(spec.Tags) != 0 {
+		return spec
+	}
+
+	constraintTags := pool.constraint.Tags()
+
+	var tags []string
+	for _, tag := range worker.Tags() {
+		if !containsTag(constraintTags, tag) {
+			return spec
+		}
+
+		tags = append(tags, tag)
+	}
+
+	spec.Tags = tags
+
+	return spec
+}
+
+func (pool *pool) constrain(logger lager.Logger, workers []Worker) []Worker {
+	if pool.constraint == nil {
+		return workers
+	}
+
+	constrained := pool.constraint.Constrain(logger.Session("constrain"), workers)
+	if len(constrained) == 0 {
+		return workers
+	}
+
+	return constrained
 }
 
 func (pool *pool) Satisfying(logger lager.Logger, spec WorkerSpec, resourceTypes atc.VersionedResourceTypes) (Worker, error) {
@@ -124,8 +408,130 @@ func (pool *pool) Satisfying(logger lager.Logger, spec WorkerSpec, resourceTypes
 	if err != nil {
 		return nil, err
 	}
-	randomWorker := compatibleWorkers[pool.rand.Intn(len(compatibleWorkers))]
-	return randomWorker, nil
+	return pool.strategy.Choose(compatibleWorkers), nil
+}
+
+// CanEverSatisfy determines whether any registered worker could satisfy the
+// spec, regardless of how many containers the workers are currently running.
+// If none could, a human-readable reason naming the unsatisfiable constraint
+// is returned, e.g. "no worker has platform=darwin".
+//
+// If there are no workers at all, ErrNoWorkers is returned, as they may just
+// not have registered yet.
+func (pool *pool) CanEverSatisfy(logger lager.Logger, spec WorkerSpec, resourceTypes atc.VersionedResourceTypes) (bool, string, error) {
+	workers, err := pool.provider.RunningWorkers(logger)
+	if err != nil {
+		return false, "", err
+	}
+
+	if len(workers) == 0 {
+		return false, "", ErrNoWorkers
+	}
+
+	// each check narrows the previous one, so the first to fail names the
+	// constraint which cannot be met
+	checks := []struct {
+		spec   func(Worker) WorkerSpec
+		reason string
+	}{
+		{
+			spec: func(worker Worker) WorkerSpec {
+				return WorkerSpec{TeamID: spec.TeamID, Tags: worker.Tags()}
+			},
+			reason: "no worker is available to the team",
+		},
+		{
+			spec: func(Worker) WorkerSpec {
+				return WorkerSpec{TeamID: spec.TeamID, Tags: spec.Tags}
+			},
+			reason: unsatisfiableTagsReason(spec.Tags),
+		},
+		{
+			spec: func(Worker) WorkerSpec {
+				return WorkerSpec{TeamID: spec.TeamID, Tags: spec.Tags, Platform: spec.Platform}
+			},
+			reason: "no worker" + withTags(spec.Tags) + " has platform=" + spec.Platform,
+		},
+		{
+			spec: func(Worker) WorkerSpec {
+				return spec
+			},
+			reason: "no worker" + withPlatform(spec.Platform) + withTags(spec.Tags) + " supports resource type=" + spec.ResourceType,
+		},
+	}
+
+	for _, check := range checks {
+		satisfiable := false
+		for _, worker := range workers {
+			_, err := worker.Satisfying(logger, check.spec(worker), resourceTypes)
+			if err == nil {
+				satisfiable = true
+				break
+			}
+		}
+
+		if !satisfiable {
+			return false, check.reason, nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// withResourceTypeTags adds the tags declared by a custom resource type to the
+// spec of a container running that type, so that it's only placed on workers
+// with those tags.
+func withResourceTypeTags(spec ContainerSpec, resourceTypes atc.VersionedResourceTypes, resourceType string) ContainerSpec {
+	customType, found := resourceTypes.Lookup(resourceType)
+	if !found || len(customType.Tags) == 0 {
+		return spec
+	}
+
+	tags := append([]string{}, spec.Tags...)
+
+	for _, tag := range customType.Tags {
+		if !containsTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	spec.Tags = tags
+
+	return spec
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unsatisfiableTagsReason(tags []string) string {
+	if len(tags) == 0 {
+		return "every worker requires tags"
+	}
+
+	return "no worker has tags=" + strings.Join(tags, ",")
+}
+
+func withTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	return " with tags=" + strings.Join(tags, ",")
+}
+
+func withPlatform(platform string) string {
+	if platform == "" {
+		return ""
+	}
+
+	return " with platform=" + platform
 }
 
 func (pool *pool) FindOrCreateBuildContainer(
@@ -168,6 +574,7 @@ func (pool *pool) FindOrCreateBuildContainer(
 	}
 
 	workersByCount := map[int][]Worker{}
+	inputVolumes := map[string]int{}
 	var highestCount int
 	for _, w := range compatibleWorkers {
 		candidateInputCount := 0
@@ -184,21 +591,90 @@ func (pool *pool) FindOrCreateBuildContainer(
 		}
 
 		workersByCount[candidateInputCount] = append(workersByCount[candidateInputCount], w)
+		inputVolumes[w.Name()] = candidateInputCount
 
 		if candidateInputCount >= highestCount {
 			highestCount = candidateInputCount
 		}
 	}
 
-	workers := workersByCount[highestCount]
+	workers := pool.orderByLoad(workersByCount[highestCount])
+
+	preferred := []string{}
+	for _, w := range workers {
+		preferred = append(preferred, w.Name())
+	}
+
+	logger.Debug("choosing-worker", lager.Data{
+		"candidates":    inputVolumes,
+		"input-volumes": highestCount,
+		"preferred":     preferred,
+	})
 
-	return pool.findWorkerNotRunningVTXTask(workers,logger, signals, delegate,
+	return pool.findWorkerNotRunningVTXTask(workers, inputVolumes, logger, signals, delegate,
 		buildID, planID, metadata, spec, resourceTypes)
 }
 
 
+// orderByLoad orders the workers from fewest to most active containers.
+// Workers with the same number of active containers are ordered by the
+// pool's selection strategy.
+func (pool *pool) orderByLoad(workers []Worker) []Worker {
+	sorted := make([]Worker, len(workers))
+	copy(sorted, workers)
+	sort.Stable(byActiveContainers(sorted))
+
+	ordered := make([]Worker, 0, len(sorted))
+	for len(sorted) > 0 {
+		tied := 1
+		for tied < len(sorted) && sorted[tied].ActiveContainers() == sorted[0].ActiveContainers() {
+			tied++
+		}
+
+		ordered = append(ordered, pool.orderByStrategy(sorted[:tied])...)
+		sorted = sorted[tied:]
+	}
+
+	return ordered
+}
+
+// orderByStrategy orders the workers by repeatedly asking the pool's
+// selection strategy to choose among those not yet ordered.
+func (pool *pool) orderByStrategy(workers []Worker) []Worker {
+	remaining := make([]Worker, len(workers))
+	copy(remaining, workers)
+
+	ordered := make([]Worker, 0, len(remaining))
+	for len(remaining) > 0 {
+		chosen := pool.strategy.Choose(remaining)
+		ordered = append(ordered, chosen)
+
+		for i, worker := range remaining {
+			if worker == chosen {
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return ordered
+}
+
+func isWorkerFull(err error) bool {
+	return strings.Contains(err.Error(), "worker already has the maximum number of active containers")
+}
+
+type byActiveContainers []Worker
+
+func (workers byActiveContainers) Len() int      { return len(workers) }
+func (workers byActiveContainers) Swap(i, j int) { workers[i], workers[j] = workers[j], workers[i] }
+func (workers byActiveContainers) Less(i, j int) bool {
+	return workers[i].ActiveContainers() < workers[j].ActiveContainers()
+}
+
 func (pool *pool) findWorkerNotRunningVTXTask(
 	workers []Worker,
+	inputVolumes map[string]int,
 	logger lager.Logger,
 	signals <-chan os.Signal,
 	delegate ImageFetchingDelegate,
@@ -208,7 +684,8 @@ func (pool *pool) findWorkerNotRunningVTXTask(
 	spec ContainerSpec,
 	resourceTypes atc.VersionedResourceTypes) (Container, error) {
 
-	for _, worker := range workers {
+	failures := []WorkerNotCreatedError{}
+	for i, worker := range workers {
 		container, err := worker.FindOrCreateBuildContainer(
 			logger,
 			signals,
@@ -220,7 +697,9 @@ func (pool *pool) findWorkerNotRunningVTXTask(
 			resourceTypes,
 		)
 
-		if err != nil && strings.Contains(err.Error(), "worker already has the maximum number of active containers") {
+		if err != nil && isWorkerFull(err) {
+			logger.Info("worker-full", lager.Data{"worker": worker.Name()})
+			failures = append(failures, WorkerNotCreatedError{Worker: worker.Name(), Err: err})
 			continue
 		}
 
@@ -228,10 +707,24 @@ func (pool *pool) findWorkerNotRunningVTXTask(
 			return nil, err
 		}
 
+		reason := "most-input-volumes"
+		if i > 0 {
+			reason = "preferred-workers-full"
+		} else if len(workers) > 1 {
+			reason = "fewest-active-containers"
+		}
+
+		logger.Info("chose-worker", lager.Data{
+			"worker":            worker.Name(),
+			"input-volumes":     inputVolumes[worker.Name()],
+			"active-containers": worker.ActiveContainers(),
+			"reason":            reason,
+		})
+
 		return container, nil
 	}
 
-	return nil, errors.New("failed to create container on all compatible workers")
+	return nil, AllWorkersFailedError{Failures: failures}
 }
 
 func (pool *pool) CreateResourceGetContainer(
@@ -247,24 +740,44 @@ func (pool *pool) CreateResourceGetContainer(
 	source atc.Source,
 	params atc.Params,
 ) (Container, error) {
-	worker, err := pool.Satisfying(logger, spec.WorkerSpec(), resourceTypes)
+	spec = withResourceTypeTags(spec, resourceTypes, resourceType)
+
+	compatibleWorkers, err := pool.AllSatisfying(logger, spec.WorkerSpec(), resourceTypes)
 	if err != nil {
 		return nil, err
 	}
 
-	return worker.CreateResourceGetContainer(
-		logger,
-		resourceUser,
-		cancel,
-		delegate,
-		metadata,
-		spec,
-		resourceTypes,
-		resourceType,
-		version,
-		source,
-		params,
-	)
+	// gets have no inputs to be near, so any compatible worker will do; try
+	// them in the strategy's order, moving on from any that are full
+	var lastErr error
+	for _, worker := range pool.orderByStrategy(compatibleWorkers) {
+		container, err := worker.CreateResourceGetContainer(
+			logger,
+			resourceUser,
+			cancel,
+			delegate,
+			metadata,
+			spec,
+			resourceTypes,
+			resourceType,
+			version,
+			source,
+			params,
+		)
+		if err != nil && isWorkerFull(err) {
+			logger.Info("worker-full", lager.Data{"worker": worker.Name()})
+			lastErr = err
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return container, nil
+	}
+
+	return nil, lastErr
 }
 
 func (pool *pool) FindOrCreateResourceCheckContainer(
@@ -278,6 +791,8 @@ func (pool *pool) FindOrCreateResourceCheckContainer(
 	resourceType string,
 	source atc.Source,
 ) (Container, error) {
+	spec = withResourceTypeTags(spec, resourceTypes, resourceType)
+
 	worker, found, err := pool.provider.FindWorkerForResourceCheckContainer(
 		logger.Session("find-worker"),
 		spec.TeamID, // XXX: better place for this?
@@ -290,6 +805,12 @@ func (pool *pool) FindOrCreateResourceCheckContainer(
 		return nil, err
 	}
 
+	if found && len(spec.Tags) > 0 {
+		// the type's tags may have been added since the container was created
+		_, err := worker.Satisfying(logger, spec.WorkerSpec(), resourceTypes)
+		found = err == nil
+	}
+
 	if !found {
 		worker, err = pool.Satisfying(logger, spec.WorkerSpec(), resourceTypes)
 		if err != nil {
@@ -327,20 +848,459 @@ func (pool *pool) FindContainerByHandle(logger lager.Logger, teamID int, handle
 	return worker.FindContainerByHandle(logger, teamID, handle)
 }
 
+// RunInContainer runs a process in an existing container belonging to the
+// given team. Containers owned by other teams are reported as not found.
+func (pool *pool) RunInContainer(
+	logger lager.Logger,
+	teamID int,
+	handle string,
+	spec garden.ProcessSpec,
+	io garden.ProcessIO,
+) (garden.Process, error) {
+	logger = logger.Session("run-in-container", lager.Data{"handle": handle})
+
+	worker, found, err := pool.provider.FindWorkerForContainer(
+		logger.Session("find-worker"),
+		teamID,
+		handle,
+	)
+	if err != nil {
+		logger.Error("failed-to-find-worker", err)
+		return nil, err
+	}
+
+	if !found {
+		return nil, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	return worker.RunInContainer(logger, teamID, handle, spec, io)
+}
+
+// StreamContainerOutput streams the live output of the task process running in
+// a container belonging to the given team. Containers owned by other teams are
+// reported as not found.
+func (pool *pool) StreamContainerOutput(logger lager.Logger, teamID int, handle string) (io.ReadCloser, error) {
+	logger = logger.Session("stream-container-output", lager.Data{"handle": handle})
+
+	worker, found, err := pool.provider.FindWorkerForContainer(
+		logger.Session("find-worker"),
+		teamID,
+		handle,
+	)
+	if err != nil {
+		logger.Error("failed-to-find-worker", err)
+		return nil, err
+	}
+
+	if !found {
+		return nil, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	return worker.StreamContainerOutput(logger, teamID, handle)
+}
+
+func (pool *pool) StreamFileFromLatestAttempt(
+	logger lager.Logger,
+	teamID int,
+	buildID int,
+	stepName string,
+	path string,
+) (io.ReadCloser, error) {
+	logger = logger.Session("stream-file-from-latest-attempt", lager.Data{
+		"build":     buildID,
+		"step-name": stepName,
+		"path":      path,
+	})
+
+	handle, found, err := pool.provider.FindLatestAttemptContainerHandle(logger, teamID, buildID, stepName)
+	if err != nil {
+		logger.Error("failed-to-find-latest-attempt-container", err)
+		return nil, err
+	}
+
+	if !found {
+		return nil, NoAttemptContainerError{BuildID: buildID, StepName: stepName}
+	}
+
+	container, found, err := pool.FindContainerByHandle(logger, teamID, handle)
+	if err != nil {
+		logger.Error("failed-to-find-container", err, lager.Data{"handle": handle})
+		return nil, err
+	}
+
+	if !found {
+		return nil, NoAttemptContainerError{BuildID: buildID, StepName: stepName}
+	}
+
+	out, err := container.StreamOut(garden.StreamOutSpec{Path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	tarReader := tar.NewReader(out)
+
+	_, err = tarReader.Next()
+	if err != nil {
+		out.Close()
+		return nil, FileNotFoundError{Path: path}
+	}
+
+	return fileReadCloser{
+		Reader: tarReader,
+		Closer: out,
+	}, nil
+}
+
+type fileReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// EvacuateWorker moves the task containers on the given worker that have
+// volumes mounted into them to other compatible workers. Each volume, including
+// the image, is streamed into a freshly created container for the same build
+// step, and the original container is discontinued so that re-attaching to the
+// step finds the new one. Processes running in the original container are not
+// moved.
+func (pool *pool) EvacuateWorker(logger lager.Logger, workerName string) error {
+	logger = logger.Session("evacuate-worker", lager.Data{"worker": workerName})
+
+	containers, err := pool.provider.FindEvacuatableContainers(logger, workerName)
+	if err != nil {
+		logger.Error("failed-to-find-evacuatable-containers", err)
+		return err
+	}
+
+	for _, container := range containers {
+		err := pool.evacuateContainer(logger, workerName, container)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pool *pool) evacuateContainer(
+	logger lager.Logger,
+	workerName string,
+	evacuatable dbng.EvacuatableContainer,
+) error {
+	handle := evacuatable.Container.Handle()
+
+	logger = logger.Session("evacuate-container", lager.Data{"container": handle})
+
+	sourceWorker, found, err := pool.provider.FindWorkerForContainer(
+		logger.Session("find-worker"),
+		evacuatable.TeamID,
+		handle,
+	)
+	if err != nil {
+		logger.Error("failed-to-find-worker", err)
+		return err
+	}
+
+	if !found {
+		return ErrMissingWorker
+	}
+
+	sourceContainer, found, err := sourceWorker.FindContainerByHandle(logger, evacuatable.TeamID, handle)
+	if err != nil {
+		logger.Error("failed-to-find-container", err)
+		return err
+	}
+
+	if !found {
+		logger.Info("container-disappeared")
+		return nil
+	}
+
+	properties, err := sourceContainer.Properties()
+	if err != nil {
+		logger.Error("failed-to-get-container-properties", err)
+		return err
+	}
+
+	spec := ContainerSpec{
+		Platform: sourceWorker.Platform(),
+		Tags:     sourceWorker.Tags(),
+		TeamID:   evacuatable.TeamID,
+		User:     properties[userPropertyName],
+		Dir:      properties[dirPropertyName],
+	}
+
+	spec.ImageSpec.Privileged = properties[privilegedPropertyName] == "true"
+
+	if envJSON, found := properties[envPropertyName]; found {
+		err = json.Unmarshal([]byte(envJSON), &spec.Env)
+		if err != nil {
+			logger.Error("failed-to-unmarshal-container-env", err)
+			return err
+		}
+	}
+
+	for _, mount := range sourceContainer.VolumeMounts() {
+		source := &evacuatedVolumeSource{
+			logger: logger,
+			volume: mount.Volume,
+		}
+
+		if mount.MountPath == "/" {
+			spec.ImageSpec.ImageArtifactSource = source
+			continue
+		}
+
+		spec.Inputs = append(spec.Inputs, &evacuatedVolumeInputSource{
+			source:          source,
+			destinationPath: mount.MountPath,
+		})
+	}
+
+	if spec.ImageSpec.ImageArtifactSource == nil {
+		logger.Info("skipping-container-without-image-volume")
+		return nil
+	}
+
+	compatibleWorkers, err := pool.AllSatisfying(logger, spec.WorkerSpec(), atc.VersionedResourceTypes{})
+	if err != nil {
+		logger.Error("failed-to-find-compatible-workers", err)
+		return err
+	}
+
+	destinationWorkers := []Worker{}
+	for _, w := range compatibleWorkers {
+		if w.Name() != workerName {
+			destinationWorkers = append(destinationWorkers, w)
+		}
+	}
+
+	if len(destinationWorkers) == 0 {
+		return NoCompatibleWorkersError{
+			Spec:    spec.WorkerSpec(),
+			Workers: compatibleWorkers,
+		}
+	}
+
+	destinationWorker := pool.strategy.Choose(destinationWorkers)
+
+	_, err = destinationWorker.FindOrCreateBuildContainer(
+		logger,
+		nil,
+		NoopImageFetchingDelegate{},
+		evacuatable.BuildID,
+		evacuatable.PlanID,
+		evacuatable.Container.Metadata(),
+		spec,
+		atc.VersionedResourceTypes{},
+	)
+	if err != nil {
+		logger.Error("failed-to-create-container-on-destination", err, lager.Data{"destination": destinationWorker.Name()})
+		return err
+	}
+
+	_, err = evacuatable.Container.Discontinue()
+	if err != nil {
+		logger.Error("failed-to-discontinue-container", err)
+		return err
+	}
+
+	logger.Info("evacuated", lager.Data{"destination": destinationWorker.Name()})
+
+	return nil
+}
+
+type evacuatedVolumeSource struct {
+	logger lager.Logger
+	volume Volume
+}
+
+func (src *evacuatedVolumeSource) StreamTo(destination ArtifactDestination) error {
+	out, err := src.volume.StreamOut(".")
+	if err != nil {
+		return err
+	}
+
+	defer out.Close()
+
+	return destination.StreamIn(".", out)
+}
+
+func (src *evacuatedVolumeSource) StreamFile(path string) (io.ReadCloser, error) {
+	out, err := src.volume.StreamOut(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tarReader := tar.NewReader(out)
+
+	_, err = tarReader.Next()
+	if err != nil {
+		out.Close()
+		return nil, FileNotFoundError{Path: path}
+	}
+
+	return fileReadCloser{
+		Reader: tarReader,
+		Closer: out,
+	}, nil
+}
+
+func (src *evacuatedVolumeSource) VolumeOn(w Worker) (Volume, bool, error) {
+	return w.LookupVolume(src.logger, src.volume.Handle())
+}
+
+type evacuatedVolumeInputSource struct {
+	source          ArtifactSource
+	destinationPath string
+}
+
+func (s *evacuatedVolumeInputSource) Name() ArtifactName      { return ArtifactName(s.destinationPath) }
+func (s *evacuatedVolumeInputSource) Source() ArtifactSource  { return s.source }
+func (s *evacuatedVolumeInputSource) DestinationPath() string { return s.destinationPath }
+
+// WarmInputs streams each of the sources into a cache volume on the named
+// worker, so that a build later placed on that worker finds them with
+// VolumeOn rather than streaming them in itself. Sources already present on
+// the worker are skipped, as are sources that cannot be cached.
+func (pool *pool) WarmInputs(logger lager.Logger, workerName string, sources []ArtifactSource) error {
+	logger = logger.Session("warm-inputs", lager.Data{"worker": workerName})
+
+	workers, err := pool.provider.RunningWorkers(logger)
+	if err != nil {
+		return err
+	}
+
+	var target Worker
+	for _, w := range workers {
+		if w.Name() == workerName {
+			target = w
+			break
+		}
+	}
+
+	if target == nil {
+		return ErrMissingWorker
+	}
+
+	for i, source := range sources {
+		sourceLogger := logger.Session("source", lager.Data{"index": i})
+
+		_, found, err := source.VolumeOn(target)
+		if err != nil {
+			sourceLogger.Error("failed-to-look-up-volume", err)
+			return err
+		}
+
+		if found {
+			sourceLogger.Debug("already-present")
+			continue
+		}
+
+		cacheable, ok := source.(CacheableArtifactSource)
+		if !ok {
+			sourceLogger.Info("not-cacheable")
+			continue
+		}
+
+		volume, err := cacheable.CreateCacheOn(sourceLogger, target)
+		if err != nil {
+			sourceLogger.Error("failed-to-create-cache-volume", err)
+			return err
+		}
+
+		destination := NewRetryingDestination(
+			volume,
+			&retryhttp.DefaultRetryer{},
+			pool.inputStreamPolicy,
+			clock.NewClock(),
+			nil, // warming is not tied to a build, so nothing can interrupt it
+		)
+
+		err = destination.StreamFrom(source)
+		if err != nil {
+			sourceLogger.Error("failed-to-stream-in", err)
+			return err
+		}
+
+		err = volume.Initialize()
+		if err != nil {
+			sourceLogger.Error("failed-to-initialize-cache-volume", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (*pool) FindResourceTypeByPath(string) (atc.WorkerResourceType, bool) {
 	return atc.WorkerResourceType{}, false
 }
 
-func (*pool) CreateVolumeForResourceCache(lager.Logger, VolumeSpec, *dbng.UsedResourceCache) (Volume, error) {
-	return nil, errors.New("CreateVolumeForResourceCache not implemented for pool")
+// CreateVolumeForResourceCache creates the volume on a running worker
+// satisfying the spec, chosen by the pool's selection strategy.
+func (pool *pool) CreateVolumeForResourceCache(logger lager.Logger, spec WorkerSpec, resourceTypes atc.VersionedResourceTypes, volumeSpec VolumeSpec, resourceCache *dbng.UsedResourceCache) (Volume, error) {
+	chosen, err := pool.Satisfying(logger, spec, resourceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return chosen.CreateVolumeForResourceCache(logger, spec, resourceTypes, volumeSpec, resourceCache)
 }
 
-func (*pool) FindInitializedVolumeForResourceCache(logger lager.Logger, resourceCache *dbng.UsedResourceCache) (Volume, bool, error) {
-	return nil, false, errors.New("FindInitializedVolumeForResourceCache not implemented for pool")
+// FindInitializedVolumeForResourceCache returns the initialized cache volume
+// from the first running worker that has one. As with LookupVolume, a worker
+// failing the lookup does not stop the search.
+func (pool *pool) FindInitializedVolumeForResourceCache(logger lager.Logger, resourceCache *dbng.UsedResourceCache) (Volume, bool, error) {
+	workers, err := pool.provider.RunningWorkers(logger)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(workers) == 0 {
+		return nil, false, ErrNoWorkers
+	}
+
+	var errs error
+	for _, w := range workers {
+		volume, found, err := w.FindInitializedVolumeForResourceCache(logger, resourceCache)
+		if err != nil {
+			logger.Error("failed-to-find-cache-volume-on-worker", err, lager.Data{"worker": w.Name()})
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if found {
+			return volume, true, nil
+		}
+	}
+
+	return nil, false, errs
 }
 
-func (*pool) LookupVolume(lager.Logger, string) (Volume, bool, error) {
-	return nil, false, errors.New("LookupVolume not implemented for pool")
+// LookupVolume looks for the volume on each running worker, returning it from
+// the first worker that has it. A worker failing to look up the volume does
+// not stop the search; its error is only returned if no worker has the volume.
+func (pool *pool) LookupVolume(logger lager.Logger, handle string) (Volume, bool, error) {
+	workers, err := pool.provider.RunningWorkers(logger)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var errs error
+	for _, w := range workers {
+		volume, found, err := w.LookupVolume(logger, handle)
+		if err != nil {
+			logger.Error("failed-to-lookup-volume-on-worker", err, lager.Data{"worker": w.Name()})
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if found {
+			return volume, true, nil
+		}
+	}
+
+	return nil, false, errs
 }
 
 func resourcesDir(suffix string) string {
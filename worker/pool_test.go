@@ -1,12 +1,23 @@
 package worker_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
 	"os"
+	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
+	"github.com/concourse/atc/dbng/dbngfakes"
 	. "github.com/concourse/atc/worker"
 	"github.com/concourse/atc/worker/workerfakes"
 
@@ -26,7 +37,333 @@ var _ = Describe("Pool", func() {
 		logger = lagertest.NewTestLogger("test")
 		fakeProvider = new(workerfakes.FakeWorkerProvider)
 
-		pool = NewPool(fakeProvider)
+		pool = NewPool(fakeProvider, NewRandomStrategy(rand.NewSource(GinkgoRandomSeed())), nil, 10, TeamWorkerAffinityStrict, StreamInRetryPolicy{BufferLimit: 1024, Attempts: 1})
+	})
+
+	Describe("ClusterCapacity", func() {
+		Context("when the provider returns workers", func() {
+			BeforeEach(func() {
+				linuxWorker1 := new(workerfakes.FakeWorker)
+				linuxWorker1.NameReturns("linux-1")
+				linuxWorker1.PlatformReturns("linux")
+				linuxWorker1.ActiveContainersReturns(3)
+
+				linuxWorker2 := new(workerfakes.FakeWorker)
+				linuxWorker2.NameReturns("linux-2")
+				linuxWorker2.PlatformReturns("linux")
+				linuxWorker2.ActiveContainersReturns(12)
+
+				windowsWorker := new(workerfakes.FakeWorker)
+				windowsWorker.NameReturns("windows")
+				windowsWorker.PlatformReturns("windows")
+				windowsWorker.ActiveContainersReturns(4)
+
+				fakeProvider.RunningWorkersReturns([]Worker{linuxWorker1, linuxWorker2, windowsWorker}, nil)
+			})
+
+			It("sums the capacity of all running workers", func() {
+				capacity, err := pool.ClusterCapacity(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(capacity.Workers).To(Equal(3))
+				Expect(capacity.MaxContainers).To(Equal(30))
+				Expect(capacity.ActiveContainers).To(Equal(19))
+				Expect(capacity.FreeContainers).To(Equal(13))
+
+				Expect(capacity.Partial).To(BeFalse())
+				Expect(capacity.UnavailableWorkers).To(BeEmpty())
+			})
+
+			It("breaks the capacity down by platform, not counting overcommitted workers as negative free capacity", func() {
+				capacity, err := pool.ClusterCapacity(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(capacity.Platforms).To(Equal(map[string]PlatformCapacity{
+					"linux": {
+						Workers:          2,
+						MaxContainers:    20,
+						ActiveContainers: 15,
+						FreeContainers:   7,
+					},
+					"windows": {
+						Workers:          1,
+						MaxContainers:    10,
+						ActiveContainers: 4,
+						FreeContainers:   6,
+					},
+				}))
+			})
+
+			It("breaks the capacity down by worker", func() {
+				capacity, err := pool.ClusterCapacity(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(capacity.PerWorker).To(Equal(map[string]WorkerCapacity{
+					"linux-1": {
+						Platform:         "linux",
+						MaxContainers:    10,
+						ActiveContainers: 3,
+						FreeContainers:   7,
+					},
+					"linux-2": {
+						Platform:         "linux",
+						MaxContainers:    10,
+						ActiveContainers: 12,
+						FreeContainers:   0,
+					},
+					"windows": {
+						Platform:         "windows",
+						MaxContainers:    10,
+						ActiveContainers: 4,
+						FreeContainers:   6,
+					},
+				}))
+			})
+		})
+
+		Context("when a worker reports an invalid container count", func() {
+			BeforeEach(func() {
+				healthyWorker := new(workerfakes.FakeWorker)
+				healthyWorker.NameReturns("healthy")
+				healthyWorker.PlatformReturns("linux")
+				healthyWorker.ActiveContainersReturns(3)
+
+				brokenWorker := new(workerfakes.FakeWorker)
+				brokenWorker.NameReturns("broken")
+				brokenWorker.PlatformReturns("linux")
+				brokenWorker.ActiveContainersReturns(-1)
+
+				fakeProvider.RunningWorkersReturns([]Worker{healthyWorker, brokenWorker}, nil)
+			})
+
+			It("leaves the worker out of the totals and reports the capacity as partial", func() {
+				capacity, err := pool.ClusterCapacity(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(capacity.Workers).To(Equal(1))
+				Expect(capacity.MaxContainers).To(Equal(10))
+				Expect(capacity.ActiveContainers).To(Equal(3))
+				Expect(capacity.FreeContainers).To(Equal(7))
+				Expect(capacity.PerWorker).NotTo(HaveKey("broken"))
+
+				Expect(capacity.Partial).To(BeTrue())
+				Expect(capacity.UnavailableWorkers).To(Equal([]string{"broken"}))
+			})
+		})
+
+		Context("when there are no running workers", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+			})
+
+			It("returns empty capacity", func() {
+				capacity, err := pool.ClusterCapacity(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capacity.Workers).To(BeZero())
+				Expect(capacity.MaxContainers).To(BeZero())
+				Expect(capacity.Platforms).To(BeEmpty())
+				Expect(capacity.PerWorker).To(BeEmpty())
+			})
+		})
+
+		Context("when the provider fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				_, err := pool.ClusterCapacity(logger)
+				Expect(err).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("CanEverSatisfy", func() {
+		var (
+			spec WorkerSpec
+
+			satisfiable bool
+			reason      string
+			canErr      error
+		)
+
+		newWorker := func(teamID int, platform string, tags []string, resourceTypes []string, activeContainers int) *workerfakes.FakeWorker {
+			worker := new(workerfakes.FakeWorker)
+			worker.TagsReturns(tags)
+			worker.ActiveContainersReturns(activeContainers)
+			worker.SatisfyingStub = func(_ lager.Logger, spec WorkerSpec, _ atc.VersionedResourceTypes) (Worker, error) {
+				if teamID != 0 && spec.TeamID != teamID {
+					return nil, ErrTeamMismatch
+				}
+
+				if spec.ResourceType != "" {
+					supported := false
+					for _, t := range resourceTypes {
+						supported = supported || t == spec.ResourceType
+					}
+
+					if !supported {
+						return nil, ErrUnsupportedResourceType
+					}
+				}
+
+				if spec.Platform != "" && spec.Platform != platform {
+					return nil, ErrIncompatiblePlatform
+				}
+
+				if len(tags) > 0 && len(spec.Tags) == 0 {
+					return nil, ErrMismatchedTags
+				}
+
+				for _, stag := range spec.Tags {
+					found := false
+					for _, wtag := range tags {
+						found = found || stag == wtag
+					}
+
+					if !found {
+						return nil, ErrMismatchedTags
+					}
+				}
+
+				return worker, nil
+			}
+
+			return worker
+		}
+
+		BeforeEach(func() {
+			spec = WorkerSpec{
+				TeamID:       1,
+				Platform:     "linux",
+				ResourceType: "git",
+			}
+
+			fakeProvider.RunningWorkersReturns([]Worker{
+				newWorker(0, "linux", nil, []string{"git", "s3"}, 10),
+				newWorker(0, "windows", nil, []string{"s3"}, 0),
+				newWorker(0, "linux", []string{"gpu"}, []string{"git"}, 0),
+				newWorker(2, "darwin", nil, []string{"git"}, 0),
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			satisfiable, reason, canErr = pool.CanEverSatisfy(logger, spec, atc.VersionedResourceTypes{})
+		})
+
+		Context("when a worker satisfies the spec but is full", func() {
+			It("returns true", func() {
+				Expect(canErr).NotTo(HaveOccurred())
+				Expect(satisfiable).To(BeTrue())
+				Expect(reason).To(BeEmpty())
+			})
+		})
+
+		Context("when no worker has the platform", func() {
+			BeforeEach(func() {
+				spec.Platform = "darwin"
+			})
+
+			It("returns false with the platform as the reason", func() {
+				Expect(canErr).NotTo(HaveOccurred())
+				Expect(satisfiable).To(BeFalse())
+				Expect(reason).To(Equal("no worker has platform=darwin"))
+			})
+
+			Context("when the team has a worker with the platform", func() {
+				BeforeEach(func() {
+					spec.TeamID = 2
+				})
+
+				It("returns true", func() {
+					Expect(satisfiable).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when no worker has the tags", func() {
+			BeforeEach(func() {
+				spec.Tags = []string{"gpu", "fpga"}
+			})
+
+			It("returns false with the tags as the reason", func() {
+				Expect(satisfiable).To(BeFalse())
+				Expect(reason).To(Equal("no worker has tags=gpu,fpga"))
+			})
+		})
+
+		Context("when workers have the tags, but not the platform", func() {
+			BeforeEach(func() {
+				spec.Tags = []string{"gpu"}
+				spec.Platform = "windows"
+			})
+
+			It("returns false with the platform as the reason", func() {
+				Expect(satisfiable).To(BeFalse())
+				Expect(reason).To(Equal("no worker with tags=gpu has platform=windows"))
+			})
+		})
+
+		Context("when no worker with the platform supports the resource type", func() {
+			BeforeEach(func() {
+				spec.Platform = "windows"
+			})
+
+			It("returns false with the resource type as the reason", func() {
+				Expect(satisfiable).To(BeFalse())
+				Expect(reason).To(Equal("no worker with platform=windows supports resource type=git"))
+			})
+		})
+
+		Context("when every worker requires tags", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{
+					newWorker(0, "linux", []string{"gpu"}, []string{"git"}, 0),
+				}, nil)
+			})
+
+			It("returns false with the tags as the reason", func() {
+				Expect(satisfiable).To(BeFalse())
+				Expect(reason).To(Equal("every worker requires tags"))
+			})
+		})
+
+		Context("when every worker belongs to another team", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{
+					newWorker(2, "linux", nil, []string{"git"}, 0),
+				}, nil)
+			})
+
+			It("returns false with the team as the reason", func() {
+				Expect(satisfiable).To(BeFalse())
+				Expect(reason).To(Equal("no worker is available to the team"))
+			})
+		})
+
+		Context("when there are no workers", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+			})
+
+			It("returns ErrNoWorkers", func() {
+				Expect(canErr).To(Equal(ErrNoWorkers))
+			})
+		})
+
+		Context("when listing the workers fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(canErr).To(Equal(disaster))
+			})
+		})
 	})
 
 	Describe("Satisfying", func() {
@@ -118,9 +455,12 @@ var _ = Describe("Pool", func() {
 				})
 
 				It("returns a NoCompatibleWorkersError", func() {
+					nope := WorkerMismatch{Reason: errors.New("nope"), Detail: "nope"}
+
 					Expect(satisfyingErr).To(Equal(NoCompatibleWorkersError{
-						Spec:    spec,
-						Workers: []Worker{workerA, workerB, workerC},
+						Spec:       spec,
+						Workers:    []Worker{workerA, workerB, workerC},
+						Mismatches: []WorkerMismatch{nope, nope, nope},
 					}))
 				})
 			})
@@ -225,6 +565,131 @@ var _ = Describe("Pool", func() {
 				Expect(satisfyingWorkers).To(ConsistOf(workerA, workerB))
 			})
 
+			Context("when the pool has a selection constraint", func() {
+				var fakeConstraint *workerfakes.FakeSelectionConstraint
+
+				BeforeEach(func() {
+					fakeConstraint = new(workerfakes.FakeSelectionConstraint)
+					fakeConstraint.ConstrainReturns([]Worker{workerB})
+
+					pool = NewPool(fakeProvider, NewRandomStrategy(rand.NewSource(GinkgoRandomSeed())), fakeConstraint, 10, TeamWorkerAffinityStrict, StreamInRetryPolicy{BufferLimit: 1024, Attempts: 1})
+				})
+
+				It("constrains the satisfying workers", func() {
+					Expect(fakeConstraint.ConstrainCallCount()).To(Equal(1))
+					_, constrainedWorkers := fakeConstraint.ConstrainArgsForCall(0)
+					Expect(constrainedWorkers).To(ConsistOf(workerA, workerB))
+
+					Expect(satisfyingWorkers).To(Equal([]Worker{workerB}))
+				})
+
+				Context("when the constraint returns no workers", func() {
+					BeforeEach(func() {
+						fakeConstraint.ConstrainReturns([]Worker{})
+					})
+
+					It("returns all of the satisfying workers", func() {
+						Expect(satisfyingWorkers).To(ConsistOf(workerA, workerB))
+					})
+				})
+			})
+
+			Context("when the pool has a time window constraint and the spec has no tags", func() {
+				var (
+					fakeClock  *fakeclock.FakeClock
+					spotWorker *workerfakes.FakeWorker
+				)
+
+				BeforeEach(func() {
+					spec.Tags = nil
+
+					spotWorker = new(workerfakes.FakeWorker)
+					spotWorker.TagsReturns(atc.Tags{"spot"})
+					spotWorker.SatisfyingStub = func(_ lager.Logger, spec WorkerSpec, _ atc.VersionedResourceTypes) (Worker, error) {
+						// like a real worker, a tagged worker rejects specs without its tags
+						if len(spec.Tags) == 0 {
+							return nil, ErrMismatchedTags
+						}
+
+						return spotWorker, nil
+					}
+
+					fakeProvider.RunningWorkersReturns([]Worker{workerA, spotWorker}, nil)
+
+					fakeClock = fakeclock.NewFakeClock(time.Date(2017, time.March, 1, 23, 0, 0, 0, time.UTC))
+
+					constraint := NewTimeWindowConstraint(fakeClock, "spot", []TimeWindow{
+						{Start: 22 * time.Hour, End: 6 * time.Hour},
+					})
+
+					pool = NewPool(fakeProvider, NewRandomStrategy(rand.NewSource(GinkgoRandomSeed())), constraint, 10, TeamWorkerAffinityStrict, StreamInRetryPolicy{BufferLimit: 1024, Attempts: 1})
+				})
+
+				It("prefers the tagged workers within the window", func() {
+					Expect(satisfyingErr).NotTo(HaveOccurred())
+					Expect(satisfyingWorkers).To(Equal([]Worker{spotWorker}))
+				})
+
+				It("checks only the tagged workers against the window's tag", func() {
+					_, actualSpec, _ := spotWorker.SatisfyingArgsForCall(0)
+					Expect(actualSpec.Tags).To(Equal([]string{"spot"}))
+
+					_, actualSpec, _ = workerA.SatisfyingArgsForCall(0)
+					Expect(actualSpec).To(Equal(spec))
+				})
+
+				Context("outside of the window", func() {
+					BeforeEach(func() {
+						fakeClock.Increment(12 * time.Hour)
+					})
+
+					It("avoids the tagged workers", func() {
+						Expect(satisfyingErr).NotTo(HaveOccurred())
+						Expect(satisfyingWorkers).To(Equal([]Worker{workerA}))
+					})
+				})
+
+				Context("when the tagged worker also has another tag", func() {
+					BeforeEach(func() {
+						spotWorker.TagsReturns(atc.Tags{"spot", "gpu"})
+						spotWorker.SatisfyingStub = func(_ lager.Logger, spec WorkerSpec, _ atc.VersionedResourceTypes) (Worker, error) {
+							// like a real worker, every tag in the spec must be one of the worker's
+							if len(spec.Tags) == 0 {
+								return nil, ErrMismatchedTags
+							}
+
+							for _, tag := range spec.Tags {
+								if tag != "spot" && tag != "gpu" {
+									return nil, ErrMismatchedTags
+								}
+							}
+
+							return spotWorker, nil
+						}
+					})
+
+					It("does not give it untagged work within the window", func() {
+						Expect(satisfyingErr).NotTo(HaveOccurred())
+						Expect(satisfyingWorkers).To(Equal([]Worker{workerA}))
+
+						_, actualSpec, _ := spotWorker.SatisfyingArgsForCall(0)
+						Expect(actualSpec).To(Equal(spec))
+					})
+
+					Context("when the spec asks for the other tag", func() {
+						BeforeEach(func() {
+							spec.Tags = []string{"gpu"}
+							workerA.SatisfyingReturns(nil, ErrMismatchedTags)
+						})
+
+						It("still gives it the work", func() {
+							Expect(satisfyingErr).NotTo(HaveOccurred())
+							Expect(satisfyingWorkers).To(Equal([]Worker{spotWorker}))
+						})
+					})
+				})
+			})
+
 			Context("when no workers satisfy the spec", func() {
 				BeforeEach(func() {
 					workerA.SatisfyingReturns(nil, errors.New("nope"))
@@ -233,11 +698,60 @@ var _ = Describe("Pool", func() {
 				})
 
 				It("returns a NoCompatibleWorkersError", func() {
+					nope := WorkerMismatch{Reason: errors.New("nope"), Detail: "nope"}
+
 					Expect(satisfyingErr).To(Equal(NoCompatibleWorkersError{
-						Spec:    spec,
-						Workers: []Worker{workerA, workerB, workerC},
+						Spec:       spec,
+						Workers:    []Worker{workerA, workerB, workerC},
+						Mismatches: []WorkerMismatch{nope, nope, nope},
+					}))
+				})
+			})
+
+			Context("when the workers are rejected for different reasons", func() {
+				BeforeEach(func() {
+					spec.ResourceType = "some-resource-type"
+					spec.Platform = "windows"
+					spec.Tags = []string{"gpu"}
+
+					workerA.NameReturns("worker-a")
+					workerA.PlatformReturns("linux")
+					workerA.SatisfyingReturns(nil, ErrIncompatiblePlatform)
+
+					workerB.NameReturns("worker-b")
+					workerB.TagsReturns([]string{"ssd"})
+					workerB.SatisfyingReturns(nil, ErrMismatchedTags)
+
+					workerC.NameReturns("worker-c")
+					workerC.SatisfyingReturns(nil, ErrUnsupportedResourceType)
+				})
+
+				It("records why each worker was rejected", func() {
+					Expect(satisfyingErr).To(BeAssignableToTypeOf(NoCompatibleWorkersError{}))
+
+					mismatches := satisfyingErr.(NoCompatibleWorkersError).Mismatches
+					Expect(mismatches).To(Equal([]WorkerMismatch{
+						{
+							Worker: "worker-a",
+							Reason: ErrIncompatiblePlatform,
+							Detail: "platform linux != windows",
+						},
+						{
+							Worker: "worker-b",
+							Reason: ErrMismatchedTags,
+							Detail: "tags [ssd] do not match [gpu]",
+						},
+						{
+							Worker: "worker-c",
+							Reason: ErrUnsupportedResourceType,
+							Detail: "resource type 'some-resource-type' is not supported",
+						},
 					}))
 				})
+
+				It("lists the rejections in the error message", func() {
+					Expect(satisfyingErr.Error()).To(ContainSubstring("rejected workers:\n  - worker worker-a rejected: platform linux != windows"))
+				})
 			})
 		})
 
@@ -268,6 +782,48 @@ var _ = Describe("Pool", func() {
 				Expect(satisfyingErr).NotTo(HaveOccurred())
 				Expect(satisfyingWorkers).To(ConsistOf(teamWorker1, teamWorker2))
 			})
+
+			Context("when the team workers are saturated", func() {
+				BeforeEach(func() {
+					teamWorker1.ActiveContainersReturns(10)
+					teamWorker2.ActiveContainersReturns(12)
+				})
+
+				It("still returns only the team workers", func() {
+					Expect(satisfyingErr).NotTo(HaveOccurred())
+					Expect(satisfyingWorkers).To(ConsistOf(teamWorker1, teamWorker2))
+				})
+			})
+
+			Context("when the pool only prefers team workers", func() {
+				BeforeEach(func() {
+					pool = NewPool(fakeProvider, NewRandomStrategy(rand.NewSource(GinkgoRandomSeed())), nil, 10, TeamWorkerAffinityPreferred, StreamInRetryPolicy{BufferLimit: 1024, Attempts: 1})
+				})
+
+				Context("when some of the team workers have capacity left", func() {
+					BeforeEach(func() {
+						teamWorker1.ActiveContainersReturns(10)
+						teamWorker2.ActiveContainersReturns(9)
+					})
+
+					It("returns the team workers with capacity left", func() {
+						Expect(satisfyingErr).NotTo(HaveOccurred())
+						Expect(satisfyingWorkers).To(ConsistOf(teamWorker2))
+					})
+				})
+
+				Context("when all of the team workers are saturated", func() {
+					BeforeEach(func() {
+						teamWorker1.ActiveContainersReturns(10)
+						teamWorker2.ActiveContainersReturns(12)
+					})
+
+					It("falls back to the general workers", func() {
+						Expect(satisfyingErr).NotTo(HaveOccurred())
+						Expect(satisfyingWorkers).To(ConsistOf(generalWorker))
+					})
+				})
+			})
 		})
 
 		Context("when only general workers satisfy the spec", func() {
@@ -371,44 +927,575 @@ var _ = Describe("Pool", func() {
 		})
 	})
 
-	Describe("FindOrCreateBuildContainer", func() {
+	Describe("StreamFileFromLatestAttempt", func() {
 		var (
-			signals                   <-chan os.Signal
-			fakeImageFetchingDelegate *workerfakes.FakeImageFetchingDelegate
-			metadata                  dbng.ContainerMetadata
-			spec                      ContainerSpec
-			resourceTypes             atc.VersionedResourceTypes
+			stream    io.ReadCloser
+			streamErr error
+		)
 
-			fakeContainer *workerfakes.FakeContainer
+		JustBeforeEach(func() {
+			stream, streamErr = pool.StreamFileFromLatestAttempt(
+				logger,
+				4567,
+				42,
+				"some-task",
+				"some/file",
+			)
+		})
 
-			createdContainer Container
-			createErr        error
+		Context("when the latest attempt has a container", func() {
+			var fakeWorker *workerfakes.FakeWorker
+			var fakeContainer *workerfakes.FakeContainer
 
-			incompatibleWorker        *workerfakes.FakeWorker
-			compatibleWorkerOneCache1 *workerfakes.FakeWorker
-			compatibleWorkerOneCache2 *workerfakes.FakeWorker
-			compatibleWorkerTwoCaches *workerfakes.FakeWorker
-			compatibleWorkerNoCaches1 *workerfakes.FakeWorker
-			compatibleWorkerNoCaches2 *workerfakes.FakeWorker
-		)
+			BeforeEach(func() {
+				fakeProvider.FindLatestAttemptContainerHandleReturns("some-handle", true, nil)
 
-		BeforeEach(func() {
-			fakeImageFetchingDelegate = new(workerfakes.FakeImageFetchingDelegate)
+				fakeWorker = new(workerfakes.FakeWorker)
+				fakeProvider.FindWorkerForContainerReturns(fakeWorker, true, nil)
 
-			fakeInput1 := new(workerfakes.FakeInputSource)
-			fakeInput1AS := new(workerfakes.FakeArtifactSource)
-			fakeInput1AS.VolumeOnStub = func(worker Worker) (Volume, bool, error) {
-				switch worker {
-				case compatibleWorkerOneCache1, compatibleWorkerOneCache2, compatibleWorkerTwoCaches:
-					return new(workerfakes.FakeVolume), true, nil
-				default:
-					return nil, false, nil
-				}
-			}
-			fakeInput1.SourceReturns(fakeInput1AS)
+				fakeContainer = new(workerfakes.FakeContainer)
+				fakeWorker.FindContainerByHandleReturns(fakeContainer, true, nil)
+			})
 
-			fakeInput2 := new(workerfakes.FakeInputSource)
-			fakeInput2AS := new(workerfakes.FakeArtifactSource)
+			It("looks up the latest attempt for the build's step", func() {
+				Expect(fakeProvider.FindLatestAttemptContainerHandleCallCount()).To(Equal(1))
+
+				_, actualTeamID, actualBuildID, actualStepName := fakeProvider.FindLatestAttemptContainerHandleArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualBuildID).To(Equal(42))
+				Expect(actualStepName).To(Equal("some-task"))
+			})
+
+			It("finds the container by the attempt's handle", func() {
+				_, actualTeamID, actualHandle := fakeProvider.FindWorkerForContainerArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualHandle).To(Equal("some-handle"))
+			})
+
+			Context("when the file exists", func() {
+				BeforeEach(func() {
+					buffer := new(bytes.Buffer)
+					tarWriter := tar.NewWriter(buffer)
+
+					contents := []byte("some-contents")
+					err := tarWriter.WriteHeader(&tar.Header{
+						Name: "file",
+						Mode: 0644,
+						Size: int64(len(contents)),
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = tarWriter.Write(contents)
+					Expect(err).NotTo(HaveOccurred())
+
+					err = tarWriter.Close()
+					Expect(err).NotTo(HaveOccurred())
+
+					fakeContainer.StreamOutReturns(ioutil.NopCloser(buffer), nil)
+				})
+
+				It("streams the file out of the container", func() {
+					Expect(streamErr).NotTo(HaveOccurred())
+
+					Expect(fakeContainer.StreamOutCallCount()).To(Equal(1))
+					Expect(fakeContainer.StreamOutArgsForCall(0)).To(Equal(garden.StreamOutSpec{Path: "some/file"}))
+
+					contents, err := ioutil.ReadAll(stream)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(contents)).To(Equal("some-contents"))
+				})
+			})
+
+			Context("when the file does not exist", func() {
+				BeforeEach(func() {
+					fakeContainer.StreamOutReturns(ioutil.NopCloser(new(bytes.Buffer)), nil)
+				})
+
+				It("returns a FileNotFoundError", func() {
+					Expect(streamErr).To(Equal(FileNotFoundError{Path: "some/file"}))
+				})
+			})
+
+			Context("when streaming out fails", func() {
+				disaster := errors.New("nope")
+
+				BeforeEach(func() {
+					fakeContainer.StreamOutReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(streamErr).To(Equal(disaster))
+				})
+			})
+		})
+
+		Context("when the container for the latest attempt is gone from the worker", func() {
+			BeforeEach(func() {
+				fakeProvider.FindLatestAttemptContainerHandleReturns("some-handle", true, nil)
+				fakeProvider.FindWorkerForContainerReturns(nil, false, nil)
+			})
+
+			It("returns a NoAttemptContainerError", func() {
+				Expect(streamErr).To(Equal(NoAttemptContainerError{BuildID: 42, StepName: "some-task"}))
+			})
+		})
+
+		Context("when no attempt has a container", func() {
+			BeforeEach(func() {
+				fakeProvider.FindLatestAttemptContainerHandleReturns("", false, nil)
+			})
+
+			It("returns a NoAttemptContainerError", func() {
+				Expect(streamErr).To(Equal(NoAttemptContainerError{BuildID: 42, StepName: "some-task"}))
+			})
+
+			It("does not look for a worker", func() {
+				Expect(fakeProvider.FindWorkerForContainerCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when looking up the latest attempt fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeProvider.FindLatestAttemptContainerHandleReturns("", false, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(streamErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("RunInContainer", func() {
+		var (
+			processSpec garden.ProcessSpec
+			processIO   garden.ProcessIO
+
+			process garden.Process
+			runErr  error
+		)
+
+		BeforeEach(func() {
+			processSpec = garden.ProcessSpec{Path: "bash"}
+			processIO = garden.ProcessIO{Stdin: new(bytes.Buffer)}
+		})
+
+		JustBeforeEach(func() {
+			process, runErr = pool.RunInContainer(logger, 4567, "some-handle", processSpec, processIO)
+		})
+
+		It("looks up the container's worker within the team", func() {
+			Expect(fakeProvider.FindWorkerForContainerCallCount()).To(Equal(1))
+
+			_, actualTeamID, actualHandle := fakeProvider.FindWorkerForContainerArgsForCall(0)
+			Expect(actualTeamID).To(Equal(4567))
+			Expect(actualHandle).To(Equal("some-handle"))
+		})
+
+		Context("when the container belongs to the team", func() {
+			var fakeWorker *workerfakes.FakeWorker
+			var fakeProcess *gardenfakes.FakeProcess
+
+			BeforeEach(func() {
+				fakeWorker = new(workerfakes.FakeWorker)
+				fakeProvider.FindWorkerForContainerReturns(fakeWorker, true, nil)
+
+				fakeProcess = new(gardenfakes.FakeProcess)
+				fakeWorker.RunInContainerReturns(fakeProcess, nil)
+			})
+
+			It("runs the process on the container's worker", func() {
+				Expect(runErr).NotTo(HaveOccurred())
+				Expect(process).To(Equal(fakeProcess))
+
+				Expect(fakeWorker.RunInContainerCallCount()).To(Equal(1))
+				_, actualTeamID, actualHandle, actualSpec, actualIO := fakeWorker.RunInContainerArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualHandle).To(Equal("some-handle"))
+				Expect(actualSpec).To(Equal(processSpec))
+				Expect(actualIO).To(Equal(processIO))
+			})
+		})
+
+		Context("when the container does not belong to the team", func() {
+			BeforeEach(func() {
+				fakeProvider.FindWorkerForContainerReturns(nil, false, nil)
+			})
+
+			It("returns a container not found error", func() {
+				Expect(runErr).To(Equal(garden.ContainerNotFoundError{Handle: "some-handle"}))
+				Expect(process).To(BeNil())
+			})
+		})
+
+		Context("when looking up the worker fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeProvider.FindWorkerForContainerReturns(nil, false, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(runErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("StreamContainerOutput", func() {
+		var (
+			output    io.ReadCloser
+			streamErr error
+		)
+
+		JustBeforeEach(func() {
+			output, streamErr = pool.StreamContainerOutput(logger, 4567, "some-handle")
+		})
+
+		Context("when the container belongs to the team", func() {
+			var fakeWorker *workerfakes.FakeWorker
+			var fakeOutput io.ReadCloser
+
+			BeforeEach(func() {
+				fakeWorker = new(workerfakes.FakeWorker)
+				fakeProvider.FindWorkerForContainerReturns(fakeWorker, true, nil)
+
+				fakeOutput = ioutil.NopCloser(bytes.NewBufferString("some-output"))
+				fakeWorker.StreamContainerOutputReturns(fakeOutput, nil)
+			})
+
+			It("streams the output from the container's worker", func() {
+				Expect(streamErr).NotTo(HaveOccurred())
+				Expect(output).To(Equal(fakeOutput))
+
+				_, actualTeamID, actualHandle := fakeProvider.FindWorkerForContainerArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualHandle).To(Equal("some-handle"))
+
+				Expect(fakeWorker.StreamContainerOutputCallCount()).To(Equal(1))
+				_, actualTeamID, actualHandle = fakeWorker.StreamContainerOutputArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualHandle).To(Equal("some-handle"))
+			})
+		})
+
+		Context("when the container does not belong to the team", func() {
+			BeforeEach(func() {
+				fakeProvider.FindWorkerForContainerReturns(nil, false, nil)
+			})
+
+			It("returns a container not found error", func() {
+				Expect(streamErr).To(Equal(garden.ContainerNotFoundError{Handle: "some-handle"}))
+				Expect(output).To(BeNil())
+			})
+		})
+	})
+
+	Describe("EvacuateWorker", func() {
+		var (
+			evacuateErr error
+
+			fakeDBContainer   *dbngfakes.FakeCreatedContainer
+			sourceWorker      *workerfakes.FakeWorker
+			sourceContainer   *workerfakes.FakeContainer
+			destinationWorker *workerfakes.FakeWorker
+
+			imageVolume  *workerfakes.FakeVolume
+			outputVolume *workerfakes.FakeVolume
+		)
+
+		BeforeEach(func() {
+			fakeDBContainer = new(dbngfakes.FakeCreatedContainer)
+			fakeDBContainer.HandleReturns("some-handle")
+			fakeDBContainer.MetadataReturns(dbng.ContainerMetadata{
+				Type:     dbng.ContainerTypeTask,
+				StepName: "some-task",
+			})
+
+			fakeProvider.FindEvacuatableContainersReturns([]dbng.EvacuatableContainer{
+				{
+					Container: fakeDBContainer,
+					TeamID:    4567,
+					BuildID:   42,
+					PlanID:    atc.PlanID("some-plan"),
+				},
+			}, nil)
+
+			sourceWorker = new(workerfakes.FakeWorker)
+			sourceWorker.NameReturns("some-worker")
+			sourceWorker.PlatformReturns("some-platform")
+			sourceWorker.TagsReturns(atc.Tags{"some-tag"})
+			sourceWorker.SatisfyingReturns(sourceWorker, nil)
+			fakeProvider.FindWorkerForContainerReturns(sourceWorker, true, nil)
+
+			imageVolume = new(workerfakes.FakeVolume)
+			imageVolume.HandleReturns("image-volume")
+			outputVolume = new(workerfakes.FakeVolume)
+			outputVolume.HandleReturns("output-volume")
+
+			sourceContainer = new(workerfakes.FakeContainer)
+			sourceContainer.PropertiesReturns(garden.Properties{
+				"user":                 "some-user",
+				"concourse:privileged": "true",
+				"concourse:env":        `["SOME=ENV"]`,
+				"concourse:dir":        "/tmp/build/some-guid",
+			}, nil)
+			sourceContainer.VolumeMountsReturns([]VolumeMount{
+				{Volume: imageVolume, MountPath: "/"},
+				{Volume: outputVolume, MountPath: "/tmp/build/some-guid/some-output"},
+			})
+			sourceWorker.FindContainerByHandleReturns(sourceContainer, true, nil)
+
+			destinationWorker = new(workerfakes.FakeWorker)
+			destinationWorker.NameReturns("other-worker")
+			destinationWorker.SatisfyingReturns(destinationWorker, nil)
+
+			fakeProvider.RunningWorkersReturns([]Worker{sourceWorker, destinationWorker}, nil)
+		})
+
+		JustBeforeEach(func() {
+			evacuateErr = pool.EvacuateWorker(logger, "some-worker")
+		})
+
+		It("looks up the evacuatable containers on the worker", func() {
+			Expect(fakeProvider.FindEvacuatableContainersCallCount()).To(Equal(1))
+			_, actualWorkerName := fakeProvider.FindEvacuatableContainersArgsForCall(0)
+			Expect(actualWorkerName).To(Equal("some-worker"))
+		})
+
+		It("recreates the container for the same build step on another worker", func() {
+			Expect(evacuateErr).NotTo(HaveOccurred())
+
+			Expect(sourceWorker.FindOrCreateBuildContainerCallCount()).To(Equal(0))
+			Expect(destinationWorker.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+
+			_, _, _, actualBuildID, actualPlanID, actualMetadata, actualSpec, _ := destinationWorker.FindOrCreateBuildContainerArgsForCall(0)
+			Expect(actualBuildID).To(Equal(42))
+			Expect(actualPlanID).To(Equal(atc.PlanID("some-plan")))
+			Expect(actualMetadata).To(Equal(dbng.ContainerMetadata{
+				Type:     dbng.ContainerTypeTask,
+				StepName: "some-task",
+			}))
+			Expect(actualSpec.TeamID).To(Equal(4567))
+			Expect(actualSpec.Platform).To(Equal("some-platform"))
+			Expect(actualSpec.Tags).To(Equal([]string{"some-tag"}))
+			Expect(actualSpec.User).To(Equal("some-user"))
+		})
+
+		It("recreates the container with the same privileges, environment and working directory", func() {
+			_, _, _, _, _, _, actualSpec, _ := destinationWorker.FindOrCreateBuildContainerArgsForCall(0)
+			Expect(actualSpec.ImageSpec.Privileged).To(BeTrue())
+			Expect(actualSpec.Env).To(Equal([]string{"SOME=ENV"}))
+			Expect(actualSpec.Dir).To(Equal("/tmp/build/some-guid"))
+		})
+
+		It("streams the container's volumes into the new container", func() {
+			_, _, _, _, _, _, actualSpec, _ := destinationWorker.FindOrCreateBuildContainerArgsForCall(0)
+
+			Expect(actualSpec.ImageSpec.ImageArtifactSource).NotTo(BeNil())
+			Expect(actualSpec.Inputs).To(HaveLen(1))
+			Expect(actualSpec.Inputs[0].DestinationPath()).To(Equal("/tmp/build/some-guid/some-output"))
+
+			fakeDestination := new(workerfakes.FakeArtifactDestination)
+			outputVolume.StreamOutReturns(ioutil.NopCloser(bytes.NewBufferString("some-tar")), nil)
+
+			err := actualSpec.Inputs[0].Source().StreamTo(fakeDestination)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(outputVolume.StreamOutCallCount()).To(Equal(1))
+			Expect(outputVolume.StreamOutArgsForCall(0)).To(Equal("."))
+			Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+		})
+
+		It("discontinues the original container", func() {
+			Expect(fakeDBContainer.DiscontinueCallCount()).To(Equal(1))
+		})
+
+		Context("when another worker has a different platform", func() {
+			var otherPlatformWorker *workerfakes.FakeWorker
+
+			BeforeEach(func() {
+				otherPlatformWorker = new(workerfakes.FakeWorker)
+				otherPlatformWorker.NameReturns("other-platform-worker")
+				otherPlatformWorker.SatisfyingStub = func(_ lager.Logger, spec WorkerSpec, _ atc.VersionedResourceTypes) (Worker, error) {
+					if spec.Platform != "other-platform" {
+						return nil, errors.New("nope")
+					}
+
+					return otherPlatformWorker, nil
+				}
+
+				fakeProvider.RunningWorkersReturns([]Worker{sourceWorker, otherPlatformWorker, destinationWorker}, nil)
+			})
+
+			It("only evacuates onto a worker with the source worker's platform", func() {
+				Expect(evacuateErr).NotTo(HaveOccurred())
+				Expect(otherPlatformWorker.FindOrCreateBuildContainerCallCount()).To(Equal(0))
+				Expect(destinationWorker.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when no other worker is compatible", func() {
+			BeforeEach(func() {
+				destinationWorker.SatisfyingReturns(nil, errors.New("nope"))
+			})
+
+			It("returns an error and leaves the container alone", func() {
+				Expect(evacuateErr).To(BeAssignableToTypeOf(NoCompatibleWorkersError{}))
+				Expect(fakeDBContainer.DiscontinueCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when creating the new container fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				destinationWorker.FindOrCreateBuildContainerReturns(nil, disaster)
+			})
+
+			It("returns the error and leaves the container alone", func() {
+				Expect(evacuateErr).To(Equal(disaster))
+				Expect(fakeDBContainer.DiscontinueCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("WarmInputs", func() {
+		var (
+			targetWorker *workerfakes.FakeWorker
+			otherWorker  *workerfakes.FakeWorker
+
+			presentSource   *workerfakes.FakeArtifactSource
+			cacheableSource *workerfakes.FakeCacheableArtifactSource
+			cacheVolume     *workerfakes.FakeVolume
+
+			warmErr error
+		)
+
+		BeforeEach(func() {
+			targetWorker = new(workerfakes.FakeWorker)
+			targetWorker.NameReturns("some-worker")
+			otherWorker = new(workerfakes.FakeWorker)
+			otherWorker.NameReturns("other-worker")
+			fakeProvider.RunningWorkersReturns([]Worker{otherWorker, targetWorker}, nil)
+
+			presentSource = new(workerfakes.FakeArtifactSource)
+			presentSource.VolumeOnReturns(new(workerfakes.FakeVolume), true, nil)
+
+			cacheVolume = new(workerfakes.FakeVolume)
+
+			cacheableSource = new(workerfakes.FakeCacheableArtifactSource)
+			cacheableSource.CreateCacheOnReturns(cacheVolume, nil)
+			cacheableSource.VolumeOnStub = func(Worker) (Volume, bool, error) {
+				if cacheVolume.InitializeCallCount() > 0 {
+					return cacheVolume, true, nil
+				}
+
+				return nil, false, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			warmErr = pool.WarmInputs(logger, "some-worker", []ArtifactSource{presentSource, cacheableSource})
+		})
+
+		It("succeeds", func() {
+			Expect(warmErr).NotTo(HaveOccurred())
+		})
+
+		It("skips sources already present on the worker", func() {
+			Expect(presentSource.VolumeOnArgsForCall(0)).To(Equal(targetWorker))
+			Expect(presentSource.StreamToCallCount()).To(BeZero())
+		})
+
+		It("streams missing sources into an initialized cache volume on the worker", func() {
+			Expect(cacheableSource.CreateCacheOnCallCount()).To(Equal(1))
+			_, w := cacheableSource.CreateCacheOnArgsForCall(0)
+			Expect(w).To(Equal(targetWorker))
+
+			Expect(cacheableSource.StreamToCallCount()).To(Equal(1))
+			Expect(cacheVolume.InitializeCallCount()).To(Equal(1))
+		})
+
+		It("makes the warmed source available through VolumeOn", func() {
+			volume, found, err := cacheableSource.VolumeOn(targetWorker)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(volume).To(Equal(cacheVolume))
+		})
+
+		Context("when warmed again", func() {
+			It("does not stream the source a second time", func() {
+				err := pool.WarmInputs(logger, "some-worker", []ArtifactSource{presentSource, cacheableSource})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cacheableSource.CreateCacheOnCallCount()).To(Equal(1))
+				Expect(cacheableSource.StreamToCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when streaming the source fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				cacheableSource.StreamToReturns(disaster)
+			})
+
+			It("returns the error without initializing the volume", func() {
+				Expect(warmErr).To(Equal(disaster))
+				Expect(cacheVolume.InitializeCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the worker is not running", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{otherWorker}, nil)
+			})
+
+			It("returns ErrMissingWorker", func() {
+				Expect(warmErr).To(Equal(ErrMissingWorker))
+			})
+		})
+	})
+
+	Describe("FindOrCreateBuildContainer", func() {
+		var (
+			signals                   <-chan os.Signal
+			fakeImageFetchingDelegate *workerfakes.FakeImageFetchingDelegate
+			metadata                  dbng.ContainerMetadata
+			spec                      ContainerSpec
+			resourceTypes             atc.VersionedResourceTypes
+
+			fakeContainer *workerfakes.FakeContainer
+
+			createdContainer Container
+			createErr        error
+
+			incompatibleWorker        *workerfakes.FakeWorker
+			compatibleWorkerOneCache1 *workerfakes.FakeWorker
+			compatibleWorkerOneCache2 *workerfakes.FakeWorker
+			compatibleWorkerTwoCaches *workerfakes.FakeWorker
+			compatibleWorkerNoCaches1 *workerfakes.FakeWorker
+			compatibleWorkerNoCaches2 *workerfakes.FakeWorker
+		)
+
+		BeforeEach(func() {
+			fakeImageFetchingDelegate = new(workerfakes.FakeImageFetchingDelegate)
+
+			fakeInput1 := new(workerfakes.FakeInputSource)
+			fakeInput1AS := new(workerfakes.FakeArtifactSource)
+			fakeInput1AS.VolumeOnStub = func(worker Worker) (Volume, bool, error) {
+				switch worker {
+				case compatibleWorkerOneCache1, compatibleWorkerOneCache2, compatibleWorkerTwoCaches:
+					return new(workerfakes.FakeVolume), true, nil
+				default:
+					return nil, false, nil
+				}
+			}
+			fakeInput1.SourceReturns(fakeInput1AS)
+
+			fakeInput2 := new(workerfakes.FakeInputSource)
+			fakeInput2AS := new(workerfakes.FakeArtifactSource)
 			fakeInput2AS.VolumeOnStub = func(worker Worker) (Volume, bool, error) {
 				switch worker {
 				case compatibleWorkerTwoCaches:
@@ -416,26 +1503,681 @@ var _ = Describe("Pool", func() {
 				default:
 					return nil, false, nil
 				}
-			}
-			fakeInput2.SourceReturns(fakeInput2AS)
+			}
+			fakeInput2.SourceReturns(fakeInput2AS)
+
+			spec = ContainerSpec{
+				ImageSpec: ImageSpec{ResourceType: "some-type"},
+
+				TeamID: 4567,
+
+				Inputs: []InputSource{
+					fakeInput1,
+					fakeInput2,
+				},
+			}
+
+			resourceTypes = atc.VersionedResourceTypes{
+				{
+					ResourceType: atc.ResourceType{
+						Name:   "custom-type-b",
+						Type:   "custom-type-a",
+						Source: atc.Source{"some": "source"},
+					},
+					Version: atc.Version{"some": "version"},
+				},
+			}
+
+			fakeContainer = new(workerfakes.FakeContainer)
+
+			incompatibleWorker = new(workerfakes.FakeWorker)
+			incompatibleWorker.SatisfyingReturns(nil, ErrIncompatiblePlatform)
+
+			compatibleWorkerOneCache1 = new(workerfakes.FakeWorker)
+			compatibleWorkerOneCache1.SatisfyingReturns(compatibleWorkerOneCache1, nil)
+			compatibleWorkerOneCache1.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+
+			compatibleWorkerOneCache2 = new(workerfakes.FakeWorker)
+			compatibleWorkerOneCache2.SatisfyingReturns(compatibleWorkerOneCache2, nil)
+			compatibleWorkerOneCache2.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+
+			compatibleWorkerTwoCaches = new(workerfakes.FakeWorker)
+			compatibleWorkerTwoCaches.SatisfyingReturns(compatibleWorkerTwoCaches, nil)
+			compatibleWorkerTwoCaches.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+
+			compatibleWorkerNoCaches1 = new(workerfakes.FakeWorker)
+			compatibleWorkerNoCaches1.SatisfyingReturns(compatibleWorkerNoCaches1, nil)
+			compatibleWorkerNoCaches1.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+
+			compatibleWorkerNoCaches2 = new(workerfakes.FakeWorker)
+			compatibleWorkerNoCaches2.SatisfyingReturns(compatibleWorkerNoCaches2, nil)
+			compatibleWorkerNoCaches2.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+		})
+
+		JustBeforeEach(func() {
+			createdContainer, createErr = pool.FindOrCreateBuildContainer(
+				logger,
+				signals,
+				fakeImageFetchingDelegate,
+				42,
+				atc.PlanID("some-plan-id"),
+				metadata,
+				spec,
+				resourceTypes,
+			)
+		})
+
+		Context("when a worker is found with the container", func() {
+			var fakeWorker *workerfakes.FakeWorker
+
+			BeforeEach(func() {
+				fakeWorker = new(workerfakes.FakeWorker)
+				fakeProvider.FindWorkerForBuildContainerReturns(fakeWorker, true, nil)
+				fakeWorker.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+			})
+
+			It("succeeds", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+			})
+
+			It("returns the created container", func() {
+				Expect(createdContainer).To(Equal(fakeContainer))
+			})
+
+			It("'find-or-create's on the particular worker", func() {
+				Expect(fakeWorker.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+
+				_, actualTeamID, actualBuildID, actualPlanID := fakeProvider.FindWorkerForBuildContainerArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualBuildID).To(Equal(42))
+				Expect(actualPlanID).To(Equal(atc.PlanID("some-plan-id")))
+			})
+		})
+
+		Context("when no worker is found with the container", func() {
+			BeforeEach(func() {
+				fakeProvider.FindWorkerForBuildContainerReturns(nil, false, nil)
+			})
+
+			Context("with no workers available", func() {
+				BeforeEach(func() {
+					fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+				})
+
+				It("returns ErrNoWorkers", func() {
+					Expect(createErr).To(Equal(ErrNoWorkers))
+				})
+			})
+
+			Context("with no compatible workers available", func() {
+				BeforeEach(func() {
+					fakeProvider.RunningWorkersReturns([]Worker{incompatibleWorker}, nil)
+				})
+
+				It("returns NoCompatibleWorkersError", func() {
+					Expect(createErr).To(Equal(NoCompatibleWorkersError{
+						Spec:    spec.WorkerSpec(),
+						Workers: []Worker{incompatibleWorker},
+						Mismatches: []WorkerMismatch{
+							{Reason: ErrIncompatiblePlatform, Detail: "platform  != "},
+						},
+					}))
+				})
+			})
+
+			Context("with compatible workers available, with one having the most local caches", func() {
+				BeforeEach(func() {
+					fakeProvider.RunningWorkersReturns([]Worker{
+						incompatibleWorker,
+						compatibleWorkerOneCache1,
+						compatibleWorkerTwoCaches,
+						compatibleWorkerNoCaches1,
+						compatibleWorkerNoCaches2,
+					}, nil)
+				})
+
+				It("creates it on the worker with the most caches", func() {
+					Expect(createErr).ToNot(HaveOccurred())
+					Expect(compatibleWorkerTwoCaches.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+					Expect(createdContainer).To(Equal(fakeContainer))
+				})
+
+				Context("when the workers are named", func() {
+					BeforeEach(func() {
+						compatibleWorkerOneCache1.NameReturns("one-cache")
+						compatibleWorkerTwoCaches.NameReturns("two-caches")
+						compatibleWorkerTwoCaches.ActiveContainersReturns(3)
+						compatibleWorkerNoCaches1.NameReturns("no-caches-1")
+						compatibleWorkerNoCaches2.NameReturns("no-caches-2")
+					})
+
+					It("logs the input volumes found on each candidate", func() {
+						var choosing []lager.LogFormat
+						for _, log := range logger.Logs() {
+							if log.Message == "test.choosing-worker" {
+								choosing = append(choosing, log)
+							}
+						}
+
+						Expect(choosing).To(HaveLen(1))
+						Expect(choosing[0].Data["candidates"]).To(Equal(map[string]interface{}{
+							"one-cache":   float64(1),
+							"two-caches":  float64(2),
+							"no-caches-1": float64(0),
+							"no-caches-2": float64(0),
+						}))
+						Expect(choosing[0].Data["input-volumes"]).To(Equal(float64(2)))
+						Expect(choosing[0].Data["preferred"]).To(Equal([]interface{}{"two-caches"}))
+					})
+
+					It("logs the chosen worker", func() {
+						var chosen []lager.LogFormat
+						for _, log := range logger.Logs() {
+							if log.Message == "test.chose-worker" {
+								chosen = append(chosen, log)
+							}
+						}
+
+						Expect(chosen).To(HaveLen(1))
+						Expect(chosen[0].Data["worker"]).To(Equal("two-caches"))
+						Expect(chosen[0].Data["input-volumes"]).To(Equal(float64(2)))
+						Expect(chosen[0].Data["active-containers"]).To(Equal(float64(3)))
+						Expect(chosen[0].Data["reason"]).To(Equal("most-input-volumes"))
+					})
+				})
+			})
+
+			Context("with compatible workers available, with multiple with the same amount of local caches", func() {
+				BeforeEach(func() {
+					fakeProvider.RunningWorkersReturns([]Worker{
+						incompatibleWorker,
+						compatibleWorkerOneCache1,
+						compatibleWorkerOneCache2,
+						compatibleWorkerNoCaches1,
+						compatibleWorkerNoCaches2,
+					}, nil)
+				})
+
+				It("creates it on a random one of the two", func() {
+					Expect(createErr).ToNot(HaveOccurred())
+					Expect(createdContainer).To(Equal(fakeContainer))
+
+					for i := 0; i < 100; i++ {
+						container, err := pool.FindOrCreateBuildContainer(
+							logger,
+							signals,
+							fakeImageFetchingDelegate,
+							42,
+							atc.PlanID("some-plan-id"),
+							metadata,
+							spec,
+							resourceTypes,
+						)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(container).To(Equal(fakeContainer))
+					}
+
+					Expect(compatibleWorkerOneCache1.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
+					Expect(compatibleWorkerOneCache2.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
+					Expect(compatibleWorkerNoCaches1.FindOrCreateBuildContainerCallCount()).To(BeZero())
+					Expect(compatibleWorkerNoCaches2.FindOrCreateBuildContainerCallCount()).To(BeZero())
+				})
+			})
+
+			Context("with workers that have the same amount of local caches but different loads", func() {
+				BeforeEach(func() {
+					compatibleWorkerOneCache1.ActiveContainersReturns(20)
+					compatibleWorkerOneCache2.ActiveContainersReturns(5)
+					compatibleWorkerNoCaches1.ActiveContainersReturns(0)
+
+					fakeProvider.RunningWorkersReturns([]Worker{
+						compatibleWorkerOneCache1,
+						compatibleWorkerOneCache2,
+						compatibleWorkerNoCaches1,
+					}, nil)
+				})
+
+				It("creates it on the least loaded of the workers with the most caches", func() {
+					Expect(createErr).ToNot(HaveOccurred())
+					Expect(createdContainer).To(Equal(fakeContainer))
+
+					for i := 0; i < 100; i++ {
+						_, err := pool.FindOrCreateBuildContainer(
+							logger,
+							signals,
+							fakeImageFetchingDelegate,
+							42,
+							atc.PlanID("some-plan-id"),
+							metadata,
+							spec,
+							resourceTypes,
+						)
+						Expect(err).ToNot(HaveOccurred())
+					}
+
+					Expect(compatibleWorkerOneCache2.FindOrCreateBuildContainerCallCount()).To(Equal(101))
+					Expect(compatibleWorkerOneCache1.FindOrCreateBuildContainerCallCount()).To(BeZero())
+					Expect(compatibleWorkerNoCaches1.FindOrCreateBuildContainerCallCount()).To(BeZero())
+				})
+
+				Context("when the least loaded worker is full", func() {
+					BeforeEach(func() {
+						compatibleWorkerOneCache2.FindOrCreateBuildContainerReturns(nil, errors.New("worker already has the maximum number of active containers"))
+					})
+
+					It("falls back to the next least loaded worker with the most caches", func() {
+						Expect(createErr).ToNot(HaveOccurred())
+						Expect(compatibleWorkerOneCache2.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+						Expect(compatibleWorkerOneCache1.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+						Expect(compatibleWorkerNoCaches1.FindOrCreateBuildContainerCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when all of the workers with the most caches are full", func() {
+					var fullErr1, fullErr2 error
+
+					BeforeEach(func() {
+						compatibleWorkerOneCache1.NameReturns("worker-1")
+						compatibleWorkerOneCache2.NameReturns("worker-2")
+
+						fullErr1 = errors.New("worker already has the maximum number of active containers (250)")
+						fullErr2 = errors.New("worker already has the maximum number of active containers (100)")
+						compatibleWorkerOneCache1.FindOrCreateBuildContainerReturns(nil, fullErr1)
+						compatibleWorkerOneCache2.FindOrCreateBuildContainerReturns(nil, fullErr2)
+					})
+
+					It("returns an error listing each worker and why it failed", func() {
+						Expect(createErr).To(Equal(AllWorkersFailedError{
+							Failures: []WorkerNotCreatedError{
+								{Worker: "worker-2", Err: fullErr2},
+								{Worker: "worker-1", Err: fullErr1},
+							},
+						}))
+
+						Expect(createErr.Error()).To(Equal(
+							"failed to create container on all compatible workers\n" +
+								"  - worker worker-2: worker already has the maximum number of active containers (100)\n" +
+								"  - worker worker-1: worker already has the maximum number of active containers (250)",
+						))
+					})
+				})
+			})
+
+			Context("with compatible workers available, with none having any local caches", func() {
+				BeforeEach(func() {
+					fakeProvider.RunningWorkersReturns([]Worker{
+						incompatibleWorker,
+						compatibleWorkerNoCaches1,
+						compatibleWorkerNoCaches2,
+					}, nil)
+				})
+
+				It("creates it on a random one of them", func() {
+					Expect(createErr).ToNot(HaveOccurred())
+					Expect(createdContainer).To(Equal(fakeContainer))
+
+					for i := 0; i < 100; i++ {
+						container, err := pool.FindOrCreateBuildContainer(
+							logger,
+							signals,
+							fakeImageFetchingDelegate,
+							42,
+							atc.PlanID("some-plan-id"),
+							metadata,
+							spec,
+							resourceTypes,
+						)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(container).To(Equal(fakeContainer))
+					}
+
+					Expect(incompatibleWorker.FindOrCreateBuildContainerCallCount()).To(BeZero())
+					Expect(compatibleWorkerNoCaches1.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
+					Expect(compatibleWorkerNoCaches2.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
+				})
+			})
+		})
+	})
+
+	Describe("FindOrCreateResourceCheckContainer", func() {
+		var (
+			fakeImageFetchingDelegate *workerfakes.FakeImageFetchingDelegate
+
+			spec ContainerSpec
+
+			fakeContainer *workerfakes.FakeContainer
+
+			createdContainer Container
+			createErr        error
+			resourceTypes    atc.VersionedResourceTypes
+		)
+
+		BeforeEach(func() {
+			fakeImageFetchingDelegate = new(workerfakes.FakeImageFetchingDelegate)
+
+			spec = ContainerSpec{
+				ImageSpec: ImageSpec{ResourceType: "some-type"},
+				TeamID:    4567,
+			}
+
+			resourceTypes = atc.VersionedResourceTypes{
+				{
+					ResourceType: atc.ResourceType{
+						Name:   "custom-type-b",
+						Type:   "custom-type-a",
+						Source: atc.Source{"some": "source"},
+					},
+					Version: atc.Version{"some": "version"},
+				},
+				{
+					ResourceType: atc.ResourceType{
+						Name:   "custom-type-a",
+						Type:   "some-resource",
+						Source: atc.Source{"some": "source"},
+					},
+					Version: atc.Version{"some": "version"},
+				},
+				{
+					ResourceType: atc.ResourceType{
+						Name:   "custom-type-c",
+						Type:   "custom-type-b",
+						Source: atc.Source{"some": "source"},
+					},
+					Version: atc.Version{"some": "version"},
+				},
+				{
+					ResourceType: atc.ResourceType{
+						Name:   "custom-type-d",
+						Type:   "custom-type-b",
+						Source: atc.Source{"some": "source"},
+					},
+					Version: atc.Version{"some": "version"},
+				},
+				{
+					ResourceType: atc.ResourceType{
+						Name:   "unknown-custom-type",
+						Type:   "unknown-base-type",
+						Source: atc.Source{"some": "source"},
+					},
+					Version: atc.Version{"some": "version"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			createdContainer, createErr = pool.FindOrCreateResourceCheckContainer(
+				logger,
+				dbng.ForBuild(42),
+				make(chan os.Signal),
+				fakeImageFetchingDelegate,
+				dbng.ContainerMetadata{},
+				spec,
+				resourceTypes,
+				"some-type",
+				atc.Source{"some": "source"},
+			)
+		})
+
+		Context("when a worker is found with the container", func() {
+			var fakeWorker *workerfakes.FakeWorker
+
+			BeforeEach(func() {
+				fakeWorker = new(workerfakes.FakeWorker)
+				fakeProvider.FindWorkerForResourceCheckContainerReturns(fakeWorker, true, nil)
+				fakeWorker.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+			})
+
+			It("succeeds", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+			})
+
+			It("returns the created container", func() {
+				Expect(createdContainer).To(Equal(fakeContainer))
+			})
+
+			It("'find-or-create's on the particular worker", func() {
+				Expect(fakeWorker.FindOrCreateResourceCheckContainerCallCount()).To(Equal(1))
+
+				_, actualTeamID, actualResourceUser, actualResourceType, actualResourceSource, actualResourceTypes := fakeProvider.FindWorkerForResourceCheckContainerArgsForCall(0)
+				Expect(actualTeamID).To(Equal(4567))
+				Expect(actualResourceUser).To(Equal(dbng.ForBuild(42)))
+				Expect(actualResourceType).To(Equal("some-type"))
+				Expect(actualResourceSource).To(Equal(atc.Source{"some": "source"}))
+				Expect(actualResourceTypes).To(Equal(resourceTypes))
+			})
+		})
+
+		Context("when the resource type is a custom type with tags", func() {
+			var (
+				fakeWorker  *workerfakes.FakeWorker
+				otherWorker *workerfakes.FakeWorker
+			)
+
+			BeforeEach(func() {
+				spec.Tags = []string{"some-tag"}
+
+				resourceTypes = append(resourceTypes, atc.VersionedResourceType{
+					ResourceType: atc.ResourceType{
+						Name:   "some-type",
+						Type:   "some-resource",
+						Source: atc.Source{"some": "source"},
+						Tags:   atc.Tags{"some-tag", "gpu"},
+					},
+					Version: atc.Version{"some": "version"},
+				})
+
+				fakeWorker = new(workerfakes.FakeWorker)
+				fakeWorker.SatisfyingReturns(fakeWorker, nil)
+				fakeWorker.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+
+				otherWorker = new(workerfakes.FakeWorker)
+				otherWorker.SatisfyingReturns(otherWorker, nil)
+				otherWorker.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+
+				fakeProvider.RunningWorkersReturns([]Worker{otherWorker}, nil)
+			})
+
+			Context("when a worker is found with the container", func() {
+				BeforeEach(func() {
+					fakeProvider.FindWorkerForResourceCheckContainerReturns(fakeWorker, true, nil)
+				})
+
+				It("checks that the worker has the type's tags", func() {
+					Expect(fakeWorker.SatisfyingCallCount()).To(Equal(1))
+					_, actualSpec, _ := fakeWorker.SatisfyingArgsForCall(0)
+					Expect(actualSpec.Tags).To(Equal([]string{"some-tag", "gpu"}))
+				})
+
+				It("'find-or-create's on the worker with the type's tags", func() {
+					Expect(fakeWorker.FindOrCreateResourceCheckContainerCallCount()).To(Equal(1))
+					_, _, _, _, _, actualSpec, _, _, _ := fakeWorker.FindOrCreateResourceCheckContainerArgsForCall(0)
+					Expect(actualSpec.Tags).To(Equal([]string{"some-tag", "gpu"}))
+				})
+
+				Context("when the worker does not have the type's tags", func() {
+					BeforeEach(func() {
+						fakeWorker.SatisfyingReturns(nil, ErrMismatchedTags)
+					})
+
+					It("'find-or-create's on a worker that does", func() {
+						Expect(fakeWorker.FindOrCreateResourceCheckContainerCallCount()).To(BeZero())
+						Expect(otherWorker.FindOrCreateResourceCheckContainerCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			Context("when a worker is not found", func() {
+				BeforeEach(func() {
+					fakeProvider.FindWorkerForResourceCheckContainerReturns(nil, false, nil)
+				})
+
+				It("only considers workers with the type's tags", func() {
+					Expect(otherWorker.SatisfyingCallCount()).To(Equal(1))
+					_, actualSpec, _ := otherWorker.SatisfyingArgsForCall(0)
+					Expect(actualSpec.Tags).To(Equal([]string{"some-tag", "gpu"}))
+				})
+
+				It("does not modify the given spec", func() {
+					Expect(spec.Tags).To(Equal([]string{"some-tag"}))
+				})
+			})
+		})
+
+		Context("when a worker is not found, and multiple are present", func() {
+			var (
+				workerA *workerfakes.FakeWorker
+				workerB *workerfakes.FakeWorker
+				workerC *workerfakes.FakeWorker
+			)
+
+			BeforeEach(func() {
+				fakeProvider.FindWorkerForResourceCheckContainerReturns(nil, false, nil)
+
+				workerA = new(workerfakes.FakeWorker)
+				workerB = new(workerfakes.FakeWorker)
+				workerC = new(workerfakes.FakeWorker)
+
+				workerA.ActiveContainersReturns(3)
+				workerB.ActiveContainersReturns(2)
+
+				workerA.SatisfyingReturns(workerA, nil)
+				workerB.SatisfyingReturns(workerB, nil)
+				workerC.SatisfyingReturns(nil, errors.New("nope"))
+
+				workerA.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+				workerB.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+				workerC.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+
+				fakeProvider.RunningWorkersReturns([]Worker{workerA, workerB, workerC}, nil)
+			})
+
+			It("succeeds", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+			})
+
+			It("returns the created container", func() {
+				Expect(createdContainer).To(Equal(fakeContainer))
+			})
+
+			It("checks that the workers satisfy the given spec", func() {
+				Expect(workerA.SatisfyingCallCount()).To(Equal(1))
+				_, actualSpec, actualResourceTypes := workerA.SatisfyingArgsForCall(0)
+				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
+				Expect(actualResourceTypes).To(Equal(resourceTypes))
+
+				Expect(workerB.SatisfyingCallCount()).To(Equal(1))
+				_, actualSpec, actualResourceTypes = workerB.SatisfyingArgsForCall(0)
+				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
+				Expect(actualResourceTypes).To(Equal(resourceTypes))
+
+				Expect(workerC.SatisfyingCallCount()).To(Equal(1))
+				_, actualSpec, actualResourceTypes = workerC.SatisfyingArgsForCall(0)
+				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
+				Expect(actualResourceTypes).To(Equal(resourceTypes))
+			})
+
+			It("creates using a random worker", func() {
+				for i := 1; i < 100; i++ { // account for initial create in JustBefore
+					createdContainer, createErr := pool.FindOrCreateResourceCheckContainer(
+						logger,
+						dbng.ForBuild(42),
+						make(chan os.Signal),
+						fakeImageFetchingDelegate,
+						dbng.ContainerMetadata{},
+						spec,
+						resourceTypes,
+						"some-type",
+						atc.Source{"some": "source"},
+					)
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(createdContainer).To(Equal(fakeContainer))
+				}
 
-			spec = ContainerSpec{
-				ImageSpec: ImageSpec{ResourceType: "some-type"},
+				Expect(workerA.FindOrCreateResourceCheckContainerCallCount()).To(BeNumerically("~", workerB.FindOrCreateResourceCheckContainerCallCount(), 50))
+				Expect(workerC.FindOrCreateResourceCheckContainerCallCount()).To(BeZero())
+			})
 
-				TeamID: 4567,
+			Context("when creating the container fails", func() {
+				disaster := errors.New("nope")
 
-				Inputs: []InputSource{
-					fakeInput1,
-					fakeInput2,
-				},
+				BeforeEach(func() {
+					workerA.FindOrCreateResourceCheckContainerReturns(nil, disaster)
+					workerB.FindOrCreateResourceCheckContainerReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(createErr).To(Equal(disaster))
+				})
+			})
+
+			Context("when no workers satisfy the spec", func() {
+				BeforeEach(func() {
+					workerA.SatisfyingReturns(nil, errors.New("nope"))
+					workerB.SatisfyingReturns(nil, errors.New("nope"))
+					workerC.SatisfyingReturns(nil, errors.New("nope"))
+				})
+
+				It("returns a NoCompatibleWorkersError", func() {
+					nope := WorkerMismatch{Reason: errors.New("nope"), Detail: "nope"}
+
+					Expect(createErr).To(Equal(NoCompatibleWorkersError{
+						Spec:       spec.WorkerSpec(),
+						Workers:    []Worker{workerA, workerB, workerC},
+						Mismatches: []WorkerMismatch{nope, nope, nope},
+					}))
+
+				})
+			})
+		})
+
+		Context("with no workers", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+			})
+
+			It("returns ErrNoWorkers", func() {
+				Expect(createErr).To(Equal(ErrNoWorkers))
+			})
+		})
+
+		Context("when getting the workers fails", func() {
+			disaster := errors.New("nope")
+
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(createErr).To(Equal(disaster))
+			})
+		})
+	})
+
+	Describe("CreateResourceGetContainer", func() {
+		var (
+			spec          ContainerSpec
+			resourceTypes atc.VersionedResourceTypes
+
+			fakeWorker    *workerfakes.FakeWorker
+			fakeContainer *workerfakes.FakeContainer
+
+			createdContainer Container
+			createErr        error
+		)
+
+		BeforeEach(func() {
+			spec = ContainerSpec{
+				ImageSpec: ImageSpec{ResourceType: "some-type"},
+				TeamID:    4567,
 			}
 
 			resourceTypes = atc.VersionedResourceTypes{
 				{
 					ResourceType: atc.ResourceType{
-						Name:   "custom-type-b",
-						Type:   "custom-type-a",
+						Name:   "some-type",
+						Type:   "some-resource",
 						Source: atc.Source{"some": "source"},
+						Tags:   atc.Tags{"gpu"},
 					},
 					Version: atc.Version{"some": "version"},
 				},
@@ -443,420 +2185,437 @@ var _ = Describe("Pool", func() {
 
 			fakeContainer = new(workerfakes.FakeContainer)
 
-			incompatibleWorker = new(workerfakes.FakeWorker)
-			incompatibleWorker.SatisfyingReturns(nil, ErrIncompatiblePlatform)
-
-			compatibleWorkerOneCache1 = new(workerfakes.FakeWorker)
-			compatibleWorkerOneCache1.SatisfyingReturns(compatibleWorkerOneCache1, nil)
-			compatibleWorkerOneCache1.FindOrCreateBuildContainerReturns(fakeContainer, nil)
-
-			compatibleWorkerOneCache2 = new(workerfakes.FakeWorker)
-			compatibleWorkerOneCache2.SatisfyingReturns(compatibleWorkerOneCache2, nil)
-			compatibleWorkerOneCache2.FindOrCreateBuildContainerReturns(fakeContainer, nil)
-
-			compatibleWorkerTwoCaches = new(workerfakes.FakeWorker)
-			compatibleWorkerTwoCaches.SatisfyingReturns(compatibleWorkerTwoCaches, nil)
-			compatibleWorkerTwoCaches.FindOrCreateBuildContainerReturns(fakeContainer, nil)
-
-			compatibleWorkerNoCaches1 = new(workerfakes.FakeWorker)
-			compatibleWorkerNoCaches1.SatisfyingReturns(compatibleWorkerNoCaches1, nil)
-			compatibleWorkerNoCaches1.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+			fakeWorker = new(workerfakes.FakeWorker)
+			fakeWorker.SatisfyingReturns(fakeWorker, nil)
+			fakeWorker.CreateResourceGetContainerReturns(fakeContainer, nil)
 
-			compatibleWorkerNoCaches2 = new(workerfakes.FakeWorker)
-			compatibleWorkerNoCaches2.SatisfyingReturns(compatibleWorkerNoCaches2, nil)
-			compatibleWorkerNoCaches2.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+			fakeProvider.RunningWorkersReturns([]Worker{fakeWorker}, nil)
 		})
 
 		JustBeforeEach(func() {
-			createdContainer, createErr = pool.FindOrCreateBuildContainer(
+			createdContainer, createErr = pool.CreateResourceGetContainer(
 				logger,
-				signals,
-				fakeImageFetchingDelegate,
-				42,
-				atc.PlanID("some-plan-id"),
-				metadata,
+				dbng.ForBuild(42),
+				make(chan os.Signal),
+				new(workerfakes.FakeImageFetchingDelegate),
+				dbng.ContainerMetadata{},
 				spec,
 				resourceTypes,
+				"some-type",
+				atc.Version{"some": "version"},
+				atc.Source{"some": "source"},
+				atc.Params{},
 			)
 		})
 
-		Context("when a worker is found with the container", func() {
-			var fakeWorker *workerfakes.FakeWorker
+		It("returns the created container", func() {
+			Expect(createErr).NotTo(HaveOccurred())
+			Expect(createdContainer).To(Equal(fakeContainer))
+		})
 
+		It("only considers workers with the resource type's tags", func() {
+			Expect(fakeWorker.SatisfyingCallCount()).To(Equal(1))
+			_, actualSpec, _ := fakeWorker.SatisfyingArgsForCall(0)
+			Expect(actualSpec.Tags).To(Equal([]string{"gpu"}))
+		})
+
+		It("creates the container with the resource type's tags", func() {
+			Expect(fakeWorker.CreateResourceGetContainerCallCount()).To(Equal(1))
+			_, _, _, _, _, actualSpec, _, _, _, _, _ := fakeWorker.CreateResourceGetContainerArgsForCall(0)
+			Expect(actualSpec.Tags).To(Equal([]string{"gpu"}))
+		})
+
+		Context("when the resource type is not a custom type", func() {
 			BeforeEach(func() {
-				fakeWorker = new(workerfakes.FakeWorker)
-				fakeProvider.FindWorkerForBuildContainerReturns(fakeWorker, true, nil)
-				fakeWorker.FindOrCreateBuildContainerReturns(fakeContainer, nil)
+				resourceTypes = atc.VersionedResourceTypes{}
 			})
 
-			It("succeeds", func() {
-				Expect(createErr).NotTo(HaveOccurred())
+			It("uses the spec's tags as-is", func() {
+				_, actualSpec, _ := fakeWorker.SatisfyingArgsForCall(0)
+				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
 			})
+		})
 
-			It("returns the created container", func() {
-				Expect(createdContainer).To(Equal(fakeContainer))
-			})
+		Context("with multiple compatible workers", func() {
+			var otherWorker *workerfakes.FakeWorker
 
-			It("'find-or-create's on the particular worker", func() {
-				Expect(fakeWorker.FindOrCreateBuildContainerCallCount()).To(Equal(1))
+			BeforeEach(func() {
+				otherWorker = new(workerfakes.FakeWorker)
+				otherWorker.SatisfyingReturns(otherWorker, nil)
+				otherWorker.CreateResourceGetContainerReturns(fakeContainer, nil)
 
-				_, actualTeamID, actualBuildID, actualPlanID := fakeProvider.FindWorkerForBuildContainerArgsForCall(0)
-				Expect(actualTeamID).To(Equal(4567))
-				Expect(actualBuildID).To(Equal(42))
-				Expect(actualPlanID).To(Equal(atc.PlanID("some-plan-id")))
+				fakeProvider.RunningWorkersReturns([]Worker{fakeWorker, otherWorker}, nil)
 			})
-		})
 
-		Context("when no worker is found with the container", func() {
-			BeforeEach(func() {
-				fakeProvider.FindWorkerForBuildContainerReturns(nil, false, nil)
+			It("creates the container on only one of them", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+				Expect(createdContainer).To(Equal(fakeContainer))
+
+				Expect(fakeWorker.CreateResourceGetContainerCallCount() +
+					otherWorker.CreateResourceGetContainerCallCount()).To(Equal(1))
 			})
 
-			Context("with no workers available", func() {
+			Context("when one of them is full", func() {
 				BeforeEach(func() {
-					fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+					fakeWorker.CreateResourceGetContainerReturns(nil, errors.New("worker already has the maximum number of active containers"))
 				})
 
-				It("returns ErrNoWorkers", func() {
-					Expect(createErr).To(Equal(ErrNoWorkers))
+				It("creates the container on the other", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(createdContainer).To(Equal(fakeContainer))
+					Expect(otherWorker.CreateResourceGetContainerCallCount()).To(Equal(1))
 				})
 			})
 
-			Context("with no compatible workers available", func() {
+			Context("when all of them are full", func() {
+				var fullErr error
+
 				BeforeEach(func() {
-					fakeProvider.RunningWorkersReturns([]Worker{incompatibleWorker}, nil)
+					fullErr = errors.New("worker already has the maximum number of active containers")
+					fakeWorker.CreateResourceGetContainerReturns(nil, fullErr)
+					otherWorker.CreateResourceGetContainerReturns(nil, fullErr)
 				})
 
-				It("returns NoCompatibleWorkersError", func() {
-					Expect(createErr).To(Equal(NoCompatibleWorkersError{
-						Spec:    spec.WorkerSpec(),
-						Workers: []Worker{incompatibleWorker},
-					}))
+				It("tries each of them and returns the last error", func() {
+					Expect(createErr).To(Equal(fullErr))
+					Expect(fakeWorker.CreateResourceGetContainerCallCount()).To(Equal(1))
+					Expect(otherWorker.CreateResourceGetContainerCallCount()).To(Equal(1))
 				})
 			})
 
-			Context("with compatible workers available, with one having the most local caches", func() {
+			Context("when creating the container fails for another reason", func() {
+				var disaster error
+
 				BeforeEach(func() {
-					fakeProvider.RunningWorkersReturns([]Worker{
-						incompatibleWorker,
-						compatibleWorkerOneCache1,
-						compatibleWorkerTwoCaches,
-						compatibleWorkerNoCaches1,
-						compatibleWorkerNoCaches2,
-					}, nil)
+					disaster = errors.New("nope")
+					fakeWorker.CreateResourceGetContainerReturns(nil, disaster)
+					otherWorker.CreateResourceGetContainerReturns(nil, disaster)
 				})
 
-				It("creates it on the worker with the most caches", func() {
-					Expect(createErr).ToNot(HaveOccurred())
-					Expect(compatibleWorkerTwoCaches.FindOrCreateBuildContainerCallCount()).To(Equal(1))
-					Expect(createdContainer).To(Equal(fakeContainer))
+				It("returns the error without trying the other worker", func() {
+					Expect(createErr).To(Equal(disaster))
+					Expect(fakeWorker.CreateResourceGetContainerCallCount() +
+						otherWorker.CreateResourceGetContainerCallCount()).To(Equal(1))
 				})
 			})
+		})
+	})
 
-			Context("with compatible workers available, with multiple with the same amount of local caches", func() {
+	Describe("LookupVolume", func() {
+		var (
+			workerA *workerfakes.FakeWorker
+			workerB *workerfakes.FakeWorker
+			workerC *workerfakes.FakeWorker
+
+			fakeVolume *workerfakes.FakeVolume
+
+			foundVolume Volume
+			found       bool
+			lookupErr   error
+		)
+
+		BeforeEach(func() {
+			workerA = new(workerfakes.FakeWorker)
+			workerA.NameReturns("worker-a")
+			workerB = new(workerfakes.FakeWorker)
+			workerB.NameReturns("worker-b")
+			workerC = new(workerfakes.FakeWorker)
+			workerC.NameReturns("worker-c")
+
+			fakeVolume = new(workerfakes.FakeVolume)
+
+			fakeProvider.RunningWorkersReturns([]Worker{workerA, workerB, workerC}, nil)
+		})
+
+		JustBeforeEach(func() {
+			foundVolume, found, lookupErr = pool.LookupVolume(logger, "some-handle")
+		})
+
+		Context("when the volume is on the second worker", func() {
+			BeforeEach(func() {
+				workerB.LookupVolumeReturns(fakeVolume, true, nil)
+			})
+
+			It("returns the volume from that worker", func() {
+				Expect(lookupErr).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(foundVolume).To(Equal(fakeVolume))
+
+				_, handle := workerB.LookupVolumeArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+			})
+
+			It("stops looking once it is found", func() {
+				Expect(workerA.LookupVolumeCallCount()).To(Equal(1))
+				Expect(workerB.LookupVolumeCallCount()).To(Equal(1))
+				Expect(workerC.LookupVolumeCallCount()).To(BeZero())
+			})
+
+			Context("when the first worker fails to look it up", func() {
 				BeforeEach(func() {
-					fakeProvider.RunningWorkersReturns([]Worker{
-						incompatibleWorker,
-						compatibleWorkerOneCache1,
-						compatibleWorkerOneCache2,
-						compatibleWorkerNoCaches1,
-						compatibleWorkerNoCaches2,
-					}, nil)
+					workerA.LookupVolumeReturns(nil, false, errors.New("nope"))
 				})
 
-				It("creates it on a random one of the two", func() {
-					Expect(createErr).ToNot(HaveOccurred())
-					Expect(createdContainer).To(Equal(fakeContainer))
+				It("still returns the volume from the second worker", func() {
+					Expect(lookupErr).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(foundVolume).To(Equal(fakeVolume))
+				})
+			})
+		})
 
-					for i := 0; i < 100; i++ {
-						container, err := pool.FindOrCreateBuildContainer(
-							logger,
-							signals,
-							fakeImageFetchingDelegate,
-							42,
-							atc.PlanID("some-plan-id"),
-							metadata,
-							spec,
-							resourceTypes,
-						)
-						Expect(err).ToNot(HaveOccurred())
-						Expect(container).To(Equal(fakeContainer))
-					}
+		Context("when no worker has the volume", func() {
+			It("returns not found", func() {
+				Expect(lookupErr).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+				Expect(foundVolume).To(BeNil())
 
-					Expect(compatibleWorkerOneCache1.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
-					Expect(compatibleWorkerOneCache2.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
-					Expect(compatibleWorkerNoCaches1.FindOrCreateBuildContainerCallCount()).To(BeZero())
-					Expect(compatibleWorkerNoCaches2.FindOrCreateBuildContainerCallCount()).To(BeZero())
-				})
+				Expect(workerA.LookupVolumeCallCount()).To(Equal(1))
+				Expect(workerB.LookupVolumeCallCount()).To(Equal(1))
+				Expect(workerC.LookupVolumeCallCount()).To(Equal(1))
 			})
 
-			Context("with compatible workers available, with none having any local caches", func() {
+			Context("when some workers fail to look it up", func() {
+				var (
+					disasterA error
+					disasterC error
+				)
+
 				BeforeEach(func() {
-					fakeProvider.RunningWorkersReturns([]Worker{
-						incompatibleWorker,
-						compatibleWorkerNoCaches1,
-						compatibleWorkerNoCaches2,
-					}, nil)
+					disasterA = errors.New("worker a fell over")
+					disasterC = errors.New("worker c fell over")
+
+					workerA.LookupVolumeReturns(nil, false, disasterA)
+					workerC.LookupVolumeReturns(nil, false, disasterC)
 				})
 
-				It("creates it on a random one of them", func() {
-					Expect(createErr).ToNot(HaveOccurred())
-					Expect(createdContainer).To(Equal(fakeContainer))
+				It("returns every worker's error", func() {
+					Expect(found).To(BeFalse())
+					Expect(lookupErr).To(HaveOccurred())
+					Expect(lookupErr.Error()).To(ContainSubstring("worker a fell over"))
+					Expect(lookupErr.Error()).To(ContainSubstring("worker c fell over"))
+				})
+			})
+		})
 
-					for i := 0; i < 100; i++ {
-						container, err := pool.FindOrCreateBuildContainer(
-							logger,
-							signals,
-							fakeImageFetchingDelegate,
-							42,
-							atc.PlanID("some-plan-id"),
-							metadata,
-							spec,
-							resourceTypes,
-						)
-						Expect(err).ToNot(HaveOccurred())
-						Expect(container).To(Equal(fakeContainer))
-					}
+		Context("when listing the workers fails", func() {
+			var disaster error
 
-					Expect(incompatibleWorker.FindOrCreateBuildContainerCallCount()).To(BeZero())
-					Expect(compatibleWorkerNoCaches1.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
-					Expect(compatibleWorkerNoCaches2.FindOrCreateBuildContainerCallCount()).ToNot(BeZero())
-				})
+			BeforeEach(func() {
+				disaster = errors.New("nope")
+				fakeProvider.RunningWorkersReturns(nil, disaster)
+			})
+
+			It("returns the error", func() {
+				Expect(lookupErr).To(Equal(disaster))
+				Expect(found).To(BeFalse())
 			})
 		})
 	})
 
-	Describe("FindOrCreateResourceCheckContainer", func() {
+	Describe("FindInitializedVolumeForResourceCache", func() {
 		var (
-			fakeImageFetchingDelegate *workerfakes.FakeImageFetchingDelegate
-
-			spec ContainerSpec
+			workerWithCache    *workerfakes.FakeWorker
+			workerWithoutCache *workerfakes.FakeWorker
 
-			fakeContainer *workerfakes.FakeContainer
+			fakeVolume    *workerfakes.FakeVolume
+			resourceCache *dbng.UsedResourceCache
 
-			createdContainer Container
-			createErr        error
-			resourceTypes    atc.VersionedResourceTypes
+			foundVolume Volume
+			found       bool
+			findErr     error
 		)
 
 		BeforeEach(func() {
-			fakeImageFetchingDelegate = new(workerfakes.FakeImageFetchingDelegate)
+			workerWithCache = new(workerfakes.FakeWorker)
+			workerWithoutCache = new(workerfakes.FakeWorker)
 
-			spec = ContainerSpec{
-				ImageSpec: ImageSpec{ResourceType: "some-type"},
-				TeamID:    4567,
-			}
+			fakeVolume = new(workerfakes.FakeVolume)
+			resourceCache = &dbng.UsedResourceCache{ID: 42}
 
-			resourceTypes = atc.VersionedResourceTypes{
-				{
-					ResourceType: atc.ResourceType{
-						Name:   "custom-type-b",
-						Type:   "custom-type-a",
-						Source: atc.Source{"some": "source"},
-					},
-					Version: atc.Version{"some": "version"},
-				},
-				{
-					ResourceType: atc.ResourceType{
-						Name:   "custom-type-a",
-						Type:   "some-resource",
-						Source: atc.Source{"some": "source"},
-					},
-					Version: atc.Version{"some": "version"},
-				},
-				{
-					ResourceType: atc.ResourceType{
-						Name:   "custom-type-c",
-						Type:   "custom-type-b",
-						Source: atc.Source{"some": "source"},
-					},
-					Version: atc.Version{"some": "version"},
-				},
-				{
-					ResourceType: atc.ResourceType{
-						Name:   "custom-type-d",
-						Type:   "custom-type-b",
-						Source: atc.Source{"some": "source"},
-					},
-					Version: atc.Version{"some": "version"},
-				},
-				{
-					ResourceType: atc.ResourceType{
-						Name:   "unknown-custom-type",
-						Type:   "unknown-base-type",
-						Source: atc.Source{"some": "source"},
-					},
-					Version: atc.Version{"some": "version"},
-				},
-			}
+			workerWithCache.FindInitializedVolumeForResourceCacheReturns(fakeVolume, true, nil)
+			workerWithoutCache.FindInitializedVolumeForResourceCacheReturns(nil, false, nil)
 		})
 
 		JustBeforeEach(func() {
-			createdContainer, createErr = pool.FindOrCreateResourceCheckContainer(
-				logger,
-				dbng.ForBuild(42),
-				make(chan os.Signal),
-				fakeImageFetchingDelegate,
-				dbng.ContainerMetadata{},
-				spec,
-				resourceTypes,
-				"some-type",
-				atc.Source{"some": "source"},
-			)
+			foundVolume, found, findErr = pool.FindInitializedVolumeForResourceCache(logger, resourceCache)
 		})
 
-		Context("when a worker is found with the container", func() {
-			var fakeWorker *workerfakes.FakeWorker
-
+		Context("when one worker has the cache volume and another doesn't", func() {
 			BeforeEach(func() {
-				fakeWorker = new(workerfakes.FakeWorker)
-				fakeProvider.FindWorkerForResourceCheckContainerReturns(fakeWorker, true, nil)
-				fakeWorker.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+				fakeProvider.RunningWorkersReturns([]Worker{workerWithoutCache, workerWithCache}, nil)
 			})
 
-			It("succeeds", func() {
-				Expect(createErr).NotTo(HaveOccurred())
+			It("returns the volume from the worker that has it", func() {
+				Expect(findErr).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(foundVolume).To(Equal(fakeVolume))
+
+				_, actualCache := workerWithCache.FindInitializedVolumeForResourceCacheArgsForCall(0)
+				Expect(actualCache).To(Equal(resourceCache))
 			})
+		})
 
-			It("returns the created container", func() {
-				Expect(createdContainer).To(Equal(fakeContainer))
+		Context("when no worker has the cache volume", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{workerWithoutCache}, nil)
 			})
 
-			It("'find-or-create's on the particular worker", func() {
-				Expect(fakeWorker.FindOrCreateResourceCheckContainerCallCount()).To(Equal(1))
+			It("returns not found", func() {
+				Expect(findErr).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
 
-				_, actualTeamID, actualResourceUser, actualResourceType, actualResourceSource, actualResourceTypes := fakeProvider.FindWorkerForResourceCheckContainerArgsForCall(0)
-				Expect(actualTeamID).To(Equal(4567))
-				Expect(actualResourceUser).To(Equal(dbng.ForBuild(42)))
-				Expect(actualResourceType).To(Equal("some-type"))
-				Expect(actualResourceSource).To(Equal(atc.Source{"some": "source"}))
-				Expect(actualResourceTypes).To(Equal(resourceTypes))
+		Context("when there are no workers", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+			})
+
+			It("returns ErrNoWorkers", func() {
+				Expect(findErr).To(Equal(ErrNoWorkers))
 			})
 		})
 
-		Context("when a worker is not found, and multiple are present", func() {
-			var (
-				workerA *workerfakes.FakeWorker
-				workerB *workerfakes.FakeWorker
-				workerC *workerfakes.FakeWorker
-			)
+		Context("when listing the workers fails", func() {
+			var disaster error
 
 			BeforeEach(func() {
-				fakeProvider.FindWorkerForResourceCheckContainerReturns(nil, false, nil)
-
-				workerA = new(workerfakes.FakeWorker)
-				workerB = new(workerfakes.FakeWorker)
-				workerC = new(workerfakes.FakeWorker)
+				disaster = errors.New("nope")
+				fakeProvider.RunningWorkersReturns(nil, disaster)
+			})
 
-				workerA.ActiveContainersReturns(3)
-				workerB.ActiveContainersReturns(2)
+			It("returns the error", func() {
+				Expect(findErr).To(Equal(disaster))
+			})
+		})
+	})
 
-				workerA.SatisfyingReturns(workerA, nil)
-				workerB.SatisfyingReturns(workerB, nil)
-				workerC.SatisfyingReturns(nil, errors.New("nope"))
+	Describe("CreateVolumeForResourceCache", func() {
+		var (
+			workerA *workerfakes.FakeWorker
+			workerB *workerfakes.FakeWorker
 
-				workerA.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
-				workerB.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
-				workerC.FindOrCreateResourceCheckContainerReturns(fakeContainer, nil)
+			spec          WorkerSpec
+			resourceTypes atc.VersionedResourceTypes
+			fakeVolume    *workerfakes.FakeVolume
+			volumeSpec    VolumeSpec
+			resourceCache *dbng.UsedResourceCache
 
-				fakeProvider.RunningWorkersReturns([]Worker{workerA, workerB, workerC}, nil)
-			})
+			createdVolume Volume
+			createErr     error
+		)
 
-			It("succeeds", func() {
-				Expect(createErr).NotTo(HaveOccurred())
-			})
+		BeforeEach(func() {
+			workerA = new(workerfakes.FakeWorker)
+			workerA.SatisfyingReturns(workerA, nil)
+			workerB = new(workerfakes.FakeWorker)
+			workerB.SatisfyingReturns(workerB, nil)
 
-			It("returns the created container", func() {
-				Expect(createdContainer).To(Equal(fakeContainer))
-			})
+			fakeVolume = new(workerfakes.FakeVolume)
+			workerA.CreateVolumeForResourceCacheReturns(fakeVolume, nil)
+			workerB.CreateVolumeForResourceCacheReturns(fakeVolume, nil)
 
-			It("checks that the workers satisfy the given spec", func() {
-				Expect(workerA.SatisfyingCallCount()).To(Equal(1))
-				_, actualSpec, actualResourceTypes := workerA.SatisfyingArgsForCall(0)
-				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
-				Expect(actualResourceTypes).To(Equal(resourceTypes))
+			spec = WorkerSpec{
+				ResourceType: "some-type",
+				Tags:         []string{"some-tag"},
+				TeamID:       4567,
+			}
+			resourceTypes = atc.VersionedResourceTypes{
+				{
+					ResourceType: atc.ResourceType{Name: "some-custom-type", Type: "some-type"},
+					Version:      atc.Version{"some": "version"},
+				},
+			}
+			volumeSpec = VolumeSpec{Privileged: true}
+			resourceCache = &dbng.UsedResourceCache{ID: 42}
+		})
 
-				Expect(workerB.SatisfyingCallCount()).To(Equal(1))
-				_, actualSpec, actualResourceTypes = workerB.SatisfyingArgsForCall(0)
-				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
-				Expect(actualResourceTypes).To(Equal(resourceTypes))
+		JustBeforeEach(func() {
+			createdVolume, createErr = pool.CreateVolumeForResourceCache(logger, spec, resourceTypes, volumeSpec, resourceCache)
+		})
 
-				Expect(workerC.SatisfyingCallCount()).To(Equal(1))
-				_, actualSpec, actualResourceTypes = workerC.SatisfyingArgsForCall(0)
-				Expect(actualSpec).To(Equal(spec.WorkerSpec()))
-				Expect(actualResourceTypes).To(Equal(resourceTypes))
+		Context("with multiple workers", func() {
+			BeforeEach(func() {
+				fakeProvider.RunningWorkersReturns([]Worker{workerA, workerB}, nil)
 			})
 
-			It("creates using a random worker", func() {
-				for i := 1; i < 100; i++ { // account for initial create in JustBefore
-					createdContainer, createErr := pool.FindOrCreateResourceCheckContainer(
-						logger,
-						dbng.ForBuild(42),
-						make(chan os.Signal),
-						fakeImageFetchingDelegate,
-						dbng.ContainerMetadata{},
-						spec,
-						resourceTypes,
-						"some-type",
-						atc.Source{"some": "source"},
-					)
-					Expect(createErr).NotTo(HaveOccurred())
-					Expect(createdContainer).To(Equal(fakeContainer))
-				}
+			It("creates the volume on one of them", func() {
+				Expect(createErr).NotTo(HaveOccurred())
+				Expect(createdVolume).To(Equal(fakeVolume))
 
-				Expect(workerA.FindOrCreateResourceCheckContainerCallCount()).To(BeNumerically("~", workerB.FindOrCreateResourceCheckContainerCallCount(), 50))
-				Expect(workerC.FindOrCreateResourceCheckContainerCallCount()).To(BeZero())
+				Expect(workerA.CreateVolumeForResourceCacheCallCount() +
+					workerB.CreateVolumeForResourceCacheCallCount()).To(Equal(1))
 			})
 
-			Context("when creating the container fails", func() {
-				disaster := errors.New("nope")
+			It("checks each worker against the spec", func() {
+				_, actualSpec, actualResourceTypes := workerA.SatisfyingArgsForCall(0)
+				Expect(actualSpec).To(Equal(spec))
+				Expect(actualResourceTypes).To(Equal(resourceTypes))
+			})
 
+			Context("when only one of them satisfies the spec", func() {
 				BeforeEach(func() {
-					workerA.FindOrCreateResourceCheckContainerReturns(nil, disaster)
-					workerB.FindOrCreateResourceCheckContainerReturns(nil, disaster)
+					workerA.SatisfyingReturns(nil, ErrMismatchedTags)
 				})
 
-				It("returns the error", func() {
-					Expect(createErr).To(Equal(disaster))
+				It("creates the volume on the satisfying worker", func() {
+					Expect(createErr).NotTo(HaveOccurred())
+					Expect(workerA.CreateVolumeForResourceCacheCallCount()).To(BeZero())
+					Expect(workerB.CreateVolumeForResourceCacheCallCount()).To(Equal(1))
 				})
 			})
 
-			Context("when no workers satisfy the spec", func() {
+			Context("when none of them satisfy the spec", func() {
 				BeforeEach(func() {
-					workerA.SatisfyingReturns(nil, errors.New("nope"))
-					workerB.SatisfyingReturns(nil, errors.New("nope"))
-					workerC.SatisfyingReturns(nil, errors.New("nope"))
+					workerA.SatisfyingReturns(nil, ErrMismatchedTags)
+					workerB.SatisfyingReturns(nil, ErrMismatchedTags)
 				})
 
 				It("returns a NoCompatibleWorkersError", func() {
-					Expect(createErr).To(Equal(NoCompatibleWorkersError{
-						Spec:    spec.WorkerSpec(),
-						Workers: []Worker{workerA, workerB, workerC},
-					}))
-
+					Expect(createErr).To(BeAssignableToTypeOf(NoCompatibleWorkersError{}))
+					Expect(workerA.CreateVolumeForResourceCacheCallCount()).To(BeZero())
+					Expect(workerB.CreateVolumeForResourceCacheCallCount()).To(BeZero())
 				})
 			})
 		})
 
-		Context("with no workers", func() {
+		Context("with one worker", func() {
 			BeforeEach(func() {
-				fakeProvider.RunningWorkersReturns([]Worker{}, nil)
+				fakeProvider.RunningWorkersReturns([]Worker{workerA}, nil)
 			})
 
-			It("returns ErrNoWorkers", func() {
-				Expect(createErr).To(Equal(ErrNoWorkers))
+			It("passes the specs and cache along", func() {
+				Expect(workerA.CreateVolumeForResourceCacheCallCount()).To(Equal(1))
+
+				_, actualSpec, actualResourceTypes, actualVolumeSpec, actualCache := workerA.CreateVolumeForResourceCacheArgsForCall(0)
+				Expect(actualSpec).To(Equal(spec))
+				Expect(actualResourceTypes).To(Equal(resourceTypes))
+				Expect(actualVolumeSpec).To(Equal(volumeSpec))
+				Expect(actualCache).To(Equal(resourceCache))
 			})
-		})
 
-		Context("when getting the workers fails", func() {
-			disaster := errors.New("nope")
+			Context("when creating the volume fails", func() {
+				var disaster error
+
+				BeforeEach(func() {
+					disaster = errors.New("nope")
+					workerA.CreateVolumeForResourceCacheReturns(nil, disaster)
+				})
+
+				It("returns the error", func() {
+					Expect(createErr).To(Equal(disaster))
+				})
+			})
+		})
 
+		Context("when there are no workers", func() {
 			BeforeEach(func() {
-				fakeProvider.RunningWorkersReturns(nil, disaster)
+				fakeProvider.RunningWorkersReturns([]Worker{}, nil)
 			})
 
-			It("returns the error", func() {
-				Expect(createErr).To(Equal(disaster))
+			It("returns ErrNoWorkers", func() {
+				Expect(createErr).To(Equal(ErrNoWorkers))
 			})
 		})
 	})
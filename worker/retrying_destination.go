@@ -0,0 +1,138 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/concourse/retryhttp"
+)
+
+// StreamInRetryPolicy configures how a RetryingDestination retries.
+type StreamInRetryPolicy struct {
+	// BufferLimit is the size up to which streams are held in memory so
+	// that they can be replayed.
+	BufferLimit int64
+
+	// Attempts bounds how many times a stream is streamed in.
+	Attempts int
+
+	// Backoff is the delay before the first retry. It doubles before each
+	// retry after that.
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between retries. Zero leaves it uncapped.
+	MaxBackoff time.Duration
+}
+
+// ErrStreamInInterrupted is returned when a RetryingDestination is signalled
+// while waiting to retry.
+var ErrStreamInInterrupted = errors.New("interrupted while retrying stream-in")
+
+// ReopenableArtifactSource is an ArtifactSource that can open a fresh tar
+// stream of its entire contents on demand. This allows a stream-in that
+// failed part-way through to be retried from the start, no matter how large
+// the artifact is.
+type ReopenableArtifactSource interface {
+	ArtifactSource
+
+	StreamOut() (io.ReadCloser, error)
+}
+
+// RetryingDestination wraps an ArtifactDestination, retrying StreamIn up to
+// a bounded number of attempts, with backoff, when it fails with a transient
+// error. A signal received while waiting to retry gives up with
+// ErrStreamInInterrupted.
+//
+// Streams that fit within the buffer limit are held in memory so they can be
+// replayed. Larger streams are passed through as-is and can only be retried
+// by re-opening their source; see StreamFrom.
+type RetryingDestination struct {
+	destination ArtifactDestination
+	retryer     retryhttp.Retryer
+	policy      StreamInRetryPolicy
+	clock       clock.Clock
+	cancel      <-chan os.Signal
+}
+
+func NewRetryingDestination(
+	destination ArtifactDestination,
+	retryer retryhttp.Retryer,
+	policy StreamInRetryPolicy,
+	clock clock.Clock,
+	cancel <-chan os.Signal,
+) *RetryingDestination {
+	return &RetryingDestination{
+		destination: destination,
+		retryer:     retryer,
+		policy:      policy,
+		clock:       clock,
+		cancel:      cancel,
+	}
+}
+
+// StreamFrom streams the source into the destination. If the source can be
+// re-opened, each attempt streams from a fresh reader; otherwise the source
+// streams into the destination once, relying on StreamIn's buffering.
+func (dest *RetryingDestination) StreamFrom(source ArtifactSource) error {
+	reopenable, ok := source.(ReopenableArtifactSource)
+	if !ok {
+		return source.StreamTo(dest)
+	}
+
+	return dest.retry(func() error {
+		out, err := reopenable.StreamOut()
+		if err != nil {
+			return err
+		}
+
+		defer out.Close()
+
+		return dest.destination.StreamIn(".", out)
+	})
+}
+
+func (dest *RetryingDestination) StreamIn(path string, src io.Reader) error {
+	buffer := new(bytes.Buffer)
+
+	_, err := io.CopyN(buffer, src, dest.policy.BufferLimit+1)
+	if err == io.EOF {
+		return dest.retry(func() error {
+			return dest.destination.StreamIn(path, bytes.NewReader(buffer.Bytes()))
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return dest.destination.StreamIn(path, io.MultiReader(buffer, src))
+}
+
+func (dest *RetryingDestination) retry(streamIn func() error) error {
+	delay := dest.policy.Backoff
+
+	for attempt := 1; ; attempt++ {
+		err := streamIn()
+		if err == nil || !dest.retryer.IsRetryable(err) || attempt >= dest.policy.Attempts {
+			return err
+		}
+
+		timer := dest.clock.NewTimer(delay)
+
+		select {
+		case <-timer.C():
+		case <-dest.cancel:
+			timer.Stop()
+			return ErrStreamInInterrupted
+		}
+
+		delay *= 2
+		if dest.policy.MaxBackoff > 0 && delay > dest.policy.MaxBackoff {
+			delay = dest.policy.MaxBackoff
+		}
+	}
+}
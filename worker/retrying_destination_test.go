@@ -0,0 +1,258 @@
+package worker_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	. "github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+	"github.com/concourse/retryhttp/retryhttpfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeReopenableSource struct {
+	*workerfakes.FakeArtifactSource
+
+	content string
+	opened  int
+}
+
+func (source *fakeReopenableSource) StreamOut() (io.ReadCloser, error) {
+	source.opened++
+	return ioutil.NopCloser(bytes.NewBufferString(source.content)), nil
+}
+
+var _ = Describe("RetryingDestination", func() {
+	var (
+		fakeDestination *workerfakes.FakeArtifactDestination
+		fakeRetryer     *retryhttpfakes.FakeRetryer
+		fakeClock       *fakeclock.FakeClock
+		policy          StreamInRetryPolicy
+		cancel          chan os.Signal
+
+		streamedIn []string
+		transient  error
+
+		destination *RetryingDestination
+	)
+
+	BeforeEach(func() {
+		fakeDestination = new(workerfakes.FakeArtifactDestination)
+		fakeRetryer = new(retryhttpfakes.FakeRetryer)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(0, 123))
+
+		transient = errors.New("connection reset by peer")
+		fakeRetryer.IsRetryableStub = func(err error) bool {
+			return err == transient
+		}
+
+		streamedIn = nil
+		fakeDestination.StreamInStub = func(path string, src io.Reader) error {
+			defer GinkgoRecover()
+
+			content, err := ioutil.ReadAll(src)
+			Expect(err).NotTo(HaveOccurred())
+
+			streamedIn = append(streamedIn, string(content))
+
+			if len(streamedIn) == 1 {
+				return transient
+			}
+
+			return nil
+		}
+
+		policy = StreamInRetryPolicy{
+			BufferLimit: 16,
+			Attempts:    3,
+			Backoff:     time.Second,
+		}
+
+		cancel = make(chan os.Signal, 1)
+	})
+
+	JustBeforeEach(func() {
+		destination = NewRetryingDestination(
+			fakeDestination,
+			fakeRetryer,
+			policy,
+			fakeClock,
+			cancel,
+		)
+	})
+
+	streamIn := func(src io.Reader) <-chan error {
+		errs := make(chan error, 1)
+		go func() {
+			errs <- destination.StreamIn("some-path", src)
+		}()
+
+		return errs
+	}
+
+	Describe("StreamIn", func() {
+		Context("when the stream fits within the buffer limit", func() {
+			It("replays the buffered stream after a transient failure", func() {
+				errs := streamIn(bytes.NewBufferString("small-stream"))
+
+				fakeClock.WaitForWatcherAndIncrement(time.Second)
+				Eventually(errs).Should(Receive(BeNil()))
+
+				Expect(fakeDestination.StreamInCallCount()).To(Equal(2))
+				Expect(streamedIn).To(Equal([]string{"small-stream", "small-stream"}))
+
+				path, _ := fakeDestination.StreamInArgsForCall(1)
+				Expect(path).To(Equal("some-path"))
+			})
+
+			It("waits for the backoff before retrying", func() {
+				errs := streamIn(bytes.NewBufferString("small-stream"))
+
+				fakeClock.WaitForWatcherAndIncrement(time.Second - time.Millisecond)
+				Consistently(fakeDestination.StreamInCallCount).Should(Equal(1))
+
+				fakeClock.Increment(time.Millisecond)
+				Eventually(errs).Should(Receive(BeNil()))
+				Expect(fakeDestination.StreamInCallCount()).To(Equal(2))
+			})
+
+			Context("when the stream keeps failing transiently", func() {
+				BeforeEach(func() {
+					fakeDestination.StreamInReturns(transient)
+					fakeDestination.StreamInStub = nil
+				})
+
+				It("doubles the backoff and gives up after the configured number of attempts", func() {
+					errs := streamIn(bytes.NewBufferString("small-stream"))
+
+					fakeClock.WaitForWatcherAndIncrement(time.Second)
+					Eventually(fakeDestination.StreamInCallCount).Should(Equal(2))
+
+					fakeClock.WaitForWatcherAndIncrement(time.Second)
+					Consistently(fakeDestination.StreamInCallCount).Should(Equal(2))
+
+					fakeClock.Increment(time.Second)
+					Eventually(errs).Should(Receive(Equal(transient)))
+					Expect(fakeDestination.StreamInCallCount()).To(Equal(3))
+				})
+
+				Context("when the backoff is capped", func() {
+					BeforeEach(func() {
+						policy.Attempts = 4
+						policy.MaxBackoff = 1500 * time.Millisecond
+					})
+
+					It("does not wait longer than the cap between retries", func() {
+						errs := streamIn(bytes.NewBufferString("small-stream"))
+
+						fakeClock.WaitForWatcherAndIncrement(time.Second)
+						Eventually(fakeDestination.StreamInCallCount).Should(Equal(2))
+
+						fakeClock.WaitForWatcherAndIncrement(1500 * time.Millisecond)
+						Eventually(fakeDestination.StreamInCallCount).Should(Equal(3))
+
+						fakeClock.WaitForWatcherAndIncrement(1500 * time.Millisecond)
+						Eventually(errs).Should(Receive(Equal(transient)))
+						Expect(fakeDestination.StreamInCallCount()).To(Equal(4))
+					})
+				})
+
+				Context("when signalled while waiting to retry", func() {
+					It("gives up without retrying", func() {
+						errs := streamIn(bytes.NewBufferString("small-stream"))
+
+						Eventually(fakeClock.WatcherCount).Should(Equal(1))
+						cancel <- os.Interrupt
+
+						Eventually(errs).Should(Receive(Equal(ErrStreamInInterrupted)))
+						Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			Context("when the stream fails with a non-transient error", func() {
+				disaster := errors.New("no space left on device")
+
+				BeforeEach(func() {
+					fakeDestination.StreamInReturns(disaster)
+					fakeDestination.StreamInStub = nil
+				})
+
+				It("does not retry", func() {
+					err := destination.StreamIn("some-path", bytes.NewBufferString("small-stream"))
+					Expect(err).To(Equal(disaster))
+
+					Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when the stream exceeds the buffer limit", func() {
+			It("streams it through once without retrying", func() {
+				err := destination.StreamIn("some-path", bytes.NewBufferString("a-stream-too-large-to-buffer"))
+				Expect(err).To(Equal(transient))
+
+				Expect(fakeDestination.StreamInCallCount()).To(Equal(1))
+				Expect(streamedIn).To(Equal([]string{"a-stream-too-large-to-buffer"}))
+			})
+		})
+	})
+
+	Describe("StreamFrom", func() {
+		Context("when the source can be re-opened", func() {
+			var source *fakeReopenableSource
+
+			BeforeEach(func() {
+				source = &fakeReopenableSource{
+					FakeArtifactSource: new(workerfakes.FakeArtifactSource),
+					content:            "a-stream-too-large-to-buffer",
+				}
+			})
+
+			It("re-opens the source to retry after a transient failure", func() {
+				errs := make(chan error, 1)
+				go func() {
+					errs <- destination.StreamFrom(source)
+				}()
+
+				fakeClock.WaitForWatcherAndIncrement(time.Second)
+				Eventually(errs).Should(Receive(BeNil()))
+
+				Expect(source.opened).To(Equal(2))
+				Expect(streamedIn).To(Equal([]string{"a-stream-too-large-to-buffer", "a-stream-too-large-to-buffer"}))
+				Expect(source.StreamToCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the source cannot be re-opened", func() {
+			var fakeSource *workerfakes.FakeArtifactSource
+
+			BeforeEach(func() {
+				fakeSource = new(workerfakes.FakeArtifactSource)
+				fakeSource.StreamToStub = func(dest ArtifactDestination) error {
+					return dest.StreamIn(".", bytes.NewBufferString("small-stream"))
+				}
+			})
+
+			It("streams the source through the buffering destination", func() {
+				errs := make(chan error, 1)
+				go func() {
+					errs <- destination.StreamFrom(fakeSource)
+				}()
+
+				fakeClock.WaitForWatcherAndIncrement(time.Second)
+				Eventually(errs).Should(Receive(BeNil()))
+
+				Expect(fakeSource.StreamToCallCount()).To(Equal(1))
+				Expect(streamedIn).To(Equal([]string{"small-stream", "small-stream"}))
+			})
+		})
+	})
+})
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . SelectionConstraint
+
+// SelectionConstraint narrows down the workers satisfying a spec based on
+// signals external to the spec, e.g. the time of day or the cost of running
+// on each worker. It is applied before the SelectionStrategy chooses among
+// them.
+//
+// Implementations may filter or reorder the workers, but should return them
+// unchanged rather than return none at all.
+//
+// Workers tagged only with the constraint's Tags are eligible for untagged
+// specs, so that the constraint rather than tag matching decides when they
+// are used. Workers with other tags still only run work asking for them.
+type SelectionConstraint interface {
+	Constrain(logger lager.Logger, workers []Worker) []Worker
+	Tags() []string
+}
+
+// TimeWindow is a daily window of time, given as offsets from midnight. If
+// End is before Start the window wraps around midnight.
+type TimeWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseTimeWindow parses a window of the form "HH:MM-HH:MM", e.g.
+// "22:00-06:00".
+func ParseTimeWindow(window string) (TimeWindow, error) {
+	var startHour, startMinute, endHour, endMinute int
+
+	_, err := fmt.Sscanf(window, "%d:%d-%d:%d", &startHour, &startMinute, &endHour, &endMinute)
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("invalid time window '%s': must be of the form HH:MM-HH:MM", window)
+	}
+
+	for _, hour := range []int{startHour, endHour} {
+		if hour < 0 || hour > 23 {
+			return TimeWindow{}, fmt.Errorf("invalid time window '%s': hour out of range", window)
+		}
+	}
+
+	for _, minute := range []int{startMinute, endMinute} {
+		if minute < 0 || minute > 59 {
+			return TimeWindow{}, fmt.Errorf("invalid time window '%s': minute out of range", window)
+		}
+	}
+
+	return TimeWindow{
+		Start: time.Duration(startHour)*time.Hour + time.Duration(startMinute)*time.Minute,
+		End:   time.Duration(endHour)*time.Hour + time.Duration(endMinute)*time.Minute,
+	}, nil
+}
+
+// Contains reports whether the time of day of t falls within the window.
+func (window TimeWindow) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+
+	if window.Start <= window.End {
+		return offset >= window.Start && offset < window.End
+	}
+
+	return offset >= window.Start || offset < window.End
+}
+
+type timeWindowConstraint struct {
+	clock   clock.Clock
+	tag     string
+	windows []TimeWindow
+}
+
+// NewTimeWindowConstraint returns a SelectionConstraint that, during any of
+// the given windows, prefers workers with the given tag, e.g. workers running
+// on cheaper capacity only available off-peak. Outside of the windows it
+// avoids those workers instead. Either way, if no worker is left to prefer,
+// all of the workers are returned.
+func NewTimeWindowConstraint(clock clock.Clock, tag string, windows []TimeWindow) SelectionConstraint {
+	return &timeWindowConstraint{
+		clock:   clock,
+		tag:     tag,
+		windows: windows,
+	}
+}
+
+func (constraint *timeWindowConstraint) Tags() []string {
+	return []string{constraint.tag}
+}
+
+func (constraint *timeWindowConstraint) Constrain(logger lager.Logger, workers []Worker) []Worker {
+	now := constraint.clock.Now()
+
+	inWindow := false
+	for _, window := range constraint.windows {
+		if window.Contains(now) {
+			inWindow = true
+			break
+		}
+	}
+
+	preferred := []Worker{}
+	for _, worker := range workers {
+		if hasTag(worker, constraint.tag) == inWindow {
+			preferred = append(preferred, worker)
+		}
+	}
+
+	if len(preferred) == 0 {
+		logger.Debug("no-preferred-workers", lager.Data{"tag": constraint.tag, "in-window": inWindow})
+		return workers
+	}
+
+	return preferred
+}
+
+func hasTag(worker Worker, tag string) bool {
+	for _, t := range worker.Tags() {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package worker_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/concourse/atc"
+	. "github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelectionConstraint", func() {
+	Describe("ParseTimeWindow", func() {
+		It("parses the start and end as offsets from midnight", func() {
+			window, err := ParseTimeWindow("22:30-06:00")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(window).To(Equal(TimeWindow{
+				Start: 22*time.Hour + 30*time.Minute,
+				End:   6 * time.Hour,
+			}))
+		})
+
+		It("rejects malformed windows", func() {
+			_, err := ParseTimeWindow("evenings")
+			Expect(err).To(MatchError("invalid time window 'evenings': must be of the form HH:MM-HH:MM"))
+		})
+
+		It("rejects out of range times", func() {
+			_, err := ParseTimeWindow("22:00-24:00")
+			Expect(err).To(MatchError("invalid time window '22:00-24:00': hour out of range"))
+		})
+	})
+
+	Describe("TimeWindowConstraint", func() {
+		var (
+			fakeClock *fakeclock.FakeClock
+
+			spotWorker     *workerfakes.FakeWorker
+			onDemandWorker *workerfakes.FakeWorker
+
+			workers    []Worker
+			constraint SelectionConstraint
+		)
+
+		at := func(hour int, minute int) time.Time {
+			return time.Date(2017, time.March, 1, hour, minute, 0, 0, time.UTC)
+		}
+
+		BeforeEach(func() {
+			fakeClock = fakeclock.NewFakeClock(at(12, 0))
+
+			spotWorker = new(workerfakes.FakeWorker)
+			spotWorker.TagsReturns(atc.Tags{"spot"})
+			onDemandWorker = new(workerfakes.FakeWorker)
+
+			workers = []Worker{spotWorker, onDemandWorker}
+
+			constraint = NewTimeWindowConstraint(fakeClock, "spot", []TimeWindow{
+				{Start: 22 * time.Hour, End: 6 * time.Hour},
+			})
+		})
+
+		constrain := func() []Worker {
+			return constraint.Constrain(lagertest.NewTestLogger("test"), workers)
+		}
+
+		It("selects workers by the given tag", func() {
+			Expect(constraint.Tags()).To(Equal([]string{"spot"}))
+		})
+
+		Context("within a window", func() {
+			BeforeEach(func() {
+				fakeClock.Increment(11 * time.Hour)
+			})
+
+			It("prefers the tagged workers", func() {
+				Expect(constrain()).To(Equal([]Worker{spotWorker}))
+			})
+
+			Context("after the window wraps around midnight", func() {
+				BeforeEach(func() {
+					fakeClock.Increment(6 * time.Hour)
+				})
+
+				It("still prefers the tagged workers", func() {
+					Expect(constrain()).To(Equal([]Worker{spotWorker}))
+				})
+			})
+
+			Context("when none of the workers are tagged", func() {
+				BeforeEach(func() {
+					workers = []Worker{onDemandWorker}
+				})
+
+				It("returns all of the workers", func() {
+					Expect(constrain()).To(Equal([]Worker{onDemandWorker}))
+				})
+			})
+		})
+
+		Context("outside of all windows", func() {
+			It("avoids the tagged workers", func() {
+				Expect(constrain()).To(Equal([]Worker{onDemandWorker}))
+			})
+
+			Context("at the end of a window", func() {
+				BeforeEach(func() {
+					fakeClock.Increment(18 * time.Hour)
+				})
+
+				It("avoids the tagged workers", func() {
+					Expect(constrain()).To(Equal([]Worker{onDemandWorker}))
+				})
+			})
+
+			Context("when all of the workers are tagged", func() {
+				BeforeEach(func() {
+					workers = []Worker{spotWorker}
+				})
+
+				It("returns all of the workers", func() {
+					Expect(constrain()).To(Equal([]Worker{spotWorker}))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,103 @@
+package worker
+
+import "math/rand"
+
+//go:generate counterfeiter . SelectionStrategy
+
+// SelectionStrategy chooses which of a set of compatible workers a container
+// should be placed on.
+type SelectionStrategy interface {
+	Choose(workers []Worker) Worker
+}
+
+type randomStrategy struct {
+	rand *rand.Rand
+}
+
+// NewRandomStrategy returns a SelectionStrategy that chooses uniformly among
+// the given workers.
+func NewRandomStrategy(source rand.Source) SelectionStrategy {
+	return &randomStrategy{
+		rand: rand.New(source),
+	}
+}
+
+func (strategy *randomStrategy) Choose(workers []Worker) Worker {
+	return workers[strategy.rand.Intn(len(workers))]
+}
+
+type capacityWeightedStrategy struct {
+	maxContainers int
+	rand          *rand.Rand
+}
+
+// NewCapacityWeightedStrategy returns a SelectionStrategy that chooses
+// workers at random, weighted by how many more containers each can run before
+// reaching maxContainers. If none of the workers have capacity left, it
+// chooses uniformly among them.
+func NewCapacityWeightedStrategy(maxContainers int, source rand.Source) SelectionStrategy {
+	return &capacityWeightedStrategy{
+		maxContainers: maxContainers,
+		rand:          rand.New(source),
+	}
+}
+
+func (strategy *capacityWeightedStrategy) Choose(workers []Worker) Worker {
+	weights := make([]int, len(workers))
+	totalWeight := 0
+
+	for i, worker := range workers {
+		remaining := strategy.maxContainers - worker.ActiveContainers()
+		if remaining > 0 {
+			weights[i] = remaining
+			totalWeight += remaining
+		}
+	}
+
+	if totalWeight == 0 {
+		return workers[strategy.rand.Intn(len(workers))]
+	}
+
+	choice := strategy.rand.Intn(totalWeight)
+	for i, weight := range weights {
+		if choice < weight {
+			return workers[i]
+		}
+
+		choice -= weight
+	}
+
+	return workers[len(workers)-1]
+}
+
+type fewestContainersStrategy struct {
+	rand *rand.Rand
+}
+
+// NewFewestContainersStrategy returns a SelectionStrategy that chooses the
+// worker running the fewest containers. Ties are broken uniformly at random,
+// so that idle workers share new containers between them.
+func NewFewestContainersStrategy(source rand.Source) SelectionStrategy {
+	return &fewestContainersStrategy{
+		rand: rand.New(source),
+	}
+}
+
+func (strategy *fewestContainersStrategy) Choose(workers []Worker) Worker {
+	var fewest []Worker
+	fewestContainers := 0
+
+	for _, worker := range workers {
+		containers := worker.ActiveContainers()
+
+		switch {
+		case len(fewest) == 0 || containers < fewestContainers:
+			fewest = []Worker{worker}
+			fewestContainers = containers
+		case containers == fewestContainers:
+			fewest = append(fewest, worker)
+		}
+	}
+
+	return fewest[strategy.rand.Intn(len(fewest))]
+}
@@ -0,0 +1,139 @@
+package worker_test
+
+import (
+	"math/rand"
+
+	. "github.com/concourse/atc/worker"
+	"github.com/concourse/atc/worker/workerfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelectionStrategy", func() {
+	var (
+		workerA *workerfakes.FakeWorker
+		workerB *workerfakes.FakeWorker
+		workerC *workerfakes.FakeWorker
+
+		workers  []Worker
+		strategy SelectionStrategy
+	)
+
+	BeforeEach(func() {
+		workerA = new(workerfakes.FakeWorker)
+		workerB = new(workerfakes.FakeWorker)
+		workerC = new(workerfakes.FakeWorker)
+
+		workers = []Worker{workerA, workerB, workerC}
+	})
+
+	chooseMany := func(times int) map[Worker]int {
+		chosenCount := map[Worker]int{workerA: 0, workerB: 0, workerC: 0}
+		for i := 0; i < times; i++ {
+			chosenCount[strategy.Choose(workers)]++
+		}
+
+		return chosenCount
+	}
+
+	Describe("RandomStrategy", func() {
+		BeforeEach(func() {
+			strategy = NewRandomStrategy(rand.NewSource(42))
+
+			workerA.ActiveContainersReturns(0)
+			workerB.ActiveContainersReturns(150)
+			workerC.ActiveContainersReturns(240)
+		})
+
+		It("chooses each worker with equal probability", func() {
+			chosenCount := chooseMany(3000)
+			Expect(chosenCount[workerA]).To(BeNumerically("~", 1000, 100))
+			Expect(chosenCount[workerB]).To(BeNumerically("~", 1000, 100))
+			Expect(chosenCount[workerC]).To(BeNumerically("~", 1000, 100))
+		})
+	})
+
+	Describe("CapacityWeightedStrategy", func() {
+		BeforeEach(func() {
+			strategy = NewCapacityWeightedStrategy(250, rand.NewSource(42))
+		})
+
+		Context("when the workers have different amounts of free capacity", func() {
+			BeforeEach(func() {
+				workerA.ActiveContainersReturns(0)
+				workerB.ActiveContainersReturns(150)
+				workerC.ActiveContainersReturns(240)
+			})
+
+			It("chooses workers in proportion to their remaining capacity", func() {
+				chosenCount := chooseMany(3600)
+				Expect(chosenCount[workerA]).To(BeNumerically("~", 2500, 150))
+				Expect(chosenCount[workerB]).To(BeNumerically("~", 1000, 100))
+				Expect(chosenCount[workerC]).To(BeNumerically("~", 100, 50))
+			})
+		})
+
+		Context("when a worker is at or over capacity", func() {
+			BeforeEach(func() {
+				workerA.ActiveContainersReturns(100)
+				workerB.ActiveContainersReturns(250)
+				workerC.ActiveContainersReturns(300)
+			})
+
+			It("never chooses it", func() {
+				chosenCount := chooseMany(100)
+				Expect(chosenCount[workerA]).To(Equal(100))
+			})
+		})
+
+		Context("when every worker is at capacity", func() {
+			BeforeEach(func() {
+				workerA.ActiveContainersReturns(250)
+				workerB.ActiveContainersReturns(250)
+				workerC.ActiveContainersReturns(250)
+			})
+
+			It("chooses each worker with equal probability", func() {
+				chosenCount := chooseMany(3000)
+				Expect(chosenCount[workerA]).To(BeNumerically("~", 1000, 100))
+				Expect(chosenCount[workerB]).To(BeNumerically("~", 1000, 100))
+				Expect(chosenCount[workerC]).To(BeNumerically("~", 1000, 100))
+			})
+		})
+	})
+
+	Describe("FewestContainersStrategy", func() {
+		BeforeEach(func() {
+			strategy = NewFewestContainersStrategy(rand.NewSource(42))
+		})
+
+		Context("when one worker has the fewest containers", func() {
+			BeforeEach(func() {
+				workerA.ActiveContainersReturns(20)
+				workerB.ActiveContainersReturns(5)
+				workerC.ActiveContainersReturns(10)
+			})
+
+			It("always chooses it", func() {
+				chosenCount := chooseMany(100)
+				Expect(chosenCount[workerB]).To(Equal(100))
+			})
+		})
+
+		Context("when several workers tie for the fewest containers", func() {
+			BeforeEach(func() {
+				workerA.ActiveContainersReturns(5)
+				workerB.ActiveContainersReturns(20)
+				workerC.ActiveContainersReturns(5)
+			})
+
+			It("chooses each of them with equal probability", func() {
+				chosenCount := chooseMany(2000)
+				Expect(chosenCount[workerA]).To(BeNumerically("~", 1000, 100))
+				Expect(chosenCount[workerB]).To(BeZero())
+				Expect(chosenCount[workerC]).To(BeNumerically("~", 1000, 100))
+			})
+		})
+	})
+})
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// streamProgressInterval is how often progress is reported while an input is
+// being streamed into a container.
+const streamProgressInterval = time.Second
+
+// progressDestination counts the bytes streamed into an ArtifactDestination,
+// periodically writing how far along the stream is.
+type progressDestination struct {
+	destination ArtifactDestination
+	name        ArtifactName
+	writer      io.Writer
+	clock       clock.Clock
+
+	transferred int64
+}
+
+func newProgressDestination(
+	destination ArtifactDestination,
+	name ArtifactName,
+	writer io.Writer,
+	clock clock.Clock,
+) *progressDestination {
+	return &progressDestination{
+		destination: destination,
+		name:        name,
+		writer:      writer,
+		clock:       clock,
+	}
+}
+
+// StreamIn streams src into the destination, reporting its progress until
+// the destination is done with it. Errors are returned as-is. Each call
+// counts from zero, so a retried stream is reported from the start.
+func (dest *progressDestination) StreamIn(path string, src io.Reader) error {
+	atomic.StoreInt64(&dest.transferred, 0)
+
+	done := make(chan struct{})
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		dest.report(done)
+	}()
+
+	err := dest.destination.StreamIn(path, &countingReader{
+		reader: src,
+		count:  &dest.transferred,
+	})
+
+	close(done)
+	wg.Wait()
+
+	return err
+}
+
+func (dest *progressDestination) report(done <-chan struct{}) {
+	started := dest.clock.Now()
+
+	ticker := dest.clock.NewTicker(streamProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			fmt.Fprintf(
+				dest.writer,
+				"streaming input %s: %s transferred (%s elapsed)\n",
+				dest.name,
+				formatBytes(atomic.LoadInt64(&dest.transferred)),
+				dest.clock.Since(started)/time.Second*time.Second,
+			)
+
+		case <-done:
+			return
+		}
+	}
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 	"time"
 
 	"code.cloudfoundry.org/clock"
+	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
@@ -34,9 +36,21 @@ const ephemeralPropertyName = "concourse:ephemeral"
 const volumePropertyName = "concourse:volumes"
 const volumeMountsPropertyName = "concourse:volume-mounts"
 const userPropertyName = "user"
+
+// The container properties recording how a container was created, so that
+// it can be recreated on another worker when its worker is evacuated.
+const privilegedPropertyName = "concourse:privileged"
+const envPropertyName = "concourse:env"
+const dirPropertyName = "concourse:dir"
 const RawRootFSScheme = "raw"
 const ImageMetadataFile = "metadata.json"
 
+// The process ID and container properties used by task steps to find their
+// process again after e.g. an ATC restart.
+const TaskProcessID = "task"
+const TaskProcessPropertyName = "concourse:task-process"
+const TaskExitStatusPropertyName = "concourse:exit-status"
+
 //go:generate counterfeiter . Worker
 
 type Worker interface {
@@ -46,6 +60,7 @@ type Worker interface {
 
 	Description() string
 	Name() string
+	Platform() string
 	ResourceTypes() []atc.WorkerResourceType
 	Tags() atc.Tags
 	Uptime() time.Duration
@@ -152,7 +167,7 @@ func (worker *gardenWorker) FindResourceTypeByPath(path string) (atc.WorkerResou
 	return atc.WorkerResourceType{}, false
 }
 
-func (worker *gardenWorker) CreateVolumeForResourceCache(logger lager.Logger, volumeSpec VolumeSpec, resourceCache *dbng.UsedResourceCache) (Volume, error) {
+func (worker *gardenWorker) CreateVolumeForResourceCache(logger lager.Logger, spec WorkerSpec, resourceTypes atc.VersionedResourceTypes, volumeSpec VolumeSpec, resourceCache *dbng.UsedResourceCache) (Volume, error) {
 	return worker.volumeClient.CreateVolumeForResourceCache(logger, volumeSpec, resourceCache)
 }
 
@@ -248,6 +263,72 @@ func (worker *gardenWorker) FindContainerByHandle(logger lager.Logger, teamID in
 	return containerProvider.FindCreatedContainerByHandle(logger, handle, teamID)
 }
 
+func (worker *gardenWorker) RunInContainer(logger lager.Logger, teamID int, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+	container, found, err := worker.FindContainerByHandle(logger, teamID, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	return container.Run(spec, io)
+}
+
+// StreamContainerOutput attaches to the task process of the given team's
+// container and returns its combined stdout and stderr. The stream ends once
+// the process exits; attaching to a process that has already exited replays
+// whatever output garden still holds for it.
+func (worker *gardenWorker) StreamContainerOutput(logger lager.Logger, teamID int, handle string) (io.ReadCloser, error) {
+	container, found, err := worker.FindContainerByHandle(logger, teamID, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, garden.ContainerNotFoundError{Handle: handle}
+	}
+
+	processID, err := container.Property(TaskProcessPropertyName)
+	if err != nil {
+		processID = TaskProcessID
+	}
+
+	reader, writer := io.Pipe()
+
+	process, err := container.Attach(processID, garden.ProcessIO{
+		Stdout: writer,
+		Stderr: writer,
+	})
+	if err != nil {
+		writer.Close()
+
+		_, exitErr := container.Property(TaskExitStatusPropertyName)
+		if exitErr == nil {
+			logger.Info("process-already-reaped", lager.Data{"handle": handle})
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		}
+
+		return nil, err
+	}
+
+	go func() {
+		_, err := process.Wait()
+		writer.CloseWithError(err)
+	}()
+
+	return reader, nil
+}
+
+func (worker *gardenWorker) StreamFileFromLatestAttempt(logger lager.Logger, teamID int, buildID int, stepName string, path string) (io.ReadCloser, error) {
+	return nil, ErrNotImplemented
+}
+
+func (worker *gardenWorker) EvacuateWorker(logger lager.Logger, workerName string) error {
+	return ErrNotImplemented
+}
+
 func (worker *gardenWorker) ActiveContainers() int {
 	return worker.activeContainers
 }
@@ -305,10 +386,22 @@ func (worker *gardenWorker) AllSatisfying(logger lager.Logger, spec WorkerSpec,
 	return nil, ErrNotImplemented
 }
 
+func (worker *gardenWorker) CanEverSatisfy(logger lager.Logger, spec WorkerSpec, resourceTypes atc.VersionedResourceTypes) (bool, string, error) {
+	return false, "", ErrNotImplemented
+}
+
+func (worker *gardenWorker) WarmInputs(logger lager.Logger, workerName string, sources []ArtifactSource) error {
+	return ErrNotImplemented
+}
+
 func (worker *gardenWorker) RunningWorkers(logger lager.Logger) ([]Worker, error) {
 	return nil, ErrNotImplemented
 }
 
+func (worker *gardenWorker) ClusterCapacity(logger lager.Logger) (ClusterCapacity, error) {
+	return ClusterCapacity{}, ErrNotImplemented
+}
+
 func (worker *gardenWorker) GetWorker(logger lager.Logger, name string) (Worker, error) {
 	return nil, ErrNotImplemented
 }
@@ -333,6 +426,10 @@ func (worker *gardenWorker) ResourceTypes() []atc.WorkerResourceType {
 	return worker.resourceTypes
 }
 
+func (worker *gardenWorker) Platform() string {
+	return worker.platform
+}
+
 func (worker *gardenWorker) Tags() atc.Tags {
 	return worker.tags
 }
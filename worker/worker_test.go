@@ -1,9 +1,14 @@
 package worker_test
 
 import (
+	"errors"
+	"io"
+	"io/ioutil"
 	"time"
 
 	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
 
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/concourse/atc"
@@ -200,6 +205,114 @@ var _ = Describe("Worker", func() {
 
 	})
 
+	Describe("StreamContainerOutput", func() {
+		var (
+			fakeContainer *wfakes.FakeContainer
+			fakeProcess   *gardenfakes.FakeProcess
+
+			output    io.ReadCloser
+			streamErr error
+		)
+
+		BeforeEach(func() {
+			fakeContainer = new(wfakes.FakeContainer)
+			fakeContainerProvider.FindCreatedContainerByHandleReturns(fakeContainer, true, nil)
+
+			fakeContainer.PropertyStub = func(name string) (string, error) {
+				if name == TaskProcessPropertyName {
+					return "some-process-id", nil
+				}
+
+				return "", errors.New("property does not exist")
+			}
+
+			exited := make(chan struct{})
+
+			fakeProcess = new(gardenfakes.FakeProcess)
+			fakeProcess.WaitStub = func() (int, error) {
+				<-exited
+				return 0, nil
+			}
+
+			fakeContainer.AttachStub = func(processID string, processIO garden.ProcessIO) (garden.Process, error) {
+				go func() {
+					defer close(exited)
+					processIO.Stdout.Write([]byte("some-stdout\n"))
+					processIO.Stderr.Write([]byte("some-stderr\n"))
+				}()
+
+				return fakeProcess, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			output, streamErr = gardenWorker.StreamContainerOutput(logger, 42, "some-handle")
+		})
+
+		It("looks up the container within the team", func() {
+			Expect(fakeContainerProvider.FindCreatedContainerByHandleCallCount()).To(Equal(1))
+
+			_, handle, actualTeamID := fakeContainerProvider.FindCreatedContainerByHandleArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(actualTeamID).To(Equal(42))
+		})
+
+		It("attaches to the task process", func() {
+			Expect(streamErr).NotTo(HaveOccurred())
+
+			Expect(fakeContainer.AttachCallCount()).To(Equal(1))
+			processID, _ := fakeContainer.AttachArgsForCall(0)
+			Expect(processID).To(Equal("some-process-id"))
+		})
+
+		It("streams the combined output until the process exits", func() {
+			Expect(streamErr).NotTo(HaveOccurred())
+
+			content, err := ioutil.ReadAll(output)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-stdout\nsome-stderr\n"))
+		})
+
+		Context("when the process has already been reaped", func() {
+			BeforeEach(func() {
+				fakeContainer.AttachStub = nil
+				fakeContainer.AttachReturns(nil, errors.New("process not found"))
+			})
+
+			Context("when the task recorded its exit status", func() {
+				BeforeEach(func() {
+					fakeContainer.PropertyStub = nil
+					fakeContainer.PropertyReturns("0", nil)
+				})
+
+				It("returns an empty stream", func() {
+					Expect(streamErr).NotTo(HaveOccurred())
+
+					content, err := ioutil.ReadAll(output)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(content).To(BeEmpty())
+				})
+			})
+
+			Context("when the task never finished", func() {
+				It("returns the error", func() {
+					Expect(streamErr).To(MatchError("process not found"))
+				})
+			})
+		})
+
+		Context("when the container is not found", func() {
+			BeforeEach(func() {
+				fakeContainerProvider.FindCreatedContainerByHandleReturns(nil, false, nil)
+			})
+
+			It("returns a container not found error", func() {
+				Expect(streamErr).To(Equal(garden.ContainerNotFoundError{Handle: "some-handle"}))
+				Expect(output).To(BeNil())
+			})
+		})
+	})
+
 	Describe("FindOrCreateBuildContainer", func() {
 		var container Container
 		var createErr error
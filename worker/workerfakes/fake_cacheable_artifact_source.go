@@ -0,0 +1,301 @@
+// This file was generated by counterfeiter
+package workerfakes
+
+import (
+	"io"
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/worker"
+)
+
+type FakeCacheableArtifactSource struct {
+	StreamToStub        func(worker.ArtifactDestination) error
+	streamToMutex       sync.RWMutex
+	streamToArgsForCall []struct {
+		arg1 worker.ArtifactDestination
+	}
+	streamToReturns struct {
+		result1 error
+	}
+	streamToReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamFileStub        func(path string) (io.ReadCloser, error)
+	streamFileMutex       sync.RWMutex
+	streamFileArgsForCall []struct {
+		path string
+	}
+	streamFileReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	streamFileReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	VolumeOnStub        func(worker.Worker) (worker.Volume, bool, error)
+	volumeOnMutex       sync.RWMutex
+	volumeOnArgsForCall []struct {
+		arg1 worker.Worker
+	}
+	volumeOnReturns struct {
+		result1 worker.Volume
+		result2 bool
+		result3 error
+	}
+	volumeOnReturnsOnCall map[int]struct {
+		result1 worker.Volume
+		result2 bool
+		result3 error
+	}
+	CreateCacheOnStub        func(lager.Logger, worker.Worker) (worker.Volume, error)
+	createCacheOnMutex       sync.RWMutex
+	createCacheOnArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 worker.Worker
+	}
+	createCacheOnReturns struct {
+		result1 worker.Volume
+		result2 error
+	}
+	createCacheOnReturnsOnCall map[int]struct {
+		result1 worker.Volume
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCacheableArtifactSource) StreamTo(arg1 worker.ArtifactDestination) error {
+	fake.streamToMutex.Lock()
+	ret, specificReturn := fake.streamToReturnsOnCall[len(fake.streamToArgsForCall)]
+	fake.streamToArgsForCall = append(fake.streamToArgsForCall, struct {
+		arg1 worker.ArtifactDestination
+	}{arg1})
+	fake.recordInvocation("StreamTo", []interface{}{arg1})
+	fake.streamToMutex.Unlock()
+	if fake.StreamToStub != nil {
+		return fake.StreamToStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.streamToReturns.result1
+}
+
+func (fake *FakeCacheableArtifactSource) StreamToCallCount() int {
+	fake.streamToMutex.RLock()
+	defer fake.streamToMutex.RUnlock()
+	return len(fake.streamToArgsForCall)
+}
+
+func (fake *FakeCacheableArtifactSource) StreamToArgsForCall(i int) worker.ArtifactDestination {
+	fake.streamToMutex.RLock()
+	defer fake.streamToMutex.RUnlock()
+	return fake.streamToArgsForCall[i].arg1
+}
+
+func (fake *FakeCacheableArtifactSource) StreamToReturns(result1 error) {
+	fake.StreamToStub = nil
+	fake.streamToReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCacheableArtifactSource) StreamToReturnsOnCall(i int, result1 error) {
+	fake.StreamToStub = nil
+	if fake.streamToReturnsOnCall == nil {
+		fake.streamToReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamToReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCacheableArtifactSource) StreamFile(path string) (io.ReadCloser, error) {
+	fake.streamFileMutex.Lock()
+	ret, specificReturn := fake.streamFileReturnsOnCall[len(fake.streamFileArgsForCall)]
+	fake.streamFileArgsForCall = append(fake.streamFileArgsForCall, struct {
+		path string
+	}{path})
+	fake.recordInvocation("StreamFile", []interface{}{path})
+	fake.streamFileMutex.Unlock()
+	if fake.StreamFileStub != nil {
+		return fake.StreamFileStub(path)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.streamFileReturns.result1, fake.streamFileReturns.result2
+}
+
+func (fake *FakeCacheableArtifactSource) StreamFileCallCount() int {
+	fake.streamFileMutex.RLock()
+	defer fake.streamFileMutex.RUnlock()
+	return len(fake.streamFileArgsForCall)
+}
+
+func (fake *FakeCacheableArtifactSource) StreamFileArgsForCall(i int) string {
+	fake.streamFileMutex.RLock()
+	defer fake.streamFileMutex.RUnlock()
+	return fake.streamFileArgsForCall[i].path
+}
+
+func (fake *FakeCacheableArtifactSource) StreamFileReturns(result1 io.ReadCloser, result2 error) {
+	fake.StreamFileStub = nil
+	fake.streamFileReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCacheableArtifactSource) StreamFileReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.StreamFileStub = nil
+	if fake.streamFileReturnsOnCall == nil {
+		fake.streamFileReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.streamFileReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCacheableArtifactSource) VolumeOn(arg1 worker.Worker) (worker.Volume, bool, error) {
+	fake.volumeOnMutex.Lock()
+	ret, specificReturn := fake.volumeOnReturnsOnCall[len(fake.volumeOnArgsForCall)]
+	fake.volumeOnArgsForCall = append(fake.volumeOnArgsForCall, struct {
+		arg1 worker.Worker
+	}{arg1})
+	fake.recordInvocation("VolumeOn", []interface{}{arg1})
+	fake.volumeOnMutex.Unlock()
+	if fake.VolumeOnStub != nil {
+		return fake.VolumeOnStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.volumeOnReturns.result1, fake.volumeOnReturns.result2, fake.volumeOnReturns.result3
+}
+
+func (fake *FakeCacheableArtifactSource) VolumeOnCallCount() int {
+	fake.volumeOnMutex.RLock()
+	defer fake.volumeOnMutex.RUnlock()
+	return len(fake.volumeOnArgsForCall)
+}
+
+func (fake *FakeCacheableArtifactSource) VolumeOnArgsForCall(i int) worker.Worker {
+	fake.volumeOnMutex.RLock()
+	defer fake.volumeOnMutex.RUnlock()
+	return fake.volumeOnArgsForCall[i].arg1
+}
+
+func (fake *FakeCacheableArtifactSource) VolumeOnReturns(result1 worker.Volume, result2 bool, result3 error) {
+	fake.VolumeOnStub = nil
+	fake.volumeOnReturns = struct {
+		result1 worker.Volume
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeCacheableArtifactSource) VolumeOnReturnsOnCall(i int, result1 worker.Volume, result2 bool, result3 error) {
+	fake.VolumeOnStub = nil
+	if fake.volumeOnReturnsOnCall == nil {
+		fake.volumeOnReturnsOnCall = make(map[int]struct {
+			result1 worker.Volume
+			result2 bool
+			result3 error
+		})
+	}
+	fake.volumeOnReturnsOnCall[i] = struct {
+		result1 worker.Volume
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeCacheableArtifactSource) CreateCacheOn(arg1 lager.Logger, arg2 worker.Worker) (worker.Volume, error) {
+	fake.createCacheOnMutex.Lock()
+	ret, specificReturn := fake.createCacheOnReturnsOnCall[len(fake.createCacheOnArgsForCall)]
+	fake.createCacheOnArgsForCall = append(fake.createCacheOnArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 worker.Worker
+	}{arg1, arg2})
+	fake.recordInvocation("CreateCacheOn", []interface{}{arg1, arg2})
+	fake.createCacheOnMutex.Unlock()
+	if fake.CreateCacheOnStub != nil {
+		return fake.CreateCacheOnStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.createCacheOnReturns.result1, fake.createCacheOnReturns.result2
+}
+
+func (fake *FakeCacheableArtifactSource) CreateCacheOnCallCount() int {
+	fake.createCacheOnMutex.RLock()
+	defer fake.createCacheOnMutex.RUnlock()
+	return len(fake.createCacheOnArgsForCall)
+}
+
+func (fake *FakeCacheableArtifactSource) CreateCacheOnArgsForCall(i int) (lager.Logger, worker.Worker) {
+	fake.createCacheOnMutex.RLock()
+	defer fake.createCacheOnMutex.RUnlock()
+	return fake.createCacheOnArgsForCall[i].arg1, fake.createCacheOnArgsForCall[i].arg2
+}
+
+func (fake *FakeCacheableArtifactSource) CreateCacheOnReturns(result1 worker.Volume, result2 error) {
+	fake.CreateCacheOnStub = nil
+	fake.createCacheOnReturns = struct {
+		result1 worker.Volume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCacheableArtifactSource) CreateCacheOnReturnsOnCall(i int, result1 worker.Volume, result2 error) {
+	fake.CreateCacheOnStub = nil
+	if fake.createCacheOnReturnsOnCall == nil {
+		fake.createCacheOnReturnsOnCall = make(map[int]struct {
+			result1 worker.Volume
+			result2 error
+		})
+	}
+	fake.createCacheOnReturnsOnCall[i] = struct {
+		result1 worker.Volume
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCacheableArtifactSource) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.streamToMutex.RLock()
+	defer fake.streamToMutex.RUnlock()
+	fake.streamFileMutex.RLock()
+	defer fake.streamFileMutex.RUnlock()
+	fake.volumeOnMutex.RLock()
+	defer fake.volumeOnMutex.RUnlock()
+	fake.createCacheOnMutex.RLock()
+	defer fake.createCacheOnMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCacheableArtifactSource) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ worker.CacheableArtifactSource = new(FakeCacheableArtifactSource)
@@ -2,9 +2,11 @@
 package workerfakes
 
 import (
+	"io"
 	"os"
 	"sync"
 
+	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/lager"
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/dbng"
@@ -76,10 +78,12 @@ type FakeClient struct {
 		result1 worker.Container
 		result2 error
 	}
-	CreateVolumeForResourceCacheStub        func(logger lager.Logger, vs worker.VolumeSpec, resourceCache *dbng.UsedResourceCache) (worker.Volume, error)
+	CreateVolumeForResourceCacheStub        func(logger lager.Logger, spec worker.WorkerSpec, resourceTypes atc.VersionedResourceTypes, vs worker.VolumeSpec, resourceCache *dbng.UsedResourceCache) (worker.Volume, error)
 	createVolumeForResourceCacheMutex       sync.RWMutex
 	createVolumeForResourceCacheArgsForCall []struct {
 		logger        lager.Logger
+		spec          worker.WorkerSpec
+		resourceTypes atc.VersionedResourceTypes
 		vs            worker.VolumeSpec
 		resourceCache *dbng.UsedResourceCache
 	}
@@ -124,6 +128,80 @@ type FakeClient struct {
 		result2 bool
 		result3 error
 	}
+	RunInContainerStub        func(lager.Logger, int, string, garden.ProcessSpec, garden.ProcessIO) (garden.Process, error)
+	runInContainerMutex       sync.RWMutex
+	runInContainerArgsForCall []struct {
+		logger lager.Logger
+		teamID int
+		handle string
+		spec   garden.ProcessSpec
+		io     garden.ProcessIO
+	}
+	runInContainerReturns struct {
+		result1 garden.Process
+		result2 error
+	}
+	runInContainerReturnsOnCall map[int]struct {
+		result1 garden.Process
+		result2 error
+	}
+	StreamFileFromLatestAttemptStub        func(lager.Logger, int, int, string, string) (io.ReadCloser, error)
+	streamFileFromLatestAttemptMutex       sync.RWMutex
+	streamFileFromLatestAttemptArgsForCall []struct {
+		logger   lager.Logger
+		teamID   int
+		buildID  int
+		stepName string
+		path     string
+	}
+	streamFileFromLatestAttemptReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	streamFileFromLatestAttemptReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	StreamContainerOutputStub        func(logger lager.Logger, teamID int, handle string) (io.ReadCloser, error)
+	streamContainerOutputMutex       sync.RWMutex
+	streamContainerOutputArgsForCall []struct {
+		logger lager.Logger
+		teamID int
+		handle string
+	}
+	streamContainerOutputReturns struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	streamContainerOutputReturnsOnCall map[int]struct {
+		result1 io.ReadCloser
+		result2 error
+	}
+	EvacuateWorkerStub        func(lager.Logger, string) error
+	evacuateWorkerMutex       sync.RWMutex
+	evacuateWorkerArgsForCall []struct {
+		logger     lager.Logger
+		workerName string
+	}
+	evacuateWorkerReturns struct {
+		result1 error
+	}
+	evacuateWorkerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WarmInputsStub        func(logger lager.Logger, workerName string, sources []worker.ArtifactSource) error
+	warmInputsMutex       sync.RWMutex
+	warmInputsArgsForCall []struct {
+		logger     lager.Logger
+		workerName string
+		sources    []worker.ArtifactSource
+	}
+	warmInputsReturns struct {
+		result1 error
+	}
+	warmInputsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	FindResourceTypeByPathStub        func(path string) (atc.WorkerResourceType, bool)
 	findResourceTypeByPathMutex       sync.RWMutex
 	findResourceTypeByPathArgsForCall []struct {
@@ -183,6 +261,23 @@ type FakeClient struct {
 		result1 []worker.Worker
 		result2 error
 	}
+	CanEverSatisfyStub        func(lager.Logger, worker.WorkerSpec, atc.VersionedResourceTypes) (bool, string, error)
+	canEverSatisfyMutex       sync.RWMutex
+	canEverSatisfyArgsForCall []struct {
+		arg1 lager.Logger
+		arg2 worker.WorkerSpec
+		arg3 atc.VersionedResourceTypes
+	}
+	canEverSatisfyReturns struct {
+		result1 bool
+		result2 string
+		result3 error
+	}
+	canEverSatisfyReturnsOnCall map[int]struct {
+		result1 bool
+		result2 string
+		result3 error
+	}
 	RunningWorkersStub        func(lager.Logger) ([]worker.Worker, error)
 	runningWorkersMutex       sync.RWMutex
 	runningWorkersArgsForCall []struct {
@@ -196,6 +291,19 @@ type FakeClient struct {
 		result1 []worker.Worker
 		result2 error
 	}
+	ClusterCapacityStub        func(lager.Logger) (worker.ClusterCapacity, error)
+	clusterCapacityMutex       sync.RWMutex
+	clusterCapacityArgsForCall []struct {
+		arg1 lager.Logger
+	}
+	clusterCapacityReturns struct {
+		result1 worker.ClusterCapacity
+		result2 error
+	}
+	clusterCapacityReturnsOnCall map[int]struct {
+		result1 worker.ClusterCapacity
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -378,18 +486,20 @@ func (fake *FakeClient) FindOrCreateResourceCheckContainerReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
-func (fake *FakeClient) CreateVolumeForResourceCache(logger lager.Logger, vs worker.VolumeSpec, resourceCache *dbng.UsedResourceCache) (worker.Volume, error) {
+func (fake *FakeClient) CreateVolumeForResourceCache(logger lager.Logger, spec worker.WorkerSpec, resourceTypes atc.VersionedResourceTypes, vs worker.VolumeSpec, resourceCache *dbng.UsedResourceCache) (worker.Volume, error) {
 	fake.createVolumeForResourceCacheMutex.Lock()
 	ret, specificReturn := fake.createVolumeForResourceCacheReturnsOnCall[len(fake.createVolumeForResourceCacheArgsForCall)]
 	fake.createVolumeForResourceCacheArgsForCall = append(fake.createVolumeForResourceCacheArgsForCall, struct {
 		logger        lager.Logger
+		spec          worker.WorkerSpec
+		resourceTypes atc.VersionedResourceTypes
 		vs            worker.VolumeSpec
 		resourceCache *dbng.UsedResourceCache
-	}{logger, vs, resourceCache})
-	fake.recordInvocation("CreateVolumeForResourceCache", []interface{}{logger, vs, resourceCache})
+	}{logger, spec, resourceTypes, vs, resourceCache})
+	fake.recordInvocation("CreateVolumeForResourceCache", []interface{}{logger, spec, resourceTypes, vs, resourceCache})
 	fake.createVolumeForResourceCacheMutex.Unlock()
 	if fake.CreateVolumeForResourceCacheStub != nil {
-		return fake.CreateVolumeForResourceCacheStub(logger, vs, resourceCache)
+		return fake.CreateVolumeForResourceCacheStub(logger, spec, resourceTypes, vs, resourceCache)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -403,10 +513,10 @@ func (fake *FakeClient) CreateVolumeForResourceCacheCallCount() int {
 	return len(fake.createVolumeForResourceCacheArgsForCall)
 }
 
-func (fake *FakeClient) CreateVolumeForResourceCacheArgsForCall(i int) (lager.Logger, worker.VolumeSpec, *dbng.UsedResourceCache) {
+func (fake *FakeClient) CreateVolumeForResourceCacheArgsForCall(i int) (lager.Logger, worker.WorkerSpec, atc.VersionedResourceTypes, worker.VolumeSpec, *dbng.UsedResourceCache) {
 	fake.createVolumeForResourceCacheMutex.RLock()
 	defer fake.createVolumeForResourceCacheMutex.RUnlock()
-	return fake.createVolumeForResourceCacheArgsForCall[i].logger, fake.createVolumeForResourceCacheArgsForCall[i].vs, fake.createVolumeForResourceCacheArgsForCall[i].resourceCache
+	return fake.createVolumeForResourceCacheArgsForCall[i].logger, fake.createVolumeForResourceCacheArgsForCall[i].spec, fake.createVolumeForResourceCacheArgsForCall[i].resourceTypes, fake.createVolumeForResourceCacheArgsForCall[i].vs, fake.createVolumeForResourceCacheArgsForCall[i].resourceCache
 }
 
 func (fake *FakeClient) CreateVolumeForResourceCacheReturns(result1 worker.Volume, result2 error) {
@@ -542,6 +652,273 @@ func (fake *FakeClient) FindContainerByHandleReturnsOnCall(i int, result1 worker
 	}{result1, result2, result3}
 }
 
+func (fake *FakeClient) RunInContainer(logger lager.Logger, teamID int, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+	fake.runInContainerMutex.Lock()
+	ret, specificReturn := fake.runInContainerReturnsOnCall[len(fake.runInContainerArgsForCall)]
+	fake.runInContainerArgsForCall = append(fake.runInContainerArgsForCall, struct {
+		logger lager.Logger
+		teamID int
+		handle string
+		spec   garden.ProcessSpec
+		io     garden.ProcessIO
+	}{logger, teamID, handle, spec, io})
+	fake.recordInvocation("RunInContainer", []interface{}{logger, teamID, handle, spec, io})
+	fake.runInContainerMutex.Unlock()
+	if fake.RunInContainerStub != nil {
+		return fake.RunInContainerStub(logger, teamID, handle, spec, io)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.runInContainerReturns.result1, fake.runInContainerReturns.result2
+}
+
+func (fake *FakeClient) RunInContainerCallCount() int {
+	fake.runInContainerMutex.RLock()
+	defer fake.runInContainerMutex.RUnlock()
+	return len(fake.runInContainerArgsForCall)
+}
+
+func (fake *FakeClient) RunInContainerArgsForCall(i int) (lager.Logger, int, string, garden.ProcessSpec, garden.ProcessIO) {
+	fake.runInContainerMutex.RLock()
+	defer fake.runInContainerMutex.RUnlock()
+	return fake.runInContainerArgsForCall[i].logger, fake.runInContainerArgsForCall[i].teamID, fake.runInContainerArgsForCall[i].handle, fake.runInContainerArgsForCall[i].spec, fake.runInContainerArgsForCall[i].io
+}
+
+func (fake *FakeClient) RunInContainerReturns(result1 garden.Process, result2 error) {
+	fake.RunInContainerStub = nil
+	fake.runInContainerReturns = struct {
+		result1 garden.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) RunInContainerReturnsOnCall(i int, result1 garden.Process, result2 error) {
+	fake.RunInContainerStub = nil
+	if fake.runInContainerReturnsOnCall == nil {
+		fake.runInContainerReturnsOnCall = make(map[int]struct {
+			result1 garden.Process
+			result2 error
+		})
+	}
+	fake.runInContainerReturnsOnCall[i] = struct {
+		result1 garden.Process
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StreamFileFromLatestAttempt(logger lager.Logger, teamID int, buildID int, stepName string, path string) (io.ReadCloser, error) {
+	fake.streamFileFromLatestAttemptMutex.Lock()
+	ret, specificReturn := fake.streamFileFromLatestAttemptReturnsOnCall[len(fake.streamFileFromLatestAttemptArgsForCall)]
+	fake.streamFileFromLatestAttemptArgsForCall = append(fake.streamFileFromLatestAttemptArgsForCall, struct {
+		logger   lager.Logger
+		teamID   int
+		buildID  int
+		stepName string
+		path     string
+	}{logger, teamID, buildID, stepName, path})
+	fake.recordInvocation("StreamFileFromLatestAttempt", []interface{}{logger, teamID, buildID, stepName, path})
+	fake.streamFileFromLatestAttemptMutex.Unlock()
+	if fake.StreamFileFromLatestAttemptStub != nil {
+		return fake.StreamFileFromLatestAttemptStub(logger, teamID, buildID, stepName, path)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.streamFileFromLatestAttemptReturns.result1, fake.streamFileFromLatestAttemptReturns.result2
+}
+
+func (fake *FakeClient) StreamFileFromLatestAttemptCallCount() int {
+	fake.streamFileFromLatestAttemptMutex.RLock()
+	defer fake.streamFileFromLatestAttemptMutex.RUnlock()
+	return len(fake.streamFileFromLatestAttemptArgsForCall)
+}
+
+func (fake *FakeClient) StreamFileFromLatestAttemptArgsForCall(i int) (lager.Logger, int, int, string, string) {
+	fake.streamFileFromLatestAttemptMutex.RLock()
+	defer fake.streamFileFromLatestAttemptMutex.RUnlock()
+	return fake.streamFileFromLatestAttemptArgsForCall[i].logger, fake.streamFileFromLatestAttemptArgsForCall[i].teamID, fake.streamFileFromLatestAttemptArgsForCall[i].buildID, fake.streamFileFromLatestAttemptArgsForCall[i].stepName, fake.streamFileFromLatestAttemptArgsForCall[i].path
+}
+
+func (fake *FakeClient) StreamFileFromLatestAttemptReturns(result1 io.ReadCloser, result2 error) {
+	fake.StreamFileFromLatestAttemptStub = nil
+	fake.streamFileFromLatestAttemptReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StreamFileFromLatestAttemptReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.StreamFileFromLatestAttemptStub = nil
+	if fake.streamFileFromLatestAttemptReturnsOnCall == nil {
+		fake.streamFileFromLatestAttemptReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.streamFileFromLatestAttemptReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StreamContainerOutput(logger lager.Logger, teamID int, handle string) (io.ReadCloser, error) {
+	fake.streamContainerOutputMutex.Lock()
+	ret, specificReturn := fake.streamContainerOutputReturnsOnCall[len(fake.streamContainerOutputArgsForCall)]
+	fake.streamContainerOutputArgsForCall = append(fake.streamContainerOutputArgsForCall, struct {
+		logger lager.Logger
+		teamID int
+		handle string
+	}{logger, teamID, handle})
+	fake.recordInvocation("StreamContainerOutput", []interface{}{logger, teamID, handle})
+	fake.streamContainerOutputMutex.Unlock()
+	if fake.StreamContainerOutputStub != nil {
+		return fake.StreamContainerOutputStub(logger, teamID, handle)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.streamContainerOutputReturns.result1, fake.streamContainerOutputReturns.result2
+}
+
+func (fake *FakeClient) StreamContainerOutputCallCount() int {
+	fake.streamContainerOutputMutex.RLock()
+	defer fake.streamContainerOutputMutex.RUnlock()
+	return len(fake.streamContainerOutputArgsForCall)
+}
+
+func (fake *FakeClient) StreamContainerOutputArgsForCall(i int) (lager.Logger, int, string) {
+	fake.streamContainerOutputMutex.RLock()
+	defer fake.streamContainerOutputMutex.RUnlock()
+	return fake.streamContainerOutputArgsForCall[i].logger, fake.streamContainerOutputArgsForCall[i].teamID, fake.streamContainerOutputArgsForCall[i].handle
+}
+
+func (fake *FakeClient) StreamContainerOutputReturns(result1 io.ReadCloser, result2 error) {
+	fake.StreamContainerOutputStub = nil
+	fake.streamContainerOutputReturns = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StreamContainerOutputReturnsOnCall(i int, result1 io.ReadCloser, result2 error) {
+	fake.StreamContainerOutputStub = nil
+	if fake.streamContainerOutputReturnsOnCall == nil {
+		fake.streamContainerOutputReturnsOnCall = make(map[int]struct {
+			result1 io.ReadCloser
+			result2 error
+		})
+	}
+	fake.streamContainerOutputReturnsOnCall[i] = struct {
+		result1 io.ReadCloser
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) EvacuateWorker(logger lager.Logger, workerName string) error {
+	fake.evacuateWorkerMutex.Lock()
+	ret, specificReturn := fake.evacuateWorkerReturnsOnCall[len(fake.evacuateWorkerArgsForCall)]
+	fake.evacuateWorkerArgsForCall = append(fake.evacuateWorkerArgsForCall, struct {
+		logger     lager.Logger
+		workerName string
+	}{logger, workerName})
+	fake.recordInvocation("EvacuateWorker", []interface{}{logger, workerName})
+	fake.evacuateWorkerMutex.Unlock()
+	if fake.EvacuateWorkerStub != nil {
+		return fake.EvacuateWorkerStub(logger, workerName)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.evacuateWorkerReturns.result1
+}
+
+func (fake *FakeClient) EvacuateWorkerCallCount() int {
+	fake.evacuateWorkerMutex.RLock()
+	defer fake.evacuateWorkerMutex.RUnlock()
+	return len(fake.evacuateWorkerArgsForCall)
+}
+
+func (fake *FakeClient) EvacuateWorkerArgsForCall(i int) (lager.Logger, string) {
+	fake.evacuateWorkerMutex.RLock()
+	defer fake.evacuateWorkerMutex.RUnlock()
+	return fake.evacuateWorkerArgsForCall[i].logger, fake.evacuateWorkerArgsForCall[i].workerName
+}
+
+func (fake *FakeClient) EvacuateWorkerReturns(result1 error) {
+	fake.EvacuateWorkerStub = nil
+	fake.evacuateWorkerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) EvacuateWorkerReturnsOnCall(i int, result1 error) {
+	fake.EvacuateWorkerStub = nil
+	if fake.evacuateWorkerReturnsOnCall == nil {
+		fake.evacuateWorkerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.evacuateWorkerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) WarmInputs(logger lager.Logger, workerName string, sources []worker.ArtifactSource) error {
+	var sourcesCopy []worker.ArtifactSource
+	if sources != nil {
+		sourcesCopy = make([]worker.ArtifactSource, len(sources))
+		copy(sourcesCopy, sources)
+	}
+	fake.warmInputsMutex.Lock()
+	ret, specificReturn := fake.warmInputsReturnsOnCall[len(fake.warmInputsArgsForCall)]
+	fake.warmInputsArgsForCall = append(fake.warmInputsArgsForCall, struct {
+		logger     lager.Logger
+		workerName string
+		sources    []worker.ArtifactSource
+	}{logger, workerName, sourcesCopy})
+	fake.recordInvocation("WarmInputs", []interface{}{logger, workerName, sourcesCopy})
+	fake.warmInputsMutex.Unlock()
+	if fake.WarmInputsStub != nil {
+		return fake.WarmInputsStub(logger, workerName, sources)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.warmInputsReturns.result1
+}
+
+func (fake *FakeClient) WarmInputsCallCount() int {
+	fake.warmInputsMutex.RLock()
+	defer fake.warmInputsMutex.RUnlock()
+	return len(fake.warmInputsArgsForCall)
+}
+
+func (fake *FakeClient) WarmInputsArgsForCall(i int) (lager.Logger, string, []worker.ArtifactSource) {
+	fake.warmInputsMutex.RLock()
+	defer fake.warmInputsMutex.RUnlock()
+	return fake.warmInputsArgsForCall[i].logger, fake.warmInputsArgsForCall[i].workerName, fake.warmInputsArgsForCall[i].sources
+}
+
+func (fake *FakeClient) WarmInputsReturns(result1 error) {
+	fake.WarmInputsStub = nil
+	fake.warmInputsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) WarmInputsReturnsOnCall(i int, result1 error) {
+	fake.WarmInputsStub = nil
+	if fake.warmInputsReturnsOnCall == nil {
+		fake.warmInputsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.warmInputsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) FindResourceTypeByPath(path string) (atc.WorkerResourceType, bool) {
 	fake.findResourceTypeByPathMutex.Lock()
 	ret, specificReturn := fake.findResourceTypeByPathReturnsOnCall[len(fake.findResourceTypeByPathArgsForCall)]
@@ -754,6 +1131,62 @@ func (fake *FakeClient) AllSatisfyingReturnsOnCall(i int, result1 []worker.Worke
 	}{result1, result2}
 }
 
+func (fake *FakeClient) CanEverSatisfy(arg1 lager.Logger, arg2 worker.WorkerSpec, arg3 atc.VersionedResourceTypes) (bool, string, error) {
+	fake.canEverSatisfyMutex.Lock()
+	ret, specificReturn := fake.canEverSatisfyReturnsOnCall[len(fake.canEverSatisfyArgsForCall)]
+	fake.canEverSatisfyArgsForCall = append(fake.canEverSatisfyArgsForCall, struct {
+		arg1 lager.Logger
+		arg2 worker.WorkerSpec
+		arg3 atc.VersionedResourceTypes
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("CanEverSatisfy", []interface{}{arg1, arg2, arg3})
+	fake.canEverSatisfyMutex.Unlock()
+	if fake.CanEverSatisfyStub != nil {
+		return fake.CanEverSatisfyStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.canEverSatisfyReturns.result1, fake.canEverSatisfyReturns.result2, fake.canEverSatisfyReturns.result3
+}
+
+func (fake *FakeClient) CanEverSatisfyCallCount() int {
+	fake.canEverSatisfyMutex.RLock()
+	defer fake.canEverSatisfyMutex.RUnlock()
+	return len(fake.canEverSatisfyArgsForCall)
+}
+
+func (fake *FakeClient) CanEverSatisfyArgsForCall(i int) (lager.Logger, worker.WorkerSpec, atc.VersionedResourceTypes) {
+	fake.canEverSatisfyMutex.RLock()
+	defer fake.canEverSatisfyMutex.RUnlock()
+	return fake.canEverSatisfyArgsForCall[i].arg1, fake.canEverSatisfyArgsForCall[i].arg2, fake.canEverSatisfyArgsForCall[i].arg3
+}
+
+func (fake *FakeClient) CanEverSatisfyReturns(result1 bool, result2 string, result3 error) {
+	fake.CanEverSatisfyStub = nil
+	fake.canEverSatisfyReturns = struct {
+		result1 bool
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) CanEverSatisfyReturnsOnCall(i int, result1 bool, result2 string, result3 error) {
+	fake.CanEverSatisfyStub = nil
+	if fake.canEverSatisfyReturnsOnCall == nil {
+		fake.canEverSatisfyReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 string
+			result3 error
+		})
+	}
+	fake.canEverSatisfyReturnsOnCall[i] = struct {
+		result1 bool
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeClient) RunningWorkers(arg1 lager.Logger) ([]worker.Worker, error) {
 	fake.runningWorkersMutex.Lock()
 	ret, specificReturn := fake.runningWorkersReturnsOnCall[len(fake.runningWorkersArgsForCall)]
@@ -805,6 +1238,57 @@ func (fake *FakeClient) RunningWorkersReturnsOnCall(i int, result1 []worker.Work
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ClusterCapacity(arg1 lager.Logger) (worker.ClusterCapacity, error) {
+	fake.clusterCapacityMutex.Lock()
+	ret, specificReturn := fake.clusterCapacityReturnsOnCall[len(fake.clusterCapacityArgsForCall)]
+	fake.clusterCapacityArgsForCall = append(fake.clusterCapacityArgsForCall, struct {
+		arg1 lager.Logger
+	}{arg1})
+	fake.recordInvocation("ClusterCapacity", []interface{}{arg1})
+	fake.clusterCapacityMutex.Unlock()
+	if fake.ClusterCapacityStub != nil {
+		return fake.ClusterCapacityStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.clusterCapacityReturns.result1, fake.clusterCapacityReturns.result2
+}
+
+func (fake *FakeClient) ClusterCapacityCallCount() int {
+	fake.clusterCapacityMutex.RLock()
+	defer fake.clusterCapacityMutex.RUnlock()
+	return len(fake.clusterCapacityArgsForCall)
+}
+
+func (fake *FakeClient) ClusterCapacityArgsForCall(i int) lager.Logger {
+	fake.clusterCapacityMutex.RLock()
+	defer fake.clusterCapacityMutex.RUnlock()
+	return fake.clusterCapacityArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) ClusterCapacityReturns(result1 worker.ClusterCapacity, result2 error) {
+	fake.ClusterCapacityStub = nil
+	fake.clusterCapacityReturns = struct {
+		result1 worker.ClusterCapacity
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ClusterCapacityReturnsOnCall(i int, result1 worker.ClusterCapacity, result2 error) {
+	fake.ClusterCapacityStub = nil
+	if fake.clusterCapacityReturnsOnCall == nil {
+		fake.clusterCapacityReturnsOnCall = make(map[int]struct {
+			result1 worker.ClusterCapacity
+			result2 error
+		})
+	}
+	fake.clusterCapacityReturnsOnCall[i] = struct {
+		result1 worker.ClusterCapacity
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -820,6 +1304,16 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.findInitializedVolumeForResourceCacheMutex.RUnlock()
 	fake.findContainerByHandleMutex.RLock()
 	defer fake.findContainerByHandleMutex.RUnlock()
+	fake.runInContainerMutex.RLock()
+	defer fake.runInContainerMutex.RUnlock()
+	fake.streamFileFromLatestAttemptMutex.RLock()
+	defer fake.streamFileFromLatestAttemptMutex.RUnlock()
+	fake.streamContainerOutputMutex.RLock()
+	defer fake.streamContainerOutputMutex.RUnlock()
+	fake.evacuateWorkerMutex.RLock()
+	defer fake.evacuateWorkerMutex.RUnlock()
+	fake.warmInputsMutex.RLock()
+	defer fake.warmInputsMutex.RUnlock()
 	fake.findResourceTypeByPathMutex.RLock()
 	defer fake.findResourceTypeByPathMutex.RUnlock()
 	fake.lookupVolumeMutex.RLock()
@@ -828,8 +1322,12 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.satisfyingMutex.RUnlock()
 	fake.allSatisfyingMutex.RLock()
 	defer fake.allSatisfyingMutex.RUnlock()
+	fake.canEverSatisfyMutex.RLock()
+	defer fake.canEverSatisfyMutex.RUnlock()
 	fake.runningWorkersMutex.RLock()
 	defer fake.runningWorkersMutex.RUnlock()
+	fake.clusterCapacityMutex.RLock()
+	defer fake.clusterCapacityMutex.RUnlock()
 	return fake.invocations
 }
 
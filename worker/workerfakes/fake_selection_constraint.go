@@ -0,0 +1,153 @@
+// This file was generated by counterfeiter
+package workerfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/concourse/atc/worker"
+)
+
+type FakeSelectionConstraint struct {
+	ConstrainStub        func(logger lager.Logger, workers []worker.Worker) []worker.Worker
+	constrainMutex       sync.RWMutex
+	constrainArgsForCall []struct {
+		logger  lager.Logger
+		workers []worker.Worker
+	}
+	constrainReturns struct {
+		result1 []worker.Worker
+	}
+	constrainReturnsOnCall map[int]struct {
+		result1 []worker.Worker
+	}
+	TagsStub        func() []string
+	tagsMutex       sync.RWMutex
+	tagsArgsForCall []struct{}
+	tagsReturns     struct {
+		result1 []string
+	}
+	tagsReturnsOnCall map[int]struct {
+		result1 []string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSelectionConstraint) Constrain(logger lager.Logger, workers []worker.Worker) []worker.Worker {
+	var workersCopy []worker.Worker
+	if workers != nil {
+		workersCopy = make([]worker.Worker, len(workers))
+		copy(workersCopy, workers)
+	}
+	fake.constrainMutex.Lock()
+	ret, specificReturn := fake.constrainReturnsOnCall[len(fake.constrainArgsForCall)]
+	fake.constrainArgsForCall = append(fake.constrainArgsForCall, struct {
+		logger  lager.Logger
+		workers []worker.Worker
+	}{logger, workersCopy})
+	fake.recordInvocation("Constrain", []interface{}{logger, workersCopy})
+	fake.constrainMutex.Unlock()
+	if fake.ConstrainStub != nil {
+		return fake.ConstrainStub(logger, workers)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.constrainReturns.result1
+}
+
+func (fake *FakeSelectionConstraint) ConstrainCallCount() int {
+	fake.constrainMutex.RLock()
+	defer fake.constrainMutex.RUnlock()
+	return len(fake.constrainArgsForCall)
+}
+
+func (fake *FakeSelectionConstraint) ConstrainArgsForCall(i int) (lager.Logger, []worker.Worker) {
+	fake.constrainMutex.RLock()
+	defer fake.constrainMutex.RUnlock()
+	return fake.constrainArgsForCall[i].logger, fake.constrainArgsForCall[i].workers
+}
+
+func (fake *FakeSelectionConstraint) ConstrainReturns(result1 []worker.Worker) {
+	fake.ConstrainStub = nil
+	fake.constrainReturns = struct {
+		result1 []worker.Worker
+	}{result1}
+}
+
+func (fake *FakeSelectionConstraint) ConstrainReturnsOnCall(i int, result1 []worker.Worker) {
+	fake.ConstrainStub = nil
+	if fake.constrainReturnsOnCall == nil {
+		fake.constrainReturnsOnCall = make(map[int]struct {
+			result1 []worker.Worker
+		})
+	}
+	fake.constrainReturnsOnCall[i] = struct {
+		result1 []worker.Worker
+	}{result1}
+}
+
+func (fake *FakeSelectionConstraint) Tags() []string {
+	fake.tagsMutex.Lock()
+	ret, specificReturn := fake.tagsReturnsOnCall[len(fake.tagsArgsForCall)]
+	fake.tagsArgsForCall = append(fake.tagsArgsForCall, struct{}{})
+	fake.recordInvocation("Tags", []interface{}{})
+	fake.tagsMutex.Unlock()
+	if fake.TagsStub != nil {
+		return fake.TagsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.tagsReturns.result1
+}
+
+func (fake *FakeSelectionConstraint) TagsCallCount() int {
+	fake.tagsMutex.RLock()
+	defer fake.tagsMutex.RUnlock()
+	return len(fake.tagsArgsForCall)
+}
+
+func (fake *FakeSelectionConstraint) TagsReturns(result1 []string) {
+	fake.TagsStub = nil
+	fake.tagsReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeSelectionConstraint) TagsReturnsOnCall(i int, result1 []string) {
+	fake.TagsStub = nil
+	if fake.tagsReturnsOnCall == nil {
+		fake.tagsReturnsOnCall = make(map[int]struct {
+			result1 []string
+		})
+	}
+	fake.tagsReturnsOnCall[i] = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeSelectionConstraint) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.constrainMutex.RLock()
+	defer fake.constrainMutex.RUnlock()
+	fake.tagsMutex.RLock()
+	defer fake.tagsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeSelectionConstraint) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ worker.SelectionConstraint = new(FakeSelectionConstraint)
@@ -0,0 +1,99 @@
+// This file was generated by counterfeiter
+package workerfakes
+
+import (
+	"sync"
+
+	"github.com/concourse/atc/worker"
+)
+
+type FakeSelectionStrategy struct {
+	ChooseStub        func(workers []worker.Worker) worker.Worker
+	chooseMutex       sync.RWMutex
+	chooseArgsForCall []struct {
+		workers []worker.Worker
+	}
+	chooseReturns struct {
+		result1 worker.Worker
+	}
+	chooseReturnsOnCall map[int]struct {
+		result1 worker.Worker
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSelectionStrategy) Choose(workers []worker.Worker) worker.Worker {
+	var workersCopy []worker.Worker
+	if workers != nil {
+		workersCopy = make([]worker.Worker, len(workers))
+		copy(workersCopy, workers)
+	}
+	fake.chooseMutex.Lock()
+	ret, specificReturn := fake.chooseReturnsOnCall[len(fake.chooseArgsForCall)]
+	fake.chooseArgsForCall = append(fake.chooseArgsForCall, struct {
+		workers []worker.Worker
+	}{workersCopy})
+	fake.recordInvocation("Choose", []interface{}{workersCopy})
+	fake.chooseMutex.Unlock()
+	if fake.ChooseStub != nil {
+		return fake.ChooseStub(workers)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.chooseReturns.result1
+}
+
+func (fake *FakeSelectionStrategy) ChooseCallCount() int {
+	fake.chooseMutex.RLock()
+	defer fake.chooseMutex.RUnlock()
+	return len(fake.chooseArgsForCall)
+}
+
+func (fake *FakeSelectionStrategy) ChooseArgsForCall(i int) []worker.Worker {
+	fake.chooseMutex.RLock()
+	defer fake.chooseMutex.RUnlock()
+	return fake.chooseArgsForCall[i].workers
+}
+
+func (fake *FakeSelectionStrategy) ChooseReturns(result1 worker.Worker) {
+	fake.ChooseStub = nil
+	fake.chooseReturns = struct {
+		result1 worker.Worker
+	}{result1}
+}
+
+func (fake *FakeSelectionStrategy) ChooseReturnsOnCall(i int, result1 worker.Worker) {
+	fake.ChooseStub = nil
+	if fake.chooseReturnsOnCall == nil {
+		fake.chooseReturnsOnCall = make(map[int]struct {
+			result1 worker.Worker
+		})
+	}
+	fake.chooseReturnsOnCall[i] = struct {
+		result1 worker.Worker
+	}{result1}
+}
+
+func (fake *FakeSelectionStrategy) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.chooseMutex.RLock()
+	defer fake.chooseMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeSelectionStrategy) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ worker.SelectionStrategy = new(FakeSelectionStrategy)
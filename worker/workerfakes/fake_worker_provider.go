@@ -79,6 +79,38 @@ type FakeWorkerProvider struct {
 		result2 bool
 		result3 error
 	}
+	FindLatestAttemptContainerHandleStub        func(lager.Logger, int, int, string) (string, bool, error)
+	findLatestAttemptContainerHandleMutex       sync.RWMutex
+	findLatestAttemptContainerHandleArgsForCall []struct {
+		logger   lager.Logger
+		teamID   int
+		buildID  int
+		stepName string
+	}
+	findLatestAttemptContainerHandleReturns struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	findLatestAttemptContainerHandleReturnsOnCall map[int]struct {
+		result1 string
+		result2 bool
+		result3 error
+	}
+	FindEvacuatableContainersStub        func(lager.Logger, string) ([]dbng.EvacuatableContainer, error)
+	findEvacuatableContainersMutex       sync.RWMutex
+	findEvacuatableContainersArgsForCall []struct {
+		logger     lager.Logger
+		workerName string
+	}
+	findEvacuatableContainersReturns struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}
+	findEvacuatableContainersReturnsOnCall map[int]struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -306,6 +338,115 @@ func (fake *FakeWorkerProvider) FindWorkerForBuildContainerReturnsOnCall(i int,
 	}{result1, result2, result3}
 }
 
+func (fake *FakeWorkerProvider) FindLatestAttemptContainerHandle(logger lager.Logger, teamID int, buildID int, stepName string) (string, bool, error) {
+	fake.findLatestAttemptContainerHandleMutex.Lock()
+	ret, specificReturn := fake.findLatestAttemptContainerHandleReturnsOnCall[len(fake.findLatestAttemptContainerHandleArgsForCall)]
+	fake.findLatestAttemptContainerHandleArgsForCall = append(fake.findLatestAttemptContainerHandleArgsForCall, struct {
+		logger   lager.Logger
+		teamID   int
+		buildID  int
+		stepName string
+	}{logger, teamID, buildID, stepName})
+	fake.recordInvocation("FindLatestAttemptContainerHandle", []interface{}{logger, teamID, buildID, stepName})
+	fake.findLatestAttemptContainerHandleMutex.Unlock()
+	if fake.FindLatestAttemptContainerHandleStub != nil {
+		return fake.FindLatestAttemptContainerHandleStub(logger, teamID, buildID, stepName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.findLatestAttemptContainerHandleReturns.result1, fake.findLatestAttemptContainerHandleReturns.result2, fake.findLatestAttemptContainerHandleReturns.result3
+}
+
+func (fake *FakeWorkerProvider) FindLatestAttemptContainerHandleCallCount() int {
+	fake.findLatestAttemptContainerHandleMutex.RLock()
+	defer fake.findLatestAttemptContainerHandleMutex.RUnlock()
+	return len(fake.findLatestAttemptContainerHandleArgsForCall)
+}
+
+func (fake *FakeWorkerProvider) FindLatestAttemptContainerHandleArgsForCall(i int) (lager.Logger, int, int, string) {
+	fake.findLatestAttemptContainerHandleMutex.RLock()
+	defer fake.findLatestAttemptContainerHandleMutex.RUnlock()
+	return fake.findLatestAttemptContainerHandleArgsForCall[i].logger, fake.findLatestAttemptContainerHandleArgsForCall[i].teamID, fake.findLatestAttemptContainerHandleArgsForCall[i].buildID, fake.findLatestAttemptContainerHandleArgsForCall[i].stepName
+}
+
+func (fake *FakeWorkerProvider) FindLatestAttemptContainerHandleReturns(result1 string, result2 bool, result3 error) {
+	fake.FindLatestAttemptContainerHandleStub = nil
+	fake.findLatestAttemptContainerHandleReturns = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeWorkerProvider) FindLatestAttemptContainerHandleReturnsOnCall(i int, result1 string, result2 bool, result3 error) {
+	fake.FindLatestAttemptContainerHandleStub = nil
+	if fake.findLatestAttemptContainerHandleReturnsOnCall == nil {
+		fake.findLatestAttemptContainerHandleReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 bool
+			result3 error
+		})
+	}
+	fake.findLatestAttemptContainerHandleReturnsOnCall[i] = struct {
+		result1 string
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeWorkerProvider) FindEvacuatableContainers(logger lager.Logger, workerName string) ([]dbng.EvacuatableContainer, error) {
+	fake.findEvacuatableContainersMutex.Lock()
+	ret, specificReturn := fake.findEvacuatableContainersReturnsOnCall[len(fake.findEvacuatableContainersArgsForCall)]
+	fake.findEvacuatableContainersArgsForCall = append(fake.findEvacuatableContainersArgsForCall, struct {
+		logger     lager.Logger
+		workerName string
+	}{logger, workerName})
+	fake.recordInvocation("FindEvacuatableContainers", []interface{}{logger, workerName})
+	fake.findEvacuatableContainersMutex.Unlock()
+	if fake.FindEvacuatableContainersStub != nil {
+		return fake.FindEvacuatableContainersStub(logger, workerName)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.findEvacuatableContainersReturns.result1, fake.findEvacuatableContainersReturns.result2
+}
+
+func (fake *FakeWorkerProvider) FindEvacuatableContainersCallCount() int {
+	fake.findEvacuatableContainersMutex.RLock()
+	defer fake.findEvacuatableContainersMutex.RUnlock()
+	return len(fake.findEvacuatableContainersArgsForCall)
+}
+
+func (fake *FakeWorkerProvider) FindEvacuatableContainersArgsForCall(i int) (lager.Logger, string) {
+	fake.findEvacuatableContainersMutex.RLock()
+	defer fake.findEvacuatableContainersMutex.RUnlock()
+	return fake.findEvacuatableContainersArgsForCall[i].logger, fake.findEvacuatableContainersArgsForCall[i].workerName
+}
+
+func (fake *FakeWorkerProvider) FindEvacuatableContainersReturns(result1 []dbng.EvacuatableContainer, result2 error) {
+	fake.FindEvacuatableContainersStub = nil
+	fake.findEvacuatableContainersReturns = struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeWorkerProvider) FindEvacuatableContainersReturnsOnCall(i int, result1 []dbng.EvacuatableContainer, result2 error) {
+	fake.FindEvacuatableContainersStub = nil
+	if fake.findEvacuatableContainersReturnsOnCall == nil {
+		fake.findEvacuatableContainersReturnsOnCall = make(map[int]struct {
+			result1 []dbng.EvacuatableContainer
+			result2 error
+		})
+	}
+	fake.findEvacuatableContainersReturnsOnCall[i] = struct {
+		result1 []dbng.EvacuatableContainer
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeWorkerProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -317,6 +458,10 @@ func (fake *FakeWorkerProvider) Invocations() map[string][][]interface{} {
 	defer fake.findWorkerForResourceCheckContainerMutex.RUnlock()
 	fake.findWorkerForBuildContainerMutex.RLock()
 	defer fake.findWorkerForBuildContainerMutex.RUnlock()
+	fake.findLatestAttemptContainerHandleMutex.RLock()
+	defer fake.findLatestAttemptContainerHandleMutex.RUnlock()
+	fake.findEvacuatableContainersMutex.RLock()
+	defer fake.findEvacuatableContainersMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -64,7 +64,8 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 
 		// pipeline and job are public or authorized
 		case atc.GetBuildPreparation,
-			atc.BuildEvents:
+			atc.BuildEvents,
+			atc.BuildStepEvents:
 			newHandler = wrappa.checkBuildReadAccessHandlerFactory.CheckIfPrivateJobHandler(handler, rejector)
 
 		// resource belongs to authorized team
@@ -124,10 +125,13 @@ func (wrappa *APIAuthWrappa) Wrap(handlers rata.Handlers) rata.Handlers {
 			atc.GetVersionsDB,
 			atc.ListJobInputs,
 			atc.OrderPipelines,
+			atc.PauseAllPipelines,
 			atc.PauseJob,
 			atc.PausePipeline,
 			atc.PauseResource,
 			atc.RenamePipeline,
+			atc.RerunJobBuild,
+			atc.UnpauseAllPipelines,
 			atc.UnpauseJob,
 			atc.UnpausePipeline,
 			atc.UnpauseResource,
@@ -6,6 +6,7 @@ import (
 	"github.com/concourse/atc"
 	"github.com/concourse/atc/auth"
 	"github.com/concourse/atc/auth/authfakes"
+	"github.com/concourse/atc/db/dbfakes"
 	"github.com/concourse/atc/dbng/dbngfakes"
 	"github.com/concourse/atc/wrappa"
 	"github.com/tedsuo/rata"
@@ -36,6 +37,7 @@ var _ = Describe("APIAuthWrappa", func() {
 		fakeBuildFactory = new(dbngfakes.FakeBuildFactory)
 		fakeCheckPipelineAccessHandlerFactory = auth.NewCheckPipelineAccessHandlerFactory(
 			fakeTeamFactory,
+			new(dbfakes.FakeTeamDBFactory),
 		)
 		rejector = auth.UnauthorizedRejector{}
 
@@ -225,6 +227,7 @@ var _ = Describe("APIAuthWrappa", func() {
 
 				// authorized or public pipeline and public job
 				atc.BuildEvents:         checksIfPrivateJob(inputHandlers[atc.BuildEvents]),
+				atc.BuildStepEvents:     checksIfPrivateJob(inputHandlers[atc.BuildStepEvents]),
 				atc.GetBuildPreparation: checksIfPrivateJob(inputHandlers[atc.GetBuildPreparation]),
 
 				// resource belongs to authorized team
@@ -281,11 +284,14 @@ var _ = Describe("APIAuthWrappa", func() {
 				atc.GetVersionsDB:          authorized(inputHandlers[atc.GetVersionsDB]),
 				atc.ListJobInputs:          authorized(inputHandlers[atc.ListJobInputs]),
 				atc.OrderPipelines:         authorized(inputHandlers[atc.OrderPipelines]),
+				atc.PauseAllPipelines:      authorized(inputHandlers[atc.PauseAllPipelines]),
 				atc.PauseJob:               authorized(inputHandlers[atc.PauseJob]),
 				atc.PausePipeline:          authorized(inputHandlers[atc.PausePipeline]),
 				atc.PauseResource:          authorized(inputHandlers[atc.PauseResource]),
 				atc.RenamePipeline:         authorized(inputHandlers[atc.RenamePipeline]),
+				atc.RerunJobBuild:          authorized(inputHandlers[atc.RerunJobBuild]),
 				atc.SaveConfig:             authorized(inputHandlers[atc.SaveConfig]),
+				atc.UnpauseAllPipelines:    authorized(inputHandlers[atc.UnpauseAllPipelines]),
 				atc.UnpauseJob:             authorized(inputHandlers[atc.UnpauseJob]),
 				atc.UnpausePipeline:        authorized(inputHandlers[atc.UnpausePipeline]),
 				atc.UnpauseResource:        authorized(inputHandlers[atc.UnpauseResource]),